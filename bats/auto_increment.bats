@@ -228,4 +228,57 @@ SQL
     run dolt sql -q "CREATE TABLE bad (pk1 int AUTO_INCREMENT DEFAULT 10, c0 int);"
     [ "$status" -ne 0 ]
     [[ "$output" =~ "there can be only one auto_increment column and it must be defined as a key" ]] || false
-}
\ No newline at end of file
+}
+
+@test "AUTO_INCREMENT values are not reused after the rows holding them are deleted" {
+    dolt sql -q "INSERT INTO test (c0) VALUES (11),(22),(33);"
+    dolt sql -q "DELETE FROM test WHERE pk = 3;"
+    dolt sql -q "INSERT INTO test (c0) VALUES (44);"
+    run dolt sql -q "SELECT * FROM test WHERE c0 = 44;" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "4,44" ]] || false
+
+    dolt sql -q "ALTER TABLE test ADD COLUMN c1 int;"
+    dolt sql -q "DELETE FROM test WHERE pk = 4;"
+    dolt sql -q "INSERT INTO test (c0) VALUES (55);"
+    run dolt sql -q "SELECT pk, c0 FROM test WHERE c0 = 55;" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "5,55" ]] || false
+}
+
+@test "table import generates AUTO_INCREMENT values and applies column defaults" {
+    dolt sql -q "CREATE TABLE defaults (pk int NOT NULL PRIMARY KEY AUTO_INCREMENT, c0 int, c1 varchar(10) DEFAULT 'abc');"
+    dolt sql -q "INSERT INTO defaults (c0) VALUES (1);"
+    cat <<DELIM > data.csv
+c0
+2
+3
+DELIM
+    run dolt table import -u defaults data.csv
+    [ "$status" -eq 0 ]
+    run dolt sql -q "SELECT * FROM defaults ORDER BY pk;" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "2,2,abc" ]] || false
+    [[ "$output" =~ "3,3,abc" ]] || false
+}
+
+@test "merge keeps the larger AUTO_INCREMENT counter of the two branches" {
+    dolt sql -q "INSERT INTO test (c0) VALUES (11);"
+    dolt add .
+    dolt commit -m "added a row"
+    dolt branch other
+    dolt sql -q "UPDATE test SET c0 = 12 WHERE pk = 1;"
+    dolt add .
+    dolt commit -m "updated a row"
+    dolt checkout other
+    dolt sql -q "INSERT INTO test (c0) VALUES (22),(33);"
+    dolt sql -q "DELETE FROM test WHERE pk > 1;"
+    dolt add .
+    dolt commit -m "inserted and deleted rows"
+    dolt checkout master
+    dolt merge other
+    dolt sql -q "INSERT INTO test (c0) VALUES (44);"
+    run dolt sql -q "SELECT * FROM test WHERE c0 = 44;" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "4,44" ]] || false
+}
@@ -135,3 +135,46 @@ SQL
     [ "$status" -eq 1 ]
     [[ "$output" =~ "no table named blame_test found" ]] || false
 }
+
+@test "dolt blame --where annotates only the matching rows" {
+    run dolt blame --where pk=2 blame_test
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Harry Wombat" ]] || false
+    [[ ! "$output" =~ "Thomas Foolery" ]] || false
+    [[ ! "$output" =~ "Johnny Moolah" ]] || false
+
+    run dolt blame --where nosuch=2 blame_test
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "'nosuch' is not a known column" ]] || false
+}
+
+@test "dolt blame --at starts annotating from the given revision" {
+    run dolt blame --at HEAD~2 blame_test
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Richard Tracy" ]] || false
+    [[ ! "$output" =~ "Harry Wombat" ]] || false
+
+    run dolt blame --at HEAD~2 HEAD blame_test
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "usage" ]] || false
+}
+
+@test "dolt_blame system table has the commit which last modified each row and column" {
+    run dolt sql -r csv -q "select pk, message from dolt_blame_blame_test order by pk"
+    [ "$status" -eq 0 ]
+    [[ "${lines[1]}" = "1,create blame_test table" ]] || false
+    [[ "${lines[2]}" = "2,replace richard with harry" ]] || false
+    [[ "${lines[3]}" = "3,add more people to blame_test" ]] || false
+    [[ "${lines[4]}" = "4,add more people to blame_test" ]] || false
+
+    run dolt sql -q "select committer from dolt_blame_blame_test where pk = 2"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Harry Wombat" ]] || false
+
+    run dolt sql -r csv -q "select b.pk, l.message from dolt_blame_blame_test b join dolt_log l on b.name_commit_hash = l.commit_hash where b.pk = 2"
+    [ "$status" -eq 0 ]
+    [[ "${lines[1]}" = "2,replace richard with harry" ]] || false
+
+    run dolt sql -q "select * from dolt_blame_missing"
+    [ "$status" -eq 1 ]
+}
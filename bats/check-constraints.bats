@@ -0,0 +1,87 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    setup_common
+
+    dolt sql <<SQL
+CREATE TABLE test (
+    pk int PRIMARY KEY,
+    v1 int CHECK (v1 > 0),
+    v2 int,
+    CONSTRAINT v2_small CHECK (v2 < 100)
+);
+SQL
+}
+
+teardown() {
+    teardown_common
+}
+
+@test "check-constraints: CREATE TABLE checks are shown by schema show" {
+    run dolt schema show test
+    [ "$status" -eq "0" ]
+    [[ "$output" =~ 'CONSTRAINT `test_chk_1` CHECK (v1 > 0)' ]] || false
+    [[ "$output" =~ 'CONSTRAINT `v2_small` CHECK (v2 < 100)' ]] || false
+    run dolt sql -q "SHOW CREATE TABLE test"
+    [ "$status" -eq "0" ]
+    [[ "$output" =~ 'CONSTRAINT `v2_small` CHECK (v2 < 100)' ]] || false
+}
+
+@test "check-constraints: writes violating a check fail" {
+    run dolt sql -q "INSERT INTO test VALUES (1, 0, 1)"
+    [ "$status" -eq "1" ]
+    [[ "$output" =~ "Check constraint 'test_chk_1' is violated." ]] || false
+    dolt sql -q "INSERT INTO test VALUES (1, 1, 1), (2, NULL, NULL)"
+    run dolt sql -q "UPDATE test SET v2 = 100 WHERE pk = 1"
+    [ "$status" -eq "1" ]
+    [[ "$output" =~ "Check constraint 'v2_small' is violated." ]] || false
+}
+
+@test "check-constraints: ALTER TABLE ADD and DROP CHECK" {
+    dolt sql -q "INSERT INTO test VALUES (1, 1, 50)"
+    run dolt sql -q "ALTER TABLE test ADD CONSTRAINT v2_tiny CHECK (v2 < 10)"
+    [ "$status" -eq "1" ]
+    [[ "$output" =~ "Check constraint 'v2_tiny' is violated." ]] || false
+    dolt sql -q "ALTER TABLE test DROP CHECK v2_small"
+    dolt sql -q "ALTER TABLE test ADD CHECK (pk < 10) NOT ENFORCED"
+    dolt sql -q "INSERT INTO test VALUES (20, 1, 500)"
+    run dolt schema show test
+    [ "$status" -eq "0" ]
+    [[ ! "$output" =~ "v2_small" ]] || false
+    [[ "$output" =~ 'CONSTRAINT `test_chk_2` CHECK (pk < 10) NOT ENFORCED' ]] || false
+}
+
+@test "check-constraints: columns used by checks cannot be dropped" {
+    run dolt sql -q "ALTER TABLE test DROP COLUMN v1"
+    [ "$status" -eq "1" ]
+    [[ "$output" =~ "test_chk_1" ]] || false
+}
+
+@test "check-constraints: table import enforces checks" {
+    cat <<DELIM > data.csv
+pk,v1,v2
+1,1,1
+2,-1,1
+DELIM
+    run dolt table import -u test data.csv
+    [ "$status" -eq "1" ]
+    [[ "$output" =~ "Check constraint 'test_chk_1' is violated." ]] || false
+}
+
+@test "check-constraints: merge fails when merged rows violate a check" {
+    dolt add .
+    dolt commit -m "created table"
+    dolt branch other
+    dolt sql -q "ALTER TABLE test ADD CONSTRAINT pk_small CHECK (pk < 10)"
+    dolt add .
+    dolt commit -m "added check"
+    dolt checkout other
+    dolt sql -q "INSERT INTO test VALUES (20, 1, 1)"
+    dolt add .
+    dolt commit -m "added row"
+    dolt checkout master
+    run dolt merge other
+    [ "$status" -eq "1" ]
+    [[ "$output" =~ "Check constraint 'pk_small' is violated." ]] || false
+}
@@ -164,7 +164,6 @@ SQL
     [ "$status" -eq "1" ]
     [[ "$output" =~ "not valid type" ]] || false
 
-    skip "TEXT passed, BLOB not yet supported"
     dolt sql <<SQL
 CREATE TABLE parent2 (
   id INT PRIMARY KEY,
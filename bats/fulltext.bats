@@ -0,0 +1,119 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    setup_common
+
+    dolt sql <<SQL
+CREATE TABLE articles (
+    pk int PRIMARY KEY,
+    title varchar(200),
+    body longtext
+);
+INSERT INTO articles VALUES
+    (1, 'MySQL Tutorial', 'DBMS stands for DataBase'),
+    (2, 'How To Use MySQL Well', 'After you went through a tutorial'),
+    (3, 'Optimizing MySQL', 'In this tutorial we show how to optimize'),
+    (4, 'MySQL vs. YourSQL', 'In the following database comparison');
+SQL
+    dolt sql -q "CREATE FULLTEXT INDEX ft_articles ON articles (title, body)"
+}
+
+teardown() {
+    teardown_common
+}
+
+@test "fulltext: index holds each distinct token of the indexed columns" {
+    run dolt index cat articles ft_articles -r csv
+    [ "$status" -eq "0" ]
+    [[ "$output" =~ "token,pk" ]] || false
+    [[ "$output" =~ "database,1" ]] || false
+    [[ "$output" =~ "database,4" ]] || false
+    [[ "$output" =~ "tutorial,3" ]] || false
+    [[ ! "$output" =~ "the," ]] || false
+    [[ ! "$output" =~ "how," ]] || false
+
+    dolt sql -q "UPDATE articles SET body = 'zebra' WHERE pk = 3"
+    dolt sql -q "DELETE FROM articles WHERE pk = 4"
+    run dolt index cat articles ft_articles -r csv
+    [ "$status" -eq "0" ]
+    [[ "$output" =~ "zebra,3" ]] || false
+    [[ ! "$output" =~ "tutorial,3" ]] || false
+    [[ ! "$output" =~ "database,4" ]] || false
+}
+
+@test "fulltext: MATCH ... AGAINST ranks rows by relevance" {
+    run dolt sql -r csv -q "SELECT pk FROM articles WHERE MATCH (title, body) AGAINST ('database') > 0 ORDER BY pk"
+    [ "$status" -eq "0" ]
+    [ "${lines[1]}" = "1" ]
+    [ "${lines[2]}" = "4" ]
+    [ "${#lines[@]}" -eq "3" ]
+    run dolt sql -r csv -q "SELECT pk FROM articles WHERE MATCH (body, title) AGAINST ('database tutorial' IN NATURAL LANGUAGE MODE) > 0 ORDER BY MATCH (body, title) AGAINST ('database tutorial') DESC, pk"
+    [ "$status" -eq "0" ]
+    [ "${lines[1]}" = "1" ]
+    [ "${#lines[@]}" -eq "5" ]
+    run dolt sql -q "SELECT MATCH (title) AGAINST ('database') FROM articles"
+    [ "$status" -eq "1" ]
+    [[ "$output" =~ "can't find FULLTEXT index" ]] || false
+    run dolt sql -q "SELECT MATCH (title, body) AGAINST ('+database' IN BOOLEAN MODE) FROM articles"
+    [ "$status" -eq "1" ]
+    [[ "$output" =~ "only supports searches IN NATURAL LANGUAGE MODE" ]] || false
+}
+
+@test "fulltext: only text columns may be indexed" {
+    run dolt sql -q "CREATE FULLTEXT INDEX ft_pk ON articles (pk)"
+    [ "$status" -eq "1" ]
+    [[ "$output" =~ "cannot be part of a FULLTEXT index" ]] || false
+    run dolt sql -q "CREATE INDEX idx_title ON articles (title)"
+    [ "$status" -eq "0" ]
+    run dolt index ls articles
+    [[ "$output" =~ "ft_articles(title, body)" ]] || false
+    [[ "$output" =~ "idx_title(title)" ]] || false
+}
+
+@test "fulltext: index is merged and rebuilt" {
+    dolt add .
+    dolt commit -m "articles"
+    dolt checkout -b other
+    dolt sql -q "UPDATE articles SET body = 'zebra' WHERE pk = 2"
+    dolt add .
+    dolt commit -m "zebra"
+    dolt checkout master
+    dolt sql -q "INSERT INTO articles VALUES (5, 'Giraffe', 'giraffe facts')"
+    dolt add .
+    dolt commit -m "giraffe"
+    run dolt merge other
+    [ "$status" -eq "0" ]
+    run dolt index cat articles ft_articles -r csv
+    [[ "$output" =~ "zebra,2" ]] || false
+    [[ "$output" =~ "giraffe,5" ]] || false
+    [[ ! "$output" =~ "tutorial,2" ]] || false
+    dolt index cat articles ft_articles -r csv > before.csv
+    dolt index rebuild articles ft_articles
+    run dolt index cat articles ft_articles -r csv
+    [ "$output" = "$(cat before.csv)" ]
+}
+
+@test "fulltext: index is shown and exported, but can't be defined in CREATE TABLE" {
+    run dolt sql -q "SHOW CREATE TABLE articles"
+    [ "$status" -eq "0" ]
+    [[ "$output" =~ 'FULLTEXT KEY `ft_articles` (`title`,`body`)' ]] || false
+    run dolt sql -q "CREATE TABLE notes (pk int PRIMARY KEY, body text, FULLTEXT KEY ft_body (body))"
+    [ "$status" -eq "1" ]
+    [[ "$output" =~ "FULLTEXT indexes cannot be defined in CREATE TABLE" ]] || false
+
+    dolt table export articles articles.sql
+    run cat articles.sql
+    [[ ! "$output" =~ "FULLTEXT KEY" ]] || false
+    [[ "$output" =~ 'ALTER TABLE `articles` ADD FULLTEXT INDEX `ft_articles`(`title`,`body`);' ]] || false
+    dolt sql -q "DROP TABLE articles"
+    dolt sql < articles.sql
+    run dolt index ls articles
+    [[ "$output" =~ "ft_articles(title, body)" ]] || false
+}
+
+@test "fulltext: repositories with FULLTEXT indexes need a newer feature version" {
+    run dolt version --feature
+    [ "$status" -eq "0" ]
+    [[ "$output" =~ "feature version: 1" ]] || false
+}
@@ -0,0 +1,101 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    setup_common
+
+    dolt sql <<SQL
+CREATE TABLE users (
+    pk int PRIMARY KEY,
+    email varchar(100),
+    bio longtext,
+    INDEX bio_prefix (bio(5))
+);
+INSERT INTO users VALUES
+    (1, 'Bob@Example.com', 'hello world'),
+    (2, 'alice@x.org', 'help me'),
+    (3, 'CAROL@x.org', 'other');
+SQL
+    dolt sql -q "CREATE UNIQUE INDEX lower_email ON users ((LOWER(email)))"
+}
+
+teardown() {
+    teardown_common
+}
+
+@test "index-expressions: expression index holds the values of its expressions" {
+    run dolt index cat users lower_email -r csv
+    [ "$status" -eq "0" ]
+    [[ "$output" =~ "bob@example.com,1" ]] || false
+    [[ "$output" =~ "carol@x.org,3" ]] || false
+
+    dolt sql -q "UPDATE users SET email = 'Dave@x.org' WHERE pk = 3"
+    dolt sql -q "DELETE FROM users WHERE pk = 2"
+    run dolt index cat users lower_email -r csv
+    [ "$status" -eq "0" ]
+    [[ "$output" =~ "dave@x.org,3" ]] || false
+    [[ ! "$output" =~ "carol@x.org" ]] || false
+    [[ ! "$output" =~ "alice@x.org" ]] || false
+
+    run dolt sql -q "INSERT INTO users VALUES (4, 'BOB@example.COM', NULL)"
+    [ "$status" -eq "1" ]
+    [[ "$output" =~ "UNIQUE constraint violation" ]] || false
+}
+
+@test "index-expressions: prefix index holds the prefixes of its columns" {
+    run dolt index cat users bio_prefix -r csv
+    [ "$status" -eq "0" ]
+    [[ "$output" =~ "hello,1" ]] || false
+    [[ "$output" =~ "help ,2" ]] || false
+    [[ ! "$output" =~ "hello world" ]] || false
+}
+
+@test "index-expressions: queries filtering on an indexed expression use the index" {
+    run dolt sql -q "EXPLAIN SELECT * FROM users WHERE LOWER(email) = 'bob@example.com'"
+    [ "$status" -eq "0" ]
+    [[ "$output" =~ "Indexed table access on index [LOWER(users.email)]" ]] || false
+    run dolt sql -r csv -q "SELECT pk FROM users WHERE LOWER(email) = 'bob@example.com'"
+    [ "$status" -eq "0" ]
+    [ "${lines[1]}" = "1" ]
+    [ "${#lines[@]}" -eq "2" ]
+
+    run dolt sql -q "EXPLAIN SELECT * FROM users WHERE bio = 'hello world'"
+    [ "$status" -eq "0" ]
+    [[ "$output" =~ "Indexed table access on index [users.bio]" ]] || false
+    run dolt sql -r csv -q "SELECT pk FROM users WHERE bio < 'help' ORDER BY pk"
+    [ "$status" -eq "0" ]
+    [ "${lines[1]}" = "1" ]
+    [ "${#lines[@]}" -eq "2" ]
+}
+
+@test "index-expressions: definitions are shown and exported" {
+    dolt sql -q "ALTER TABLE users ADD INDEX ((UPPER(bio)), pk)"
+    run dolt schema show users
+    [ "$status" -eq "0" ]
+    [[ "$output" =~ 'KEY `bio_prefix` (`bio`(5))' ]] || false
+    [[ "$output" =~ 'UNIQUE KEY `lower_email` ((LOWER(email)))' ]] || false
+    [[ "$output" =~ 'KEY `functional_index` ((UPPER(bio)),(pk))' ]] || false
+
+    dolt schema export users export.sql
+    sed -i -e 's/`users`/`users2`/' export.sql
+    dolt sql < export.sql
+    run dolt index ls users2
+    [ "$status" -eq "0" ]
+    [[ "$output" =~ 'bio_prefix(`bio`(5))' ]] || false
+    [[ "$output" =~ "lower_email((LOWER(email)))" ]] || false
+}
+
+@test "index-expressions: invalid definitions" {
+    run dolt sql -q "CREATE INDEX idx ON users ((1 + 1))"
+    [ "$status" -eq "1" ]
+    [[ "$output" =~ "does not reference any column" ]] || false
+    run dolt sql -q "CREATE INDEX idx ON users ((LOWER(email)), bio(3))"
+    [ "$status" -eq "1" ]
+    run dolt sql -q "CREATE INDEX idx ON users (pk(3))"
+    [ "$status" -eq "1" ]
+    run dolt sql -q "CREATE INDEX idx ON users (bio)"
+    [ "$status" -eq "1" ]
+    run dolt sql -q "ALTER TABLE users RENAME COLUMN email TO mail"
+    [ "$status" -eq "1" ]
+    [[ "$output" =~ "used in expression index" ]] || false
+}
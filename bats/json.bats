@@ -0,0 +1,85 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    setup_common
+
+    dolt sql <<SQL
+CREATE TABLE js (
+    pk int PRIMARY KEY,
+    doc json
+);
+INSERT INTO js VALUES (1, '{"a": 1, "b": {"c": [1, 2]}}'), (2, '["x", "y"]');
+SQL
+}
+
+teardown() {
+    teardown_common
+}
+
+@test "json: invalid documents are rejected" {
+    run dolt sql -q "INSERT INTO js VALUES (3, 'not json')"
+    [ "$status" -eq "1" ]
+    [[ "$output" =~ "Invalid JSON text" ]] || false
+    run dolt schema show js
+    [[ "$output" =~ '`doc` json' ]] || false
+}
+
+@test "json: JSON_EXTRACT and the -> and ->> operators" {
+    run dolt sql -q "SELECT doc->'$.b.c[1]' FROM js WHERE pk = 1" -r csv
+    [ "$status" -eq "0" ]
+    [ "${lines[1]}" = "2" ]
+    run dolt sql -q "SELECT doc->>'\$[0]', JSON_EXTRACT(doc, '\$[1]') FROM js WHERE pk = 2" -r csv
+    [ "$status" -eq "0" ]
+    [ "${lines[1]}" = 'x,"""y"""' ]
+    run dolt sql -q "SELECT pk FROM js WHERE doc->'$.a' = 1" -r csv
+    [ "$status" -eq "0" ]
+    [ "${#lines[@]}" -eq "2" ]
+    [ "${lines[1]}" = "1" ]
+}
+
+@test "json: diff shows the paths that changed" {
+    dolt add .
+    dolt commit -m "added rows"
+    dolt sql -q "UPDATE js SET doc = '{\"a\": 2, \"b\": {\"c\": [1, 2]}, \"d\": true}' WHERE pk = 1"
+    run dolt diff
+    [ "$status" -eq "0" ]
+    [[ "$output" =~ '$.a: 1' ]] || false
+    [[ "$output" =~ '$.a: 2, $.d: true' ]] || false
+    [[ ! "$output" =~ '$.b' ]] || false
+}
+
+@test "json: changes to different keys merge" {
+    dolt add .
+    dolt commit -m "added rows"
+    dolt branch other
+    dolt sql -q "UPDATE js SET doc = '{\"a\": 5, \"b\": {\"c\": [1, 2]}}' WHERE pk = 1"
+    dolt add .
+    dolt commit -m "changed a"
+    dolt checkout other
+    dolt sql -q "UPDATE js SET doc = '{\"a\": 1, \"b\": {\"c\": [3]}}' WHERE pk = 1"
+    dolt add .
+    dolt commit -m "changed b"
+    dolt checkout master
+    run dolt merge other
+    [ "$status" -eq "0" ]
+    [[ ! "$output" =~ "CONFLICT" ]] || false
+    run dolt sql -q "SELECT doc FROM js WHERE pk = 1" -r csv
+    [ "${lines[1]}" = '"{""a"":5,""b"":{""c"":[3]}}"' ]
+}
+
+@test "json: changes to the same key conflict" {
+    dolt add .
+    dolt commit -m "added rows"
+    dolt branch other
+    dolt sql -q "UPDATE js SET doc = '{\"a\": 5}' WHERE pk = 1"
+    dolt add .
+    dolt commit -m "changed a"
+    dolt checkout other
+    dolt sql -q "UPDATE js SET doc = '{\"a\": 6}' WHERE pk = 1"
+    dolt add .
+    dolt commit -m "changed a again"
+    dolt checkout master
+    run dolt merge other
+    [[ "$output" =~ "CONFLICT" ]] || false
+}
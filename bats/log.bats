@@ -89,3 +89,118 @@ teardown() {
     regex='Merge:.*MergeCommit.*'
     [[ "$output" =~ $regex ]] || false
 }
+
+@test "dolt log --oneline and --graph" {
+    dolt sql -q "create table test (pk int, c1 int, primary key(pk))"
+    dolt add test
+    dolt commit -m "Commit1"
+    dolt checkout -b test-branch
+    dolt sql -q "insert into test values (0,0)"
+    dolt add test
+    dolt commit -m "Commit2"
+    dolt checkout master
+    dolt sql -q "insert into test values (1,1)"
+    dolt add test
+    dolt commit -m "Commit3"
+    dolt merge test-branch
+    dolt add test
+    dolt commit -m "MergeCommit"
+    run dolt log --oneline
+    [ $status -eq 0 ]
+    [ "${#lines[@]}" -eq 5 ]
+    [[ "${lines[0]}" =~ ^[0-9a-v]{32}" MergeCommit"$ ]] || false
+    [[ "${lines[4]}" =~ "Initialize data repository" ]] || false
+    run dolt log --oneline --graph
+    [ $status -eq 0 ]
+    [[ "${lines[0]}" =~ ^"*   "[0-9a-v]{32}" MergeCommit"$ ]] || false
+    [[ "${lines[1]}" == '|\' ]] || false
+    [[ "${lines[2]}" =~ ^"* | "[0-9a-v]{32}" Commit3"$ ]] || false
+    [[ "${lines[3]}" =~ ^"| * "[0-9a-v]{32}" Commit2"$ ]] || false
+    [[ "${lines[4]}" == '|/' ]] || false
+    [[ "${lines[5]}" =~ ^"* "[0-9a-v]{32}" Commit1"$ ]] || false
+    run dolt log --graph -n 1
+    [ $status -eq 0 ]
+    [[ "${lines[0]}" =~ ^"*   commit " ]] || false
+    [[ "${lines[1]}" =~ ^'|\  Merge: ' ]] || false
+}
+
+@test "dolt log filters commits" {
+    dolt sql -q "create table test (pk int, c1 int, primary key(pk))"
+    dolt sql -q "create table other (pk int, primary key(pk))"
+    dolt add .
+    dolt commit -m "Commit1" --date 2020-01-01
+    dolt checkout -b test-branch
+    dolt sql -q "insert into other values (0)"
+    dolt add .
+    dolt commit -m "Commit2" --date 2020-02-01
+    dolt checkout master
+    dolt sql -q "insert into test values (1,1)"
+    dolt add .
+    dolt commit -m "Commit3" --date 2020-03-01
+    dolt merge test-branch
+    dolt add .
+    dolt commit -m "MergeCommit" --date 2020-04-01
+    run dolt log --oneline --merges
+    [ $status -eq 0 ]
+    [ "${#lines[@]}" -eq 1 ]
+    [[ "$output" =~ "MergeCommit" ]] || false
+    run dolt log --oneline --no-merges -n 2
+    [ $status -eq 0 ]
+    [ "${#lines[@]}" -eq 2 ]
+    [[ "${lines[0]}" =~ "Commit3" ]] || false
+    [[ "${lines[1]}" =~ "Commit2" ]] || false
+    run dolt log --oneline --since 2020-01-15 --until 2020-03-01
+    [ $status -eq 0 ]
+    [ "${#lines[@]}" -eq 2 ]
+    [[ "${lines[0]}" =~ "Commit3" ]] || false
+    [[ "${lines[1]}" =~ "Commit2" ]] || false
+    run dolt log --oneline --author "^Bats Tests <"
+    [ $status -eq 0 ]
+    [ "${#lines[@]}" -eq 5 ]
+    run dolt log --oneline --author "nobody"
+    [ $status -eq 0 ]
+    [ "$output" = "" ]
+    run dolt log --merges --no-merges
+    [ $status -eq 1 ]
+    [[ "$output" =~ "cannot be used together" ]] || false
+}
+
+@test "dolt log with tables after --" {
+    dolt sql -q "create table test (pk int, c1 int, primary key(pk))"
+    dolt add .
+    dolt commit -m "Commit1"
+    dolt checkout -b test-branch
+    dolt sql -q "create table other (pk int, primary key(pk))"
+    dolt add .
+    dolt commit -m "Commit2"
+    dolt checkout master
+    dolt sql -q "insert into test values (1,1)"
+    dolt add .
+    dolt commit -m "Commit3"
+    dolt merge test-branch
+    dolt add .
+    dolt commit -m "MergeCommit"
+    run dolt log --oneline -- test
+    [ $status -eq 0 ]
+    [ "${#lines[@]}" -eq 2 ]
+    [[ "${lines[0]}" =~ "Commit3" ]] || false
+    [[ "${lines[1]}" =~ "Commit1" ]] || false
+    run dolt log --oneline -- other
+    [ $status -eq 0 ]
+    [ "${#lines[@]}" -eq 1 ]
+    [[ "${lines[0]}" =~ "Commit2" ]] || false
+    run dolt log --oneline test-branch -- test
+    [ $status -eq 0 ]
+    [ "${#lines[@]}" -eq 1 ]
+    [[ "${lines[0]}" =~ "Commit1" ]] || false
+    run dolt log --oneline --graph -- test other
+    [ $status -eq 0 ]
+    [ "${#lines[@]}" -eq 5 ]
+    [[ "${lines[0]}" =~ ^"* "[0-9a-v]{32}" Commit3"$ ]] || false
+    [[ "${lines[1]}" =~ ^"| * "[0-9a-v]{32}" Commit2"$ ]] || false
+    [[ "${lines[2]}" == '|/' ]] || false
+    [[ "${lines[3]}" =~ ^"* "[0-9a-v]{32}" Commit1"$ ]] || false
+    run dolt log --oneline -- missing
+    [ $status -eq 0 ]
+    [ "$output" = "" ]
+}
@@ -173,3 +173,115 @@ SQL
     [ "$status" -eq 1 ]
     [ ! -d test-repo ]
 }
+
+@test "clone and fetch only the data of some tables" {
+    dolt sql -q "CREATE TABLE one (pk BIGINT PRIMARY KEY, c1 BIGINT)"
+    dolt sql -q "CREATE TABLE two (pk BIGINT PRIMARY KEY, c1 BIGINT)"
+    dolt sql -q "INSERT INTO one VALUES (1, 1), (2, 2)"
+    dolt sql -q "INSERT INTO two VALUES (10, 10), (20, 20)"
+    dolt add .
+    dolt commit -m "added tables"
+
+    mkdir remotedir
+    dolt remote add origin file://remotedir
+    dolt push origin master
+
+    cd dolt-repo-clones
+    run dolt clone --tables one file://../remotedir test-repo
+    [ "$status" -eq 0 ]
+    cd test-repo
+    grep '"sparse"' .dolt/repo_state.json
+
+    run dolt sql -q "SELECT COUNT(*) FROM one" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "2" ]] || false
+
+    # data for other tables is read from the remote
+    run dolt sql -q "SELECT COUNT(*) FROM two" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "2" ]] || false
+
+    cd ../..
+    dolt sql -q "INSERT INTO two VALUES (30, 30)"
+    dolt add .
+    dolt commit -m "added a row"
+    dolt push origin master
+
+    cd dolt-repo-clones/test-repo
+    run dolt fetch --tables one,two
+    [ "$status" -eq 0 ]
+    dolt merge origin/master
+    run dolt sql -q "SELECT COUNT(*) FROM two" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "3" ]] || false
+}
+
+# kill_on_checkpoint runs a dolt command in the background, and kills it as soon as it has saved its progress
+kill_on_checkpoint() {
+    dolt "$@" > /dev/null 2>&1 &
+    pid=$!
+    while ! ls .dolt/temptf/pull-checkpoint-*/checkpoint.json > /dev/null 2>&1; do
+        kill -0 $pid 2> /dev/null || break
+    done
+    kill -9 $pid
+    wait $pid || true
+}
+
+@test "push and pull with --resume" {
+    # small table files make progress get saved often
+    export DOLT_PULL_CHUNKS_PER_TABLE_FILE=1
+    mkdir remotedir
+    dolt remote add origin file://remotedir
+    dolt sql -q "CREATE TABLE test (pk int PRIMARY KEY, c1 varchar(120))"
+    dolt add .
+    dolt commit -m "created a table"
+    dolt push origin master
+    cd dolt-repo-clones
+    dolt clone file://../remotedir test-repo
+    cd ..
+
+    echo "pk,c1" > data.csv
+    seq 1 50000 | awk '{printf "%d,aaaaaaaaaabbbbbbbbbbccccccccccddddddddddeeeeeeeeeeffffffffffgggggggggghhhhhhhhhh%d\n", $1, $1}' >> data.csv
+    dolt table import -u test data.csv
+    dolt add .
+    dolt commit -m "added rows"
+
+    kill_on_checkpoint push origin master
+    run ls .dolt/temptf
+    [[ "$output" =~ "pull-checkpoint-" ]] || false
+    # other transfers keep the progress
+    run dolt sql -q "SELECT dolt_fetch()"
+    [ "$status" -eq 0 ]
+    run ls .dolt/temptf
+    [[ "$output" =~ "pull-checkpoint-" ]] || false
+    run dolt push origin master --resume
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Resuming from saved progress" ]] || false
+    run ls .dolt/temptf
+    [[ ! "$output" =~ "pull-checkpoint-" ]] || false
+
+    # progress is only used with --resume
+    cd dolt-repo-clones/test-repo
+    kill_on_checkpoint pull
+    run dolt pull
+    [ "$status" -eq 0 ]
+    [[ ! "$output" =~ "Resuming from saved progress" ]] || false
+    run dolt sql -q "SELECT COUNT(*) FROM test" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "50000" ]] || false
+
+    cd ../..
+    dolt sql -q "UPDATE test SET c1 = CONCAT(c1, 'z')"
+    dolt add .
+    dolt commit -m "updated rows"
+    dolt push origin master
+
+    cd dolt-repo-clones/test-repo
+    kill_on_checkpoint pull
+    run dolt pull --resume
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Resuming from saved progress" ]] || false
+    run dolt sql -q "SELECT COUNT(*) FROM test WHERE c1 LIKE '%z'" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "50000" ]] || false
+}
@@ -0,0 +1,66 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    setup_common
+
+    dolt sql <<SQL
+CREATE TABLE places (
+    pk int PRIMARY KEY,
+    location point NOT NULL,
+    SPATIAL INDEX (location)
+);
+INSERT INTO places VALUES
+    (1, ST_GeomFromText('POINT(10.40744 57.64911)')),
+    (2, POINT(-5.6, 42.6));
+SQL
+}
+
+teardown() {
+    teardown_common
+}
+
+@test "spatial: geometries are created from and formatted as WKT" {
+    run dolt sql -r csv -q "SELECT ST_AsText(ST_GeomFromText('linestring(0 0, 1 1)')) AS l, ST_AsText(POINT(1.5, -2)) AS p"
+    [ "$status" -eq "0" ]
+    [[ "$output" =~ '"LINESTRING(0 0,1 1)",POINT(1.5 -2)' ]] || false
+    run dolt sql -r csv -q "SELECT ST_AsText(ST_GeomFromWKB(ST_AsBinary(POINT(1, 2)))) AS p"
+    [ "$status" -eq "0" ]
+    [[ "$output" =~ "POINT(1 2)" ]] || false
+    run dolt sql -q "SELECT ST_GeomFromText('POLYGON((0 0,1 0,1 1))')"
+    [[ "$output" =~ "invalid GIS data" ]] || false
+}
+
+@test "spatial: ST_Distance, ST_Contains and ST_Within" {
+    run dolt sql -r csv -q "SELECT ST_Distance(POINT(0, 0), POINT(3, 4)) AS d"
+    [ "$status" -eq "0" ]
+    [[ "$output" =~ "5" ]] || false
+    run dolt sql -r csv -q "SELECT ST_Contains(ST_GeomFromText('POLYGON((0 0,4 0,4 4,0 4,0 0))'), POINT(1, 1)) AS c, ST_Within(POINT(5, 5), ST_GeomFromText('POLYGON((0 0,4 0,4 4,0 4,0 0))')) AS w"
+    [ "$status" -eq "0" ]
+    [[ "$output" =~ "true,false" ]] || false
+}
+
+@test "spatial: geometry columns and spatial indexes" {
+    run dolt sql -r csv -q "SELECT pk, ST_AsText(location) AS l FROM places WHERE ST_Within(location, ST_GeomFromText('POLYGON((10 57,11 57,11 58,10 58,10 57))'))"
+    [ "$status" -eq "0" ]
+    [ "${#lines[@]}" -eq "2" ]
+    [ "${lines[1]}" = "1,POINT(10.40744 57.64911)" ]
+    run dolt sql -q "SHOW CREATE TABLE places"
+    [ "$status" -eq "0" ]
+    [[ "$output" =~ "\`location\` point NOT NULL" ]] || false
+    [[ "$output" =~ "SPATIAL KEY \`location\` (\`location\`)" ]] || false
+    run dolt sql -q "INSERT INTO places VALUES (3, ST_GeomFromText('LINESTRING(0 0,1 1)'))"
+    [ "$status" -ne "0" ]
+    run dolt sql -q "ALTER TABLE places ADD COLUMN area polygon, ADD SPATIAL INDEX (area)"
+    [ "$status" -ne "0" ]
+    [[ "$output" =~ "must be NOT NULL" ]] || false
+}
+
+@test "spatial: geohashes of points" {
+    run dolt sql -r csv -q "SELECT pk FROM places WHERE ST_GeoHash(ST_X(location), ST_Y(location), 4) = 'u4pr'"
+    [ "$status" -eq "0" ]
+    [ "${#lines[@]}" -eq "2" ]
+    [ "${lines[1]}" = "1" ]
+    run dolt sql -r csv -q "SELECT ST_AsText(ST_PointFromGeoHash('ezs42', 0)) AS p"
+    [[ "$output" =~ "POINT(-5.60302734375 42.60498046875)" ]] || false
+}
@@ -255,4 +255,128 @@ teardown() {
 
     server_query 1 "SELECT * FROM repo1.r1_one_pk" "pk,c1,c2\n1,1,1\n2,2,2\n3,3,3"
     server_query 1 "SELECT * FROM repo2.r2_one_pk" "pk,c3,c4\n1,1,1\n2,2,2\n3,3,3"
-}
\ No newline at end of file
+}
+@test "read replica pulls from its remote and rejects writes" {
+    skiponwindows "Has dependencies that are missing on the Jenkins Windows installation."
+
+    mkdir remote
+    cd repo1
+    dolt sql -q "create table test (pk int primary key);"
+    dolt add -A && dolt commit -m "create test"
+    dolt remote add origin file://../remote
+    dolt push origin master
+    cd .. && dolt clone file://remote replica && cd replica
+
+    DEFAULT_DB="replica"
+    let PORT="$$ % (65536-1024) + 1024"
+    echo "
+user:
+  name: dolt
+
+listener:
+  host: 0.0.0.0
+  port: $PORT
+  max_connections: 10
+
+replication:
+  - database: replica
+    interval_millis: 100
+" > .cliconfig.yaml
+    dolt sql-server --config .cliconfig.yaml &
+    SERVER_PID=$!
+    wait_for_connection $PORT 5000
+
+    server_query 1 "SELECT * FROM test" ""
+
+    cd ../repo1
+    dolt sql -q "insert into test values (1);"
+    dolt add -A && dolt commit -m "insert 1"
+    dolt push origin master
+    sleep 1
+
+    server_query 1 "SELECT * FROM test" "pk\n1"
+    server_query 1 "SELECT message FROM dolt_log ORDER BY date DESC LIMIT 1" "message\ninsert 1"
+
+    run server_query 1 "INSERT INTO test VALUES (2)" ""
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "read replica" ]] || false
+}
+
+@test "push on commit pushes new branch heads to the remote" {
+    skiponwindows "Has dependencies that are missing on the Jenkins Windows installation."
+
+    mkdir remote
+    cd repo1
+    dolt sql -q "create table test (pk int primary key);"
+    dolt add -A && dolt commit -m "create test"
+    dolt remote add origin file://../remote
+    dolt push origin master
+
+    DEFAULT_DB="repo1"
+    let PORT="$$ % (65536-1024) + 1024"
+    echo "
+user:
+  name: dolt
+
+listener:
+  host: 0.0.0.0
+  port: $PORT
+  max_connections: 10
+
+push_on_commit:
+  - database: repo1
+    retry_delay_millis: 100
+" > .cliconfig.yaml
+    dolt sql-server --config .cliconfig.yaml &
+    SERVER_PID=$!
+    wait_for_connection $PORT 5000
+
+    multi_query 1 "
+    INSERT INTO test VALUES (1);
+    SET @@repo1_head=commit('insert 1');
+    INSERT INTO dolt_branches (name,hash) VALUES ('pushed', @@repo1_head);"
+    sleep 1
+
+    server_query 1 "SELECT remote,branch,status FROM dolt_push_status" "remote,branch,status\norigin,pushed,succeeded"
+
+    cd .. && dolt clone -b pushed file://remote pushed_clone && cd pushed_clone
+    run dolt sql -q "SELECT * FROM test" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "1" ]] || false
+}
+
+@test "query stats and slow query log" {
+    skiponwindows "Has dependencies that are missing on the Jenkins Windows installation."
+
+    cd repo1
+    dolt sql -q "create table test (pk int primary key);"
+    dolt sql -q "insert into test values (1), (2), (3);"
+
+    DEFAULT_DB="repo1"
+    let PORT="$$ % (65536-1024) + 1024"
+    echo "
+user:
+  name: dolt
+
+listener:
+  host: 0.0.0.0
+  port: $PORT
+  max_connections: 10
+
+performance:
+  slow_query_threshold_millis: 5
+" > .cliconfig.yaml
+    dolt sql-server --config .cliconfig.yaml > server.log 2>&1 &
+    SERVER_PID=$!
+    wait_for_connection $PORT 5000
+
+    server_query 1 "SELECT * FROM test WHERE pk = 1" "pk\n1"
+    server_query 1 "SELECT * FROM test WHERE pk = 2" "pk\n2"
+    server_query 1 "SELECT count,rows_sent,rows_examined FROM dolt_query_stats WHERE fingerprint = 'select * from test where pk = ?'" "count,rows_sent,rows_examined\n2,2,2"
+
+    server_query 1 "SELECT SLEEP(0.1) AS s" "s\n0"
+    run cat server.log
+    [[ "$output" =~ "slow query" ]] || false
+    [[ "$output" =~ "select SLEEP(?) as s from dual" ]] || false
+    [[ ! "$output" =~ "SLEEP(0.1)" ]] || false
+}
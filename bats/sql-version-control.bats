@@ -0,0 +1,109 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    setup_common
+    dolt sql -q "CREATE TABLE test (pk int PRIMARY KEY, c1 int)"
+    dolt add test
+    dolt commit -m "created table test"
+}
+
+teardown() {
+    teardown_common
+}
+
+@test "dolt_branch creates, copies, renames and deletes branches" {
+    run dolt sql -q "SELECT dolt_branch('feature')"
+    [ $status -eq 0 ]
+    run dolt sql -q "SELECT dolt_branch('-c', 'feature', 'copy')"
+    [ $status -eq 0 ]
+    run dolt sql -q "SELECT dolt_branch('-m', 'copy', 'renamed')"
+    [ $status -eq 0 ]
+    run dolt branch
+    [[ "$output" =~ "feature" ]] || false
+    [[ "$output" =~ "renamed" ]] || false
+    [[ ! "$output" =~ "copy" ]] || false
+
+    run dolt sql -q "SELECT dolt_branch('-d', 'renamed')"
+    [ $status -eq 0 ]
+    run dolt branch
+    [[ ! "$output" =~ "renamed" ]] || false
+
+    run dolt sql -q "SELECT dolt_branch('-d', 'master')"
+    [ $status -ne 0 ]
+    [[ "$output" =~ "attempted to delete checked out branch" ]] || false
+}
+
+@test "dolt_tag creates and deletes tags" {
+    run dolt sql -q "SELECT dolt_tag('-m', 'the first release', 'v1')"
+    [ $status -eq 0 ]
+    run dolt tag -v
+    [[ "$output" =~ "v1" ]] || false
+    [[ "$output" =~ "the first release" ]] || false
+
+    run dolt sql -q "SELECT dolt_tag('-d', 'v1')"
+    [ $status -eq 0 ]
+    run dolt tag
+    [[ ! "$output" =~ "v1" ]] || false
+}
+
+@test "dolt_add and dolt_reset stage and unstage tables" {
+    dolt sql -q "INSERT INTO test VALUES (1, 1)"
+    run dolt sql -q "SELECT dolt_add('test')"
+    [ $status -eq 0 ]
+    run dolt status
+    [[ "$output" =~ "Changes to be committed" ]] || false
+
+    run dolt sql -q "SELECT dolt_reset('test')"
+    [ $status -eq 0 ]
+    run dolt status
+    [[ "$output" =~ "Changes not staged for commit" ]] || false
+
+    run dolt sql -q "SELECT dolt_reset('--hard')"
+    [ $status -eq 0 ]
+    run dolt status
+    [[ "$output" =~ "nothing to commit, working tree clean" ]] || false
+}
+
+@test "dolt_checkout discards changes to tables and creates branches" {
+    dolt sql -q "INSERT INTO test VALUES (1, 1)"
+    run dolt sql -q "SELECT dolt_checkout('test')"
+    [ $status -eq 0 ]
+    run dolt sql -q "SELECT count(*) FROM test" -r csv
+    [[ "$output" =~ "0" ]] || false
+
+    run dolt sql -q "SELECT dolt_checkout('-b', 'feature')"
+    [ $status -eq 0 ]
+    run dolt branch
+    [[ "$output" =~ "feature" ]] || false
+    [[ "$output" =~ "* master" ]] || false
+}
+
+@test "dolt_push, dolt_fetch and dolt_pull use file based remotes" {
+    mkdir remotedir
+    dolt remote add origin file://remotedir
+    run dolt sql -q "SELECT dolt_push('--set-upstream', 'origin', 'master')"
+    [ $status -eq 0 ]
+
+    mkdir dolt-repo-clones
+    cd dolt-repo-clones
+    dolt clone file://../remotedir test-repo
+    cd test-repo
+    dolt sql -q "INSERT INTO test VALUES (1, 1)"
+    dolt add test
+    dolt commit -m "added a row"
+    dolt push origin master
+    cd ../..
+
+    run dolt sql -q "SELECT dolt_fetch()"
+    [ $status -eq 0 ]
+    run dolt log remotes/origin/master
+    [[ "$output" =~ "added a row" ]] || false
+
+    run dolt sql -q "SELECT dolt_pull()"
+    [ $status -eq 0 ]
+    run dolt log
+    [[ "$output" =~ "added a row" ]] || false
+    run dolt sql -q "SELECT * FROM test" -r csv
+    [[ "$output" =~ "1,1" ]] || false
+}
@@ -548,6 +548,74 @@ SQL
     [[ "$output" =~ "InnerJoin" ]] || false
 }
 
+@test "explain analyze reports how dolt tables are read" {
+    run dolt sql -q "explain analyze select * from one_pk where c1 = 10"
+    [ $status -eq 0 ]
+    [[ "$output" =~ "Filter(one_pk.c1 = 10) (actual time=" ]] || false
+    [[ "$output" =~ "Table(one_pk) (actual time=" ]] || false
+    [[ "$output" =~ "rows=4 loops=1) [full scan; database-wide chunks read: " ]] || false
+
+    run dolt sql -q "explain analyze select * from one_pk where pk = 1"
+    [ $status -eq 0 ]
+    [[ "$output" =~ "rows=1 loops=1) [index PRIMARY: [1, 1]; database-wide chunks read: " ]] || false
+
+    run dolt sql -q "explain analyze select op.pk, pk1, pk2 from two_pk join one_pk as op on op.pk = pk1"
+    [ $status -eq 0 ]
+    [[ "$output" =~ "IndexedTableAccess(one_pk)" ]] || false
+    [[ "$output" =~ "loops=4) [index PRIMARY: [0, 0], [1, 1] (4 reads); database-wide chunks read: " ]] || false
+
+    dolt add one_pk
+    dolt commit -m "added one_pk"
+    run dolt sql -q "explain analyze select * from dolt_history_one_pk where pk = 0 or pk > 2"
+    [ $status -eq 0 ]
+    [[ "$output" =~ "[primary key: {0} U (2, +inf); database-wide chunks read: " ]] || false
+
+    run dolt sql -q "explain analyze insert into one_pk values (5, 5, 5, 5, 5, 5)"
+    [ $status -ne 0 ]
+}
+
+@test "analyze table collects statistics used to choose indexes" {
+    dolt sql <<SQL
+CREATE TABLE stats (pk BIGINT PRIMARY KEY, a BIGINT, b BIGINT, INDEX idx_a (a), INDEX idx_b (b));
+INSERT INTO stats VALUES (0,0,0),(1,1,1),(2,0,2),(3,1,3),(4,0,4),(5,1,5),(6,0,6),(7,1,7),(8,0,8),(9,1,9);
+SQL
+    dolt add .
+    dolt commit -m "add stats table"
+    run dolt sql -q "explain analyze select * from stats where a = 1 and b = 7"
+    [ $status -eq 0 ]
+    [[ "$output" =~ "[index idx_a: [1, 1]; database-wide chunks read: " ]] || false
+
+    run dolt sql -q "analyze table stats, missing"
+    [ $status -eq 0 ]
+    [[ "$output" =~ ".stats " ]] || false
+    [[ "$output" =~ "| analyze | status   | OK " ]] || false
+    [[ "$output" =~ ".missing' doesn't exist" ]] || false
+
+    run dolt status
+    [ $status -eq 0 ]
+    [[ "$output" =~ "nothing to commit, working tree clean" ]] || false
+
+    run dolt sql -q "explain analyze select * from stats where a = 1 and b = 7"
+    [ $status -eq 0 ]
+    [[ "$output" =~ "[index idx_b: [7, 7]; database-wide chunks read: " ]] || false
+
+    run dolt sql -r csv -q "select table_rows from information_schema.tables where table_name = 'stats'"
+    [ $status -eq 0 ]
+    [[ "${lines[1]}" = "10" ]] || false
+
+    run dolt sql -r csv -q "select column_name, distinct_count from dolt_statistics where table_name = 'stats' order by column_name"
+    [ $status -eq 0 ]
+    [[ "$output" =~ "a,2" ]] || false
+    [[ "$output" =~ "b,10" ]] || false
+
+    run dolt sql -q "select column_name from information_schema.column_statistics where table_name = 'stats'"
+    [ $status -eq 0 ]
+    [[ "$output" =~ "pk" ]] || false
+
+    run dolt sql -q "analyze table"
+    [ $status -ne 0 ]
+}
+
 @test "sql replace count" {
     skip "right now we always count a replace as a delete and insert when we shouldn't"
     dolt sql -q "CREATE TABLE test(pk BIGINT PRIMARY KEY, v BIGINT);"
@@ -139,7 +139,6 @@ SQL
 }
 
 @test "types: BLOB" {
-    skip "This is not yet persisted in dolt"
     dolt sql <<SQL
 CREATE TABLE test (
   pk BIGINT NOT NULL,
@@ -646,7 +645,6 @@ SQL
 }
 
 @test "types: LONGBLOB" {
-    skip "This is not yet persisted in dolt"
     dolt sql <<SQL
 CREATE TABLE test (
   pk BIGINT NOT NULL,
@@ -689,7 +687,6 @@ SQL
 }
 
 @test "types: MEDIUMBLOB" {
-    skip "This is not yet persisted in dolt"
     dolt sql <<SQL
 CREATE TABLE test (
   pk BIGINT NOT NULL,
@@ -1067,7 +1064,6 @@ SQL
 }
 
 @test "types: TINYBLOB" {
-    skip "This is not yet persisted in dolt"
     dolt sql <<SQL
 CREATE TABLE test (
   pk BIGINT NOT NULL,
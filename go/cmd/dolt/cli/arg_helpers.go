@@ -122,7 +122,7 @@ func parseTuples(args []string, pkCols *schema.ColCollection) ([]map[uint64]stri
 	return results, nil
 }
 
-func ParseKeyValues(nbf *types.NomsBinFormat, sch schema.Schema, args []string) ([]types.Value, error) {
+func ParseKeyValues(ctx context.Context, vrw types.ValueReadWriter, sch schema.Schema, args []string) ([]types.Value, error) {
 	pkCols := sch.GetPKCols()
 
 	var pkMaps []map[uint64]string
@@ -149,12 +149,15 @@ func ParseKeyValues(nbf *types.NomsBinFormat, sch schema.Schema, args []string)
 
 	convFuncs := make(map[uint64]func(*string) (types.Value, error))
 	err := sch.GetPKCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
-		if typeinfo.IsStringType(col.TypeInfo) {
+		if typeinfo.IsStringType(col.TypeInfo) && col.TypeInfo.NomsKind() == types.StringKind {
 			convFuncs[tag] = func(v *string) (types.Value, error) {
 				return types.String(*v), nil
 			}
 		} else {
-			convFuncs[tag] = col.TypeInfo.ParseValue
+			ti := col.TypeInfo
+			convFuncs[tag] = func(v *string) (types.Value, error) {
+				return ti.ParseValue(ctx, vrw, v)
+			}
 		}
 		return false, nil
 	})
@@ -176,7 +179,7 @@ func ParseKeyValues(nbf *types.NomsBinFormat, sch schema.Schema, args []string)
 			taggedVals[k] = val
 		}
 
-		tpl, err := taggedVals.NomsTupleForPKCols(nbf, pkCols).Value(context.TODO())
+		tpl, err := taggedVals.NomsTupleForPKCols(vrw.Format(), pkCols).Value(ctx)
 
 		if err != nil {
 			return nil, err
@@ -44,6 +44,7 @@ import (
 const (
 	remoteParam = "remote"
 	branchParam = "branch"
+	tablesParam = "tables"
 )
 
 var cloneDocs = cli.CommandDocumentationContent{
@@ -53,9 +54,11 @@ var cloneDocs = cli.CommandDocumentationContent{
 After the clone, a plain {{.EmphasisLeft}}dolt fetch{{.EmphasisRight}} without arguments will update all the remote-tracking branches, and a {{.EmphasisLeft}}dolt pull{{.EmphasisRight}} without arguments will in addition merge the remote branch into the current branch.
 
 This default configuration is achieved by creating references to the remote branch heads under {{.LessThan}}refs/remotes/origin{{.GreaterThan}}  and by creating a remote named 'origin'.
+
+When {{.EmphasisLeft}}--tables{{.EmphasisRight}} is provided, the history and schemas of every table are cloned, but row data is only downloaded for the listed tables. The data of other tables is retrieved from the remote the first time it is accessed.
 `,
 	Synopsis: []string{
-		"[-remote {{.LessThan}}remote{{.GreaterThan}}] [-branch {{.LessThan}}branch{{.GreaterThan}}]  [--aws-region {{.LessThan}}region{{.GreaterThan}}] [--aws-creds-type {{.LessThan}}creds-type{{.GreaterThan}}] [--aws-creds-file {{.LessThan}}file{{.GreaterThan}}] [--aws-creds-profile {{.LessThan}}profile{{.GreaterThan}}] {{.LessThan}}remote-url{{.GreaterThan}} {{.LessThan}}new-dir{{.GreaterThan}}",
+		"[-remote {{.LessThan}}remote{{.GreaterThan}}] [-branch {{.LessThan}}branch{{.GreaterThan}}] [--tables {{.LessThan}}table{{.GreaterThan}},...] [--aws-region {{.LessThan}}region{{.GreaterThan}}] [--aws-creds-type {{.LessThan}}creds-type{{.GreaterThan}}] [--aws-creds-file {{.LessThan}}file{{.GreaterThan}}] [--aws-creds-profile {{.LessThan}}profile{{.GreaterThan}}] {{.LessThan}}remote-url{{.GreaterThan}} {{.LessThan}}new-dir{{.GreaterThan}}",
 	},
 }
 
@@ -87,6 +90,7 @@ func (cmd CloneCmd) createArgParser() *argparser.ArgParser {
 	ap := argparser.NewArgParser()
 	ap.SupportsString(remoteParam, "", "name", "Name of the remote to be added. Default will be 'origin'.")
 	ap.SupportsString(branchParam, "b", "branch", "The branch to be cloned.  If not specified all branches will be cloned.")
+	ap.SupportsString(tablesParam, "", "tables", "Comma separated list of the tables whose row data should be cloned. If not specified the data of all tables will be cloned.")
 	ap.SupportsString(dbfactory.AWSRegionParam, "", "region", "")
 	ap.SupportsValidatedString(dbfactory.AWSCredsTypeParam, "", "creds-type", "", argparser.ValidatorFromStrList(dbfactory.AWSCredsTypeParam, credTypes))
 	ap.SupportsString(dbfactory.AWSCredsFileParam, "", "file", "AWS credentials file.")
@@ -107,6 +111,7 @@ func (cmd CloneCmd) Exec(ctx context.Context, commandStr string, args []string,
 
 	remoteName := apr.GetValueOrDefault(remoteParam, "origin")
 	branch := apr.GetValueOrDefault(branchParam, "")
+	tables, _ := parseTablesParam(apr)
 	dir, urlStr, verr := parseArgs(apr)
 
 	scheme, remoteUrl, err := getAbsRemoteUrl(dEnv.FS, dEnv.Config, urlStr)
@@ -128,7 +133,7 @@ func (cmd CloneCmd) Exec(ctx context.Context, commandStr string, args []string,
 				dEnv, verr = envForClone(ctx, srcDB.ValueReadWriter().Format(), r, dir, dEnv.FS, dEnv.Version)
 
 				if verr == nil {
-					verr = cloneRemote(ctx, srcDB, remoteName, branch, dEnv)
+					verr = cloneRemote(ctx, srcDB, remoteName, branch, tables, dEnv)
 
 					if verr == nil {
 						evt := events.GetEventFromContext(ctx)
@@ -275,20 +280,31 @@ func cloneProg(eventCh <-chan datas.TableFileEvent) {
 	cli.Println()
 }
 
-func cloneRemote(ctx context.Context, srcDB *doltdb.DoltDB, remoteName, branch string, dEnv *env.DoltEnv) errhand.VerboseError {
-	eventCh := make(chan datas.TableFileEvent, 128)
+func cloneRemote(ctx context.Context, srcDB *doltdb.DoltDB, remoteName, branch string, tables []string, dEnv *env.DoltEnv) errhand.VerboseError {
+	var err error
+	if len(tables) > 0 {
+		if verr := validateTableNames(tables); verr != nil {
+			return verr
+		}
 
-	wg := &sync.WaitGroup{}
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		cloneProg(eventCh)
-	}()
+		wg, progChan, pullerEventCh := runProgFuncs()
+		err = actions.CloneTables(ctx, dEnv, remoteName, tables, srcDB, progChan, pullerEventCh)
+		stopProgFuncs(wg, progChan, pullerEventCh)
+	} else {
+		eventCh := make(chan datas.TableFileEvent, 128)
 
-	err := actions.Clone(ctx, srcDB, dEnv.DoltDB, eventCh)
-	close(eventCh)
+		wg := &sync.WaitGroup{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cloneProg(eventCh)
+		}()
 
-	wg.Wait()
+		err = actions.Clone(ctx, srcDB, dEnv.DoltDB, eventCh)
+		close(eventCh)
+
+		wg.Wait()
+	}
 
 	if err != nil {
 		if err == datas.ErrNoData {
@@ -165,7 +165,7 @@ func printConflicts(ctx context.Context, root *doltdb.RootValue, tblNames []stri
 
 			defer cnfRd.Close()
 
-			splitter, err := merge.NewConflictSplitter(cnfRd.GetJoiner())
+			splitter, err := merge.NewConflictSplitter(ctx, tbl.ValueReadWriter(), cnfRd.GetJoiner())
 
 			if err != nil {
 				return errhand.BuildDError("error: unable to handle schemas").AddCause(err).Build()
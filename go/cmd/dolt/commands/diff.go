@@ -406,7 +406,7 @@ func diffUserTables(ctx context.Context, fromRoot, toRoot *doltdb.RootValue, dAr
 			} else if td.IsAdd() {
 				fromSch = toSch
 			}
-			verr = diffRows(ctx, fromMap, toMap, fromSch, toSch, dArgs, tblName)
+			verr = diffRows(ctx, toRoot.VRW(), fromMap, toMap, fromSch, toSch, dArgs, tblName)
 		}
 
 		if verr != nil {
@@ -631,7 +631,7 @@ func fromNamer(name string) string {
 	return diff.From + "_" + name
 }
 
-func diffRows(ctx context.Context, fromRows, toRows types.Map, fromSch, toSch schema.Schema, dArgs *diffArgs, tblName string) errhand.VerboseError {
+func diffRows(ctx context.Context, vrw types.ValueReadWriter, fromRows, toRows types.Map, fromSch, toSch schema.Schema, dArgs *diffArgs, tblName string) errhand.VerboseError {
 	joiner, err := rowconv.NewJoiner(
 		[]rowconv.NamedSchema{
 			{Name: diff.From, Sch: fromSch},
@@ -644,7 +644,7 @@ func diffRows(ctx context.Context, fromRows, toRows types.Map, fromSch, toSch sc
 		return errhand.BuildDError("").AddCause(err).Build()
 	}
 
-	unionSch, ds, verr := createSplitter(fromSch, toSch, joiner, dArgs)
+	unionSch, ds, verr := createSplitter(ctx, vrw, fromSch, toSch, joiner, dArgs)
 	if verr != nil {
 		return verr
 	}
@@ -678,7 +678,7 @@ func diffRows(ctx context.Context, fromRows, toRows types.Map, fromSch, toSch sc
 	if dArgs.diffOutput == TabularDiffOutput {
 		sink, err = diff.NewColorDiffSink(iohelp.NopWrCloser(cli.CliOut), unionSch, numHeaderRows)
 	} else {
-		sink, err = diff.NewSQLDiffSink(iohelp.NopWrCloser(cli.CliOut), unionSch, tblName)
+		sink, err = diff.NewSQLDiffSink(ctx, iohelp.NopWrCloser(cli.CliOut), unionSch, tblName)
 	}
 
 	if err != nil {
@@ -803,7 +803,7 @@ func mapTagToColName(sch, untypedUnionSch schema.Schema) (map[uint64]string, err
 	return tagToCol, nil
 }
 
-func createSplitter(fromSch schema.Schema, toSch schema.Schema, joiner *rowconv.Joiner, dArgs *diffArgs) (schema.Schema, *diff.DiffSplitter, errhand.VerboseError) {
+func createSplitter(ctx context.Context, vrw types.ValueReadWriter, fromSch schema.Schema, toSch schema.Schema, joiner *rowconv.Joiner, dArgs *diffArgs) (schema.Schema, *diff.DiffSplitter, errhand.VerboseError) {
 
 	var unionSch schema.Schema
 	if dArgs.diffOutput == TabularDiffOutput {
@@ -836,7 +836,7 @@ func createSplitter(fromSch schema.Schema, toSch schema.Schema, joiner *rowconv.
 			return nil, nil, errhand.BuildDError("Error creating unioned mapping").AddCause(err).Build()
 		}
 
-		newToUnionConv, _ = rowconv.NewRowConverter(newToUnionMapping)
+		newToUnionConv, _ = rowconv.NewRowConverter(ctx, vrw, newToUnionMapping)
 	}
 
 	oldToUnionConv := rowconv.IdentityConverter
@@ -847,7 +847,7 @@ func createSplitter(fromSch schema.Schema, toSch schema.Schema, joiner *rowconv.
 			return nil, nil, errhand.BuildDError("Error creating unioned mapping").AddCause(err).Build()
 		}
 
-		oldToUnionConv, _ = rowconv.NewRowConverter(oldToUnionMapping)
+		oldToUnionConv, _ = rowconv.NewRowConverter(ctx, vrw, oldToUnionMapping)
 	}
 
 	ds := diff.NewDiffSplitter(joiner, oldToUnionConv, newToUnionConv)
@@ -16,6 +16,8 @@ package commands
 
 import (
 	"context"
+	"errors"
+	"strings"
 
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
 	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
@@ -28,6 +30,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/utils/argparser"
 	"github.com/dolthub/dolt/go/libraries/utils/earl"
 	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/libraries/utils/funcitr"
 )
 
 const (
@@ -41,10 +44,12 @@ var fetchDocs = cli.CommandDocumentationContent{
 By default dolt will attempt to fetch from a remote named {{.EmphasisLeft}}origin{{.EmphasisRight}}.  The {{.LessThan}}remote{{.GreaterThan}} parameter allows you to specify the name of a different remote you wish to pull from by the remote's name.
 
 When no refspec(s) are specified on the command line, the fetch_specs for the default remote are used.
+
+When {{.EmphasisLeft}}--tables{{.EmphasisRight}} is provided, the history and schemas of every table are fetched, but row data is only fetched for the listed tables. The data of other tables is retrieved from the remote the first time it is accessed. Subsequent fetches and pulls will continue to fetch only the listed tables.
 `,
 
 	Synopsis: []string{
-		"[{{.LessThan}}remote{{.GreaterThan}}] [{{.LessThan}}refspec{{.GreaterThan}} ...]",
+		"[--tables {{.LessThan}}table{{.GreaterThan}},...] [{{.LessThan}}remote{{.GreaterThan}}] [{{.LessThan}}refspec{{.GreaterThan}} ...]",
 	},
 }
 
@@ -74,6 +79,7 @@ func (cmd FetchCmd) CreateMarkdown(fs filesys.Filesys, path, commandStr string)
 func (cmd FetchCmd) createArgParser() *argparser.ArgParser {
 	ap := argparser.NewArgParser()
 	ap.SupportsFlag(ForceFetchFlag, "f", "Update refs to remote branches with the current state of the remote, overwriting any conflicting history.")
+	ap.SupportsString(tablesParam, "", "tables", "Comma separated list of the tables whose row data should be fetched.")
 	return ap
 }
 
@@ -88,6 +94,12 @@ func (cmd FetchCmd) Exec(ctx context.Context, commandStr string, args []string,
 
 	updateMode := ref.RefUpdateMode{Force: apr.Contains(ForceFetchFlag)}
 
+	if verr == nil {
+		if tables, ok := parseTablesParam(apr); ok {
+			verr = setSparseTables(dEnv, r.Name, tables)
+		}
+	}
+
 	if verr == nil {
 		verr = fetchRefSpecs(ctx, updateMode, dEnv, r, refSpecs)
 	}
@@ -95,6 +107,41 @@ func (cmd FetchCmd) Exec(ctx context.Context, commandStr string, args []string,
 	return HandleVErrAndExitCode(verr, usage)
 }
 
+// parseTablesParam returns the list of tables given with the --tables parameter
+func parseTablesParam(apr *argparser.ArgParseResults) ([]string, bool) {
+	val, ok := apr.GetValue(tablesParam)
+
+	if !ok {
+		return nil, false
+	}
+
+	return funcitr.MapStrings(strings.Split(val, ","), strings.TrimSpace), true
+}
+
+func validateTableNames(tables []string) errhand.VerboseError {
+	for _, tbl := range tables {
+		if !doltdb.IsValidTableName(tbl) {
+			return errhand.BuildDError("error: '%s' is not a valid table name", tbl).Build()
+		}
+	}
+
+	return nil
+}
+
+func setSparseTables(dEnv *env.DoltEnv, remoteName string, tables []string) errhand.VerboseError {
+	if verr := validateTableNames(tables); verr != nil {
+		return verr
+	}
+
+	err := dEnv.SetSparse(remoteName, tables)
+
+	if err != nil {
+		return errhand.BuildDError("error: unable to update repo state").AddCause(err).Build()
+	}
+
+	return nil
+}
+
 func getRefSpecs(args []string, dEnv *env.DoltEnv, remotes map[string]env.Remote) (env.Remote, []ref.RemoteRefSpec, errhand.VerboseError) {
 	if len(remotes) == 0 {
 		return env.NoRemote, nil, errhand.BuildDError("error: no remotes set").AddDetails("to add a remote run: dolt remote add <remote> <url>").Build()
@@ -132,30 +179,12 @@ func getRefSpecs(args []string, dEnv *env.DoltEnv, remotes map[string]env.Remote
 }
 
 func parseRSFromArgs(remName string, args []string) ([]ref.RemoteRefSpec, errhand.VerboseError) {
-	var refSpecs []ref.RemoteRefSpec
-	for i := 0; i < len(args); i++ {
-		rsStr := args[i]
-		rs, err := ref.ParseRefSpec(rsStr)
+	refSpecs, err := actions.ParseRemoteRefSpecs(remName, args)
 
-		if err != nil {
-			return nil, errhand.BuildDError("error: '%s' is not a valid refspec.", rsStr).SetPrintUsage().Build()
-		}
-
-		if _, ok := rs.(ref.BranchToBranchRefSpec); ok {
-			local := "refs/heads/" + rsStr
-			remTracking := "remotes/" + remName + "/" + rsStr
-			rs2, err := ref.ParseRefSpec(local + ":" + remTracking)
-
-			if err == nil {
-				rs = rs2
-			}
-		}
-
-		if rrs, ok := rs.(ref.RemoteRefSpec); !ok {
-			return nil, errhand.BuildDError("error: '%s' is not a valid refspec referring to a remote tracking branch", rsStr).Build()
-		} else {
-			refSpecs = append(refSpecs, rrs)
-		}
+	if errors.Is(err, ref.ErrInvalidRefSpec) {
+		return nil, errhand.BuildDError("error: %s", err.Error()).SetPrintUsage().Build()
+	} else if err != nil {
+		return nil, errhand.BuildDError("error: %s", err.Error()).Build()
 	}
 
 	return refSpecs, nil
@@ -185,54 +214,37 @@ func fetchRefSpecs(ctx context.Context, mode ref.RefUpdateMode, dEnv *env.DoltEn
 		return errhand.BuildDError("error: failed to get remote db").AddCause(err).Build()
 	}
 
-	for _, rs := range refSpecs {
+	setRemoteURLSchemeAttribute(ctx, rem)
 
-		branchRefs, err := srcDB.GetRefs(ctx)
-
-		if err != nil {
-			return errhand.BuildDError("error: failed to read from ").AddCause(err).Build()
-		}
+	wg, progChan, pullerEventCh := runProgFuncs()
+	err = actions.FetchRefSpecs(ctx, dEnv, mode, rem, srcDB, refSpecs, progChan, pullerEventCh)
+	stopProgFuncs(wg, progChan, pullerEventCh)
 
-		for _, branchRef := range branchRefs {
-			remoteTrackRef := rs.DestRef(branchRef)
-
-			if remoteTrackRef != nil {
-				srcDBCommit, verr := fetchRemoteBranch(ctx, dEnv, rem, srcDB, dEnv.DoltDB, branchRef, remoteTrackRef)
-
-				if verr != nil {
-					return verr
-				}
-
-				switch mode {
-				case ref.ForceUpdate:
-					err = dEnv.DoltDB.SetHeadToCommit(ctx, remoteTrackRef, srcDBCommit)
-				case ref.FastForwardOnly:
-					ok, err := dEnv.DoltDB.CanFastForward(ctx, remoteTrackRef, srcDBCommit)
-					if !ok {
-						return errhand.BuildDError("error: fetch failed, can't fast forward remote tracking ref").Build()
-					}
-					if err == nil {
-						err = dEnv.DoltDB.FastForward(ctx, remoteTrackRef, srcDBCommit)
-					}
-				}
-
-				if err != nil {
-					return errhand.BuildDError("error: fetch failed").AddCause(err).Build()
-				}
-			}
-		}
+	if err == actions.ErrCantFFRemoteTrackingRef {
+		return errhand.BuildDError("error: fetch failed, can't fast forward remote tracking ref").Build()
+	} else if err != nil {
+		return errhand.BuildDError("error: fetch failed").AddCause(err).Build()
 	}
 
-	verr := fetchFollowTags(ctx, dEnv, srcDB, dEnv.DoltDB)
+	return nil
+}
 
-	if verr != nil {
-		return verr
+func fetchRemoteBranch(ctx context.Context, dEnv *env.DoltEnv, rem env.Remote, srcDB, destDB *doltdb.DoltDB, srcRef ref.DoltRef) (*doltdb.Commit, errhand.VerboseError) {
+	setRemoteURLSchemeAttribute(ctx, rem)
+
+	wg, progChan, pullerEventCh := runProgFuncs()
+	srcDBCommit, err := actions.FetchRemoteBranch(ctx, dEnv, rem, srcDB, destDB, srcRef, progChan, pullerEventCh)
+	stopProgFuncs(wg, progChan, pullerEventCh)
+
+	if err != nil {
+		return nil, errhand.BuildDError("error: fetch failed").AddCause(err).Build()
 	}
 
-	return nil
+	return srcDBCommit, nil
 }
 
-func fetchRemoteBranch(ctx context.Context, dEnv *env.DoltEnv, rem env.Remote, srcDB, destDB *doltdb.DoltDB, srcRef, destRef ref.DoltRef) (*doltdb.Commit, errhand.VerboseError) {
+// setRemoteURLSchemeAttribute records the scheme of the remote's url on the event of the command being run
+func setRemoteURLSchemeAttribute(ctx context.Context, rem env.Remote) {
 	evt := events.GetEventFromContext(ctx)
 
 	u, err := earl.Parse(rem.Url)
@@ -242,72 +254,14 @@ func fetchRemoteBranch(ctx context.Context, dEnv *env.DoltEnv, rem env.Remote, s
 			evt.SetAttribute(eventsapi.AttributeID_REMOTE_URL_SCHEME, u.Scheme)
 		}
 	}
-
-	cs, _ := doltdb.NewCommitSpec(srcRef.String())
-	srcDBCommit, err := srcDB.Resolve(ctx, cs, nil)
-
-	if err != nil {
-		return nil, errhand.BuildDError("error: unable to find '%s' on '%s'", srcRef.GetPath(), rem.Name).Build()
-	} else {
-		wg, progChan, pullerEventCh := runProgFuncs()
-		err = actions.FetchCommit(ctx, dEnv, srcDB, destDB, srcDBCommit, progChan, pullerEventCh)
-		stopProgFuncs(wg, progChan, pullerEventCh)
-
-		if err != nil {
-			return nil, errhand.BuildDError("error: fetch failed").AddCause(err).Build()
-		}
-	}
-
-	return srcDBCommit, nil
 }
 
 // fetchFollowTags fetches all tags from the source DB whose commits have already
 // been fetched into the destination DB.
-// todo: potentially too expensive to iterate over all srcDB tags
 func fetchFollowTags(ctx context.Context, dEnv *env.DoltEnv, srcDB, destDB *doltdb.DoltDB) errhand.VerboseError {
-	err := actions.IterResolvedTags(ctx, srcDB, func(tag *doltdb.Tag) (stop bool, err error) {
-		stRef, err := tag.GetStRef()
-		if err != nil {
-			return true, err
-		}
-
-		tagHash := stRef.TargetHash()
-
-		tv, err := destDB.ValueReadWriter().ReadValue(ctx, tagHash)
-		if err != nil {
-			return true, err
-		}
-		if tv != nil {
-			// tag is already fetched
-			return false, nil
-		}
-
-		cmHash, err := tag.Commit.HashOf()
-		if err != nil {
-			return true, err
-		}
-
-		cv, err := destDB.ValueReadWriter().ReadValue(ctx, cmHash)
-		if err != nil {
-			return true, err
-		}
-		if cv == nil {
-			// neither tag nor commit has been fetched
-			return false, nil
-		}
-
-		wg, progChan, pullerEventCh := runProgFuncs()
-		err = actions.FetchTag(ctx, dEnv, srcDB, destDB, tag, progChan, pullerEventCh)
-		stopProgFuncs(wg, progChan, pullerEventCh)
-
-		if err != nil {
-			return true, err
-		}
-
-		err = destDB.SetHead(ctx, tag.GetDoltRef(), stRef)
-
-		return false, err
-	})
+	wg, progChan, pullerEventCh := runProgFuncs()
+	err := actions.FetchFollowTags(ctx, dEnv, srcDB, destDB, progChan, pullerEventCh)
+	stopProgFuncs(wg, progChan, pullerEventCh)
 
 	if err != nil {
 		return errhand.VerboseErrorFromError(err)
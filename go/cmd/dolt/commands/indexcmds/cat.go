@@ -238,7 +238,7 @@ func (cmd CatCmd) prettyPrintResults(ctx context.Context, doltSch schema.Schema,
 				}
 				if val != types.NullValue {
 					tag := uint64(tagVal.(types.Uint))
-					strPtr, err := doltSch.GetAllCols().TagToCol[tag].TypeInfo.FormatValue(val)
+					strPtr, err := doltSch.GetAllCols().TagToCol[tag].TypeInfo.FormatValue(ctx, val)
 					if err != nil {
 						return nil, err
 					}
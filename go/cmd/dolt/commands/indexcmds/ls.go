@@ -23,6 +23,7 @@ import (
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
 	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlfmt"
 	"github.com/dolthub/dolt/go/libraries/utils/argparser"
 	"github.com/dolthub/dolt/go/libraries/utils/filesys"
 )
@@ -109,7 +110,11 @@ func (cmd LsCmd) Exec(ctx context.Context, commandStr string, args []string, dEn
 				output = append(output, fmt.Sprintf("%s:", tableName))
 			}
 			for _, index := range sch.Indexes().AllIndexes() {
-				output = append(output, fmt.Sprintf("    %s(%s)", index.Name(), strings.Join(index.ColumnNames(), ", ")))
+				keyParts := strings.Join(index.ColumnNames(), ", ")
+				if len(index.Expressions()) > 0 || index.PrefixLengths() != nil {
+					keyParts = sqlfmt.FmtIndexKeyParts(index)
+				}
+				output = append(output, fmt.Sprintf("    %s(%s)", index.Name(), keyParts))
 			}
 		}
 	}
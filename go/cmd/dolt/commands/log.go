@@ -16,8 +16,12 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/fatih/color"
 
@@ -33,52 +37,77 @@ import (
 
 const (
 	numLinesParam = "number"
+	graphParam    = "graph"
+	onelineParam  = "oneline"
+	authorParam   = "author"
+	sinceParam    = "since"
+	untilParam    = "until"
+	mergesParam   = "merges"
+	noMergesParam = "no-merges"
 )
 
 var logDocs = cli.CommandDocumentationContent{
 	ShortDesc: `Show commit logs`,
 	LongDesc: `Shows the commit logs
 
-The command takes options to control what is shown and how.`,
+The command takes options to control what is shown and how.
+
+{{.EmphasisLeft}}--graph{{.EmphasisRight}} draws the history of the commits shown as a text-based graph on the left side of the output, and {{.EmphasisLeft}}--oneline{{.EmphasisRight}} shows each commit on a single line, with its hash and the first line of its message.
+
+The commits shown can be limited with {{.EmphasisLeft}}--author{{.EmphasisRight}}, which matches a regular expression against the author of each commit formatted as {{.EmphasisLeft}}name <email>{{.EmphasisRight}}, with {{.EmphasisLeft}}--since{{.EmphasisRight}} and {{.EmphasisLeft}}--until{{.EmphasisRight}}, which take dates in the formats {{.LessThan}}YYYY-MM-DD{{.GreaterThan}}, {{.LessThan}}YYYY-MM-DDTHH:MM:SS{{.GreaterThan}}, or {{.LessThan}}YYYY-MM-DDTHH:MM:SSZ07:00{{.GreaterThan}}, and with {{.EmphasisLeft}}--merges{{.EmphasisRight}} or {{.EmphasisLeft}}--no-merges{{.EmphasisRight}}.
+
+Table names given after {{.EmphasisLeft}}--{{.EmphasisRight}} limit the commits shown to those which changed any of the tables. A commit changed a table if the table is different than it is in each of the commit's parents, so a merge commit which takes a table unchanged from one of its parents isn't shown.
+
+{{.EmphasisLeft}}-n{{.EmphasisRight}} limits the number of commits shown after the other limits are applied.`,
 	Synopsis: []string{
-		`[-n {{.LessThan}}num_commits{{.GreaterThan}}] [{{.LessThan}}commit{{.GreaterThan}}]`,
+		`[-n {{.LessThan}}num_commits{{.GreaterThan}}] [--graph] [--oneline] [--author {{.LessThan}}pattern{{.GreaterThan}}] [--since {{.LessThan}}date{{.GreaterThan}}] [--until {{.LessThan}}date{{.GreaterThan}}] [--merges | --no-merges] [{{.LessThan}}commit{{.GreaterThan}}] [-- {{.LessThan}}table{{.GreaterThan}}...]`,
 	},
 }
 
-type commitLoggerFunc func(*doltdb.CommitMeta, []hash.Hash, hash.Hash)
+type commitFormatterFunc func(*doltdb.CommitMeta, []hash.Hash, hash.Hash) []string
 
-func logToStdOutFunc(cm *doltdb.CommitMeta, parentHashes []hash.Hash, ch hash.Hash) {
-	cli.Println(color.YellowString("commit %s", ch.String()))
+func formatCommit(cm *doltdb.CommitMeta, parentHashes []hash.Hash, ch hash.Hash) []string {
+	lines := []string{color.YellowString("commit %s", ch.String())}
 
 	if len(parentHashes) > 1 {
-		printMerge(parentHashes)
+		lines = append(lines, formatMerge(parentHashes))
 	}
 
-	printAuthor(cm)
-	printDate(cm)
-	printDesc(cm)
+	lines = append(lines, formatAuthor(cm), formatDate(cm))
+	return append(lines, formatDesc(cm)...)
 }
 
-func printMerge(hashes []hash.Hash) {
-	cli.Print("Merge:")
+func formatCommitOneline(cm *doltdb.CommitMeta, _ []hash.Hash, ch hash.Hash) []string {
+	desc := cm.Description
+	if i := strings.IndexByte(desc, '\n'); i >= 0 {
+		desc = desc[:i]
+	}
+
+	return []string{color.YellowString(ch.String()) + " " + desc}
+}
+
+func formatMerge(hashes []hash.Hash) string {
+	line := "Merge:"
 	for _, h := range hashes {
-		cli.Print(" " + h.String())
+		line += " " + h.String()
 	}
-	cli.Println()
+	return line
 }
 
-func printAuthor(cm *doltdb.CommitMeta) {
-	cli.Printf("Author: %s <%s>\n", cm.Name, cm.Email)
+func formatAuthor(cm *doltdb.CommitMeta) string {
+	return fmt.Sprintf("Author: %s <%s>", cm.Name, cm.Email)
 }
 
-func printDate(cm *doltdb.CommitMeta) {
-	timeStr := cm.FormatTS()
-	cli.Println("Date:  ", timeStr)
+func formatDate(cm *doltdb.CommitMeta) string {
+	return "Date:   " + cm.FormatTS()
 }
 
-func printDesc(cm *doltdb.CommitMeta) {
-	formattedDesc := "\n\t" + strings.Replace(cm.Description, "\n", "\n\t", -1) + "\n"
-	cli.Println(formattedDesc)
+func formatDesc(cm *doltdb.CommitMeta) []string {
+	lines := []string{""}
+	for _, line := range strings.Split(cm.Description, "\n") {
+		lines = append(lines, "\t"+line)
+	}
+	return append(lines, "")
 }
 
 type LogCmd struct{}
@@ -107,32 +136,63 @@ func (cmd LogCmd) CreateMarkdown(fs filesys.Filesys, path, commandStr string) er
 func createLogArgParser() *argparser.ArgParser {
 	ap := argparser.NewArgParser()
 	ap.SupportsInt(numLinesParam, "n", "num_commits", "Limit the number of commits to output")
+	ap.SupportsFlag(graphParam, "", "Draw a text-based graph of the commit history on the left side of the output.")
+	ap.SupportsFlag(onelineParam, "", "Show each commit on a single line, with its hash and the first line of its message.")
+	ap.SupportsString(authorParam, "", "pattern", "Only show commits whose author, formatted as {{.EmphasisLeft}}name <email>{{.EmphasisRight}}, matches the regular expression given.")
+	ap.SupportsString(sinceParam, "", "date", "Only show commits made at or after the date given.")
+	ap.SupportsString(untilParam, "", "date", "Only show commits made at or before the date given.")
+	ap.SupportsFlag(mergesParam, "", "Only show merge commits.")
+	ap.SupportsFlag(noMergesParam, "", "Don't show merge commits.")
 	return ap
 }
 
 // Exec executes the command
 func (cmd LogCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv) int {
-	return logWithLoggerFunc(ctx, commandStr, args, dEnv, logToStdOutFunc)
-}
-
-func logWithLoggerFunc(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, loggerFunc commitLoggerFunc) int {
 	ap := createLogArgParser()
 	help, usage := cli.HelpAndUsagePrinters(cli.GetCommandDocumentation(commandStr, logDocs, ap))
 	apr := cli.ParseArgs(ap, args, help)
 
-	if apr.NArg() > 1 {
+	revArgs, tables := splitLogArgs(apr.Args())
+	if len(revArgs) > 1 {
 		usage()
 		return 1
 	}
 
-	cs, err := parseCommitSpec(dEnv, apr)
+	cs := dEnv.RepoState.CWBHeadSpec()
+	if len(revArgs) == 1 {
+		var err error
+		cs, err = doltdb.NewCommitSpec(revArgs[0])
+
+		if err != nil {
+			cli.PrintErrln(color.RedString("invalid commit %s", revArgs[0]))
+			return 1
+		}
+	}
+
+	filter, err := parseLogFilter(apr, tables)
 	if err != nil {
-		cli.PrintErr(err)
+		cli.PrintErrln(color.RedString(err.Error()))
+		usage()
 		return 1
 	}
 
+	formatter := formatCommit
+	if apr.Contains(onelineParam) {
+		formatter = formatCommitOneline
+	}
+
 	numLines := apr.GetIntOrDefault(numLinesParam, -1)
-	return logCommits(ctx, dEnv, cs, loggerFunc, numLines)
+	return logCommits(ctx, dEnv, cs, filter, formatter, apr.Contains(graphParam), numLines)
+}
+
+// splitLogArgs splits the arguments of the log command into the arguments before --, and the table names after it.
+func splitLogArgs(args []string) ([]string, []string) {
+	for i, arg := range args {
+		if arg == "--" {
+			return args[:i], args[i+1:]
+		}
+	}
+	return args, nil
 }
 
 func parseCommitSpec(dEnv *env.DoltEnv, apr *argparser.ArgParseResults) (*doltdb.CommitSpec, error) {
@@ -150,7 +210,220 @@ func parseCommitSpec(dEnv *env.DoltEnv, apr *argparser.ArgParseResults) (*doltdb
 	return cs, nil
 }
 
-func logCommits(ctx context.Context, dEnv *env.DoltEnv, cs *doltdb.CommitSpec, loggerFunc commitLoggerFunc, numLines int) int {
+// logFilter decides which commits are shown by the log command.
+type logFilter struct {
+	author   *regexp.Regexp
+	since    *time.Time
+	until    *time.Time
+	merges   bool
+	noMerges bool
+	tables   []string
+
+	// shown and parents memoize which commits are shown, and the closest shown ancestors of commits.
+	shown   map[hash.Hash]bool
+	parents map[hash.Hash][]hash.Hash
+}
+
+func parseLogFilter(apr *argparser.ArgParseResults, tables []string) (*logFilter, error) {
+	if apr.Contains(mergesParam) && apr.Contains(noMergesParam) {
+		return nil, errors.New("error: --merges and --no-merges cannot be used together")
+	}
+
+	filter := &logFilter{
+		merges:   apr.Contains(mergesParam),
+		noMerges: apr.Contains(noMergesParam),
+		tables:   tables,
+		shown:    make(map[hash.Hash]bool),
+		parents:  make(map[hash.Hash][]hash.Hash),
+	}
+
+	if pattern, ok := apr.GetValue(authorParam); ok {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("error: invalid --author pattern: %v", err)
+		}
+		filter.author = re
+	}
+
+	for _, param := range []string{sinceParam, untilParam} {
+		if dateStr, ok := apr.GetValue(param); ok {
+			t, err := parseDate(dateStr)
+			if err != nil {
+				return nil, err
+			}
+
+			if param == sinceParam {
+				filter.since = &t
+			} else {
+				filter.until = &t
+			}
+		}
+	}
+
+	return filter, nil
+}
+
+// isEmpty returns whether the filter shows every commit.
+func (lf *logFilter) isEmpty() bool {
+	return lf.author == nil && lf.since == nil && lf.until == nil && !lf.merges && !lf.noMerges && len(lf.tables) == 0
+}
+
+// matches returns whether the commit given is shown.
+func (lf *logFilter) matches(ctx context.Context, ddb *doltdb.DoltDB, h hash.Hash, cm *doltdb.Commit) (bool, error) {
+	if shown, ok := lf.shown[h]; ok {
+		return shown, nil
+	}
+
+	shown, err := lf.evaluate(ctx, ddb, cm)
+	if err != nil {
+		return false, err
+	}
+
+	lf.shown[h] = shown
+	return shown, nil
+}
+
+func (lf *logFilter) evaluate(ctx context.Context, ddb *doltdb.DoltDB, cm *doltdb.Commit) (bool, error) {
+	meta, err := cm.GetCommitMeta()
+	if err != nil {
+		return false, err
+	}
+
+	if lf.author != nil && !lf.author.MatchString(fmt.Sprintf("%s <%s>", meta.Name, meta.Email)) {
+		return false, nil
+	}
+
+	ts := meta.Time()
+	if lf.since != nil && ts.Before(*lf.since) {
+		return false, nil
+	} else if lf.until != nil && ts.After(*lf.until) {
+		return false, nil
+	}
+
+	numParents, err := cm.NumParents()
+	if err != nil {
+		return false, err
+	}
+
+	if lf.merges && numParents < 2 {
+		return false, nil
+	} else if lf.noMerges && numParents > 1 {
+		return false, nil
+	}
+
+	if len(lf.tables) == 0 {
+		return true, nil
+	}
+
+	return changesTables(ctx, ddb, cm, numParents, lf.tables)
+}
+
+// changesTables returns whether any of the tables named are different in the commit given than they are in each of
+// its parents.  A table is changed by a commit without parents if it exists in the commit.
+func changesTables(ctx context.Context, ddb *doltdb.DoltDB, cm *doltdb.Commit, numParents int, tables []string) (bool, error) {
+	root, err := cm.GetRootValue()
+	if err != nil {
+		return false, err
+	}
+
+	parentRoots := make([]*doltdb.RootValue, numParents)
+	for i := range parentRoots {
+		parent, err := ddb.ResolveParent(ctx, cm, i)
+		if err != nil {
+			return false, err
+		}
+
+		parentRoots[i], err = parent.GetRootValue()
+		if err != nil {
+			return false, err
+		}
+	}
+
+	for _, tbl := range tables {
+		h, ok, err := root.GetTableHash(ctx, tbl)
+		if err != nil {
+			return false, err
+		}
+
+		changed := len(parentRoots) > 0 || ok
+		for _, parentRoot := range parentRoots {
+			parentH, parentOk, err := parentRoot.GetTableHash(ctx, tbl)
+			if err != nil {
+				return false, err
+			}
+
+			if parentOk == ok && parentH == h {
+				changed = false
+				break
+			}
+		}
+
+		if changed {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// shownParents returns the closest ancestors of the commit given on each of its lines of history which are shown, so
+// that the graph connects shown commits through the commits which aren't shown.
+func (lf *logFilter) shownParents(ctx context.Context, ddb *doltdb.DoltDB, h hash.Hash, cm *doltdb.Commit) ([]hash.Hash, error) {
+	if parents, ok := lf.parents[h]; ok {
+		return parents, nil
+	}
+
+	numParents, err := cm.NumParents()
+	if err != nil {
+		return nil, err
+	}
+
+	var parents []hash.Hash
+	add := func(ph hash.Hash) {
+		for _, existing := range parents {
+			if existing == ph {
+				return
+			}
+		}
+		parents = append(parents, ph)
+	}
+
+	for i := 0; i < numParents; i++ {
+		parent, err := ddb.ResolveParent(ctx, cm, i)
+		if err != nil {
+			return nil, err
+		}
+
+		ph, err := parent.HashOf()
+		if err != nil {
+			return nil, err
+		}
+
+		shown, err := lf.matches(ctx, ddb, ph, parent)
+		if err != nil {
+			return nil, err
+		}
+
+		if shown {
+			add(ph)
+			continue
+		}
+
+		ancestors, err := lf.shownParents(ctx, ddb, ph, parent)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, ah := range ancestors {
+			add(ah)
+		}
+	}
+
+	lf.parents[h] = parents
+	return parents, nil
+}
+
+func logCommits(ctx context.Context, dEnv *env.DoltEnv, cs *doltdb.CommitSpec, filter *logFilter, formatter commitFormatterFunc, drawGraph bool, numLines int) int {
 	commit, err := dEnv.DoltDB.Resolve(ctx, cs, dEnv.RepoState.CWBHeadRef())
 
 	if err != nil {
@@ -165,14 +438,37 @@ func logCommits(ctx context.Context, dEnv *env.DoltEnv, cs *doltdb.CommitSpec, l
 		return 1
 	}
 
-	commits, err := commitwalk.GetTopNTopoOrderedCommits(ctx, dEnv.DoltDB, h, numLines)
+	itr, err := commitwalk.GetTopologicalOrderIterator(ctx, dEnv.DoltDB, h)
 
 	if err != nil {
 		cli.PrintErrln("Error retrieving commit.")
 		return 1
 	}
 
-	for _, comm := range commits {
+	var graph *commitGraph
+	if drawGraph {
+		graph = &commitGraph{}
+	}
+
+	for shown := 0; numLines < 0 || shown < numLines; {
+		cmHash, comm, err := itr.Next(ctx)
+
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			cli.PrintErrln("Error retrieving commit.")
+			return 1
+		}
+
+		ok, err := filter.matches(ctx, dEnv.DoltDB, cmHash, comm)
+
+		if err != nil {
+			cli.PrintErrln("error: failed to filter commits:", err.Error())
+			return 1
+		} else if !ok {
+			continue
+		}
+
 		meta, err := comm.GetCommitMeta()
 
 		if err != nil {
@@ -187,13 +483,27 @@ func logCommits(ctx context.Context, dEnv *env.DoltEnv, cs *doltdb.CommitSpec, l
 			return 1
 		}
 
-		cmHash, err := comm.HashOf()
+		lines := formatter(meta, pHashes, cmHash)
 
-		if err != nil {
-			cli.PrintErrln("error: failed to get commit hash")
-			return 1
+		if graph != nil {
+			graphParents := pHashes
+			if !filter.isEmpty() {
+				graphParents, err = filter.shownParents(ctx, dEnv.DoltDB, cmHash, comm)
+
+				if err != nil {
+					cli.PrintErrln("error: failed to get parent hashes")
+					return 1
+				}
+			}
+
+			lines = graph.render(cmHash, graphParents, lines)
+		}
+
+		for _, line := range lines {
+			cli.Println(line)
 		}
-		loggerFunc(meta, pHashes, cmHash)
+
+		shown++
 	}
 
 	return 0
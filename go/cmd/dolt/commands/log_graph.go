@@ -0,0 +1,152 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"strings"
+
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// commitGraph draws the graph of dolt log --graph.  Commits must be rendered in topological order, children before
+// their parents.  The graph has a column for each line of history, which holds the hash of the next commit shown on
+// it.
+type commitGraph struct {
+	columns []hash.Hash
+}
+
+// graphEdge is the line connecting a column before a commit to a column after it.
+type graphEdge struct {
+	from, to int
+}
+
+// render returns the lines describing a commit prefixed with the graph.  The first line is prefixed with the commit's
+// column marked with a *, and the following lines with the lines connecting the commit to its parents, and the other
+// columns to their new positions.
+func (g *commitGraph) render(h hash.Hash, parents []hash.Hash, lines []string) []string {
+	idx := indexOfHash(g.columns, h)
+	if idx < 0 {
+		g.columns = append(g.columns, h)
+		idx = len(g.columns) - 1
+	}
+
+	// the commit's column is replaced by its parents, and columns which are waiting for the same commit are joined
+	var next []hash.Hash
+	var edges []graphEdge
+	addEdge := func(from int, h hash.Hash) {
+		to := indexOfHash(next, h)
+		if to < 0 {
+			next = append(next, h)
+			to = len(next) - 1
+		}
+		edges = append(edges, graphEdge{from, to})
+	}
+
+	for i, col := range g.columns {
+		if i != idx {
+			addEdge(i, col)
+			continue
+		}
+
+		for _, parent := range parents {
+			addEdge(i, parent)
+		}
+	}
+
+	width := len(g.columns)
+	if len(next) > width {
+		width = len(next)
+	}
+
+	commitRow := newGraphRow(width)
+	for i := range g.columns {
+		commitRow[2*i] = '|'
+	}
+	commitRow[2*idx] = '*'
+
+	prefixes := append([][]byte{commitRow}, connectingRows(edges, width)...)
+
+	restRow := newGraphRow(width)
+	for i := range next {
+		restRow[2*i] = '|'
+	}
+
+	g.columns = next
+
+	var graphLines []string
+	for i := 0; i < len(lines) || i < len(prefixes); i++ {
+		prefix := restRow
+		if i < len(prefixes) {
+			prefix = prefixes[i]
+		}
+
+		line := string(prefix)
+		if i < len(lines) {
+			line += lines[i]
+		}
+		graphLines = append(graphLines, strings.TrimRight(line, " "))
+	}
+
+	return graphLines
+}
+
+// connectingRows returns the rows of the graph connecting the columns before a commit to the columns after it.  Each
+// row moves an edge at most one column, drawn with / or \, and edges which have reached their column are drawn with |.
+// There are no rows if no edge moves.
+func connectingRows(edges []graphEdge, width int) [][]byte {
+	var rows [][]byte
+	for {
+		moving := false
+		for _, e := range edges {
+			if e.from != e.to {
+				moving = true
+				break
+			}
+		}
+
+		if !moving {
+			return rows
+		}
+
+		row := newGraphRow(width)
+		for i, e := range edges {
+			switch {
+			case e.from == e.to:
+				row[2*e.from] = '|'
+			case e.to < e.from:
+				row[2*e.from-1] = '/'
+				edges[i].from--
+			default:
+				row[2*e.from+1] = '\\'
+				edges[i].from++
+			}
+		}
+
+		rows = append(rows, row)
+	}
+}
+
+func newGraphRow(width int) []byte {
+	return []byte(strings.Repeat(" ", 2*width))
+}
+
+func indexOfHash(hashes []hash.Hash, h hash.Hash) int {
+	for i, curr := range hashes {
+		if curr == h {
+			return i
+		}
+	}
+	return -1
+}
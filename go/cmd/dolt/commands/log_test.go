@@ -18,8 +18,11 @@ import (
 	"context"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/store/hash"
 	"github.com/dolthub/dolt/go/store/types"
 )
 
@@ -36,3 +39,52 @@ func TestLog(t *testing.T) {
 
 	cli.Println(commit)
 }
+
+func TestCommitGraph(t *testing.T) {
+	merge, left, right, base, root := hash.Of([]byte("merge")), hash.Of([]byte("left")), hash.Of([]byte("right")), hash.Of([]byte("base")), hash.Of([]byte("root"))
+
+	commits := []struct {
+		h       hash.Hash
+		parents []hash.Hash
+		lines   []string
+	}{
+		{merge, []hash.Hash{left, right}, []string{"merge", "Merge: left right", ""}},
+		{left, []hash.Hash{base}, []string{"left"}},
+		{right, []hash.Hash{base}, []string{"right"}},
+		{base, []hash.Hash{root}, []string{"base"}},
+		{root, nil, []string{"root"}},
+	}
+
+	var actual []string
+	g := &commitGraph{}
+	for _, c := range commits {
+		actual = append(actual, g.render(c.h, c.parents, c.lines)...)
+	}
+
+	assert.Equal(t, []string{
+		"*   merge",
+		"|\\  Merge: left right",
+		"| |",
+		"* | left",
+		"| * right",
+		"|/",
+		"* base",
+		"* root",
+	}, actual)
+	assert.Empty(t, g.columns)
+}
+
+func TestCommitGraphMovesColumns(t *testing.T) {
+	a, b, c, d := hash.Of([]byte("a")), hash.Of([]byte("b")), hash.Of([]byte("c")), hash.Of([]byte("d"))
+
+	g := &commitGraph{columns: []hash.Hash{a, b, c}}
+
+	// a has no parents shown, so the columns after it move left
+	assert.Equal(t, []string{"* | | a", " / /"}, g.render(a, nil, []string{"a"}))
+	assert.Equal(t, []hash.Hash{b, c}, g.columns)
+
+	// c joins b's column, two columns to the left of it
+	g.columns = []hash.Hash{b, d, c}
+	assert.Equal(t, []string{"| | * c", "| |/", "|/|"}, g.render(c, []hash.Hash{b}, []string{"c"}))
+	assert.Equal(t, []hash.Hash{b, d}, g.columns)
+}
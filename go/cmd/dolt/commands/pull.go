@@ -24,6 +24,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
 	"github.com/dolthub/dolt/go/libraries/utils/argparser"
 	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/datas"
 )
 
 var pullDocs = cli.CommandDocumentationContent{
@@ -31,9 +32,11 @@ var pullDocs = cli.CommandDocumentationContent{
 	LongDesc: `Incorporates changes from a remote repository into the current branch. In its default mode, {{.EmphasisLeft}}dolt pull{{.EmphasisRight}} is shorthand for {{.EmphasisLeft}}dolt fetch{{.EmphasisRight}} followed by {{.EmphasisLeft}}dolt merge <remote>/<branch>{{.EmphasisRight}}.
 
 More precisely, dolt pull runs {{.EmphasisLeft}}dolt fetch{{.EmphasisRight}} with the given parameters and calls {{.EmphasisLeft}}dolt merge{{.EmphasisRight}} to merge the retrieved branch {{.EmphasisLeft}}HEAD{{.EmphasisRight}} into the current branch.
+
+While fetching, progress is periodically saved to the {{.EmphasisLeft}}.dolt/temptf{{.EmphasisRight}} directory. If a pull is interrupted, running it again with {{.EmphasisLeft}}--resume{{.EmphasisRight}} continues from the last saved progress instead of starting over. The saved progress is removed once the fetch succeeds.
 `,
 	Synopsis: []string{
-		"{{.LessThan}}remote{{.GreaterThan}}",
+		"[--resume] {{.LessThan}}remote{{.GreaterThan}}",
 	},
 }
 
@@ -58,6 +61,7 @@ func (cmd PullCmd) CreateMarkdown(fs filesys.Filesys, path, commandStr string) e
 func (cmd PullCmd) createArgParser() *argparser.ArgParser {
 	ap := argparser.NewArgParser()
 	ap.SupportsFlag(squashParam, "", "Merges changes to the working set without updating the commit history")
+	ap.SupportsFlag(ResumeFlag, "", "Resume an interrupted pull from its last saved progress.")
 	return ap
 }
 
@@ -100,6 +104,10 @@ func pullFromRemote(ctx context.Context, dEnv *env.DoltEnv, apr *argparser.ArgPa
 
 	remote := dEnv.RepoState.Remotes[refSpecs[0].GetRemote()]
 
+	if apr.Contains(ResumeFlag) {
+		ctx = datas.WithPullResume(ctx)
+	}
+
 	for _, refSpec := range refSpecs {
 		remoteTrackRef := refSpec.DestRef(branch)
 
@@ -135,7 +143,7 @@ func pullRemoteBranch(ctx context.Context, squash bool, dEnv *env.DoltEnv, r env
 		return errhand.BuildDError("error: failed to get remote db").AddCause(err).Build()
 	}
 
-	srcDBCommit, verr := fetchRemoteBranch(ctx, dEnv, r, srcDB, dEnv.DoltDB, srcRef, destRef)
+	srcDBCommit, verr := fetchRemoteBranch(ctx, dEnv, r, srcDB, dEnv.DoltDB, srcRef)
 
 	if verr != nil {
 		return verr
@@ -40,6 +40,7 @@ import (
 const (
 	SetUpstreamFlag = "set-upstream"
 	ForcePushFlag   = "force"
+	ResumeFlag      = "resume"
 )
 
 type pushOpts struct {
@@ -49,6 +50,7 @@ type pushOpts struct {
 	remote      env.Remote
 	mode        ref.RefUpdateMode
 	setUpstream bool
+	resume      bool
 }
 
 var pushDocs = cli.CommandDocumentationContent{
@@ -60,10 +62,12 @@ When the command line does not specify where to push with the {{.LessThan}}remot
 When the command line does not specify what to push with {{.LessThan}}refspec{{.GreaterThan}}... then the current branch will be used.
 
 When neither the command-line does not specify what to push, the default behavior is used, which corresponds to the current branch being pushed to the corresponding upstream branch, but as a safety measure, the push is aborted if the upstream branch does not have the same name as the local one.
+
+While pushing, progress is periodically saved to the {{.EmphasisLeft}}.dolt/temptf{{.EmphasisRight}} directory. If a push is interrupted, running it again with {{.EmphasisLeft}}--resume{{.EmphasisRight}} continues from the last saved progress instead of starting over. The saved progress is removed once the push succeeds.
 `,
 
 	Synopsis: []string{
-		"[-u | --set-upstream] [{{.LessThan}}remote{{.GreaterThan}}] [{{.LessThan}}refspec{{.GreaterThan}}]",
+		"[-u | --set-upstream] [--resume] [{{.LessThan}}remote{{.GreaterThan}}] [{{.LessThan}}refspec{{.GreaterThan}}]",
 	},
 }
 
@@ -89,6 +93,7 @@ func (cmd PushCmd) createArgParser() *argparser.ArgParser {
 	ap := argparser.NewArgParser()
 	ap.SupportsFlag(SetUpstreamFlag, "u", "For every branch that is up to date or successfully pushed, add upstream (tracking) reference, used by argument-less {{.EmphasisLeft}}dolt pull{{.EmphasisRight}} and other commands.")
 	ap.SupportsFlag(ForcePushFlag, "f", "Update the remote with local history, overwriting any conflicting history in the remote.")
+	ap.SupportsFlag(ResumeFlag, "", "Resume an interrupted push from its last saved progress.")
 	return ap
 }
 
@@ -140,7 +145,7 @@ func parsePushArgs(ctx context.Context, apr *argparser.ArgParseResults, dEnv *en
 	if remoteOK && len(args) == 1 {
 		refSpecStr := args[0]
 
-		refSpecStr, err = disambiguateRefSpecStr(ctx, dEnv.DoltDB, refSpecStr)
+		refSpecStr, err = actions.DisambiguateRefSpecStr(ctx, dEnv.DoltDB, refSpecStr)
 		if err != nil {
 			verr = errhand.VerboseErrorFromError(err)
 		}
@@ -154,7 +159,7 @@ func parsePushArgs(ctx context.Context, apr *argparser.ArgParseResults, dEnv *en
 		remoteName = args[0]
 		refSpecStr := args[1]
 
-		refSpecStr, err = disambiguateRefSpecStr(ctx, dEnv.DoltDB, refSpecStr)
+		refSpecStr, err = actions.DisambiguateRefSpecStr(ctx, dEnv.DoltDB, refSpecStr)
 		if err != nil {
 			verr = errhand.VerboseErrorFromError(err)
 		}
@@ -240,41 +245,12 @@ func parsePushArgs(ctx context.Context, apr *argparser.ArgParseResults, dEnv *en
 			Force: apr.Contains(ForcePushFlag),
 		},
 		setUpstream: apr.Contains(SetUpstreamFlag),
+		resume:      apr.Contains(ResumeFlag),
 	}
 
 	return opts, nil
 }
 
-// if possible, convert refs to full spec names. prefer branches over tags.
-// eg "master" -> "refs/heads/master", "v1" -> "refs/tags/v1"
-func disambiguateRefSpecStr(ctx context.Context, ddb *doltdb.DoltDB, refSpecStr string) (string, error) {
-	brachRefs, err := ddb.GetBranches(ctx)
-
-	if err != nil {
-		return "", err
-	}
-
-	for _, br := range brachRefs {
-		if br.GetPath() == refSpecStr {
-			return br.String(), nil
-		}
-	}
-
-	tagRefs, err := ddb.GetTags(ctx)
-
-	if err != nil {
-		return "", err
-	}
-
-	for _, tr := range tagRefs {
-		if tr.GetPath() == refSpecStr {
-			return tr.String(), nil
-		}
-	}
-
-	return refSpecStr, nil
-}
-
 func doPush(ctx context.Context, dEnv *env.DoltEnv, opts *pushOpts) (verr errhand.VerboseError) {
 	destDB, err := opts.remote.GetRemoteDB(ctx, dEnv.DoltDB.ValueReadWriter().Format())
 
@@ -296,6 +272,10 @@ func doPush(ctx context.Context, dEnv *env.DoltEnv, opts *pushOpts) (verr errhan
 		return bdr.Build()
 	}
 
+	if opts.resume {
+		ctx = datas.WithPullResume(ctx)
+	}
+
 	switch opts.srcRef.GetType() {
 	case ref.BranchRefType:
 		if opts.srcRef == ref.EmptyBranchRef {
@@ -332,21 +312,13 @@ func doPush(ctx context.Context, dEnv *env.DoltEnv, opts *pushOpts) (verr errhan
 }
 
 func getTrackingRef(branchRef ref.DoltRef, remote env.Remote) (ref.DoltRef, errhand.VerboseError) {
-	for _, fsStr := range remote.FetchSpecs {
-		fs, err := ref.ParseRefSpecForRemote(remote.Name, fsStr)
+	remoteRef, err := actions.GetTrackingRef(branchRef, remote)
 
-		if err != nil {
-			return nil, errhand.BuildDError("error: invalid fetch spec '%s' for remote '%s'", fsStr, remote.Name).Build()
-		}
-
-		remoteRef := fs.DestRef(branchRef)
-
-		if remoteRef != nil {
-			return remoteRef, nil
-		}
+	if err != nil {
+		return nil, errhand.BuildDError("error: %s", err.Error()).Build()
 	}
 
-	return nil, nil
+	return remoteRef, nil
 }
 
 func deleteRemoteBranch(ctx context.Context, toDelete, remoteRef ref.DoltRef, localDB, remoteDB *doltdb.DoltDB, remote env.Remote) errhand.VerboseError {
@@ -471,6 +443,9 @@ func pullerProgFunc(pullerEventCh chan datas.PullerEvent) {
 
 		case datas.EndUpdateTableFile:
 			pos = cli.DeleteAndPrint(pos, fmt.Sprintf("Successfully uploaded %d of %d file(s).", evt.TFEventDetails.TableFilesUploaded, evt.TFEventDetails.TableFileCount))
+
+		case datas.ResumeTWEvent:
+			cli.Println(fmt.Sprintf("Resuming from saved progress at tree level %d.", evt.TWEventDetails.TreeLevel))
 		}
 	}
 }
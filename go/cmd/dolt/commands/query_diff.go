@@ -57,7 +57,9 @@ func diffQuery(ctx context.Context, dEnv *env.DoltEnv, fromRoot, toRoot *doltdb.
 		return errhand.VerboseErrorFromError(err)
 	}
 
-	p, err := buildQueryDiffPipeline(qd, doltSch, joiner)
+	// the rows of the results aren't stored, so values which are written to their own chunks are kept in memory
+	vrw := types.NewMemoryValueStore()
+	p, err := buildQueryDiffPipeline(ctx, vrw, qd, doltSch, joiner)
 
 	if err != nil {
 		return errhand.BuildDError("error building diff pipeline").AddCause(err).Build()
@@ -82,7 +84,7 @@ func doltSchWithPKFromSqlSchema(sch sql.Schema) schema.Schema {
 	return schema.MustSchemaFromCols(newCC)
 }
 
-func nextQueryDiff(qd *querydiff.QueryDiffer, joiner *rowconv.Joiner) (row.Row, pipeline.ImmutableProperties, error) {
+func nextQueryDiff(ctx context.Context, vrw types.ValueReadWriter, qd *querydiff.QueryDiffer, joiner *rowconv.Joiner) (row.Row, pipeline.ImmutableProperties, error) {
 	fromRow, toRow, err := qd.NextDiff()
 	if err != nil {
 		return nil, pipeline.ImmutableProperties{}, err
@@ -91,7 +93,7 @@ func nextQueryDiff(qd *querydiff.QueryDiffer, joiner *rowconv.Joiner) (row.Row,
 	rows := make(map[string]row.Row)
 	if fromRow != nil {
 		sch := joiner.SchemaForName(diff.From)
-		oldRow, err := dsqle.SqlRowToDoltRow(types.Format_Default, fromRow, sch)
+		oldRow, err := dsqle.SqlRowToDoltRow(ctx, vrw, fromRow, sch)
 		if err != nil {
 			return nil, pipeline.ImmutableProperties{}, err
 		}
@@ -100,7 +102,7 @@ func nextQueryDiff(qd *querydiff.QueryDiffer, joiner *rowconv.Joiner) (row.Row,
 
 	if toRow != nil {
 		sch := joiner.SchemaForName(diff.To)
-		newRow, err := dsqle.SqlRowToDoltRow(types.Format_Default, toRow, sch)
+		newRow, err := dsqle.SqlRowToDoltRow(ctx, vrw, toRow, sch)
 		if err != nil {
 			return nil, pipeline.ImmutableProperties{}, err
 		}
@@ -115,9 +117,9 @@ func nextQueryDiff(qd *querydiff.QueryDiffer, joiner *rowconv.Joiner) (row.Row,
 	return joinedRow, pipeline.ImmutableProperties{}, nil
 }
 
-func buildQueryDiffPipeline(qd *querydiff.QueryDiffer, doltSch schema.Schema, joiner *rowconv.Joiner) (*pipeline.Pipeline, error) {
+func buildQueryDiffPipeline(ctx context.Context, vrw types.ValueReadWriter, qd *querydiff.QueryDiffer, doltSch schema.Schema, joiner *rowconv.Joiner) (*pipeline.Pipeline, error) {
 
-	unionSch, ds, verr := createSplitter(doltSch, doltSch, joiner, &diffArgs{diffOutput: TabularDiffOutput})
+	unionSch, ds, verr := createSplitter(ctx, vrw, doltSch, doltSch, joiner, &diffArgs{diffOutput: TabularDiffOutput})
 	if verr != nil {
 		return nil, verr
 	}
@@ -144,7 +146,7 @@ func buildQueryDiffPipeline(qd *querydiff.QueryDiffer, doltSch schema.Schema, jo
 	sinkProcFunc := pipeline.ProcFuncForSinkFunc(sink.ProcRowWithProps)
 
 	srcProcFunc := pipeline.ProcFuncForSourceFunc(func() (row.Row, pipeline.ImmutableProperties, error) {
-		return nextQueryDiff(qd, joiner)
+		return nextQueryDiff(ctx, vrw, qd, joiner)
 	})
 
 	p := pipeline.NewAsyncPipeline(srcProcFunc, sinkProcFunc, transforms, badRowCB)
@@ -112,40 +112,10 @@ func resetHard(ctx context.Context, dEnv *env.DoltEnv, apr *argparser.ArgParseRe
 		return errhand.BuildDError("--%s does not support additional params", HardResetParam).SetPrintUsage().Build()
 	}
 
-	// need to save the state of files that aren't tracked
-	untrackedTables := make(map[string]*doltdb.Table)
-	wTblNames, err := workingRoot.GetTableNames(ctx)
+	newWkRoot, err := actions.ResetHardTables(ctx, workingRoot, stagedRoot, headRoot)
 
 	if err != nil {
-		return errhand.BuildDError("error: failed to read tables from the working set").AddCause(err).Build()
-	}
-
-	for _, tblName := range wTblNames {
-		untrackedTables[tblName], _, err = workingRoot.GetTable(ctx, tblName)
-
-		if err != nil {
-			return errhand.BuildDError("error: failed to read '%s' from the working set", tblName).AddCause(err).Build()
-		}
-	}
-
-	headTblNames, err := stagedRoot.GetTableNames(ctx)
-
-	if err != nil {
-		return errhand.BuildDError("error: failed to read tables from head").AddCause(err).Build()
-	}
-
-	for _, tblName := range headTblNames {
-		delete(untrackedTables, tblName)
-	}
-
-	newWkRoot := headRoot
-	for tblName, tbl := range untrackedTables {
-		if tblName != doltdb.DocTableName {
-			newWkRoot, err = newWkRoot.PutTable(ctx, tblName, tbl)
-		}
-		if err != nil {
-			return errhand.BuildDError("error: failed to write table back to database").Build()
-		}
+		return errhand.BuildDError("error: failed to reset the working tables").AddCause(err).Build()
 	}
 
 	// TODO: update working and staged in one repo_state write.
@@ -140,8 +140,19 @@ func exportSchemas(ctx context.Context, apr *argparser.ArgParseResults, root *do
 }
 
 func exportTblSchema(ctx context.Context, tblName string, root *doltdb.RootValue, wr io.Writer) errhand.VerboseError {
+	tbl, ok, err := root.GetTable(ctx, tblName)
+	if err != nil {
+		return errhand.BuildDError("unable to get table %s", tblName).AddCause(err).Build()
+	} else if !ok {
+		return errhand.BuildDError("%s not found", tblName).Build()
+	}
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return errhand.BuildDError("unable to get schema for table %s", tblName).AddCause(err).Build()
+	}
+
 	sqlCtx, engine, _ := dsqle.PrepareCreateTableStmt(ctx, dsqle.NewUserSpaceDatabase(root))
-	stmt, err := dsqle.GetCreateTableStmt(sqlCtx, engine, tblName)
+	stmt, err := dsqle.GetExportTableStmts(sqlCtx, engine, tblName, sch)
 	if err != nil {
 		return errhand.VerboseErrorFromError(err)
 	}
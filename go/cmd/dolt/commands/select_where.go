@@ -15,6 +15,7 @@
 package commands
 
 import (
+	"context"
 	"errors"
 	"strings"
 
@@ -69,11 +70,12 @@ func ParseWhere(sch schema.Schema, whereClause string) (FilterFn, error) {
 		}
 
 		var val types.Value
-		if typeinfo.IsStringType(cols[0].TypeInfo) {
+		// values stored as Blobs are compared with the Blob of the value given
+		if typeinfo.IsStringType(cols[0].TypeInfo) && cols[0].TypeInfo.NomsKind() == types.StringKind {
 			val = types.String(valStr)
 		} else {
 			var err error
-			val, err = cols[0].TypeInfo.ParseValue(&valStr)
+			val, err = cols[0].TypeInfo.ParseValue(context.Background(), types.NewMemoryValueStore(), &valStr)
 			if err != nil {
 				return nil, errors.New("unable to convert '" + valStr + "' to " + col.TypeInfo.String())
 			}
@@ -27,9 +27,7 @@ import (
 	sqle "github.com/dolthub/go-mysql-server"
 	"github.com/dolthub/go-mysql-server/auth"
 	"github.com/dolthub/go-mysql-server/sql"
-	"github.com/dolthub/go-mysql-server/sql/analyzer"
 	"github.com/dolthub/go-mysql-server/sql/expression"
-	"github.com/dolthub/go-mysql-server/sql/information_schema"
 	"github.com/dolthub/go-mysql-server/sql/parse"
 	"github.com/dolthub/go-mysql-server/sql/plan"
 	"github.com/dolthub/vitess/go/vt/sqlparser"
@@ -70,6 +68,10 @@ var sqlDocs = cli.CommandDocumentationContent{
 
 By default, {{.EmphasisLeft}}-q{{.EmphasisRight}} executes a single statement. To execute multiple SQL statements separated by semicolons, use {{.EmphasisLeft}}-b{{.EmphasisRight}} to enable batch mode. Queries can be saved with {{.EmphasisLeft}}-s{{.EmphasisRight}}. Alternatively {{.EmphasisLeft}}-x{{.EmphasisRight}} can be used to execute a saved query by name. Pipe SQL statements to dolt sql (no {{.EmphasisLeft}}-q{{.EmphasisRight}}) to execute a SQL import or update script. 
 
+{{.EmphasisLeft}}EXPLAIN ANALYZE <select statement>{{.EmphasisRight}} runs the query and prints its plan, each node being followed by the time spent in it, the number of rows it returned and the number of times it ran. The nodes reading Dolt tables also show whether the table was fully scanned or read through an index, the key ranges which were read, and the number of chunks read from the database while the node was running, database-wide, including those read by the queries running concurrently.
+
+{{.EmphasisLeft}}ANALYZE TABLE <table>[, <table>...]{{.EmphasisRight}} collects the row counts of the tables, and the number of nulls, the number of distinct values and a histogram of the values of each of their columns, and stores them in the repository. Statistics are not versioned: they are shared by every branch, and are never committed. They are shown by the {{.EmphasisLeft}}dolt_statistics{{.EmphasisRight}}, {{.EmphasisLeft}}information_schema.column_statistics{{.EmphasisRight}} and {{.EmphasisLeft}}information_schema.tables{{.EmphasisRight}} tables, and are used to choose between the indexes of an analyzed table, and between an index and a scan of the table, when a query is run. Statistics are not updated as the table changes, so tables should be analyzed again after large changes.
+
 By default this command uses the dolt data repository in the current working directory as the one and only database. Running with {{.EmphasisLeft}}--multi-db-dir <directory>{{.EmphasisRight}} uses each of the subdirectories of the supplied directory (each subdirectory must be a valid dolt data repository) as databases. Subdirectories starting with '.' are ignored. Known limitations: 
 	- No support for creating indexes 
 	- No support for foreign keys 
@@ -379,11 +381,11 @@ func execBatch(sqlCtx *sql.Context, readOnly bool, mrEnv env.MultiRepoEnv, roots
 type createDBFunc func(name string, dEnv *env.DoltEnv) dsqle.Database
 
 func newDatabase(name string, dEnv *env.DoltEnv) dsqle.Database {
-	return dsqle.NewDatabase(name, dEnv.DoltDB, dEnv.RepoState, dEnv.RepoStateWriter())
+	return dsqle.NewDatabaseFromEnv(name, dEnv)
 }
 
 func newBatchedDatabase(name string, dEnv *env.DoltEnv) dsqle.Database {
-	return dsqle.NewBatchedDatabase(name, dEnv.DoltDB, dEnv.RepoState, dEnv.RepoStateWriter())
+	return dsqle.NewBatchedDatabaseFromEnv(name, dEnv)
 }
 
 func execQuery(sqlCtx *sql.Context, readOnly bool, mrEnv env.MultiRepoEnv, roots map[string]*doltdb.RootValue, query string, format resultFormat) (newRoot map[string]*doltdb.RootValue, verr errhand.VerboseError) {
@@ -814,6 +816,42 @@ func prepend(s string, ss []string) []string {
 // Processes a single query. The Root of the sqlEngine will be updated if necessary.
 // Returns the schema and the row iterator for the results, which may be nil, and an error if one occurs.
 func processQuery(ctx *sql.Context, query string, se *sqlEngine) (sql.Schema, sql.RowIter, error) {
+	query = dsqle.RewriteMatchAgainst(dsqle.RewriteJSONOperators(query))
+	spatialDDL, err := dsqle.ParseSpatialDDL(query)
+	if err != nil {
+		return nil, nil, err
+	} else if spatialDDL != nil {
+		return nil, nil, se.spatialDDL(ctx, spatialDDL)
+	}
+
+	indexDDL, err := dsqle.ParseIndexDDL(query)
+	if err != nil {
+		return nil, nil, err
+	} else if indexDDL != nil {
+		return nil, nil, se.indexDDL(ctx, indexDDL)
+	}
+
+	checkDDL, err := dsqle.ParseCheckDDL(query)
+	if err != nil {
+		return nil, nil, err
+	} else if checkDDL != nil {
+		return nil, nil, se.checkDDL(ctx, checkDDL)
+	}
+
+	explain, err := dsqle.ParseExplainAnalyze(query)
+	if err != nil {
+		return nil, nil, err
+	} else if explain != nil {
+		return explain.Exec(ctx, se.engine)
+	}
+
+	analyze, err := dsqle.ParseAnalyzeTable(query)
+	if err != nil {
+		return nil, nil, err
+	} else if analyze != nil {
+		return analyze.Exec(ctx, se.engine)
+	}
+
 	sqlStatement, err := sqlparser.Parse(query)
 	if err == sqlparser.ErrEmpty {
 		// silently skip empty statements
@@ -910,6 +948,28 @@ func flushBatchedEdits(ctx *sql.Context, se *sqlEngine) error {
 
 // Processes a single query in batch mode. The Root of the sqlEngine may or may not be changed.
 func processBatchQuery(ctx *sql.Context, query string, se *sqlEngine) error {
+	query = dsqle.RewriteMatchAgainst(dsqle.RewriteJSONOperators(query))
+	spatialDDL, err := dsqle.ParseSpatialDDL(query)
+	if err != nil {
+		return err
+	} else if spatialDDL != nil {
+		return processNonInsertBatchQuery(ctx, se, query, nil)
+	}
+
+	indexDDL, err := dsqle.ParseIndexDDL(query)
+	if err != nil {
+		return err
+	} else if indexDDL != nil {
+		return processNonInsertBatchQuery(ctx, se, query, nil)
+	}
+
+	checkDDL, err := dsqle.ParseCheckDDL(query)
+	if err != nil {
+		return err
+	} else if checkDDL != nil {
+		return processNonInsertBatchQuery(ctx, se, query, nil)
+	}
+
 	sqlStatement, err := sqlparser.Parse(query)
 	if err == sqlparser.ErrEmpty {
 		// silently skip empty statements
@@ -1134,8 +1194,9 @@ func newSqlEngine(sqlCtx *sql.Context, readOnly bool, mrEnv env.MultiRepoEnv, ro
 	}
 
 	parallelism := runtime.GOMAXPROCS(0)
-	engine := sqle.New(c, analyzer.NewBuilder(c).WithParallelism(parallelism).Build(), &sqle.Config{Auth: au})
-	engine.AddDatabase(information_schema.NewInformationSchemaDatabase(engine.Catalog))
+	engine := sqle.New(c, dsqle.NewAnalyzerBuilder(c).WithParallelism(parallelism).Build(), &sqle.Config{Auth: au})
+	dfunctions.RegisterOverrides(c.FunctionRegistry)
+	engine.AddDatabase(dsqle.NewInformationSchemaDatabase(engine.Catalog))
 
 	dsess := dsqle.DSessFromSess(sqlCtx.Session)
 
@@ -1304,8 +1365,10 @@ func PrettyPrintResults(ctx context.Context, resultFormat resultFormat, sqlSch s
 	var rowFn func(r sql.Row) (row.Row, error)
 	switch resultFormat {
 	case formatJson:
+		// the rows of the results aren't stored, so values which are written to their own chunks are kept in memory
+		vrw := types.NewMemoryValueStore()
 		rowFn = func(r sql.Row) (r2 row.Row, err error) {
-			return dsqle.SqlRowToDoltRow(nbf, r, doltSch)
+			return dsqle.SqlRowToDoltRow(ctx, vrw, r, doltSch)
 		}
 	default:
 		rowFn = func(r sql.Row) (row.Row, error) {
@@ -1475,6 +1538,49 @@ func (se *sqlEngine) checkThenDeleteAllRows(ctx *sql.Context, s *sqlparser.Delet
 	return false
 }
 
+// Executes a CREATE TABLE or ALTER TABLE statement that defines or drops CHECK constraints.
+func (se *sqlEngine) checkDDL(ctx *sql.Context, checkDDL *dsqle.CheckDDL) error {
+	return checkDDL.Exec(ctx, se.engine.Catalog, func(query string) error {
+		return se.execDDL(ctx, query)
+	})
+}
+
+// Executes a statement defining geometry columns or SPATIAL indexes, along with any other indexes or CHECK constraints
+// it defines.
+func (se *sqlEngine) spatialDDL(ctx *sql.Context, spatialDDL *dsqle.SpatialDDL) error {
+	return spatialDDL.Exec(ctx, se.engine.Catalog, func(query string) error {
+		return dsqle.ExecIndexDDL(ctx, se.engine.Catalog, query, func(query string) error {
+			return se.execDDL(ctx, query)
+		})
+	})
+}
+
+// Executes a statement defining expression or prefix indexes, along with any CHECK constraints it defines.
+func (se *sqlEngine) indexDDL(ctx *sql.Context, indexDDL *dsqle.IndexDDL) error {
+	return indexDDL.Exec(ctx, se.engine.Catalog, func(query string) error {
+		return dsqle.ExecCheckDDL(ctx, se.engine.Catalog, query, func(query string) error {
+			return se.execDDL(ctx, query)
+		})
+	})
+}
+
+// Executes the remainder of a statement whose CHECK constraint, index or geometry column clauses were removed or
+// replaced, which must be DDL.
+func (se *sqlEngine) execDDL(ctx *sql.Context, query string) error {
+	sqlStatement, err := sqlparser.ParseStrictDDL(query)
+	if err != nil {
+		return fmt.Errorf("Error parsing DDL: %v.", err.Error())
+	}
+
+	ddl, ok := sqlStatement.(*sqlparser.DDL)
+	if !ok {
+		return fmt.Errorf("Unsupported SQL statement: '%v'.", query)
+	}
+
+	_, _, err = se.ddl(ctx, ddl, query)
+	return err
+}
+
 // Executes a SQL DDL statement (create, update, etc.). Updates the new root value in
 // the sqlEngine if necessary.
 func (se *sqlEngine) ddl(ctx *sql.Context, ddl *sqlparser.DDL, query string) (sql.Schema, sql.RowIter, error) {
@@ -0,0 +1,85 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"io"
+
+	sqle "github.com/dolthub/go-mysql-server"
+	"github.com/dolthub/go-mysql-server/server"
+	"github.com/dolthub/vitess/go/mysql"
+	"github.com/dolthub/vitess/go/sqltypes"
+	querypb "github.com/dolthub/vitess/go/vt/proto/query"
+
+	dsqle "github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+)
+
+// analyzeTableHandler is a mysql.Handler which executes ANALYZE TABLE statements, which the engine can't execute, and
+// passes all other queries to the wrapped Handler.
+type analyzeTableHandler struct {
+	mysql.Handler
+	sm *server.SessionManager
+	e  *sqle.Engine
+}
+
+func (h analyzeTableHandler) ComQuery(c *mysql.Conn, query string, callback func(*sqltypes.Result) error) error {
+	analyze, err := dsqle.ParseAnalyzeTable(query)
+	if err != nil {
+		return err
+	} else if analyze == nil {
+		return h.Handler.ComQuery(c, query, callback)
+	}
+
+	ctx, err := h.sm.NewContextWithQuery(c, query)
+	if err != nil {
+		return err
+	}
+
+	sch, iter, err := analyze.Exec(ctx, h.e)
+	if err != nil {
+		return err
+	}
+	defer iter.Close()
+
+	result := &sqltypes.Result{}
+	for _, col := range sch {
+		result.Fields = append(result.Fields, &querypb.Field{Name: col.Name, Type: sqltypes.VarChar})
+	}
+
+	for {
+		row, err := iter.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		var vals []sqltypes.Value
+		for _, v := range row {
+			vals = append(vals, sqltypes.NewVarChar(v.(string)))
+		}
+		result.Rows = append(result.Rows, vals)
+	}
+	result.RowsAffected = uint64(len(result.Rows))
+
+	if isSessionAutocommit(ctx) {
+		err = ctx.Session.CommitTransaction(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	return callback(result)
+}
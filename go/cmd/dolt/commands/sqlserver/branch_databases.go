@@ -0,0 +1,176 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"strings"
+	"sync"
+
+	sqle "github.com/dolthub/go-mysql-server"
+	"github.com/dolthub/go-mysql-server/server"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/vitess/go/mysql"
+	"github.com/dolthub/vitess/go/sqltypes"
+	querypb "github.com/dolthub/vitess/go/vt/proto/query"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	dsqle "github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+)
+
+// branchDatabaseHandler is a mysql.Handler which resolves the branch databases, named db/branch, used by a connection
+// before passing its commands to the wrapped Handler.  A branch database is added to the catalog and to the session the
+// first time the session uses it, if the branch exists at that time, so branches created after the session started
+// can be used, and the databases of deleted branches can't be used by new sessions.
+type branchDatabaseHandler struct {
+	mysql.Handler
+	sm *server.SessionManager
+	e  *sqle.Engine
+	// mu serializes adding branch databases to the catalog
+	mu *sync.Mutex
+}
+
+func (h branchDatabaseHandler) ComInitDB(c *mysql.Conn, schemaName string) error {
+	err := h.resolve(c, "", []string{schemaName})
+	if err != nil {
+		return err
+	}
+
+	return h.Handler.ComInitDB(c, schemaName)
+}
+
+func (h branchDatabaseHandler) ComQuery(c *mysql.Conn, query string, callback func(*sqltypes.Result) error) error {
+	err := h.resolve(c, query, branchDatabaseNames(query, h.databases()))
+	if err != nil {
+		return err
+	}
+
+	return h.Handler.ComQuery(c, query, callback)
+}
+
+func (h branchDatabaseHandler) ComPrepare(c *mysql.Conn, query string) ([]*querypb.Field, error) {
+	err := h.resolve(c, query, branchDatabaseNames(query, h.databases()))
+	if err != nil {
+		return nil, err
+	}
+
+	return h.Handler.ComPrepare(c, query)
+}
+
+// databases returns the dolt databases of the catalog, excluding branch databases
+func (h branchDatabaseHandler) databases() []dsqle.Database {
+	var dbs []dsqle.Database
+	for _, db := range dbsAsDSQLDBs(h.e.Catalog.AllDatabases()) {
+		if !db.IsBranchDatabase() {
+			dbs = append(dbs, db)
+		}
+	}
+
+	return dbs
+}
+
+// resolve adds the branch databases |names| to the catalog and to the session of |c|.  It returns ErrDatabaseNotFound if
+// the session doesn't use a database of the catalog whose branch has been deleted.  Names which aren't the names of
+// existing branch databases are ignored, and left for the engine to report.
+func (h branchDatabaseHandler) resolve(c *mysql.Conn, query string, names []string) error {
+	var branchDBs []dsqle.Database
+	var branches []ref.DoltRef
+	for _, name := range names {
+		idx := strings.Index(name, dsqle.BranchDatabaseSeparator)
+		if idx < 0 {
+			continue
+		}
+
+		for _, db := range h.databases() {
+			if strings.EqualFold(db.Name(), name[:idx]) {
+				branch := ref.NewBranchRef(name[idx+1:])
+				branchDBs = append(branchDBs, dsqle.NewBranchDatabase(db, branch))
+				branches = append(branches, branch)
+				break
+			}
+		}
+	}
+
+	if len(branchDBs) == 0 {
+		return nil
+	}
+
+	ctx, err := h.sm.NewContextWithQuery(c, query)
+	if err != nil {
+		return err
+	}
+
+	dsess := dsqle.DSessFromSess(ctx.Session)
+	for i, db := range branchDBs {
+		if _, ok := dsess.GetDoltDB(db.Name()); ok {
+			continue
+		}
+
+		hasBranch, err := db.GetDoltDB().HasRef(ctx, branches[i])
+		if err != nil {
+			return err
+		} else if !hasBranch && h.e.Catalog.HasDB(db.Name()) {
+			// the catalog keeps the databases of deleted branches, which only the sessions using them can use
+			return sql.ErrDatabaseNotFound.New(db.Name())
+		} else if !hasBranch {
+			// left for the engine to report
+			continue
+		}
+
+		h.mu.Lock()
+		if !h.e.Catalog.HasDB(db.Name()) {
+			h.e.Catalog.AddDatabase(db)
+		}
+		h.mu.Unlock()
+
+		err = dsess.AddDB(ctx, db)
+		if err != nil {
+			return err
+		}
+
+		err = db.LoadRootFromRepoState(ctx)
+		if err != nil {
+			return err
+		}
+
+		root, err := db.GetRoot(ctx)
+		if err != nil {
+			return err
+		}
+
+		err = dsqle.RegisterSchemaFragments(ctx, db, root)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// branchDatabaseNames returns the identifiers of |query| which name a branch database of one of |dbs|.  As the names
+// of branch databases contain BranchDatabaseSeparator they must be quoted with backticks to be used in a query.
+func branchDatabaseNames(query string, dbs []dsqle.Database) []string {
+	var names []string
+	for _, id := range dsqle.Identifiers(query) {
+		for _, db := range dbs {
+			prefix := db.Name() + dsqle.BranchDatabaseSeparator
+			if len(id) > len(prefix) && strings.EqualFold(id[:len(prefix)], prefix) {
+				names = append(names, id)
+				break
+			}
+		}
+	}
+
+	return names
+}
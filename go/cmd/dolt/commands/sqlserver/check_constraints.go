@@ -0,0 +1,126 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	sqle "github.com/dolthub/go-mysql-server"
+	"github.com/dolthub/go-mysql-server/server"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/vitess/go/mysql"
+	"github.com/dolthub/vitess/go/sqltypes"
+
+	dsqle "github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+)
+
+// checkConstraintHandler is a mysql.Handler which executes the statements defining CHECK constraints, expression and
+// prefix indexes, geometry columns or SPATIAL indexes, which the engine can't parse, and passes all other queries to the
+// wrapped Handler.
+type checkConstraintHandler struct {
+	mysql.Handler
+	sm *server.SessionManager
+	e  *sqle.Engine
+}
+
+func (h checkConstraintHandler) ComQuery(c *mysql.Conn, query string, callback func(*sqltypes.Result) error) error {
+	spatialDDL, err := dsqle.ParseSpatialDDL(query)
+	if err != nil {
+		return err
+	}
+
+	var indexDDL *dsqle.IndexDDL
+	var checkDDL *dsqle.CheckDDL
+	if spatialDDL == nil {
+		indexDDL, err = dsqle.ParseIndexDDL(query)
+		if err != nil {
+			return err
+		}
+	}
+	if spatialDDL == nil && indexDDL == nil {
+		checkDDL, err = dsqle.ParseCheckDDL(query)
+		if err != nil {
+			return err
+		} else if checkDDL == nil {
+			return h.Handler.ComQuery(c, query, callback)
+		}
+	}
+
+	ctx, err := h.sm.NewContextWithQuery(c, query)
+	if err != nil {
+		return err
+	}
+
+	// the engine would commit the part of the statement it executes, which must be undone if the rest can't be applied,
+	// so the statement is only committed once all of it is applied
+	autocommit := isSessionAutocommit(ctx)
+	if autocommit {
+		typ, val := ctx.Get(sql.AutoCommitSessionVar)
+		err = ctx.Set(ctx, sql.AutoCommitSessionVar, sql.Boolean, false)
+		if err != nil {
+			return err
+		}
+		defer ctx.Set(ctx, sql.AutoCommitSessionVar, typ, val)
+	}
+
+	result := &sqltypes.Result{}
+	execQuery := func(query string) error {
+		return h.Handler.ComQuery(c, query, func(r *sqltypes.Result) error {
+			result = r
+			return nil
+		})
+	}
+
+	switch {
+	case spatialDDL != nil:
+		err = spatialDDL.Exec(ctx, h.e.Catalog, func(query string) error {
+			return dsqle.ExecIndexDDL(ctx, h.e.Catalog, query, execQuery)
+		})
+	case indexDDL != nil:
+		err = indexDDL.Exec(ctx, h.e.Catalog, func(query string) error {
+			return dsqle.ExecCheckDDL(ctx, h.e.Catalog, query, execQuery)
+		})
+	default:
+		err = checkDDL.Exec(ctx, h.e.Catalog, execQuery)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if autocommit {
+		err = ctx.Session.CommitTransaction(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	return callback(result)
+}
+
+func isSessionAutocommit(ctx *sql.Context) bool {
+	typ, val := ctx.Get(sql.AutoCommitSessionVar)
+	if val == nil {
+		return false
+	}
+
+	switch typ {
+	case sql.Int64:
+		return val.(int64) == 1
+	case sql.Boolean:
+		autocommit, _ := sql.ConvertToBool(val)
+		return autocommit
+	default:
+		return false
+	}
+}
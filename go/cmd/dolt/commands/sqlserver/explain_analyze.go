@@ -0,0 +1,70 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"io"
+
+	sqle "github.com/dolthub/go-mysql-server"
+	"github.com/dolthub/go-mysql-server/server"
+	"github.com/dolthub/vitess/go/mysql"
+	"github.com/dolthub/vitess/go/sqltypes"
+	querypb "github.com/dolthub/vitess/go/vt/proto/query"
+
+	dsqle "github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+)
+
+// explainAnalyzeHandler is a mysql.Handler which executes EXPLAIN ANALYZE statements, which the engine runs as plain
+// EXPLAIN statements, and passes all other queries to the wrapped Handler.
+type explainAnalyzeHandler struct {
+	mysql.Handler
+	sm *server.SessionManager
+	e  *sqle.Engine
+}
+
+func (h explainAnalyzeHandler) ComQuery(c *mysql.Conn, query string, callback func(*sqltypes.Result) error) error {
+	explain, err := dsqle.ParseExplainAnalyze(query)
+	if err != nil {
+		return err
+	} else if explain == nil {
+		return h.Handler.ComQuery(c, query, callback)
+	}
+
+	ctx, err := h.sm.NewContextWithQuery(c, query)
+	if err != nil {
+		return err
+	}
+
+	sch, iter, err := explain.Exec(ctx, h.e)
+	if err != nil {
+		return err
+	}
+	defer iter.Close()
+
+	result := &sqltypes.Result{Fields: []*querypb.Field{{Name: sch[0].Name, Type: sqltypes.VarChar}}}
+	for {
+		row, err := iter.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		result.Rows = append(result.Rows, []sqltypes.Value{sqltypes.NewVarChar(row[0].(string))})
+	}
+	result.RowsAffected = uint64(len(result.Rows))
+
+	return callback(result)
+}
@@ -0,0 +1,107 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"net"
+	"sync"
+)
+
+// connLimitListener is a net.Listener which doesn't accept connections while the number of open connections it
+// accepted is at its limit.  Unlike the limit of the vitess listener, the limit can be changed while the listener is
+// running, and it only counts the connections of this listener.
+type connLimitListener struct {
+	net.Listener
+	// onAccept is called with every accepted connection
+	onAccept func(conn *net.Conn)
+
+	cond     *sync.Cond
+	maxConns uint64
+	open     uint64
+	closed   bool
+}
+
+// newConnLimitListener returns a listener accepting connections from |l| while less than |maxConns| of them are
+// open.  If |maxConns| is 0 the number of connections isn't limited.
+func newConnLimitListener(l net.Listener, maxConns uint64, onAccept func(conn *net.Conn)) *connLimitListener {
+	return &connLimitListener{
+		Listener: l,
+		onAccept: onAccept,
+		cond:     sync.NewCond(&sync.Mutex{}),
+		maxConns: maxConns,
+	}
+}
+
+// Accept waits until the number of open connections is below the limit, and then accepts the next connection.
+func (l *connLimitListener) Accept() (net.Conn, error) {
+	l.cond.L.Lock()
+	for !l.closed && l.maxConns > 0 && l.open >= l.maxConns {
+		l.cond.Wait()
+	}
+	l.cond.L.Unlock()
+
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	l.cond.L.Lock()
+	l.open++
+	l.cond.L.Unlock()
+
+	conn = &limitedConn{Conn: conn, once: &sync.Once{}, l: l}
+	if l.onAccept != nil {
+		l.onAccept(&conn)
+	}
+
+	return conn, nil
+}
+
+// Close closes the listener, unblocking any waiting Accept call.
+func (l *connLimitListener) Close() error {
+	l.cond.L.Lock()
+	l.closed = true
+	l.cond.Broadcast()
+	l.cond.L.Unlock()
+
+	return l.Listener.Close()
+}
+
+// setMaxConns changes the limit of open connections.  Open connections above a lowered limit are not closed.
+func (l *connLimitListener) setMaxConns(maxConns uint64) {
+	l.cond.L.Lock()
+	l.maxConns = maxConns
+	l.cond.Broadcast()
+	l.cond.L.Unlock()
+}
+
+func (l *connLimitListener) connClosed() {
+	l.cond.L.Lock()
+	l.open--
+	l.cond.Broadcast()
+	l.cond.L.Unlock()
+}
+
+// limitedConn is a connection accepted by a connLimitListener, which stops counting it once it's closed.
+type limitedConn struct {
+	net.Conn
+	once *sync.Once
+	l    *connLimitListener
+}
+
+func (c *limitedConn) Close() error {
+	c.once.Do(c.l.connClosed)
+	return c.Conn.Close()
+}
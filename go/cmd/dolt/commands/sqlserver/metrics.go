@@ -0,0 +1,294 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dolthub/vitess/go/mysql"
+	"github.com/dolthub/vitess/go/sqltypes"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sirupsen/logrus"
+
+	dsqle "github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/metrics"
+	"github.com/dolthub/dolt/go/store/nbs"
+)
+
+const metricsNamespace = "dolt"
+
+// exportedHistogramBuckets is the number of buckets of an nbs histogram which are exported.  Bucket i holds values
+// less than 2^(i+1), so the largest exported bucket holds latencies up to ~18 minutes and sizes up to 1TB.
+const exportedHistogramBuckets = 40
+
+// serverMetrics are the metrics of a running sql-server, which are served in the Prometheus format
+type serverMetrics struct {
+	registry *prometheus.Registry
+
+	queries       prometheus.Counter
+	queryErrors   prometheus.Counter
+	queryDuration prometheus.Histogram
+	sessions      prometheus.Gauge
+	sessionsTotal prometheus.Counter
+}
+
+// newServerMetrics returns the serverMetrics for a server serving the databases returned by |dbs|, which is called each
+// time the metrics are collected.  |labels| are added to every metric.
+func newServerMetrics(labels map[string]string, dbs func() []dsqle.Database) (*serverMetrics, error) {
+	sm := &serverMetrics{
+		registry: prometheus.NewRegistry(),
+		queries: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "sql",
+			Name:      "queries_total",
+			Help:      "The number of queries executed by the server.",
+		}),
+		queryErrors: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "sql",
+			Name:      "query_errors_total",
+			Help:      "The number of queries which returned an error.",
+		}),
+		queryDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "sql",
+			Name:      "query_duration_seconds",
+			Help:      "The time taken to execute queries and send their results to the client.",
+			Buckets:   prometheus.ExponentialBuckets(0.001, 4, 10),
+		}),
+		sessions: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "sql",
+			Name:      "sessions",
+			Help:      "The number of open client connections.",
+		}),
+		sessionsTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "sql",
+			Name:      "sessions_total",
+			Help:      "The number of client connections which have been opened.",
+		}),
+	}
+
+	reg := prometheus.WrapRegistererWith(labels, sm.registry)
+	collectors := []prometheus.Collector{
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+		newChunkStoreCollector(dbs),
+		sm.queries,
+		sm.queryErrors,
+		sm.queryDuration,
+		sm.sessions,
+		sm.sessionsTotal,
+	}
+
+	for _, c := range collectors {
+		err := reg.Register(c)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return sm, nil
+}
+
+// listen starts serving the metrics at /metrics on |hostPort|.  The returned server must be closed when the
+// sql-server stops.
+func (sm *serverMetrics) listen(hostPort string) (*http.Server, error) {
+	l, err := net.Listen("tcp", hostPort)
+
+	if err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(sm.registry, promhttp.HandlerOpts{}))
+	srv := &http.Server{Handler: mux}
+
+	go func() {
+		err := srv.Serve(l)
+
+		if err != nil && err != http.ErrServerClosed {
+			logrus.Errorf("metrics listener stopped: %v", err)
+		}
+	}()
+
+	return srv, nil
+}
+
+// metricsHandler is a mysql.Handler which records the metrics of the connections and queries handled by the wrapped
+// Handler.
+type metricsHandler struct {
+	mysql.Handler
+	sm *serverMetrics
+}
+
+func (h metricsHandler) NewConnection(c *mysql.Conn) {
+	h.sm.sessions.Inc()
+	h.sm.sessionsTotal.Inc()
+	h.Handler.NewConnection(c)
+}
+
+func (h metricsHandler) ConnectionClosed(c *mysql.Conn) {
+	h.sm.sessions.Dec()
+	h.Handler.ConnectionClosed(c)
+}
+
+func (h metricsHandler) ComQuery(c *mysql.Conn, query string, callback func(*sqltypes.Result) error) error {
+	start := time.Now()
+	err := h.Handler.ComQuery(c, query, callback)
+
+	h.sm.queries.Inc()
+	h.sm.queryDuration.Observe(time.Since(start).Seconds())
+
+	if err != nil {
+		h.sm.queryErrors.Inc()
+	}
+
+	return err
+}
+
+type nbsHistogram struct {
+	name    string
+	help    string
+	seconds bool
+	get     func(nbs.Stats) metrics.Histogram
+}
+
+// nbsHistograms are the histograms of nbs.Stats which are exported.  Latencies are converted to seconds.
+var nbsHistograms = []nbsHistogram{
+	{"open_latency_seconds", "The time taken to open the chunk store.", true, func(s nbs.Stats) metrics.Histogram { return s.OpenLatency }},
+	{"commit_latency_seconds", "The time taken to commit a new root to the chunk store.", true, func(s nbs.Stats) metrics.Histogram { return s.CommitLatency }},
+	{"index_read_latency_seconds", "The time taken to read table file indexes.", true, func(s nbs.Stats) metrics.Histogram { return s.IndexReadLatency }},
+	{"index_bytes_per_read", "The number of bytes read per table file index read.", false, func(s nbs.Stats) metrics.Histogram { return s.IndexBytesPerRead }},
+	{"get_latency_seconds", "The time taken to get chunks.", true, func(s nbs.Stats) metrics.Histogram { return s.GetLatency }},
+	{"chunks_per_get", "The number of chunks requested per get.", false, func(s nbs.Stats) metrics.Histogram { return s.ChunksPerGet }},
+	{"file_read_latency_seconds", "The time taken to read from table files on disk.", true, func(s nbs.Stats) metrics.Histogram { return s.FileReadLatency }},
+	{"file_bytes_per_read", "The number of bytes read per table file read.", false, func(s nbs.Stats) metrics.Histogram { return s.FileBytesPerRead }},
+	{"s3_read_latency_seconds", "The time taken to read from table files in S3.", true, func(s nbs.Stats) metrics.Histogram { return s.S3ReadLatency }},
+	{"s3_bytes_per_read", "The number of bytes read per S3 read.", false, func(s nbs.Stats) metrics.Histogram { return s.S3BytesPerRead }},
+	{"mem_read_latency_seconds", "The time taken to read from in memory tables.", true, func(s nbs.Stats) metrics.Histogram { return s.MemReadLatency }},
+	{"mem_bytes_per_read", "The number of bytes read per in memory table read.", false, func(s nbs.Stats) metrics.Histogram { return s.MemBytesPerRead }},
+	{"dynamo_read_latency_seconds", "The time taken to read from table files in DynamoDB.", true, func(s nbs.Stats) metrics.Histogram { return s.DynamoReadLatency }},
+	{"dynamo_bytes_per_read", "The number of bytes read per DynamoDB read.", false, func(s nbs.Stats) metrics.Histogram { return s.DynamoBytesPerRead }},
+	{"has_latency_seconds", "The time taken to check for the existence of chunks.", true, func(s nbs.Stats) metrics.Histogram { return s.HasLatency }},
+	{"addresses_per_has", "The number of chunks checked per has.", false, func(s nbs.Stats) metrics.Histogram { return s.AddressesPerHas }},
+	{"put_latency_seconds", "The time taken to put chunks.", true, func(s nbs.Stats) metrics.Histogram { return s.PutLatency }},
+	{"persist_latency_seconds", "The time taken to persist memory tables as table files.", true, func(s nbs.Stats) metrics.Histogram { return s.PersistLatency }},
+	{"bytes_per_persist", "The number of bytes written per persist.", false, func(s nbs.Stats) metrics.Histogram { return s.BytesPerPersist }},
+	{"chunks_per_persist", "The number of chunks written per persist.", false, func(s nbs.Stats) metrics.Histogram { return s.ChunksPerPersist }},
+	{"compressed_chunk_bytes_per_persist", "The number of compressed chunk bytes written per persist.", false, func(s nbs.Stats) metrics.Histogram { return s.CompressedChunkBytesPerPersist }},
+	{"uncompressed_chunk_bytes_per_persist", "The number of uncompressed chunk bytes written per persist.", false, func(s nbs.Stats) metrics.Histogram { return s.UncompressedChunkBytesPerPersist }},
+	{"conjoin_latency_seconds", "The time taken to conjoin table files.", true, func(s nbs.Stats) metrics.Histogram { return s.ConjoinLatency }},
+	{"bytes_per_conjoin", "The number of bytes written per conjoin.", false, func(s nbs.Stats) metrics.Histogram { return s.BytesPerConjoin }},
+	{"chunks_per_conjoin", "The number of chunks written per conjoin.", false, func(s nbs.Stats) metrics.Histogram { return s.ChunksPerConjoin }},
+	{"tables_per_conjoin", "The number of table files conjoined per conjoin.", false, func(s nbs.Stats) metrics.Histogram { return s.TablesPerConjoin }},
+	{"read_manifest_latency_seconds", "The time taken to read the manifest.", true, func(s nbs.Stats) metrics.Histogram { return s.ReadManifestLatency }},
+	{"write_manifest_latency_seconds", "The time taken to write the manifest.", true, func(s nbs.Stats) metrics.Histogram { return s.WriteManifestLatency }},
+}
+
+// chunkStoreCollector is a prometheus.Collector which exports the statistics of the chunk stores of each database.
+// The databases are fetched at collection time, so that databases added by reloading the server are exported.
+type chunkStoreCollector struct {
+	dbs func() []dsqle.Database
+
+	chunkGets      *prometheus.Desc
+	chunkHasChecks *prometheus.Desc
+	chunkPuts      *prometheus.Desc
+	histograms     []*prometheus.Desc
+}
+
+func newChunkStoreCollector(dbs func() []dsqle.Database) *chunkStoreCollector {
+	labels := []string{"database"}
+	histograms := make([]*prometheus.Desc, len(nbsHistograms))
+	for i, h := range nbsHistograms {
+		histograms[i] = prometheus.NewDesc(prometheus.BuildFQName(metricsNamespace, "nbs", h.name), h.help, labels, nil)
+	}
+
+	return &chunkStoreCollector{
+		dbs:            dbs,
+		chunkGets:      prometheus.NewDesc(prometheus.BuildFQName(metricsNamespace, "chunk_store", "chunk_gets_total"), "The number of chunks read from the chunk store.", labels, nil),
+		chunkHasChecks: prometheus.NewDesc(prometheus.BuildFQName(metricsNamespace, "chunk_store", "chunk_has_checks_total"), "The number of chunk existence checks.", labels, nil),
+		chunkPuts:      prometheus.NewDesc(prometheus.BuildFQName(metricsNamespace, "chunk_store", "chunk_puts_total"), "The number of chunks written to the chunk store.", labels, nil),
+		histograms:     histograms,
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *chunkStoreCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.chunkGets
+	ch <- c.chunkHasChecks
+	ch <- c.chunkPuts
+
+	for _, desc := range c.histograms {
+		ch <- desc
+	}
+}
+
+// Collect implements prometheus.Collector
+func (c *chunkStoreCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, db := range c.dbs() {
+		// branch databases share the chunk store of their database
+		if db.IsBranchDatabase() {
+			continue
+		}
+
+		stats := db.GetDoltDB().CSMetrics()
+
+		if csMetrics, ok := stats.(chunks.CSMetrics); ok {
+			ch <- prometheus.MustNewConstMetric(c.chunkGets, prometheus.CounterValue, float64(csMetrics.TotalChunkGets), db.Name())
+			ch <- prometheus.MustNewConstMetric(c.chunkHasChecks, prometheus.CounterValue, float64(csMetrics.TotalChunkHasChecks), db.Name())
+			ch <- prometheus.MustNewConstMetric(c.chunkPuts, prometheus.CounterValue, float64(csMetrics.TotalChunkPuts), db.Name())
+			stats = csMetrics.Delegate
+		}
+
+		if nbsStats, ok := stats.(nbs.Stats); ok {
+			for i, h := range nbsHistograms {
+				ch <- constHistogram(c.histograms[i], h.get(nbsStats), h.seconds, db.Name())
+			}
+		}
+	}
+}
+
+// constHistogram converts a metrics.Histogram to a prometheus histogram.  Histograms of durations are converted from
+// nanoseconds to seconds.
+func constHistogram(desc *prometheus.Desc, h metrics.Histogram, seconds bool, labelValues ...string) prometheus.Metric {
+	scale := 1.0
+	if seconds {
+		scale = float64(time.Nanosecond) / float64(time.Second)
+	}
+
+	buckets := make(map[float64]uint64, exportedHistogramBuckets)
+	var count uint64
+	for i, n := range h.Buckets() {
+		count += n
+
+		if i < exportedHistogramBuckets {
+			buckets[float64(uint64(1)<<uint(i+1))*scale] = count
+		}
+	}
+
+	return prometheus.MustNewConstHistogram(desc, count, float64(h.Sum())*scale, buckets, labelValues...)
+}
+
+func metricsHostPort(serverConfig ServerConfig) string {
+	return net.JoinHostPort(serverConfig.MetricsHost(), strconv.Itoa(serverConfig.MetricsPort()))
+}
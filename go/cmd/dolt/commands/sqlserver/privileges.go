@@ -0,0 +1,352 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"regexp"
+
+	"github.com/dolthub/go-mysql-server/server"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/vitess/go/mysql"
+	"github.com/dolthub/vitess/go/sqltypes"
+	"github.com/dolthub/vitess/go/vt/proto/query"
+	"github.com/dolthub/vitess/go/vt/sqlparser"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	dsqle "github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/privileges"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+)
+
+// showWarningsRegex matches the SHOW WARNINGS statements the engine parses itself, which require no privileges.
+var showWarningsRegex = regexp.MustCompile(`(?i)^\s*show\s+warnings\b`)
+
+// erUnsupportedPS is the MySQL error code returned for statements which can't be prepared.
+const erUnsupportedPS = 1295
+
+// newPrivilegeStore returns the store of the users of the server.  The user of |cfg| is a superuser, and the other
+// users of |cfg| have the grants it lists.  Users created by SQL statements are persisted in the privilege file of
+// |cfg|.
+func newPrivilegeStore(cfg ServerConfig, fs filesys.ReadWriteFS) (*privileges.Store, error) {
+	store, err := privileges.NewStore(fs, cfg.PrivilegeFilePath())
+	if err != nil {
+		return nil, err
+	}
+
+	store.AddStaticUser(cfg.User(), cfg.Password(), []privileges.Grant{
+		privileges.NewGlobalGrant(privileges.AllPrivileges | privileges.GrantOptionPriv),
+	})
+
+	for _, user := range cfg.Users() {
+		var grants []privileges.Grant
+		for _, grantStr := range user.Grants {
+			grant, err := privileges.ParseGrant(grantStr)
+			if err != nil {
+				return nil, err
+			}
+			grants = append(grants, grant)
+		}
+
+		store.AddStaticUser(user.Name, user.Password, grants)
+	}
+
+	return store, nil
+}
+
+// requirementsHandler is a mysql.Handler wrapped by privilegesHandler, which passes it the privileges required by each
+// query rather than have it compute them again.
+type requirementsHandler interface {
+	mysql.Handler
+	// ComQueryWithRequirements is ComQuery for a query requiring the privileges of |reqs|.
+	ComQueryWithRequirements(c *mysql.Conn, query string, reqs []privileges.Requirement, callback func(*sqltypes.Result) error) error
+}
+
+// privilegesHandler is a mysql.Handler which executes the statements managing users and their privileges, and checks
+// that the user of a connection holds the privileges required by every other query, prepared statement and execution of
+// a prepared statement before passing it to the wrapped Handler.  When the server is read only, queries requiring any
+// privilege but SELECT are rejected.
+type privilegesHandler struct {
+	mysql.Handler
+	sm       *server.SessionManager
+	store    *privileges.Store
+	readOnly bool
+}
+
+func (h privilegesHandler) ComInitDB(c *mysql.Conn, schemaName string) error {
+	ctx, err := h.sm.NewContextWithQuery(c, "")
+	if err != nil {
+		return err
+	}
+
+	reqs, err := privileges.RequiredPrivileges(&sqlparser.Use{DBName: sqlparser.NewTableIdent(schemaName)}, "")
+	if err != nil {
+		return err
+	}
+
+	err = h.checkRequirements(ctx, c.User, reqs)
+	if err != nil {
+		return err
+	}
+
+	return h.Handler.ComInitDB(c, schemaName)
+}
+
+func (h privilegesHandler) ComQuery(c *mysql.Conn, query string, callback func(*sqltypes.Result) error) error {
+	st, err := privileges.ParseAccountStatement(query)
+	if err != nil {
+		return err
+	} else if st != nil {
+		return h.execAccountStatement(c, st, callback)
+	}
+
+	reqs, err := h.checkQuery(c, query)
+	if err != nil {
+		return err
+	}
+
+	if reqsHandler, ok := h.Handler.(requirementsHandler); ok {
+		return reqsHandler.ComQueryWithRequirements(c, query, reqs, callback)
+	}
+	return h.Handler.ComQuery(c, query, callback)
+}
+
+func (h privilegesHandler) ComPrepare(c *mysql.Conn, query string) ([]*query.Field, error) {
+	st, err := privileges.ParseAccountStatement(query)
+	if err != nil {
+		return nil, err
+	} else if st != nil {
+		return nil, mysql.NewSQLError(erUnsupportedPS, mysql.SSUnknownSQLState,
+			"This command is not supported in the prepared statement protocol yet")
+	}
+
+	_, err = h.checkQuery(c, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return h.Handler.ComPrepare(c, query)
+}
+
+func (h privilegesHandler) ComStmtExecute(c *mysql.Conn, prepare *mysql.PrepareData, callback func(*sqltypes.Result) error) error {
+	// privileges may have been revoked since the statement was prepared
+	_, err := h.checkQuery(c, prepare.PrepareStmt)
+	if err != nil {
+		return err
+	}
+
+	return h.Handler.ComStmtExecute(c, prepare, callback)
+}
+
+// checkQuery returns the privileges required to execute |query|, or an error if the user of |c| doesn't hold them.
+func (h privilegesHandler) checkQuery(c *mysql.Conn, query string) ([]privileges.Requirement, error) {
+	ctx, err := h.sm.NewContextWithQuery(c, query)
+	if err != nil {
+		return nil, err
+	}
+
+	reqs, err := requiredPrivileges(query, ctx.GetCurrentDatabase())
+	if err != nil {
+		return nil, err
+	}
+
+	err = h.checkRequirements(ctx, c.User, reqs)
+	if err != nil {
+		return nil, err
+	}
+
+	return reqs, nil
+}
+
+func (h privilegesHandler) execAccountStatement(c *mysql.Conn, st *privileges.AccountStatement, callback func(*sqltypes.Result) error) error {
+	ctx, err := h.sm.NewContextWithQuery(c, "")
+	if err != nil {
+		return err
+	}
+
+	grants, err := st.Exec(h.store, c.User, ctx.GetCurrentDatabase())
+	if err != nil {
+		return err
+	} else if grants == nil {
+		return callback(&sqltypes.Result{})
+	}
+
+	result := &sqltypes.Result{Fields: []*query.Field{{Name: grants.Column, Type: sqltypes.VarChar}}}
+	for _, grant := range grants.Grants {
+		result.Rows = append(result.Rows, []sqltypes.Value{sqltypes.NewVarChar(grant)})
+	}
+	result.RowsAffected = uint64(len(result.Rows))
+
+	return callback(result)
+}
+
+// requiredPrivileges returns the privileges required to execute |query|.  Queries which can't be parsed are denied, as
+// the privileges they require can't be known.
+func requiredPrivileges(query, currentDB string) ([]privileges.Requirement, error) {
+	spatialDDL, err := dsqle.ParseSpatialDDL(query)
+	if err != nil {
+		return nil, err
+	} else if spatialDDL != nil {
+		return spatialDDLRequirements(spatialDDL, currentDB)
+	}
+
+	indexDDL, err := dsqle.ParseIndexDDL(query)
+	if err != nil {
+		return nil, err
+	} else if indexDDL != nil {
+		return indexDDLRequirements(indexDDL, currentDB), nil
+	}
+
+	checkDDL, err := dsqle.ParseCheckDDL(query)
+	if err != nil {
+		return nil, err
+	} else if checkDDL != nil {
+		return checkDDLRequirements(checkDDL, currentDB), nil
+	}
+
+	analyze, err := dsqle.ParseAnalyzeTable(query)
+	if err != nil {
+		return nil, err
+	} else if analyze != nil {
+		return analyzeTableRequirements(analyze, currentDB), nil
+	}
+
+	stmt, err := sqlparser.Parse(query)
+	if err == sqlparser.ErrEmpty {
+		// empty statements are skipped
+		return nil, nil
+	} else if err != nil && showWarningsRegex.MatchString(query) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return privileges.RequiredPrivileges(stmt, currentDB)
+}
+
+func checkDDLRequirements(checkDDL *dsqle.CheckDDL, currentDB string) []privileges.Requirement {
+	db := checkDDL.Database
+	if db == "" {
+		db = currentDB
+	}
+
+	privs := privileges.AlterPriv
+	if checkDDL.CreatesTable {
+		privs = privileges.CreatePriv
+	}
+
+	return []privileges.Requirement{{Database: db, Table: checkDDL.TableName, Privileges: privs}}
+}
+
+func indexDDLRequirements(indexDDL *dsqle.IndexDDL, currentDB string) []privileges.Requirement {
+	db := indexDDL.Database
+	if db == "" {
+		db = currentDB
+	}
+
+	privs := privileges.IndexPriv
+	if indexDDL.CreatesTable {
+		privs = privileges.CreatePriv
+	} else if indexDDL.Query != "" {
+		// the rest of an ALTER TABLE statement adds or drops CHECK constraints
+		privs |= privileges.AlterPriv
+	}
+
+	return []privileges.Requirement{{Database: db, Table: indexDDL.TableName, Privileges: privs}}
+}
+
+// spatialDDLRequirements returns the privileges required to execute |spatialDDL|: those of the rest of the statement,
+// and the INDEX privilege for an ALTER TABLE statement adding SPATIAL indexes.
+func spatialDDLRequirements(spatialDDL *dsqle.SpatialDDL, currentDB string) ([]privileges.Requirement, error) {
+	var reqs []privileges.Requirement
+	if spatialDDL.Query != "" {
+		var err error
+		reqs, err = requiredPrivileges(spatialDDL.Query, currentDB)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(spatialDDL.Indexes) > 0 && !spatialDDL.CreatesTable {
+		db := spatialDDL.Database
+		if db == "" {
+			db = currentDB
+		}
+		reqs = append(reqs, privileges.Requirement{Database: db, Table: spatialDDL.TableName, Privileges: privileges.IndexPriv})
+	}
+
+	return reqs, nil
+}
+
+// checkRequirements returns an error if the user named |user| doesn't hold the privileges of every requirement in
+// |reqs|.
+func (h privilegesHandler) checkRequirements(ctx *sql.Context, user string, reqs []privileges.Requirement) error {
+	for _, req := range reqs {
+		if req.Database == "" {
+			continue
+		}
+
+		target := privileges.NewTarget(req.Database, sessionBranch(ctx, req.Database), req.Table)
+		if req.Privileges == privileges.NoPrivileges {
+			if !h.store.HasAnyPrivilegeIn(user, target) {
+				return privileges.AccessDeniedError(user, req.Database)
+			}
+			continue
+		}
+
+		if h.readOnly && req.Privileges&^privileges.SelectPriv != privileges.NoPrivileges {
+			return mysql.NewSQLError(mysql.EROptionPreventsStatement, mysql.SSUnknownSQLState,
+				"The MySQL server is running with the --read-only option so it cannot execute this statement")
+		}
+
+		held := h.store.PrivilegesOn(user, target)
+		if !held.Has(req.Privileges) {
+			return privileges.CommandDeniedError(user, req.Privileges&^held, target)
+		}
+	}
+
+	return nil
+}
+
+// sessionBranch returns the branch the session of |ctx| has checked out in the database named |dbName|, or "" if it
+// isn't known.
+func sessionBranch(ctx *sql.Context, dbName string) string {
+	_, val := ctx.Session.Get(dbName + dsqle.HeadRefKeySuffix)
+	headRef, ok := val.(string)
+	if !ok || headRef == "" {
+		return ""
+	}
+
+	dref, err := ref.Parse(headRef)
+	if err != nil {
+		return ""
+	}
+
+	return dref.GetPath()
+}
+
+// analyzeTableRequirements returns the privileges required to analyze the tables of |analyze|, which are the privileges
+// to read them and to write their statistics, like MySQL.
+func analyzeTableRequirements(analyze *dsqle.AnalyzeTable, currentDB string) []privileges.Requirement {
+	var reqs []privileges.Requirement
+	for _, name := range analyze.Tables {
+		db := name.Database
+		if db == "" {
+			db = currentDB
+		}
+		reqs = append(reqs, privileges.Requirement{Database: db, Table: name.Table, Privileges: privileges.SelectPriv | privileges.InsertPriv})
+	}
+
+	return reqs
+}
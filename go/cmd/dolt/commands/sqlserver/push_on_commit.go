@@ -0,0 +1,58 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	dsqle "github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+)
+
+// newPushOnCommitHooks returns the hooks pushing the databases configured by |cfgs| to their remotes, and sets them
+// as the commit hooks of the databases.  The hooks must be run for the pushes to be made.
+func newPushOnCommitHooks(cfgs []PushOnCommitConfig, mrEnv env.MultiRepoEnv) ([]*dsqle.PushOnCommitHook, error) {
+	var hooks []*dsqle.PushOnCommitHook
+	for _, cfg := range cfgs {
+		dEnv, ok := mrEnv[cfg.Database]
+		if !ok {
+			return nil, fmt.Errorf("pushed database %s does not exist", cfg.Database)
+		}
+
+		remotes, err := dEnv.GetRemotes()
+		if err != nil {
+			return nil, err
+		}
+
+		var dbHooks []doltdb.CommitHook
+		for _, name := range cfg.RemoteNames() {
+			remote, ok := remotes[name]
+			if !ok {
+				return nil, fmt.Errorf("pushed database %s has no remote named %s", cfg.Database, name)
+			}
+
+			retryDelay := time.Duration(cfg.RetryDelay()) * time.Millisecond
+			hook := dsqle.NewPushOnCommitHook(dEnv, remote, cfg.Retries(), cfg.QueueLength(), retryDelay)
+			hooks = append(hooks, hook)
+			dbHooks = append(dbHooks, hook)
+		}
+
+		dEnv.DoltDB.SetCommitHooks(dbHooks...)
+	}
+
+	return hooks, nil
+}
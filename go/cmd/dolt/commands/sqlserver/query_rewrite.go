@@ -0,0 +1,41 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"github.com/dolthub/vitess/go/mysql"
+	"github.com/dolthub/vitess/go/sqltypes"
+	querypb "github.com/dolthub/vitess/go/vt/proto/query"
+
+	dsqle "github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+)
+
+// queryRewriteHandler is a mysql.Handler which rewrites the syntax the engine doesn't support in queries, the JSON ->
+// and ->> operators and MATCH ... AGAINST, before passing them to the wrapped Handler.
+type queryRewriteHandler struct {
+	mysql.Handler
+}
+
+func (h queryRewriteHandler) ComQuery(c *mysql.Conn, query string, callback func(*sqltypes.Result) error) error {
+	return h.Handler.ComQuery(c, rewriteQuery(query), callback)
+}
+
+func (h queryRewriteHandler) ComPrepare(c *mysql.Conn, query string) ([]*querypb.Field, error) {
+	return h.Handler.ComPrepare(c, rewriteQuery(query))
+}
+
+func rewriteQuery(query string) string {
+	return dsqle.RewriteMatchAgainst(dsqle.RewriteJSONOperators(query))
+}
@@ -0,0 +1,69 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"time"
+
+	"github.com/dolthub/go-mysql-server/server"
+	"github.com/dolthub/vitess/go/mysql"
+	"github.com/dolthub/vitess/go/sqltypes"
+	"github.com/sirupsen/logrus"
+
+	dsqle "github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+)
+
+// maxQueryFingerprints is the number of distinct queries whose statistics are kept by the server
+const maxQueryFingerprints = 1000
+
+// queryStatsHandler is a mysql.Handler which records the latency, the number of rows sent and the number of table
+// rows examined of every query handled by the wrapped Handler.  Queries running for at least slowThreshold are logged
+// as slow queries, unless slowThreshold is 0.  Prepared statements aren't recorded, as the engine doesn't support them.
+type queryStatsHandler struct {
+	mysql.Handler
+	sm            *server.SessionManager
+	stats         *dsqle.QueryStats
+	slowThreshold time.Duration
+}
+
+func (h queryStatsHandler) ComQuery(c *mysql.Conn, query string, callback func(*sqltypes.Result) error) error {
+	ctx, err := h.sm.NewContext(c)
+	if err != nil {
+		return err
+	}
+
+	sess := dsqle.DSessFromSess(ctx.Session)
+	database := ctx.GetCurrentDatabase()
+	examinedBefore := sess.RowsExamined()
+
+	var rowsSent uint64
+	start := time.Now()
+	err = h.Handler.ComQuery(c, query, func(res *sqltypes.Result) error {
+		rowsSent += uint64(len(res.Rows))
+		return callback(res)
+	})
+	latency := time.Since(start)
+	rowsExamined := sess.RowsExamined() - examinedBefore
+
+	fingerprint := h.stats.Record(database, query, latency, rowsSent, rowsExamined, err)
+
+	// the fingerprint is logged rather than the query, which may hold secrets such as passwords
+	if h.slowThreshold > 0 && latency >= h.slowThreshold {
+		logrus.Warnf("slow query on connection %d to database %q: %dms, %d rows sent, %d rows examined: %s",
+			c.ConnectionID, database, latency.Milliseconds(), rowsSent, rowsExamined, fingerprint)
+	}
+
+	return err
+}
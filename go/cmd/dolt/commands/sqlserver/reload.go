@@ -0,0 +1,461 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	sqle "github.com/dolthub/go-mysql-server"
+	"github.com/dolthub/go-mysql-server/server"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/vitess/go/mysql"
+	"github.com/dolthub/vitess/go/sqltypes"
+	querypb "github.com/dolthub/vitess/go/vt/proto/query"
+	"github.com/opentracing/opentracing-go"
+	"github.com/sirupsen/logrus"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/commands"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	dsqle "github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dfunctions"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+)
+
+// serverEngine is the engine serving the connections opened while a configuration of the server is current, along
+// with the handler chain running queries on it.
+type serverEngine struct {
+	engine  *sqle.Engine
+	gms     *server.Handler
+	handler mysql.Handler
+}
+
+// engineOptions are the options of a serverEngine which don't come from the databases it serves.
+type engineOptions struct {
+	listenerOptions
+	username   string
+	email      string
+	tracer     opentracing.Tracer
+	address    string
+	rdTimeout  time.Duration
+	processes  *sql.ProcessList
+	memManager *sql.MemoryManager
+	// queryStats are the statistics of the queries of every engine of the server
+	queryStats *dsqle.QueryStats
+}
+
+// newServerEngine returns an engine serving |dbs|.  Its handler is the handler of the go-mysql-server engine wrapped in
+// these handlers, innermost first:
+//  1. checkConstraintHandler, executing the DDL with CHECK constraints and expression or prefix indexes
+//  2. explainAnalyzeHandler, executing EXPLAIN ANALYZE
+//  3. analyzeTableHandler, executing ANALYZE TABLE
+//  4. transactionHandler, starting the transactions of the statements
+//  5. branchDatabaseHandler, adding the databases of the branches used by a query
+//  6. replicaHandler, refreshing the replicated databases used by a query, if the server has replicas
+//  7. privilegesHandler, checking the privileges of the users of the store of |opts|, which it passes to replicaHandler
+//  8. queryRewriteHandler, rewriting the JSON operators and MATCH ... AGAINST
+//  9. queryStatsHandler, recording the statistics of the queries
+//
+// The engines of a server share their process list, so that every connection is listed and can be killed no matter
+// which engine serves it.
+func newServerEngine(cfg ServerConfig, dbs []dsqle.Database, opts engineOptions) (*serverEngine, error) {
+	c := sql.NewCatalog()
+	if opts.processes != nil {
+		c.ProcessList = opts.processes
+		c.MemoryManager = opts.memManager
+	}
+
+	a := dsqle.NewAnalyzerBuilder(c).WithParallelism(cfg.QueryParallelism()).Build()
+	e := sqle.New(c, a, nil)
+
+	err := e.Catalog.Register(dfunctions.DoltFunctions...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	dfunctions.RegisterOverrides(e.Catalog.FunctionRegistry)
+
+	for _, db := range dbs {
+		e.AddDatabase(db)
+	}
+
+	e.AddDatabase(dsqle.NewInformationSchemaDatabase(e.Catalog))
+
+	sb := newSessionBuilder(e, opts.username, opts.email, cfg.AutoCommit(), opts.queryStats)
+	sm := server.NewSessionManager(sb, opts.tracer, e.Catalog.HasDB, e.Catalog.MemoryManager, opts.address)
+	gms := server.NewHandler(e, sm, opts.rdTimeout)
+
+	var handler mysql.Handler = checkConstraintHandler{gms, sm, e}
+	handler = explainAnalyzeHandler{handler, sm, e}
+	handler = analyzeTableHandler{handler, sm, e}
+	handler = transactionHandler{handler, sm}
+	handler = branchDatabaseHandler{handler, sm, e, &sync.Mutex{}}
+	if len(opts.replicas) > 0 {
+		handler = replicaHandler{handler, sm, opts.replicas}
+	}
+	handler = privilegesHandler{handler, sm, opts.store, opts.readOnly}
+	handler = queryRewriteHandler{handler}
+	handler = queryStatsHandler{handler, sm, opts.queryStats, time.Duration(cfg.SlowQueryThreshold()) * time.Millisecond}
+
+	return &serverEngine{e, gms, handler}, nil
+}
+
+// reloadableHandler is a mysql.Handler which serves every connection with the engine which was current when the
+// connection was opened.  Reloading the server makes a new engine current without affecting open connections.
+type reloadableHandler struct {
+	mu      *sync.RWMutex
+	current *serverEngine
+	conns   map[uint32]*serverEngine
+}
+
+func newReloadableHandler(engine *serverEngine) *reloadableHandler {
+	return &reloadableHandler{
+		mu:      &sync.RWMutex{},
+		current: engine,
+		conns:   make(map[uint32]*serverEngine),
+	}
+}
+
+// currentEngine returns the engine serving new connections.
+func (h *reloadableHandler) currentEngine() *serverEngine {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.current
+}
+
+// setCurrentEngine makes |engine| serve the connections opened from now on.
+func (h *reloadableHandler) setCurrentEngine(engine *serverEngine) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.current = engine
+}
+
+func (h *reloadableHandler) engineFor(c *mysql.Conn) *serverEngine {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if engine, ok := h.conns[c.ConnectionID]; ok {
+		return engine
+	}
+
+	return h.current
+}
+
+func (h *reloadableHandler) NewConnection(c *mysql.Conn) {
+	h.mu.Lock()
+	engine := h.current
+	h.conns[c.ConnectionID] = engine
+	h.mu.Unlock()
+
+	engine.handler.NewConnection(c)
+}
+
+func (h *reloadableHandler) ConnectionClosed(c *mysql.Conn) {
+	engine := h.engineFor(c)
+
+	h.mu.Lock()
+	delete(h.conns, c.ConnectionID)
+	h.mu.Unlock()
+
+	engine.handler.ConnectionClosed(c)
+}
+
+func (h *reloadableHandler) ComInitDB(c *mysql.Conn, schemaName string) error {
+	return h.engineFor(c).handler.ComInitDB(c, schemaName)
+}
+
+func (h *reloadableHandler) ComQuery(c *mysql.Conn, query string, callback func(*sqltypes.Result) error) error {
+	return h.engineFor(c).handler.ComQuery(c, query, callback)
+}
+
+func (h *reloadableHandler) ComPrepare(c *mysql.Conn, query string) ([]*querypb.Field, error) {
+	return h.engineFor(c).handler.ComPrepare(c, query)
+}
+
+func (h *reloadableHandler) ComStmtExecute(c *mysql.Conn, prepare *mysql.PrepareData, callback func(*sqltypes.Result) error) error {
+	return h.engineFor(c).handler.ComStmtExecute(c, prepare, callback)
+}
+
+func (h *reloadableHandler) WarningCount(c *mysql.Conn) uint16 {
+	return h.engineFor(c).handler.WarningCount(c)
+}
+
+func (h *reloadableHandler) ComResetConnection(c *mysql.Conn) {
+	h.engineFor(c).handler.ComResetConnection(c)
+}
+
+// serverReloader applies a new configuration to a running server.  The log level, the maximum number of connections,
+// the databases, autocommit and the query parallelism are reloaded.  The databases, autocommit and the query
+// parallelism only apply to connections opened after the reload.  Changes to any other setting require a restart and
+// are ignored with a warning.  So do changes of the databases which move or remove a database with replicas or push on
+// commit hooks, as those keep running on the databases the server started with.
+type serverReloader struct {
+	ctx      context.Context
+	version  string
+	dEnv     *env.DoltEnv
+	opts     engineOptions
+	handler  *reloadableHandler
+	listener *connLimitListener
+	// pinnedDBs are the names of the databases with replicas or push on commit hooks, in order
+	pinnedDBs []string
+
+	mu  *sync.Mutex
+	cfg ServerConfig
+	// namesAndPaths are the configured databases which are served, which are the databases of cfg unless a change of
+	// them was ignored
+	namesAndPaths []env.EnvNameAndPath
+	dbs           map[env.EnvNameAndPath]dsqle.Database
+}
+
+func newServerReloader(ctx context.Context, version string, dEnv *env.DoltEnv, cfg ServerConfig, dbs []dsqle.Database, opts engineOptions, handler *reloadableHandler, listener *connLimitListener) *serverReloader {
+	pinned := make(map[string]bool)
+	for _, replica := range cfg.Replicas() {
+		pinned[replica.Database] = true
+	}
+	for _, push := range cfg.PushOnCommit() {
+		pinned[push.Database] = true
+	}
+	var pinnedDBs []string
+	for name := range pinned {
+		pinnedDBs = append(pinnedDBs, name)
+	}
+	sort.Strings(pinnedDBs)
+
+	return &serverReloader{
+		ctx:           ctx,
+		version:       version,
+		dEnv:          dEnv,
+		opts:          opts,
+		handler:       handler,
+		listener:      listener,
+		pinnedDBs:     pinnedDBs,
+		mu:            &sync.Mutex{},
+		cfg:           cfg,
+		namesAndPaths: cfg.DatabaseNamesAndPaths(),
+		dbs:           databasesByNameAndPath(cfg.DatabaseNamesAndPaths(), dbs),
+	}
+}
+
+// databasesByNameAndPath returns |dbs| keyed by the name and path they are configured with in |namesAndPaths|.  The
+// database of the working directory, which is served when no databases are configured, has an empty path.
+func databasesByNameAndPath(namesAndPaths []env.EnvNameAndPath, dbs []dsqle.Database) map[env.EnvNameAndPath]dsqle.Database {
+	paths := make(map[string]string)
+	for _, nameAndPath := range namesAndPaths {
+		paths[nameAndPath.Name] = nameAndPath.Path
+	}
+
+	byNameAndPath := make(map[env.EnvNameAndPath]dsqle.Database)
+	for _, db := range dbs {
+		byNameAndPath[env.EnvNameAndPath{Name: db.Name(), Path: paths[db.Name()]}] = db
+	}
+
+	return byNameAndPath
+}
+
+// reload applies |cfg| to the server.  If |cfg| is invalid or its databases can't be loaded, nothing is changed.
+func (r *serverReloader) reload(cfg ServerConfig) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	err := ValidateConfig(cfg)
+	if err != nil {
+		return err
+	}
+
+	level, err := logrus.ParseLevel(cfg.LogLevel().String())
+	if err != nil {
+		return err
+	}
+
+	namesAndPaths := cfg.DatabaseNamesAndPaths()
+	pinnedDB := changedPinnedDatabase(r.pinnedDBs, r.namesAndPaths, namesAndPaths)
+	if pinnedDB != "" {
+		namesAndPaths = r.namesAndPaths
+	}
+
+	dbs, err := r.loadDatabases(namesAndPaths)
+	if err != nil {
+		return err
+	}
+
+	engine, err := newServerEngine(cfg, dbs, r.opts)
+	if err != nil {
+		return err
+	}
+
+	for _, setting := range restartRequiredChanges(r.cfg, cfg) {
+		logrus.Warnf("ignoring the change of %s, which requires a restart of the server", setting)
+	}
+	if pinnedDB != "" {
+		logrus.Warnf("ignoring the change of databases, which changes database %s with replicas or push on commit hooks and requires a restart of the server", pinnedDB)
+	}
+
+	logrus.SetLevel(level)
+	r.listener.setMaxConns(cfg.MaxConnections())
+	r.handler.setCurrentEngine(engine)
+	r.cfg = cfg
+	r.namesAndPaths = namesAndPaths
+	r.dbs = databasesByNameAndPath(namesAndPaths, dbs)
+
+	logrus.Infof("reloaded the server config, serving %d databases", len(dbs))
+	return nil
+}
+
+// loadDatabases returns the databases configured by |namesAndPaths|.  Databases which are already served with the same
+// name and path are reused, and the others are loaded.
+func (r *serverReloader) loadDatabases(namesAndPaths []env.EnvNameAndPath) ([]dsqle.Database, error) {
+	if len(namesAndPaths) == 0 {
+		if len(r.namesAndPaths) == 0 {
+			return dbsFromMap(r.dbs), nil
+		}
+
+		return commands.CollectDBs(env.DoltEnvAsMultiEnv(r.dEnv), newDatabase), nil
+	}
+
+	var dbs []dsqle.Database
+	var toLoad []env.EnvNameAndPath
+	names := make(map[string]bool)
+	for _, nameAndPath := range namesAndPaths {
+		if names[nameAndPath.Name] {
+			return nil, fmt.Errorf("database %s is configured more than once", nameAndPath.Name)
+		}
+		names[nameAndPath.Name] = true
+
+		if db, ok := r.dbs[nameAndPath]; ok {
+			dbs = append(dbs, db)
+		} else {
+			toLoad = append(toLoad, nameAndPath)
+		}
+	}
+
+	mrEnv, err := env.LoadMultiEnv(r.ctx, env.GetCurrentUserHomeDir, r.dEnv.FS, r.version, toLoad...)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(dbs, commands.CollectDBs(mrEnv, newDatabase)...), nil
+}
+
+func dbsFromMap(dbs map[env.EnvNameAndPath]dsqle.Database) []dsqle.Database {
+	var result []dsqle.Database
+	for _, db := range dbs {
+		result = append(result, db)
+	}
+
+	return result
+}
+
+// changedPinnedDatabase returns the first of |pinned| which is served from another path, or not served at all, when
+// the databases configured by |new| are served instead of those configured by |old|.  It returns "" if there's none.
+func changedPinnedDatabase(pinned []string, old, new []env.EnvNameAndPath) string {
+	if len(pinned) == 0 {
+		return ""
+	}
+
+	// the database of the working directory is served when no databases are configured
+	if len(old) == 0 && len(new) == 0 {
+		return ""
+	} else if len(old) == 0 || len(new) == 0 {
+		return pinned[0]
+	}
+
+	oldPaths, newPaths := make(map[string]string), make(map[string]string)
+	for _, nameAndPath := range old {
+		oldPaths[nameAndPath.Name] = nameAndPath.Path
+	}
+	for _, nameAndPath := range new {
+		newPaths[nameAndPath.Name] = nameAndPath.Path
+	}
+
+	for _, name := range pinned {
+		if path, ok := newPaths[name]; !ok || path != oldPaths[name] {
+			return name
+		}
+	}
+
+	return ""
+}
+
+// restartRequiredChanges returns the names of the settings which differ between |old| and |new| and can't be
+// reloaded.
+func restartRequiredChanges(old, new ServerConfig) []string {
+	settings := []struct {
+		name     string
+		old, new interface{}
+	}{
+		{"listener.host", old.Host(), new.Host()},
+		{"listener.port", old.Port(), new.Port()},
+		{"listener.read_timeout_millis", old.ReadTimeout(), new.ReadTimeout()},
+		{"listener.write_timeout_millis", old.WriteTimeout(), new.WriteTimeout()},
+		{"listener.tls_key", old.TLSKey(), new.TLSKey()},
+		{"listener.tls_cert", old.TLSCert(), new.TLSCert()},
+		{"listener.require_secure_transport", old.RequireSecureTransport(), new.RequireSecureTransport()},
+		{"behavior.read_only", old.ReadOnly(), new.ReadOnly()},
+		{"user", []string{old.User(), old.Password()}, []string{new.User(), new.Password()}},
+		{"users", old.Users(), new.Users()},
+		{"privilege_file", old.PrivilegeFilePath(), new.PrivilegeFilePath()},
+		{"metrics", []interface{}{old.MetricsHost(), old.MetricsPort(), old.MetricsLabels()}, []interface{}{new.MetricsHost(), new.MetricsPort(), new.MetricsLabels()}},
+		{"replication", old.Replicas(), new.Replicas()},
+		{"push_on_commit", old.PushOnCommit(), new.PushOnCommit()},
+	}
+
+	var changed []string
+	for _, setting := range settings {
+		if !reflect.DeepEqual(setting.old, setting.new) {
+			changed = append(changed, setting.name)
+		}
+	}
+
+	return changed
+}
+
+// reloadOnSignal reloads the server of |controller| with the yaml config file at |path| whenever the process receives
+// SIGHUP, until the returned function is called.
+func reloadOnSignal(fs filesys.Filesys, path string, controller *ServerController) func() {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGHUP)
+
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-sigCh:
+				cfg, err := getYAMLServerConfig(fs, path)
+				if err == nil {
+					err = controller.ReloadConfig(cfg)
+				}
+
+				if err != nil {
+					logrus.Errorf("failed to reload the server config from '%s': %v", path, err)
+				}
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(sigCh)
+		close(done)
+	}
+}
@@ -0,0 +1,218 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/server"
+	"github.com/dolthub/vitess/go/mysql"
+	"github.com/dolthub/vitess/go/sqltypes"
+	"github.com/sirupsen/logrus"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	dsqle "github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/privileges"
+)
+
+// replica is a database of the server which follows a branch of one of its remotes.
+type replica struct {
+	db          dsqle.Database
+	dEnv        *env.DoltEnv
+	remote      env.Remote
+	branch      ref.DoltRef
+	trackingRef ref.DoltRef
+	interval    time.Duration
+}
+
+// newReplicas returns the replicas configured by |cfgs|, whose databases are among |dbs|.
+func newReplicas(cfgs []ReplicaConfig, mrEnv env.MultiRepoEnv, dbs []dsqle.Database) ([]*replica, error) {
+	var replicas []*replica
+	for _, cfg := range cfgs {
+		dEnv, ok := mrEnv[cfg.Database]
+		if !ok {
+			return nil, fmt.Errorf("replicated database %s does not exist", cfg.Database)
+		}
+
+		var db dsqle.Database
+		for _, curr := range dbs {
+			if curr.Name() == cfg.Database {
+				db = curr
+			}
+		}
+
+		remotes, err := dEnv.GetRemotes()
+		if err != nil {
+			return nil, err
+		}
+
+		remote, ok := remotes[cfg.RemoteName()]
+		if !ok {
+			return nil, fmt.Errorf("replicated database %s has no remote named %s", cfg.Database, cfg.RemoteName())
+		}
+
+		branch := dEnv.RepoState.CWBHeadRef()
+		if cfg.Branch != "" {
+			branch = ref.NewBranchRef(cfg.Branch)
+		}
+
+		trackingRef, err := actions.GetTrackingRef(branch, remote)
+		if err != nil {
+			return nil, err
+		} else if trackingRef == nil {
+			return nil, fmt.Errorf("remote %s of replicated database %s does not fetch branch %s", remote.Name, cfg.Database, branch.GetPath())
+		}
+
+		replicas = append(replicas, &replica{
+			db:          db,
+			dEnv:        dEnv,
+			remote:      remote,
+			branch:      branch,
+			trackingRef: trackingRef,
+			interval:    time.Duration(cfg.Interval()) * time.Millisecond,
+		})
+	}
+
+	return replicas, nil
+}
+
+// run pulls the branch of the replica every interval until |ctx| is done.
+func (r *replica) run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		err := r.pull(ctx)
+		if err != nil && ctx.Err() == nil {
+			logrus.Errorf("failed to pull %s from %s into database %s: %v", r.branch.GetPath(), r.remote.Name, r.db.Name(), err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// pull fetches the branch of the replica from its remote and fast forwards the branch to it.  Sessions pick up the new
+// head of the branch when they run their next query.
+func (r *replica) pull(ctx context.Context) error {
+	srcDB, err := r.remote.GetRemoteDB(ctx, r.dEnv.DoltDB.ValueReadWriter().Format())
+	if err != nil {
+		return err
+	}
+
+	progChan, pullerEventCh, done := actions.DiscardProgress()
+	cm, err := actions.FetchRemoteBranch(ctx, r.dEnv, r.remote, srcDB, r.dEnv.DoltDB, r.branch, progChan, pullerEventCh)
+	done()
+
+	if err != nil {
+		return err
+	}
+
+	err = r.dEnv.DoltDB.SetHeadToCommit(ctx, r.trackingRef, cm)
+	if err != nil {
+		return err
+	}
+
+	return r.db.FastForwardBranch(ctx, r.branch, cm)
+}
+
+// replicaHandler is a mysql.Handler which moves the sessions working on the branches followed by replicas to the
+// latest pulled commit before each query using them, and which rejects queries writing to replicated databases.
+type replicaHandler struct {
+	mysql.Handler
+	sm       *server.SessionManager
+	replicas []*replica
+}
+
+var _ requirementsHandler = replicaHandler{}
+
+func (h replicaHandler) ComQuery(c *mysql.Conn, query string, callback func(*sqltypes.Result) error) error {
+	ctx, err := h.sm.NewContextWithQuery(c, query)
+	if err != nil {
+		return err
+	}
+
+	reqs, err := requiredPrivileges(query, ctx.GetCurrentDatabase())
+	if err != nil {
+		return err
+	}
+
+	return h.ComQueryWithRequirements(c, query, reqs, callback)
+}
+
+// ComQueryWithRequirements implements requirementsHandler.
+func (h replicaHandler) ComQueryWithRequirements(c *mysql.Conn, query string, reqs []privileges.Requirement, callback func(*sqltypes.Result) error) error {
+	ctx, err := h.sm.NewContextWithQuery(c, query)
+	if err != nil {
+		return err
+	}
+
+	for _, req := range reqs {
+		if req.Privileges&^privileges.SelectPriv != privileges.NoPrivileges && h.isReplicated(req.Database) {
+			return mysql.NewSQLError(mysql.EROptionPreventsStatement, mysql.SSUnknownSQLState,
+				"The database %s is a read replica so it cannot execute this statement", req.Database)
+		}
+	}
+
+	// only the databases used by the query are refreshed
+	dSess := dsqle.DSessFromSess(ctx.Session)
+	for _, req := range reqs {
+		if dbName := h.followedDatabase(req.Database); dbName != "" {
+			err = dSess.RefreshHead(ctx, dbName)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return h.Handler.ComQuery(c, query, callback)
+}
+
+// followedDatabase returns the name of the database |dbName| refers to if it's a replicated database or the branch
+// database of the branch a replica follows, or "" if it isn't.
+func (h replicaHandler) followedDatabase(dbName string) string {
+	for _, r := range h.replicas {
+		for _, name := range []string{r.db.Name(), r.db.Name() + "/" + r.branch.GetPath()} {
+			if strings.EqualFold(name, dbName) {
+				return name
+			}
+		}
+	}
+
+	return ""
+}
+
+// isReplicated returns whether |dbName| is a replicated database or one of its branch databases.
+func (h replicaHandler) isReplicated(dbName string) bool {
+	if i := strings.IndexByte(dbName, '/'); i >= 0 {
+		dbName = dbName[:i]
+	}
+
+	for _, r := range h.replicas {
+		if strings.EqualFold(r.db.Name(), dbName) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -16,7 +16,9 @@ package sqlserver
 
 import (
 	"context"
+	"crypto/tls"
 	"net"
+	"net/http"
 	"strconv"
 	"time"
 
@@ -24,17 +26,16 @@ import (
 	"github.com/dolthub/go-mysql-server/auth"
 	"github.com/dolthub/go-mysql-server/server"
 	"github.com/dolthub/go-mysql-server/sql"
-	"github.com/dolthub/go-mysql-server/sql/analyzer"
-	"github.com/dolthub/go-mysql-server/sql/information_schema"
 	"github.com/dolthub/vitess/go/mysql"
+	"github.com/opentracing/opentracing-go"
 	"github.com/sirupsen/logrus"
 
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
 	"github.com/dolthub/dolt/go/cmd/dolt/commands"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	dsqle "github.com/dolthub/dolt/go/libraries/doltcore/sqle"
-	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dfunctions"
 	_ "github.com/dolthub/dolt/go/libraries/doltcore/sqle/dfunctions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/privileges"
 )
 
 // Serve starts a MySQL-compatible server. Returns any errors that were encountered.
@@ -75,23 +76,18 @@ func Serve(ctx context.Context, version string, serverConfig ServerConfig, serve
 		logrus.SetLevel(level)
 	}
 
-	permissions := auth.AllPermissions
-	if serverConfig.ReadOnly() {
-		permissions = auth.ReadPerm
+	var privilegeStore *privileges.Store
+	if privilegeStore, startError = newPrivilegeStore(serverConfig, dEnv.FS); startError != nil {
+		return startError, nil
 	}
 
-	userAuth := auth.NewAudit(auth.NewNativeSingle(serverConfig.User(), serverConfig.Password(), permissions), auth.NewAuditLog(logrus.StandardLogger()))
-
-	c := sql.NewCatalog()
-	a := analyzer.NewBuilder(c).WithParallelism(serverConfig.QueryParallelism()).Build()
-	sqlEngine := sqle.New(c, a, nil)
-
-	err := sqlEngine.Catalog.Register(dfunctions.DoltFunctions...)
-
-	if err != nil {
-		return nil, err
+	var tlsConfig *tls.Config
+	if tlsConfig, startError = loadTLSConfig(serverConfig, dEnv.FS); startError != nil {
+		return startError, nil
 	}
 
+	userAuth := auth.NewAudit(privileges.NewAuth(privilegeStore, serverConfig.ReadOnly()), auth.NewAuditLog(logrus.StandardLogger()))
+
 	var username string
 	var email string
 	var mrEnv env.MultiRepoEnv
@@ -117,35 +113,101 @@ func Serve(ctx context.Context, version string, serverConfig ServerConfig, serve
 
 	dbs := commands.CollectDBs(mrEnv, newDatabase)
 
-	for _, db := range dbs {
-		sqlEngine.AddDatabase(db)
+	var replicas []*replica
+	if replicas, startError = newReplicas(serverConfig.Replicas(), mrEnv, dbs); startError != nil {
+		return startError, nil
 	}
 
-	sqlEngine.AddDatabase(information_schema.NewInformationSchemaDatabase(sqlEngine.Catalog))
+	var pushHooks []*dsqle.PushOnCommitHook
+	if pushHooks, startError = newPushOnCommitHooks(serverConfig.PushOnCommit(), mrEnv); startError != nil {
+		return startError, nil
+	}
 
 	hostPort := net.JoinHostPort(serverConfig.Host(), strconv.Itoa(serverConfig.Port()))
 	readTimeout := time.Duration(serverConfig.ReadTimeout()) * time.Millisecond
 	writeTimeout := time.Duration(serverConfig.WriteTimeout()) * time.Millisecond
-	mySQLServer, startError = server.NewServer(
-		server.Config{
-			Protocol:         "tcp",
-			Address:          hostPort,
-			Auth:             userAuth,
-			ConnReadTimeout:  readTimeout,
-			ConnWriteTimeout: writeTimeout,
-			MaxConnections:   serverConfig.MaxConnections(),
-			// Do not set the value of Version.  Let it default to what go-mysql-server uses.  This should be equivalent
-			// to the value of mysql that we support.
+	serverCfg := server.Config{
+		Protocol:         "tcp",
+		Address:          hostPort,
+		Auth:             userAuth,
+		ConnReadTimeout:  readTimeout,
+		ConnWriteTimeout: writeTimeout,
+		MaxConnections:   serverConfig.MaxConnections(),
+		// Do not set the value of Version.  Let it default to what go-mysql-server uses.  This should be equivalent
+		// to the value of mysql that we support.
+	}
+	engineOpts := engineOptions{
+		listenerOptions: listenerOptions{
+			store:                  privilegeStore,
+			readOnly:               serverConfig.ReadOnly(),
+			tlsConfig:              tlsConfig,
+			requireSecureTransport: serverConfig.RequireSecureTransport(),
+			replicas:               replicas,
 		},
-		sqlEngine,
-		newSessionBuilder(sqlEngine, username, email, serverConfig.AutoCommit()),
-	)
+		username:   username,
+		email:      email,
+		tracer:     opentracing.NoopTracer{},
+		address:    hostPort,
+		rdTimeout:  readTimeout,
+		queryStats: dsqle.NewQueryStats(maxQueryFingerprints),
+	}
+
+	var engine *serverEngine
+	if engine, startError = newServerEngine(serverConfig, dbs, engineOpts); startError != nil {
+		return startError, nil
+	}
+
+	// engines created by reloading the server share the process list of the first one
+	engineOpts.processes = engine.engine.Catalog.ProcessList
+	engineOpts.memManager = engine.engine.Catalog.MemoryManager
+
+	var handler *reloadableHandler
+	var listener *connLimitListener
+	if serverConfig.MetricsPort() == defaultMetricsPort {
+		mySQLServer, handler, listener, startError = newServer(serverCfg, engine, engineOpts.listenerOptions, nil)
+	} else {
+		var metrics *serverMetrics
+		metrics, startError = newServerMetrics(serverConfig.MetricsLabels(), func() []dsqle.Database {
+			return dbsAsDSQLDBs(handler.currentEngine().engine.Catalog.AllDatabases())
+		})
+
+		if startError != nil {
+			cli.PrintErr(startError)
+			return
+		}
+
+		mySQLServer, handler, listener, startError = newServer(serverCfg, engine, engineOpts.listenerOptions, metrics)
+
+		if startError == nil {
+			var metricsServer *http.Server
+			metricsServer, startError = metrics.listen(metricsHostPort(serverConfig))
+
+			if startError == nil {
+				defer metricsServer.Close()
+			} else {
+				mySQLServer.Close()
+				mySQLServer = nil
+			}
+		}
+	}
 
 	if startError != nil {
 		cli.PrintErr(startError)
 		return
 	}
 
+	backgroundCtx, stopBackground := context.WithCancel(ctx)
+	defer stopBackground()
+	for _, r := range replicas {
+		go r.run(backgroundCtx)
+	}
+	for _, hook := range pushHooks {
+		go hook.Run(backgroundCtx)
+	}
+
+	reloader := newServerReloader(ctx, version, dEnv, serverConfig, dbs, engineOpts, handler, listener)
+	serverController.registerReloadFunction(reloader.reload)
+
 	serverController.registerCloseFunction(startError, mySQLServer.Close)
 	closeError = mySQLServer.Start()
 	if closeError != nil {
@@ -155,15 +217,84 @@ func Serve(ctx context.Context, version string, serverConfig ServerConfig, serve
 	return
 }
 
-func newSessionBuilder(sqlEngine *sqle.Engine, username, email string, autocommit bool) server.SessionBuilder {
+// listenerOptions are the options of the listener of the server which server.Config doesn't support.
+type listenerOptions struct {
+	// store holds the users of the server and their privileges
+	store *privileges.Store
+	// readOnly is true if the server rejects queries which modify databases
+	readOnly bool
+	// tlsConfig is the TLS config of the listener, or nil if it doesn't support TLS
+	tlsConfig *tls.Config
+	// requireSecureTransport is true if the listener rejects connections which don't use TLS
+	requireSecureTransport bool
+	// replicas are the databases which follow a branch of a remote and reject writes
+	replicas []*replica
+}
+
+// newServer creates a server.Server which serves new connections with |engine| until it is reloaded.  The handler of
+// the listener is a reloadableHandler, wrapped in a secureTransportHandler if the listener requires secure transport,
+// and in a metricsHandler reporting to |metrics| if it isn't nil.  The returned connLimitListener enforces the maximum
+// number of connections of |cfg|.
+func newServer(cfg server.Config, engine *serverEngine, opts listenerOptions, metrics *serverMetrics) (*server.Server, *reloadableHandler, *connLimitListener, error) {
+	handler := newReloadableHandler(engine)
+
+	netListener, err := net.Listen(cfg.Protocol, cfg.Address)
+
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	l := newConnLimitListener(netListener, cfg.MaxConnections, func(conn *net.Conn) {
+		handler.currentEngine().gms.AddNetConnection(conn)
+	})
+
+	var vtHandler mysql.Handler = handler
+	if opts.requireSecureTransport {
+		vtHandler = secureTransportHandler{vtHandler}
+	}
+	if metrics != nil {
+		vtHandler = metricsHandler{vtHandler, metrics}
+	}
+
+	vtListener, err := mysql.NewListenerWithConfig(mysql.ListenerConfig{
+		Listener:           l,
+		AuthServer:         cfg.Auth.Mysql(),
+		Handler:            vtHandler,
+		ConnReadTimeout:    cfg.ConnReadTimeout,
+		ConnWriteTimeout:   cfg.ConnWriteTimeout,
+		ConnReadBufferSize: mysql.DefaultConnBufferSize,
+	})
+
+	if err != nil {
+		l.Close()
+		return nil, nil, nil, err
+	}
+
+	vtListener.TLSConfig = opts.tlsConfig
+	vtListener.RequireSecureTransport = opts.requireSecureTransport
+
+	return &server.Server{Listener: vtListener}, handler, l, nil
+}
+
+func newSessionBuilder(sqlEngine *sqle.Engine, username, email string, autocommit bool, queryStats *dsqle.QueryStats) server.SessionBuilder {
 	return func(ctx context.Context, conn *mysql.Conn, host string) (sql.Session, *sql.IndexRegistry, *sql.ViewRegistry, error) {
+		// branch databases are added to the session by the branchDatabaseHandler when the session uses them
+		var dbs []dsqle.Database
+		for _, db := range dbsAsDSQLDBs(sqlEngine.Catalog.AllDatabases()) {
+			if !db.IsBranchDatabase() {
+				dbs = append(dbs, db)
+			}
+		}
+
 		mysqlSess := sql.NewSession(host, conn.RemoteAddr().String(), conn.User, conn.ConnectionID)
-		doltSess, err := dsqle.NewDoltSession(ctx, mysqlSess, username, email, dbsAsDSQLDBs(sqlEngine.Catalog.AllDatabases())...)
+		doltSess, err := dsqle.NewDoltSession(ctx, mysqlSess, username, email, dbs...)
 
 		if err != nil {
 			return nil, nil, nil, err
 		}
 
+		doltSess.QueryStats = queryStats
+
 		err = doltSess.Set(ctx, sql.AutoCommitSessionVar, sql.Boolean, autocommit)
 
 		if err != nil {
@@ -178,7 +309,6 @@ func newSessionBuilder(sqlEngine *sqle.Engine, username, email string, autocommi
 			sql.WithViewRegistry(vr),
 			sql.WithSession(doltSess))
 
-		dbs := dbsAsDSQLDBs(sqlEngine.Catalog.AllDatabases())
 		for _, db := range dbs {
 			err := db.LoadRootFromRepoState(sqlCtx)
 			if err != nil {
@@ -203,7 +333,7 @@ func newSessionBuilder(sqlEngine *sqle.Engine, username, email string, autocommi
 }
 
 func newDatabase(name string, dEnv *env.DoltEnv) dsqle.Database {
-	return dsqle.NewDatabase(name, dEnv.DoltDB, dEnv.RepoState, dEnv.RepoStateWriter())
+	return dsqle.NewDatabaseFromEnv(name, dEnv)
 }
 
 func dbsAsDSQLDBs(dbs []sql.Database) []dsqle.Database {
@@ -15,8 +15,21 @@
 package sqlserver
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"database/sql"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/gocraft/dbr/v2"
@@ -24,10 +37,14 @@ import (
 	"github.com/stretchr/testify/require"
 	"golang.org/x/net/context"
 
+	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
 	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/typed/noms"
+	"github.com/dolthub/dolt/go/libraries/utils/earl"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/types"
 )
 
 type testPerson struct {
@@ -113,6 +130,9 @@ func TestServerBadArgs(t *testing.T) {
 		{"-P", "90000"},
 		{"-u", ""},
 		{"-l", "everything"},
+		{"--tls-key", "key.pem"},
+		{"--require-secure-transport"},
+		{"--tls-key", "missing.pem", "--tls-cert", "missing.pem"},
 	}
 
 	for _, test := range tests {
@@ -220,6 +240,376 @@ func TestServerSelect(t *testing.T) {
 	}
 }
 
+func TestServerMetrics(t *testing.T) {
+	const yamlConfig = `
+log_level: fatal
+
+listener:
+    host: localhost
+    port: 15400
+
+metrics:
+    host: localhost
+    port: 15401
+    labels:
+        instance: test
+`
+	serverController := CreateServerController()
+	go func() {
+		dEnv := createEnvWithSeedData(t)
+		dEnv.FS.WriteFile("config.yaml", []byte(yamlConfig))
+		startServer(context.Background(), "test", "dolt sql-server", []string{
+			"--config", "config.yaml",
+		}, dEnv, serverController)
+	}()
+	err := serverController.WaitForStart()
+	require.NoError(t, err)
+	defer func() {
+		serverController.StopServer()
+		err = serverController.WaitForClose()
+		assert.NoError(t, err)
+	}()
+
+	conn, err := dbr.Open("mysql", "root:@tcp(localhost:15400)/dolt", nil)
+	require.NoError(t, err)
+	var peoples []testPerson
+	_, err = conn.NewSession(nil).Select("*").From("people").LoadContext(context.Background(), &peoples)
+	require.NoError(t, err)
+	_, err = conn.Exec("select * from not_a_table")
+	require.Error(t, err)
+
+	resp, err := http.Get("http://localhost:15401/metrics")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(body), `dolt_sql_queries_total{instance="test"}`)
+	assert.Contains(t, string(body), `dolt_sql_query_errors_total{instance="test"} 1`)
+	assert.Contains(t, string(body), `dolt_sql_sessions{instance="test"} 1`)
+	assert.Contains(t, string(body), `dolt_sql_query_duration_seconds_bucket{instance="test",le="+Inf"}`)
+
+	err = conn.Close()
+	require.NoError(t, err)
+}
+
+func TestServerConcurrentTransactions(t *testing.T) {
+	env := createEnvWithSeedData(t)
+	serverConfig := DefaultServerConfig().withLogLevel(LogLevel_Fatal).withPort(15600).withMaxConnections(3)
+
+	sc := CreateServerController()
+	defer sc.StopServer()
+	go func() {
+		_, _ = Serve(context.Background(), "", serverConfig, sc, env)
+	}()
+	err := sc.WaitForStart()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	db, err := sql.Open("mysql", ConnectionString(serverConfig)+"dolt")
+	require.NoError(t, err)
+	defer db.Close()
+
+	conn1, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer conn1.Close()
+	_, err = conn1.ExecContext(ctx, "create table t (pk int primary key, v int)")
+	require.NoError(t, err)
+	_, err = conn1.ExecContext(ctx, "insert into t values (1, 1), (2, 2)")
+	require.NoError(t, err)
+
+	conn2, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer conn2.Close()
+	_, err = conn2.ExecContext(ctx, "select * from t")
+	require.NoError(t, err)
+
+	// each autocommit statement sees the changes committed before it started
+	_, err = conn1.ExecContext(ctx, "update t set v = 10 where pk = 1")
+	require.NoError(t, err)
+	var v int
+	err = conn2.QueryRowContext(ctx, "select v from t where pk = 1").Scan(&v)
+	require.NoError(t, err)
+	assert.Equal(t, 10, v)
+	_, err = conn2.ExecContext(ctx, "update t set v = 11 where pk = 1")
+	require.NoError(t, err)
+
+	_, err = conn1.ExecContext(ctx, "set autocommit = 0")
+	require.NoError(t, err)
+	_, err = conn2.ExecContext(ctx, "set autocommit = 0")
+	require.NoError(t, err)
+
+	// changes of overlapping transactions to different rows are merged
+	_, err = conn1.ExecContext(ctx, "update t set v = 12 where pk = 1")
+	require.NoError(t, err)
+	_, err = conn2.ExecContext(ctx, "update t set v = 20 where pk = 2")
+	require.NoError(t, err)
+	_, err = conn1.ExecContext(ctx, "commit")
+	require.NoError(t, err)
+	_, err = conn2.ExecContext(ctx, "commit")
+	require.NoError(t, err)
+
+	// conflicting changes of overlapping transactions to the same row fail the second transaction
+	_, err = conn1.ExecContext(ctx, "update t set v = 13 where pk = 1")
+	require.NoError(t, err)
+	_, err = conn2.ExecContext(ctx, "update t set v = 21 where pk = 1")
+	require.NoError(t, err)
+	_, err = conn1.ExecContext(ctx, "commit")
+	require.NoError(t, err)
+	_, err = conn2.ExecContext(ctx, "commit")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "serialization failure")
+
+	// the failed transaction is rolled back and can be retried
+	err = conn2.QueryRowContext(ctx, "select v from t where pk = 1").Scan(&v)
+	require.NoError(t, err)
+	assert.Equal(t, 13, v)
+	_, err = conn2.ExecContext(ctx, "update t set v = 21 where pk = 1")
+	require.NoError(t, err)
+	_, err = conn2.ExecContext(ctx, "commit")
+	require.NoError(t, err)
+
+	conn3, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer conn3.Close()
+	rows, err := conn3.QueryContext(ctx, "select pk, v from t order by pk")
+	require.NoError(t, err)
+	defer rows.Close()
+
+	var actual [][2]int
+	for rows.Next() {
+		var row [2]int
+		require.NoError(t, rows.Scan(&row[0], &row[1]))
+		actual = append(actual, row)
+	}
+	require.NoError(t, rows.Err())
+	assert.Equal(t, [][2]int{{1, 21}, {2, 20}}, actual)
+}
+
+func TestServerBranchSelection(t *testing.T) {
+	env := createEnvWithSeedData(t)
+	serverConfig := DefaultServerConfig().withLogLevel(LogLevel_Fatal).withPort(15700).withMaxConnections(5)
+
+	sc := CreateServerController()
+	defer sc.StopServer()
+	go func() {
+		_, _ = Serve(context.Background(), "", serverConfig, sc, env)
+	}()
+	err := sc.WaitForStart()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	openConn := func(dbName string) *sql.Conn {
+		db, err := sql.Open("mysql", ConnectionString(serverConfig)+dbName)
+		require.NoError(t, err)
+		conn, err := db.Conn(ctx)
+		require.NoError(t, err)
+		return conn
+	}
+	tables := func(conn *sql.Conn) []string {
+		rows, err := conn.QueryContext(ctx, "show tables")
+		require.NoError(t, err)
+		defer rows.Close()
+
+		var names []string
+		for rows.Next() {
+			var name string
+			require.NoError(t, rows.Scan(&name))
+			names = append(names, name)
+		}
+		require.NoError(t, rows.Err())
+		return names
+	}
+
+	master := openConn("dolt")
+	defer master.Close()
+	// a session can use the database of a branch created after it started
+	early := openConn("dolt")
+	defer early.Close()
+	_, err = master.ExecContext(ctx, "insert into dolt_branches (name, hash) values ('feature', @@dolt_head)")
+	require.NoError(t, err)
+
+	var headRef string
+	err = master.QueryRowContext(ctx, "select @@dolt_head_ref").Scan(&headRef)
+	require.NoError(t, err)
+	assert.Equal(t, "refs/heads/master", headRef)
+
+	// branches start from the head commit, which doesn't include the uncommitted people table
+	// a session using a branch's database works on that branch
+	feature := openConn("dolt")
+	defer feature.Close()
+	_, err = feature.ExecContext(ctx, "use `dolt/feature`")
+	require.NoError(t, err)
+	_, err = feature.ExecContext(ctx, "create table t (pk int primary key)")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"t"}, tables(feature))
+	assert.ElementsMatch(t, []string{"people"}, tables(master))
+
+	_, err = early.ExecContext(ctx, "use `dolt/feature`")
+	require.NoError(t, err)
+	assert.Empty(t, tables(early))
+
+	// the database of a deleted branch can't be used by new sessions
+	_, err = master.ExecContext(ctx, "insert into dolt_branches (name, hash) values ('deleted', @@dolt_head)")
+	require.NoError(t, err)
+	_, err = early.ExecContext(ctx, "use `dolt/deleted`")
+	require.NoError(t, err)
+	_, err = master.ExecContext(ctx, "delete from dolt_branches where name = 'deleted'")
+	require.NoError(t, err)
+	late := openConn("dolt")
+	defer late.Close()
+	_, err = late.ExecContext(ctx, "use `dolt/deleted`")
+	assert.Error(t, err)
+
+	// names of branch databases in strings and comments are not resolved, and unknown ones are reported by the engine
+	var str string
+	err = late.QueryRowContext(ctx, "select '`dolt/missing`' /* `dolt/missing` */").Scan(&str)
+	require.NoError(t, err)
+	assert.Equal(t, "`dolt/missing`", str)
+	_, err = late.ExecContext(ctx, "use `dolt/missing`")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "database not found: dolt/missing")
+
+	// a session can switch branches with @@dbname_head_ref
+	other := openConn("dolt")
+	defer other.Close()
+	_, err = other.ExecContext(ctx, "set @@dolt_head_ref = 'feature'")
+	require.NoError(t, err)
+	err = other.QueryRowContext(ctx, "select @@dolt_head_ref").Scan(&headRef)
+	require.NoError(t, err)
+	assert.Equal(t, "refs/heads/feature", headRef)
+	_, err = other.ExecContext(ctx, "create table u (pk int primary key)")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"u"}, tables(other))
+	assert.ElementsMatch(t, []string{"people"}, tables(master))
+
+	_, err = other.ExecContext(ctx, "set @@dolt_head_ref = 'refs/heads/master'")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"people"}, tables(other))
+
+	_, err = other.ExecContext(ctx, "set @@dolt_head_ref = 'not_a_branch'")
+	assert.Error(t, err)
+}
+
+func TestServerPrivileges(t *testing.T) {
+	const yamlConfig = `
+log_level: fatal
+
+listener:
+    host: localhost
+    port: 15800
+    max_connections: 100
+
+users:
+    - name: reader
+      password: pass
+      grants:
+          - SELECT ON dolt.people
+`
+	dEnv := createEnvWithSeedData(t)
+	serverController := CreateServerController()
+	go func() {
+		dEnv.FS.WriteFile("config.yaml", []byte(yamlConfig))
+		startServer(context.Background(), "test", "dolt sql-server", []string{
+			"--config", "config.yaml",
+		}, dEnv, serverController)
+	}()
+	err := serverController.WaitForStart()
+	require.NoError(t, err)
+	defer func() {
+		serverController.StopServer()
+		err = serverController.WaitForClose()
+		assert.NoError(t, err)
+	}()
+
+	ctx := context.Background()
+	openConn := func(user string) *sql.Conn {
+		db, err := sql.Open("mysql", user+"@tcp(localhost:15800)/dolt")
+		require.NoError(t, err)
+		conn, err := db.Conn(ctx)
+		require.NoError(t, err)
+		return conn
+	}
+	grants := func(conn *sql.Conn) []string {
+		rows, err := conn.QueryContext(ctx, "show grants")
+		require.NoError(t, err)
+		defer rows.Close()
+
+		var grants []string
+		for rows.Next() {
+			var grant string
+			require.NoError(t, rows.Scan(&grant))
+			grants = append(grants, grant)
+		}
+		require.NoError(t, rows.Err())
+		return grants
+	}
+
+	root := openConn("root:")
+	defer root.Close()
+	for _, query := range []string{
+		"create table other (pk int primary key)",
+		"insert into dolt_branches (name, hash) values ('feature', @@dolt_head)",
+		"create user dev identified by 'secret'",
+		"grant all on dolt.* to dev",
+		"revoke insert, update, delete on `dolt/master`.* from dev",
+	} {
+		_, err = root.ExecContext(ctx, query)
+		require.NoError(t, err, query)
+	}
+
+	// users defined by the config can only use the privileges it grants
+	reader := openConn("reader:pass")
+	defer reader.Close()
+	_, err = reader.ExecContext(ctx, "select * from people")
+	assert.NoError(t, err)
+	_, err = reader.ExecContext(ctx, "select * from other")
+	assert.EqualError(t, err, "Error 1142: SELECT command denied to user 'reader'@'%' for table 'dolt/master.other'")
+	_, err = reader.ExecContext(ctx, "insert into people (id) values (5)")
+	assert.Error(t, err)
+	_, err = reader.ExecContext(ctx, "create user other")
+	assert.Error(t, err)
+	_, err = reader.ExecContext(ctx, "select dolt_checkout('-b', 'reader_branch')")
+	assert.EqualError(t, err, "Error 1044: Access denied for user 'reader'@'%' to database 'dolt/master'")
+	_, err = reader.ExecContext(ctx, "select * from people where")
+	assert.Error(t, err)
+	_, err = reader.PrepareContext(ctx, "insert into people (id) values (?)")
+	assert.EqualError(t, err, "Error 1142: INSERT command denied to user 'reader'@'%' for table 'dolt/master.people'")
+	_, err = reader.PrepareContext(ctx, "show grants")
+	assert.EqualError(t, err, "Error 1295: This command is not supported in the prepared statement protocol yet")
+	assert.Equal(t, []string{"GRANT USAGE ON *.* TO `reader`@`%`", "GRANT SELECT ON `dolt`.`people` TO `reader`@`%`"}, grants(reader))
+
+	// users created by statements can write on every branch but master
+	dev := openConn("dev:secret")
+	defer dev.Close()
+	_, err = dev.ExecContext(ctx, "create table t (pk int primary key)")
+	assert.NoError(t, err)
+	_, err = dev.ExecContext(ctx, "insert into other values (1)")
+	assert.EqualError(t, err, "Error 1142: INSERT command denied to user 'dev'@'%' for table 'dolt/master.other'")
+	_, err = dev.ExecContext(ctx, "set @@dolt_head_ref = 'feature'")
+	require.NoError(t, err)
+	_, err = dev.ExecContext(ctx, "create table u (pk int primary key)")
+	assert.NoError(t, err)
+	_, err = dev.ExecContext(ctx, "insert into u values (1)")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"GRANT USAGE ON *.* TO `dev`@`%`",
+		"GRANT ALL PRIVILEGES ON `dolt`.* TO `dev`@`%`",
+		"REVOKE INSERT, UPDATE, DELETE ON `dolt/master`.* FROM `dev`@`%`",
+	}, grants(dev))
+
+	wrong, err := sql.Open("mysql", "dev:wrong@tcp(localhost:15800)/dolt")
+	require.NoError(t, err)
+	assert.Error(t, wrong.PingContext(ctx))
+
+	// users created by statements are persisted outside of the database
+	data, err := dEnv.FS.ReadFile(defaultPrivilegeFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"name": "dev"`)
+	assert.NotContains(t, string(data), "reader")
+}
+
 func createEnvWithSeedData(t *testing.T) *env.DoltEnv {
 	dEnv := dtestutils.CreateTestEnv()
 	imt, sch := dtestutils.CreateTestDataTable(true)
@@ -243,3 +633,306 @@ func createEnvWithSeedData(t *testing.T) *env.DoltEnv {
 
 	return dEnv
 }
+
+func TestServerTLS(t *testing.T) {
+	const yamlConfig = `
+log_level: fatal
+
+listener:
+    host: localhost
+    port: 15900
+    max_connections: 100
+    tls_key: key.pem
+    tls_cert: cert.pem
+    require_secure_transport: true
+`
+	dEnv := createEnvWithSeedData(t)
+	keyPEM, certPEM := generateTestCertificate(t)
+	require.NoError(t, dEnv.FS.WriteFile("key.pem", keyPEM))
+	require.NoError(t, dEnv.FS.WriteFile("cert.pem", certPEM))
+	require.NoError(t, dEnv.FS.WriteFile("config.yaml", []byte(yamlConfig)))
+
+	serverController := CreateServerController()
+	go func() {
+		startServer(context.Background(), "test", "dolt sql-server", []string{
+			"--config", "config.yaml",
+		}, dEnv, serverController)
+	}()
+	err := serverController.WaitForStart()
+	require.NoError(t, err)
+	defer func() {
+		serverController.StopServer()
+		err = serverController.WaitForClose()
+		assert.NoError(t, err)
+	}()
+
+	secure, err := sql.Open("mysql", "root:@tcp(localhost:15900)/dolt?tls=skip-verify")
+	require.NoError(t, err)
+	defer secure.Close()
+
+	var name string
+	err = secure.QueryRow("select name from people where name = 'Bill Billerson'").Scan(&name)
+	require.NoError(t, err)
+	assert.Equal(t, "Bill Billerson", name)
+
+	insecure, err := sql.Open("mysql", "root:@tcp(localhost:15900)/dolt")
+	require.NoError(t, err)
+	defer insecure.Close()
+	assert.Error(t, insecure.Ping())
+}
+
+// generateTestCertificate returns a PEM encoded private key and a self-signed certificate for localhost.
+func generateTestCertificate(t *testing.T) (keyPEM, certPEM []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	return keyPEM, certPEM
+}
+
+func TestServerReload(t *testing.T) {
+	const yamlConfig = `
+log_level: fatal
+
+listener:
+    host: localhost
+    port: 15920
+    max_connections: 1
+`
+	dEnv := createEnvWithSeedData(t)
+	require.NoError(t, dEnv.FS.WriteFile("config.yaml", []byte(yamlConfig)))
+
+	otherDir, err := ioutil.TempDir("", "reload")
+	require.NoError(t, err)
+	defer os.RemoveAll(otherDir)
+	otherFS, err := filesys.LocalFilesysWithWorkingDir(otherDir)
+	require.NoError(t, err)
+	otherURL := earl.FileUrlFromPath(filepath.Join(otherDir, dbfactory.DoltDataDir), os.PathSeparator)
+	otherEnv := env.Load(context.Background(), env.GetCurrentUserHomeDir, otherFS, otherURL, "test")
+	require.NoError(t, otherEnv.InitRepo(context.Background(), types.Format_Default, "Bill Billerson", "bigbillieb@fake.horse"))
+
+	serverController := CreateServerController()
+	go func() {
+		startServer(context.Background(), "test", "dolt sql-server", []string{
+			"--config", "config.yaml",
+		}, dEnv, serverController)
+	}()
+	err = serverController.WaitForStart()
+	require.NoError(t, err)
+	defer func() {
+		serverController.StopServer()
+		err = serverController.WaitForClose()
+		assert.NoError(t, err)
+	}()
+
+	ctx := context.Background()
+	db, err := sql.Open("mysql", "root:@tcp(localhost:15920)/")
+	require.NoError(t, err)
+	defer db.Close()
+
+	before, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer before.Close()
+
+	reloaded := yamlConfig + `
+    read_timeout_millis: 1
+
+databases:
+    - name: other
+      path: ` + otherDir + `
+`
+	reloaded = strings.Replace(reloaded, "max_connections: 1", "max_connections: 2", 1)
+	cfg, err := getYAMLServerConfig(dEnv.FS, "config.yaml")
+	require.NoError(t, err)
+	require.NoError(t, dEnv.FS.WriteFile("config.yaml", []byte(reloaded)))
+	reloadedCfg, err := getYAMLServerConfig(dEnv.FS, "config.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"listener.read_timeout_millis"}, restartRequiredChanges(cfg, reloadedCfg))
+	require.NoError(t, serverController.ReloadConfig(reloadedCfg))
+
+	// the connection opened before the reload keeps serving the old databases, and new connections serve the new ones
+	var count int
+	require.NoError(t, before.QueryRowContext(ctx, "select count(*) from dolt.people").Scan(&count))
+	assert.Equal(t, 3, count)
+
+	after, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer after.Close()
+
+	rows, err := after.QueryContext(ctx, "show databases")
+	require.NoError(t, err)
+	var dbNames []string
+	for rows.Next() {
+		var dbName string
+		require.NoError(t, rows.Scan(&dbName))
+		dbNames = append(dbNames, dbName)
+	}
+	require.NoError(t, rows.Close())
+	assert.Contains(t, dbNames, "other")
+	assert.NotContains(t, dbNames, "dolt")
+
+	// an invalid config is rejected without changing the server
+	require.NoError(t, dEnv.FS.WriteFile("config.yaml", []byte(strings.Replace(reloaded, otherDir, otherDir+"/missing", 1))))
+	invalidCfg, err := getYAMLServerConfig(dEnv.FS, "config.yaml")
+	require.NoError(t, err)
+	assert.Error(t, serverController.ReloadConfig(invalidCfg))
+
+	_, err = after.ExecContext(ctx, "use other")
+	assert.NoError(t, err)
+}
+
+func TestChangedPinnedDatabase(t *testing.T) {
+	dbs := []env.EnvNameAndPath{{Name: "a", Path: "/a"}, {Name: "b", Path: "/b"}}
+	assert.Equal(t, "", changedPinnedDatabase(nil, dbs, nil))
+	assert.Equal(t, "", changedPinnedDatabase([]string{"a"}, dbs, []env.EnvNameAndPath{{Name: "a", Path: "/a"}}))
+	assert.Equal(t, "", changedPinnedDatabase([]string{"a"}, nil, nil))
+	assert.Equal(t, "a", changedPinnedDatabase([]string{"a"}, dbs, []env.EnvNameAndPath{{Name: "a", Path: "/other"}}))
+	assert.Equal(t, "b", changedPinnedDatabase([]string{"a", "b"}, dbs, dbs[:1]))
+	assert.Equal(t, "a", changedPinnedDatabase([]string{"a"}, nil, dbs))
+}
+
+func TestServerQueryStats(t *testing.T) {
+	env := createEnvWithSeedData(t)
+	serverConfig := DefaultServerConfig().withLogLevel(LogLevel_Fatal).withPort(15940)
+
+	sc := CreateServerController()
+	go func() {
+		_, _ = Serve(context.Background(), "", serverConfig, sc, env)
+	}()
+	err := sc.WaitForStart()
+	require.NoError(t, err)
+	defer func() {
+		sc.StopServer()
+		err = sc.WaitForClose()
+		assert.NoError(t, err)
+	}()
+
+	ctx := context.Background()
+	db, err := sql.Open("mysql", ConnectionString(serverConfig)+"dolt")
+	require.NoError(t, err)
+	defer db.Close()
+	conn, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	for _, query := range []string{"select * from people where age = 32", "select * from people where age = 25"} {
+		rows, err := conn.QueryContext(ctx, query)
+		require.NoError(t, err)
+		require.NoError(t, rows.Close())
+	}
+
+	var count, rowsSent, rowsExamined int
+	err = conn.QueryRowContext(ctx, "select count, rows_sent, rows_examined from dolt_query_stats where `database` = 'dolt' and fingerprint = 'select * from people where age = ?'").Scan(&count, &rowsSent, &rowsExamined)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, 2, rowsSent)
+	assert.Equal(t, 6, rowsExamined)
+}
+
+func TestServerExplainAnalyze(t *testing.T) {
+	env := createEnvWithSeedData(t)
+	serverConfig := DefaultServerConfig().withLogLevel(LogLevel_Fatal).withPort(15941)
+
+	sc := CreateServerController()
+	go func() {
+		_, _ = Serve(context.Background(), "", serverConfig, sc, env)
+	}()
+	err := sc.WaitForStart()
+	require.NoError(t, err)
+	defer func() {
+		sc.StopServer()
+		err = sc.WaitForClose()
+		assert.NoError(t, err)
+	}()
+
+	db, err := sql.Open("mysql", ConnectionString(serverConfig)+"dolt")
+	require.NoError(t, err)
+	defer db.Close()
+
+	rows, err := db.Query("explain analyze select * from people where age = 32")
+	require.NoError(t, err)
+	defer rows.Close()
+
+	var plan []string
+	for rows.Next() {
+		var line string
+		require.NoError(t, rows.Scan(&line))
+		plan = append(plan, line)
+	}
+	require.NoError(t, rows.Err())
+
+	// the plan may be run in parallel, depending on the number of CPUs
+	planStr := strings.Join(plan, "\n")
+	assert.Regexp(t, `Filter\(people.age = 32\) \(actual time=[0-9.]+ms rows=1 loops=\d+\)`, planStr)
+	assert.Regexp(t, `Table\(people\) \(actual time=[0-9.]+ms rows=3 loops=1\) \[full scan\]`, planStr)
+}
+
+func TestServerCheckDDLFailure(t *testing.T) {
+	env := createEnvWithSeedData(t)
+	serverConfig := DefaultServerConfig().withLogLevel(LogLevel_Fatal).withPort(15942).withMaxConnections(3)
+
+	sc := CreateServerController()
+	go func() {
+		_, _ = Serve(context.Background(), "", serverConfig, sc, env)
+	}()
+	err := sc.WaitForStart()
+	require.NoError(t, err)
+	defer func() {
+		sc.StopServer()
+		err = sc.WaitForClose()
+		assert.NoError(t, err)
+	}()
+
+	ctx := context.Background()
+	db, err := sql.Open("mysql", ConnectionString(serverConfig)+"dolt")
+	require.NoError(t, err)
+	defer db.Close()
+
+	conn1, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer conn1.Close()
+	conn2, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer conn2.Close()
+
+	_, err = conn1.ExecContext(ctx, "create table t (pk int primary key, v int)")
+	require.NoError(t, err)
+	_, err = conn1.ExecContext(ctx, "insert into t values (1, 0)")
+	require.NoError(t, err)
+
+	// the table is created by the engine before its check is found to be invalid, and isn't committed
+	_, err = conn1.ExecContext(ctx, "create table t2 (pk int primary key check (missing > 0))")
+	assert.Error(t, err)
+	_, err = conn2.ExecContext(ctx, "select * from t2")
+	assert.Error(t, err)
+
+	// the first check is added before the second is found to be violated, and neither is committed
+	_, err = conn1.ExecContext(ctx, "alter table t add check (pk > 0), add check (v > 0)")
+	assert.Error(t, err)
+
+	// statements are still committed once they are complete
+	_, err = conn1.ExecContext(ctx, "alter table t add check (pk < 10)")
+	require.NoError(t, err)
+
+	var tableName, createTable string
+	err = conn2.QueryRowContext(ctx, "show create table t").Scan(&tableName, &createTable)
+	require.NoError(t, err)
+	assert.NotContains(t, createTable, "pk > 0")
+	assert.Contains(t, createTable, "CHECK (pk < 10)")
+}
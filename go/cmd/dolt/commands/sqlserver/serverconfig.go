@@ -19,6 +19,7 @@ import (
 	"net"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/privileges"
 )
 
 // LogLevel defines the available levels of logging for the server.
@@ -46,6 +47,17 @@ const (
 	defaultAutoCommit       = true
 	defaultMaxConnections   = 1
 	defaultQueryParallelism = 2
+	defaultSlowQueryThresh  = 0
+	defaultMetricsHost      = "localhost"
+	defaultMetricsPort      = -1
+	defaultPrivilegeFile    = ".doltcfg/privileges.json"
+	defaultRequireSecure    = false
+	defaultReplicaRemote    = "origin"
+	defaultReplicaInterval  = 5 * 1000
+	defaultPushRemote       = "origin"
+	defaultPushMaxRetries   = 5
+	defaultPushQueueSize    = 100
+	defaultPushRetryDelay   = 1000
 )
 
 // String returns the string representation of the log level.
@@ -96,6 +108,119 @@ type ServerConfig interface {
 	MaxConnections() uint64
 	// QueryParallelism returns the parallelism that should be used by the go-mysql-server analyzer
 	QueryParallelism() int
+	// SlowQueryThreshold returns the number of milliseconds a query must run for to be logged as a slow query.  If it
+	// is 0 slow queries are not logged.
+	SlowQueryThreshold() uint64
+	// MetricsLabels returns the labels added to every metric served by the metrics listener
+	MetricsLabels() map[string]string
+	// MetricsHost returns the domain that the metrics listener will run on.
+	MetricsHost() string
+	// MetricsPort returns the port that the metrics listener will run on.  If it is -1 metrics are not served.
+	MetricsPort() int
+	// Users returns the user accounts defined in addition to User.  Like User, they can't be altered by SQL statements.
+	Users() []UserAccount
+	// PrivilegeFilePath returns the path of the file which persists the users created by SQL statements.
+	PrivilegeFilePath() string
+	// TLSKey returns the path of the PEM encoded private key used for TLS connections.  If it is empty the server
+	// doesn't support TLS.
+	TLSKey() string
+	// TLSCert returns the path of the PEM encoded certificate chain used for TLS connections.
+	TLSCert() string
+	// RequireSecureTransport returns whether the server rejects connections which don't use TLS.
+	RequireSecureTransport() bool
+	// Replicas returns the databases which are read replicas following a branch of one of their remotes.
+	Replicas() []ReplicaConfig
+	// PushOnCommit returns the databases whose branches are pushed to remotes whenever they move.
+	PushOnCommit() []PushOnCommitConfig
+}
+
+// UserAccount is a user account defined by the server config, and the privileges granted to it
+type UserAccount struct {
+	Name     string `yaml:"name"`
+	Password string `yaml:"password"`
+	// Grants are the privileges of the user as they follow GRANT in a GRANT statement, like "SELECT ON mydb.*"
+	Grants []string `yaml:"grants"`
+}
+
+// ReplicaConfig makes a database of the server a read replica, which periodically pulls a branch of one of its remotes
+// and rejects writes
+type ReplicaConfig struct {
+	Database string `yaml:"database"`
+	// Remote is the name of the remote which is followed.  The default is origin
+	Remote string `yaml:"remote"`
+	// Branch is the branch which is followed.  The default is the branch checked out in the database
+	Branch string `yaml:"branch"`
+	// IntervalMillis is the number of milliseconds between pulls.  The default is 5000
+	IntervalMillis uint64 `yaml:"interval_millis"`
+}
+
+// RemoteName returns the name of the remote which is followed.
+func (rc ReplicaConfig) RemoteName() string {
+	if rc.Remote == "" {
+		return defaultReplicaRemote
+	}
+
+	return rc.Remote
+}
+
+// Interval returns the number of milliseconds between pulls.
+func (rc ReplicaConfig) Interval() uint64 {
+	if rc.IntervalMillis == 0 {
+		return defaultReplicaInterval
+	}
+
+	return rc.IntervalMillis
+}
+
+// PushOnCommitConfig makes the server push the branches of a database to some of its remotes in the background
+// whenever they move
+type PushOnCommitConfig struct {
+	Database string `yaml:"database"`
+	// Remotes are the names of the remotes which are pushed to.  The default is origin
+	Remotes []string `yaml:"remotes"`
+	// MaxRetries is the number of times a failed push is retried.  The default is 5
+	MaxRetries *int `yaml:"max_retries"`
+	// QueueSize is the number of branches which can wait to be pushed to each remote.  The default is 100
+	QueueSize int `yaml:"queue_size"`
+	// RetryDelayMillis is the number of milliseconds before the first retry of a failed push, which doubles with
+	// every retry.  The default is 1000
+	RetryDelayMillis uint64 `yaml:"retry_delay_millis"`
+}
+
+// RemoteNames returns the names of the remotes which are pushed to.
+func (pc PushOnCommitConfig) RemoteNames() []string {
+	if len(pc.Remotes) == 0 {
+		return []string{defaultPushRemote}
+	}
+
+	return pc.Remotes
+}
+
+// Retries returns the number of times a failed push is retried.
+func (pc PushOnCommitConfig) Retries() int {
+	if pc.MaxRetries == nil {
+		return defaultPushMaxRetries
+	}
+
+	return *pc.MaxRetries
+}
+
+// QueueLength returns the number of branches which can wait to be pushed to each remote.
+func (pc PushOnCommitConfig) QueueLength() int {
+	if pc.QueueSize == 0 {
+		return defaultPushQueueSize
+	}
+
+	return pc.QueueSize
+}
+
+// RetryDelay returns the number of milliseconds before the first retry of a failed push.
+func (pc PushOnCommitConfig) RetryDelay() uint64 {
+	if pc.RetryDelayMillis == 0 {
+		return defaultPushRetryDelay
+	}
+
+	return pc.RetryDelayMillis
 }
 
 type commandLineServerConfig struct {
@@ -110,6 +235,9 @@ type commandLineServerConfig struct {
 	autoCommit       bool
 	maxConnections   uint64
 	queryParallelism int
+	tlsKey           string
+	tlsCert          string
+	requireSecure    bool
 }
 
 // Host returns the domain that the server will run on. Accepts an IPv4 or IPv6 address, in addition to localhost.
@@ -167,6 +295,12 @@ func (cfg *commandLineServerConfig) QueryParallelism() int {
 	return cfg.queryParallelism
 }
 
+// SlowQueryThreshold returns the number of milliseconds a query must run for to be logged as a slow query.  Slow
+// queries can only be logged with a yaml config file.
+func (cfg *commandLineServerConfig) SlowQueryThreshold() uint64 {
+	return defaultSlowQueryThresh
+}
+
 // DatabaseNamesAndPaths returns an array of env.EnvNameAndPathObjects corresponding to the databases to be loaded in
 // a multiple db configuration. If nil is returned the server will look for a database in the current directory and
 // give it a name automatically.
@@ -174,6 +308,59 @@ func (cfg *commandLineServerConfig) DatabaseNamesAndPaths() []env.EnvNameAndPath
 	return cfg.dbNamesAndPaths
 }
 
+// MetricsLabels returns the labels added to every metric.  Metrics can only be configured with a yaml config file.
+func (cfg *commandLineServerConfig) MetricsLabels() map[string]string {
+	return nil
+}
+
+// MetricsHost returns the domain that the metrics listener will run on.
+func (cfg *commandLineServerConfig) MetricsHost() string {
+	return defaultMetricsHost
+}
+
+// MetricsPort returns the port that the metrics listener will run on.  Metrics are not served when the server is
+// configured on the command line.
+func (cfg *commandLineServerConfig) MetricsPort() int {
+	return defaultMetricsPort
+}
+
+// Users returns the user accounts defined in addition to User.  There are none when the server is configured on the
+// command line.
+func (cfg *commandLineServerConfig) Users() []UserAccount {
+	return nil
+}
+
+// PrivilegeFilePath returns the path of the file which persists the users created by SQL statements.
+func (cfg *commandLineServerConfig) PrivilegeFilePath() string {
+	return defaultPrivilegeFile
+}
+
+// TLSKey returns the path of the PEM encoded private key used for TLS connections.
+func (cfg *commandLineServerConfig) TLSKey() string {
+	return cfg.tlsKey
+}
+
+// TLSCert returns the path of the PEM encoded certificate chain used for TLS connections.
+func (cfg *commandLineServerConfig) TLSCert() string {
+	return cfg.tlsCert
+}
+
+// RequireSecureTransport returns whether the server rejects connections which don't use TLS.
+func (cfg *commandLineServerConfig) RequireSecureTransport() bool {
+	return cfg.requireSecure
+}
+
+// Replicas returns the databases which are read replicas.  Replicas can only be configured with a yaml config file.
+func (cfg *commandLineServerConfig) Replicas() []ReplicaConfig {
+	return nil
+}
+
+// PushOnCommit returns the databases which are pushed to remotes on commit.  They can only be configured with a yaml
+// config file.
+func (cfg *commandLineServerConfig) PushOnCommit() []PushOnCommitConfig {
+	return nil
+}
+
 // withHost updates the host and returns the called `*commandLineServerConfig`, which is useful for chaining calls.
 func (cfg *commandLineServerConfig) withHost(host string) *commandLineServerConfig {
 	cfg.host = host
@@ -229,6 +416,21 @@ func (cfg *commandLineServerConfig) withQueryParallelism(queryParallelism int) *
 	return cfg
 }
 
+// withTLS updates the paths of the TLS key and certificate and returns the called `*commandLineServerConfig`, which is
+// useful for chaining calls.
+func (cfg *commandLineServerConfig) withTLS(tlsKey, tlsCert string) *commandLineServerConfig {
+	cfg.tlsKey = tlsKey
+	cfg.tlsCert = tlsCert
+	return cfg
+}
+
+// withRequireSecureTransport updates the require secure transport flag and returns the called
+// `*commandLineServerConfig`, which is useful for chaining calls.
+func (cfg *commandLineServerConfig) withRequireSecureTransport(requireSecure bool) *commandLineServerConfig {
+	cfg.requireSecure = requireSecure
+	return cfg
+}
+
 func (cfg *commandLineServerConfig) withDBNamesAndPaths(dbNamesAndPaths []env.EnvNameAndPath) *commandLineServerConfig {
 	cfg.dbNamesAndPaths = dbNamesAndPaths
 	return cfg
@@ -247,6 +449,7 @@ func DefaultServerConfig() *commandLineServerConfig {
 		autoCommit:       defaultAutoCommit,
 		maxConnections:   defaultMaxConnections,
 		queryParallelism: defaultQueryParallelism,
+		requireSecure:    defaultRequireSecure,
 	}
 }
 
@@ -264,9 +467,67 @@ func ValidateConfig(config ServerConfig) error {
 	if len(config.User()) == 0 {
 		return fmt.Errorf("user cannot be empty")
 	}
+	userNames := map[string]bool{config.User(): true}
+	for _, user := range config.Users() {
+		if len(user.Name) == 0 {
+			return fmt.Errorf("users cannot have an empty name")
+		}
+		if userNames[user.Name] {
+			return fmt.Errorf("user %s is defined more than once", user.Name)
+		}
+		userNames[user.Name] = true
+		for _, grant := range user.Grants {
+			if _, err := privileges.ParseGrant(grant); err != nil {
+				return fmt.Errorf("invalid grant for user %s: %v", user.Name, err)
+			}
+		}
+	}
+	if (config.TLSKey() == "") != (config.TLSCert() == "") {
+		return fmt.Errorf("tls_key and tls_cert must both be provided")
+	}
+	if config.RequireSecureTransport() && config.TLSKey() == "" {
+		return fmt.Errorf("require_secure_transport requires tls_key and tls_cert to be provided")
+	}
+	replicaNames := map[string]bool{}
+	for _, replica := range config.Replicas() {
+		if len(replica.Database) == 0 {
+			return fmt.Errorf("replicas must name a database")
+		}
+		if replicaNames[replica.Database] {
+			return fmt.Errorf("database %s is replicated more than once", replica.Database)
+		}
+		replicaNames[replica.Database] = true
+	}
+	pushedNames := map[string]bool{}
+	for _, pushed := range config.PushOnCommit() {
+		if len(pushed.Database) == 0 {
+			return fmt.Errorf("push_on_commit entries must name a database")
+		}
+		if pushedNames[pushed.Database] {
+			return fmt.Errorf("database %s is in push_on_commit more than once", pushed.Database)
+		}
+		if pushed.Retries() < 0 {
+			return fmt.Errorf("max_retries of database %s must not be negative", pushed.Database)
+		}
+		if pushed.QueueLength() < 0 {
+			return fmt.Errorf("queue_size of database %s must be positive", pushed.Database)
+		}
+		pushedNames[pushed.Database] = true
+	}
 	if config.LogLevel().String() == "unknown" {
 		return fmt.Errorf("loglevel is invalid: %v\n", string(config.LogLevel()))
 	}
+	if config.MetricsPort() != defaultMetricsPort {
+		if config.MetricsHost() != "localhost" && net.ParseIP(config.MetricsHost()) == nil {
+			return fmt.Errorf("metrics address is not a valid IP: %v", config.MetricsHost())
+		}
+		if config.MetricsPort() < 1024 || config.MetricsPort() > 65535 {
+			return fmt.Errorf("metrics port is not in the range between 1024-65535: %v\n", config.MetricsPort())
+		}
+		if config.MetricsPort() == config.Port() {
+			return fmt.Errorf("metrics port cannot be the same as the listener port: %v\n", config.MetricsPort())
+		}
+	}
 	return nil
 }
 
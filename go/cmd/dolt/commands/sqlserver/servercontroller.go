@@ -15,9 +15,12 @@
 package sqlserver
 
 import (
+	"errors"
 	"sync"
 )
 
+var ErrServerNotRunning = errors.New("the server is not running")
+
 type ServerController struct {
 	//serverClosed    *sync.WaitGroup
 	//serverStarted   *sync.WaitGroup
@@ -27,6 +30,7 @@ type ServerController struct {
 	closeRegistered *sync.Once
 	stopRegistered  *sync.Once
 	closeFunction   func() error
+	reloadFunction  func(ServerConfig) error
 	startError      error
 	closeError      error
 }
@@ -55,6 +59,12 @@ func (controller *ServerController) registerCloseFunction(startError error, clos
 	})
 }
 
+// registerReloadFunction is called within `Serve` before the server starts to associate the reload function with
+// future `ReloadConfig` calls.
+func (controller *ServerController) registerReloadFunction(reloadFunc func(ServerConfig) error) {
+	controller.reloadFunction = reloadFunc
+}
+
 // serverStopped is called within `Serve` to signal that the server has stopped and set the exit code.
 // Only the first call will register and unblock, thus it is safe to be called multiple times.
 func (controller *ServerController) serverStopped(closeError error) {
@@ -99,3 +109,23 @@ func (controller *ServerController) WaitForStart() error {
 
 	return controller.startError
 }
+
+// ReloadConfig applies |cfg| to the running server, as described by serverReloader.  It blocks until the server has
+// started, and returns an error if the server failed to start or has stopped.
+func (controller *ServerController) ReloadConfig(cfg ServerConfig) error {
+	if err := controller.WaitForStart(); err != nil {
+		return err
+	}
+
+	select {
+	case <-controller.closeCh:
+		return ErrServerNotRunning
+	default:
+	}
+
+	if controller.reloadFunction == nil {
+		return ErrServerNotRunning
+	}
+
+	return controller.reloadFunction(cfg)
+}
@@ -43,6 +43,9 @@ const (
 	noAutoCommitFlag     = "no-auto-commit"
 	configFileFlag       = "config"
 	queryParallelismFlag = "query-parallelism"
+	tlsKeyFlag           = "tls-key"
+	tlsCertFlag          = "tls-cert"
+	requireSecureFlag    = "require-secure-transport"
 )
 
 var sqlServerDocs = cli.CommandDocumentationContent{
@@ -64,9 +67,19 @@ SUPPORTED CONFIG FILE FIELDS:
 
 		{{.EmphasisLeft}}behavior.autocommit{{.EmphasisRight}} - If true write queries will automatically alter the working set. When working with autocommit enabled it is highly recommended that listener.max_connections be set to 1 as concurrency issues will arise otherwise
 
-		{{.EmphasisLeft}}user.name{{.EmphasisRight}} - The username that connections should use for authentication
+		{{.EmphasisLeft}}user.name{{.EmphasisRight}} - The username of the superuser, which holds every privilege and can create other users
 
-		{{.EmphasisLeft}}user.password{{.EmphasisRight}} - The password that connections should use for authentication.
+		{{.EmphasisLeft}}user.password{{.EmphasisRight}} - The password of the superuser.
+
+		{{.EmphasisLeft}}users{{.EmphasisRight}} - a list of additional user accounts. Like the superuser, they can't be altered by SQL statements
+
+		{{.EmphasisLeft}}users[i].name{{.EmphasisRight}} - The username of the account
+
+		{{.EmphasisLeft}}users[i].password{{.EmphasisRight}} - The password of the account
+
+		{{.EmphasisLeft}}users[i].grants{{.EmphasisRight}} - The privileges of the account, as they follow GRANT in a GRANT statement, e.g. {{.EmphasisLeft}}SELECT, INSERT ON mydb.*{{.EmphasisRight}}
+
+		{{.EmphasisLeft}}privilege_file{{.EmphasisRight}} - The file that users created with {{.EmphasisLeft}}CREATE USER{{.EmphasisRight}}, and their privileges, are stored in. It is not versioned with any database
 
 		{{.EmphasisLeft}}listener.host{{.EmphasisRight}} - The host address that the server will run on.  This may be {{.EmphasisLeft}}localhost{{.EmphasisRight}} or an IPv4 or IPv6 address
 
@@ -78,18 +91,62 @@ SUPPORTED CONFIG FILE FIELDS:
 
 		{{.EmphasisLeft}}listener.write_timeout_millis{{.EmphasisRight}} - The number of milliseconds that the server will wait for a write operation
 
+		{{.EmphasisLeft}}listener.tls_key{{.EmphasisRight}} - The path of an unencrypted private key in PEM format. When it and {{.EmphasisLeft}}listener.tls_cert{{.EmphasisRight}} are provided clients can connect using TLS
+
+		{{.EmphasisLeft}}listener.tls_cert{{.EmphasisRight}} - The path of a certificate chain in PEM format, whose first certificate is the certificate of {{.EmphasisLeft}}listener.tls_key{{.EmphasisRight}}
+
+		{{.EmphasisLeft}}listener.require_secure_transport{{.EmphasisRight}} - If true connections which don't use TLS are rejected
+
 		{{.EmphasisLeft}}performance.query_parallelism{{.EmphasisRight}} - Amount of go routines spawned to process each query
 
+		{{.EmphasisLeft}}performance.slow_query_threshold_millis{{.EmphasisRight}} - The number of milliseconds a query must run for to be logged as a slow query, along with its latency and the number of rows it sent and examined. If not set slow queries are not logged
+
+		{{.EmphasisLeft}}metrics.host{{.EmphasisRight}} - The host address that the http listener serving metrics in the Prometheus format at {{.EmphasisLeft}}/metrics{{.EmphasisRight}} will run on.
+
+		{{.EmphasisLeft}}metrics.port{{.EmphasisRight}} - The port that metrics are served on.  If not set metrics are not served
+
+		{{.EmphasisLeft}}metrics.labels{{.EmphasisRight}} - A map of labels which are added to every metric
+
+		{{.EmphasisLeft}}replication{{.EmphasisRight}} - a list of databases which are read replicas. A replica periodically pulls a branch of one of its remotes, moves every session working on the branch to the pulled commit before its next query, and rejects writes
+
+		{{.EmphasisLeft}}replication[i].database{{.EmphasisRight}} - The name of the replicated database
+
+		{{.EmphasisLeft}}replication[i].remote{{.EmphasisRight}} - The remote which is pulled from. Defaults to {{.EmphasisLeft}}origin{{.EmphasisRight}}
+
+		{{.EmphasisLeft}}replication[i].branch{{.EmphasisRight}} - The branch which is followed. Defaults to the branch checked out in the database, whose working set is replaced with every pull
+
+		{{.EmphasisLeft}}replication[i].interval_millis{{.EmphasisRight}} - The number of milliseconds between pulls. Defaults to 5000
+
+		{{.EmphasisLeft}}push_on_commit{{.EmphasisRight}} - a list of databases whose branches are pushed to remotes in the background whenever they move, such as when a commit is added to a branch through {{.EmphasisLeft}}dolt_branches{{.EmphasisRight}} or a merge. The state of the latest push of every branch is shown by the {{.EmphasisLeft}}dolt_push_status{{.EmphasisRight}} system table
+
+		{{.EmphasisLeft}}push_on_commit[i].database{{.EmphasisRight}} - The name of the pushed database
+
+		{{.EmphasisLeft}}push_on_commit[i].remotes{{.EmphasisRight}} - The remotes which are pushed to. Defaults to {{.EmphasisLeft}}[origin]{{.EmphasisRight}}
+
+		{{.EmphasisLeft}}push_on_commit[i].max_retries{{.EmphasisRight}} - The number of times a failed push is retried. Defaults to 5
+
+		{{.EmphasisLeft}}push_on_commit[i].retry_delay_millis{{.EmphasisRight}} - The number of milliseconds before the first retry of a failed push, which doubles with every retry. Defaults to 1000
+
+		{{.EmphasisLeft}}push_on_commit[i].queue_size{{.EmphasisRight}} - The number of branches which can wait to be pushed to each remote. Pushes of other branches are dropped while the queue is full. Defaults to 100
+
 		{{.EmphasisLeft}}databases{{.EmphasisRight}} - a list of dolt data repositories to make available as SQL databases. If databases is missing or empty then the working directory must be a valid dolt data repository which will be made available as a SQL database
 		
 		{{.EmphasisLeft}}databases[i].path{{.EmphasisRight}} - A path to a dolt data repository
 		
 		{{.EmphasisLeft}}databases[i].name{{.EmphasisRight}} - The name that the database corresponding to the given path should be referenced via SQL
 
-If a config file is not provided many of these settings may be configured on the command line.`,
+When the server is started with a config file, sending it {{.EmphasisLeft}}SIGHUP{{.EmphasisRight}} reloads the file without dropping any client. The log level, {{.EmphasisLeft}}listener.max_connections{{.EmphasisRight}}, {{.EmphasisLeft}}behavior.autocommit{{.EmphasisRight}}, {{.EmphasisLeft}}performance.query_parallelism{{.EmphasisRight}}, {{.EmphasisLeft}}performance.slow_query_threshold_millis{{.EmphasisRight}} and {{.EmphasisLeft}}databases{{.EmphasisRight}} are reloaded. Connections opened before the reload keep the databases and settings they started with, and lowering the maximum number of connections doesn't close any of them. Changes to other settings require a restart, as do changes of {{.EmphasisLeft}}databases{{.EmphasisRight}} which move or remove a database with replicas or push on commit remotes. If the file is invalid the server keeps its current configuration.
+
+The server keeps statistics of the queries it runs, grouped by their current database and their fingerprint, which is the query with its literals replaced by {{.EmphasisLeft}}?{{.EmphasisRight}}. The {{.EmphasisLeft}}dolt_query_stats{{.EmphasisRight}} system table shows the number of queries and errors, their total, average and maximum latency, and the number of rows they sent and examined, from the highest total latency to the lowest. The statistics of the least recently seen queries are dropped once there are 1000 fingerprints. The plan of a slow query, with the time spent and the rows returned by each of its nodes, is shown by {{.EmphasisLeft}}EXPLAIN ANALYZE <select statement>{{.EmphasisRight}}. The chunks read by a node include those read by the queries running concurrently.
+
+If a config file is not provided many of these settings may be configured on the command line.
+
+Each session works on the branch checked out in the repository unless it selects another one. A branch can be selected by using the database {{.EmphasisLeft}}<database>/<branch>{{.EmphasisRight}}, e.g. {{.EmphasisLeft}}USE ` + "`mydb/feature`" + `{{.EmphasisRight}}, or by setting the session variable {{.EmphasisLeft}}@@<database>_head_ref{{.EmphasisRight}}, e.g. {{.EmphasisLeft}}SET @@mydb_head_ref = 'feature'{{.EmphasisRight}}. Changes made on a branch which isn't checked out are only visible to the session which made them until they are committed.
+
+Users are managed with {{.EmphasisLeft}}CREATE USER{{.EmphasisRight}}, {{.EmphasisLeft}}ALTER USER{{.EmphasisRight}}, {{.EmphasisLeft}}DROP USER{{.EmphasisRight}}, {{.EmphasisLeft}}GRANT{{.EmphasisRight}}, {{.EmphasisLeft}}REVOKE{{.EmphasisRight}} and {{.EmphasisLeft}}SHOW GRANTS{{.EmphasisRight}}. Privileges can be granted on all databases, on a database, on a branch of a database using its {{.EmphasisLeft}}<database>/<branch>{{.EmphasisRight}} name, or on a table. Database names may contain the wildcards {{.EmphasisLeft}}%{{.EmphasisRight}} and {{.EmphasisLeft}}_{{.EmphasisRight}}. Revoking a privilege from a branch of a database it is granted on restricts it on that branch only, e.g. {{.EmphasisLeft}}GRANT ALL ON mydb.* TO dev{{.EmphasisRight}} followed by {{.EmphasisLeft}}REVOKE INSERT, UPDATE, DELETE ON ` + "`mydb/main`" + `.* FROM dev{{.EmphasisRight}} lets dev write to every branch but main.`,
 	Synopsis: []string{
 		"--config {{.LessThan}}file{{.GreaterThan}}",
-		"[-H {{.LessThan}}host{{.GreaterThan}}] [-P {{.LessThan}}port{{.GreaterThan}}] [-u {{.LessThan}}user{{.GreaterThan}}] [-p {{.LessThan}}password{{.GreaterThan}}] [-t {{.LessThan}}timeout{{.GreaterThan}}] [-l {{.LessThan}}loglevel{{.GreaterThan}}] [--multi-db-dir {{.LessThan}}directory{{.GreaterThan}}] [--query-parallelism {{.LessThan}}num-go-routines{{.GreaterThan}}] [-r]",
+		"[-H {{.LessThan}}host{{.GreaterThan}}] [-P {{.LessThan}}port{{.GreaterThan}}] [-u {{.LessThan}}user{{.GreaterThan}}] [-p {{.LessThan}}password{{.GreaterThan}}] [-t {{.LessThan}}timeout{{.GreaterThan}}] [-l {{.LessThan}}loglevel{{.GreaterThan}}] [--multi-db-dir {{.LessThan}}directory{{.GreaterThan}}] [--query-parallelism {{.LessThan}}num-go-routines{{.GreaterThan}}] [--tls-key {{.LessThan}}file{{.GreaterThan}} --tls-cert {{.LessThan}}file{{.GreaterThan}} [--require-secure-transport]] [-r]",
 	},
 }
 
@@ -128,6 +185,9 @@ func (cmd SqlServerCmd) CreateArgParser() *argparser.ArgParser {
 	ap.SupportsString(multiDBDirFlag, "", "directory", "Defines a directory whose subdirectories should all be dolt data repositories accessible as independent databases.")
 	ap.SupportsFlag(noAutoCommitFlag, "", "When provided sessions will not automatically commit their changes to the working set. Anything not manually committed will be lost.")
 	ap.SupportsInt(queryParallelismFlag, "", "num-go-routines", fmt.Sprintf("Set the number of go routines spawned to handle each query (default `%d`)", serverConfig.QueryParallelism()))
+	ap.SupportsString(tlsKeyFlag, "", "file", "Defines the path of the unencrypted private key in PEM format used for TLS connections.")
+	ap.SupportsString(tlsCertFlag, "", "file", "Defines the path of the certificate chain in PEM format used for TLS connections.")
+	ap.SupportsFlag(requireSecureFlag, "", "When provided connections which don't use TLS are rejected.")
 	return ap
 }
 
@@ -169,6 +229,15 @@ func startServer(ctx context.Context, versionStr, commandStr string, args []stri
 
 	cli.PrintErrf("Starting server with Config %v\n", ConfigInfo(serverConfig))
 
+	if cfgFile, ok := apr.GetValue(configFileFlag); ok {
+		if serverController == nil {
+			serverController = CreateServerController()
+		}
+
+		stopReloading := reloadOnSignal(dEnv.FS, cfgFile, serverController)
+		defer stopReloading()
+	}
+
 	if startError, closeError := Serve(ctx, versionStr, serverConfig, serverController, dEnv); startError != nil || closeError != nil {
 		if startError != nil {
 			cli.PrintErrln(startError)
@@ -240,6 +309,11 @@ func getCommandLineServerConfig(dEnv *env.DoltEnv, apr *argparser.ArgParseResult
 		serverConfig.withQueryParallelism(queryParallelism)
 	}
 
+	tlsKey, _ := apr.GetValue(tlsKeyFlag)
+	tlsCert, _ := apr.GetValue(tlsCertFlag)
+	serverConfig.withTLS(tlsKey, tlsCert)
+	serverConfig.withRequireSecureTransport(apr.Contains(requireSecureFlag))
+
 	serverConfig.autoCommit = !apr.Contains(noAutoCommitFlag)
 	return serverConfig, nil
 }
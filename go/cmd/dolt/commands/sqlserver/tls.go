@@ -0,0 +1,105 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"crypto/tls"
+	"fmt"
+
+	"github.com/dolthub/vitess/go/mysql"
+	"github.com/dolthub/vitess/go/sqltypes"
+	querypb "github.com/dolthub/vitess/go/vt/proto/query"
+
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+)
+
+// erSecureTransportRequired is the MySQL error code returned to clients which don't use TLS when the server requires
+// secure transport.
+const erSecureTransportRequired = 3159
+
+// loadTLSConfig returns the TLS config of the listener of the server, or nil if |cfg| doesn't configure TLS.
+func loadTLSConfig(cfg ServerConfig, fs filesys.ReadableFS) (*tls.Config, error) {
+	if cfg.TLSKey() == "" && cfg.TLSCert() == "" {
+		return nil, nil
+	}
+
+	keyPEM, err := fs.ReadFile(cfg.TLSKey())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tls_key '%s'. error: %s", cfg.TLSKey(), err.Error())
+	}
+
+	certPEM, err := fs.ReadFile(cfg.TLSCert())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tls_cert '%s'. error: %s", cfg.TLSCert(), err.Error())
+	}
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the TLS key pair. error: %s", err.Error())
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+// secureTransportHandler is a mysql.Handler which rejects every command of connections which don't use TLS.  The
+// listener reports an error to such clients during the handshake when it requires secure transport, but it doesn't
+// close their connection.
+type secureTransportHandler struct {
+	mysql.Handler
+}
+
+func (h secureTransportHandler) ComInitDB(c *mysql.Conn, schemaName string) error {
+	if err := checkSecureTransport(c); err != nil {
+		return err
+	}
+
+	return h.Handler.ComInitDB(c, schemaName)
+}
+
+func (h secureTransportHandler) ComQuery(c *mysql.Conn, query string, callback func(*sqltypes.Result) error) error {
+	if err := checkSecureTransport(c); err != nil {
+		return err
+	}
+
+	return h.Handler.ComQuery(c, query, callback)
+}
+
+func (h secureTransportHandler) ComPrepare(c *mysql.Conn, query string) ([]*querypb.Field, error) {
+	if err := checkSecureTransport(c); err != nil {
+		return nil, err
+	}
+
+	return h.Handler.ComPrepare(c, query)
+}
+
+func (h secureTransportHandler) ComStmtExecute(c *mysql.Conn, prepare *mysql.PrepareData, callback func(*sqltypes.Result) error) error {
+	if err := checkSecureTransport(c); err != nil {
+		return err
+	}
+
+	return h.Handler.ComStmtExecute(c, prepare, callback)
+}
+
+func checkSecureTransport(c *mysql.Conn) error {
+	if c.Capabilities&mysql.CapabilityClientSSL == 0 {
+		return mysql.NewSQLError(erSecureTransportRequired, mysql.SSUnknownSQLState,
+			"Connections using insecure transport are prohibited while --require_secure_transport=ON.")
+	}
+
+	return nil
+}
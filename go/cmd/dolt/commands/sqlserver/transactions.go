@@ -0,0 +1,45 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"github.com/dolthub/go-mysql-server/server"
+	"github.com/dolthub/vitess/go/mysql"
+	"github.com/dolthub/vitess/go/sqltypes"
+
+	dsqle "github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+)
+
+// transactionHandler is a mysql.Handler which starts a new transaction from the repository's working set before each
+// query run outside of an open transaction, so that queries see the changes committed by other sessions before they
+// started.
+type transactionHandler struct {
+	mysql.Handler
+	sm *server.SessionManager
+}
+
+func (h transactionHandler) ComQuery(c *mysql.Conn, query string, callback func(*sqltypes.Result) error) error {
+	ctx, err := h.sm.NewContextWithQuery(c, query)
+	if err != nil {
+		return err
+	}
+
+	err = dsqle.DSessFromSess(ctx.Session).StartStatement(ctx, isSessionAutocommit(ctx))
+	if err != nil {
+		return err
+	}
+
+	return h.Handler.ComQuery(c, query, callback)
+}
@@ -28,6 +28,14 @@ func strPtr(s string) *string {
 	return &s
 }
 
+// nillableStrPtr returns nil if |s| is empty, and a pointer to |s| otherwise
+func nillableStrPtr(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
 func boolPtr(b bool) *bool {
 	return &b
 }
@@ -65,11 +73,25 @@ type ListenerYAMLConfig struct {
 	MaxConnections     *uint64 `yaml:"max_connections"`
 	ReadTimeoutMillis  *uint64 `yaml:"read_timeout_millis"`
 	WriteTimeoutMillis *uint64 `yaml:"write_timeout_millis"`
+	// TLSKey is a file system path to an unencrypted private TLS key in PEM format.
+	TLSKey *string `yaml:"tls_key"`
+	// TLSCert is a file system path to a TLS certificate chain in PEM format.
+	TLSCert *string `yaml:"tls_cert"`
+	// RequireSecureTransport can enable a mode where non-TLS connections are turned away.
+	RequireSecureTransport *bool `yaml:"require_secure_transport"`
 }
 
 // PerformanceYAMLConfig contains configuration parameters for performance tweaking
 type PerformanceYAMLConfig struct {
-	QueryParallelism *int `yaml:"query_parallelism"`
+	QueryParallelism         *int    `yaml:"query_parallelism"`
+	SlowQueryThresholdMillis *uint64 `yaml:"slow_query_threshold_millis"`
+}
+
+// MetricsYAMLConfig contains the configuration of the http listener which serves metrics in the Prometheus format
+type MetricsYAMLConfig struct {
+	Labels map[string]string `yaml:"labels"`
+	Host   *string           `yaml:"host"`
+	Port   *int              `yaml:"port"`
 }
 
 // YAMLConfig is a ServerConfig implementation which is read from a yaml file
@@ -80,6 +102,11 @@ type YAMLConfig struct {
 	ListenerConfig    ListenerYAMLConfig    `yaml:"listener"`
 	DatabaseConfig    []DatabaseYAMLConfig  `yaml:"databases"`
 	PerformanceConfig PerformanceYAMLConfig `yaml:"performance"`
+	MetricsConfig     MetricsYAMLConfig     `yaml:"metrics"`
+	UsersConfig       []UserAccount         `yaml:"users"`
+	PrivilegeFileStr  *string               `yaml:"privilege_file"`
+	ReplicationConfig []ReplicaConfig       `yaml:"replication"`
+	PushConfig        []PushOnCommitConfig  `yaml:"push_on_commit"`
 }
 
 func serverConfigAsYAMLConfig(cfg ServerConfig) YAMLConfig {
@@ -93,8 +120,12 @@ func serverConfigAsYAMLConfig(cfg ServerConfig) YAMLConfig {
 			uint64Ptr(cfg.MaxConnections()),
 			uint64Ptr(cfg.ReadTimeout()),
 			uint64Ptr(cfg.WriteTimeout()),
+			nillableStrPtr(cfg.TLSKey()),
+			nillableStrPtr(cfg.TLSCert()),
+			boolPtr(cfg.RequireSecureTransport()),
 		},
-		DatabaseConfig: nil,
+		DatabaseConfig:   nil,
+		PrivilegeFileStr: strPtr(cfg.PrivilegeFilePath()),
 	}
 }
 
@@ -240,3 +271,87 @@ func (cfg YAMLConfig) QueryParallelism() int {
 
 	return *cfg.PerformanceConfig.QueryParallelism
 }
+
+// SlowQueryThreshold returns the number of milliseconds a query must run for to be logged as a slow query.  If it is
+// 0 slow queries are not logged.
+func (cfg YAMLConfig) SlowQueryThreshold() uint64 {
+	if cfg.PerformanceConfig.SlowQueryThresholdMillis == nil {
+		return defaultSlowQueryThresh
+	}
+
+	return *cfg.PerformanceConfig.SlowQueryThresholdMillis
+}
+
+// MetricsLabels returns the labels added to every metric served by the metrics listener
+func (cfg YAMLConfig) MetricsLabels() map[string]string {
+	return cfg.MetricsConfig.Labels
+}
+
+// MetricsHost returns the domain that the metrics listener will run on.
+func (cfg YAMLConfig) MetricsHost() string {
+	if cfg.MetricsConfig.Host == nil {
+		return defaultMetricsHost
+	}
+
+	return *cfg.MetricsConfig.Host
+}
+
+// MetricsPort returns the port that the metrics listener will run on.  If it is -1 metrics are not served.
+func (cfg YAMLConfig) MetricsPort() int {
+	if cfg.MetricsConfig.Port == nil {
+		return defaultMetricsPort
+	}
+
+	return *cfg.MetricsConfig.Port
+}
+
+// Users returns the user accounts defined in addition to User.
+func (cfg YAMLConfig) Users() []UserAccount {
+	return cfg.UsersConfig
+}
+
+// PrivilegeFilePath returns the path of the file which persists the users created by SQL statements.
+func (cfg YAMLConfig) PrivilegeFilePath() string {
+	if cfg.PrivilegeFileStr == nil {
+		return defaultPrivilegeFile
+	}
+
+	return *cfg.PrivilegeFileStr
+}
+
+// TLSKey returns the path of the PEM encoded private key used for TLS connections.
+func (cfg YAMLConfig) TLSKey() string {
+	if cfg.ListenerConfig.TLSKey == nil {
+		return ""
+	}
+
+	return *cfg.ListenerConfig.TLSKey
+}
+
+// TLSCert returns the path of the PEM encoded certificate chain used for TLS connections.
+func (cfg YAMLConfig) TLSCert() string {
+	if cfg.ListenerConfig.TLSCert == nil {
+		return ""
+	}
+
+	return *cfg.ListenerConfig.TLSCert
+}
+
+// RequireSecureTransport returns whether the server rejects connections which don't use TLS.
+func (cfg YAMLConfig) RequireSecureTransport() bool {
+	if cfg.ListenerConfig.RequireSecureTransport == nil {
+		return defaultRequireSecure
+	}
+
+	return *cfg.ListenerConfig.RequireSecureTransport
+}
+
+// Replicas returns the databases which are read replicas following a branch of one of their remotes.
+func (cfg YAMLConfig) Replicas() []ReplicaConfig {
+	return cfg.ReplicationConfig
+}
+
+// PushOnCommit returns the databases whose branches are pushed to remotes whenever they move.
+func (cfg YAMLConfig) PushOnCommit() []PushOnCommitConfig {
+	return cfg.PushConfig
+}
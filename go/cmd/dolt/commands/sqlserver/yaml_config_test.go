@@ -41,12 +41,41 @@ listener:
     max_connections: 1
     read_timeout_millis: 30000
     write_timeout_millis: 30000
+    tls_key: /etc/dolt/key.pem
+    tls_cert: /etc/dolt/cert.pem
+    require_secure_transport: true
+
+performance:
+    slow_query_threshold_millis: 250
     
 databases:
     - name: irs_soi
       path: ./datasets/irs-soi
     - name: noaa
       path: /Users/brian/datasets/noaa
+
+users:
+    - name: reader
+      password: pass
+      grants:
+          - SELECT ON irs_soi.*
+          - SELECT, INSERT ON noaa.*
+
+privilege_file: /etc/dolt/privileges.json
+
+replication:
+    - database: noaa
+      remote: upstream
+      branch: main
+      interval_millis: 60000
+    - database: irs_soi
+
+push_on_commit:
+    - database: irs_soi
+      remotes: [origin, backup]
+      max_retries: 0
+      queue_size: 10
+      retry_delay_millis: 500
 `
 
 	expected := serverConfigAsYAMLConfig(DefaultServerConfig())
@@ -60,6 +89,25 @@ databases:
 			Path: "/Users/brian/datasets/noaa",
 		},
 	}
+	expected.UsersConfig = []UserAccount{
+		{
+			Name:     "reader",
+			Password: "pass",
+			Grants:   []string{"SELECT ON irs_soi.*", "SELECT, INSERT ON noaa.*"},
+		},
+	}
+	expected.PrivilegeFileStr = strPtr("/etc/dolt/privileges.json")
+	expected.ReplicationConfig = []ReplicaConfig{
+		{Database: "noaa", Remote: "upstream", Branch: "main", IntervalMillis: 60000},
+		{Database: "irs_soi"},
+	}
+	expected.PushConfig = []PushOnCommitConfig{
+		{Database: "irs_soi", Remotes: []string{"origin", "backup"}, MaxRetries: intPtr(0), QueueSize: 10, RetryDelayMillis: 500},
+	}
+	expected.ListenerConfig.TLSKey = strPtr("/etc/dolt/key.pem")
+	expected.ListenerConfig.TLSCert = strPtr("/etc/dolt/cert.pem")
+	expected.ListenerConfig.RequireSecureTransport = boolPtr(true)
+	expected.PerformanceConfig.SlowQueryThresholdMillis = uint64Ptr(250)
 
 	config := YAMLConfig{}
 	err := yaml.Unmarshal([]byte(testStr), &config)
@@ -82,4 +130,39 @@ func TestYAMLConfigDefaults(t *testing.T) {
 	assert.Equal(t, defaultLogLevel, cfg.LogLevel())
 	assert.Equal(t, defaultAutoCommit, cfg.AutoCommit())
 	assert.Equal(t, uint64(defaultMaxConnections), cfg.MaxConnections())
+	assert.Equal(t, defaultMetricsHost, cfg.MetricsHost())
+	assert.Equal(t, defaultMetricsPort, cfg.MetricsPort())
+	assert.Nil(t, cfg.MetricsLabels())
+	assert.Nil(t, cfg.Users())
+	assert.Equal(t, defaultPrivilegeFile, cfg.PrivilegeFilePath())
+	assert.Equal(t, "", cfg.TLSKey())
+	assert.Equal(t, "", cfg.TLSCert())
+	assert.Equal(t, defaultRequireSecure, cfg.RequireSecureTransport())
+	assert.Nil(t, cfg.Replicas())
+	assert.Nil(t, cfg.PushOnCommit())
+	assert.Equal(t, uint64(defaultSlowQueryThresh), cfg.SlowQueryThreshold())
+}
+
+func TestReplicaConfigDefaults(t *testing.T) {
+	replica := ReplicaConfig{Database: "noaa"}
+	assert.Equal(t, defaultReplicaRemote, replica.RemoteName())
+	assert.Equal(t, uint64(defaultReplicaInterval), replica.Interval())
+
+	replica = ReplicaConfig{Database: "noaa", Remote: "upstream", IntervalMillis: 100}
+	assert.Equal(t, "upstream", replica.RemoteName())
+	assert.Equal(t, uint64(100), replica.Interval())
+}
+
+func TestPushOnCommitConfigDefaults(t *testing.T) {
+	pushed := PushOnCommitConfig{Database: "noaa"}
+	assert.Equal(t, []string{defaultPushRemote}, pushed.RemoteNames())
+	assert.Equal(t, defaultPushMaxRetries, pushed.Retries())
+	assert.Equal(t, defaultPushQueueSize, pushed.QueueLength())
+	assert.Equal(t, uint64(defaultPushRetryDelay), pushed.RetryDelay())
+
+	pushed = PushOnCommitConfig{Database: "noaa", Remotes: []string{"backup"}, MaxRetries: intPtr(0), QueueSize: 1, RetryDelayMillis: 10}
+	assert.Equal(t, []string{"backup"}, pushed.RemoteNames())
+	assert.Equal(t, 0, pushed.Retries())
+	assert.Equal(t, 1, pushed.QueueLength())
+	assert.Equal(t, uint64(10), pushed.RetryDelay())
 }
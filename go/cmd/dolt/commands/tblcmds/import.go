@@ -439,7 +439,8 @@ func newImportDataMover(ctx context.Context, root *doltdb.RootValue, fs filesys.
 	err = wrSch.GetPKCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
 		preImage := impOpts.nameMapper.PreImage(col.Name)
 		_, found := rd.GetSchema().GetAllCols().GetByName(preImage)
-		if !found {
+		// AUTO_INCREMENT values are generated as the rows are written
+		if !found && !col.AutoIncrement {
 			err = fmt.Errorf("input primary keys do not match primary keys of existing table")
 		}
 		return err == nil, err
@@ -449,7 +450,7 @@ func newImportDataMover(ctx context.Context, root *doltdb.RootValue, fs filesys.
 		return nil, &mvdata.DataMoverCreationError{ErrType: mvdata.SchemaErr, Cause: err}
 	}
 
-	transforms, err := mvdata.NameMapTransform(rd.GetSchema(), wrSch, impOpts.nameMapper)
+	transforms, err := mvdata.NameMapTransform(ctx, root.VRW(), rd.GetSchema(), wrSch, impOpts.nameMapper)
 
 	if err != nil {
 		return nil, &mvdata.DataMoverCreationError{ErrType: mvdata.CreateMapperErr, Cause: err}
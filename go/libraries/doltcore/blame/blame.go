@@ -0,0 +1,310 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package blame finds the commits which last modified the rows of a table, and each of their columns.
+package blame
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions/commitwalk"
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// Commit is a commit which modified a row.
+type Commit struct {
+	// Hash is the hash of the commit
+	Hash hash.Hash
+	// Meta is the metadata of the commit, with its author, timestamp and description
+	Meta *doltdb.CommitMeta
+}
+
+// RowBlame is the blame of a row of a table.
+type RowBlame struct {
+	// Row is the row, as it is in the commit the table was blamed at
+	Row row.Row
+	// Key is the primary key of the row
+	Key types.Tuple
+	// Commit is the commit which last modified the row
+	Commit *Commit
+	// Columns are the commits which last modified each of the row's non primary key columns, keyed by tag
+	Columns map[uint64]*Commit
+}
+
+// RowFilter returns whether the blame of a row should be found.
+type RowFilter func(r row.Row) (bool, error)
+
+// ForTable returns the schema of the table named at |commit|, and the blame of each of its rows accepted by |filter|,
+// which may be nil to blame every row, in primary key order.
+//
+// Blame is found by walking the commits in reverse topological order starting with |commit|, and comparing the
+// table in each commit with the table in its first parent.  A row, or a column of a row, is blamed on the first commit
+// found in which it changed.  A commit which creates the table or changes its schema changes every row.
+func ForTable(ctx context.Context, ddb *doltdb.DoltDB, commit *doltdb.Commit, tableName string, filter RowFilter) (schema.Schema, []*RowBlame, error) {
+	tbl, err := tableFromCommit(ctx, commit, tableName)
+	if err != nil {
+		return nil, nil, err
+	} else if tbl == nil {
+		return nil, nil, fmt.Errorf("no table named %s found", tableName)
+	}
+
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rowData, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nonPKTags := sch.GetNonPKCols().Tags
+	var blames []*RowBlame
+	err = rowData.IterAll(ctx, func(key, value types.Value) error {
+		r, err := row.FromNoms(sch, key.(types.Tuple), value.(types.Tuple))
+		if err != nil {
+			return err
+		}
+
+		if filter != nil {
+			ok, err := filter(r)
+			if err != nil || !ok {
+				return err
+			}
+		}
+
+		blames = append(blames, &RowBlame{Row: r, Key: key.(types.Tuple), Columns: make(map[uint64]*Commit, len(nonPKTags))})
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	h, err := commit.HashOf()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	itr, err := commitwalk.GetTopologicalOrderIterator(ctx, ddb, h)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pending := blames
+	for len(pending) > 0 {
+		h, cm, err := itr.Next(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, nil, err
+		}
+
+		pending, err = blameCommit(ctx, ddb, h, cm, tableName, nonPKTags, pending)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	for _, rb := range pending {
+		if rb.Commit == nil {
+			return nil, nil, fmt.Errorf("couldn't find blame for row with primary key %v", strings.Join(rb.KeyStrings(ctx), ", "))
+		}
+	}
+
+	return sch, blames, nil
+}
+
+// blameCommit blames the rows of |pending| which changed in the commit |cm|, and returns the rows which are still
+// waiting for blame.
+func blameCommit(ctx context.Context, ddb *doltdb.DoltDB, h hash.Hash, cm *doltdb.Commit, tableName string, nonPKTags []uint64, pending []*RowBlame) ([]*RowBlame, error) {
+	tbl, err := tableFromCommit(ctx, cm, tableName)
+	if err != nil || tbl == nil {
+		// the rows weren't modified by a commit without the table
+		return pending, err
+	}
+
+	var parentTbl *doltdb.Table
+	numParents, err := cm.NumParents()
+	if err != nil {
+		return nil, err
+	} else if numParents > 0 {
+		parent, err := ddb.ResolveParent(ctx, cm, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		parentTbl, err = tableFromCommit(ctx, parent, tableName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rowData, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// every row changed if the table was created, or if its schema changed
+	changedAll := parentTbl == nil
+	var parentData types.Map
+	if parentTbl != nil {
+		parentSch, err := parentTbl.GetSchema(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		eq, err := schema.SchemasAreEqual(parentSch, sch)
+		if err != nil {
+			return nil, err
+		}
+		changedAll = !eq
+
+		parentData, err = parentTbl.GetRowData(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if !changedAll && rowData.Equals(parentData) {
+			return pending, nil
+		}
+	}
+
+	meta, err := cm.GetCommitMeta()
+	if err != nil {
+		return nil, err
+	}
+	blamed := &Commit{Hash: h, Meta: meta}
+
+	var remaining []*RowBlame
+	for _, rb := range pending {
+		val, ok, err := rowData.MaybeGet(ctx, rb.Key)
+		if err != nil {
+			return nil, err
+		} else if !ok {
+			// the row was added by a commit merged later
+			remaining = append(remaining, rb)
+			continue
+		}
+
+		var parentVal types.Value
+		if !changedAll {
+			parentVal, ok, err = parentData.MaybeGet(ctx, rb.Key)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if changedAll || !ok {
+			rb.blameAll(blamed, nonPKTags)
+			continue
+		}
+
+		if val.Equals(parentVal) {
+			remaining = append(remaining, rb)
+			continue
+		}
+
+		r, err := row.FromNoms(sch, rb.Key, val.(types.Tuple))
+		if err != nil {
+			return nil, err
+		}
+		parentRow, err := row.FromNoms(sch, rb.Key, parentVal.(types.Tuple))
+		if err != nil {
+			return nil, err
+		}
+
+		if rb.Commit == nil {
+			rb.Commit = blamed
+		}
+		for _, tag := range nonPKTags {
+			if _, ok := rb.Columns[tag]; ok {
+				continue
+			}
+
+			v, _ := r.GetColVal(tag)
+			parentV, _ := parentRow.GetColVal(tag)
+			if !valuesEqual(v, parentV) {
+				rb.Columns[tag] = blamed
+			}
+		}
+
+		if len(rb.Columns) < len(nonPKTags) {
+			remaining = append(remaining, rb)
+		}
+	}
+
+	return remaining, nil
+}
+
+// blameAll blames the row, and each of its columns which aren't blamed yet, on |cm|.
+func (rb *RowBlame) blameAll(cm *Commit, nonPKTags []uint64) {
+	if rb.Commit == nil {
+		rb.Commit = cm
+	}
+	for _, tag := range nonPKTags {
+		if _, ok := rb.Columns[tag]; !ok {
+			rb.Columns[tag] = cm
+		}
+	}
+}
+
+// KeyStrings returns the values of the primary key of the row, formatted as strings.
+func (rb *RowBlame) KeyStrings(ctx context.Context) []string {
+	var strs []string
+	i := 0
+	_ = rb.Key.WalkValues(ctx, func(val types.Value) error {
+		// even-indexed values are tags. they aren't useful, don't return them.
+		if i%2 == 1 {
+			strs = append(strs, fmt.Sprintf("%v", val))
+		}
+		i++
+		return nil
+	})
+
+	return strs
+}
+
+func valuesEqual(a, b types.Value) bool {
+	if a == nil || types.IsNull(a) {
+		return b == nil || types.IsNull(b)
+	}
+	return b != nil && a.Equals(b)
+}
+
+// tableFromCommit returns the table named at the commit given, or nil if there is no such table.
+func tableFromCommit(ctx context.Context, cm *doltdb.Commit, tableName string) (*doltdb.Table, error) {
+	root, err := cm.GetRootValue()
+	if err != nil {
+		return nil, fmt.Errorf("error getting root value of commit: %v", err)
+	}
+
+	tbl, _, err := root.GetTable(ctx, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting table %s from root value: %v", tableName, err)
+	}
+
+	return tbl, nil
+}
@@ -47,6 +47,12 @@ const (
 	// InMemBlobstore Scheme
 	LocalBSScheme = "localbs"
 
+	// StaticHTTPScheme
+	StaticHTTPScheme = "static+http"
+
+	// StaticHTTPSScheme
+	StaticHTTPSScheme = "static+https"
+
 	defaultScheme       = HTTPSScheme
 	defaultMemTableSize = 256 * 1024 * 1024
 )
@@ -59,11 +65,13 @@ type DBFactory interface {
 // DBFactories is a map from url scheme name to DBFactory.  Additional factories can be added to the DBFactories map
 // from external packages.
 var DBFactories = map[string]DBFactory{
-	AWSScheme:     AWSFactory{},
-	GSScheme:      GSFactory{},
-	FileScheme:    FileFactory{},
-	MemScheme:     MemFactory{},
-	LocalBSScheme: LocalBSFactory{},
+	AWSScheme:         AWSFactory{},
+	GSScheme:          GSFactory{},
+	FileScheme:        FileFactory{},
+	MemScheme:         MemFactory{},
+	LocalBSScheme:     LocalBSFactory{},
+	StaticHTTPScheme:  StaticHTTPFactory{insecure: true},
+	StaticHTTPSScheme: StaticHTTPFactory{insecure: false},
 }
 
 // InitializeFactories initializes any factories that rely on a GRPCConnectionProvider (Namely http and https)
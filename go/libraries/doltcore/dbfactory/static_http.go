@@ -0,0 +1,54 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbfactory
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/dolthub/dolt/go/store/blobstore"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/nbs"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// StaticHTTPFactory is a DBFactory implementation for creating read only databases which are served by a plain HTTP
+// server. The server needs to serve the contents of a file system remote, the manifest and the table files, and
+// should support range requests.
+type StaticHTTPFactory struct {
+	insecure bool
+}
+
+// CreateDB creates a read only database backed by the files served at the url given
+func (fact StaticHTTPFactory) CreateDB(ctx context.Context, nbf *types.NomsBinFormat, urlObj *url.URL, params map[string]string) (datas.Database, error) {
+	var db datas.Database
+
+	httpURL := *urlObj
+	httpURL.Scheme = "https"
+	if fact.insecure {
+		httpURL.Scheme = "http"
+	}
+
+	bs := blobstore.NewHTTPBlobstore(httpURL.String(), nil)
+	httpStore, err := nbs.NewBSStore(ctx, nbf.VersionString(), bs, defaultMemTableSize)
+
+	if err != nil {
+		return nil, err
+	}
+
+	db = datas.NewDatabase(httpStore)
+
+	return db, err
+}
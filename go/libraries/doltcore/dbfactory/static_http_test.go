@@ -0,0 +1,60 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbfactory
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestCreateStaticHTTPDB(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "static_http")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	fileDB, err := CreateDB(ctx, types.Format_Default, "file://"+filepath.ToSlash(dir), nil)
+	require.NoError(t, err)
+	ds, err := fileDB.GetDataset(ctx, "ds")
+	require.NoError(t, err)
+	_, err = fileDB.CommitValue(ctx, ds, types.String("published"))
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.FileServer(http.Dir(dir)))
+	defer server.Close()
+
+	db, err := CreateDB(ctx, types.Format_Default, "static+"+server.URL, nil)
+	require.NoError(t, err)
+
+	ds, err = db.GetDataset(ctx, "ds")
+	require.NoError(t, err)
+	val, ok, err := ds.MaybeHeadValue()
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, types.String("published"), val)
+
+	_, err = db.CommitValue(ctx, ds, types.String("changed"))
+	assert.Error(t, err)
+}
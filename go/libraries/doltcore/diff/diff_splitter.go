@@ -15,11 +15,15 @@
 package diff
 
 import (
+	"strings"
+
 	"github.com/dolthub/dolt/go/libraries/doltcore/row"
 	"github.com/dolthub/dolt/go/libraries/doltcore/rowconv"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/pipeline"
 	"github.com/dolthub/dolt/go/libraries/utils/valutil"
+	"github.com/dolthub/dolt/go/store/types"
 )
 
 const (
@@ -128,6 +132,13 @@ func (ds *DiffSplitter) SplitDiffIntoOldAndNew(inRow row.Row, props pipeline.Rea
 				if !valutil.NilSafeEqCheck(oldVal, newVal) {
 					newColDiffs[col.Name] = DiffModifiedNew
 					oldColDiffs[col.Name] = DiffModifiedOld
+
+					if col.Kind == types.StringKind {
+						mappedOld, mappedNew, err = ds.diffJSONPaths(rows, tag, mappedOld, mappedNew, outSch)
+						if err != nil {
+							return true, err
+						}
+					}
 				}
 			} else if inOld {
 				oldColDiffs[col.Name] = DiffRemoved
@@ -157,3 +168,49 @@ func (ds *DiffSplitter) SplitDiffIntoOldAndNew(inRow row.Row, props pipeline.Rea
 
 	return results, ""
 }
+
+// diffJSONPaths replaces the values of a modified JSON column in the rows being output with the paths in the
+// document that changed, and their values before and after the change, so that the whole document isn't shown.
+func (ds *DiffSplitter) diffJSONPaths(rows map[string]row.Row, tag uint64, mappedOld, mappedNew row.Row, outSch schema.Schema) (row.Row, row.Row, error) {
+	fromVal, fromOk := jsonColVal(rows[From], ds.joiner.SchemaForName(From), tag)
+	toVal, toOk := jsonColVal(rows[To], ds.joiner.SchemaForName(To), tag)
+	if !fromOk || !toOk || types.IsNull(fromVal) || types.IsNull(toVal) {
+		return mappedOld, mappedNew, nil
+	}
+
+	pathDiffs, err := typeinfo.DiffJSON(fromVal, toVal)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var oldPaths, newPaths []string
+	for _, pathDiff := range pathDiffs {
+		if pathDiff.From != nil {
+			oldPaths = append(oldPaths, pathDiff.Path+": "+*pathDiff.From)
+		}
+		if pathDiff.To != nil {
+			newPaths = append(newPaths, pathDiff.Path+": "+*pathDiff.To)
+		}
+	}
+
+	mappedOld, err = mappedOld.SetColVal(tag, types.String(strings.Join(oldPaths, ", ")), outSch)
+	if err != nil {
+		return nil, nil, err
+	}
+	mappedNew, err = mappedNew.SetColVal(tag, types.String(strings.Join(newPaths, ", ")), outSch)
+	if err != nil {
+		return nil, nil, err
+	}
+	return mappedOld, mappedNew, nil
+}
+
+func jsonColVal(r row.Row, sch schema.Schema, tag uint64) (types.Value, bool) {
+	if r == nil {
+		return nil, false
+	}
+	col, ok := sch.GetAllCols().GetByTag(tag)
+	if !ok || col.TypeInfo.GetTypeIdentifier() != typeinfo.JSONTypeIdentifier {
+		return nil, false
+	}
+	return r.GetColVal(tag)
+}
@@ -103,7 +103,7 @@ type IndexDifference struct {
 // It returns matched and unmatched Indexes as a slice of IndexDifferences.
 func DiffSchIndexes(fromSch, toSch schema.Schema) (diffs []IndexDifference) {
 	_ = fromSch.Indexes().Iter(func(fromIdx schema.Index) (stop bool, err error) {
-		toIdx, ok := toSch.Indexes().GetIndexByTags(fromIdx.IndexedColumnTags()...)
+		toIdx, ok := toSch.Indexes().GetIndexLike(fromIdx)
 
 		if !ok {
 			diffs = append(diffs, IndexDifference{
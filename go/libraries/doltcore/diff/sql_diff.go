@@ -15,6 +15,7 @@
 package diff
 
 import (
+	"context"
 	"errors"
 	"io"
 
@@ -27,14 +28,15 @@ import (
 )
 
 type SQLDiffSink struct {
+	ctx       context.Context
 	wr        io.WriteCloser
 	sch       schema.Schema
 	tableName string
 }
 
-// NewSQLDiffSink creates a SQLDiffSink for a diff pipeline.
-func NewSQLDiffSink(wr io.WriteCloser, sch schema.Schema, tableName string) (*SQLDiffSink, error) {
-	return &SQLDiffSink{wr, sch, tableName}, nil
+// NewSQLDiffSink creates a SQLDiffSink for a diff pipeline. The values of rows are read with the context given.
+func NewSQLDiffSink(ctx context.Context, wr io.WriteCloser, sch schema.Schema, tableName string) (*SQLDiffSink, error) {
+	return &SQLDiffSink{ctx, wr, sch, tableName}, nil
 }
 
 // GetSchema gets the schema that the SQLDiffSink was created with.
@@ -77,32 +79,14 @@ func (sds *SQLDiffSink) ProcRowWithProps(r row.Row, props pipeline.ReadableMap)
 		if dt, convertedOK := prop.(DiffChType); convertedOK {
 			switch dt {
 			case DiffAdded:
-				stmt, err := sqlfmt.RowAsInsertStmt(r, sds.tableName, sds.sch)
-
-				if err != nil {
-					return err
-				}
-
-				return iohelp.WriteLine(sds.wr, stmt)
+				return sds.writeStmt(sqlfmt.WriteRowAsInsertStmt, r)
 			case DiffRemoved:
-				stmt, err := sqlfmt.RowAsDeleteStmt(r, sds.tableName, sds.sch)
-
-				if err != nil {
-					return err
-				}
-
-				return iohelp.WriteLine(sds.wr, stmt)
+				return sds.writeStmt(sqlfmt.WriteRowAsDeleteStmt, r)
 			case DiffModifiedOld:
 				return nil
 			case DiffModifiedNew:
 				// TODO: minimize update statement to modified rows
-				stmt, err := sqlfmt.RowAsUpdateStmt(r, sds.tableName, sds.sch)
-
-				if err != nil {
-					return err
-				}
-
-				return iohelp.WriteLine(sds.wr, stmt)
+				return sds.writeStmt(sqlfmt.WriteRowAsUpdateStmt, r)
 			}
 			// Treat the diff indicator string as a diff of the same type
 			colDiffs[diffColName] = dt
@@ -114,13 +98,17 @@ func (sds *SQLDiffSink) ProcRowWithProps(r row.Row, props pipeline.ReadableMap)
 
 // ProcRowWithProps satisfies pipeline.SinkFunc; it writes rows as SQL statements.
 func (sds *SQLDiffSink) ProcRowForExport(r row.Row, _ pipeline.ReadableMap) error {
-	stmt, err := sqlfmt.RowAsInsertStmt(r, sds.tableName, sds.sch)
+	return sds.writeStmt(sqlfmt.WriteRowAsInsertStmt, r)
+}
 
+// writeStmt writes the statement for a row with the writer given, followed by a newline.
+func (sds *SQLDiffSink) writeStmt(writeRowAsStmt func(context.Context, io.Writer, row.Row, string, schema.Schema) error, r row.Row) error {
+	err := writeRowAsStmt(sds.ctx, sds.wr, r, sds.tableName, sds.sch)
 	if err != nil {
 		return err
 	}
 
-	return iohelp.WriteLine(sds.wr, stmt)
+	return iohelp.WriteLine(sds.wr, "")
 }
 
 // Close should release resources being held
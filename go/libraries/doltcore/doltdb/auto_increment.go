@@ -0,0 +1,221 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"math"
+	"sync"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// autoIncrementTracker assigns values to the AUTO_INCREMENT column of the rows written by a TableEditor, and tracks
+// the value to assign next.
+type autoIncrementTracker struct {
+	sch schema.Schema
+	col schema.Column
+	vrw types.ValueReadWriter
+	// rowData is the table's data when editing began, which is used to find the largest value in the column if the
+	// table has not recorded the next value.
+	rowData types.Map
+	// initial is the value recorded by the table when editing began.
+	initial uint64
+	// next is 0 until it's first needed.
+	next uint64
+	mu   *sync.Mutex
+}
+
+// newAutoIncrementTracker returns the autoIncrementTracker for the table given, or nil if it has no AUTO_INCREMENT
+// column.
+func newAutoIncrementTracker(t *Table, sch schema.Schema, rowData types.Map) (*autoIncrementTracker, error) {
+	var aiCol schema.Column
+	var found bool
+	_ = sch.GetAllCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		if col.AutoIncrement {
+			aiCol, found = col, true
+			return true, nil
+		}
+		return false, nil
+	})
+
+	if !found {
+		return nil, nil
+	}
+
+	initial, err := t.GetAutoIncrementValue()
+	if err != nil {
+		return nil, err
+	}
+
+	return &autoIncrementTracker{
+		sch:     sch,
+		col:     aiCol,
+		vrw:     t.ValueReadWriter(),
+		rowData: rowData,
+		initial: initial,
+		next:    initial,
+		mu:      &sync.Mutex{},
+	}, nil
+}
+
+// assign gives the row a value for the AUTO_INCREMENT column if it has none, or a value of zero. Otherwise, the value
+// the row has is tracked so that later values are assigned after it.
+func (ait *autoIncrementTracker) assign(ctx context.Context, r row.Row) (row.Row, error) {
+	ait.mu.Lock()
+	defer ait.mu.Unlock()
+
+	if err := ait.init(ctx); err != nil {
+		return nil, err
+	}
+
+	val, ok := r.GetColVal(ait.col.Tag)
+	if ok && !types.IsNull(val) && !isZero(val) {
+		ait.track(val)
+		return r, nil
+	}
+
+	newVal, err := ait.col.TypeInfo.ConvertValueToNomsValue(ctx, ait.vrw, ait.next)
+	if err != nil {
+		return nil, err
+	}
+
+	ait.next++
+	return r.SetColVal(ait.col.Tag, newVal, ait.sch)
+}
+
+// observe tracks the value the row has for the AUTO_INCREMENT column, if any.
+func (ait *autoIncrementTracker) observe(ctx context.Context, r row.Row) error {
+	ait.mu.Lock()
+	defer ait.mu.Unlock()
+
+	if err := ait.init(ctx); err != nil {
+		return err
+	}
+
+	if val, ok := r.GetColVal(ait.col.Tag); ok && !types.IsNull(val) {
+		ait.track(val)
+	}
+
+	return nil
+}
+
+// nextValue returns the value that will be assigned next.
+func (ait *autoIncrementTracker) nextValue(ctx context.Context) (uint64, error) {
+	ait.mu.Lock()
+	defer ait.mu.Unlock()
+
+	if err := ait.init(ctx); err != nil {
+		return 0, err
+	}
+
+	return ait.next, nil
+}
+
+// updatedTable returns the table given with the next value recorded, if it changed.
+func (ait *autoIncrementTracker) updatedTable(t *Table) (*Table, error) {
+	ait.mu.Lock()
+	defer ait.mu.Unlock()
+
+	if ait.next == ait.initial {
+		return t, nil
+	}
+
+	return t.SetAutoIncrementValue(ait.next)
+}
+
+func (ait *autoIncrementTracker) init(ctx context.Context) error {
+	if ait.next != 0 {
+		return nil
+	}
+
+	ait.next = 1
+	if ait.rowData.Empty() {
+		return nil
+	}
+
+	pkCols := ait.sch.GetPKCols()
+	if pkCols.Size() == 1 && pkCols.Tags[0] == ait.col.Tag {
+		// rows are ordered by the column, so the last row holds the largest value
+		key, _, err := ait.rowData.Last(ctx)
+		if err != nil || key == nil {
+			return err
+		}
+
+		r, err := row.FromNoms(ait.sch, key.(types.Tuple), types.EmptyTuple(ait.rowData.Format()))
+		if err != nil {
+			return err
+		}
+
+		if val, ok := r.GetColVal(ait.col.Tag); ok {
+			ait.track(val)
+		}
+
+		return nil
+	}
+
+	return ait.rowData.IterAll(ctx, func(key, value types.Value) error {
+		r, err := row.FromNoms(ait.sch, key.(types.Tuple), value.(types.Tuple))
+		if err != nil {
+			return err
+		}
+
+		if val, ok := r.GetColVal(ait.col.Tag); ok {
+			ait.track(val)
+		}
+
+		return nil
+	})
+}
+
+// track makes sure the next value assigned is greater than |val|.
+func (ait *autoIncrementTracker) track(val types.Value) {
+	var n uint64
+	switch v := val.(type) {
+	case types.Int:
+		if v < 0 {
+			return
+		}
+		n = uint64(v)
+	case types.Uint:
+		n = uint64(v)
+	case types.Float:
+		if v < 0 {
+			return
+		}
+		n = uint64(math.Floor(float64(v)))
+	default:
+		return
+	}
+
+	if n != math.MaxUint64 && n >= ait.next {
+		ait.next = n + 1
+	}
+}
+
+func isZero(val types.Value) bool {
+	switch v := val.(type) {
+	case types.Int:
+		return v == 0
+	case types.Uint:
+		return v == 0
+	case types.Float:
+		return v == 0
+	default:
+		return false
+	}
+}
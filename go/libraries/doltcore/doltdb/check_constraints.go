@@ -0,0 +1,84 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// ErrCheckConstraintsUnsupported is returned when writing to a table with enforced CHECK constraints if no
+// RowCheckerFactory has been set.
+var ErrCheckConstraintsUnsupported = errors.New("CHECK constraints cannot be evaluated without the SQL engine")
+
+// RowChecker checks table rows against the enforced CHECK constraints of a table.
+type RowChecker interface {
+	// CheckRow returns an ErrCheckConstraintViolated if the row given does not satisfy every check.
+	CheckRow(ctx context.Context, r row.Row) error
+}
+
+// RowCheckerFactory returns a RowChecker for the |checks| of the schema given. Check expressions are SQL expressions,
+// so it is set by the sqle package, which evaluates them.
+var RowCheckerFactory func(sch schema.Schema, checks []schema.Check) (RowChecker, error)
+
+// newRowChecker returns a RowChecker for the enforced checks of the schema given. Returns nil if there are none.
+func newRowChecker(sch schema.Schema) (RowChecker, error) {
+	var checks []schema.Check
+	for _, check := range sch.Checks().AllChecks() {
+		if check.Enforced {
+			checks = append(checks, check)
+		}
+	}
+
+	if len(checks) == 0 {
+		return nil, nil
+	} else if RowCheckerFactory == nil {
+		return nil, ErrCheckConstraintsUnsupported
+	}
+
+	return RowCheckerFactory(sch, checks)
+}
+
+// ValidateCheckConstraints returns an ErrCheckConstraintViolated if any row of the table given does not satisfy one of
+// the enforced checks in the table's schema.
+func ValidateCheckConstraints(ctx context.Context, tbl *Table) error {
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return err
+	}
+
+	rc, err := newRowChecker(sch)
+	if err != nil || rc == nil {
+		return err
+	}
+
+	rowData, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return err
+	}
+
+	return rowData.Iter(ctx, func(key, value types.Value) (stop bool, err error) {
+		r, err := row.FromNoms(sch, key.(types.Tuple), value.(types.Tuple))
+		if err != nil {
+			return true, err
+		}
+		err = rc.CheckRow(ctx, r)
+		return err != nil, err
+	})
+}
@@ -19,11 +19,14 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"os"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
 	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/remotestorage"
 	"github.com/dolthub/dolt/go/libraries/utils/filesys"
 	"github.com/dolthub/dolt/go/libraries/utils/pantoerr"
 	"github.com/dolthub/dolt/go/store/chunks"
@@ -45,11 +48,33 @@ const (
 	MasterBranch     = "master"
 	CommitStructName = "Commit"
 
-	FeatureVersion featureVersion = 0
+	// FeatureVersion is the feature version of this client. Roots using features added since a client's version can't
+	// be read by that client.
+	FeatureVersion featureVersion = spatialFeatureVersion
+
+	// fullTextFeatureVersion is the feature version of roots holding FULLTEXT indexes.
+	fullTextFeatureVersion featureVersion = 1
+	// spatialFeatureVersion is the feature version of roots holding spatial columns or SPATIAL indexes.
+	spatialFeatureVersion featureVersion = 2
 
 	defaultChunksPerTF = 256 * 1024
+
+	// chunksPerTFEnvVar overrides the number of chunks written to each table file by pushes and pulls.  Their progress
+	// is saved each time a table file is filled, so smaller table files let an interrupted transfer resume from closer
+	// to where it stopped.
+	chunksPerTFEnvVar = "DOLT_PULL_CHUNKS_PER_TABLE_FILE"
 )
 
+func chunksPerTF() int {
+	if val, ok := os.LookupEnv(chunksPerTFEnvVar); ok {
+		if n, err := strconv.Atoi(val); err == nil && n > 0 {
+			return n
+		}
+	}
+
+	return defaultChunksPerTF
+}
+
 type featureVersion int64
 
 // LocalDirDoltDB stores the db in the current directory
@@ -63,13 +88,20 @@ var InMemDoltDB = "mem://"
 // errors in many cases.
 type DoltDB struct {
 	db datas.Database
+
+	// localDB is only set for databases created by WithLazyRemote, in which case |db| reads through to a remote for
+	// chunks which are missing from |localDB|.
+	localDB datas.Database
+
+	// hooks are notified when the head of a branch moves
+	hooks []CommitHook
 }
 
 // DoltDBFromCS creates a DoltDB from a noms chunks.ChunkStore
 func DoltDBFromCS(cs chunks.ChunkStore) *DoltDB {
 	db := datas.NewDatabase(cs)
 
-	return &DoltDB{db}
+	return &DoltDB{db: db}
 }
 
 // LoadDoltDB will acquire a reference to the underlying noms db.  If the Location is InMemDoltDB then a reference
@@ -96,13 +128,19 @@ func LoadDoltDBWithParams(ctx context.Context, nbf *types.NomsBinFormat, urlStr
 		return nil, err
 	}
 
-	return &DoltDB{db}, nil
+	return &DoltDB{db: db}, nil
 }
 
 func (ddb *DoltDB) CSMetricsSummary() string {
 	return datas.GetCSStatSummaryForDB(ddb.db)
 }
 
+// CSMetrics returns the statistics collected by the ChunkStore backing this DoltDB.  The type is implementation
+// dependent, and may be nil.
+func (ddb *DoltDB) CSMetrics() interface{} {
+	return datas.ChunkStoreFromDatabase(ddb.db).Stats()
+}
+
 // WriteEmptyRepo will create initialize the given db with a master branch which points to a commit which has valid
 // metadata for the creation commit, and an empty RootValue.
 func (ddb *DoltDB) WriteEmptyRepo(ctx context.Context, name, email string) error {
@@ -468,7 +506,12 @@ func (ddb *DoltDB) FastForward(ctx context.Context, branch ref.DoltRef, commit *
 
 	_, err = ddb.db.FastForward(ctx, ds, rf)
 
-	return err
+	if err != nil {
+		return err
+	}
+
+	ddb.executeCommitHooks(ctx, branch, commit.commitSt)
+	return nil
 }
 
 // CanFastForward returns whether the given branch can be fast-forwarded to the commit given.
@@ -498,15 +541,32 @@ func (ddb *DoltDB) SetHeadToCommit(ctx context.Context, ref ref.DoltRef, cm *Com
 	return ddb.SetHead(ctx, ref, stRef)
 }
 
-func (ddb *DoltDB) SetHead(ctx context.Context, ref ref.DoltRef, stRef types.Ref) error {
-	ds, err := ddb.db.GetDataset(ctx, ref.String())
+func (ddb *DoltDB) SetHead(ctx context.Context, dref ref.DoltRef, stRef types.Ref) error {
+	ds, err := ddb.db.GetDataset(ctx, dref.String())
 
 	if err != nil {
 		return err
 	}
 
 	_, err = ddb.db.SetHead(ctx, ds, stRef)
-	return err
+
+	if err != nil {
+		return err
+	}
+
+	if len(ddb.hooks) > 0 && dref.GetType() == ref.BranchRefType {
+		val, err := stRef.TargetValue(ctx, ddb.db)
+
+		if err != nil {
+			return err
+		}
+
+		if commitSt, ok := val.(types.Struct); ok {
+			ddb.executeCommitHooks(ctx, dref, commitSt)
+		}
+	}
+
+	return nil
 }
 
 // CommitWithParentSpecs commits the value hash given to the branch given, using the list of parent hashes given. Returns an
@@ -653,6 +713,7 @@ func (ddb *DoltDB) CommitWithParentCommits(ctx context.Context, valHash hash.Has
 		return nil, errors.New("commit has no head but commit succeeded (How?!?!?)")
 	}
 
+	ddb.executeCommitHooks(ctx, dref, commitSt)
 	return NewCommit(ddb.db, commitSt), nil
 }
 
@@ -856,7 +917,12 @@ func (ddb *DoltDB) NewBranchAtCommit(ctx context.Context, dref ref.DoltRef, comm
 
 	_, err = ddb.db.SetHead(ctx, ds, rf)
 
-	return err
+	if err != nil {
+		return err
+	}
+
+	ddb.executeCommitHooks(ctx, dref, commit.commitSt)
+	return nil
 }
 
 // DeleteBranch deletes the branch given, returning an error if it doesn't exist.
@@ -1031,7 +1097,7 @@ func (ddb *DoltDB) pruneUnreferencedDatasets(ctx context.Context) error {
 // communicated over the provided channel.
 func (ddb *DoltDB) PushChunks(ctx context.Context, tempDir string, srcDB *DoltDB, rf types.Ref, progChan chan datas.PullProgress, pullerEventCh chan datas.PullerEvent) error {
 	if datas.CanUsePuller(srcDB.db) && datas.CanUsePuller(ddb.db) {
-		puller, err := datas.NewPuller(ctx, tempDir, defaultChunksPerTF, srcDB.db, ddb.db, rf.TargetHash(), pullerEventCh)
+		puller, err := datas.NewPuller(ctx, tempDir, chunksPerTF(), srcDB.db, ddb.db, rf.TargetHash(), pullerEventCh)
 
 		if err == datas.ErrDBUpToDate {
 			return nil
@@ -1047,7 +1113,7 @@ func (ddb *DoltDB) PushChunks(ctx context.Context, tempDir string, srcDB *DoltDB
 
 func (ddb *DoltDB) PushChunksForRefHash(ctx context.Context, tempDir string, srcDB *DoltDB, h hash.Hash, pullerEventCh chan datas.PullerEvent) error {
 	if datas.CanUsePuller(srcDB.db) && datas.CanUsePuller(ddb.db) {
-		puller, err := datas.NewPuller(ctx, tempDir, defaultChunksPerTF, srcDB.db, ddb.db, h, pullerEventCh)
+		puller, err := datas.NewPuller(ctx, tempDir, chunksPerTF(), srcDB.db, ddb.db, h, pullerEventCh)
 
 		if err == datas.ErrDBUpToDate {
 			return nil
@@ -1064,8 +1130,9 @@ func (ddb *DoltDB) PushChunksForRefHash(ctx context.Context, tempDir string, src
 // PullChunks initiates a pull into a database from the source database given, at the commit given. Progress is
 // communicated over the provided channel.
 func (ddb *DoltDB) PullChunks(ctx context.Context, tempDir string, srcDB *DoltDB, stRef types.Ref, progChan chan datas.PullProgress, pullerEventCh chan datas.PullerEvent) error {
-	if datas.CanUsePuller(srcDB.db) && datas.CanUsePuller(ddb.db) {
-		puller, err := datas.NewPuller(ctx, tempDir, 256*1024, srcDB.db, ddb.db, stRef.TargetHash(), pullerEventCh)
+	sinkDB := ddb.pullSink()
+	if datas.CanUsePuller(srcDB.db) && datas.CanUsePuller(sinkDB) {
+		puller, err := datas.NewPuller(ctx, tempDir, chunksPerTF(), srcDB.db, sinkDB, stRef.TargetHash(), pullerEventCh)
 
 		if err == datas.ErrDBUpToDate {
 			return nil
@@ -1075,8 +1142,79 @@ func (ddb *DoltDB) PullChunks(ctx context.Context, tempDir string, srcDB *DoltDB
 
 		return puller.Pull(ctx)
 	} else {
-		return datas.PullWithoutBatching(ctx, srcDB.db, ddb.db, stRef, progChan)
+		return datas.PullWithoutBatching(ctx, srcDB.db, sinkDB, stRef, progChan)
+	}
+}
+
+// PullTableChunks initiates a pull into a database from the source database given, at the commit given, in the same way
+// as PullChunks. The history, root values and schemas of all tables are pulled, but row and index data is only pulled
+// for the tables named in |tables|. Data for other tables must be retrieved on first access, which requires this
+// database to be created with WithLazyRemote.
+func (ddb *DoltDB) PullTableChunks(ctx context.Context, tempDir string, srcDB *DoltDB, stRef types.Ref, tables []string, pullerEventCh chan datas.PullerEvent) error {
+	sinkDB := ddb.pullSink()
+	if !datas.CanUsePuller(srcDB.db) || !datas.CanUsePuller(sinkDB) {
+		return ErrSparsePullUnsupported
+	}
+
+	puller, err := datas.NewPuller(ctx, tempDir, chunksPerTF(), srcDB.db, sinkDB, stRef.TargetHash(), pullerEventCh)
+
+	if err == datas.ErrDBUpToDate {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	return puller.WithRefFilter(newTableRefFilter(srcDB.db, tables)).Pull(ctx)
+}
+
+// pullSink returns the database that pulled chunks should be written to.  For databases which read through to a remote
+// this is the local database, so that chunks which are only present in the remote are still pulled.
+func (ddb *DoltDB) pullSink() datas.Database {
+	if ddb.localDB != nil {
+		return ddb.localDB
+	}
+
+	return ddb.db
+}
+
+// chunkReadCounter is implemented by the chunk stores which count the chunks read from them.
+type chunkReadCounter interface {
+	ChunksRead() uint64
+}
+
+// ChunksRead returns the number of chunks which were read from the local chunk store of this database since it was
+// opened, and false if the chunk store doesn't count the chunks read from it.
+func (ddb *DoltDB) ChunksRead() (uint64, bool) {
+	counter, ok := datas.ChunkStoreFromDatabase(ddb.pullSink()).(chunkReadCounter)
+	if !ok {
+		return 0, false
 	}
+
+	return counter.ChunksRead(), true
+}
+
+// HasLocally returns true if the chunk with the given hash is stored in this database. Unlike reading the value, this
+// does not read through to the remote of a database created by WithLazyRemote.
+func (ddb *DoltDB) HasLocally(ctx context.Context, h hash.Hash) (bool, error) {
+	return datas.ChunkStoreFromDatabase(ddb.pullSink()).Has(ctx, h)
+}
+
+// WithLazyRemote returns a DoltDB which reads any chunks missing from this database from the remote database returned
+// by |openRemote|. This is used for repositories which were partially cloned or fetched using PullTableChunks. The
+// remote database is not opened until a chunk is missing locally.
+func (ddb *DoltDB) WithLazyRemote(openRemote func(ctx context.Context) (*DoltDB, error)) *DoltDB {
+	local := datas.ChunkStoreFromDatabase(ddb.db)
+	lazyCS := remotestorage.NewLazyChunkStore(local, func(ctx context.Context) (chunks.ChunkStore, error) {
+		remoteDDB, err := openRemote(ctx)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return datas.ChunkStoreFromDatabase(remoteDDB.db), nil
+	})
+
+	return &DoltDB{db: datas.NewDatabase(lazyCS), localDB: ddb.db}
 }
 
 func (ddb *DoltDB) Clone(ctx context.Context, destDB *DoltDB, eventCh chan<- datas.TableFileEvent) error {
@@ -42,6 +42,7 @@ var ErrNoConflicts = errors.New("no conflicts")
 var ErrUpToDate = errors.New("up to date")
 var ErrIsAhead = errors.New("current fast forward from a to b. a is ahead of b already")
 var ErrIsBehind = errors.New("cannot reverse from b to a. b is a is behind a already")
+var ErrSparsePullUnsupported = errors.New("the chunk stores of these databases do not support pulling a subset of tables")
 
 type ErrClientOutOfDate struct {
 	repoVer   featureVersion
@@ -53,6 +54,16 @@ func (e ErrClientOutOfDate) Error() string {
 	visit https://github.com/dolthub/dolt/releases/latest/`, e.clientVer, e.repoVer)
 }
 
+// ErrCheckConstraintViolated is returned when a row written to a table does not satisfy one of the table's CHECK
+// constraints.
+type ErrCheckConstraintViolated struct {
+	CheckName string
+}
+
+func (e ErrCheckConstraintViolated) Error() string {
+	return fmt.Sprintf("Check constraint '%s' is violated.", e.CheckName)
+}
+
 func IsInvalidFormatErr(err error) bool {
 	switch err {
 	case ErrInvBranchName, ErrInvTableName, ErrInvHash, ErrInvalidAncestorSpec, ErrInvalidBranchOrHash:
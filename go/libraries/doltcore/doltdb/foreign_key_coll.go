@@ -113,7 +113,7 @@ func (fk ForeignKey) HashOf() hash.Hash {
 
 // ConstraintIsSatisfied ensures that the foreign key is valid by comparing the index data from the given table against the index
 // data from the referenced table.
-func (fk ForeignKey) ConstraintIsSatisfied(ctx context.Context, childIdx, parentIdx types.Map, childDef, parentDef schema.Index) error {
+func (fk ForeignKey) ConstraintIsSatisfied(ctx context.Context, vrw types.ValueReadWriter, childIdx, parentIdx types.Map, childDef, parentDef schema.Index) error {
 	if fk.ReferencedTableIndex != parentDef.Name() {
 		return fmt.Errorf("cannot validate data as wrong referenced index was given: expected `%s` but received `%s`",
 			fk.ReferencedTableIndex, parentDef.Name())
@@ -130,7 +130,7 @@ func (fk ForeignKey) ConstraintIsSatisfied(ctx context.Context, childIdx, parent
 		return err
 	}
 
-	rc, err := rowconv.NewRowConverter(fm)
+	rc, err := rowconv.NewRowConverter(ctx, vrw, fm)
 	if err != nil {
 		return err
 	}
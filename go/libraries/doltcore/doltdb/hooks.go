@@ -0,0 +1,54 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// CommitHook is notified whenever the head of a branch of a DoltDB is moved to a commit, whether by committing to the
+// branch, creating it, fast forwarding it or setting its head.
+type CommitHook interface {
+	// Execute is called after |branch| has been moved to |cm|.  It is called synchronously by the writer of the branch,
+	// so it must not block.
+	Execute(ctx context.Context, branch ref.DoltRef, cm *Commit)
+}
+
+// SetCommitHooks sets the hooks which are notified when the head of a branch of the database moves.  It must be
+// called before the database is shared between goroutines.
+func (ddb *DoltDB) SetCommitHooks(hooks ...CommitHook) {
+	ddb.hooks = hooks
+}
+
+// CommitHooks returns the hooks which are notified when the head of a branch of the database moves.
+func (ddb *DoltDB) CommitHooks() []CommitHook {
+	return ddb.hooks
+}
+
+// executeCommitHooks notifies the hooks of the database that |dref| was moved to the commit |commitSt|.  Refs which
+// aren't branches are ignored.
+func (ddb *DoltDB) executeCommitHooks(ctx context.Context, dref ref.DoltRef, commitSt types.Struct) {
+	if len(ddb.hooks) == 0 || dref.GetType() != ref.BranchRefType {
+		return
+	}
+
+	cm := NewCommit(ddb.db, commitSt)
+	for _, hook := range ddb.hooks {
+		hook.Execute(ctx, dref, cm)
+	}
+}
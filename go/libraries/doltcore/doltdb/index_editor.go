@@ -20,6 +20,8 @@ import (
 	"io"
 	"sync"
 
+	"github.com/dolthub/dolt/go/libraries/doltcore/fulltext"
+	"github.com/dolthub/dolt/go/libraries/doltcore/geometry"
 	"github.com/dolthub/dolt/go/libraries/doltcore/row"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table"
@@ -40,6 +42,11 @@ type IndexEditor struct {
 	numOutstandingEdits uint64        // The number of edits that have been made since the last flush
 	updated             bool          // Whether the data has changed since the editor was created
 
+	// The expressions of an expression index, which are compiled when they're first needed
+	exprs     IndexExpressions
+	exprsErr  error
+	exprsOnce *sync.Once
+
 	// This mutex blocks on key count updates
 	keyMutex *sync.Mutex
 	// This mutex blocks on map edits
@@ -62,6 +69,7 @@ func NewIndexEditor(index schema.Index, indexData types.Map) *IndexEditor {
 		keyMutex:            &sync.Mutex{},
 		mapMutex:            &sync.Mutex{},
 		flushMutex:          &sync.RWMutex{},
+		exprsOnce:           &sync.Once{},
 	}
 }
 
@@ -212,6 +220,151 @@ func (indexEd *IndexEditor) UpdateIndex(ctx context.Context, originalIndexRow ro
 	return nil
 }
 
+// UpdateFullTextIndex updates a FULLTEXT index for a change to a table row, given the full table rows before and after
+// the change. Only the tokens which were removed from or added to the row are written.
+func (indexEd *IndexEditor) UpdateFullTextIndex(ctx context.Context, originalRow row.Row, updatedRow row.Row) error {
+	originalIndexRows, err := indexEd.fullTextIndexRows(ctx, originalRow)
+	if err != nil {
+		return err
+	}
+	updatedIndexRows, err := indexEd.fullTextIndexRows(ctx, updatedRow)
+	if err != nil {
+		return err
+	}
+	for token, indexRow := range originalIndexRows {
+		if _, ok := updatedIndexRows[token]; !ok {
+			err = indexEd.UpdateIndex(ctx, indexRow, nil)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	for token, indexRow := range updatedIndexRows {
+		if _, ok := originalIndexRows[token]; !ok {
+			err = indexEd.UpdateIndex(ctx, nil, indexRow)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// UpdateSpatialIndex updates a SPATIAL index for a change to a table row, given the full table rows before and after
+// the change.
+func (indexEd *IndexEditor) UpdateSpatialIndex(ctx context.Context, originalRow row.Row, updatedRow row.Row) error {
+	originalIndexRow, err := indexEd.spatialIndexRow(ctx, originalRow)
+	if err != nil {
+		return err
+	}
+	updatedIndexRow, err := indexEd.spatialIndexRow(ctx, updatedRow)
+	if err != nil {
+		return err
+	}
+	return indexEd.UpdateIndex(ctx, originalIndexRow, updatedIndexRow)
+}
+
+// UpdateExpressionIndex updates an expression index for a change to a table row, given the full table rows before and
+// after the change.
+func (indexEd *IndexEditor) UpdateExpressionIndex(ctx context.Context, originalRow row.Row, updatedRow row.Row) error {
+	indexEd.exprsOnce.Do(func() {
+		indexEd.exprs, indexEd.exprsErr = newIndexExpressions(indexEd.idx)
+	})
+	if indexEd.exprsErr != nil {
+		return indexEd.exprsErr
+	}
+
+	var err error
+	var originalIndexRow row.Row
+	var updatedIndexRow row.Row
+	if originalRow != nil {
+		originalIndexRow, err = indexEd.exprs.IndexRow(ctx, originalRow, indexEd.idxSch)
+		if err != nil {
+			return err
+		}
+	}
+	if updatedRow != nil {
+		updatedIndexRow, err = indexEd.exprs.IndexRow(ctx, updatedRow, indexEd.idxSch)
+		if err != nil {
+			return err
+		}
+	}
+	return indexEd.UpdateIndex(ctx, originalIndexRow, updatedIndexRow)
+}
+
+// fullTextIndexRows returns the rows of a FULLTEXT index's map for a table row, keyed by their token. There is one for
+// each distinct token found in the row's indexed columns.
+func (indexEd *IndexEditor) fullTextIndexRows(ctx context.Context, tblRow row.Row) (map[string]row.Row, error) {
+	if tblRow == nil {
+		return nil, nil
+	}
+	var tokens []string
+	for _, tag := range indexEd.idx.IndexedColumnTags() {
+		val, ok := tblRow.GetColVal(tag)
+		if !ok || types.IsNull(val) {
+			continue
+		}
+		col, ok := indexEd.idx.GetColumn(tag)
+		if !ok {
+			return nil, fmt.Errorf("index `%s` has column with tag `%d` which cannot be found", indexEd.idx.Name(), tag)
+		}
+		str, err := col.TypeInfo.FormatValue(ctx, val)
+		if err != nil {
+			return nil, err
+		}
+		if str != nil {
+			tokens = append(tokens, fulltext.Tokenize(*str)...)
+		}
+	}
+
+	pkRow, err := tblRow.ReduceToIndex(ctx, indexEd.idx)
+	if err != nil {
+		return nil, err
+	}
+	indexRows := make(map[string]row.Row, len(tokens))
+	for _, token := range tokens {
+		if _, ok := indexRows[token]; ok {
+			continue
+		}
+		indexRows[token], err = pkRow.SetColVal(schema.FullTextTokenTag, types.String(token), indexEd.idxSch)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return indexRows, nil
+}
+
+// spatialIndexRow returns the row of a SPATIAL index's map for a table row, which holds the cell of the geometry in
+// the row's indexed column.
+func (indexEd *IndexEditor) spatialIndexRow(ctx context.Context, tblRow row.Row) (row.Row, error) {
+	if tblRow == nil {
+		return nil, nil
+	}
+	tag := indexEd.idx.IndexedColumnTags()[0]
+	val, ok := tblRow.GetColVal(tag)
+	if !ok || types.IsNull(val) {
+		return nil, fmt.Errorf("the column of SPATIAL index `%s` cannot be NULL", indexEd.idx.Name())
+	}
+	col, ok := indexEd.idx.GetColumn(tag)
+	if !ok {
+		return nil, fmt.Errorf("index `%s` has column with tag `%d` which cannot be found", indexEd.idx.Name(), tag)
+	}
+	g, err := col.TypeInfo.ConvertNomsValueToValue(ctx, val)
+	if err != nil {
+		return nil, err
+	}
+	geom, ok := g.(geometry.Geometry)
+	if !ok {
+		return nil, fmt.Errorf("column `%s` of SPATIAL index `%s` does not hold geometries", col.Name, indexEd.idx.Name())
+	}
+
+	pkRow, err := tblRow.ReduceToIndex(ctx, indexEd.idx)
+	if err != nil {
+		return nil, err
+	}
+	return pkRow.SetColVal(schema.SpatialCellTag, types.String(geometry.SpatialCell(geom.Bounds())), indexEd.idxSch)
+}
+
 // autoFlush is called at the end of every write call (after all locks have been released) and checks if we need to
 // automatically flush the edits.
 func (indexEd *IndexEditor) autoFlush(ctx context.Context, err *error) {
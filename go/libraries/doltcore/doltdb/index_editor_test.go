@@ -400,3 +400,95 @@ func TestIndexEditorWriteAfterFlush(t *testing.T) {
 	require.NoError(t, err)
 	assert.True(t, sameIndexData.Equals(newIndexData))
 }
+
+func TestIndexEditorFullText(t *testing.T) {
+	format := types.Format_7_18
+	db, err := dbfactory.MemFactory{}.CreateDB(context.Background(), format, nil, nil)
+	require.NoError(t, err)
+	colColl, err := schema.NewColCollection(
+		schema.NewColumn("pk", 0, types.IntKind, true),
+		schema.NewColumn("title", 1, types.StringKind, false),
+		schema.NewColumn("body", 2, types.StringKind, false))
+	require.NoError(t, err)
+	tableSch, err := schema.SchemaFromCols(colColl)
+	require.NoError(t, err)
+	index, err := tableSch.Indexes().AddIndexByColNames("idx_fulltext", []string{"title", "body"}, schema.IndexProperties{IsFullText: true})
+	require.NoError(t, err)
+	indexSch := index.Schema()
+	emptyMap, err := types.NewMap(context.Background(), db)
+	require.NoError(t, err)
+
+	indexEditor := NewIndexEditor(index, emptyMap)
+	originalRow, err := row.New(format, tableSch, row.TaggedValues{
+		0: types.Int(1),
+		1: types.String("Dolt Tutorial"),
+		2: types.String("the dolt data tutorial"),
+	})
+	require.NoError(t, err)
+	require.NoError(t, indexEditor.UpdateFullTextIndex(context.Background(), nil, originalRow))
+	updatedRow, err := originalRow.SetColVal(2, types.String("versioned data"), tableSch)
+	require.NoError(t, err)
+	require.NoError(t, indexEditor.UpdateFullTextIndex(context.Background(), originalRow, updatedRow))
+
+	indexData, err := indexEditor.Map(context.Background())
+	require.NoError(t, err)
+	var tokens []string
+	err = indexData.IterAll(context.Background(), func(key, value types.Value) error {
+		dReadRow, err := row.FromNoms(indexSch, key.(types.Tuple), value.(types.Tuple))
+		require.NoError(t, err)
+		dReadVals, err := row.GetTaggedVals(dReadRow)
+		require.NoError(t, err)
+		assert.Equal(t, types.Int(1), dReadVals[0])
+		tokens = append(tokens, string(dReadVals[schema.FullTextTokenTag].(types.String)))
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"data", "dolt", "tutorial", "versioned"}, tokens)
+}
+
+func TestIndexEditorPrefixes(t *testing.T) {
+	format := types.Format_7_18
+	db, err := dbfactory.MemFactory{}.CreateDB(context.Background(), format, nil, nil)
+	require.NoError(t, err)
+	colColl, err := schema.NewColCollection(
+		schema.NewColumn("pk", 0, types.IntKind, true),
+		schema.NewColumn("email", 1, types.StringKind, false))
+	require.NoError(t, err)
+	tableSch, err := schema.SchemaFromCols(colColl)
+	require.NoError(t, err)
+	prefixIndex, err := tableSch.Indexes().AddIndexByColNames("idx_email_prefix", []string{"email"}, schema.IndexProperties{
+		PrefixLengths: []uint16{4},
+	})
+	require.NoError(t, err)
+	emptyMap, err := types.NewMap(context.Background(), db)
+	require.NoError(t, err)
+
+	dRow, err := row.New(format, tableSch, row.TaggedValues{
+		0: types.Int(1),
+		1: types.String("Bob@Example.com"),
+	})
+	require.NoError(t, err)
+
+	prefixRow, err := dRow.ReduceToIndex(context.Background(), prefixIndex)
+	require.NoError(t, err)
+	prefixEditor := NewIndexEditor(prefixIndex, emptyMap)
+	require.NoError(t, prefixEditor.UpdateIndex(context.Background(), nil, prefixRow))
+	indexData, err := prefixEditor.Map(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []row.TaggedValues{{1: types.String("Bob@"), 0: types.Int(1)}},
+		indexDataValues(t, prefixIndex.Schema(), indexData))
+}
+
+func indexDataValues(t *testing.T, indexSch schema.Schema, indexData types.Map) []row.TaggedValues {
+	var vals []row.TaggedValues
+	err := indexData.IterAll(context.Background(), func(key, value types.Value) error {
+		dReadRow, err := row.FromNoms(indexSch, key.(types.Tuple), value.(types.Tuple))
+		require.NoError(t, err)
+		dReadVals, err := row.GetTaggedVals(dReadRow)
+		require.NoError(t, err)
+		vals = append(vals, dReadVals)
+		return nil
+	})
+	require.NoError(t, err)
+	return vals
+}
@@ -0,0 +1,47 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+)
+
+// ErrIndexExpressionsUnsupported is returned when writing to a table with expression indexes if no
+// IndexExpressionsFactory has been set.
+var ErrIndexExpressionsUnsupported = errors.New("expression indexes cannot be evaluated without the SQL engine")
+
+// IndexExpressions evaluates the expressions of an expression index against table rows.
+type IndexExpressions interface {
+	// IndexRow returns the row of the index's map for the table row given, which holds the values of the index's
+	// expressions followed by the row's primary keys.
+	IndexRow(ctx context.Context, tblRow row.Row, idxSch schema.Schema) (row.Row, error)
+}
+
+// IndexExpressionsFactory returns the IndexExpressions of the expression index given. Index expressions are SQL
+// expressions, so it is set by the sqle package, which evaluates them.
+var IndexExpressionsFactory func(idx schema.Index) (IndexExpressions, error)
+
+// newIndexExpressions returns the IndexExpressions of the expression index given.
+func newIndexExpressions(idx schema.Index) (IndexExpressions, error) {
+	if IndexExpressionsFactory == nil {
+		return nil, ErrIndexExpressionsUnsupported
+	}
+
+	return IndexExpressionsFactory(idx)
+}
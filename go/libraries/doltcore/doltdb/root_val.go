@@ -24,6 +24,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/row"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema/encoding"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
 	"github.com/dolthub/dolt/go/libraries/utils/set"
 	"github.com/dolthub/dolt/go/store/hash"
 	"github.com/dolthub/dolt/go/store/types"
@@ -83,7 +84,24 @@ func NewRootValue(ctx context.Context, vrw types.ValueReadWriter, tables map[str
 		return nil, err
 	}
 
-	return newRootFromMaps(vrw, tblMap, ssMap, fkMap)
+	root, err := newRootFromMaps(vrw, tblMap, ssMap, fkMap)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// the tables may come from several roots, so the feature version is found from the tables themselves
+	newRoot := root
+	err = root.IterTables(ctx, func(name string, table *Table, sch schema.Schema) (stop bool, err error) {
+		newRoot, err = newRoot.withTableFeatureVersion(ctx, sch)
+		return false, err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return newRoot, nil
 }
 
 func newRootValue(vrw types.ValueReadWriter, st types.Struct) *RootValue {
@@ -212,7 +230,7 @@ func (root *RootValue) GenerateTagsForNewColColl(ctx context.Context, tableName
 	newColKinds := make([]types.NomsKind, 0, cc.Size())
 	_ = cc.Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
 		newColNames = append(newColNames, col.Name)
-		newColKinds = append(newColKinds, col.Kind)
+		newColKinds = append(newColKinds, typeinfo.TagKind(col.TypeInfo))
 		return false, nil
 	})
 
@@ -253,7 +271,7 @@ func (root *RootValue) GenerateTagsForNewColumns(ctx context.Context, tableName
 			return nil, err
 		}
 		_ = sch.GetAllCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
-			existingColKinds = append(existingColKinds, col.Kind)
+			existingColKinds = append(existingColKinds, typeinfo.TagKind(col.TypeInfo))
 			return false, nil
 		})
 	}
@@ -392,6 +410,18 @@ func (root *RootValue) SetTableHash(ctx context.Context, tName string, h hash.Ha
 		return nil, err
 	}
 
+	sch, err := (&Table{root.vrw, val.(types.Struct)}).GetSchema(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	root, err = root.withTableFeatureVersion(ctx, sch)
+
+	if err != nil {
+		return nil, err
+	}
+
 	return putTable(ctx, root, tName, ref)
 }
 
@@ -657,7 +687,13 @@ func (root *RootValue) PutSuperSchema(ctx context.Context, tName string, ss *sch
 
 // PutTable inserts a table by name into the map of tables. If a table already exists with that name it will be replaced
 func (root *RootValue) PutTable(ctx context.Context, tName string, table *Table) (*RootValue, error) {
-	err := validateTagUniqueness(ctx, root, tName, table)
+	sch, err := table.GetSchema(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateTagUniqueness(ctx, root, tName, sch)
 
 	if err != nil {
 		return nil, err
@@ -669,9 +705,48 @@ func (root *RootValue) PutTable(ctx context.Context, tName string, table *Table)
 		return nil, err
 	}
 
+	root, err = root.withTableFeatureVersion(ctx, sch)
+
+	if err != nil {
+		return nil, err
+	}
+
 	return putTable(ctx, root, tName, tableRef)
 }
 
+// withTableFeatureVersion returns this root with its feature version raised to the one needed to read a table with the
+// given schema, so that older clients refuse to read the root rather than ignore what they don't support.
+func (root *RootValue) withTableFeatureVersion(ctx context.Context, sch schema.Schema) (*RootValue, error) {
+	ver := featureVersion(0)
+	for _, idx := range sch.Indexes().AllIndexes() {
+		if idx.IsFullText() && ver < fullTextFeatureVersion {
+			ver = fullTextFeatureVersion
+		}
+		if idx.IsSpatial() {
+			ver = spatialFeatureVersion
+		}
+	}
+	for _, col := range sch.GetAllCols().GetColumns() {
+		if col.TypeInfo.GetTypeIdentifier() == typeinfo.SpatialTypeIdentifier {
+			ver = spatialFeatureVersion
+		}
+	}
+
+	rootVer, ok, err := root.GetFeatureVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if ver == 0 || (ok && featureVersion(rootVer) >= ver) {
+		return root, nil
+	}
+
+	rootValSt, err := root.valueSt.Set(featureVersKey, types.Int(ver))
+	if err != nil {
+		return nil, err
+	}
+	return newRootValue(root.vrw, rootValSt), nil
+}
+
 func putTable(ctx context.Context, root *RootValue, tName string, tableRef types.Ref) (*RootValue, error) {
 	if !IsValidTableName(tName) {
 		panic("Don't attempt to put a table with a name that fails the IsValidTableName check")
@@ -1527,12 +1602,7 @@ func UnionTableNames(ctx context.Context, roots ...*RootValue) ([]string, error)
 }
 
 // validateTagUniqueness checks for tag collisions between the given table and the set of tables in then given root.
-func validateTagUniqueness(ctx context.Context, root *RootValue, tableName string, table *Table) error {
-	sch, err := table.GetSchema(ctx)
-	if err != nil {
-		return err
-	}
-
+func validateTagUniqueness(ctx context.Context, root *RootValue, tableName string, sch schema.Schema) error {
 	var ee []string
 	_ = root.iterSuperSchemas(ctx, func(tn string, ss *schema.SuperSchema) (stop bool, err error) {
 		if tn == tableName {
@@ -19,9 +19,11 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/row"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
 	"github.com/dolthub/dolt/go/store/types"
 )
 
@@ -110,6 +112,58 @@ func TestTableDiff(t *testing.T) {
 	}
 }
 
+func TestFeatureVersion(t *testing.T) {
+	ctx := context.Background()
+	ddb, _ := LoadDoltDB(ctx, types.Format_7_18, InMemDoltDB)
+	ddb.WriteEmptyRepo(ctx, "billy bob", "bigbillieb@fake.horse")
+
+	cs, _ := NewCommitSpec("master")
+	cm, _ := ddb.Resolve(ctx, cs, nil)
+	root, err := cm.GetRootValue()
+	require.NoError(t, err)
+
+	m, err := types.NewMap(ctx, ddb.ValueReadWriter())
+	require.NoError(t, err)
+	tbl, err := createTestTable(ddb.ValueReadWriter(), createTestSchema(t), m)
+	require.NoError(t, err)
+	root, err = root.PutTable(ctx, "tbl1", tbl)
+	require.NoError(t, err)
+	_, ok, err := root.GetFeatureVersion(ctx)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	sch := createTestSchema(t)
+	_, err = sch.Indexes().AddIndexByColTags("ft", []uint64{firstTag, lastTag}, schema.IndexProperties{IsFullText: true})
+	require.NoError(t, err)
+	tbl, err = createTestTable(ddb.ValueReadWriter(), sch, m)
+	require.NoError(t, err)
+	root, err = root.PutTable(ctx, "tbl2", tbl)
+	require.NoError(t, err)
+	ver, ok, err := root.GetFeatureVersion(ctx)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, int64(fullTextFeatureVersion), ver)
+
+	loc, err := schema.NewColumnWithTypeInfo("location", 1, typeinfo.PointType, false, "", false, "")
+	require.NoError(t, err)
+	cols, err := schema.NewColCollection(schema.NewColumn("id", 0, types.IntKind, true), loc)
+	require.NoError(t, err)
+	sch, err = schema.SchemaFromCols(cols)
+	require.NoError(t, err)
+	tbl, err = createTestTable(ddb.ValueReadWriter(), sch, m)
+	require.NoError(t, err)
+	root, err = root.PutTable(ctx, "tbl3", tbl)
+	require.NoError(t, err)
+	ver, _, err = root.GetFeatureVersion(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(spatialFeatureVersion), ver)
+
+	h, err := ddb.WriteRootValue(ctx, root)
+	require.NoError(t, err)
+	_, err = ddb.ReadRootValue(ctx, h)
+	require.NoError(t, err)
+}
+
 func TestDocDiff(t *testing.T) {
 	ctx := context.Background()
 	ddb, _ := LoadDoltDB(ctx, types.Format_7_18, InMemDoltDB)
@@ -65,6 +65,15 @@ func (ste *SessionedTableEditor) InsertRow(ctx context.Context, dRow row.Row) er
 	ste.tableEditSession.writeMutex.RLock()
 	defer ste.tableEditSession.writeMutex.RUnlock()
 
+	// the AUTO_INCREMENT value must be assigned before the row's foreign keys are validated
+	if ste.tableEditor.autoInc != nil {
+		var err error
+		dRow, err = ste.tableEditor.autoInc.assign(ctx, dRow)
+		if err != nil {
+			return err
+		}
+	}
+
 	err := ste.validateForInsert(ctx, dRow)
 	if err != nil {
 		return err
@@ -73,6 +82,12 @@ func (ste *SessionedTableEditor) InsertRow(ctx context.Context, dRow row.Row) er
 	return ste.tableEditor.InsertRow(ctx, dRow)
 }
 
+// NextAutoIncrementValue returns the value that will be given to the AUTO_INCREMENT column of the next row inserted
+// without one.
+func (ste *SessionedTableEditor) NextAutoIncrementValue(ctx context.Context) (uint64, error) {
+	return ste.tableEditor.NextAutoIncrementValue(ctx)
+}
+
 // DeleteKey removes the given key from the table.
 func (ste *SessionedTableEditor) DeleteKey(ctx context.Context, key types.Tuple) error {
 	ste.tableEditSession.writeMutex.RLock()
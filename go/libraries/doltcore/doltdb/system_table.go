@@ -136,12 +136,16 @@ var generatedSystemTables = []string{
 	BranchesTableName,
 	LogTableName,
 	TableOfTablesInConflictName,
+	PushStatusTableName,
+	QueryStatsTableName,
+	StatisticsTableName,
 }
 
 var generatedSystemTablePrefixes = []string{
 	DoltDiffTablePrefix,
 	DoltHistoryTablePrefix,
 	DoltConfTablePrefix,
+	DoltBlameTablePrefix,
 }
 
 const (
@@ -231,6 +235,24 @@ const (
 	DoltSchemasFragmentTag
 )
 
+const (
+	// StatisticsTableName is the name of the system table showing the table statistics collected by ANALYZE TABLE
+	StatisticsTableName = "dolt_statistics"
+	// StatisticsTableNameCol is the name of the column containing the name of the table a row has the statistics of
+	StatisticsTableNameCol = "table_name"
+	// StatisticsColumnNameCol is the name of the column containing the name of the column a row has the statistics of
+	StatisticsColumnNameCol = "column_name"
+	// StatisticsRowCountCol is the name of the column containing the number of rows in the table
+	StatisticsRowCountCol = "row_count"
+	// StatisticsNullCountCol is the name of the column containing the number of NULL values in the column
+	StatisticsNullCountCol = "null_count"
+	// StatisticsDistinctCountCol is the name of the column containing the estimated number of distinct values in the
+	// column
+	StatisticsDistinctCountCol = "distinct_count"
+	// StatisticsHistogramCol is the name of the column containing the histogram of the column's values, as JSON
+	StatisticsHistogramCol = "histogram"
+)
+
 const (
 	// DoltHistoryTablePrefix is the prefix assigned to all the generated history tables
 	DoltHistoryTablePrefix = "dolt_history_"
@@ -238,6 +260,8 @@ const (
 	DoltDiffTablePrefix = "dolt_diff_"
 	// DoltConfTablePrefix is the prefix assigned to all the generated conflict tables
 	DoltConfTablePrefix = "dolt_conflicts_"
+	// DoltBlameTablePrefix is the prefix assigned to all the generated blame tables
+	DoltBlameTablePrefix = "dolt_blame_"
 )
 
 // Tags for dolt_history_ table
@@ -262,4 +286,10 @@ const (
 
 	// BranchesTableName is the system table name
 	BranchesTableName = "dolt_branches"
+
+	// PushStatusTableName is the push on commit status system table name
+	PushStatusTableName = "dolt_push_status"
+
+	// QueryStatsTableName is the sql-server query statistics system table name
+	QueryStatsTableName = "dolt_query_stats"
 )
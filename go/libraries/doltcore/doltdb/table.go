@@ -35,6 +35,7 @@ const (
 	conflictsKey       = "conflicts"
 	conflictSchemasKey = "conflict_schemas"
 	indexesKey         = "indexes"
+	autoIncrementKey   = "auto_increment"
 
 	// TableNameRegexStr is the regular expression that valid tables must match.
 	TableNameRegexStr = `^[a-zA-Z]{1}$|^[a-zA-Z]+[-_0-9a-zA-Z]*[0-9a-zA-Z]+$`
@@ -334,7 +335,7 @@ func (t *Table) UpdateSchema(ctx context.Context, sch schema.Schema) (*Table, er
 	if err != nil {
 		return nil, err
 	}
-	return newTable, nil
+	return CopyAutoIncrementValue(t, newTable)
 }
 
 // HasTheSameSchema tests the schema within 2 tables for equality
@@ -466,6 +467,46 @@ func (t *Table) UpdateRows(ctx context.Context, updatedRows types.Map) (*Table,
 	return &Table{t.vrw, updatedSt}, nil
 }
 
+// GetAutoIncrementValue returns the value that will be given to the AUTO_INCREMENT column of the next row inserted
+// without one, or 0 if the table has not recorded a value.
+func (t *Table) GetAutoIncrementValue() (uint64, error) {
+	val, ok, err := t.tableStruct.MaybeGet(autoIncrementKey)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if !ok {
+		return 0, nil
+	}
+
+	return uint64(val.(types.Uint)), nil
+}
+
+// SetAutoIncrementValue sets the value that will be given to the AUTO_INCREMENT column of the next row inserted
+// without one, and returns an updated Table.
+func (t *Table) SetAutoIncrementValue(val uint64) (*Table, error) {
+	updatedSt, err := t.tableStruct.Set(autoIncrementKey, types.Uint(val))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Table{t.vrw, updatedSt}, nil
+}
+
+// CopyAutoIncrementValue returns |to| with the AUTO_INCREMENT value recorded by |from|, if any. It's used when a table
+// is rebuilt from another, such as when its schema is altered.
+func CopyAutoIncrementValue(from, to *Table) (*Table, error) {
+	val, err := from.GetAutoIncrementValue()
+
+	if err != nil || val == 0 {
+		return to, err
+	}
+
+	return to.SetAutoIncrementValue(val)
+}
+
 // GetRowData retrieves the underlying map which is a map from a primary key to a list of field values.
 func (t *Table) GetRowData(ctx context.Context) (types.Map, error) {
 	val, _, err := t.tableStruct.MaybeGet(tableRowsKey)
@@ -771,7 +812,14 @@ func rebuildIndexRowData(ctx context.Context, vrw types.ValueReadWriter, sch sch
 		if err != nil {
 			return err
 		}
-		indexRow, err := dRow.ReduceToIndex(index)
+		if index.IsFullText() {
+			return indexEditor.UpdateFullTextIndex(ctx, nil, dRow)
+		} else if index.IsSpatial() {
+			return indexEditor.UpdateSpatialIndex(ctx, nil, dRow)
+		} else if len(index.Expressions()) > 0 {
+			return indexEditor.UpdateExpressionIndex(ctx, nil, dRow)
+		}
+		indexRow, err := dRow.ReduceToIndex(ctx, index)
 		if err != nil {
 			return err
 		}
@@ -45,6 +45,8 @@ type TableEditor struct {
 	aq       *async.ActionExecutor
 	nbf      *types.NomsBinFormat
 	indexEds []*IndexEditor
+	checks   RowChecker            // nil when the table has no enforced checks
+	autoInc  *autoIncrementTracker // nil when the table has no AUTO_INCREMENT column
 
 	rowData types.Map // cached for GetRow and ContainsKey operations
 
@@ -82,6 +84,16 @@ func NewTableEditor(ctx context.Context, t *Table, tableSch schema.Schema) (*Tab
 	}
 	te.aq = async.NewActionExecutor(ctx, te.flushEditAccumulator, 1, 1)
 
+	te.checks, err = newRowChecker(tableSch)
+	if err != nil {
+		return nil, err
+	}
+
+	te.autoInc, err = newAutoIncrementTracker(t, tableSch, te.rowData)
+	if err != nil {
+		return nil, err
+	}
+
 	for i, index := range tableSch.Indexes().AllIndexes() {
 		indexData, err := t.GetIndexRowData(ctx, index.Name())
 		if err != nil {
@@ -231,8 +243,23 @@ func (te *TableEditor) GetRowData(ctx context.Context) (types.Map, error) {
 	return te.rowData, nil
 }
 
-// InsertRow adds the given row to the table. If the row already exists, use UpdateRow.
+// InsertRow adds the given row to the table. If the row already exists, use UpdateRow. Rows without a value for the
+// table's AUTO_INCREMENT column are given the next one.
 func (te *TableEditor) InsertRow(ctx context.Context, dRow row.Row) error {
+	if te.autoInc != nil {
+		var err error
+		dRow, err = te.autoInc.assign(ctx, dRow)
+		if err != nil {
+			return err
+		}
+	}
+
+	if te.checks != nil {
+		if err := te.checks.CheckRow(ctx, dRow); err != nil {
+			return err
+		}
+	}
+
 	defer te.autoFlush()
 	te.flushMutex.RLock()
 	defer te.flushMutex.RUnlock()
@@ -304,6 +331,18 @@ func (te *TableEditor) DeleteRow(ctx context.Context, dRow row.Row) error {
 
 // UpdateRow takes the current row and new rows, and updates it accordingly.
 func (te *TableEditor) UpdateRow(ctx context.Context, dOldRow row.Row, dNewRow row.Row) error {
+	if te.autoInc != nil {
+		if err := te.autoInc.observe(ctx, dNewRow); err != nil {
+			return err
+		}
+	}
+
+	if te.checks != nil {
+		if err := te.checks.CheckRow(ctx, dNewRow); err != nil {
+			return err
+		}
+	}
+
 	defer te.autoFlush()
 	te.flushMutex.RLock()
 	defer te.flushMutex.RUnlock()
@@ -355,6 +394,15 @@ func (te *TableEditor) UpdateRow(ctx context.Context, dOldRow row.Row, dNewRow r
 	return nil
 }
 
+// NextAutoIncrementValue returns the value that will be given to the AUTO_INCREMENT column of the next row inserted
+// without one. Returns an error if the table has no AUTO_INCREMENT column.
+func (te *TableEditor) NextAutoIncrementValue(ctx context.Context) (uint64, error) {
+	if te.autoInc == nil {
+		return 0, fmt.Errorf("table has no AUTO_INCREMENT column")
+	}
+	return te.autoInc.nextValue(ctx)
+}
+
 // Flush finalizes all of the changes made so far.
 func (te *TableEditor) Flush() {
 	te.flushMutex.Lock()
@@ -370,6 +418,11 @@ func (te *TableEditor) Flush() {
 func (te *TableEditor) Table() (*Table, error) {
 	te.Flush()
 	err := te.aq.WaitForEmpty()
+	if err != nil || te.autoInc == nil {
+		return te.t, err
+	}
+
+	te.t, err = te.autoInc.updatedTable(te.t)
 	return te.t, err
 }
 
@@ -544,17 +597,37 @@ func (te *TableEditor) updateIndexes(ctx context.Context, tea *tableEditAccumula
 		}
 
 		for _, indexEd := range te.indexEds {
+			if indexEd.Index().IsFullText() {
+				err := indexEd.UpdateFullTextIndex(ctx, originalRow, updatedRow)
+				if err != nil {
+					return err
+				}
+				continue
+			} else if indexEd.Index().IsSpatial() {
+				err := indexEd.UpdateSpatialIndex(ctx, originalRow, updatedRow)
+				if err != nil {
+					return err
+				}
+				continue
+			} else if len(indexEd.Index().Expressions()) > 0 {
+				err := indexEd.UpdateExpressionIndex(ctx, originalRow, updatedRow)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+
 			var err error
 			var originalIndexRow row.Row
 			var updatedIndexRow row.Row
 			if originalRow != nil {
-				originalIndexRow, err = originalRow.ReduceToIndex(indexEd.Index())
+				originalIndexRow, err = originalRow.ReduceToIndex(ctx, indexEd.Index())
 				if err != nil {
 					return err
 				}
 			}
 			if updatedRow != nil {
-				updatedIndexRow, err = updatedRow.ReduceToIndex(indexEd.Index())
+				updatedIndexRow, err = updatedRow.ReduceToIndex(ctx, indexEd.Index())
 				if err != nil {
 					return err
 				}
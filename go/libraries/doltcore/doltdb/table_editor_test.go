@@ -379,3 +379,64 @@ func TestTableEditorDuplicateKeyHandling(t *testing.T) {
 		})
 	}
 }
+
+func TestTableEditorAutoIncrement(t *testing.T) {
+	format := types.Format_7_18
+	db, err := dbfactory.MemFactory{}.CreateDB(context.Background(), format, nil, nil)
+	require.NoError(t, err)
+	pkCol := schema.NewColumn("pk", 0, types.IntKind, true)
+	pkCol.AutoIncrement = true
+	colColl, err := schema.NewColCollection(pkCol, schema.NewColumn("v1", 1, types.IntKind, false))
+	require.NoError(t, err)
+	tableSch, err := schema.SchemaFromCols(colColl)
+	require.NoError(t, err)
+	tableSchVal, err := encoding.MarshalSchemaAsNomsValue(context.Background(), db, tableSch)
+	require.NoError(t, err)
+	emptyMap, err := types.NewMap(context.Background(), db)
+	require.NoError(t, err)
+	table, err := NewTable(context.Background(), db, tableSchVal, emptyMap, nil)
+	require.NoError(t, err)
+
+	insert := func(tableEditor *TableEditor, vals row.TaggedValues) {
+		dRow, err := row.New(format, tableSch, vals)
+		require.NoError(t, err)
+		require.NoError(t, tableEditor.InsertRow(context.Background(), dRow))
+	}
+
+	tableEditor, err := NewTableEditor(context.Background(), table, tableSch)
+	require.NoError(t, err)
+	insert(tableEditor, row.TaggedValues{1: types.Int(1)})
+	insert(tableEditor, row.TaggedValues{0: types.Int(0), 1: types.Int(2)})
+	insert(tableEditor, row.TaggedValues{0: types.Int(10), 1: types.Int(10)})
+	insert(tableEditor, row.TaggedValues{1: types.Int(11)})
+	table, err = tableEditor.Table()
+	require.NoError(t, err)
+
+	next, err := table.GetAutoIncrementValue()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(12), next)
+	for pk, v1 := range map[int64]int64{1: 1, 2: 2, 10: 10, 11: 11} {
+		r, ok, err := table.GetRowByPKVals(context.Background(), row.TaggedValues{0: types.Int(pk)}, tableSch)
+		require.NoError(t, err)
+		require.True(t, ok, "missing row %d", pk)
+		val, _ := r.GetColVal(1)
+		assert.Equal(t, types.Int(v1), val)
+	}
+
+	// values are not reused once the largest one is deleted
+	tableEditor, err = NewTableEditor(context.Background(), table, tableSch)
+	require.NoError(t, err)
+	dRow, err := row.New(format, tableSch, row.TaggedValues{0: types.Int(11), 1: types.Int(11)})
+	require.NoError(t, err)
+	require.NoError(t, tableEditor.DeleteRow(context.Background(), dRow))
+	insert(tableEditor, row.TaggedValues{1: types.Int(12)})
+	table, err = tableEditor.Table()
+	require.NoError(t, err)
+
+	_, ok, err := table.GetRowByPKVals(context.Background(), row.TaggedValues{0: types.Int(12)}, tableSch)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	next, err = table.GetAutoIncrementValue()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(13), next)
+}
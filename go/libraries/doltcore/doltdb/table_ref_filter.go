@@ -0,0 +1,129 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+
+	"github.com/dolthub/dolt/go/libraries/utils/set"
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// tableRefFilter prunes the row data and index data of all tables other than the ones requested from the chunk graph
+// walked while pulling. Commits, root values, table structs and schemas are always pulled so that the history and the
+// schemas of every table are available locally.
+type tableRefFilter struct {
+	vrw    types.ValueReadWriter
+	tables *set.StrSet
+
+	// keep holds the hashes of the table structs of tables whose data should be pulled, and prune holds the hashes of
+	// the table structs of all other tables.  As the Puller walks the graph breadth first, a root value is always seen
+	// before the table structs it references.
+	keep  hash.HashSet
+	prune hash.HashSet
+}
+
+func newTableRefFilter(vrw types.ValueReadWriter, tables []string) datas.RefFilter {
+	f := &tableRefFilter{
+		vrw:    vrw,
+		tables: set.NewStrSet(tables),
+		keep:   hash.HashSet{},
+		prune:  hash.HashSet{},
+	}
+
+	return f.filter
+}
+
+func (f *tableRefFilter) filter(ctx context.Context, c chunks.Chunk, refs map[hash.Hash]int) error {
+	data := c.Data()
+
+	// the first byte of an encoded value is its kind. Avoid decoding anything that can't be a root value or a table.
+	if len(data) == 0 || types.NomsKind(data[0]) != types.StructKind {
+		return nil
+	}
+
+	val, err := types.DecodeValue(c, f.vrw)
+
+	if err != nil {
+		return err
+	}
+
+	st, ok := val.(types.Struct)
+
+	if !ok {
+		return nil
+	}
+
+	switch st.Name() {
+	case CommitStructName:
+		// the root value of a commit is stored inline in the commit struct
+		rootVal, ok, err := st.MaybeGet(datas.ValueField)
+
+		if err != nil {
+			return err
+		}
+
+		if rootSt, isSt := rootVal.(types.Struct); ok && isSt && rootSt.Name() == ddbRootStructName {
+			return f.addTablesFromRoot(ctx, rootSt)
+		}
+
+	case ddbRootStructName:
+		return f.addTablesFromRoot(ctx, st)
+
+	case tableStructName:
+		h := c.Hash()
+		if !f.prune.Has(h) || f.keep.Has(h) {
+			return nil
+		}
+
+		for _, key := range []string{tableRowsKey, indexesKey} {
+			fieldVal, ok, err := st.MaybeGet(key)
+
+			if err != nil {
+				return err
+			}
+
+			if r, isRef := fieldVal.(types.Ref); ok && isRef {
+				delete(refs, r.TargetHash())
+			}
+		}
+	}
+
+	return nil
+}
+
+func (f *tableRefFilter) addTablesFromRoot(ctx context.Context, rootSt types.Struct) error {
+	tablesVal, ok, err := rootSt.MaybeGet(tablesKey)
+
+	if err != nil || !ok {
+		return err
+	}
+
+	return tablesVal.(types.Map).IterAll(ctx, func(key, value types.Value) error {
+		tblName := string(key.(types.String))
+		tblHash := value.(types.Ref).TargetHash()
+
+		if f.tables.Contains(tblName) {
+			f.keep.Insert(tblHash)
+		} else {
+			f.prune.Insert(tblHash)
+		}
+
+		return nil
+	})
+}
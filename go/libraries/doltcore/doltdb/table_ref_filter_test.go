@@ -0,0 +1,99 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestTableRefFilter(t *testing.T) {
+	ctx := context.Background()
+	db, err := dbfactory.MemFactory{}.CreateDB(ctx, types.Format_7_18, nil, nil)
+	require.NoError(t, err)
+
+	sch := createTestSchema(t)
+	rowData, _ := createTestRowData(t, db, sch)
+	kept, err := createTestTable(db, sch, rowData)
+	require.NoError(t, err)
+
+	updatedData, _ := createUpdatedTestRowData(t, db, sch)
+	pruned, err := createTestTable(db, sch, updatedData)
+	require.NoError(t, err)
+
+	root, err := emptyRootValue(ctx, db)
+	require.NoError(t, err)
+	root, err = root.PutTable(ctx, "kept", kept)
+	require.NoError(t, err)
+	root, err = root.PutTable(ctx, "pruned", pruned)
+	require.NoError(t, err)
+
+	filter := newTableRefFilter(db, []string{"kept"})
+
+	tableRefs := func(tbl *Table) (map[hash.Hash]int, hash.Hash, hash.Hash) {
+		refs := make(map[hash.Hash]int)
+		err := tbl.tableStruct.WalkRefs(db.Format(), func(r types.Ref) error {
+			refs[r.TargetHash()] = int(r.Height())
+			return nil
+		})
+		require.NoError(t, err)
+
+		rowsRef, _, err := tbl.tableStruct.MaybeGet(tableRowsKey)
+		require.NoError(t, err)
+		schemaRef, _, err := tbl.tableStruct.MaybeGet(schemaRefKey)
+		require.NoError(t, err)
+
+		return refs, rowsRef.(types.Ref).TargetHash(), schemaRef.(types.Ref).TargetHash()
+	}
+
+	filterTable := func(tbl *Table) map[hash.Hash]int {
+		c, err := types.EncodeValue(tbl.tableStruct, db.Format())
+		require.NoError(t, err)
+
+		refs, _, _ := tableRefs(tbl)
+		require.NoError(t, filter(ctx, c, refs))
+		return refs
+	}
+
+	// tables aren't pruned until they are found in a root value
+	_, prunedRows, _ := tableRefs(pruned)
+	assert.Contains(t, filterTable(pruned), prunedRows)
+
+	rootChunk, err := types.EncodeValue(root.valueSt, db.Format())
+	require.NoError(t, err)
+	require.NoError(t, filter(ctx, rootChunk, map[hash.Hash]int{}))
+
+	expectedKept, _, _ := tableRefs(kept)
+	assert.Equal(t, expectedKept, filterTable(kept))
+
+	_, prunedRows, prunedSchema := tableRefs(pruned)
+	prunedRefs := filterTable(pruned)
+	assert.NotContains(t, prunedRefs, prunedRows)
+	assert.Contains(t, prunedRefs, prunedSchema)
+
+	// values which can't be root values or tables are ignored
+	c, err := types.EncodeValue(types.String("pruned"), db.Format())
+	require.NoError(t, err)
+	refs := map[hash.Hash]int{prunedRows: 1}
+	require.NoError(t, filter(ctx, c, refs))
+	assert.Contains(t, refs, prunedRows)
+}
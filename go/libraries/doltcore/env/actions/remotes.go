@@ -17,14 +17,18 @@ package actions
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sync"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
 	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/types"
 )
 
 var ErrCantFF = errors.New("can't fast forward merge")
+var ErrCantFFRemoteTrackingRef = errors.New("can't fast forward remote tracking ref")
 
 // Push will update a destination branch, in a given destination database if it can be done as a fast forward merge.
 // This is accomplished first by verifying that the remote tracking reference for the source database can be updated to
@@ -126,7 +130,7 @@ func FetchCommit(ctx context.Context, dEnv *env.DoltEnv, srcDB, destDB *doltdb.D
 		return err
 	}
 
-	return destDB.PullChunks(ctx, dEnv.TempTableFilesDir(), srcDB, stRef, progChan, pullerEventCh)
+	return pullChunks(ctx, dEnv, srcDB, destDB, stRef, progChan, pullerEventCh)
 }
 
 // FetchCommit takes a fetches a commit tag and all underlying data from a remote source database to the local destination database.
@@ -137,10 +141,297 @@ func FetchTag(ctx context.Context, dEnv *env.DoltEnv, srcDB, destDB *doltdb.Dolt
 		return err
 	}
 
+	return pullChunks(ctx, dEnv, srcDB, destDB, stRef, progChan, pullerEventCh)
+}
+
+// pullChunks pulls the chunks of the given ref into the destination database.  If the repository only holds the data
+// of some of its tables, only the data of those tables is pulled.
+func pullChunks(ctx context.Context, dEnv *env.DoltEnv, srcDB, destDB *doltdb.DoltDB, stRef types.Ref, progChan chan datas.PullProgress, pullerEventCh chan datas.PullerEvent) error {
+	if dEnv.IsSparse() {
+		return destDB.PullTableChunks(ctx, dEnv.TempTableFilesDir(), srcDB, stRef, dEnv.SparseTables(), pullerEventCh)
+	}
+
 	return destDB.PullChunks(ctx, dEnv.TempTableFilesDir(), srcDB, stRef, progChan, pullerEventCh)
 }
 
+// DiscardProgress returns channels for the progress of pulling chunks which are drained without reporting the
+// progress anywhere.  The returned function must be called once the pull is done.
+func DiscardProgress() (chan datas.PullProgress, chan datas.PullerEvent, func()) {
+	progChan := make(chan datas.PullProgress)
+	pullerEventCh := make(chan datas.PullerEvent)
+
+	wg := &sync.WaitGroup{}
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+		for range progChan {
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+		for range pullerEventCh {
+		}
+	}()
+
+	return progChan, pullerEventCh, func() {
+		close(progChan)
+		close(pullerEventCh)
+		wg.Wait()
+	}
+}
+
 // Clone pulls all data from a remote source database to a local destination database.
 func Clone(ctx context.Context, srcDB, destDB *doltdb.DoltDB, eventCh chan<- datas.TableFileEvent) error {
 	return srcDB.Clone(ctx, destDB, eventCh)
 }
+
+// CloneTables pulls every branch and tag from a remote source database into the database of the environment given, but
+// only pulls the row and index data of |tables|.  The data of all other tables will be retrieved from the remote named
+// |remoteName| when it is first accessed. A local branch is created for every branch in the source database.
+func CloneTables(ctx context.Context, dEnv *env.DoltEnv, remoteName string, tables []string, srcDB *doltdb.DoltDB, progChan chan datas.PullProgress, pullerEventCh chan datas.PullerEvent) error {
+	err := dEnv.SetSparse(remoteName, tables)
+
+	if err != nil {
+		return err
+	}
+
+	branches, err := srcDB.GetBranches(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	for _, branch := range branches {
+		cm, err := srcDB.ResolveRef(ctx, branch)
+
+		if err != nil {
+			return err
+		}
+
+		err = FetchCommit(ctx, dEnv, srcDB, dEnv.DoltDB, cm, progChan, pullerEventCh)
+
+		if err != nil {
+			return err
+		}
+
+		err = dEnv.DoltDB.SetHeadToCommit(ctx, branch, cm)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return IterResolvedTags(ctx, srcDB, func(tag *doltdb.Tag) (stop bool, err error) {
+		err = FetchTag(ctx, dEnv, srcDB, dEnv.DoltDB, tag, progChan, pullerEventCh)
+
+		if err != nil {
+			return true, err
+		}
+
+		stRef, err := tag.GetStRef()
+
+		if err != nil {
+			return true, err
+		}
+
+		err = dEnv.DoltDB.SetHead(ctx, tag.GetDoltRef(), stRef)
+
+		return err != nil, err
+	})
+}
+
+// FetchRefSpecs fetches every branch of |srcDB|, the database of the remote |rem|, which matches one of |refSpecs|, and
+// updates the remote tracking refs they map to.  Tags whose commits have been fetched are fetched as well.
+func FetchRefSpecs(ctx context.Context, dEnv *env.DoltEnv, mode ref.RefUpdateMode, rem env.Remote, srcDB *doltdb.DoltDB, refSpecs []ref.RemoteRefSpec, progChan chan datas.PullProgress, pullerEventCh chan datas.PullerEvent) error {
+	for _, rs := range refSpecs {
+		branchRefs, err := srcDB.GetRefs(ctx)
+
+		if err != nil {
+			return fmt.Errorf("failed to read from '%s': %w", rem.Name, err)
+		}
+
+		for _, branchRef := range branchRefs {
+			remoteTrackRef := rs.DestRef(branchRef)
+
+			if remoteTrackRef == nil {
+				continue
+			}
+
+			srcDBCommit, err := FetchRemoteBranch(ctx, dEnv, rem, srcDB, dEnv.DoltDB, branchRef, progChan, pullerEventCh)
+
+			if err != nil {
+				return err
+			}
+
+			switch mode {
+			case ref.ForceUpdate:
+				err = dEnv.DoltDB.SetHeadToCommit(ctx, remoteTrackRef, srcDBCommit)
+			case ref.FastForwardOnly:
+				var canFF bool
+				canFF, err = dEnv.DoltDB.CanFastForward(ctx, remoteTrackRef, srcDBCommit)
+				if err == doltdb.ErrUpToDate {
+					err = nil
+				} else if err == doltdb.ErrIsAhead || (err == nil && !canFF) {
+					return ErrCantFFRemoteTrackingRef
+				} else if err == nil {
+					err = dEnv.DoltDB.FastForward(ctx, remoteTrackRef, srcDBCommit)
+				}
+			}
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return FetchFollowTags(ctx, dEnv, srcDB, dEnv.DoltDB, progChan, pullerEventCh)
+}
+
+// FetchRemoteBranch fetches the commit |srcRef| refers to in |srcDB|, the database of the remote |rem|, along with all
+// the data it references, into |destDB|.
+func FetchRemoteBranch(ctx context.Context, dEnv *env.DoltEnv, rem env.Remote, srcDB, destDB *doltdb.DoltDB, srcRef ref.DoltRef, progChan chan datas.PullProgress, pullerEventCh chan datas.PullerEvent) (*doltdb.Commit, error) {
+	cs, _ := doltdb.NewCommitSpec(srcRef.String())
+	srcDBCommit, err := srcDB.Resolve(ctx, cs, nil)
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to find '%s' on '%s'", srcRef.GetPath(), rem.Name)
+	}
+
+	err = FetchCommit(ctx, dEnv, srcDB, destDB, srcDBCommit, progChan, pullerEventCh)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return srcDBCommit, nil
+}
+
+// FetchFollowTags fetches all tags from the source DB whose commits have already
+// been fetched into the destination DB.
+// todo: potentially too expensive to iterate over all srcDB tags
+func FetchFollowTags(ctx context.Context, dEnv *env.DoltEnv, srcDB, destDB *doltdb.DoltDB, progChan chan datas.PullProgress, pullerEventCh chan datas.PullerEvent) error {
+	return IterResolvedTags(ctx, srcDB, func(tag *doltdb.Tag) (stop bool, err error) {
+		stRef, err := tag.GetStRef()
+		if err != nil {
+			return true, err
+		}
+
+		tagHash := stRef.TargetHash()
+
+		hasTag, err := destDB.HasLocally(ctx, tagHash)
+		if err != nil {
+			return true, err
+		}
+		if hasTag {
+			// tag is already fetched
+			return false, nil
+		}
+
+		cmHash, err := tag.Commit.HashOf()
+		if err != nil {
+			return true, err
+		}
+
+		hasCommit, err := destDB.HasLocally(ctx, cmHash)
+		if err != nil {
+			return true, err
+		}
+		if !hasCommit {
+			// neither tag nor commit has been fetched
+			return false, nil
+		}
+
+		err = FetchTag(ctx, dEnv, srcDB, destDB, tag, progChan, pullerEventCh)
+
+		if err != nil {
+			return true, err
+		}
+
+		err = destDB.SetHead(ctx, tag.GetDoltRef(), stRef)
+
+		return false, err
+	})
+}
+
+// DisambiguateRefSpecStr converts the refs in a refspec to their full names if possible, preferring branches over tags.
+// eg "master" -> "refs/heads/master", "v1" -> "refs/tags/v1"
+func DisambiguateRefSpecStr(ctx context.Context, ddb *doltdb.DoltDB, refSpecStr string) (string, error) {
+	brachRefs, err := ddb.GetBranches(ctx)
+
+	if err != nil {
+		return "", err
+	}
+
+	for _, br := range brachRefs {
+		if br.GetPath() == refSpecStr {
+			return br.String(), nil
+		}
+	}
+
+	tagRefs, err := ddb.GetTags(ctx)
+
+	if err != nil {
+		return "", err
+	}
+
+	for _, tr := range tagRefs {
+		if tr.GetPath() == refSpecStr {
+			return tr.String(), nil
+		}
+	}
+
+	return refSpecStr, nil
+}
+
+// GetTrackingRef returns the remote tracking ref which the fetch specs of |remote| map the remote branch |branchRef|
+// to, or nil if none of them do.
+func GetTrackingRef(branchRef ref.DoltRef, remote env.Remote) (ref.DoltRef, error) {
+	for _, fsStr := range remote.FetchSpecs {
+		fs, err := ref.ParseRefSpecForRemote(remote.Name, fsStr)
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid fetch spec '%s' for remote '%s'", fsStr, remote.Name)
+		}
+
+		remoteRef := fs.DestRef(branchRef)
+
+		if remoteRef != nil {
+			return remoteRef, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// ParseRemoteRefSpecs parses refspecs given for the remote |remName|.  A branch name is shorthand for the refspec which
+// maps the branch to its remote tracking branch.
+func ParseRemoteRefSpecs(remName string, args []string) ([]ref.RemoteRefSpec, error) {
+	var refSpecs []ref.RemoteRefSpec
+	for _, rsStr := range args {
+		rs, err := ref.ParseRefSpec(rsStr)
+
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is not a valid refspec: %w", rsStr, err)
+		}
+
+		if _, ok := rs.(ref.BranchToBranchRefSpec); ok {
+			local := "refs/heads/" + rsStr
+			remTracking := "remotes/" + remName + "/" + rsStr
+			rs2, err := ref.ParseRefSpec(local + ":" + remTracking)
+
+			if err == nil {
+				rs = rs2
+			}
+		}
+
+		if rrs, ok := rs.(ref.RemoteRefSpec); !ok {
+			return nil, fmt.Errorf("'%s' is not a valid refspec referring to a remote tracking branch", rsStr)
+		} else {
+			refSpecs = append(refSpecs, rrs)
+		}
+	}
+
+	return refSpecs, nil
+}
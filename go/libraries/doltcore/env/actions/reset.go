@@ -0,0 +1,63 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+)
+
+// ResetHardTables returns the working root which results from resetting the |working| root to the |head| root.  Tables
+// which are untracked, i.e. in neither the |staged| root nor the head root, are kept.
+func ResetHardTables(ctx context.Context, working, staged, head *doltdb.RootValue) (*doltdb.RootValue, error) {
+	// need to save the state of files that aren't tracked
+	untrackedTables := make(map[string]*doltdb.Table)
+	wTblNames, err := working.GetTableNames(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, tblName := range wTblNames {
+		untrackedTables[tblName], _, err = working.GetTable(ctx, tblName)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	headTblNames, err := staged.GetTableNames(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, tblName := range headTblNames {
+		delete(untrackedTables, tblName)
+	}
+
+	newWkRoot := head
+	for tblName, tbl := range untrackedTables {
+		if tblName != doltdb.DocTableName {
+			newWkRoot, err = newWkRoot.PutTable(ctx, tblName, tbl)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return newWkRoot, nil
+}
@@ -94,17 +94,7 @@ func StageAllTables(ctx context.Context, dEnv *env.DoltEnv) error {
 }
 
 func stageTables(ctx context.Context, dEnv *env.DoltEnv, tbls []string, staged *doltdb.RootValue, working *doltdb.RootValue) error {
-	err := ValidateTables(ctx, tbls, staged, working)
-	if err != nil {
-		return err
-	}
-
-	working, err = checkTablesForConflicts(ctx, tbls, working)
-	if err != nil {
-		return err
-	}
-
-	staged, err = MoveTablesBetweenRoots(ctx, tbls, working, staged)
+	staged, working, err := StageTablesInRoots(ctx, tbls, staged, working)
 	if err != nil {
 		return err
 	}
@@ -125,6 +115,27 @@ func stageTables(ctx context.Context, dEnv *env.DoltEnv, tbls []string, staged *
 	return doltdb.ErrNomsIO
 }
 
+// StageTablesInRoots copies the tables |tbls| from the |working| root to the |staged| root, and returns the updated
+// staged and working roots.  Conflicts which have all been resolved are cleared from the working root.
+func StageTablesInRoots(ctx context.Context, tbls []string, staged, working *doltdb.RootValue) (*doltdb.RootValue, *doltdb.RootValue, error) {
+	err := ValidateTables(ctx, tbls, staged, working)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	working, err = checkTablesForConflicts(ctx, tbls, working)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	staged, err = MoveTablesBetweenRoots(ctx, tbls, working, staged)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return staged, working, nil
+}
+
 func checkTablesForConflicts(ctx context.Context, tbls []string, working *doltdb.RootValue) (*doltdb.RootValue, error) {
 	var inConflict []string
 	for _, tblName := range tbls {
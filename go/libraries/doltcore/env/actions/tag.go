@@ -31,9 +31,15 @@ type TagProps struct {
 }
 
 func CreateTag(ctx context.Context, dEnv *env.DoltEnv, tagName, startPoint string, props TagProps) error {
+	return CreateTagOnDB(ctx, dEnv.DoltDB, tagName, startPoint, props, dEnv.RepoState.CWBHeadRef())
+}
+
+// CreateTagOnDB creates the tag |tagName| for the commit |startPoint| resolves to.  |headRef| is the branch that HEAD
+// refers to when resolving |startPoint|.
+func CreateTagOnDB(ctx context.Context, ddb *doltdb.DoltDB, tagName, startPoint string, props TagProps, headRef ref.DoltRef) error {
 	tagRef := ref.NewTagRef(tagName)
 
-	hasRef, err := dEnv.DoltDB.HasRef(ctx, tagRef)
+	hasRef, err := ddb.HasRef(ctx, tagRef)
 
 	if err != nil {
 		return err
@@ -53,7 +59,7 @@ func CreateTag(ctx context.Context, dEnv *env.DoltEnv, tagName, startPoint strin
 		return err
 	}
 
-	cm, err := dEnv.DoltDB.Resolve(ctx, cs, dEnv.RepoState.CWBHeadRef())
+	cm, err := ddb.Resolve(ctx, cs, headRef)
 
 	if err != nil {
 		return err
@@ -61,14 +67,18 @@ func CreateTag(ctx context.Context, dEnv *env.DoltEnv, tagName, startPoint strin
 
 	meta := doltdb.NewTagMeta(props.TaggerName, props.TaggerEmail, props.Description)
 
-	return dEnv.DoltDB.NewTagAtCommit(ctx, tagRef, cm, meta)
+	return ddb.NewTagAtCommit(ctx, tagRef, cm, meta)
 }
 
 func DeleteTags(ctx context.Context, dEnv *env.DoltEnv, tagNames ...string) error {
+	return DeleteTagsOnDB(ctx, dEnv.DoltDB, tagNames...)
+}
+
+func DeleteTagsOnDB(ctx context.Context, ddb *doltdb.DoltDB, tagNames ...string) error {
 	for _, tn := range tagNames {
 		dref := ref.NewTagRef(tn)
 
-		hasRef, err := dEnv.DoltDB.HasRef(ctx, dref)
+		hasRef, err := ddb.HasRef(ctx, dref)
 
 		if err != nil {
 			return err
@@ -77,7 +87,7 @@ func DeleteTags(ctx context.Context, dEnv *env.DoltEnv, tagNames ...string) erro
 			return doltdb.ErrTagNotFound
 		}
 
-		err = dEnv.DoltDB.DeleteTag(ctx, dref)
+		err = ddb.DeleteTag(ctx, dref)
 
 		if err != nil {
 			return err
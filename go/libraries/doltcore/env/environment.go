@@ -51,6 +51,7 @@ const (
 	DefaultRemotesApiHost = "doltremoteapi.dolthub.com"
 	DefaultRemotesApiPort = "443"
 	tempTablesDir         = "temptf"
+	statisticsFile        = "statistics.json"
 )
 
 var ErrPreexistingDoltDir = errors.New(".dolt dir already exists")
@@ -58,6 +59,7 @@ var ErrStateUpdate = errors.New("error updating local data repo state")
 var ErrMarshallingSchema = errors.New("error marshalling schema")
 var ErrInvalidCredsFile = errors.New("invalid creds file")
 var ErrDocsUpdate = errors.New("error updating local docs")
+var ErrSparseRemoteNotFound = errors.New("the remote used to retrieve table data missing from this repository could not be found")
 
 // DoltEnv holds the state of the current environment used by the cli.
 type DoltEnv struct {
@@ -127,6 +129,10 @@ func Load(ctx context.Context, hdp HomeDirProvider, fs filesys.Filesys, urlStr,
 
 	dbfactory.InitializeFactories(dEnv)
 
+	if dbLoadErr == nil && rsErr == nil && repoState.Sparse != nil {
+		dEnv.DoltDB = ddb.WithLazyRemote(dEnv.openSparseRemote)
+	}
+
 	return dEnv
 }
 
@@ -382,6 +388,17 @@ func (r *repoStateWriter) SetWorkingHash(ctx context.Context, h hash.Hash) error
 	return nil
 }
 
+func (r *repoStateWriter) SetStagedHash(ctx context.Context, h hash.Hash) error {
+	r.dEnv.RepoState.Staged = h.String()
+	err := r.dEnv.RepoState.Save(r.dEnv.FS)
+
+	if err != nil {
+		return ErrStateUpdate
+	}
+
+	return nil
+}
+
 func (dEnv *DoltEnv) RepoStateWriter() RepoStateWriter {
 	return &repoStateWriter{dEnv}
 }
@@ -789,6 +806,54 @@ func (dEnv *DoltEnv) GetDefaultRemote() (Remote, errhand.VerboseError) {
 	return NoRemote, ErrCantDetermineDefault
 }
 
+// IsSparse returns true if the data of only some of the tables in this repository was cloned or fetched.
+func (dEnv *DoltEnv) IsSparse() bool {
+	return dEnv.RepoState != nil && dEnv.RepoState.Sparse != nil
+}
+
+// SparseTables returns the tables whose data is pulled when fetching from the remote. It returns nil if the repository
+// is not sparse.
+func (dEnv *DoltEnv) SparseTables() []string {
+	if !dEnv.IsSparse() {
+		return nil
+	}
+
+	return dEnv.RepoState.Sparse.Tables
+}
+
+// SetSparse marks the repository as only containing the data of |tables|, with the data of all other tables being
+// retrieved from the remote named |remoteName| on first access.
+func (dEnv *DoltEnv) SetSparse(remoteName string, tables []string) error {
+	if _, ok := dEnv.RepoState.Remotes[remoteName]; !ok {
+		return ErrSparseRemoteNotFound
+	}
+
+	wasSparse := dEnv.IsSparse()
+	dEnv.RepoState.Sparse = &SparseState{Remote: remoteName, Tables: tables}
+
+	err := dEnv.RepoState.Save(dEnv.FS)
+
+	if err != nil {
+		return ErrStateUpdate
+	}
+
+	if !wasSparse {
+		dEnv.DoltDB = dEnv.DoltDB.WithLazyRemote(dEnv.openSparseRemote)
+	}
+
+	return nil
+}
+
+func (dEnv *DoltEnv) openSparseRemote(ctx context.Context) (*doltdb.DoltDB, error) {
+	r, ok := dEnv.RepoState.Remotes[dEnv.RepoState.Sparse.Remote]
+
+	if !ok {
+		return nil, ErrSparseRemoteNotFound
+	}
+
+	return r.GetRemoteDB(ctx, types.Format_Default)
+}
+
 // GetUserHomeDir returns the user's home dir
 // based on current filesys
 func (dEnv *DoltEnv) GetUserHomeDir() (string, error) {
@@ -799,6 +864,12 @@ func (dEnv *DoltEnv) TempTableFilesDir() string {
 	return mustAbs(dEnv, dEnv.GetDoltDir(), tempTablesDir)
 }
 
+// StatisticsFile returns the path of the file holding the table statistics collected by ANALYZE TABLE, which aren't
+// versioned.
+func (dEnv *DoltEnv) StatisticsFile() string {
+	return mustAbs(dEnv, dEnv.GetDoltDir(), statisticsFile)
+}
+
 func (dEnv *DoltEnv) GetAllValidDocDetails() (docs []doltdb.DocDetails, err error) {
 	docs = []doltdb.DocDetails{}
 	for _, doc := range *AllValidDocDetails {
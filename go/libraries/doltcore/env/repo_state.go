@@ -35,7 +35,7 @@ type RepoStateWriter interface {
 	// SetCWBHeadRef(context.Context, ref.DoltRef) error
 	// SetCWBHeadSpec(context.Context, *doltdb.CommitSpec) error
 	SetWorkingHash(context.Context, hash.Hash) error
-	//	SetStagedHash(context.Context, hash.Hash) error
+	SetStagedHash(context.Context, hash.Hash) error
 }
 
 type BranchConfig struct {
@@ -48,6 +48,13 @@ type MergeState struct {
 	PreMergeWorking string `json:"working_pre_merge"`
 }
 
+// SparseState is stored in the repo state of repositories which were cloned or fetched with only a subset of their
+// tables' data.  Data for other tables is retrieved from Remote on first access.
+type SparseState struct {
+	Remote string   `json:"remote"`
+	Tables []string `json:"tables"`
+}
+
 type RepoState struct {
 	Head     ref.MarshalableRef      `json:"head"`
 	Staged   string                  `json:"staged"`
@@ -55,6 +62,7 @@ type RepoState struct {
 	Merge    *MergeState             `json:"merge"`
 	Remotes  map[string]Remote       `json:"remotes"`
 	Branches map[string]BranchConfig `json:"branches"`
+	Sparse   *SparseState            `json:"sparse,omitempty"`
 }
 
 func LoadRepoState(fs filesys.ReadWriteFS) (*RepoState, error) {
@@ -85,6 +93,7 @@ func CloneRepoState(fs filesys.ReadWriteFS, r Remote) (*RepoState, error) {
 		nil,
 		map[string]Remote{r.Name: r},
 		make(map[string]BranchConfig),
+		nil,
 	}
 
 	err := rs.Save(fs)
@@ -111,6 +120,7 @@ func CreateRepoState(fs filesys.ReadWriteFS, br string, rootHash hash.Hash) (*Re
 		nil,
 		make(map[string]Remote),
 		make(map[string]BranchConfig),
+		nil,
 	}
 
 	err = rs.Save(fs)
@@ -0,0 +1,96 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fulltext splits text into the tokens held by FULLTEXT indexes, and scores rows against a search using those
+// tokens. The rules follow those of InnoDB's built-in parser with its default settings.
+package fulltext
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// MinTokenLength is the length, in characters, of the shortest token which is indexed.
+	MinTokenLength = 3
+	// MaxTokenLength is the length, in characters, of the longest token which is indexed.
+	MaxTokenLength = 84
+)
+
+// stopwords are the words which are too common to be worth indexing, being InnoDB's default stopword list.
+var stopwords = map[string]struct{}{
+	"a": {}, "about": {}, "an": {}, "are": {}, "as": {}, "at": {}, "be": {}, "by": {}, "com": {}, "de": {}, "en": {},
+	"for": {}, "from": {}, "how": {}, "i": {}, "in": {}, "is": {}, "it": {}, "la": {}, "of": {}, "on": {}, "or": {},
+	"that": {}, "the": {}, "this": {}, "to": {}, "was": {}, "what": {}, "when": {}, "where": {}, "who": {}, "will": {},
+	"with": {}, "und": {}, "www": {},
+}
+
+// IsStopword returns whether the given lowercase word is never indexed.
+func IsStopword(word string) bool {
+	_, ok := stopwords[word]
+	return ok
+}
+
+// Words splits text into its words, which are runs of letters, digits and underscores, and returns those which are
+// indexed, lowercased and in the order they appear. A word appears once for each time it is found in the text.
+func Words(text string) []string {
+	var words []string
+	for _, word := range strings.FieldsFunc(text, isSeparator) {
+		word = strings.ToLower(word)
+		length := utf8.RuneCountInString(word)
+		if length < MinTokenLength || length > MaxTokenLength || IsStopword(word) {
+			continue
+		}
+		words = append(words, word)
+	}
+	return words
+}
+
+// Tokenize returns the distinct words of the text, in sorted order. These are the tokens held by a FULLTEXT index.
+func Tokenize(text string) []string {
+	counts := TermFrequencies(text)
+	tokens := make([]string, 0, len(counts))
+	for token := range counts {
+		tokens = append(tokens, token)
+	}
+	sort.Strings(tokens)
+	return tokens
+}
+
+// TermFrequencies returns the number of times each of the text's words is found in it.
+func TermFrequencies(text string) map[string]int {
+	counts := make(map[string]int)
+	for _, word := range Words(text) {
+		counts[word]++
+	}
+	return counts
+}
+
+// Relevance returns the relevance of a row to a single search token, as in InnoDB. termFrequency is the number of
+// times the token is found in the row, matchingRows is the number of rows containing the token, and totalRows is the
+// number of rows in the table. Tokens found in every row are not relevant to any of them.
+func Relevance(termFrequency int, matchingRows, totalRows uint64) float64 {
+	if termFrequency == 0 || matchingRows == 0 || totalRows == 0 {
+		return 0
+	}
+	idf := math.Log10(float64(totalRows) / float64(matchingRows))
+	return float64(termFrequency) * idf * idf
+}
+
+func isSeparator(r rune) bool {
+	return !(unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_')
+}
@@ -0,0 +1,55 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fulltext
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected []string
+	}{
+		{"", []string{}},
+		{"The quick brown fox", []string{"brown", "fox", "quick"}},
+		{"fox, FOX and Fox's den", []string{"and", "den", "fox"}},
+		{"go is a language", []string{"language"}},
+		{"snake_case and dash-separated", []string{"and", "dash", "separated", "snake_case"}},
+		{"über straße 100", []string{"100", "straße", "über"}},
+		{strings.Repeat("x", MaxTokenLength+1) + " yes", []string{"yes"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.text, func(t *testing.T) {
+			assert.Equal(t, test.expected, Tokenize(test.text))
+		})
+	}
+}
+
+func TestTermFrequencies(t *testing.T) {
+	assert.Equal(t, map[string]int{"dolt": 3, "data": 1}, TermFrequencies("Dolt dolt DOLT, it's data"))
+}
+
+func TestRelevance(t *testing.T) {
+	assert.Equal(t, 0.0, Relevance(0, 1, 10))
+	assert.Equal(t, 0.0, Relevance(1, 10, 10))
+	assert.InDelta(t, 1.0, Relevance(1, 1, 10), 1e-9)
+	assert.InDelta(t, 2.0, Relevance(2, 10, 100), 1e-9)
+	assert.Greater(t, Relevance(1, 1, 10), Relevance(1, 2, 10))
+}
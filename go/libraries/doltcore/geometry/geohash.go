@@ -0,0 +1,123 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package geometry
+
+import (
+	"fmt"
+	"strings"
+)
+
+const geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// MaxGeohashLength is the longest geohash which can be computed, as in MySQL.
+const MaxGeohashLength = 100
+
+// Geohash returns the geohash of a point whose X is a longitude and Y a latitude. Points close to one another share a
+// prefix of their geohashes, so an index on a column of geohashes finds the points within an area by prefix.
+func Geohash(p Point, length int) (string, error) {
+	if p.X < -180 || p.X > 180 {
+		return "", fmt.Errorf("longitude %v is out of range [-180, 180]", p.X)
+	}
+	if p.Y < -90 || p.Y > 90 {
+		return "", fmt.Errorf("latitude %v is out of range [-90, 90]", p.Y)
+	}
+	if length < 1 || length > MaxGeohashLength {
+		return "", fmt.Errorf("geohash length %d is out of range [1, %d]", length, MaxGeohashLength)
+	}
+
+	lon := [2]float64{-180, 180}
+	lat := [2]float64{-90, 90}
+	var sb strings.Builder
+	evenBit := true
+	for sb.Len() < length {
+		idx := 0
+		for bit := 0; bit < 5; bit++ {
+			// the bits alternate between halving the range of longitudes and that of latitudes
+			rng, val := &lat, p.Y
+			if evenBit {
+				rng, val = &lon, p.X
+			}
+			mid := (rng[0] + rng[1]) / 2
+			idx <<= 1
+			if val >= mid {
+				idx |= 1
+				rng[0] = mid
+			} else {
+				rng[1] = mid
+			}
+			evenBit = !evenBit
+		}
+		sb.WriteByte(geohashAlphabet[idx])
+	}
+	return sb.String(), nil
+}
+
+// SpatialCellLength is the length of the geohashes which spatial indexes file geometries under.
+const SpatialCellLength = 12
+
+// SpatialCell returns the geohash of the smallest cell, of at most SpatialCellLength characters, containing the whole
+// rectangle given. It's empty when no cell does, which is the case for rectangles outside the range of longitudes and
+// latitudes. Geometries whose bounds intersect are filed under cells which are a prefix of one another, so a spatial
+// index finds the candidates for a relation with a geometry by looking up the prefixes of its cell, and the cells
+// having its cell as a prefix.
+func SpatialCell(r Rect) string {
+	if !(r.MinX >= -180 && r.MaxX <= 180 && r.MinY >= -90 && r.MaxY <= 90) {
+		return ""
+	}
+	min, err := Geohash(Point{r.MinX, r.MinY}, SpatialCellLength)
+	if err != nil {
+		return ""
+	}
+	max, err := Geohash(Point{r.MaxX, r.MaxY}, SpatialCellLength)
+	if err != nil {
+		return ""
+	}
+	n := 0
+	for n < len(min) && min[n] == max[n] {
+		n++
+	}
+	return min[:n]
+}
+
+// GeohashBounds returns the area described by a geohash, whose points all have the geohash as a prefix of their own.
+func GeohashBounds(geohash string) (Rect, error) {
+	if len(geohash) == 0 || len(geohash) > MaxGeohashLength {
+		return Rect{}, fmt.Errorf("geohash length %d is out of range [1, %d]", len(geohash), MaxGeohashLength)
+	}
+
+	lon := [2]float64{-180, 180}
+	lat := [2]float64{-90, 90}
+	evenBit := true
+	for i := 0; i < len(geohash); i++ {
+		idx := strings.IndexByte(geohashAlphabet, geohash[i])
+		if idx < 0 {
+			return Rect{}, fmt.Errorf("invalid geohash '%s'", geohash)
+		}
+		for bit := 4; bit >= 0; bit-- {
+			rng := &lat
+			if evenBit {
+				rng = &lon
+			}
+			mid := (rng[0] + rng[1]) / 2
+			if idx&(1<<bit) != 0 {
+				rng[0] = mid
+			} else {
+				rng[1] = mid
+			}
+			evenBit = !evenBit
+		}
+	}
+	return Rect{lon[0], lat[0], lon[1], lat[1]}, nil
+}
@@ -0,0 +1,160 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package geometry implements the planar geometries of MySQL's spatial types: their WKT and WKB encodings, and the
+// relations between them used by the spatial SQL functions. All geometries use SRID 0, a flat Cartesian plane.
+package geometry
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	PointType      = "POINT"
+	LineStringType = "LINESTRING"
+	PolygonType    = "POLYGON"
+)
+
+// Geometry is a Point, LineString or Polygon.
+type Geometry interface {
+	// GeometryType returns the name of the geometry's type as it's written in WKT, such as POINT.
+	GeometryType() string
+	// Bounds returns the smallest rectangle containing the geometry.
+	Bounds() Rect
+	// String returns the geometry's WKT.
+	String() string
+}
+
+// Point is a single location.
+type Point struct {
+	X, Y float64
+}
+
+// LineString is a sequence of points joined by line segments.
+type LineString struct {
+	Points []Point
+}
+
+// Polygon is an area bounded by rings, which are closed LineStrings. The first ring is the polygon's exterior, and
+// any others are holes in it.
+type Polygon struct {
+	Rings []LineString
+}
+
+// Rect is an axis-aligned rectangle.
+type Rect struct {
+	MinX, MinY, MaxX, MaxY float64
+}
+
+var _ Geometry = Point{}
+var _ Geometry = LineString{}
+var _ Geometry = Polygon{}
+
+// GeometryType implements Geometry.
+func (p Point) GeometryType() string {
+	return PointType
+}
+
+// Bounds implements Geometry.
+func (p Point) Bounds() Rect {
+	return Rect{p.X, p.Y, p.X, p.Y}
+}
+
+// String implements Geometry.
+func (p Point) String() string {
+	return PointType + "(" + formatPoint(p) + ")"
+}
+
+// GeometryType implements Geometry.
+func (l LineString) GeometryType() string {
+	return LineStringType
+}
+
+// Bounds implements Geometry.
+func (l LineString) Bounds() Rect {
+	return boundsOf(l.Points)
+}
+
+// String implements Geometry.
+func (l LineString) String() string {
+	return LineStringType + formatPoints(l.Points)
+}
+
+// IsClosed returns whether the LineString ends where it starts.
+func (l LineString) IsClosed() bool {
+	return len(l.Points) > 0 && l.Points[0] == l.Points[len(l.Points)-1]
+}
+
+// GeometryType implements Geometry.
+func (p Polygon) GeometryType() string {
+	return PolygonType
+}
+
+// Bounds implements Geometry.
+func (p Polygon) Bounds() Rect {
+	if len(p.Rings) == 0 {
+		return boundsOf(nil)
+	}
+	return p.Rings[0].Bounds()
+}
+
+// String implements Geometry.
+func (p Polygon) String() string {
+	rings := make([]string, len(p.Rings))
+	for i, ring := range p.Rings {
+		rings[i] = formatPoints(ring.Points)
+	}
+	return PolygonType + "(" + strings.Join(rings, ",") + ")"
+}
+
+// Intersects returns whether the rectangles share any point.
+func (r Rect) Intersects(other Rect) bool {
+	return r.MinX <= other.MaxX && other.MinX <= r.MaxX && r.MinY <= other.MaxY && other.MinY <= r.MaxY
+}
+
+func boundsOf(points []Point) Rect {
+	if len(points) == 0 {
+		return Rect{math.NaN(), math.NaN(), math.NaN(), math.NaN()}
+	}
+	r := points[0].Bounds()
+	for _, p := range points[1:] {
+		r.MinX = math.Min(r.MinX, p.X)
+		r.MinY = math.Min(r.MinY, p.Y)
+		r.MaxX = math.Max(r.MaxX, p.X)
+		r.MaxY = math.Max(r.MaxY, p.Y)
+	}
+	return r
+}
+
+func formatPoint(p Point) string {
+	return formatCoord(p.X) + " " + formatCoord(p.Y)
+}
+
+func formatPoints(points []Point) string {
+	strs := make([]string, len(points))
+	for i, p := range points {
+		strs[i] = formatPoint(p)
+	}
+	return "(" + strings.Join(strs, ",") + ")"
+}
+
+// formatCoord formats coordinates without an exponent unless they're very large or small, as MySQL does.
+func formatCoord(f float64) string {
+	if abs := math.Abs(f); abs != 0 && (abs >= 1e15 || abs < 1e-6) {
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,199 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package geometry
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustParse(t *testing.T, wkt string) Geometry {
+	g, err := ParseWKT(wkt)
+	require.NoError(t, err, wkt)
+	return g
+}
+
+func TestParseWKT(t *testing.T) {
+	tests := []struct {
+		wkt      string
+		expected Geometry
+		formats  string
+	}{
+		{"POINT(1 2)", Point{1, 2}, "POINT(1 2)"},
+		{" point ( -1.5  2e3 ) ", Point{-1.5, 2000}, "POINT(-1.5 2000)"},
+		{"LineString(0 0, 1 1,2 0)", LineString{[]Point{{0, 0}, {1, 1}, {2, 0}}}, "LINESTRING(0 0,1 1,2 0)"},
+		{
+			"POLYGON((0 0,4 0,4 4,0 4,0 0),(1 1,2 1,2 2,1 1))",
+			Polygon{[]LineString{
+				{[]Point{{0, 0}, {4, 0}, {4, 4}, {0, 4}, {0, 0}}},
+				{[]Point{{1, 1}, {2, 1}, {2, 2}, {1, 1}}},
+			}},
+			"POLYGON((0 0,4 0,4 4,0 4,0 0),(1 1,2 1,2 2,1 1))",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.wkt, func(t *testing.T) {
+			g := mustParse(t, test.wkt)
+			assert.Equal(t, test.expected, g)
+			assert.Equal(t, test.formats, g.String())
+		})
+	}
+}
+
+func TestParseInvalidWKT(t *testing.T) {
+	tests := []string{
+		"",
+		"POINT",
+		"POINT(1)",
+		"POINT(1 2, 3 4)",
+		"POINT(1 2) extra",
+		"CIRCLE(1 2)",
+		"LINESTRING(1 2)",
+		"POLYGON((0 0,1 0,1 1))",
+		"POLYGON((0 0,1 0,1 1,0 1))",
+	}
+
+	for _, wkt := range tests {
+		t.Run(wkt, func(t *testing.T) {
+			_, err := ParseWKT(wkt)
+			assert.True(t, errors.Is(err, ErrInvalidGeometry), "%v", err)
+		})
+	}
+}
+
+func TestWKB(t *testing.T) {
+	for _, wkt := range []string{
+		"POINT(1 -2.5)",
+		"LINESTRING(0 0,1 1,2 0)",
+		"POLYGON((0 0,4 0,4 4,0 4,0 0),(1 1,2 1,2 2,1 1))",
+	} {
+		t.Run(wkt, func(t *testing.T) {
+			g := mustParse(t, wkt)
+			parsed, err := UnmarshalWKB(MarshalWKB(g))
+			require.NoError(t, err)
+			assert.Equal(t, g, parsed)
+
+			parsed, err = UnmarshalMySQL(MarshalMySQL(g))
+			require.NoError(t, err)
+			assert.Equal(t, g, parsed)
+		})
+	}
+
+	// POINT(1 2) in MySQL's internal format, as returned by SELECT HEX(ST_GeomFromText('POINT(1 2)'))
+	mysqlPoint, err := hex.DecodeString("000000000101000000000000000000F03F0000000000000040")
+	require.NoError(t, err)
+	assert.Equal(t, mysqlPoint, MarshalMySQL(Point{1, 2}))
+
+	bigEndian, err := hex.DecodeString("00000000013FF00000000000004000000000000000")
+	require.NoError(t, err)
+	g, err := UnmarshalWKB(bigEndian)
+	require.NoError(t, err)
+	assert.Equal(t, Point{1, 2}, g)
+
+	_, err = UnmarshalWKB(mysqlPoint[4:20])
+	assert.True(t, errors.Is(err, ErrInvalidGeometry))
+	_, err = UnmarshalWKB(append(MarshalWKB(Point{1, 2}), 0))
+	assert.True(t, errors.Is(err, ErrInvalidGeometry))
+	// a line string claiming more points than there are bytes for
+	_, err = UnmarshalWKB([]byte{1, 2, 0, 0, 0, 255, 255, 255, 255})
+	assert.True(t, errors.Is(err, ErrInvalidGeometry))
+}
+
+func TestRelations(t *testing.T) {
+	square := "POLYGON((0 0,4 0,4 4,0 4,0 0))"
+	donut := "POLYGON((0 0,4 0,4 4,0 4,0 0),(1 1,3 1,3 3,1 3,1 1))"
+
+	tests := []struct {
+		a, b     string
+		distance float64
+		contains bool
+	}{
+		{"POINT(0 0)", "POINT(3 4)", 5, false},
+		{"POINT(1 1)", "POINT(1 1)", 0, true},
+		{square, "POINT(2 2)", 0, true},
+		{square, "POINT(0 2)", 0, false},
+		{square, "POINT(7 8)", 5, false},
+		{donut, "POINT(2 2)", 1, false},
+		{donut, "POINT(0.5 0.5)", 0, true},
+		{square, "LINESTRING(1 1,3 3)", 0, true},
+		{square, "LINESTRING(0 0,4 0)", 0, false},
+		{square, "LINESTRING(1 1,5 1)", 0, false},
+		{donut, "LINESTRING(0.5 0.5,3.5 3.5)", 0, false},
+		{square, "POLYGON((1 1,2 1,2 2,1 2,1 1))", 0, true},
+		{square, square, 0, true},
+		{donut, "POLYGON((0.5 0.5,3.5 0.5,3.5 3.5,0.5 3.5,0.5 0.5))", 0, false},
+		{square, "POLYGON((6 0,7 0,7 4,6 4,6 0))", 2, false},
+		{"LINESTRING(0 0,10 0)", "POINT(5 0)", 0, true},
+		{"LINESTRING(0 0,10 0)", "POINT(0 0)", 0, false},
+		{"LINESTRING(0 0,10 0)", "POINT(5 3)", 3, false},
+		{"LINESTRING(0 0,10 0)", "LINESTRING(2 0,4 0)", 0, true},
+		{"LINESTRING(0 0,10 0)", "LINESTRING(5 -1,5 1)", 0, false},
+		{"LINESTRING(0 0,10 0)", "LINESTRING(12 3,16 3)", 3.605551275463989, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.a+" "+test.b, func(t *testing.T) {
+			a, b := mustParse(t, test.a), mustParse(t, test.b)
+			assert.InDelta(t, test.distance, Distance(a, b), 1e-9)
+			assert.InDelta(t, test.distance, Distance(b, a), 1e-9)
+			assert.Equal(t, test.contains, Contains(a, b))
+			assert.Equal(t, test.contains, Within(b, a))
+			assert.Equal(t, test.distance == 0, Intersects(a, b))
+		})
+	}
+}
+
+func TestGeohash(t *testing.T) {
+	gh, err := Geohash(Point{-5.6, 42.6}, 5)
+	require.NoError(t, err)
+	assert.Equal(t, "ezs42", gh)
+
+	gh, err = Geohash(Point{10.40744, 57.64911}, 11)
+	require.NoError(t, err)
+	assert.Equal(t, "u4pruydqqvj", gh)
+
+	r, err := GeohashBounds(gh[:5])
+	require.NoError(t, err)
+	assert.True(t, r.Intersects(Point{10.40744, 57.64911}.Bounds()))
+	assert.False(t, r.Intersects(Point{10.5, 57.64911}.Bounds()))
+
+	_, err = Geohash(Point{200, 0}, 5)
+	assert.Error(t, err)
+	_, err = Geohash(Point{0, 0}, 0)
+	assert.Error(t, err)
+	_, err = GeohashBounds("abc")
+	assert.Error(t, err)
+}
+
+func TestSpatialCell(t *testing.T) {
+	p := Point{10.40744, 57.64911}
+	assert.Equal(t, "u4pruydqqvj8", SpatialCell(p.Bounds()))
+
+	cell := SpatialCell(Rect{10.40744, 57.64911, 10.4075, 57.6492})
+	assert.Equal(t, "u4pruyd", cell)
+	r, err := GeohashBounds(cell)
+	require.NoError(t, err)
+	assert.True(t, r.Intersects(Rect{10.40744, 57.64911, 10.4075, 57.6492}))
+
+	// rectangles on either side of the equator share no cell but the whole world's
+	assert.Equal(t, "", SpatialCell(Rect{0, -1, 1, 1}))
+	assert.Equal(t, "", SpatialCell(Rect{1000, 0, 1001, 1}))
+	assert.Equal(t, "", SpatialCell(Polygon{}.Bounds()))
+}
@@ -0,0 +1,312 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package geometry
+
+import (
+	"math"
+)
+
+// where a point lies relative to an area or a line
+const (
+	outside  = -1
+	boundary = 0
+	inside   = 1
+)
+
+type segment struct {
+	a, b Point
+}
+
+// Distance returns the shortest distance between two geometries, which is 0 when they intersect.
+func Distance(g1, g2 Geometry) float64 {
+	if Intersects(g1, g2) {
+		return 0
+	}
+
+	// as the geometries don't intersect, the shortest distance is from a vertex of one to the other
+	pts1, segs1 := parts(g1)
+	pts2, segs2 := parts(g2)
+	dist := math.Inf(1)
+	for _, p := range pts1 {
+		dist = math.Min(dist, pointDistance(p, pts2, segs2))
+	}
+	for _, p := range pts2 {
+		dist = math.Min(dist, pointDistance(p, pts1, segs1))
+	}
+	return dist
+}
+
+// Intersects returns whether two geometries share any point.
+func Intersects(g1, g2 Geometry) bool {
+	if !g1.Bounds().Intersects(g2.Bounds()) {
+		return false
+	}
+
+	pts1, segs1 := parts(g1)
+	pts2, segs2 := parts(g2)
+	for _, s1 := range segs1 {
+		for _, s2 := range segs2 {
+			if segmentsIntersect(s1, s2) {
+				return true
+			}
+		}
+	}
+	for _, p := range pts1 {
+		if locate(p, g2) != outside {
+			return true
+		}
+	}
+	for _, p := range pts2 {
+		if locate(p, g1) != outside {
+			return true
+		}
+	}
+	return false
+}
+
+// Contains returns whether g2 lies entirely within g1, with at least one point of g2 in the interior of g1, as MySQL's
+// ST_Contains does. A line's interior excludes its end points, and a polygon's excludes its rings.
+func Contains(g1, g2 Geometry) bool {
+	if !g1.Bounds().Intersects(g2.Bounds()) {
+		return false
+	}
+
+	switch g1 := g1.(type) {
+	case Point:
+		p2, ok := g2.(Point)
+		return ok && g1 == p2
+	case LineString:
+		switch g2 := g2.(type) {
+		case Point:
+			return locate(g2, g1) == inside
+		case LineString:
+			return coveredBy(g2, g1) && hasInteriorPoint(g2, g1)
+		default:
+			return false
+		}
+	case Polygon:
+		if p2, ok := g2.(Point); ok {
+			return locate(p2, g1) == inside
+		}
+		_, segs1 := parts(g1)
+		_, segs2 := parts(g2)
+		for _, s1 := range segs1 {
+			for _, s2 := range segs2 {
+				if segmentsCross(s1, s2) {
+					return false
+				}
+			}
+		}
+		if p2, ok := g2.(Polygon); ok {
+			// a polygon can surround one of g1's holes without crossing its rings
+			for _, hole := range g1.Rings[1:] {
+				for _, p := range hole.Points {
+					if locate(p, p2) == inside {
+						return false
+					}
+				}
+			}
+			// the interior of a polygon covered by g1 is within the interior of g1
+			return coveredBy(g2, g1)
+		}
+		return coveredBy(g2, g1) && hasInteriorPoint(g2, g1)
+	}
+	return false
+}
+
+// Within returns whether g1 lies entirely within g2. It's the inverse of Contains.
+func Within(g1, g2 Geometry) bool {
+	return Contains(g2, g1)
+}
+
+// coveredBy returns whether each vertex of g, and the midpoint of each of its segments, isn't outside of other.
+func coveredBy(g Geometry, other Geometry) bool {
+	pts, segs := parts(g)
+	for _, p := range pts {
+		if locate(p, other) == outside {
+			return false
+		}
+	}
+	for _, s := range segs {
+		if locate(midpoint(s), other) == outside {
+			return false
+		}
+	}
+	return true
+}
+
+// hasInteriorPoint returns whether a vertex of g, or the midpoint of one of its segments, is in the interior of other.
+func hasInteriorPoint(g Geometry, other Geometry) bool {
+	pts, segs := parts(g)
+	for _, p := range pts {
+		if locate(p, other) == inside {
+			return true
+		}
+	}
+	for _, s := range segs {
+		if locate(midpoint(s), other) == inside {
+			return true
+		}
+	}
+	return false
+}
+
+// parts returns the vertices and segments of a geometry.
+func parts(g Geometry) ([]Point, []segment) {
+	switch g := g.(type) {
+	case Point:
+		return []Point{g}, nil
+	case LineString:
+		return g.Points, segments(g.Points)
+	case Polygon:
+		var pts []Point
+		var segs []segment
+		for _, ring := range g.Rings {
+			pts = append(pts, ring.Points...)
+			segs = append(segs, segments(ring.Points)...)
+		}
+		return pts, segs
+	}
+	return nil, nil
+}
+
+func segments(pts []Point) []segment {
+	var segs []segment
+	for i := 1; i < len(pts); i++ {
+		segs = append(segs, segment{pts[i-1], pts[i]})
+	}
+	return segs
+}
+
+// locate returns whether a point is inside, outside or on the boundary of a geometry.
+func locate(p Point, g Geometry) int {
+	switch g := g.(type) {
+	case Point:
+		if p == g {
+			return inside
+		}
+		return outside
+	case LineString:
+		if !g.IsClosed() && (p == g.Points[0] || p == g.Points[len(g.Points)-1]) {
+			return boundary
+		}
+		for _, s := range segments(g.Points) {
+			if onSegment(p, s) {
+				return inside
+			}
+		}
+		return outside
+	case Polygon:
+		loc := locateInRing(p, g.Rings[0])
+		if loc != inside {
+			return loc
+		}
+		for _, hole := range g.Rings[1:] {
+			switch locateInRing(p, hole) {
+			case inside:
+				return outside
+			case boundary:
+				return boundary
+			}
+		}
+		return inside
+	}
+	return outside
+}
+
+// locateInRing finds where a point lies relative to the area enclosed by a ring by counting the ring's crossings of a
+// ray cast from the point.
+func locateInRing(p Point, ring LineString) int {
+	in := false
+	for _, s := range segments(ring.Points) {
+		if onSegment(p, s) {
+			return boundary
+		}
+		if (s.a.Y > p.Y) != (s.b.Y > p.Y) {
+			x := s.a.X + (p.Y-s.a.Y)*(s.b.X-s.a.X)/(s.b.Y-s.a.Y)
+			if x > p.X {
+				in = !in
+			}
+		}
+	}
+	if in {
+		return inside
+	}
+	return outside
+}
+
+// orientation returns the sign of the cross product of (b - a) and (c - a): positive when c is to the left of the
+// line through a and b, negative when it's to the right, and 0 when the three points are collinear.
+func orientation(a, b, c Point) int {
+	cross := (b.X-a.X)*(c.Y-a.Y) - (b.Y-a.Y)*(c.X-a.X)
+	switch {
+	case cross > 0:
+		return 1
+	case cross < 0:
+		return -1
+	default:
+		return 0
+	}
+}
+
+func onSegment(p Point, s segment) bool {
+	return orientation(s.a, s.b, p) == 0 &&
+		math.Min(s.a.X, s.b.X) <= p.X && p.X <= math.Max(s.a.X, s.b.X) &&
+		math.Min(s.a.Y, s.b.Y) <= p.Y && p.Y <= math.Max(s.a.Y, s.b.Y)
+}
+
+// segmentsIntersect returns whether two segments share any point.
+func segmentsIntersect(s1, s2 segment) bool {
+	if segmentsCross(s1, s2) {
+		return true
+	}
+	return onSegment(s2.a, s1) || onSegment(s2.b, s1) || onSegment(s1.a, s2) || onSegment(s1.b, s2)
+}
+
+// segmentsCross returns whether two segments cross each other at a single point in the interior of both.
+func segmentsCross(s1, s2 segment) bool {
+	o1, o2 := orientation(s1.a, s1.b, s2.a), orientation(s1.a, s1.b, s2.b)
+	o3, o4 := orientation(s2.a, s2.b, s1.a), orientation(s2.a, s2.b, s1.b)
+	return o1*o2 < 0 && o3*o4 < 0
+}
+
+func midpoint(s segment) Point {
+	return Point{(s.a.X + s.b.X) / 2, (s.a.Y + s.b.Y) / 2}
+}
+
+// pointDistance returns the shortest distance from a point to a set of points and segments.
+func pointDistance(p Point, pts []Point, segs []segment) float64 {
+	dist := math.Inf(1)
+	if len(segs) == 0 {
+		for _, q := range pts {
+			dist = math.Min(dist, math.Hypot(p.X-q.X, p.Y-q.Y))
+		}
+	}
+	for _, s := range segs {
+		dist = math.Min(dist, segmentDistance(p, s))
+	}
+	return dist
+}
+
+func segmentDistance(p Point, s segment) float64 {
+	dx, dy := s.b.X-s.a.X, s.b.Y-s.a.Y
+	lenSq := dx*dx + dy*dy
+	if lenSq == 0 {
+		return math.Hypot(p.X-s.a.X, p.Y-s.a.Y)
+	}
+	// the projection of p onto the segment, clamped to its end points
+	t := math.Max(0, math.Min(1, ((p.X-s.a.X)*dx+(p.Y-s.a.Y)*dy)/lenSq))
+	return math.Hypot(p.X-(s.a.X+t*dx), p.Y-(s.a.Y+t*dy))
+}
@@ -0,0 +1,216 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package geometry
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// WKB geometry type codes
+const (
+	wkbPoint      uint32 = 1
+	wkbLineString uint32 = 2
+	wkbPolygon    uint32 = 3
+)
+
+const (
+	wkbBigEndian    byte = 0
+	wkbLittleEndian byte = 1
+)
+
+// MarshalWKB returns the geometry's well-known binary, in little endian byte order.
+func MarshalWKB(g Geometry) []byte {
+	w := &wkbWriter{}
+	w.byte(wkbLittleEndian)
+	switch g := g.(type) {
+	case Point:
+		w.uint32(wkbPoint)
+		w.point(g)
+	case LineString:
+		w.uint32(wkbLineString)
+		w.points(g.Points)
+	case Polygon:
+		w.uint32(wkbPolygon)
+		w.uint32(uint32(len(g.Rings)))
+		for _, ring := range g.Rings {
+			w.points(ring.Points)
+		}
+	default:
+		panic(fmt.Sprintf("unexpected geometry %T", g))
+	}
+	return w.buf
+}
+
+// UnmarshalWKB parses well-known binary in either byte order.
+func UnmarshalWKB(wkb []byte) (Geometry, error) {
+	r := &wkbReader{buf: wkb}
+	g, err := r.geometry()
+	if err != nil {
+		return nil, err
+	}
+	if len(r.buf) != 0 {
+		return nil, fmt.Errorf("%w: %d unexpected bytes after the geometry", ErrInvalidGeometry, len(r.buf))
+	}
+	return g, nil
+}
+
+// MarshalMySQL returns the geometry in MySQL's internal format, which is its WKB prefixed with its SRID as a 4 byte
+// little endian integer. This is how values of spatial types are sent to clients.
+func MarshalMySQL(g Geometry) []byte {
+	return append([]byte{0, 0, 0, 0}, MarshalWKB(g)...)
+}
+
+// UnmarshalMySQL parses a geometry in MySQL's internal format. Only SRID 0 is supported.
+func UnmarshalMySQL(b []byte) (Geometry, error) {
+	if len(b) < 4 {
+		return nil, fmt.Errorf("%w: too short", ErrInvalidGeometry)
+	}
+	if srid := binary.LittleEndian.Uint32(b); srid != 0 {
+		return nil, fmt.Errorf("%w: SRID %d is not supported", ErrInvalidGeometry, srid)
+	}
+	return UnmarshalWKB(b[4:])
+}
+
+type wkbWriter struct {
+	buf []byte
+}
+
+func (w *wkbWriter) byte(b byte) {
+	w.buf = append(w.buf, b)
+}
+
+func (w *wkbWriter) uint32(n uint32) {
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], n)
+	w.buf = append(w.buf, b[:]...)
+}
+
+func (w *wkbWriter) point(p Point) {
+	var b [16]byte
+	binary.LittleEndian.PutUint64(b[:8], math.Float64bits(p.X))
+	binary.LittleEndian.PutUint64(b[8:], math.Float64bits(p.Y))
+	w.buf = append(w.buf, b[:]...)
+}
+
+func (w *wkbWriter) points(pts []Point) {
+	w.uint32(uint32(len(pts)))
+	for _, p := range pts {
+		w.point(p)
+	}
+}
+
+type wkbReader struct {
+	buf   []byte
+	order binary.ByteOrder
+}
+
+func (r *wkbReader) read(n int) ([]byte, error) {
+	if len(r.buf) < n {
+		return nil, fmt.Errorf("%w: unexpected end of WKB", ErrInvalidGeometry)
+	}
+	b := r.buf[:n]
+	r.buf = r.buf[n:]
+	return b, nil
+}
+
+func (r *wkbReader) uint32() (uint32, error) {
+	b, err := r.read(4)
+	if err != nil {
+		return 0, err
+	}
+	return r.order.Uint32(b), nil
+}
+
+func (r *wkbReader) point() (Point, error) {
+	b, err := r.read(16)
+	if err != nil {
+		return Point{}, err
+	}
+	return Point{math.Float64frombits(r.order.Uint64(b[:8])), math.Float64frombits(r.order.Uint64(b[8:]))}, nil
+}
+
+func (r *wkbReader) points() ([]Point, error) {
+	n, err := r.uint32()
+	if err != nil {
+		return nil, err
+	}
+	// each point takes 16 bytes, which bounds the count before anything is allocated for it
+	if uint64(n)*16 > uint64(len(r.buf)) {
+		return nil, fmt.Errorf("%w: unexpected end of WKB", ErrInvalidGeometry)
+	}
+	pts := make([]Point, n)
+	for i := range pts {
+		if pts[i], err = r.point(); err != nil {
+			return nil, err
+		}
+	}
+	return pts, nil
+}
+
+func (r *wkbReader) geometry() (Geometry, error) {
+	b, err := r.read(1)
+	if err != nil {
+		return nil, err
+	}
+	switch b[0] {
+	case wkbLittleEndian:
+		r.order = binary.LittleEndian
+	case wkbBigEndian:
+		r.order = binary.BigEndian
+	default:
+		return nil, fmt.Errorf("%w: unknown byte order %d", ErrInvalidGeometry, b[0])
+	}
+
+	typ, err := r.uint32()
+	if err != nil {
+		return nil, err
+	}
+	switch typ {
+	case wkbPoint:
+		return r.point()
+	case wkbLineString:
+		pts, err := r.points()
+		if err != nil {
+			return nil, err
+		}
+		l := LineString{pts}
+		if err := validateLineString(l); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidGeometry, err.Error())
+		}
+		return l, nil
+	case wkbPolygon:
+		n, err := r.uint32()
+		if err != nil {
+			return nil, err
+		}
+		var rings []LineString
+		for i := uint32(0); i < n; i++ {
+			pts, err := r.points()
+			if err != nil {
+				return nil, err
+			}
+			rings = append(rings, LineString{pts})
+		}
+		poly := Polygon{rings}
+		if err := validatePolygon(poly); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidGeometry, err.Error())
+		}
+		return poly, nil
+	default:
+		return nil, fmt.Errorf("%w: unsupported WKB geometry type %d", ErrInvalidGeometry, typ)
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package geometry
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ErrInvalidGeometry is returned for WKT or WKB which doesn't describe a valid geometry.
+var ErrInvalidGeometry = errors.New("invalid GIS data")
+
+// ParseWKT parses the well-known text of a Point, LineString or Polygon, such as POINT(1 2).
+func ParseWKT(wkt string) (Geometry, error) {
+	p := &wktParser{s: wkt}
+	name := strings.ToUpper(p.word())
+
+	var g Geometry
+	var err error
+	switch name {
+	case PointType:
+		var pts []Point
+		pts, err = p.points()
+		if err == nil && len(pts) != 1 {
+			err = p.errorf("a point has a single coordinate pair")
+		}
+		if err == nil {
+			g = pts[0]
+		}
+	case LineStringType:
+		g, err = p.lineString()
+	case PolygonType:
+		g, err = p.polygon()
+	case "":
+		return nil, p.errorf("expected a geometry type")
+	default:
+		return nil, p.errorf("unsupported geometry type %s", name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpace()
+	if p.pos < len(p.s) {
+		return nil, p.errorf("unexpected text after the geometry")
+	}
+	return g, nil
+}
+
+type wktParser struct {
+	s   string
+	pos int
+}
+
+func (p *wktParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s at position %d of '%s'", ErrInvalidGeometry, fmt.Sprintf(format, args...), p.pos, p.s)
+}
+
+func (p *wktParser) skipSpace() {
+	for p.pos < len(p.s) && unicode.IsSpace(rune(p.s[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *wktParser) word() string {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.s) && unicode.IsLetter(rune(p.s[p.pos])) {
+		p.pos++
+	}
+	return p.s[start:p.pos]
+}
+
+func (p *wktParser) expect(c byte) error {
+	p.skipSpace()
+	if p.pos >= len(p.s) || p.s[p.pos] != c {
+		return p.errorf("expected '%c'", c)
+	}
+	p.pos++
+	return nil
+}
+
+func (p *wktParser) peek(c byte) bool {
+	p.skipSpace()
+	return p.pos < len(p.s) && p.s[p.pos] == c
+}
+
+func (p *wktParser) number() (float64, error) {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.s) && strings.IndexByte("+-.0123456789eE", p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+	f, err := strconv.ParseFloat(p.s[start:p.pos], 64)
+	if err != nil {
+		p.pos = start
+		return 0, p.errorf("expected a number")
+	}
+	return f, nil
+}
+
+// points parses a parenthesized list of coordinate pairs separated by commas.
+func (p *wktParser) points() ([]Point, error) {
+	if err := p.expect('('); err != nil {
+		return nil, err
+	}
+	var pts []Point
+	for {
+		x, err := p.number()
+		if err != nil {
+			return nil, err
+		}
+		y, err := p.number()
+		if err != nil {
+			return nil, err
+		}
+		pts = append(pts, Point{x, y})
+		if !p.peek(',') {
+			break
+		}
+		p.pos++
+	}
+	if err := p.expect(')'); err != nil {
+		return nil, err
+	}
+	return pts, nil
+}
+
+func (p *wktParser) lineString() (LineString, error) {
+	pts, err := p.points()
+	if err != nil {
+		return LineString{}, err
+	}
+	l := LineString{pts}
+	if err := validateLineString(l); err != nil {
+		return LineString{}, p.errorf("%s", err.Error())
+	}
+	return l, nil
+}
+
+func (p *wktParser) polygon() (Polygon, error) {
+	if err := p.expect('('); err != nil {
+		return Polygon{}, err
+	}
+	var rings []LineString
+	for {
+		pts, err := p.points()
+		if err != nil {
+			return Polygon{}, err
+		}
+		rings = append(rings, LineString{pts})
+		if !p.peek(',') {
+			break
+		}
+		p.pos++
+	}
+	if err := p.expect(')'); err != nil {
+		return Polygon{}, err
+	}
+	poly := Polygon{rings}
+	if err := validatePolygon(poly); err != nil {
+		return Polygon{}, p.errorf("%s", err.Error())
+	}
+	return poly, nil
+}
+
+func validateLineString(l LineString) error {
+	if len(l.Points) < 2 {
+		return errors.New("a linestring has at least two points")
+	}
+	return nil
+}
+
+func validatePolygon(poly Polygon) error {
+	if len(poly.Rings) == 0 {
+		return errors.New("a polygon has at least one ring")
+	}
+	for _, ring := range poly.Rings {
+		if len(ring.Points) < 4 {
+			return errors.New("a polygon's rings have at least four points")
+		}
+		if !ring.IsClosed() {
+			return errors.New("a polygon's rings end where they start")
+		}
+	}
+	return nil
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/doltcore/row"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
 	"github.com/dolthub/dolt/go/libraries/utils/valutil"
 	"github.com/dolthub/dolt/go/store/atomicerr"
 	"github.com/dolthub/dolt/go/store/hash"
@@ -175,6 +176,12 @@ func (merger *Merger) MergeTable(ctx context.Context, tblName string, tableEditS
 		return nil, nil, err
 	}
 
+	updatedTbl, err = mergeAutoIncrementValues(updatedTbl, mergeTbl)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
 	err = tableEditSession.UpdateRoot(ctx, func(ctx context.Context, root *doltdb.RootValue) (*doltdb.RootValue, error) {
 		return root.PutTable(ctx, tblName, updatedTbl)
 	})
@@ -191,6 +198,14 @@ func (merger *Merger) MergeTable(ctx context.Context, tblName string, tableEditS
 		return nil, nil, err
 	}
 
+	// rows merged in were validated against the merged checks as they were written, but rows on our side were not
+	if !tblSchema.Checks().Equals(postMergeSchema.Checks()) {
+		err = doltdb.ValidateCheckConstraints(ctx, mergedTable)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
 	if conflicts.Len() > 0 {
 
 		asr, err := ancTbl.GetSchemaRef()
@@ -218,6 +233,26 @@ func (merger *Merger) MergeTable(ctx context.Context, tblName string, tableEditS
 	return mergedTable, stats, nil
 }
 
+// mergeAutoIncrementValues sets the AUTO_INCREMENT counter of |tbl| to the larger of its own and that of |mergeTbl|, so
+// that values used on either branch are not given out again.
+func mergeAutoIncrementValues(tbl, mergeTbl *doltdb.Table) (*doltdb.Table, error) {
+	ours, err := tbl.GetAutoIncrementValue()
+	if err != nil {
+		return nil, err
+	}
+
+	theirs, err := mergeTbl.GetAutoIncrementValue()
+	if err != nil {
+		return nil, err
+	}
+
+	if theirs <= ours {
+		return tbl, nil
+	}
+
+	return tbl.SetAutoIncrementValue(theirs)
+}
+
 func calcTableMergeStats(ctx context.Context, tbl *doltdb.Table, mergeTbl *doltdb.Table) (MergeStats, error) {
 	rows, err := tbl.GetRowData(ctx)
 
@@ -502,23 +537,27 @@ func rowMerge(ctx context.Context, nbf *types.NomsBinFormat, sch schema.Schema,
 		return nil, false, err
 	}
 
-	processTagFunc := func(tag uint64) (resultVal types.Value, isConflict bool) {
+	processTagFunc := func(tag uint64, col schema.Column) (resultVal types.Value, isConflict bool, err error) {
 		baseVal, _ := baseVals.Get(tag)
 		val, _ := rowVals.Get(tag)
 		mergeVal, _ := mergeVals.Get(tag)
 
 		if valutil.NilSafeEqCheck(val, mergeVal) {
-			return val, false
+			return val, false, nil
 		} else {
 			modified := !valutil.NilSafeEqCheck(val, baseVal)
 			mergeModified := !valutil.NilSafeEqCheck(mergeVal, baseVal)
 			switch {
 			case modified && mergeModified:
-				return nil, true
+				if col.TypeInfo.GetTypeIdentifier() == typeinfo.JSONTypeIdentifier {
+					// JSON documents changed on both sides merge cleanly if different keys were changed
+					return typeinfo.MergeJSON(baseVal, val, mergeVal)
+				}
+				return nil, true, nil
 			case modified:
-				return val, false
+				return val, false, nil
 			default:
-				return mergeVal, false
+				return mergeVal, false, nil
 			}
 		}
 
@@ -527,9 +566,12 @@ func rowMerge(ctx context.Context, nbf *types.NomsBinFormat, sch schema.Schema,
 	resultVals := make(row.TaggedValues)
 
 	var isConflict bool
-	err = sch.GetNonPKCols().Iter(func(tag uint64, _ schema.Column) (stop bool, err error) {
+	err = sch.GetNonPKCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
 		var val types.Value
-		val, isConflict = processTagFunc(tag)
+		val, isConflict, err = processTagFunc(tag, col)
+		if err != nil {
+			return true, err
+		}
 		resultVals[tag] = val
 
 		return isConflict, nil
@@ -34,12 +34,13 @@ type SchemaConflict struct {
 	TableName    string
 	ColConflicts []ColConflict
 	IdxConflicts []IdxConflict
+	ChkConflicts []ChkConflict
 }
 
 var EmptySchConflicts = SchemaConflict{}
 
 func (sc SchemaConflict) Count() int {
-	return len(sc.ColConflicts) + len(sc.IdxConflicts)
+	return len(sc.ColConflicts) + len(sc.IdxConflicts) + len(sc.ChkConflicts)
 }
 
 func (sc SchemaConflict) AsError() error {
@@ -51,6 +52,9 @@ func (sc SchemaConflict) AsError() error {
 	for _, c := range sc.IdxConflicts {
 		b.WriteString(fmt.Sprintf("\t%s\n", c.String()))
 	}
+	for _, c := range sc.ChkConflicts {
+		b.WriteString(fmt.Sprintf("\t%s\n", c.String()))
+	}
 	return fmt.Errorf(b.String())
 }
 
@@ -78,6 +82,14 @@ func (c IdxConflict) String() string {
 	return ""
 }
 
+type ChkConflict struct {
+	Ours, Theirs schema.Check
+}
+
+func (c ChkConflict) String() string {
+	return fmt.Sprintf("different definitions for check constraint '%s'", c.Ours.Name)
+}
+
 type FKConflict struct {
 	Kind         conflictKind
 	Ours, Theirs doltdb.ForeignKey
@@ -106,6 +118,12 @@ func SchemaMerge(ourSch, theirSch, ancSch schema.Schema, tblName string) (sch sc
 		return nil, sc, nil
 	}
 
+	var mergedChks []schema.Check
+	mergedChks, sc.ChkConflicts = mergeChecks(ourSch.Checks(), theirSch.Checks(), ancSch.Checks())
+	if len(sc.ChkConflicts) > 0 {
+		return nil, sc, nil
+	}
+
 	sch, err = schema.SchemaFromCols(mergedCC)
 	if err != nil {
 		return nil, sc, err
@@ -114,6 +132,7 @@ func SchemaMerge(ourSch, theirSch, ancSch schema.Schema, tblName string) (sch sc
 		sch.Indexes().AddIndex(index)
 		return false, nil
 	})
+	sch.Checks().AddChecks(mergedChks...)
 
 	return sch, sc, nil
 }
@@ -320,6 +339,56 @@ func mergeIndexes(mergedCC *schema.ColCollection, ourSch, theirSch, ancSch schem
 	return merged, conflicts
 }
 
+// mergeChecks performs a three-way merge of the check constraints of each schema. A check that was added, dropped or
+// redefined on only one side since the ancestor takes that side's definition, and one that was changed differently on
+// both sides is a conflict.
+func mergeChecks(ours, theirs, anc schema.CheckCollection) (merged []schema.Check, conflicts []ChkConflict) {
+	var names []string
+	seen := make(map[string]bool)
+	for _, coll := range []schema.CheckCollection{ours, theirs, anc} {
+		for _, chk := range coll.AllChecks() {
+			name := strings.ToLower(chk.Name)
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+
+	for _, name := range names {
+		ourChk, inOurs := ours.GetByNameCaseInsensitive(name)
+		theirChk, inTheirs := theirs.GetByNameCaseInsensitive(name)
+		ancChk, inAnc := anc.GetByNameCaseInsensitive(name)
+
+		ourChanged := inOurs != inAnc || ourChk != ancChk
+		theirChanged := inTheirs != inAnc || theirChk != ancChk
+
+		switch {
+		case inOurs == inTheirs && ourChk == theirChk:
+			if inOurs {
+				merged = append(merged, ourChk)
+			}
+		case !theirChanged:
+			if inOurs {
+				merged = append(merged, ourChk)
+			}
+		case !ourChanged:
+			if inTheirs {
+				merged = append(merged, theirChk)
+			}
+		default:
+			if !inOurs {
+				ourChk.Name = theirChk.Name
+			} else if !inTheirs {
+				theirChk.Name = ourChk.Name
+			}
+			conflicts = append(conflicts, ChkConflict{Ours: ourChk, Theirs: theirChk})
+		}
+	}
+
+	return merged, conflicts
+}
+
 func indexesInCommon(mergedCC *schema.ColCollection, ours, theirs, anc schema.IndexCollection) (common schema.IndexCollection, conflicts []IdxConflict) {
 	common = schema.NewIndexCollection(mergedCC)
 	_ = ours.Iter(func(ourIdx schema.Index) (stop bool, err error) {
@@ -333,7 +402,7 @@ func indexesInCommon(mergedCC *schema.ColCollection, ours, theirs, anc schema.In
 			}
 		}
 
-		theirIdx, ok := theirs.GetIndexByTags(idxTags...)
+		theirIdx, ok := theirs.GetIndexLike(ourIdx)
 		if !ok {
 			return false, nil
 		}
@@ -343,7 +412,7 @@ func indexesInCommon(mergedCC *schema.ColCollection, ours, theirs, anc schema.In
 			return false, nil
 		}
 
-		ancIdx, ok := anc.GetIndexByTags(idxTags...)
+		ancIdx, ok := anc.GetIndexLike(ourIdx)
 
 		if !ok {
 			// index added on our branch and their branch with different defs, conflict
@@ -407,7 +476,7 @@ func indexCollSetDifference(left, right schema.IndexCollection, cc *schema.ColCo
 			}
 		}
 
-		_, ok := right.GetIndexByTags(idxTags...)
+		_, ok := right.GetIndexLike(idx)
 		if !ok {
 			d.AddIndex(idx)
 		}
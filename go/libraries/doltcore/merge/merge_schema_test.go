@@ -245,6 +245,36 @@ var mergeSchemaTests = []mergeSchemaTest{
 			schema.NewIndex("c3_idx", []uint64{4696}, []uint64{4696, 3228}, nil, schema.IndexProperties{IsUserDefined: true}),
 		),
 	},
+	{
+		name: "add checks, drop check, merge",
+		setup: []testCommand{
+			{commands.SqlCmd{}, []string{"-q", "alter table test add constraint c1_positive check (c1 > 0);"}},
+			{commands.SqlCmd{}, []string{"-q", "alter table test add constraint c2_positive check (c2 > 0);"}},
+			{commands.AddCmd{}, []string{"."}},
+			{commands.CommitCmd{}, []string{"-m", "modified branch master"}},
+			{commands.CheckoutCmd{}, []string{"other"}},
+			{commands.SqlCmd{}, []string{"-q", "alter table test add constraint c3_small check (c3 < 100);"}},
+			{commands.SqlCmd{}, []string{"-q", "alter table test add constraint c2_positive check (c2 > 0);"}},
+			{commands.AddCmd{}, []string{"."}},
+			{commands.CommitCmd{}, []string{"-m", "modified branch other"}},
+			{commands.SqlCmd{}, []string{"-q", "alter table test drop check c2_positive;"}},
+			{commands.AddCmd{}, []string{"."}},
+			{commands.CommitCmd{}, []string{"-m", "dropped check on branch other"}},
+			{commands.CheckoutCmd{}, []string{"master"}},
+		},
+		sch: schemaWithChecks(schemaFromColsAndIdxs(
+			colCollection(
+				newColTypeInfo("pk", uint64(3228), typeinfo.Int32Type, true, schema.NotNullConstraint{}),
+				newColTypeInfo("c1", uint64(8201), typeinfo.Int32Type, false, schema.NotNullConstraint{}),
+				newColTypeInfo("c2", uint64(8539), typeinfo.Int32Type, false),
+				newColTypeInfo("c3", uint64(4696), typeinfo.Int32Type, false)),
+			schema.NewIndex("c1_idx", []uint64{8201}, []uint64{8201, 3228}, nil, schema.IndexProperties{IsUserDefined: true}),
+		),
+			schema.Check{Name: "c1_positive", Expression: "c1 > 0", Enforced: true},
+			schema.Check{Name: "c2_positive", Expression: "c2 > 0", Enforced: true},
+			schema.Check{Name: "c3_small", Expression: "c3 < 100", Enforced: true},
+		),
+	},
 }
 
 var mergeSchemaConflictTests = []mergeSchemaConflictTest{
@@ -361,6 +391,28 @@ var mergeSchemaConflictTests = []mergeSchemaConflictTest{
 			},
 		},
 	},
+	{
+		name: "check definition collision",
+		setup: []testCommand{
+			{commands.SqlCmd{}, []string{"-q", "alter table test add constraint c2_check check (c2 > 0);"}},
+			{commands.AddCmd{}, []string{"."}},
+			{commands.CommitCmd{}, []string{"-m", "modified branch master"}},
+			{commands.CheckoutCmd{}, []string{"other"}},
+			{commands.SqlCmd{}, []string{"-q", "alter table test add constraint c2_check check (c2 < 0);"}},
+			{commands.AddCmd{}, []string{"."}},
+			{commands.CommitCmd{}, []string{"-m", "modified branch other"}},
+			{commands.CheckoutCmd{}, []string{"master"}},
+		},
+		expConflict: merge.SchemaConflict{
+			TableName: "test",
+			ChkConflicts: []merge.ChkConflict{
+				{
+					Ours:   schema.Check{Name: "c2_check", Expression: "c2 > 0", Enforced: true},
+					Theirs: schema.Check{Name: "c2_check", Expression: "c2 < 0", Enforced: true},
+				},
+			},
+		},
+	},
 }
 
 var setupForeignKeyTests = []testCommand{
@@ -435,6 +487,11 @@ func schemaFromColsAndIdxs(allCols *schema.ColCollection, indexes ...schema.Inde
 	return sch
 }
 
+func schemaWithChecks(sch schema.Schema, checks ...schema.Check) schema.Schema {
+	sch.Checks().AddChecks(checks...)
+	return sch
+}
+
 func newColTypeInfo(name string, tag uint64, typeInfo typeinfo.TypeInfo, partOfPK bool, constraints ...schema.ColConstraint) schema.Column {
 	c, err := schema.NewColumnWithTypeInfo(name, tag, typeInfo, partOfPK, "", false, "", constraints...)
 	if err != nil {
@@ -481,6 +538,7 @@ func testMergeSchemas(t *testing.T, test mergeSchemaTest) {
 
 	assert.Equal(t, test.sch.GetAllCols(), sch.GetAllCols())
 	assert.Equal(t, test.sch.Indexes(), sch.Indexes())
+	assert.Equal(t, test.sch.Checks().AllChecks(), sch.Checks().AllChecks())
 }
 
 func testMergeSchemasWithConflicts(t *testing.T, test mergeSchemaConflictTest) {
@@ -538,6 +596,8 @@ func testMergeSchemasWithConflicts(t *testing.T, test mergeSchemaConflictTest) {
 		assert.True(t, test.expConflict.IdxConflicts[i].Ours.Equals(icc.Ours))
 		assert.True(t, test.expConflict.IdxConflicts[i].Theirs.Equals(icc.Theirs))
 	}
+
+	assert.Equal(t, test.expConflict.ChkConflicts, actConflicts.ChkConflicts)
 }
 
 func testMergeForeignKeys(t *testing.T, test mergeForeignKeyTest) {
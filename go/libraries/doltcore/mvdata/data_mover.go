@@ -22,6 +22,7 @@ import (
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
 	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
 	sqleSchema "github.com/dolthub/dolt/go/libraries/doltcore/sqle/schema"
 
 	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
@@ -33,6 +34,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/pipeline"
 	"github.com/dolthub/dolt/go/libraries/utils/filesys"
 	"github.com/dolthub/dolt/go/libraries/utils/set"
+	"github.com/dolthub/dolt/go/store/types"
 )
 
 type CsvOptions struct {
@@ -195,15 +197,17 @@ func MoveData(ctx context.Context, dEnv *env.DoltEnv, mover *DataMover, mvOpts D
 	return badCount, nil
 }
 
-// NameMapTransform creates a pipeline transform that converts rows from inSch to outSch based on a name mapping.
-func NameMapTransform(inSch schema.Schema, outSch schema.Schema, mapper rowconv.NameMapper) (*pipeline.TransformCollection, error) {
+// NameMapTransform creates a pipeline transform that converts rows from inSch to outSch based on a name mapping. Columns
+// of outSch which are not mapped are given their default values, and AUTO_INCREMENT columns which are not mapped are
+// left for the table editor to fill in. Converted values which are stored in their own chunks are written to vrw.
+func NameMapTransform(ctx context.Context, vrw types.ValueReadWriter, inSch schema.Schema, outSch schema.Schema, mapper rowconv.NameMapper) (*pipeline.TransformCollection, error) {
 	mapping, err := rowconv.NameMapping(inSch, outSch, mapper)
 
 	if err != nil {
 		return nil, err
 	}
 
-	rconv, err := rowconv.NewImportRowConverter(mapping)
+	rconv, err := rowconv.NewImportRowConverter(ctx, vrw, mapping)
 
 	if err != nil {
 		return nil, err
@@ -211,13 +215,94 @@ func NameMapTransform(inSch schema.Schema, outSch schema.Schema, mapper rowconv.
 
 	transforms := pipeline.NewTransformCollection()
 	if !rconv.IdentityConverter {
-		nt := pipeline.NewNamedTransform("Mapping transform", rowconv.GetRowConvTransformFunc(rconv))
+		transformFunc, err := importTransformFunc(ctx, vrw, mapping, rconv)
+
+		if err != nil {
+			return nil, err
+		}
+
+		nt := pipeline.NewNamedTransform("Mapping transform", transformFunc)
 		transforms.AppendTransforms(nt)
 	}
 
 	return transforms, nil
 }
 
+// importTransformFunc returns the function that converts and validates each imported row, filling in the columns of the
+// destination schema that the mapping does not cover.
+func importTransformFunc(ctx context.Context, vrw types.ValueReadWriter, mapping *rowconv.FieldMapping, rconv *rowconv.RowConverter) (pipeline.TransformRowFunc, error) {
+	mapped := set.NewUint64Set(nil)
+	for _, destTag := range mapping.SrcToDest {
+		mapped.Add(destTag)
+	}
+
+	var defaultIndices []int
+	var unmappedAutoIncrement bool
+	for i, col := range mapping.DestSch.GetAllCols().GetColumns() {
+		if mapped.Contains(col.Tag) {
+			continue
+		}
+		if col.AutoIncrement {
+			unmappedAutoIncrement = true
+		} else if col.Default != "" {
+			defaultIndices = append(defaultIndices, i)
+		}
+	}
+
+	if len(defaultIndices) == 0 && !unmappedAutoIncrement {
+		return rowconv.GetRowConvTransformFunc(rconv), nil
+	}
+
+	sqlSch, err := sqleSchema.FromDoltSchema("", mapping.DestSch)
+
+	if err != nil {
+		return nil, err
+	}
+
+	validationSch := mapping.DestSch
+	if unmappedAutoIncrement {
+		// values are assigned as the rows are written, so the column may be empty here
+		cols, err := schema.MapColCollection(mapping.DestSch.GetAllCols(), func(col schema.Column) (schema.Column, error) {
+			if col.AutoIncrement {
+				col.Constraints = nil
+			}
+			return col, nil
+		})
+
+		if err != nil {
+			return nil, err
+		}
+
+		validationSch, err = schema.SchemaFromCols(cols)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return func(inRow row.Row, props pipeline.ReadableMap) ([]*pipeline.TransformedRowResult, string) {
+		outRow, err := rconv.Convert(inRow)
+
+		if err != nil {
+			return nil, err.Error()
+		}
+
+		outRow, err = sqleSchema.ApplyDefaults(ctx, vrw, mapping.DestSch, sqlSch, defaultIndices, outRow)
+
+		if err != nil {
+			return nil, err.Error()
+		}
+
+		if col, err := row.GetInvalidCol(outRow, validationSch); err != nil {
+			return nil, err.Error()
+		} else if col != nil {
+			return nil, "invalid column: " + col.Name
+		}
+
+		return []*pipeline.TransformedRowResult{{RowData: outRow, PropertyUpdates: nil}}, ""
+	}, nil
+}
+
 // SchAndTableNameFromFile reads a SQL schema file and creates a Dolt schema from it.
 func SchAndTableNameFromFile(ctx context.Context, path string, fs filesys.ReadableFS, root *doltdb.RootValue) (string, schema.Schema, error) {
 	if path != "" {
@@ -227,12 +312,61 @@ func SchAndTableNameFromFile(ctx context.Context, path string, fs filesys.Readab
 			return "", nil, err
 		}
 
-		tn, sch, err := sqleSchema.ParseCreateTableStatement(ctx, root, string(data))
+		query := string(data)
+		spatialDDL, err := sqle.ParseSpatialDDL(query)
+
+		if err != nil {
+			return "", nil, fmt.Errorf("%s in schema file %s", err.Error(), path)
+		} else if spatialDDL != nil && spatialDDL.CreatesTable {
+			query = spatialDDL.Query
+		}
+
+		indexDDL, err := sqle.ParseIndexDDL(query)
+
+		if err != nil {
+			return "", nil, fmt.Errorf("%s in schema file %s", err.Error(), path)
+		} else if indexDDL != nil && indexDDL.CreatesTable {
+			query = indexDDL.Query
+		}
+
+		checkDDL, err := sqle.ParseCheckDDL(query)
+
+		if err != nil {
+			return "", nil, fmt.Errorf("%s in schema file %s", err.Error(), path)
+		} else if checkDDL != nil && checkDDL.CreatesTable {
+			query = checkDDL.Query
+		}
+
+		tn, sch, err := sqleSchema.ParseCreateTableStatement(ctx, root, query)
 
 		if err != nil {
 			return "", nil, fmt.Errorf("%s in schema file %s", err.Error(), path)
 		}
 
+		if checkDDL != nil && checkDDL.CreatesTable {
+			err = checkDDL.AddToSchema(tn, sch)
+
+			if err != nil {
+				return "", nil, fmt.Errorf("%s in schema file %s", err.Error(), path)
+			}
+		}
+
+		if indexDDL != nil && indexDDL.CreatesTable {
+			err = indexDDL.AddToSchema(tn, sch)
+
+			if err != nil {
+				return "", nil, fmt.Errorf("%s in schema file %s", err.Error(), path)
+			}
+		}
+
+		if spatialDDL != nil && spatialDDL.CreatesTable {
+			sch, err = spatialDDL.AddToSchema(tn, sch)
+
+			if err != nil {
+				return "", nil, fmt.Errorf("%s in schema file %s", err.Error(), path)
+			}
+		}
+
 		return tn, sch, nil
 	} else {
 		return "", nil, errors.New("no schema file to parse")
@@ -26,6 +26,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/row"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema/encoding"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/typed/noms"
 	"github.com/dolthub/dolt/go/libraries/utils/set"
 	ndiff "github.com/dolthub/dolt/go/store/diff"
@@ -372,6 +373,8 @@ func replayCommitWithNewTag(ctx context.Context, root, parentRoot, rebasedParent
 				schema.IndexProperties{
 					IsUnique:      index.IsUnique(),
 					IsUserDefined: index.IsUserDefined(),
+					IsFullText:    index.IsFullText(),
+					IsSpatial:     index.IsSpatial(),
 					Comment:       index.Comment(),
 				},
 			)
@@ -748,10 +751,10 @@ func buildTagMapping(ctx context.Context, root, rebasedParentRoot *doltdb.RootVa
 			_, found := tagMapping[tn][tag]
 			if !found {
 				newColNames = append(newColNames, col.Name)
-				newColKinds = append(newColKinds, col.Kind)
+				newColKinds = append(newColKinds, typeinfo.TagKind(col.TypeInfo))
 				oldTags = append(oldTags, tag)
 			} else {
-				existingColKinds = append(existingColKinds, col.Kind)
+				existingColKinds = append(existingColKinds, typeinfo.TagKind(col.TypeInfo))
 			}
 			return false, nil
 		})
@@ -0,0 +1,363 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remotestorage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/nbs"
+)
+
+var ErrLocalStoreNotTableFileStore = errors.New("the local chunk store of a lazy chunk store is not a table file store")
+
+var _ nbs.TableFileStore = (*LazyChunkStore)(nil)
+var _ datas.NBSCompressedChunkStore = (*LazyChunkStore)(nil)
+var _ chunks.ChunkStore = (*LazyChunkStore)(nil)
+
+// RemoteChunkStoreOpener is used by a LazyChunkStore to open the remote chunk store the first time a chunk is missing
+// from the local store.
+type RemoteChunkStoreOpener func(ctx context.Context) (chunks.ChunkStore, error)
+
+// LazyChunkStore is a chunks.ChunkStore for partially cloned repositories.  All writes go to the local store, and reads
+// are served from the local store when possible. Chunks which are missing locally are retrieved from the remote store
+// on first access and are written to the local store so that subsequent reads don't need to go to the remote.
+type LazyChunkStore struct {
+	local chunks.ChunkStore
+
+	mu         *sync.Mutex
+	openRemote RemoteChunkStoreOpener
+	remote     chunks.ChunkStore
+}
+
+// NewLazyChunkStore returns a new LazyChunkStore which reads through to the remote returned by |openRemote| for any
+// chunks which are not present in |local|.
+func NewLazyChunkStore(local chunks.ChunkStore, openRemote RemoteChunkStoreOpener) *LazyChunkStore {
+	return &LazyChunkStore{local: local, mu: &sync.Mutex{}, openRemote: openRemote}
+}
+
+// Local returns the local chunk store being wrapped
+func (lcs *LazyChunkStore) Local() chunks.ChunkStore {
+	return lcs.local
+}
+
+func (lcs *LazyChunkStore) getRemote(ctx context.Context) (chunks.ChunkStore, error) {
+	lcs.mu.Lock()
+	defer lcs.mu.Unlock()
+
+	if lcs.remote == nil {
+		remote, err := lcs.openRemote(ctx)
+
+		if err != nil {
+			return nil, err
+		}
+
+		lcs.remote = remote
+	}
+
+	return lcs.remote, nil
+}
+
+// Get the Chunk for the value of the hash in the store. If the hash is absent from both the local and the remote store
+// EmptyChunk is returned.
+func (lcs *LazyChunkStore) Get(ctx context.Context, h hash.Hash) (chunks.Chunk, error) {
+	c, err := lcs.local.Get(ctx, h)
+
+	if err != nil {
+		return chunks.EmptyChunk, err
+	} else if !c.IsEmpty() {
+		return c, nil
+	}
+
+	remote, err := lcs.getRemote(ctx)
+
+	if err != nil {
+		return chunks.EmptyChunk, err
+	}
+
+	c, err = remote.Get(ctx, h)
+
+	if err != nil {
+		return chunks.EmptyChunk, err
+	} else if c.IsEmpty() {
+		return c, nil
+	}
+
+	err = lcs.local.Put(ctx, c)
+
+	if err != nil {
+		return chunks.EmptyChunk, err
+	}
+
+	return c, nil
+}
+
+// GetMany gets the Chunks with |hashes| from the local store, and retrieves any which are missing locally from the
+// remote store.
+func (lcs *LazyChunkStore) GetMany(ctx context.Context, hashes hash.HashSet, found func(*chunks.Chunk)) error {
+	missing, err := lcs.getManyLocal(ctx, hashes, found)
+
+	if err != nil || len(missing) == 0 {
+		return err
+	}
+
+	remote, err := lcs.getRemote(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	var fetched []chunks.Chunk
+	mu := &sync.Mutex{}
+	err = remote.GetMany(ctx, missing, func(c *chunks.Chunk) {
+		mu.Lock()
+		defer mu.Unlock()
+		fetched = append(fetched, *c)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	for i := range fetched {
+		err = lcs.local.Put(ctx, fetched[i])
+
+		if err != nil {
+			return err
+		}
+
+		found(&fetched[i])
+	}
+
+	return nil
+}
+
+func (lcs *LazyChunkStore) getManyLocal(ctx context.Context, hashes hash.HashSet, found func(*chunks.Chunk)) (hash.HashSet, error) {
+	missing := copyHashSet(hashes)
+	mu := &sync.Mutex{}
+	err := lcs.local.GetMany(ctx, hashes, func(c *chunks.Chunk) {
+		mu.Lock()
+		missing.Remove(c.Hash())
+		mu.Unlock()
+
+		found(c)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return missing, nil
+}
+
+// GetManyCompressed gets the compressed Chunks with |hashes| from the local store, and retrieves any which are missing
+// locally from the remote store.
+func (lcs *LazyChunkStore) GetManyCompressed(ctx context.Context, hashes hash.HashSet, found func(nbs.CompressedChunk)) error {
+	localCmp, ok := lcs.local.(datas.NBSCompressedChunkStore)
+
+	if !ok {
+		return datas.ErrIncompatibleSourceChunkStore
+	}
+
+	missing := copyHashSet(hashes)
+	mu := &sync.Mutex{}
+	err := localCmp.GetManyCompressed(ctx, hashes, func(cc nbs.CompressedChunk) {
+		mu.Lock()
+		missing.Remove(cc.H)
+		mu.Unlock()
+
+		found(cc)
+	})
+
+	if err != nil || len(missing) == 0 {
+		return err
+	}
+
+	return lcs.GetMany(ctx, missing, func(c *chunks.Chunk) {
+		found(nbs.ChunkToCompressedChunk(*c))
+	})
+}
+
+// Has returns true iff the value at the address |h| is contained in either the local or the remote store.
+func (lcs *LazyChunkStore) Has(ctx context.Context, h hash.Hash) (bool, error) {
+	absent, err := lcs.HasMany(ctx, hash.NewHashSet(h))
+
+	if err != nil {
+		return false, err
+	}
+
+	return len(absent) == 0, nil
+}
+
+// HasMany returns a new HashSet containing any members of |hashes| that are absent from both the local and the remote
+// store.
+func (lcs *LazyChunkStore) HasMany(ctx context.Context, hashes hash.HashSet) (hash.HashSet, error) {
+	absent, err := lcs.local.HasMany(ctx, hashes)
+
+	if err != nil || len(absent) == 0 {
+		return absent, err
+	}
+
+	remote, err := lcs.getRemote(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return remote.HasMany(ctx, absent)
+}
+
+// Put writes the chunk to the local store.
+func (lcs *LazyChunkStore) Put(ctx context.Context, c chunks.Chunk) error {
+	return lcs.local.Put(ctx, c)
+}
+
+// Version returns the NomsVersion of the local store.
+func (lcs *LazyChunkStore) Version() string {
+	return lcs.local.Version()
+}
+
+// Rebase brings the local store into sync with its persistent storage's current root.
+func (lcs *LazyChunkStore) Rebase(ctx context.Context) error {
+	return lcs.local.Rebase(ctx)
+}
+
+// Root returns the root of the local store.
+func (lcs *LazyChunkStore) Root(ctx context.Context) (hash.Hash, error) {
+	return lcs.local.Root(ctx)
+}
+
+// Commit persists all novel chunks written to the local store and updates its root hash from last to current.
+func (lcs *LazyChunkStore) Commit(ctx context.Context, current, last hash.Hash) (bool, error) {
+	return lcs.local.Commit(ctx, current, last)
+}
+
+// Stats returns the statistics of the local store.
+func (lcs *LazyChunkStore) Stats() interface{} {
+	return lcs.local.Stats()
+}
+
+// StatsSummary returns the summarized statistics of the local store.
+func (lcs *LazyChunkStore) StatsSummary() string {
+	return lcs.local.StatsSummary()
+}
+
+// Close closes the local store, and the remote store if it was opened.
+func (lcs *LazyChunkStore) Close() error {
+	err := lcs.local.Close()
+
+	lcs.mu.Lock()
+	defer lcs.mu.Unlock()
+
+	if lcs.remote != nil {
+		rmtErr := lcs.remote.Close()
+
+		if err == nil {
+			err = rmtErr
+		}
+	}
+
+	return err
+}
+
+func (lcs *LazyChunkStore) localTableFileStore() (nbs.TableFileStore, error) {
+	tfs, ok := lcs.local.(nbs.TableFileStore)
+
+	if !ok {
+		return nil, ErrLocalStoreNotTableFileStore
+	}
+
+	return tfs, nil
+}
+
+// Sources retrieves the current root hash, and a list of all the table files of the local store.
+func (lcs *LazyChunkStore) Sources(ctx context.Context) (hash.Hash, []nbs.TableFile, error) {
+	tfs, err := lcs.localTableFileStore()
+
+	if err != nil {
+		return hash.Hash{}, nil, err
+	}
+
+	return tfs.Sources(ctx)
+}
+
+// Size returns the total size, in bytes, of the table files in the local store.
+func (lcs *LazyChunkStore) Size(ctx context.Context) (uint64, error) {
+	tfs, err := lcs.localTableFileStore()
+
+	if err != nil {
+		return 0, err
+	}
+
+	return tfs.Size(ctx)
+}
+
+// WriteTableFile writes a table file to the local store.
+func (lcs *LazyChunkStore) WriteTableFile(ctx context.Context, fileId string, numChunks int, rd io.Reader, contentLength uint64, contentHash []byte) error {
+	tfs, err := lcs.localTableFileStore()
+
+	if err != nil {
+		return err
+	}
+
+	return tfs.WriteTableFile(ctx, fileId, numChunks, rd, contentLength, contentHash)
+}
+
+// PruneTableFiles deletes old table files that are no longer referenced in the manifest of the local store.
+func (lcs *LazyChunkStore) PruneTableFiles(ctx context.Context) error {
+	tfs, err := lcs.localTableFileStore()
+
+	if err != nil {
+		return err
+	}
+
+	return tfs.PruneTableFiles(ctx)
+}
+
+// SetRootChunk changes the root chunk hash of the local store from the previous value to the new root.
+func (lcs *LazyChunkStore) SetRootChunk(ctx context.Context, root, previous hash.Hash) error {
+	tfs, err := lcs.localTableFileStore()
+
+	if err != nil {
+		return err
+	}
+
+	return tfs.SetRootChunk(ctx, root, previous)
+}
+
+// SupportedOperations returns the table file operations supported by the local store.
+func (lcs *LazyChunkStore) SupportedOperations() nbs.TableFileStoreOps {
+	tfs, err := lcs.localTableFileStore()
+
+	if err != nil {
+		return nbs.TableFileStoreOps{}
+	}
+
+	return tfs.SupportedOperations()
+}
+
+func copyHashSet(hashes hash.HashSet) hash.HashSet {
+	cp := make(hash.HashSet, len(hashes))
+	for h := range hashes {
+		cp.Insert(h)
+	}
+
+	return cp
+}
@@ -0,0 +1,69 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remotestorage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+func TestLazyChunkStore(t *testing.T) {
+	ctx := context.Background()
+	local := (&chunks.MemoryStorage{}).NewView()
+	remote := (&chunks.MemoryStorage{}).NewView()
+
+	localChk := chunks.NewChunk([]byte("local"))
+	remoteChk := chunks.NewChunk([]byte("remote"))
+	missingChk := chunks.NewChunk([]byte("missing"))
+	require.NoError(t, local.Put(ctx, localChk))
+	require.NoError(t, remote.Put(ctx, remoteChk))
+
+	opened := 0
+	lcs := NewLazyChunkStore(local, func(ctx context.Context) (chunks.ChunkStore, error) {
+		opened++
+		return remote, nil
+	})
+
+	c, err := lcs.Get(ctx, localChk.Hash())
+	require.NoError(t, err)
+	assert.Equal(t, localChk.Data(), c.Data())
+	assert.Equal(t, 0, opened, "the remote should not be opened when all chunks are local")
+
+	absent, err := lcs.HasMany(ctx, hash.NewHashSet(localChk.Hash(), remoteChk.Hash(), missingChk.Hash()))
+	require.NoError(t, err)
+	assert.Equal(t, hash.NewHashSet(missingChk.Hash()), absent)
+
+	found := hash.HashSet{}
+	err = lcs.GetMany(ctx, hash.NewHashSet(localChk.Hash(), remoteChk.Hash(), missingChk.Hash()), func(c *chunks.Chunk) {
+		found.Insert(c.Hash())
+	})
+	require.NoError(t, err)
+	assert.Equal(t, hash.NewHashSet(localChk.Hash(), remoteChk.Hash()), found)
+	assert.Equal(t, 1, opened, "the remote should only be opened once")
+
+	ok, err := local.Has(ctx, remoteChk.Hash())
+	require.NoError(t, err)
+	assert.True(t, ok, "chunks read from the remote should be written to the local store")
+
+	c, err = lcs.Get(ctx, missingChk.Hash())
+	require.NoError(t, err)
+	assert.True(t, c.IsEmpty())
+}
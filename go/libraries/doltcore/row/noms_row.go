@@ -15,6 +15,7 @@
 package row
 
 import (
+	"context"
 	"errors"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
@@ -234,7 +235,7 @@ func FromNoms(sch schema.Schema, nomsKey, nomsVal types.Tuple) (Row, error) {
 	return FromTupleSlices(nomsKey.Format(), sch, keySl, valSl)
 }
 
-func (nr nomsRow) ReduceToIndex(idx schema.Index) (Row, error) {
+func (nr nomsRow) ReduceToIndex(ctx context.Context, idx schema.Index) (Row, error) {
 	newRow := nomsRow{
 		key:   make(TaggedValues),
 		value: make(TaggedValues),
@@ -249,6 +250,14 @@ func (nr nomsRow) ReduceToIndex(idx schema.Index) (Row, error) {
 				continue
 			}
 		}
+		if prefixLength := schema.PrefixLength(idx, tag); prefixLength > 0 {
+			col, _ := idx.GetColumn(tag)
+			var err error
+			val, err = schema.PrefixValue(ctx, col, val, prefixLength)
+			if err != nil {
+				return nil, err
+			}
+		}
 		newRow.key[tag] = val
 	}
 
@@ -256,8 +265,13 @@ func (nr nomsRow) ReduceToIndex(idx schema.Index) (Row, error) {
 }
 
 func (nr nomsRow) ReduceToIndexPartialKey(idx schema.Index) (types.Tuple, error) {
+	// the index map of an expression index holds the expressions' values in place of the indexed columns
+	tags := idx.IndexedColumnTags()
+	if exprs := idx.Expressions(); len(exprs) > 0 {
+		tags = idx.AllTags()[:len(exprs)]
+	}
 	var vals []types.Value
-	for _, tag := range idx.IndexedColumnTags() {
+	for _, tag := range tags {
 		val, ok := nr.key[tag]
 		if !ok {
 			val, ok = nr.value[tag]
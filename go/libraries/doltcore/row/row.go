@@ -15,6 +15,7 @@
 package row
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -48,8 +49,8 @@ type Row interface {
 
 	// ReduceToIndex reduces a row to only the columns contained in an index, including the parent table's primary
 	// keys. Only the column tags that are in the index will be included in the reduced row. The full index does not
-	// have to be matched.
-	ReduceToIndex(idx schema.Index) (Row, error)
+	// have to be matched. The values of columns indexed by their prefix are reduced to that prefix.
+	ReduceToIndex(ctx context.Context, idx schema.Index) (Row, error)
 
 	// ReduceToIndexPartialKey reduces a row to only the columns contained in an index, not including the parent table's
 	// primary keys. The Tuple is then returned, allowing all matching rows on an index to be found.
@@ -48,7 +48,7 @@ func TestRowConverter(t *testing.T) {
 
 	assert.NoError(t, err)
 
-	rConv, err := NewRowConverter(mapping)
+	rConv, err := NewRowConverter(context.Background(), types.NewMemoryValueStore(), mapping)
 
 	if err != nil {
 		t.Fatal("Error creating row converter")
@@ -95,7 +95,7 @@ func TestUnneccessaryConversion(t *testing.T) {
 		t.Error(err)
 	}
 
-	rconv, err := NewRowConverter(mapping)
+	rconv, err := NewRowConverter(context.Background(), types.NewMemoryValueStore(), mapping)
 
 	if !rconv.IdentityConverter {
 		t.Error("expected identity converter")
@@ -115,7 +115,7 @@ func TestSpecialBoolHandling(t *testing.T) {
 
 	mapping, err := TagMapping(untypedSch, sch)
 	require.NoError(t, err)
-	rconv, err := NewImportRowConverter(mapping)
+	rconv, err := NewImportRowConverter(context.Background(), types.NewMemoryValueStore(), mapping)
 	require.NoError(t, err)
 	inRow, err := row.New(types.Format_7_18, untypedSch, row.TaggedValues{
 		0: types.String("76"),
@@ -134,9 +134,43 @@ func TestSpecialBoolHandling(t *testing.T) {
 	require.NoError(t, err)
 	assert.True(t, row.AreEqual(outData, expected, mapping.DestSch))
 
-	rconvNoHandle, err := NewRowConverter(mapping)
+	rconvNoHandle, err := NewRowConverter(context.Background(), types.NewMemoryValueStore(), mapping)
 	require.NoError(t, err)
 	results, errStr = GetRowConvTransformFunc(rconvNoHandle)(inRow, pipeline.ImmutableProperties{})
 	assert.Nil(t, results)
 	assert.NotEmpty(t, errStr)
 }
+
+func TestConversionToLongText(t *testing.T) {
+	pkCol, err := schema.NewColumnWithTypeInfo("pk", 0, typeinfo.Int64Type, true, "", false, "")
+	require.NoError(t, err)
+	srcCol, err := schema.NewColumnWithTypeInfo("v", 1, typeinfo.Int64Type, false, "", false, "")
+	require.NoError(t, err)
+	destCol, err := schema.NewColumnWithTypeInfo("v", 1, typeinfo.LongTextType, false, "", false, "")
+	require.NoError(t, err)
+	srcColl, err := schema.NewColCollection(pkCol, srcCol)
+	require.NoError(t, err)
+	destColl, err := schema.NewColCollection(pkCol, destCol)
+	require.NoError(t, err)
+	srcSch := schema.MustSchemaFromCols(srcColl)
+	destSch := schema.MustSchemaFromCols(destColl)
+
+	mapping, err := TagMapping(srcSch, destSch)
+	require.NoError(t, err)
+	vrw := types.NewMemoryValueStore()
+	rconv, err := NewRowConverter(context.Background(), vrw, mapping)
+	require.NoError(t, err)
+
+	inRow, err := row.New(types.Format_7_18, srcSch, row.TaggedValues{0: types.Int(1), 1: types.Int(-1234)})
+	require.NoError(t, err)
+	outRow, err := rconv.Convert(inRow)
+	require.NoError(t, err)
+
+	// LONGTEXT values are stored as Blobs
+	val, ok := outRow.GetColVal(1)
+	require.True(t, ok)
+	require.Equal(t, types.BlobKind, val.Kind())
+	str, err := typeinfo.LongTextType.FormatValue(context.Background(), val)
+	require.NoError(t, err)
+	assert.Equal(t, "-1234", *str)
+}
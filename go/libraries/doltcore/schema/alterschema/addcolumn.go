@@ -84,7 +84,11 @@ func updateTableWithNewSchema(ctx context.Context, tblName string, tbl *doltdb.T
 	}
 
 	if defaultVal == "" {
-		return doltdb.NewTable(ctx, vrw, newSchemaVal, rowData, &indexData)
+		newTable, err := doltdb.NewTable(ctx, vrw, newSchemaVal, rowData, &indexData)
+		if err != nil {
+			return nil, err
+		}
+		return doltdb.CopyAutoIncrementValue(tbl, newTable)
 	}
 
 	me := rowData.Edit()
@@ -109,7 +113,7 @@ func updateTableWithNewSchema(ctx context.Context, tblName string, tbl *doltdb.T
 		if err != nil {
 			return true, err
 		}
-		newRow, err := sqleSchema.ApplyDefaults(ctx, newSchema, newSqlSchema, []int{columnIndex}, oldRow)
+		newRow, err := sqleSchema.ApplyDefaults(ctx, vrw, newSchema, newSqlSchema, []int{columnIndex}, oldRow)
 		if err != nil {
 			return true, err
 		}
@@ -127,7 +131,12 @@ func updateTableWithNewSchema(ctx context.Context, tblName string, tbl *doltdb.T
 		return nil, err
 	}
 
-	return doltdb.NewTable(ctx, vrw, newSchemaVal, m, &indexData)
+	newTable, err := doltdb.NewTable(ctx, vrw, newSchemaVal, m, &indexData)
+	if err != nil {
+		return nil, err
+	}
+
+	return doltdb.CopyAutoIncrementValue(tbl, newTable)
 }
 
 // addColumnToSchema creates a new schema with a column as specified by the params.
@@ -161,6 +170,7 @@ func addColumnToSchema(sch schema.Schema, tag uint64, newColName string, typeInf
 		return nil, err
 	}
 	newSch.Indexes().AddIndex(sch.Indexes().AllIndexes()...)
+	newSch.Checks().AddChecks(sch.Checks().AllChecks()...)
 
 	return newSch, nil
 }
@@ -60,6 +60,14 @@ func DropColumn(ctx context.Context, tbl *doltdb.Table, colName string, foreignK
 		}
 	}
 
+	checks, err := tblSch.Checks().ChecksWithColumn(colName)
+	if err != nil {
+		return nil, err
+	}
+	if len(checks) > 0 {
+		return nil, fmt.Errorf("cannot drop column `%s` as it is used in check constraint `%s`", colName, checks[0].Name)
+	}
+
 	for _, index := range tblSch.Indexes().IndexesWithColumn(colName) {
 		_, err = tblSch.Indexes().RemoveIndex(index.Name())
 		if err != nil {
@@ -93,6 +101,7 @@ func DropColumn(ctx context.Context, tbl *doltdb.Table, colName string, foreignK
 		return nil, err
 	}
 	newSch.Indexes().AddIndex(tblSch.Indexes().AllIndexes()...)
+	newSch.Checks().AddChecks(tblSch.Checks().AllChecks()...)
 
 	vrw := tbl.ValueReadWriter()
 	schemaVal, err := encoding.MarshalSchemaAsNomsValue(ctx, vrw, newSch)
@@ -113,5 +122,5 @@ func DropColumn(ctx context.Context, tbl *doltdb.Table, colName string, foreignK
 		return nil, err
 	}
 
-	return newTable, nil
+	return doltdb.CopyAutoIncrementValue(tbl, newTable)
 }
@@ -179,3 +179,29 @@ func TestDropColumnUsedByIndex(t *testing.T) {
 		})
 	}
 }
+
+func TestDropColumnUsedByCheck(t *testing.T) {
+	dEnv := createEnvWithSeedData(t)
+	ctx := context.Background()
+
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+	tbl, _, err := root.GetTable(ctx, tableName)
+	require.NoError(t, err)
+
+	sch, err := tbl.GetSchema(ctx)
+	require.NoError(t, err)
+	_, err = sch.Checks().AddCheck("chk_age", "(`age` < 150)", true)
+	require.NoError(t, err)
+	tbl, err = tbl.UpdateSchema(ctx, sch)
+	require.NoError(t, err)
+
+	_, err = DropColumn(ctx, tbl, "age", nil)
+	assert.Error(t, err)
+
+	updatedTable, err := DropColumn(ctx, tbl, "title", nil)
+	require.NoError(t, err)
+	updatedSch, err := updatedTable.GetSchema(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, sch.Checks().AllChecks(), updatedSch.Checks().AllChecks())
+}
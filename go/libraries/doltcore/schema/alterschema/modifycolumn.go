@@ -103,6 +103,21 @@ func validateModifyColumn(ctx context.Context, tbl *doltdb.Table, existingCol sc
 		return err
 	}
 
+	if existingCol.Name != modifiedCol.Name {
+		checks, err := sch.Checks().ChecksWithColumn(existingCol.Name)
+		if err != nil {
+			return err
+		}
+		if len(checks) > 0 {
+			return fmt.Errorf("cannot rename column `%s` as it is used in check constraint `%s`", existingCol.Name, checks[0].Name)
+		}
+		for _, index := range sch.Indexes().IndexesWithTag(existingCol.Tag) {
+			if len(index.Expressions()) > 0 {
+				return fmt.Errorf("cannot rename column `%s` as it is used in expression index `%s`", existingCol.Name, index.Name())
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -145,7 +160,12 @@ func updateTableWithModifiedColumn(ctx context.Context, tbl *doltdb.Table, newSc
 		return nil, err
 	}
 
-	return doltdb.NewTable(ctx, vrw, newSchemaVal, rowData, &indexData)
+	newTable, err := doltdb.NewTable(ctx, vrw, newSchemaVal, rowData, &indexData)
+	if err != nil {
+		return nil, err
+	}
+
+	return doltdb.CopyAutoIncrementValue(tbl, newTable)
 }
 
 // replaceColumnInSchema replaces the column with the name given with its new definition, optionally reordering it.
@@ -200,5 +220,6 @@ func replaceColumnInSchema(sch schema.Schema, oldCol schema.Column, newCol schem
 		return nil, err
 	}
 	newSch.Indexes().AddIndex(sch.Indexes().AllIndexes()...)
+	newSch.Checks().AddChecks(sch.Checks().AllChecks()...)
 	return newSch, nil
 }
@@ -0,0 +1,176 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/vitess/go/vt/sqlparser"
+)
+
+// ErrCheckExists is returned when adding a check whose name is already used by another check on the table.
+var ErrCheckExists = errors.New("a check constraint with this name already exists")
+
+// ErrCheckNotFound is returned when dropping a check that does not exist.
+var ErrCheckNotFound = errors.New("check constraint not found")
+
+// Check is a CHECK constraint defined on a table. A row satisfies the check unless Expression evaluates to false for
+// it. Checks that are not Enforced are recorded in the schema, but never evaluated.
+type Check struct {
+	Name       string
+	Expression string
+	Enforced   bool
+}
+
+// ColumnNames returns the names of the columns referenced by the check's expression.
+func (c Check) ColumnNames() ([]string, error) {
+	stmt, err := sqlparser.Parse("SELECT " + c.Expression)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression for check constraint '%s': %w", c.Name, err)
+	}
+
+	var names []string
+	seen := make(map[string]bool)
+	err = sqlparser.Walk(func(node sqlparser.SQLNode) (kontinue bool, err error) {
+		if col, ok := node.(*sqlparser.ColName); ok {
+			name := col.Name.String()
+			if !seen[strings.ToLower(name)] {
+				seen[strings.ToLower(name)] = true
+				names = append(names, name)
+			}
+		}
+		return true, nil
+	}, stmt)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return names, nil
+}
+
+// CheckCollection is the ordered collection of CHECK constraints defined on a table.
+type CheckCollection interface {
+	// AddCheck adds a check with the given name and expression. Check names are case insensitive.
+	AddCheck(name, expression string, enforced bool) (Check, error)
+	// AddChecks adds the given checks, overwriting any current checks with the same names. It does not perform any
+	// kind of checking, and is intended for schema modifications.
+	AddChecks(checks ...Check)
+	// AllChecks returns all of the checks in the collection in the order they were added.
+	AllChecks() []Check
+	// ChecksWithColumn returns the checks whose expressions reference the column given.
+	ChecksWithColumn(columnName string) ([]Check, error)
+	// Count returns the number of checks in the collection.
+	Count() int
+	// DropCheck removes the check with the given name from the collection.
+	DropCheck(name string) (Check, error)
+	// Equals returns whether this collection contains the same checks, in the same order, as another.
+	Equals(other CheckCollection) bool
+	// GetByNameCaseInsensitive returns the check with the given name, the bool return value indicates if it was found.
+	GetByNameCaseInsensitive(name string) (Check, bool)
+}
+
+type checkCollectionImpl struct {
+	checks []Check
+}
+
+// NewCheckCollection returns an empty CheckCollection.
+func NewCheckCollection() CheckCollection {
+	return &checkCollectionImpl{}
+}
+
+func (cc *checkCollectionImpl) AddCheck(name, expression string, enforced bool) (Check, error) {
+	if _, ok := cc.GetByNameCaseInsensitive(name); ok {
+		return Check{}, fmt.Errorf("%w: '%s'", ErrCheckExists, name)
+	}
+
+	check := Check{Name: name, Expression: expression, Enforced: enforced}
+	cc.checks = append(cc.checks, check)
+	return check, nil
+}
+
+func (cc *checkCollectionImpl) AddChecks(checks ...Check) {
+	for _, check := range checks {
+		_, _ = cc.DropCheck(check.Name)
+		cc.checks = append(cc.checks, check)
+	}
+}
+
+func (cc *checkCollectionImpl) AllChecks() []Check {
+	checks := make([]Check, len(cc.checks))
+	copy(checks, cc.checks)
+	return checks
+}
+
+func (cc *checkCollectionImpl) Count() int {
+	return len(cc.checks)
+}
+
+func (cc *checkCollectionImpl) DropCheck(name string) (Check, error) {
+	for i, check := range cc.checks {
+		if strings.EqualFold(check.Name, name) {
+			cc.checks = append(cc.checks[:i:i], cc.checks[i+1:]...)
+			return check, nil
+		}
+	}
+
+	return Check{}, fmt.Errorf("%w: '%s'", ErrCheckNotFound, name)
+}
+
+func (cc *checkCollectionImpl) Equals(other CheckCollection) bool {
+	otherChecks := other.AllChecks()
+	if len(cc.checks) != len(otherChecks) {
+		return false
+	}
+
+	for i, check := range cc.checks {
+		if check != otherChecks[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (cc *checkCollectionImpl) GetByNameCaseInsensitive(name string) (Check, bool) {
+	for _, check := range cc.checks {
+		if strings.EqualFold(check.Name, name) {
+			return check, true
+		}
+	}
+
+	return Check{}, false
+}
+
+func (cc *checkCollectionImpl) ChecksWithColumn(columnName string) ([]Check, error) {
+	var checks []Check
+	for _, check := range cc.checks {
+		names, err := check.ColumnNames()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, name := range names {
+			if strings.EqualFold(name, columnName) {
+				checks = append(checks, check)
+				break
+			}
+		}
+	}
+
+	return checks, nil
+}
@@ -143,12 +143,55 @@ type encodedIndex struct {
 	Tags            []uint64 `noms:"tags" json:"tags"`
 	Comment         string   `noms:"comment" json:"comment"`
 	Unique          bool     `noms:"unique" json:"unique"`
-	IsSystemDefined bool     `noms:"hidden,omitempty" json:"hidden,omitempty"` // Was previously named Hidden, do not change noms name
+	IsSystemDefined bool     `noms:"hidden,omitempty" json:"hidden,omitempty"`     // Was previously named Hidden, do not change noms name
+	FullText        bool     `noms:"fulltext,omitempty" json:"fulltext,omitempty"` // Ignored by older clients, see doltdb.FeatureVersion
+	Spatial         bool     `noms:"spatial,omitempty" json:"spatial,omitempty"`   // Ignored by older clients, see doltdb.FeatureVersion
+	PrefixLengths   []uint16 `noms:"prefix_lengths,omitempty" json:"prefix_lengths,omitempty"`
+
+	Expressions []encodedIndexExpression `noms:"expressions,omitempty" json:"expressions,omitempty"`
+}
+
+type encodedIndexExpression struct {
+	Expression string          `noms:"expression" json:"expression"`
+	TypeInfo   encodedTypeInfo `noms:"typeinfo" json:"typeinfo"`
+}
+
+func encodeIndexExpressions(exprs []schema.IndexExpression) []encodedIndexExpression {
+	if len(exprs) == 0 {
+		return nil
+	}
+	encoded := make([]encodedIndexExpression, len(exprs))
+	for i, expr := range exprs {
+		encoded[i] = encodedIndexExpression{expr.Expression, encodeTypeInfo(expr.TypeInfo)}
+	}
+	return encoded
+}
+
+func decodeIndexExpressions(encoded []encodedIndexExpression) ([]schema.IndexExpression, error) {
+	if len(encoded) == 0 {
+		return nil, nil
+	}
+	exprs := make([]schema.IndexExpression, len(encoded))
+	for i, enc := range encoded {
+		ti, err := enc.TypeInfo.decodeTypeInfo()
+		if err != nil {
+			return nil, err
+		}
+		exprs[i] = schema.IndexExpression{Expression: enc.Expression, TypeInfo: ti}
+	}
+	return exprs, nil
+}
+
+type encodedCheck struct {
+	Name       string `noms:"name" json:"name"`
+	Expression string `noms:"expression" json:"expression"`
+	Enforced   bool   `noms:"enforced" json:"enforced"`
 }
 
 type schemaData struct {
 	Columns         []encodedColumn `noms:"columns" json:"columns"`
 	IndexCollection []encodedIndex  `noms:"idxColl,omitempty" json:"idxColl,omitempty"`
+	CheckCollection []encodedCheck  `noms:"checkColl,omitempty" json:"checkColl,omitempty"`
 }
 
 func toSchemaData(sch schema.Schema) (schemaData, error) {
@@ -175,10 +218,23 @@ func toSchemaData(sch schema.Schema) (schemaData, error) {
 			Comment:         index.Comment(),
 			Unique:          index.IsUnique(),
 			IsSystemDefined: !index.IsUserDefined(),
+			FullText:        index.IsFullText(),
+			Spatial:         index.IsSpatial(),
+			PrefixLengths:   index.PrefixLengths(),
+			Expressions:     encodeIndexExpressions(index.Expressions()),
 		}
 	}
 
-	return schemaData{encCols, encodedIndexes}, nil
+	encodedChecks := make([]encodedCheck, sch.Checks().Count())
+	for i, check := range sch.Checks().AllChecks() {
+		encodedChecks[i] = encodedCheck{
+			Name:       check.Name,
+			Expression: check.Expression,
+			Enforced:   check.Enforced,
+		}
+	}
+
+	return schemaData{encCols, encodedIndexes, encodedChecks}, nil
 }
 
 func (sd schemaData) decodeSchema() (schema.Schema, error) {
@@ -205,12 +261,20 @@ func (sd schemaData) decodeSchema() (schema.Schema, error) {
 	}
 
 	for _, encodedIndex := range sd.IndexCollection {
+		exprs, err := decodeIndexExpressions(encodedIndex.Expressions)
+		if err != nil {
+			return nil, err
+		}
 		_, err = sch.Indexes().UnsafeAddIndexByColTags(
 			encodedIndex.Name,
 			encodedIndex.Tags,
 			schema.IndexProperties{
 				IsUnique:      encodedIndex.Unique,
 				IsUserDefined: !encodedIndex.IsSystemDefined,
+				IsFullText:    encodedIndex.FullText,
+				IsSpatial:     encodedIndex.Spatial,
+				PrefixLengths: encodedIndex.PrefixLengths,
+				Expressions:   exprs,
 				Comment:       encodedIndex.Comment,
 			},
 		)
@@ -219,6 +283,13 @@ func (sd schemaData) decodeSchema() (schema.Schema, error) {
 		}
 	}
 
+	for _, encodedCheck := range sd.CheckCollection {
+		_, err = sch.Checks().AddCheck(encodedCheck.Name, encodedCheck.Expression, encodedCheck.Enforced)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return sch, nil
 }
 
@@ -45,6 +45,13 @@ func createTestSchema() schema.Schema {
 	colColl, _ := schema.NewColCollection(columns...)
 	sch := schema.MustSchemaFromCols(colColl)
 	_, _ = sch.Indexes().AddIndexByColTags("idx_age", []uint64{3}, schema.IndexProperties{IsUnique: false, Comment: ""})
+	_, _ = sch.Indexes().AddIndexByColTags("idx_name", []uint64{1, 2}, schema.IndexProperties{IsFullText: true, Comment: ""})
+	_, _ = sch.Indexes().AddIndexByColTags("idx_prefix_last", []uint64{2}, schema.IndexProperties{PrefixLengths: []uint16{3}, Comment: ""})
+	_, _ = sch.Indexes().AddIndexByColTags("idx_upper_first", []uint64{1}, schema.IndexProperties{
+		Expressions: []schema.IndexExpression{{Expression: "upper(`first`)", TypeInfo: typeinfo.StringDefaultType}},
+		Comment:     "",
+	})
+	_, _ = sch.Checks().AddCheck("chk_age", "(age < 150)", true)
 	return sch
 }
 
@@ -216,16 +223,27 @@ type testEncodedColumn struct {
 }
 
 type testEncodedIndex struct {
-	Name    string   `noms:"name" json:"name"`
-	Tags    []uint64 `noms:"tags" json:"tags"`
-	Comment string   `noms:"comment" json:"comment"`
-	Unique  bool     `noms:"unique" json:"unique"`
-	Hidden  bool     `noms:"hidden,omitempty" json:"hidden,omitempty"`
+	Name     string   `noms:"name" json:"name"`
+	Tags     []uint64 `noms:"tags" json:"tags"`
+	Comment  string   `noms:"comment" json:"comment"`
+	Unique   bool     `noms:"unique" json:"unique"`
+	Hidden   bool     `noms:"hidden,omitempty" json:"hidden,omitempty"`
+	FullText bool     `noms:"fulltext,omitempty" json:"fulltext,omitempty"`
+
+	PrefixLengths []uint16                 `noms:"prefix_lengths,omitempty" json:"prefix_lengths,omitempty"`
+	Expressions   []encodedIndexExpression `noms:"expressions,omitempty" json:"expressions,omitempty"`
+}
+
+type testEncodedCheck struct {
+	Name       string `noms:"name" json:"name"`
+	Expression string `noms:"expression" json:"expression"`
+	Enforced   bool   `noms:"enforced" json:"enforced"`
 }
 
 type testSchemaData struct {
 	Columns         []testEncodedColumn `noms:"columns" json:"columns"`
 	IndexCollection []testEncodedIndex  `noms:"idxColl,omitempty" json:"idxColl,omitempty"`
+	CheckCollection []testEncodedCheck  `noms:"checkColl,omitempty" json:"checkColl,omitempty"`
 }
 
 func (tec testEncodedColumn) decodeColumn() (schema.Column, error) {
@@ -269,7 +287,24 @@ func (tsd testSchemaData) decodeSchema() (schema.Schema, error) {
 	}
 
 	for _, encodedIndex := range tsd.IndexCollection {
-		_, err = sch.Indexes().AddIndexByColTags(encodedIndex.Name, encodedIndex.Tags, schema.IndexProperties{IsUnique: encodedIndex.Unique, Comment: encodedIndex.Comment})
+		exprs, err := decodeIndexExpressions(encodedIndex.Expressions)
+		if err != nil {
+			return nil, err
+		}
+		_, err = sch.Indexes().AddIndexByColTags(encodedIndex.Name, encodedIndex.Tags, schema.IndexProperties{
+			IsUnique:      encodedIndex.Unique,
+			IsFullText:    encodedIndex.FullText,
+			PrefixLengths: encodedIndex.PrefixLengths,
+			Expressions:   exprs,
+			Comment:       encodedIndex.Comment,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, encodedCheck := range tsd.CheckCollection {
+		_, err = sch.Checks().AddCheck(encodedCheck.Name, encodedCheck.Expression, encodedCheck.Enforced)
 		if err != nil {
 			return nil, err
 		}
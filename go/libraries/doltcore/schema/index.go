@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
 	"github.com/dolthub/dolt/go/store/types"
 )
 
@@ -31,6 +32,9 @@ type Index interface {
 	Comment() string
 	// Count returns the number of indexed columns in this index.
 	Count() int
+	// Expressions returns the expressions of an expression index, whose map holds the values of these expressions in
+	// place of the indexed columns, which are the columns the expressions reference. Returns nil for other indexes.
+	Expressions() []IndexExpression
 	// Equals returns whether this Index is equivalent to another. This does not check for column names, thus those may
 	// be renamed and the index equivalence will be preserved. It also does not depend on the table's primary keys.
 	Equals(other Index) bool
@@ -38,12 +42,23 @@ type Index interface {
 	GetColumn(tag uint64) (Column, bool)
 	// IndexedColumnTags returns the tags of the columns in the index.
 	IndexedColumnTags() []uint64
+	// IsFullText returns whether the given index is a FULLTEXT index. Rather than the values of its columns, the map of a
+	// FULLTEXT index holds each distinct token found in those columns, along with the primary keys of the rows containing
+	// it.
+	IsFullText() bool
+	// IsSpatial returns whether the given index is a SPATIAL index. Rather than the values of its column, the map of a
+	// SPATIAL index holds the geohash of the smallest cell containing each row's geometry, followed by the row's primary
+	// keys. See geometry.SpatialCell.
+	IsSpatial() bool
 	// IsUnique returns whether the given index has the UNIQUE constraint.
 	IsUnique() bool
 	// IsUserDefined returns whether the given index was created by a user or automatically generated.
 	IsUserDefined() bool
 	// Name returns the name of the index.
 	Name() string
+	// PrefixLengths returns the number of characters of each indexed column's values that are stored in the index,
+	// with zero storing the whole value. Returns nil when the whole value of every column is stored.
+	PrefixLengths() []uint16
 	// PrimaryKeyTags returns the primary keys of the indexed table, in the order that they're stored for that table.
 	PrimaryKeyTags() []uint64
 	// Schema returns the schema for the internal index map. Can be used for table operations.
@@ -54,6 +69,35 @@ type Index interface {
 
 var _ Index = (*indexImpl)(nil)
 
+// fullTextTokenColumn is the first column of a FULLTEXT index's map, which precedes the table's primary keys.
+var fullTextTokenColumn = Column{
+	Name:     "token",
+	Tag:      FullTextTokenTag,
+	Kind:     types.StringKind,
+	TypeInfo: typeinfo.StringDefaultType,
+}
+
+// spatialCellColumn is the first column of a SPATIAL index's map, which precedes the table's primary keys.
+var spatialCellColumn = Column{
+	Name:     "cell",
+	Tag:      SpatialCellTag,
+	Kind:     types.StringKind,
+	TypeInfo: typeinfo.StringDefaultType,
+}
+
+// IndexExpression is an expression whose values are indexed by an expression index.
+type IndexExpression struct {
+	// Expression is the expression's SQL, which references columns by name.
+	Expression string
+	// TypeInfo is the type of the expression's values.
+	TypeInfo typeinfo.TypeInfo
+}
+
+// Equals returns whether this IndexExpression is equivalent to another.
+func (ie IndexExpression) Equals(other IndexExpression) bool {
+	return ie.Expression == other.Expression && ie.TypeInfo.Equals(other.TypeInfo)
+}
+
 type indexImpl struct {
 	name          string
 	tags          []uint64
@@ -61,6 +105,10 @@ type indexImpl struct {
 	indexColl     *indexCollectionImpl
 	isUnique      bool
 	isUserDefined bool
+	isFullText    bool
+	isSpatial     bool
+	prefixLengths []uint16
+	expressions   []IndexExpression
 	comment       string
 }
 
@@ -72,6 +120,10 @@ func NewIndex(name string, tags, allTags []uint64, indexColl *indexCollectionImp
 		indexColl:     indexColl,
 		isUnique:      props.IsUnique,
 		isUserDefined: props.IsUserDefined,
+		isFullText:    props.IsFullText,
+		isSpatial:     props.IsSpatial,
+		prefixLengths: props.PrefixLengths,
+		expressions:   props.Expressions,
 		comment:       props.Comment,
 	}
 }
@@ -116,10 +168,19 @@ func (ix *indexImpl) Equals(other Index) bool {
 	}
 
 	return ix.IsUnique() == other.IsUnique() &&
+		ix.IsFullText() == other.IsFullText() &&
+		ix.IsSpatial() == other.IsSpatial() &&
+		prefixLengthsEqual(ix.PrefixLengths(), other.PrefixLengths()) &&
+		indexExpressionsEqual(ix.Expressions(), other.Expressions()) &&
 		ix.Comment() == other.Comment() &&
 		ix.Name() == other.Name()
 }
 
+// Expressions implements Index.
+func (ix *indexImpl) Expressions() []IndexExpression {
+	return ix.expressions
+}
+
 // GetColumn implements Index.
 func (ix *indexImpl) GetColumn(tag uint64) (Column, bool) {
 	return ix.indexColl.colColl.GetByTag(tag)
@@ -130,6 +191,16 @@ func (ix *indexImpl) IndexedColumnTags() []uint64 {
 	return ix.tags
 }
 
+// IsFullText implements Index.
+func (ix *indexImpl) IsFullText() bool {
+	return ix.isFullText
+}
+
+// IsSpatial implements Index.
+func (ix *indexImpl) IsSpatial() bool {
+	return ix.isSpatial
+}
+
 // IsUnique implements Index.
 func (ix *indexImpl) IsUnique() bool {
 	return ix.isUnique
@@ -145,6 +216,44 @@ func (ix *indexImpl) Name() string {
 	return ix.name
 }
 
+// PrefixLengths implements Index.
+func (ix *indexImpl) PrefixLengths() []uint16 {
+	return ix.prefixLengths
+}
+
+// PrefixLength returns the prefix length of the indexed column with the given tag, which is zero if its whole value is
+// stored.
+func PrefixLength(idx Index, tag uint64) uint16 {
+	prefixLengths := idx.PrefixLengths()
+	for i, idxTag := range idx.IndexedColumnTags() {
+		if idxTag == tag && i < len(prefixLengths) {
+			return prefixLengths[i]
+		}
+	}
+	return 0
+}
+
+// PrefixValue returns the prefix of the given length of a value of the given column, which is what an index on a
+// prefix of the column stores. Prefixes are measured in characters.
+func PrefixValue(ctx context.Context, col Column, val types.Value, length uint16) (types.Value, error) {
+	if types.IsNull(val) {
+		return types.NullValue, nil
+	}
+	str, err := col.TypeInfo.FormatValue(ctx, val)
+	if err != nil || str == nil {
+		return types.NullValue, err
+	}
+	return types.String(PrefixOf(*str, length)), nil
+}
+
+// PrefixOf returns the prefix of the given number of characters of a string.
+func PrefixOf(str string, length uint16) string {
+	if runes := []rune(str); len(runes) > int(length) {
+		return string(runes[:length])
+	}
+	return str
+}
+
 // PrimaryKeyTags implements Index.
 func (ix *indexImpl) PrimaryKeyTags() []uint64 {
 	return ix.indexColl.pks
@@ -154,7 +263,7 @@ func (ix *indexImpl) PrimaryKeyTags() []uint64 {
 func (ix *indexImpl) Schema() Schema {
 	cols := make([]Column, len(ix.allTags))
 	for i, tag := range ix.allTags {
-		col := ix.indexColl.colColl.TagToCol[tag]
+		col, _ := ix.getMapColumn(tag)
 		cols[i] = Column{
 			Name:        col.Name,
 			Tag:         tag,
@@ -171,6 +280,7 @@ func (ix *indexImpl) Schema() Schema {
 		nonPKCols:       nonPkCols,
 		allCols:         allCols,
 		indexCollection: NewIndexCollection(nil),
+		checkCollection: NewCheckCollection(),
 	}
 }
 
@@ -184,7 +294,7 @@ func (ix *indexImpl) VerifyMap(ctx context.Context, iter types.MapIterator, nbf
 	cols := make([]Column, len(ix.allTags))
 	for i, tag := range ix.allTags {
 		var ok bool
-		cols[i], ok = ix.indexColl.colColl.TagToCol[tag]
+		cols[i], ok = ix.getMapColumn(tag)
 		if !ok {
 			return fmt.Errorf("index `%s` has column with tag `%d` which cannot be found", ix.name, tag)
 		}
@@ -218,7 +328,7 @@ func (ix *indexImpl) VerifyMap(ctx context.Context, iter types.MapIterator, nbf
 			return err
 		}
 		if ix.isUnique && !hasNull {
-			partialKeysEqual, err := key.PrefixEquals(ctx, lastKey, uint64(len(ix.tags)*2))
+			partialKeysEqual, err := key.PrefixEquals(ctx, lastKey, uint64(ix.keyCount()*2))
 			if err != nil {
 				return err
 			}
@@ -234,6 +344,67 @@ func (ix *indexImpl) VerifyMap(ctx context.Context, iter types.MapIterator, nbf
 	return err
 }
 
+// getMapColumn returns the column of the index map with the given tag, which is either one of the table's columns,
+// the token column of a FULLTEXT index, the cell column of a SPATIAL index, or the column of one of the expressions of
+// an expression index. Only the prefixes of prefixed columns are stored, as strings.
+func (ix *indexImpl) getMapColumn(tag uint64) (Column, bool) {
+	if ix.isFullText && tag == FullTextTokenTag {
+		return fullTextTokenColumn, true
+	}
+	if ix.isSpatial && tag == SpatialCellTag {
+		return spatialCellColumn, true
+	}
+	if i := int(tag - IndexExpressionTagMin); tag >= IndexExpressionTagMin && i < len(ix.expressions) {
+		expr := ix.expressions[i]
+		return Column{Name: expr.Expression, Tag: tag, Kind: expr.TypeInfo.NomsKind(), TypeInfo: expr.TypeInfo}, true
+	}
+	col, ok := ix.indexColl.colColl.TagToCol[tag]
+	if ok && PrefixLength(ix, tag) > 0 {
+		col.Kind, col.TypeInfo = types.StringKind, typeinfo.StringDefaultType
+	}
+	return col, ok
+}
+
+// keyCount returns the number of columns of the index map before the table's primary keys.
+func (ix *indexImpl) keyCount() int {
+	switch {
+	case ix.isFullText, ix.isSpatial:
+		return 1
+	case len(ix.expressions) > 0:
+		return len(ix.expressions)
+	default:
+		return len(ix.tags)
+	}
+}
+
+func prefixLengthsEqual(a, b []uint16) bool {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var aLen, bLen uint16
+		if i < len(a) {
+			aLen = a[i]
+		}
+		if i < len(b) {
+			bLen = b[i]
+		}
+		if aLen != bLen {
+			return false
+		}
+	}
+	return true
+}
+
+func indexExpressionsEqual(a, b []IndexExpression) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equals(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
 // copy returns an exact copy of the calling index.
 func (ix *indexImpl) copy() *indexImpl {
 	newIx := *ix
@@ -241,5 +412,13 @@ func (ix *indexImpl) copy() *indexImpl {
 	_ = copy(newIx.tags, ix.tags)
 	newIx.allTags = make([]uint64, len(ix.allTags))
 	_ = copy(newIx.allTags, ix.allTags)
+	if ix.prefixLengths != nil {
+		newIx.prefixLengths = make([]uint16, len(ix.prefixLengths))
+		_ = copy(newIx.prefixLengths, ix.prefixLengths)
+	}
+	if ix.expressions != nil {
+		newIx.expressions = make([]IndexExpression, len(ix.expressions))
+		_ = copy(newIx.expressions, ix.expressions)
+	}
 	return &newIx
 }
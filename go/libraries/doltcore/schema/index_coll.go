@@ -46,7 +46,15 @@ type IndexCollection interface {
 	// GetIndexByColumnNames returns whether the collection contains an index that has this exact collection and ordering of columns.
 	GetIndexByColumnNames(cols ...string) (Index, bool)
 	// GetIndexByTags returns whether the collection contains an index that has this exact collection and ordering of columns.
+	// FULLTEXT indexes, expression indexes and indexes on column prefixes are not considered.
 	GetIndexByTags(tags ...uint64) (Index, bool)
+	// GetFullTextIndexByTags returns whether the collection contains a FULLTEXT index that has this exact collection and
+	// ordering of columns.
+	GetFullTextIndexByTags(tags ...uint64) (Index, bool)
+	// GetIndexLike returns whether the collection contains an index over the same columns as the given index, which
+	// stores the same values of them: the same prefixes or expressions, their tokens for a FULLTEXT index, or their
+	// cells for a SPATIAL index.
+	GetIndexLike(index Index) (Index, bool)
 	// IndexesWithColumn returns all indexes that index the given column.
 	IndexesWithColumn(columnName string) []Index
 	// IndexesWithTag returns all indexes that index the given tag.
@@ -67,7 +75,14 @@ type IndexCollection interface {
 type IndexProperties struct {
 	IsUnique      bool
 	IsUserDefined bool
-	Comment       string
+	IsFullText    bool
+	IsSpatial     bool
+	// PrefixLengths holds the prefix length of each indexed column, if any are indexed by a prefix of their values.
+	PrefixLengths []uint16
+	// Expressions holds the expressions of an expression index, in which case the indexed columns are the columns
+	// referenced by the expressions.
+	Expressions []IndexExpression
+	Comment     string
 }
 
 type indexCollectionImpl struct {
@@ -102,12 +117,12 @@ func (ixc *indexCollectionImpl) AddIndex(indexes ...Index) {
 		}
 		index = index.copy()
 		index.indexColl = ixc
-		index.allTags = combineAllTags(index.tags, ixc.pks)
+		index.allTags = indexMapTags(index.tags, ixc.pks, kindOf(index))
 		oldNamedIndex, ok := ixc.indexes[index.name]
 		if ok {
 			ixc.removeIndex(oldNamedIndex)
 		}
-		oldTaggedIndex := ixc.containsColumnTagCollection(index.tags...)
+		oldTaggedIndex := ixc.containsColumnTagCollection(kindOf(index), index.tags...)
 		if oldTaggedIndex != nil {
 			ixc.removeIndex(oldTaggedIndex)
 		}
@@ -136,16 +151,21 @@ func (ixc *indexCollectionImpl) AddIndexByColTags(indexName string, tags []uint6
 	if !ixc.tagsExist(tags...) {
 		return nil, fmt.Errorf("tags %v do not exist on this table", tags)
 	}
-	if ixc.hasIndexOnTags(tags...) {
+	kind := props.kind()
+	if ixc.containsColumnTagCollection(kind, tags...) != nil {
 		return nil, fmt.Errorf("cannot create a duplicate index on this table")
 	}
 	index := &indexImpl{
 		indexColl:     ixc,
 		name:          indexName,
 		tags:          tags,
-		allTags:       combineAllTags(tags, ixc.pks),
+		allTags:       indexMapTags(tags, ixc.pks, props.kind()),
 		isUnique:      props.IsUnique,
 		isUserDefined: props.IsUserDefined,
+		isFullText:    props.IsFullText,
+		isSpatial:     props.IsSpatial,
+		prefixLengths: props.PrefixLengths,
+		expressions:   props.Expressions,
 		comment:       props.Comment,
 	}
 	ixc.indexes[indexName] = index
@@ -160,9 +180,13 @@ func (ixc *indexCollectionImpl) UnsafeAddIndexByColTags(indexName string, tags [
 		indexColl:     ixc,
 		name:          indexName,
 		tags:          tags,
-		allTags:       combineAllTags(tags, ixc.pks),
+		allTags:       indexMapTags(tags, ixc.pks, props.kind()),
 		isUnique:      props.IsUnique,
 		isUserDefined: props.IsUserDefined,
+		isFullText:    props.IsFullText,
+		isSpatial:     props.IsSpatial,
+		prefixLengths: props.PrefixLengths,
+		expressions:   props.Expressions,
 		comment:       props.Comment,
 	}
 	ixc.indexes[indexName] = index
@@ -201,7 +225,7 @@ func (ixc *indexCollectionImpl) Equals(other IndexCollection) bool {
 		return false
 	}
 	for _, index := range ixc.indexes {
-		otherIndex := otherIxc.containsColumnTagCollection(index.tags...)
+		otherIndex := otherIxc.containsColumnTagCollection(kindOf(index), index.tags...)
 		if otherIndex == nil || !index.Equals(otherIndex) {
 			return false
 		}
@@ -251,7 +275,23 @@ func (ixc *indexCollectionImpl) GetIndexByColumnNames(cols ...string) (Index, bo
 }
 
 func (ixc *indexCollectionImpl) GetIndexByTags(tags ...uint64) (Index, bool) {
-	idx := ixc.containsColumnTagCollection(tags...)
+	idx := ixc.containsColumnTagCollection(indexKind{}, tags...)
+	if idx == nil {
+		return nil, false
+	}
+	return idx, true
+}
+
+func (ixc *indexCollectionImpl) GetFullTextIndexByTags(tags ...uint64) (Index, bool) {
+	idx := ixc.containsColumnTagCollection(indexKind{isFullText: true}, tags...)
+	if idx == nil {
+		return nil, false
+	}
+	return idx, true
+}
+
+func (ixc *indexCollectionImpl) GetIndexLike(index Index) (Index, bool) {
+	idx := ixc.containsColumnTagCollection(kindOf(index), index.IndexedColumnTags()...)
 	if idx == nil {
 		return nil, false
 	}
@@ -302,6 +342,10 @@ func (ixc *indexCollectionImpl) Merge(indexes ...Index) {
 				indexColl:     ixc,
 				isUnique:      index.IsUnique(),
 				isUserDefined: index.IsUserDefined(),
+				isFullText:    index.IsFullText(),
+				isSpatial:     index.IsSpatial(),
+				prefixLengths: index.PrefixLengths(),
+				expressions:   index.Expressions(),
 				comment:       index.Comment(),
 			}
 			ixc.AddIndex(newIndex)
@@ -353,10 +397,35 @@ func (ixc *indexCollectionImpl) columnNamesToTags(cols []string) ([]uint64, bool
 	return tags, true
 }
 
-func (ixc *indexCollectionImpl) containsColumnTagCollection(tags ...uint64) *indexImpl {
+// indexKind is what distinguishes indexes over the same columns from one another: the values of the columns they store.
+type indexKind struct {
+	isFullText    bool
+	isSpatial     bool
+	prefixLengths []uint16
+	expressions   []IndexExpression
+}
+
+func kindOf(index Index) indexKind {
+	return indexKind{index.IsFullText(), index.IsSpatial(), index.PrefixLengths(), index.Expressions()}
+}
+
+func (props IndexProperties) kind() indexKind {
+	return indexKind{props.IsFullText, props.IsSpatial, props.PrefixLengths, props.Expressions}
+}
+
+func (k indexKind) equals(other indexKind) bool {
+	return k.isFullText == other.isFullText &&
+		k.isSpatial == other.isSpatial &&
+		prefixLengthsEqual(k.prefixLengths, other.prefixLengths) &&
+		indexExpressionsEqual(k.expressions, other.expressions)
+}
+
+// containsColumnTagCollection returns the index of the given kind over exactly the given tags, in order. Indexes of
+// different kinds are kept apart, so that a column may have a FULLTEXT index as well as a regular one, for example.
+func (ixc *indexCollectionImpl) containsColumnTagCollection(kind indexKind, tags ...uint64) *indexImpl {
 	tagCount := len(tags)
 	for _, idx := range ixc.indexes {
-		if tagCount == len(idx.tags) {
+		if tagCount == len(idx.tags) && kindOf(idx).equals(kind) {
 			allMatch := true
 			for i, idxTag := range idx.tags {
 				if tags[i] != idxTag {
@@ -398,6 +467,26 @@ func (ixc *indexCollectionImpl) tagsExist(tags ...uint64) bool {
 	return true
 }
 
+// indexMapTags returns the tags of the columns of the map of an index of the given kind. A FULLTEXT index's map has its
+// token column in place of the indexed columns, a SPATIAL index's map its cell column, and an expression index's map
+// has a column for each of its expressions.
+func indexMapTags(tags []uint64, pks []uint64, kind indexKind) []uint64 {
+	if kind.isFullText {
+		return combineAllTags([]uint64{FullTextTokenTag}, pks)
+	}
+	if kind.isSpatial {
+		return combineAllTags([]uint64{SpatialCellTag}, pks)
+	}
+	if len(kind.expressions) > 0 {
+		exprTags := make([]uint64, len(kind.expressions))
+		for i := range kind.expressions {
+			exprTags[i] = IndexExpressionTag(i)
+		}
+		return combineAllTags(exprTags, pks)
+	}
+	return combineAllTags(tags, pks)
+}
+
 func combineAllTags(tags []uint64, pks []uint64) []uint64 {
 	allTags := make([]uint64, len(tags))
 	_ = copy(allTags, tags)
@@ -15,11 +15,13 @@
 package schema
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
 	"github.com/dolthub/dolt/go/store/types"
 )
 
@@ -468,3 +470,80 @@ func (ixc *indexCollectionImpl) clear(_ *testing.T) {
 		ixc.colTagToIndex[key] = nil
 	}
 }
+
+func TestIndexCollectionFullText(t *testing.T) {
+	colColl, err := NewColCollection(
+		NewColumn("pk1", 1, types.IntKind, true, NotNullConstraint{}),
+		NewColumn("v1", 2, types.StringKind, false),
+		NewColumn("v2", 3, types.StringKind, false),
+	)
+	require.NoError(t, err)
+	indexColl := NewIndexCollection(colColl).(*indexCollectionImpl)
+
+	index, err := indexColl.AddIndexByColNames("idx_v1", []string{"v1"}, IndexProperties{})
+	require.NoError(t, err)
+	ftIndex, err := indexColl.AddIndexByColNames("ft_v1", []string{"v1"}, IndexProperties{IsFullText: true})
+	require.NoError(t, err)
+	_, err = indexColl.AddIndexByColNames("ft_v1_2", []string{"v1"}, IndexProperties{IsFullText: true})
+	assert.Error(t, err)
+
+	assert.True(t, ftIndex.IsFullText())
+	assert.False(t, index.Equals(ftIndex))
+	assert.Equal(t, []uint64{FullTextTokenTag, 1}, ftIndex.AllTags())
+	assert.Equal(t, []string{"token", "pk1"}, ftIndex.Schema().GetAllCols().GetColumnNames())
+	res, ok := indexColl.GetIndexByTags(2)
+	require.True(t, ok)
+	assert.Equal(t, index, res)
+	res, ok = indexColl.GetFullTextIndexByTags(2)
+	require.True(t, ok)
+	assert.Equal(t, ftIndex, res)
+
+	indexColl.AddIndex(ftIndex)
+	assert.Len(t, indexColl.AllIndexes(), 2)
+}
+
+func TestIndexCollectionPrefixesAndExpressions(t *testing.T) {
+	colColl, err := NewColCollection(
+		NewColumn("pk1", 1, types.IntKind, true, NotNullConstraint{}),
+		NewColumn("v1", 2, types.StringKind, false),
+	)
+	require.NoError(t, err)
+	indexColl := NewIndexCollection(colColl).(*indexCollectionImpl)
+
+	index, err := indexColl.AddIndexByColNames("idx_v1", []string{"v1"}, IndexProperties{})
+	require.NoError(t, err)
+	prefixIndex, err := indexColl.AddIndexByColNames("idx_v1_prefix", []string{"v1"}, IndexProperties{PrefixLengths: []uint16{3}})
+	require.NoError(t, err)
+	_, err = indexColl.AddIndexByColNames("idx_v1_prefix_2", []string{"v1"}, IndexProperties{PrefixLengths: []uint16{3}})
+	assert.Error(t, err)
+	exprs := []IndexExpression{{Expression: "lower(`v1`)", TypeInfo: typeinfo.StringDefaultType}}
+	exprIndex, err := indexColl.AddIndexByColNames("idx_lower_v1", []string{"v1"}, IndexProperties{Expressions: exprs})
+	require.NoError(t, err)
+
+	assert.False(t, index.Equals(prefixIndex))
+	assert.False(t, index.Equals(exprIndex))
+	assert.Equal(t, uint16(3), PrefixLength(prefixIndex, 2))
+	assert.Equal(t, uint16(0), PrefixLength(index, 2))
+	assert.Equal(t, []uint64{2, 1}, prefixIndex.AllTags())
+	assert.Equal(t, []uint64{IndexExpressionTag(0), 1}, exprIndex.AllTags())
+	assert.Equal(t, []string{"lower(`v1`)", "pk1"}, exprIndex.Schema().GetAllCols().GetColumnNames())
+	assert.Equal(t, []string{"v1"}, exprIndex.ColumnNames())
+	assert.Len(t, indexColl.IndexesWithTag(2), 3)
+
+	res, ok := indexColl.GetIndexByTags(2)
+	require.True(t, ok)
+	assert.Equal(t, index, res)
+	res, ok = indexColl.GetIndexLike(prefixIndex)
+	require.True(t, ok)
+	assert.Equal(t, prefixIndex, res)
+	res, ok = indexColl.GetIndexLike(exprIndex)
+	require.True(t, ok)
+	assert.Equal(t, exprIndex, res)
+
+	val, err := PrefixValue(context.Background(), colColl.GetByIndex(1), types.String("héllo"), 3)
+	require.NoError(t, err)
+	assert.Equal(t, types.String("hél"), val)
+	val, err = PrefixValue(context.Background(), colColl.GetByIndex(1), types.String("hé"), 3)
+	require.NoError(t, err)
+	assert.Equal(t, types.String("hé"), val)
+}
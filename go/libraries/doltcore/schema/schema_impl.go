@@ -25,11 +25,13 @@ var EmptySchema = &schemaImpl{
 	nonPKCols:       EmptyColColl,
 	allCols:         EmptyColColl,
 	indexCollection: NewIndexCollection(nil),
+	checkCollection: NewCheckCollection(),
 }
 
 type schemaImpl struct {
 	pkCols, nonPKCols, allCols *ColCollection
 	indexCollection            IndexCollection
+	checkCollection            CheckCollection
 }
 
 // SchemaFromCols creates a Schema from a collection of columns
@@ -57,6 +59,7 @@ func SchemaFromCols(allCols *ColCollection) (Schema, error) {
 		nonPKCols:       nonPKColColl,
 		allCols:         allCols,
 		indexCollection: NewIndexCollection(allCols),
+		checkCollection: NewCheckCollection(),
 	}, nil
 }
 
@@ -121,6 +124,7 @@ func UnkeyedSchemaFromCols(allCols *ColCollection) Schema {
 		nonPKCols:       nonPKColColl,
 		allCols:         nonPKColColl,
 		indexCollection: NewIndexCollection(nil),
+		checkCollection: NewCheckCollection(),
 	}
 }
 
@@ -158,6 +162,7 @@ func SchemaFromPKAndNonPKCols(pkCols, nonPKCols *ColCollection) (Schema, error)
 		nonPKCols:       nonPKCols,
 		allCols:         allColColl,
 		indexCollection: NewIndexCollection(allColColl),
+		checkCollection: NewCheckCollection(),
 	}, nil
 }
 
@@ -209,3 +214,7 @@ func (si *schemaImpl) String() string {
 func (si *schemaImpl) Indexes() IndexCollection {
 	return si.indexCollection
 }
+
+func (si *schemaImpl) Checks() CheckCollection {
+	return si.checkCollection
+}
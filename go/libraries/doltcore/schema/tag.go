@@ -29,8 +29,21 @@ import (
 const (
 	// ReservedTagMin is the start of a range of tags which the user should not be able to use in their schemas.
 	ReservedTagMin uint64 = 1 << 50
+	// FullTextTokenTag is the tag of the column holding the tokens of a FULLTEXT index's map.
+	FullTextTokenTag = ReservedTagMin<<1 - 1
+	// SpatialCellTag is the tag of the column holding the cells of a SPATIAL index's map.
+	SpatialCellTag = FullTextTokenTag - 1
+	// IndexExpressionTagMin is the tag of the column holding the values of the first expression of an expression
+	// index's map. The columns of its other expressions follow it in order.
+	IndexExpressionTagMin = FullTextTokenTag - 1<<16
 )
 
+// IndexExpressionTag returns the tag of the column holding the values of the expression with the given position in
+// an expression index's map.
+func IndexExpressionTag(i int) uint64 {
+	return IndexExpressionTagMin + uint64(i)
+}
+
 func ErrTagPrevUsed(tag uint64, newColName, tableName string) error {
 	return fmt.Errorf("Cannot create column %s, the tag %d was already used in table %s", newColName, tag, tableName)
 }
@@ -15,6 +15,7 @@
 package typeinfo
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 
@@ -54,7 +55,7 @@ func CreateBitTypeFromParams(params map[string]string) (TypeInfo, error) {
 }
 
 // ConvertNomsValueToValue implements TypeInfo interface.
-func (ti *bitType) ConvertNomsValueToValue(v types.Value) (interface{}, error) {
+func (ti *bitType) ConvertNomsValueToValue(ctx context.Context, v types.Value) (interface{}, error) {
 	if val, ok := v.(types.Uint); ok {
 		return uint64(val), nil
 	}
@@ -65,7 +66,7 @@ func (ti *bitType) ConvertNomsValueToValue(v types.Value) (interface{}, error) {
 }
 
 // ConvertValueToNomsValue implements TypeInfo interface.
-func (ti *bitType) ConvertValueToNomsValue(v interface{}) (types.Value, error) {
+func (ti *bitType) ConvertValueToNomsValue(ctx context.Context, vrw types.ValueReadWriter, v interface{}) (types.Value, error) {
 	if v == nil {
 		return types.NullValue, nil
 	}
@@ -92,11 +93,11 @@ func (ti *bitType) Equals(other TypeInfo) bool {
 }
 
 // FormatValue implements TypeInfo interface.
-func (ti *bitType) FormatValue(v types.Value) (*string, error) {
+func (ti *bitType) FormatValue(ctx context.Context, v types.Value) (*string, error) {
 	if _, ok := v.(types.Null); ok || v == nil {
 		return nil, nil
 	}
-	uintVal, err := ti.ConvertNomsValueToValue(v)
+	uintVal, err := ti.ConvertNomsValueToValue(ctx, v)
 	if err != nil {
 		return nil, err
 	}
@@ -141,7 +142,7 @@ func (ti *bitType) NomsKind() types.NomsKind {
 }
 
 // ParseValue implements TypeInfo interface.
-func (ti *bitType) ParseValue(str *string) (types.Value, error) {
+func (ti *bitType) ParseValue(ctx context.Context, vrw types.ValueReadWriter, str *string) (types.Value, error) {
 	if str == nil || *str == "" {
 		return types.NullValue, nil
 	}
@@ -15,6 +15,7 @@
 package typeinfo
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"strconv"
@@ -61,7 +62,7 @@ func TestBitConvertNomsValueToValue(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(fmt.Sprintf(`%v %v`, test.typ.String(), test.input), func(t *testing.T) {
-			output, err := test.typ.ConvertNomsValueToValue(test.input)
+			output, err := test.typ.ConvertNomsValueToValue(context.Background(), test.input)
 			if test.expectedErr {
 				assert.Error(t, err)
 			} else {
@@ -107,7 +108,7 @@ func TestBitConvertValueToNomsValue(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(fmt.Sprintf(`%v %v`, test.typ.String(), test.input), func(t *testing.T) {
-			output, err := test.typ.ConvertValueToNomsValue(test.input)
+			output, err := test.typ.ConvertValueToNomsValue(context.Background(), nil, test.input)
 			if !test.expectedErr {
 				require.NoError(t, err)
 				assert.Equal(t, test.output, output)
@@ -153,7 +154,7 @@ func TestBitFormatValue(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(fmt.Sprintf(`%v %v`, test.typ.String(), test.input), func(t *testing.T) {
-			output, err := test.typ.FormatValue(test.input)
+			output, err := test.typ.FormatValue(context.Background(), test.input)
 			if test.expectedErr {
 				assert.Error(t, err)
 			} else {
@@ -199,7 +200,7 @@ func TestBitParseValue(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(fmt.Sprintf(`%v %v`, test.typ.String(), test.input), func(t *testing.T) {
-			output, err := test.typ.ParseValue(&test.input)
+			output, err := test.typ.ParseValue(context.Background(), nil, &test.input)
 			if !test.expectedErr {
 				require.NoError(t, err)
 				assert.Equal(t, test.output, output)
@@ -0,0 +1,155 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package typeinfo
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/vitess/go/sqltypes"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+const (
+	blobBinaryTypeParam_Length = "length"
+)
+
+// blobBinaryType handles the BLOB types, whose values are written as Blobs in the same way as blobStringType.
+type blobBinaryType struct {
+	sqlBinaryType sql.StringType
+}
+
+var _ TypeInfo = (*blobBinaryType)(nil)
+var LongBlobType = &blobBinaryType{sql.LongBlob}
+
+func CreateBlobBinaryTypeFromParams(params map[string]string) (TypeInfo, error) {
+	var length int64
+	var err error
+	if lengthStr, ok := params[blobBinaryTypeParam_Length]; ok {
+		length, err = strconv.ParseInt(lengthStr, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		return nil, fmt.Errorf(`create blobbinary type info is missing param "%v"`, blobBinaryTypeParam_Length)
+	}
+	sqlType, err := sql.CreateBinary(sqltypes.Blob, length)
+	if err != nil {
+		return nil, err
+	}
+	return &blobBinaryType{sqlType}, nil
+}
+
+// ConvertNomsValueToValue implements TypeInfo interface.
+func (ti *blobBinaryType) ConvertNomsValueToValue(ctx context.Context, v types.Value) (interface{}, error) {
+	if val, ok := v.(types.Blob); ok {
+		return fromBlob(ctx, val)
+	}
+	if _, ok := v.(types.Null); ok || v == nil {
+		return nil, nil
+	}
+	return nil, fmt.Errorf(`"%v" cannot convert NomsKind "%v" to a value`, ti.String(), v.Kind())
+}
+
+// ConvertValueToNomsValue implements TypeInfo interface.
+func (ti *blobBinaryType) ConvertValueToNomsValue(ctx context.Context, vrw types.ValueReadWriter, v interface{}) (types.Value, error) {
+	if v == nil {
+		return types.NullValue, nil
+	}
+	strVal, err := ti.sqlBinaryType.Convert(v)
+	if err != nil {
+		return nil, err
+	}
+	val, ok := strVal.(string)
+	if ok {
+		return toBlob(ctx, vrw, ti, val)
+	}
+	return nil, fmt.Errorf(`"%v" cannot convert value "%v" of type "%T" as it is invalid`, ti.String(), v, v)
+}
+
+// Equals implements TypeInfo interface.
+func (ti *blobBinaryType) Equals(other TypeInfo) bool {
+	if other == nil {
+		return false
+	}
+	if ti2, ok := other.(*blobBinaryType); ok {
+		return ti.sqlBinaryType.MaxCharacterLength() == ti2.sqlBinaryType.MaxCharacterLength()
+	}
+	return false
+}
+
+// FormatValue implements TypeInfo interface.
+func (ti *blobBinaryType) FormatValue(ctx context.Context, v types.Value) (*string, error) {
+	if val, ok := v.(types.Blob); ok {
+		resStr, err := fromBlob(ctx, val)
+		if err != nil {
+			return nil, err
+		}
+		return &resStr, nil
+	}
+	if _, ok := v.(types.Null); ok || v == nil {
+		return nil, nil
+	}
+	return nil, fmt.Errorf(`"%v" cannot convert NomsKind "%v" to a string`, ti.String(), v.Kind())
+}
+
+// GetTypeIdentifier implements TypeInfo interface.
+func (ti *blobBinaryType) GetTypeIdentifier() Identifier {
+	return BlobBinaryTypeIdentifier
+}
+
+// GetTypeParams implements TypeInfo interface.
+func (ti *blobBinaryType) GetTypeParams() map[string]string {
+	return map[string]string{
+		blobBinaryTypeParam_Length: strconv.FormatInt(ti.sqlBinaryType.MaxCharacterLength(), 10),
+	}
+}
+
+// IsValid implements TypeInfo interface.
+func (ti *blobBinaryType) IsValid(v types.Value) bool {
+	if val, ok := v.(types.Blob); ok {
+		return int64(val.Len()) <= ti.sqlBinaryType.MaxByteLength()
+	}
+	if _, ok := v.(types.Null); ok || v == nil {
+		return true
+	}
+	return false
+}
+
+// NomsKind implements TypeInfo interface.
+func (ti *blobBinaryType) NomsKind() types.NomsKind {
+	return types.BlobKind
+}
+
+// ParseValue implements TypeInfo interface.
+func (ti *blobBinaryType) ParseValue(ctx context.Context, vrw types.ValueReadWriter, str *string) (types.Value, error) {
+	if str == nil {
+		return types.NullValue, nil
+	}
+	return ti.ConvertValueToNomsValue(ctx, vrw, *str)
+}
+
+// String implements TypeInfo interface.
+func (ti *blobBinaryType) String() string {
+	return fmt.Sprintf(`BlobBinary(%v)`, ti.sqlBinaryType.MaxCharacterLength())
+}
+
+// ToSqlType implements TypeInfo interface.
+func (ti *blobBinaryType) ToSqlType() sql.Type {
+	return ti.sqlBinaryType
+}
@@ -0,0 +1,86 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package typeinfo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestBlobBinaryConvertValueToNomsValue(t *testing.T) {
+	tests := []struct {
+		typ         *blobBinaryType
+		input       interface{}
+		output      string
+		expectedErr bool
+	}{
+		{
+			LongBlobType,
+			[]byte{0, 1, 2, 254, 255},
+			string([]byte{0, 1, 2, 254, 255}),
+			false,
+		},
+		{
+			LongBlobType,
+			"some text",
+			"some text",
+			false,
+		},
+		{
+			&blobBinaryType{sql.TinyBlob},
+			strings.Repeat("a", 255),
+			strings.Repeat("a", 255),
+			false,
+		},
+		{
+			&blobBinaryType{sql.TinyBlob},
+			strings.Repeat("a", 256),
+			"",
+			true,
+		},
+	}
+
+	vrw := types.NewMemoryValueStore()
+	for _, test := range tests {
+		t.Run(fmt.Sprintf(`%v %.20v`, test.typ.String(), test.input), func(t *testing.T) {
+			output, err := test.typ.ConvertValueToNomsValue(context.Background(), vrw, test.input)
+			if !test.expectedErr {
+				require.NoError(t, err)
+				require.Equal(t, types.BlobKind, output.Kind())
+				val, err := test.typ.ConvertNomsValueToValue(context.Background(), output)
+				require.NoError(t, err)
+				assert.Equal(t, test.output, val)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestBlobBinaryIsValid(t *testing.T) {
+	vrw := types.NewMemoryValueStore()
+	val, err := LongBlobType.ConvertValueToNomsValue(context.Background(), vrw, strings.Repeat("a", 256))
+	require.NoError(t, err)
+	assert.True(t, LongBlobType.IsValid(val))
+	assert.False(t, (&blobBinaryType{sql.TinyBlob}).IsValid(val))
+}
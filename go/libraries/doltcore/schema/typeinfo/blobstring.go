@@ -0,0 +1,189 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package typeinfo
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/vitess/go/sqltypes"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+const (
+	blobStringTypeParam_Collate = "collate"
+	blobStringTypeParam_Length  = "length"
+)
+
+// blobStringType handles LONGTEXT. Rather than being stored inline in the row, values are written as Blobs, which
+// are chunked by their content so that versions of large values share the chunks they have in common.
+type blobStringType struct {
+	sqlStringType sql.StringType
+}
+
+var _ TypeInfo = (*blobStringType)(nil)
+var LongTextType = &blobStringType{sql.LongText}
+
+func CreateBlobStringTypeFromParams(params map[string]string) (TypeInfo, error) {
+	var collation sql.Collation
+	var err error
+	if collationStr, ok := params[blobStringTypeParam_Collate]; ok {
+		collation, err = sql.ParseCollation(nil, &collationStr, false)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		return nil, fmt.Errorf(`create blobstring type info is missing param "%v"`, blobStringTypeParam_Collate)
+	}
+	var length int64
+	if lengthStr, ok := params[blobStringTypeParam_Length]; ok {
+		length, err = strconv.ParseInt(lengthStr, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		return nil, fmt.Errorf(`create blobstring type info is missing param "%v"`, blobStringTypeParam_Length)
+	}
+	sqlType, err := sql.CreateString(sqltypes.Text, length, collation)
+	if err != nil {
+		return nil, err
+	}
+	return &blobStringType{sqlType}, nil
+}
+
+// ConvertNomsValueToValue implements TypeInfo interface.
+func (ti *blobStringType) ConvertNomsValueToValue(ctx context.Context, v types.Value) (interface{}, error) {
+	if val, ok := v.(types.Blob); ok {
+		return fromBlob(ctx, val)
+	}
+	if _, ok := v.(types.Null); ok || v == nil {
+		return nil, nil
+	}
+	return nil, fmt.Errorf(`"%v" cannot convert NomsKind "%v" to a value`, ti.String(), v.Kind())
+}
+
+// ConvertValueToNomsValue implements TypeInfo interface.
+func (ti *blobStringType) ConvertValueToNomsValue(ctx context.Context, vrw types.ValueReadWriter, v interface{}) (types.Value, error) {
+	if v == nil {
+		return types.NullValue, nil
+	}
+	strVal, err := ti.sqlStringType.Convert(v)
+	if err != nil {
+		return nil, err
+	}
+	val, ok := strVal.(string)
+	if ok {
+		return toBlob(ctx, vrw, ti, val)
+	}
+	return nil, fmt.Errorf(`"%v" cannot convert value "%v" of type "%T" as it is invalid`, ti.String(), v, v)
+}
+
+// Equals implements TypeInfo interface.
+func (ti *blobStringType) Equals(other TypeInfo) bool {
+	if other == nil {
+		return false
+	}
+	if ti2, ok := other.(*blobStringType); ok {
+		return ti.sqlStringType.MaxCharacterLength() == ti2.sqlStringType.MaxCharacterLength() &&
+			ti.sqlStringType.Collation() == ti2.sqlStringType.Collation()
+	}
+	return false
+}
+
+// FormatValue implements TypeInfo interface.
+func (ti *blobStringType) FormatValue(ctx context.Context, v types.Value) (*string, error) {
+	if val, ok := v.(types.Blob); ok {
+		resStr, err := fromBlob(ctx, val)
+		if err != nil {
+			return nil, err
+		}
+		return &resStr, nil
+	}
+	if _, ok := v.(types.Null); ok || v == nil {
+		return nil, nil
+	}
+	return nil, fmt.Errorf(`"%v" cannot convert NomsKind "%v" to a string`, ti.String(), v.Kind())
+}
+
+// GetTypeIdentifier implements TypeInfo interface.
+func (ti *blobStringType) GetTypeIdentifier() Identifier {
+	return BlobStringTypeIdentifier
+}
+
+// GetTypeParams implements TypeInfo interface.
+func (ti *blobStringType) GetTypeParams() map[string]string {
+	return map[string]string{
+		blobStringTypeParam_Collate: ti.sqlStringType.Collation().String(),
+		blobStringTypeParam_Length:  strconv.FormatInt(ti.sqlStringType.MaxCharacterLength(), 10),
+	}
+}
+
+// IsValid implements TypeInfo interface.
+func (ti *blobStringType) IsValid(v types.Value) bool {
+	if val, ok := v.(types.Blob); ok {
+		return int64(val.Len()) <= ti.sqlStringType.MaxByteLength()
+	}
+	if _, ok := v.(types.Null); ok || v == nil {
+		return true
+	}
+	return false
+}
+
+// NomsKind implements TypeInfo interface.
+func (ti *blobStringType) NomsKind() types.NomsKind {
+	return types.BlobKind
+}
+
+// ParseValue implements TypeInfo interface.
+func (ti *blobStringType) ParseValue(ctx context.Context, vrw types.ValueReadWriter, str *string) (types.Value, error) {
+	if str == nil {
+		return types.NullValue, nil
+	}
+	return ti.ConvertValueToNomsValue(ctx, vrw, *str)
+}
+
+// String implements TypeInfo interface.
+func (ti *blobStringType) String() string {
+	return fmt.Sprintf(`BlobString(%v, %v)`, ti.sqlStringType.Collation().String(), ti.sqlStringType.MaxCharacterLength())
+}
+
+// ToSqlType implements TypeInfo interface.
+func (ti *blobStringType) ToSqlType() sql.Type {
+	return ti.sqlStringType
+}
+
+// fromBlob reads the contents of the Blob given, which are read from the chunks they're stored in as needed.
+func fromBlob(ctx context.Context, b types.Blob) (string, error) {
+	var sb strings.Builder
+	sb.Grow(int(b.Len()))
+	_, err := io.Copy(&sb, b.Reader(ctx))
+	if err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// toBlob writes the value given as a Blob, whose chunks are written to the ValueReadWriter given.
+func toBlob(ctx context.Context, vrw types.ValueReadWriter, ti TypeInfo, val string) (types.Value, error) {
+	if vrw == nil {
+		return nil, fmt.Errorf(`"%v" cannot store a value without a ValueReadWriter`, ti.String())
+	}
+	return types.NewBlob(ctx, vrw, strings.NewReader(val))
+}
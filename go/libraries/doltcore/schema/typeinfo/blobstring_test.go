@@ -0,0 +1,124 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package typeinfo
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestBlobStringConvertValueToNomsValue(t *testing.T) {
+	tests := []struct {
+		input       interface{}
+		output      string
+		expectedErr bool
+	}{
+		{
+			"  This is a sentence.  ",
+			"  This is a sentence.  ",
+			false,
+		},
+		{
+			[]byte("some bytes"),
+			"some bytes",
+			false,
+		},
+		{
+			int64(28354),
+			"28354",
+			false,
+		},
+		{
+			time.Date(2030, 1, 2, 4, 6, 3, 472382485, time.UTC),
+			"2030-01-02 04:06:03.472382",
+			false,
+		},
+		{
+			strings.Repeat("abc", 10000),
+			strings.Repeat("abc", 10000),
+			false,
+		},
+	}
+
+	vrw := types.NewMemoryValueStore()
+	for _, test := range tests {
+		t.Run(fmt.Sprintf(`%v %.20v`, LongTextType.String(), test.input), func(t *testing.T) {
+			output, err := LongTextType.ConvertValueToNomsValue(context.Background(), vrw, test.input)
+			if !test.expectedErr {
+				require.NoError(t, err)
+				require.Equal(t, types.BlobKind, output.Kind())
+				str, err := LongTextType.FormatValue(context.Background(), output)
+				require.NoError(t, err)
+				assert.Equal(t, test.output, *str)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestBlobStringRequiresValueReadWriter(t *testing.T) {
+	_, err := LongTextType.ConvertValueToNomsValue(context.Background(), nil, "abc")
+	assert.Error(t, err)
+}
+
+func TestBlobStringSharesChunks(t *testing.T) {
+	vrw := types.NewMemoryValueStore()
+	rnd := rand.New(rand.NewSource(0))
+	words := []string{"all", "work", "and", "no", "play", "makes", "jack", "a", "dull", "boy"}
+	var sb strings.Builder
+	for sb.Len() < 200000 {
+		sb.WriteString(words[rnd.Intn(len(words))])
+		sb.WriteByte(' ')
+	}
+	doc := sb.String()
+
+	v1, err := LongTextType.ConvertValueToNomsValue(context.Background(), vrw, doc)
+	require.NoError(t, err)
+	v2, err := LongTextType.ConvertValueToNomsValue(context.Background(), vrw, doc+"The end.")
+	require.NoError(t, err)
+	require.False(t, v1.Equals(v2))
+
+	refs := func(v types.Value) map[hash.Hash]struct{} {
+		res := make(map[hash.Hash]struct{})
+		err := v.WalkRefs(vrw.Format(), func(r types.Ref) error {
+			res[r.TargetHash()] = struct{}{}
+			return nil
+		})
+		require.NoError(t, err)
+		return res
+	}
+
+	refs1, refs2 := refs(v1), refs(v2)
+	require.True(t, len(refs1) > 1, "a large value should be stored across several chunks")
+	shared := 0
+	for h := range refs1 {
+		if _, ok := refs2[h]; ok {
+			shared++
+		}
+	}
+	// only the chunks at the end of the value should differ
+	assert.True(t, shared >= len(refs1)-1, "%d of %d chunks are shared", shared, len(refs1))
+}
@@ -15,8 +15,10 @@
 package typeinfo
 
 import (
+	"context"
 	"math"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/dolthub/go-mysql-server/sql"
@@ -24,6 +26,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/dolthub/dolt/go/libraries/utils/mathutil"
+	"github.com/dolthub/dolt/go/store/types"
 )
 
 func generateBitTypes(t *testing.T, numOfTypes uint16) []TypeInfo {
@@ -93,6 +96,24 @@ func generateEnumType(t *testing.T, numOfElements int) *enumType {
 	return &enumType{sql.MustCreateEnumType(vals, sql.Collation_Default)}
 }
 
+func generateJSONDocument(t *testing.T, text string) types.Value {
+	doc, err := JSONType.ConvertValueToNomsValue(context.Background(), nil, text)
+	require.NoError(t, err)
+	return doc
+}
+
+func generateBlob(t *testing.T, vrw types.ValueReadWriter, str string) types.Value {
+	b, err := types.NewBlob(context.Background(), vrw, strings.NewReader(str))
+	require.NoError(t, err)
+	return b
+}
+
+func generateGeometry(t *testing.T, wkt string) types.Value {
+	g, err := GeometryType.ConvertValueToNomsValue(context.Background(), nil, wkt)
+	require.NoError(t, err)
+	return g
+}
+
 func generateSetTypes(t *testing.T, numOfTypes int64) []TypeInfo {
 	res := make([]TypeInfo, numOfTypes)
 	for i := int64(1); i <= numOfTypes; i++ {
@@ -172,3 +193,21 @@ func loop(t *testing.T, start int64, endInclusive int64, numOfSteps uint16, loop
 		currentVal = int64(math.Round(fCurrentVal))
 	}
 }
+
+// humanReadableString returns a description of the value for use in test names, as tuples cannot describe themselves.
+func humanReadableString(val types.Value) string {
+	if b, ok := val.(types.Blob); ok {
+		str, err := fromBlob(context.Background(), b)
+		if err != nil {
+			return "blob"
+		}
+		return str
+	}
+	if _, ok := val.(types.Tuple); ok {
+		if str, err := JSONType.FormatValue(context.Background(), val); err == nil && str != nil {
+			return *str
+		}
+		return "tuple"
+	}
+	return val.HumanReadableString()
+}
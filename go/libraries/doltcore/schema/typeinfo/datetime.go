@@ -15,6 +15,7 @@
 package typeinfo
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -60,7 +61,7 @@ func CreateDatetimeTypeFromParams(params map[string]string) (TypeInfo, error) {
 }
 
 // ConvertNomsValueToValue implements TypeInfo interface.
-func (ti *datetimeType) ConvertNomsValueToValue(v types.Value) (interface{}, error) {
+func (ti *datetimeType) ConvertNomsValueToValue(ctx context.Context, v types.Value) (interface{}, error) {
 	if val, ok := v.(types.Timestamp); ok {
 		return time.Time(val).UTC(), nil
 	}
@@ -71,7 +72,7 @@ func (ti *datetimeType) ConvertNomsValueToValue(v types.Value) (interface{}, err
 }
 
 // ConvertValueToNomsValue implements TypeInfo interface.
-func (ti *datetimeType) ConvertValueToNomsValue(v interface{}) (types.Value, error) {
+func (ti *datetimeType) ConvertValueToNomsValue(ctx context.Context, vrw types.ValueReadWriter, v interface{}) (types.Value, error) {
 	//TODO: handle the zero value as a special case that is valid for all ranges
 	if v == nil {
 		return types.NullValue, nil
@@ -99,11 +100,11 @@ func (ti *datetimeType) Equals(other TypeInfo) bool {
 }
 
 // FormatValue implements TypeInfo interface.
-func (ti *datetimeType) FormatValue(v types.Value) (*string, error) {
+func (ti *datetimeType) FormatValue(ctx context.Context, v types.Value) (*string, error) {
 	if _, ok := v.(types.Null); ok || v == nil {
 		return nil, nil
 	}
-	timeVal, err := ti.ConvertNomsValueToValue(v)
+	timeVal, err := ti.ConvertNomsValueToValue(ctx, v)
 	if err != nil {
 		return nil, err
 	}
@@ -162,7 +163,7 @@ func (ti *datetimeType) NomsKind() types.NomsKind {
 }
 
 // ParseValue implements TypeInfo interface.
-func (ti *datetimeType) ParseValue(str *string) (types.Value, error) {
+func (ti *datetimeType) ParseValue(ctx context.Context, vrw types.ValueReadWriter, str *string) (types.Value, error) {
 	if str == nil || *str == "" {
 		return types.NullValue, nil
 	}
@@ -15,6 +15,7 @@
 package typeinfo
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 
@@ -66,7 +67,7 @@ func CreateDecimalTypeFromParams(params map[string]string) (TypeInfo, error) {
 }
 
 // ConvertNomsValueToValue implements TypeInfo interface.
-func (ti *decimalType) ConvertNomsValueToValue(v types.Value) (interface{}, error) {
+func (ti *decimalType) ConvertNomsValueToValue(ctx context.Context, v types.Value) (interface{}, error) {
 	if val, ok := v.(types.Decimal); ok {
 		return decimal.Decimal(val).StringFixed(int32(ti.sqlDecimalType.Scale())), nil
 	}
@@ -77,7 +78,7 @@ func (ti *decimalType) ConvertNomsValueToValue(v types.Value) (interface{}, erro
 }
 
 // ConvertValueToNomsValue implements TypeInfo interface.
-func (ti *decimalType) ConvertValueToNomsValue(v interface{}) (types.Value, error) {
+func (ti *decimalType) ConvertValueToNomsValue(ctx context.Context, vrw types.ValueReadWriter, v interface{}) (types.Value, error) {
 	if v == nil {
 		return types.NullValue, nil
 	}
@@ -104,11 +105,11 @@ func (ti *decimalType) Equals(other TypeInfo) bool {
 }
 
 // FormatValue implements TypeInfo interface.
-func (ti *decimalType) FormatValue(v types.Value) (*string, error) {
+func (ti *decimalType) FormatValue(ctx context.Context, v types.Value) (*string, error) {
 	if _, ok := v.(types.Null); ok || v == nil {
 		return nil, nil
 	}
-	strVal, err := ti.ConvertNomsValueToValue(v)
+	strVal, err := ti.ConvertNomsValueToValue(ctx, v)
 	if err != nil {
 		return nil, err
 	}
@@ -153,11 +154,11 @@ func (ti *decimalType) NomsKind() types.NomsKind {
 }
 
 // ParseValue implements TypeInfo interface.
-func (ti *decimalType) ParseValue(str *string) (types.Value, error) {
+func (ti *decimalType) ParseValue(ctx context.Context, vrw types.ValueReadWriter, str *string) (types.Value, error) {
 	if str == nil || *str == "" {
 		return types.NullValue, nil
 	}
-	return ti.ConvertValueToNomsValue(*str)
+	return ti.ConvertValueToNomsValue(ctx, vrw, *str)
 }
 
 // String implements TypeInfo interface.
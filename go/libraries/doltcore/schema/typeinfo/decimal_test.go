@@ -15,6 +15,7 @@
 package typeinfo
 
 import (
+	"context"
 	"fmt"
 	"math/big"
 	"testing"
@@ -75,7 +76,7 @@ func TestDecimalConvertNomsValueToValue(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(fmt.Sprintf(`%v %v`, test.typ.String(), test.input), func(t *testing.T) {
-			output, err := test.typ.ConvertNomsValueToValue(test.input)
+			output, err := test.typ.ConvertNomsValueToValue(context.Background(), test.input)
 			if test.expectedErr {
 				assert.Error(t, err)
 			} else {
@@ -133,7 +134,7 @@ func TestDecimalConvertValueToNomsValue(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(fmt.Sprintf(`%v %v`, test.typ.String(), test.input), func(t *testing.T) {
-			output, err := test.typ.ConvertValueToNomsValue(test.input)
+			output, err := test.typ.ConvertValueToNomsValue(context.Background(), nil, test.input)
 			if !test.expectedErr {
 				require.NoError(t, err)
 				assert.True(t, test.output.Equals(output))
@@ -191,7 +192,7 @@ func TestDecimalFormatValue(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(fmt.Sprintf(`%v %v`, test.typ.String(), test.input), func(t *testing.T) {
-			output, err := test.typ.FormatValue(test.input)
+			output, err := test.typ.FormatValue(context.Background(), test.input)
 			if test.expectedErr {
 				assert.Error(t, err)
 			} else {
@@ -261,7 +262,7 @@ func TestDecimalParseValue(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(fmt.Sprintf(`%v %v`, test.typ.String(), test.input), func(t *testing.T) {
-			output, err := test.typ.ParseValue(&test.input)
+			output, err := test.typ.ParseValue(context.Background(), nil, &test.input)
 			if !test.expectedErr {
 				require.NoError(t, err)
 				assert.True(t, test.output.Equals(output))
@@ -341,13 +342,13 @@ func TestDecimalMarshal(t *testing.T) {
 	for _, test := range tests {
 		t.Run(fmt.Sprintf("%v %v %v", test.precision, test.scale, test.val), func(t *testing.T) {
 			typ := &decimalType{sql.MustCreateDecimalType(test.precision, test.scale)}
-			val, err := typ.ConvertValueToNomsValue(test.val)
+			val, err := typ.ConvertValueToNomsValue(context.Background(), nil, test.val)
 			if test.expectedErr {
 				assert.Error(t, err)
 			} else {
 				require.NoError(t, err)
 				assert.Equal(t, test.expectedVal, typ.sqlDecimalType.MustConvert(decimal.Decimal(val.(types.Decimal))))
-				umar, err := typ.ConvertNomsValueToValue(val)
+				umar, err := typ.ConvertNomsValueToValue(context.Background(), val)
 				require.NoError(t, err)
 				testVal := typ.sqlDecimalType.MustConvert(test.val)
 				cmp, err := typ.sqlDecimalType.Compare(testVal, umar)
@@ -549,21 +550,21 @@ func TestDecimalRoundTrip(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(fmt.Sprintf(`%v %v %v`, test.typ.String(), test.input, test.output), func(t *testing.T) {
-			parsed, err := test.typ.ConvertValueToNomsValue(test.input)
+			parsed, err := test.typ.ConvertValueToNomsValue(context.Background(), nil, test.input)
 			if !test.expectedErr {
 				require.NoError(t, err)
-				output, err := test.typ.ConvertNomsValueToValue(parsed)
+				output, err := test.typ.ConvertNomsValueToValue(context.Background(), parsed)
 				require.NoError(t, err)
 				assert.Equal(t, test.output, output)
-				parsed2, err := test.typ.ParseValue(&test.input)
+				parsed2, err := test.typ.ParseValue(context.Background(), nil, &test.input)
 				require.NoError(t, err)
 				assert.Equal(t, parsed, parsed2)
-				output2, err := test.typ.FormatValue(parsed2)
+				output2, err := test.typ.FormatValue(context.Background(), parsed2)
 				require.NoError(t, err)
 				assert.Equal(t, test.output, *output2)
 			} else {
 				assert.Error(t, err)
-				_, err = test.typ.ParseValue(&test.input)
+				_, err = test.typ.ParseValue(context.Background(), nil, &test.input)
 				assert.Error(t, err)
 			}
 		})
@@ -15,6 +15,7 @@
 package typeinfo
 
 import (
+	"context"
 	"encoding/gob"
 	"fmt"
 	"strings"
@@ -66,7 +67,7 @@ func CreateEnumTypeFromParams(params map[string]string) (TypeInfo, error) {
 }
 
 // ConvertNomsValueToValue implements TypeInfo interface.
-func (ti *enumType) ConvertNomsValueToValue(v types.Value) (interface{}, error) {
+func (ti *enumType) ConvertNomsValueToValue(ctx context.Context, v types.Value) (interface{}, error) {
 	if val, ok := v.(types.Uint); ok {
 		res, err := ti.sqlEnumType.Unmarshal(int64(val))
 		if err != nil {
@@ -81,7 +82,7 @@ func (ti *enumType) ConvertNomsValueToValue(v types.Value) (interface{}, error)
 }
 
 // ConvertValueToNomsValue implements TypeInfo interface.
-func (ti *enumType) ConvertValueToNomsValue(v interface{}) (types.Value, error) {
+func (ti *enumType) ConvertValueToNomsValue(ctx context.Context, vrw types.ValueReadWriter, v interface{}) (types.Value, error) {
 	if v == nil {
 		return types.NullValue, nil
 	}
@@ -111,11 +112,11 @@ func (ti *enumType) Equals(other TypeInfo) bool {
 }
 
 // FormatValue implements TypeInfo interface.
-func (ti *enumType) FormatValue(v types.Value) (*string, error) {
+func (ti *enumType) FormatValue(ctx context.Context, v types.Value) (*string, error) {
 	if _, ok := v.(types.Null); ok || v == nil {
 		return nil, nil
 	}
-	strVal, err := ti.ConvertNomsValueToValue(v)
+	strVal, err := ti.ConvertNomsValueToValue(ctx, v)
 	if err != nil {
 		return nil, err
 	}
@@ -167,7 +168,7 @@ func (ti *enumType) NomsKind() types.NomsKind {
 }
 
 // ParseValue implements TypeInfo interface.
-func (ti *enumType) ParseValue(str *string) (types.Value, error) {
+func (ti *enumType) ParseValue(ctx context.Context, vrw types.ValueReadWriter, str *string) (types.Value, error) {
 	if str == nil || *str == "" {
 		return types.NullValue, nil
 	}
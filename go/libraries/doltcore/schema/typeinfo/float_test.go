@@ -15,6 +15,7 @@
 package typeinfo
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"strconv"
@@ -62,7 +63,7 @@ func TestFloatConvertNomsValueToValue(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(fmt.Sprintf(`%v %v`, test.typ.String(), test.input), func(t *testing.T) {
-			output, err := test.typ.ConvertNomsValueToValue(test.input)
+			output, err := test.typ.ConvertNomsValueToValue(context.Background(), test.input)
 			if test.expectedErr {
 				assert.Error(t, err)
 			} else {
@@ -114,7 +115,7 @@ func TestFloatConvertValueToNomsValue(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(fmt.Sprintf(`%v %v`, test.typ.String(), test.input), func(t *testing.T) {
-			output, err := test.typ.ConvertValueToNomsValue(test.input)
+			output, err := test.typ.ConvertValueToNomsValue(context.Background(), nil, test.input)
 			if !test.expectedErr {
 				require.NoError(t, err)
 				assert.Equal(t, test.output, output)
@@ -160,7 +161,7 @@ func TestFloatFormatValue(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(fmt.Sprintf(`%v %v`, test.typ.String(), test.input), func(t *testing.T) {
-			output, err := test.typ.FormatValue(test.input)
+			output, err := test.typ.FormatValue(context.Background(), test.input)
 			if test.expectedErr {
 				assert.Error(t, err)
 			} else {
@@ -206,7 +207,7 @@ func TestFloatParseValue(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(fmt.Sprintf(`%v %v`, test.typ.String(), test.input), func(t *testing.T) {
-			output, err := test.typ.ParseValue(&test.input)
+			output, err := test.typ.ParseValue(context.Background(), nil, &test.input)
 			if !test.expectedErr {
 				require.NoError(t, err)
 				assert.Equal(t, test.output, output)
@@ -15,6 +15,7 @@
 package typeinfo
 
 import (
+	"context"
 	"fmt"
 	"math"
 
@@ -33,7 +34,7 @@ var _ TypeInfo = (*inlineBlobType)(nil)
 var InlineBlobType = &inlineBlobType{sql.MustCreateBinary(sqltypes.VarBinary, math.MaxUint16)}
 
 // ConvertNomsValueToValue implements TypeInfo interface.
-func (ti *inlineBlobType) ConvertNomsValueToValue(v types.Value) (interface{}, error) {
+func (ti *inlineBlobType) ConvertNomsValueToValue(ctx context.Context, v types.Value) (interface{}, error) {
 	if val, ok := v.(types.InlineBlob); ok {
 		return string(val), nil
 	}
@@ -44,7 +45,7 @@ func (ti *inlineBlobType) ConvertNomsValueToValue(v types.Value) (interface{}, e
 }
 
 // ConvertValueToNomsValue implements TypeInfo interface.
-func (ti *inlineBlobType) ConvertValueToNomsValue(v interface{}) (types.Value, error) {
+func (ti *inlineBlobType) ConvertValueToNomsValue(ctx context.Context, vrw types.ValueReadWriter, v interface{}) (types.Value, error) {
 	if v == nil {
 		return types.NullValue, nil
 	}
@@ -69,9 +70,9 @@ func (ti *inlineBlobType) Equals(other TypeInfo) bool {
 }
 
 // FormatValue implements TypeInfo interface.
-func (ti *inlineBlobType) FormatValue(v types.Value) (*string, error) {
+func (ti *inlineBlobType) FormatValue(ctx context.Context, v types.Value) (*string, error) {
 	if val, ok := v.(types.InlineBlob); ok {
-		convVal, err := ti.ConvertNomsValueToValue(val)
+		convVal, err := ti.ConvertNomsValueToValue(ctx, val)
 		if err != nil {
 			return nil, err
 		}
@@ -114,7 +115,7 @@ func (ti *inlineBlobType) NomsKind() types.NomsKind {
 }
 
 // ParseValue implements TypeInfo interface.
-func (ti *inlineBlobType) ParseValue(str *string) (types.Value, error) {
+func (ti *inlineBlobType) ParseValue(ctx context.Context, vrw types.ValueReadWriter, str *string) (types.Value, error) {
 	if str == nil || *str == "" {
 		return types.NullValue, nil
 	}
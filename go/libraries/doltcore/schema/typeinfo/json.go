@@ -0,0 +1,235 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package typeinfo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/vitess/go/sqltypes"
+	"github.com/dolthub/vitess/go/vt/proto/query"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// jsonType stores JSON documents as a tree of noms values, so that a document's keys can be diffed and merged
+// individually. See json_doc.go for the encoding.
+type jsonType struct{}
+
+var _ TypeInfo = (*jsonType)(nil)
+
+var JSONType = &jsonType{}
+
+// ConvertNomsValueToValue implements TypeInfo interface.
+func (ti *jsonType) ConvertNomsValueToValue(ctx context.Context, v types.Value) (interface{}, error) {
+	if val, ok := v.(types.Tuple); ok {
+		return jsonText(val)
+	}
+	if _, ok := v.(types.Null); ok || v == nil {
+		return nil, nil
+	}
+	return nil, fmt.Errorf(`"%v" cannot convert NomsKind "%v" to a value`, ti.String(), v.Kind())
+}
+
+// ConvertValueToNomsValue implements TypeInfo interface.
+func (ti *jsonType) ConvertValueToNomsValue(ctx context.Context, vrw types.ValueReadWriter, v interface{}) (types.Value, error) {
+	if v == nil {
+		return types.NullValue, nil
+	}
+	if val, ok := v.(types.Tuple); ok && ti.IsValid(val) {
+		return val, nil
+	}
+	return jsonDocument(v)
+}
+
+// Equals implements TypeInfo interface.
+func (ti *jsonType) Equals(other TypeInfo) bool {
+	if other == nil {
+		return false
+	}
+	_, ok := other.(*jsonType)
+	return ok
+}
+
+// FormatValue implements TypeInfo interface.
+func (ti *jsonType) FormatValue(ctx context.Context, v types.Value) (*string, error) {
+	if val, ok := v.(types.Tuple); ok {
+		text, err := jsonText(val)
+		if err != nil {
+			return nil, err
+		}
+		return &text, nil
+	}
+	if _, ok := v.(types.Null); ok || v == nil {
+		return nil, nil
+	}
+	return nil, fmt.Errorf(`"%v" cannot convert NomsKind "%v" to a string`, ti.String(), v.Kind())
+}
+
+// GetTypeIdentifier implements TypeInfo interface.
+func (ti *jsonType) GetTypeIdentifier() Identifier {
+	return JSONTypeIdentifier
+}
+
+// GetTypeParams implements TypeInfo interface.
+func (ti *jsonType) GetTypeParams() map[string]string {
+	return nil
+}
+
+// IsValid implements TypeInfo interface.
+func (ti *jsonType) IsValid(v types.Value) bool {
+	if val, ok := v.(types.Tuple); ok {
+		return isJSONNode(val)
+	}
+	if _, ok := v.(types.Null); ok || v == nil {
+		return true
+	}
+	return false
+}
+
+// NomsKind implements TypeInfo interface.
+func (ti *jsonType) NomsKind() types.NomsKind {
+	return types.TupleKind
+}
+
+// ParseValue implements TypeInfo interface.
+func (ti *jsonType) ParseValue(ctx context.Context, vrw types.ValueReadWriter, str *string) (types.Value, error) {
+	if str == nil || *str == "" {
+		return types.NullValue, nil
+	}
+	return jsonDocument(*str)
+}
+
+// String implements TypeInfo interface.
+func (ti *jsonType) String() string {
+	return "JSON"
+}
+
+// ToSqlType implements TypeInfo interface.
+func (ti *jsonType) ToSqlType() sql.Type {
+	return JSONSqlType
+}
+
+// JSONSqlType is the SQL type of JSON columns. It differs from the engine's JSON type in representing documents as
+// their JSON text in a string, so that they can be compared with other values, and in rejecting invalid JSON text
+// rather than quoting it.
+var JSONSqlType sql.Type = sqlJSONType{}
+
+type sqlJSONType struct{}
+
+// Compare implements sql.Type interface.
+func (t sqlJSONType) Compare(a interface{}, b interface{}) (int, error) {
+	if a == nil && b == nil {
+		return 0, nil
+	} else if a == nil {
+		return -1, nil
+	} else if b == nil {
+		return 1, nil
+	}
+
+	aDoc, err := decodeJSON(a)
+	if err != nil {
+		return 0, err
+	}
+	bDoc, err := decodeJSON(b)
+	if err != nil {
+		return 0, err
+	}
+
+	aNum, aIsNum := aDoc.(json.Number)
+	bNum, bIsNum := bDoc.(json.Number)
+	if aIsNum && bIsNum {
+		aFloat, _ := aNum.Float64()
+		bFloat, _ := bNum.Float64()
+		switch {
+		case aFloat < bFloat:
+			return -1, nil
+		case aFloat > bFloat:
+			return 1, nil
+		default:
+			return 0, nil
+		}
+	}
+
+	aText, err := marshalJSON(aDoc)
+	if err != nil {
+		return 0, err
+	}
+	bText, err := marshalJSON(bDoc)
+	if err != nil {
+		return 0, err
+	}
+	return bytes.Compare(aText, bText), nil
+}
+
+// Convert implements sql.Type interface.
+func (t sqlJSONType) Convert(v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	doc, err := decodeJSON(v)
+	if err != nil {
+		return nil, err
+	}
+	text, err := marshalJSON(doc)
+	if err != nil {
+		return nil, err
+	}
+	return string(text), nil
+}
+
+// MustConvert implements sql.Type interface.
+func (t sqlJSONType) MustConvert(v interface{}) interface{} {
+	value, err := t.Convert(v)
+	if err != nil {
+		panic(err)
+	}
+	return value
+}
+
+// Promote implements sql.Type interface.
+func (t sqlJSONType) Promote() sql.Type {
+	return t
+}
+
+// SQL implements sql.Type interface.
+func (t sqlJSONType) SQL(v interface{}) (sqltypes.Value, error) {
+	if v == nil {
+		return sqltypes.NULL, nil
+	}
+	text, err := t.Convert(v)
+	if err != nil {
+		return sqltypes.Value{}, err
+	}
+	return sqltypes.MakeTrusted(sqltypes.TypeJSON, []byte(text.(string))), nil
+}
+
+// String implements sql.Type interface.
+func (t sqlJSONType) String() string {
+	return "JSON"
+}
+
+// Type implements sql.Type interface.
+func (t sqlJSONType) Type() query.Type {
+	return sqltypes.TypeJSON
+}
+
+// Zero implements sql.Type interface.
+func (t sqlJSONType) Zero() interface{} {
+	return "null"
+}
@@ -0,0 +1,426 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package typeinfo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// JSON documents are stored as nested tuples. Arrays and objects are tuples whose first field is the node's kind,
+// followed by the array's elements or the object's keys and values, in key order. Scalars are stored as the noms value
+// of the same kind, except at the top of a document, where they are wrapped in a tuple so that every document is a
+// tuple.
+const (
+	jsonScalarNode uint64 = iota
+	jsonArrayNode
+	jsonObjectNode
+)
+
+// jsonAbsent stands in for the value at a path which doesn't exist in a document.
+type jsonAbsent struct{}
+
+var jsonIdentifierRegex = regexp.MustCompile(`^[a-zA-Z_$][a-zA-Z0-9_$]*$`)
+
+// JSONPathDiff is a difference between two JSON documents at a single path.
+type JSONPathDiff struct {
+	// Path is the path in MySQL's JSON path syntax, such as $.a[2].
+	Path string
+	// From and To are the values at the path as JSON text, or nil where the path doesn't exist.
+	From *string
+	To   *string
+}
+
+// DiffJSON returns the paths at which two JSON documents differ. Objects are compared key by key, and arrays of the same
+// length element by element.
+func DiffJSON(from, to types.Value) ([]JSONPathDiff, error) {
+	fromDoc, err := jsonNomsDocToGo(from)
+	if err != nil {
+		return nil, err
+	}
+	toDoc, err := jsonNomsDocToGo(to)
+	if err != nil {
+		return nil, err
+	}
+
+	var diffs []JSONPathDiff
+	err = diffJSONValues("$", fromDoc, toDoc, &diffs)
+	if err != nil {
+		return nil, err
+	}
+	return diffs, nil
+}
+
+// MergeJSON merges the changes made to a JSON document on two branches, key by key. Returns true if both branches
+// changed the same value in different ways.
+func MergeJSON(base, ours, theirs types.Value) (types.Value, bool, error) {
+	if !isJSONDocument(base) || !isJSONDocument(ours) || !isJSONDocument(theirs) {
+		return nil, true, nil
+	}
+
+	baseDoc, err := jsonNomsDocToGo(base)
+	if err != nil {
+		return nil, false, err
+	}
+	ourDoc, err := jsonNomsDocToGo(ours)
+	if err != nil {
+		return nil, false, err
+	}
+	theirDoc, err := jsonNomsDocToGo(theirs)
+	if err != nil {
+		return nil, false, err
+	}
+
+	merged, ok := mergeJSONValues(baseDoc, ourDoc, theirDoc)
+	if !ok {
+		return nil, true, nil
+	}
+
+	doc, err := jsonGoToNomsDoc(merged)
+	if err != nil {
+		return nil, false, err
+	}
+	return doc, false, nil
+}
+
+func diffJSONValues(path string, from, to interface{}, diffs *[]JSONPathDiff) error {
+	if reflect.DeepEqual(from, to) {
+		return nil
+	}
+
+	fromObj, fromIsObj := from.(map[string]interface{})
+	toObj, toIsObj := to.(map[string]interface{})
+	if fromIsObj && toIsObj {
+		for _, key := range unionKeys(fromObj, toObj) {
+			err := diffJSONValues(path+jsonKeyPath(key), jsonObjectValue(fromObj, key), jsonObjectValue(toObj, key), diffs)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	fromArr, fromIsArr := from.([]interface{})
+	toArr, toIsArr := to.([]interface{})
+	if fromIsArr && toIsArr && len(fromArr) == len(toArr) {
+		for i := range fromArr {
+			err := diffJSONValues(fmt.Sprintf("%s[%d]", path, i), fromArr[i], toArr[i], diffs)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	fromText, err := jsonPathValueText(from)
+	if err != nil {
+		return err
+	}
+	toText, err := jsonPathValueText(to)
+	if err != nil {
+		return err
+	}
+	*diffs = append(*diffs, JSONPathDiff{Path: path, From: fromText, To: toText})
+	return nil
+}
+
+func mergeJSONValues(base, ours, theirs interface{}) (interface{}, bool) {
+	switch {
+	case reflect.DeepEqual(ours, theirs):
+		return ours, true
+	case reflect.DeepEqual(base, ours):
+		return theirs, true
+	case reflect.DeepEqual(base, theirs):
+		return ours, true
+	}
+
+	baseObj, baseIsObj := base.(map[string]interface{})
+	ourObj, oursIsObj := ours.(map[string]interface{})
+	theirObj, theirsIsObj := theirs.(map[string]interface{})
+	if !baseIsObj || !oursIsObj || !theirsIsObj {
+		return nil, false
+	}
+
+	merged := make(map[string]interface{})
+	for _, key := range unionKeys(baseObj, ourObj, theirObj) {
+		val, ok := mergeJSONValues(jsonObjectValue(baseObj, key), jsonObjectValue(ourObj, key), jsonObjectValue(theirObj, key))
+		if !ok {
+			return nil, false
+		}
+		if _, absent := val.(jsonAbsent); !absent {
+			merged[key] = val
+		}
+	}
+	return merged, true
+}
+
+func jsonObjectValue(obj map[string]interface{}, key string) interface{} {
+	if val, ok := obj[key]; ok {
+		return val
+	}
+	return jsonAbsent{}
+}
+
+func unionKeys(objs ...map[string]interface{}) []string {
+	seen := make(map[string]struct{})
+	var keys []string
+	for _, obj := range objs {
+		for key := range obj {
+			if _, ok := seen[key]; !ok {
+				seen[key] = struct{}{}
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func jsonKeyPath(key string) string {
+	if jsonIdentifierRegex.MatchString(key) {
+		return "." + key
+	}
+	return "." + strconv.Quote(key)
+}
+
+func jsonPathValueText(v interface{}) (*string, error) {
+	if _, ok := v.(jsonAbsent); ok {
+		return nil, nil
+	}
+	text, err := marshalJSON(v)
+	if err != nil {
+		return nil, err
+	}
+	str := string(text)
+	return &str, nil
+}
+
+// jsonDocument converts JSON text, or a value of the kind decoded from JSON text, to a stored JSON document.
+func jsonDocument(v interface{}) (types.Tuple, error) {
+	doc, err := decodeJSON(v)
+	if err != nil {
+		return types.Tuple{}, err
+	}
+	return jsonGoToNomsDoc(doc)
+}
+
+// decodeJSON decodes JSON text, or re-encodes a value of the kind decoded from JSON text, with numbers decoded as
+// json.Number.
+func decodeJSON(v interface{}) (interface{}, error) {
+	var text []byte
+	switch v := v.(type) {
+	case string:
+		text = []byte(v)
+	case []byte:
+		text = v
+	default:
+		var err error
+		text, err = json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(text))
+	dec.UseNumber()
+	var doc interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("Invalid JSON text: %s", err.Error())
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("Invalid JSON text: unexpected data after the document")
+	}
+	return doc, nil
+}
+
+// jsonText returns a stored JSON document as JSON text.
+func jsonText(doc types.Tuple) (string, error) {
+	v, err := jsonNomsDocToGo(doc)
+	if err != nil {
+		return "", err
+	}
+	text, err := marshalJSON(v)
+	if err != nil {
+		return "", err
+	}
+	return string(text), nil
+}
+
+func marshalJSON(v interface{}) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+func isJSONNode(t types.Tuple) bool {
+	if t.Len() == 0 {
+		return false
+	}
+	kind, err := t.Get(0)
+	if err != nil {
+		return false
+	}
+	u, ok := kind.(types.Uint)
+	return ok && uint64(u) <= jsonObjectNode
+}
+
+func isJSONDocument(v types.Value) bool {
+	t, ok := v.(types.Tuple)
+	return ok && isJSONNode(t)
+}
+
+func jsonGoToNomsDoc(v interface{}) (types.Tuple, error) {
+	val, err := jsonGoToNoms(v)
+	if err != nil {
+		return types.Tuple{}, err
+	}
+	if t, ok := val.(types.Tuple); ok {
+		return t, nil
+	}
+	return types.NewTuple(types.Format_Default, types.Uint(jsonScalarNode), val)
+}
+
+func jsonGoToNoms(v interface{}) (types.Value, error) {
+	switch v := v.(type) {
+	case nil:
+		return types.NullValue, nil
+	case bool:
+		return types.Bool(v), nil
+	case string:
+		return types.String(v), nil
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return types.Int(i), nil
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return nil, err
+		}
+		return types.Float(f), nil
+	case float64:
+		return jsonGoToNoms(json.Number(strconv.FormatFloat(v, 'g', -1, 64)))
+	case []interface{}:
+		vals := []types.Value{types.Uint(jsonArrayNode)}
+		for _, elem := range v {
+			val, err := jsonGoToNoms(elem)
+			if err != nil {
+				return nil, err
+			}
+			vals = append(vals, val)
+		}
+		return types.NewTuple(types.Format_Default, vals...)
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		vals := []types.Value{types.Uint(jsonObjectNode)}
+		for _, key := range keys {
+			val, err := jsonGoToNoms(v[key])
+			if err != nil {
+				return nil, err
+			}
+			vals = append(vals, types.String(key), val)
+		}
+		return types.NewTuple(types.Format_Default, vals...)
+	default:
+		return nil, fmt.Errorf(`unexpected value of type "%T" in a JSON document`, v)
+	}
+}
+
+func jsonNomsDocToGo(v types.Value) (interface{}, error) {
+	if !isJSONDocument(v) {
+		if types.IsNull(v) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf(`NomsKind "%v" is not a JSON document`, v.Kind())
+	}
+	return jsonNomsToGo(v)
+}
+
+func jsonNomsToGo(v types.Value) (interface{}, error) {
+	switch v := v.(type) {
+	case types.Null:
+		return nil, nil
+	case types.Bool:
+		return bool(v), nil
+	case types.String:
+		return string(v), nil
+	case types.Int:
+		return json.Number(strconv.FormatInt(int64(v), 10)), nil
+	case types.Float:
+		return json.Number(strconv.FormatFloat(float64(v), 'g', -1, 64)), nil
+	case types.Tuple:
+		vals, err := v.AsSlice()
+		if err != nil {
+			return nil, err
+		}
+		if len(vals) == 0 {
+			return nil, fmt.Errorf("empty JSON node")
+		}
+
+		kind, ok := vals[0].(types.Uint)
+		if !ok {
+			return nil, fmt.Errorf("malformed JSON node")
+		}
+
+		switch uint64(kind) {
+		case jsonScalarNode:
+			if len(vals) != 2 {
+				return nil, fmt.Errorf("malformed JSON scalar")
+			}
+			return jsonNomsToGo(vals[1])
+		case jsonArrayNode:
+			arr := make([]interface{}, len(vals)-1)
+			for i, elem := range vals[1:] {
+				arr[i], err = jsonNomsToGo(elem)
+				if err != nil {
+					return nil, err
+				}
+			}
+			return arr, nil
+		case jsonObjectNode:
+			if len(vals)%2 != 1 {
+				return nil, fmt.Errorf("malformed JSON object")
+			}
+			obj := make(map[string]interface{}, len(vals)/2)
+			for i := 1; i < len(vals); i += 2 {
+				key, ok := vals[i].(types.String)
+				if !ok {
+					return nil, fmt.Errorf("malformed JSON object")
+				}
+				obj[string(key)], err = jsonNomsToGo(vals[i+1])
+				if err != nil {
+					return nil, err
+				}
+			}
+			return obj, nil
+		}
+	}
+	return nil, fmt.Errorf(`unexpected NomsKind "%v" in a JSON document`, v.Kind())
+}
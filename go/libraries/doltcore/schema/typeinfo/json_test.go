@@ -0,0 +1,204 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package typeinfo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestJSONConvertValueToNomsValue(t *testing.T) {
+	tests := []struct {
+		input       interface{}
+		output      string
+		expectedErr bool
+	}{
+		{`{"b": 1, "a": [1.5, "x", null, true]}`, `{"a":[1.5,"x",null,true],"b":1}`, false},
+		{[]byte(` "<a & b>" `), `"<a & b>"`, false},
+		{map[string]interface{}{"k": []interface{}{float64(2), "v"}}, `{"k":[2,"v"]}`, false},
+		{float64(12345678901), `12345678901`, false},
+		{`{"a": }`, "", true},
+		{`{} {}`, "", true},
+		{`abc`, "", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.output, func(t *testing.T) {
+			val, err := JSONType.ConvertValueToNomsValue(context.Background(), nil, test.input)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			text, err := JSONType.ConvertNomsValueToValue(context.Background(), val)
+			require.NoError(t, err)
+			assert.Equal(t, test.output, text)
+		})
+	}
+}
+
+func TestJSONConvertMalformedNomsValue(t *testing.T) {
+	obj, err := types.NewTuple(types.Format_Default, types.Uint(jsonObjectNode), types.Int(1), types.String("v"))
+	require.NoError(t, err)
+	_, err = JSONType.ConvertNomsValueToValue(context.Background(), obj)
+	assert.Error(t, err)
+}
+
+func TestDiffJSON(t *testing.T) {
+	str := func(s string) *string {
+		return &s
+	}
+
+	tests := []struct {
+		name     string
+		from     string
+		to       string
+		expected []JSONPathDiff
+	}{
+		{
+			name: "equal",
+			from: `{"a": 1}`,
+			to:   `{"a": 1}`,
+		},
+		{
+			name: "object keys",
+			from: `{"a": 1, "b": {"c": "x", "d": 2}, "gone": true}`,
+			to:   `{"a": 1, "b": {"c": "y", "d": 2}, "new key": [1]}`,
+			expected: []JSONPathDiff{
+				{Path: `$.b.c`, From: str(`"x"`), To: str(`"y"`)},
+				{Path: `$.gone`, From: str(`true`)},
+				{Path: `$."new key"`, To: str(`[1]`)},
+			},
+		},
+		{
+			name: "arrays",
+			from: `{"a": [1, 2, 3], "b": [1]}`,
+			to:   `{"a": [1, 5, 3], "b": [1, 2]}`,
+			expected: []JSONPathDiff{
+				{Path: `$.a[1]`, From: str(`2`), To: str(`5`)},
+				{Path: `$.b`, From: str(`[1]`), To: str(`[1,2]`)},
+			},
+		},
+		{
+			name: "scalars",
+			from: `1`,
+			to:   `"1"`,
+			expected: []JSONPathDiff{
+				{Path: `$`, From: str(`1`), To: str(`"1"`)},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			diffs, err := DiffJSON(generateJSONDocument(t, test.from), generateJSONDocument(t, test.to))
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, diffs)
+		})
+	}
+}
+
+func TestMergeJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     string
+		ours     string
+		theirs   string
+		expected string
+		conflict bool
+	}{
+		{
+			name:     "different keys",
+			base:     `{"a": 1, "b": 2, "c": 3}`,
+			ours:     `{"a": 10, "b": 2}`,
+			theirs:   `{"a": 1, "b": 20, "c": 3, "d": 4}`,
+			expected: `{"a":10,"b":20,"d":4}`,
+		},
+		{
+			name:     "nested objects",
+			base:     `{"a": {"x": 1, "y": 1}}`,
+			ours:     `{"a": {"x": 2, "y": 1}}`,
+			theirs:   `{"a": {"x": 1, "y": 2}}`,
+			expected: `{"a":{"x":2,"y":2}}`,
+		},
+		{
+			name:     "same change",
+			base:     `{"a": 1}`,
+			ours:     `{"a": 2}`,
+			theirs:   `{"a": 2}`,
+			expected: `{"a":2}`,
+		},
+		{
+			name:     "same key",
+			base:     `{"a": 1}`,
+			ours:     `{"a": 2}`,
+			theirs:   `{"a": 3}`,
+			conflict: true,
+		},
+		{
+			name:     "arrays",
+			base:     `[1, 2]`,
+			ours:     `[1, 3]`,
+			theirs:   `[0, 2]`,
+			conflict: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			merged, conflict, err := MergeJSON(generateJSONDocument(t, test.base), generateJSONDocument(t, test.ours), generateJSONDocument(t, test.theirs))
+			require.NoError(t, err)
+			require.Equal(t, test.conflict, conflict)
+			if !conflict {
+				text, err := JSONType.FormatValue(context.Background(), merged)
+				require.NoError(t, err)
+				assert.Equal(t, test.expected, *text)
+			}
+		})
+	}
+
+	_, conflict, err := MergeJSON(types.NullValue, generateJSONDocument(t, `1`), generateJSONDocument(t, `2`))
+	require.NoError(t, err)
+	assert.True(t, conflict)
+}
+
+func TestJSONSqlType(t *testing.T) {
+	text, err := JSONSqlType.Convert(`{"b": [1, 2], "a": "x"}`)
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":"x","b":[1,2]}`, text)
+	_, err = JSONSqlType.Convert(`not json`)
+	assert.Error(t, err)
+
+	tests := []struct {
+		a, b     interface{}
+		expected int
+	}{
+		{`{"a": 1, "b": 2}`, `{"b":2,"a":1}`, 0},
+		{`2`, `10`, -1},
+		{`2.5`, `2.50`, 0},
+		{`"b"`, `"a"`, 1},
+		{nil, `1`, -1},
+	}
+	for _, test := range tests {
+		cmp, err := JSONSqlType.Compare(test.a, test.b)
+		require.NoError(t, err)
+		assert.Equal(t, test.expected, cmp, "%v, %v", test.a, test.b)
+	}
+}
@@ -0,0 +1,271 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package typeinfo
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/vitess/go/sqltypes"
+	"github.com/dolthub/vitess/go/vt/proto/query"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/geometry"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+const (
+	spatialTypeParam_Subtype = "subtype"
+)
+
+// spatialType handles the POINT, LINESTRING, POLYGON and GEOMETRY types, whose values are stored inline as their WKB.
+type spatialType struct {
+	sqlSpatialType sqlSpatialType
+}
+
+var _ TypeInfo = (*spatialType)(nil)
+
+var (
+	GeometryType   = &spatialType{sqlSpatialType{""}}
+	PointType      = &spatialType{sqlSpatialType{geometry.PointType}}
+	LineStringType = &spatialType{sqlSpatialType{geometry.LineStringType}}
+	PolygonType    = &spatialType{sqlSpatialType{geometry.PolygonType}}
+)
+
+func CreateSpatialTypeFromParams(params map[string]string) (TypeInfo, error) {
+	subtype, ok := params[spatialTypeParam_Subtype]
+	if !ok {
+		return nil, fmt.Errorf(`create spatial type info is missing param "%v"`, spatialTypeParam_Subtype)
+	}
+	for _, ti := range []*spatialType{GeometryType, PointType, LineStringType, PolygonType} {
+		if ti.sqlSpatialType.subtype == subtype {
+			return ti, nil
+		}
+	}
+	return nil, fmt.Errorf(`create spatial type info has unknown subtype "%v"`, subtype)
+}
+
+// ConvertNomsValueToValue implements TypeInfo interface.
+func (ti *spatialType) ConvertNomsValueToValue(ctx context.Context, v types.Value) (interface{}, error) {
+	if val, ok := v.(types.InlineBlob); ok {
+		return geometry.UnmarshalWKB(val)
+	}
+	if _, ok := v.(types.Null); ok || v == nil {
+		return nil, nil
+	}
+	return nil, fmt.Errorf(`"%v" cannot convert NomsKind "%v" to a value`, ti.String(), v.Kind())
+}
+
+// ConvertValueToNomsValue implements TypeInfo interface.
+func (ti *spatialType) ConvertValueToNomsValue(ctx context.Context, vrw types.ValueReadWriter, v interface{}) (types.Value, error) {
+	if v == nil {
+		return types.NullValue, nil
+	}
+	if val, ok := v.(types.InlineBlob); ok && ti.IsValid(val) {
+		return val, nil
+	}
+	g, err := ti.sqlSpatialType.Convert(v)
+	if err != nil {
+		return nil, err
+	}
+	return types.InlineBlob(geometry.MarshalWKB(g.(geometry.Geometry))), nil
+}
+
+// Equals implements TypeInfo interface.
+func (ti *spatialType) Equals(other TypeInfo) bool {
+	if other == nil {
+		return false
+	}
+	if ti2, ok := other.(*spatialType); ok {
+		return ti.sqlSpatialType.subtype == ti2.sqlSpatialType.subtype
+	}
+	return false
+}
+
+// FormatValue implements TypeInfo interface.
+func (ti *spatialType) FormatValue(ctx context.Context, v types.Value) (*string, error) {
+	if val, ok := v.(types.InlineBlob); ok {
+		g, err := geometry.UnmarshalWKB(val)
+		if err != nil {
+			return nil, err
+		}
+		res := g.String()
+		return &res, nil
+	}
+	if _, ok := v.(types.Null); ok || v == nil {
+		return nil, nil
+	}
+	return nil, fmt.Errorf(`"%v" cannot convert NomsKind "%v" to a string`, ti.String(), v.Kind())
+}
+
+// GetTypeIdentifier implements TypeInfo interface.
+func (ti *spatialType) GetTypeIdentifier() Identifier {
+	return SpatialTypeIdentifier
+}
+
+// GetTypeParams implements TypeInfo interface.
+func (ti *spatialType) GetTypeParams() map[string]string {
+	return map[string]string{spatialTypeParam_Subtype: ti.sqlSpatialType.subtype}
+}
+
+// IsValid implements TypeInfo interface.
+func (ti *spatialType) IsValid(v types.Value) bool {
+	if val, ok := v.(types.InlineBlob); ok {
+		g, err := geometry.UnmarshalWKB(val)
+		return err == nil && ti.sqlSpatialType.accepts(g)
+	}
+	if _, ok := v.(types.Null); ok || v == nil {
+		return true
+	}
+	return false
+}
+
+// NomsKind implements TypeInfo interface.
+func (ti *spatialType) NomsKind() types.NomsKind {
+	return types.InlineBlobKind
+}
+
+// ParseValue implements TypeInfo interface.
+func (ti *spatialType) ParseValue(ctx context.Context, vrw types.ValueReadWriter, str *string) (types.Value, error) {
+	if str == nil || *str == "" {
+		return types.NullValue, nil
+	}
+	return ti.ConvertValueToNomsValue(ctx, vrw, *str)
+}
+
+// String implements TypeInfo interface.
+func (ti *spatialType) String() string {
+	return fmt.Sprintf(`Spatial(%v)`, ti.sqlSpatialType.String())
+}
+
+// ToSqlType implements TypeInfo interface.
+func (ti *spatialType) ToSqlType() sql.Type {
+	return ti.sqlSpatialType
+}
+
+// The SQL types of spatial columns, whose values are geometry.Geometry. Values are sent to clients in MySQL's internal
+// format, and may be given either in that format or as WKT.
+var (
+	GeometrySqlType   sql.Type = GeometryType.sqlSpatialType
+	PointSqlType      sql.Type = PointType.sqlSpatialType
+	LineStringSqlType sql.Type = LineStringType.sqlSpatialType
+	PolygonSqlType    sql.Type = PolygonType.sqlSpatialType
+)
+
+// sqlSpatialType is the SQL type of geometries of a single subtype, or of any geometry when its subtype is empty.
+type sqlSpatialType struct {
+	subtype string
+}
+
+func (t sqlSpatialType) accepts(g geometry.Geometry) bool {
+	return t.subtype == "" || g.GeometryType() == t.subtype
+}
+
+// Compare implements sql.Type interface.
+func (t sqlSpatialType) Compare(a interface{}, b interface{}) (int, error) {
+	if a == nil && b == nil {
+		return 0, nil
+	} else if a == nil {
+		return -1, nil
+	} else if b == nil {
+		return 1, nil
+	}
+
+	aGeom, err := GeometrySqlType.Convert(a)
+	if err != nil {
+		return 0, err
+	}
+	bGeom, err := GeometrySqlType.Convert(b)
+	if err != nil {
+		return 0, err
+	}
+	return bytes.Compare(geometry.MarshalWKB(aGeom.(geometry.Geometry)), geometry.MarshalWKB(bGeom.(geometry.Geometry))), nil
+}
+
+// Convert implements sql.Type interface.
+func (t sqlSpatialType) Convert(v interface{}) (interface{}, error) {
+	var g geometry.Geometry
+	var err error
+	switch val := v.(type) {
+	case nil:
+		return nil, nil
+	case geometry.Geometry:
+		g = val
+	case []byte:
+		g, err = geometry.UnmarshalMySQL(val)
+	case string:
+		g, err = geometry.UnmarshalMySQL([]byte(val))
+		if err != nil {
+			g, err = geometry.ParseWKT(val)
+		}
+	default:
+		return nil, fmt.Errorf(`cannot convert value "%v" of type "%T" to %v`, v, v, t.String())
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !t.accepts(g) {
+		return nil, fmt.Errorf(`cannot convert %v to %v`, g.GeometryType(), t.String())
+	}
+	return g, nil
+}
+
+// MustConvert implements sql.Type interface.
+func (t sqlSpatialType) MustConvert(v interface{}) interface{} {
+	value, err := t.Convert(v)
+	if err != nil {
+		panic(err)
+	}
+	return value
+}
+
+// Promote implements sql.Type interface.
+func (t sqlSpatialType) Promote() sql.Type {
+	return GeometrySqlType
+}
+
+// SQL implements sql.Type interface.
+func (t sqlSpatialType) SQL(v interface{}) (sqltypes.Value, error) {
+	if v == nil {
+		return sqltypes.NULL, nil
+	}
+	g, err := t.Convert(v)
+	if err != nil {
+		return sqltypes.Value{}, err
+	}
+	return sqltypes.MakeTrusted(sqltypes.Geometry, geometry.MarshalMySQL(g.(geometry.Geometry))), nil
+}
+
+// String implements sql.Type interface.
+func (t sqlSpatialType) String() string {
+	if t.subtype == "" {
+		return "GEOMETRY"
+	}
+	return t.subtype
+}
+
+// Type implements sql.Type interface.
+func (t sqlSpatialType) Type() query.Type {
+	return sqltypes.Geometry
+}
+
+// Zero implements sql.Type interface.
+func (t sqlSpatialType) Zero() interface{} {
+	if t.subtype == "" || t.subtype == geometry.PointType {
+		return geometry.Point{}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package typeinfo
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/dolthub/vitess/go/sqltypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/geometry"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestSpatialConvertValueToNomsValue(t *testing.T) {
+	tests := []struct {
+		typ         *spatialType
+		input       interface{}
+		output      string
+		expectedErr bool
+	}{
+		{PointType, "POINT(1 2)", "POINT(1 2)", false},
+		{PointType, geometry.Point{X: 3, Y: 4}, "POINT(3 4)", false},
+		{PointType, geometry.MarshalMySQL(geometry.Point{X: 5, Y: 6}), "POINT(5 6)", false},
+		{PointType, "LINESTRING(0 0,1 1)", "", true},
+		{PointType, "POINT(1)", "", true},
+		{PointType, 12, "", true},
+		{LineStringType, "linestring(0 0, 1 1)", "LINESTRING(0 0,1 1)", false},
+		{PolygonType, "POLYGON((0 0,1 0,1 1,0 0))", "POLYGON((0 0,1 0,1 1,0 0))", false},
+		{PolygonType, "POINT(1 2)", "", true},
+		{GeometryType, "POINT(1 2)", "POINT(1 2)", false},
+		{GeometryType, "LINESTRING(0 0,1 1)", "LINESTRING(0 0,1 1)", false},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf(`%v %v`, test.typ.String(), test.input), func(t *testing.T) {
+			output, err := test.typ.ConvertValueToNomsValue(context.Background(), nil, test.input)
+			if !test.expectedErr {
+				require.NoError(t, err)
+				require.Equal(t, types.InlineBlobKind, output.Kind())
+				str, err := test.typ.FormatValue(context.Background(), output)
+				require.NoError(t, err)
+				assert.Equal(t, test.output, *str)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestSpatialSqlType(t *testing.T) {
+	val, err := PointSqlType.SQL("POINT(1 2)")
+	require.NoError(t, err)
+	assert.Equal(t, sqltypes.Geometry, val.Type())
+	assert.Equal(t, geometry.MarshalMySQL(geometry.Point{X: 1, Y: 2}), val.Raw())
+
+	cmp, err := GeometrySqlType.Compare("POINT(1 2)", geometry.Point{X: 1, Y: 2})
+	require.NoError(t, err)
+	assert.Equal(t, 0, cmp)
+	cmp, err = GeometrySqlType.Compare("POINT(1 2)", "POINT(1 3)")
+	require.NoError(t, err)
+	assert.NotEqual(t, 0, cmp)
+
+	ti, err := FromSqlType(LineStringSqlType)
+	require.NoError(t, err)
+	assert.True(t, LineStringType.Equals(ti))
+	assert.False(t, PointType.Equals(ti))
+}
@@ -15,6 +15,7 @@
 package typeinfo
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/dolthub/go-mysql-server/sql"
@@ -28,6 +29,8 @@ type Identifier string
 const (
 	UnknownTypeIdentifier    Identifier = "unknown"
 	BitTypeIdentifier        Identifier = "bit"
+	BlobBinaryTypeIdentifier Identifier = "blobbinary"
+	BlobStringTypeIdentifier Identifier = "blobstring"
 	BoolTypeIdentifier       Identifier = "bool"
 	DatetimeTypeIdentifier   Identifier = "datetime"
 	DecimalTypeIdentifier    Identifier = "decimal"
@@ -35,7 +38,9 @@ const (
 	FloatTypeIdentifier      Identifier = "float"
 	InlineBlobTypeIdentifier Identifier = "inlineblob"
 	IntTypeIdentifier        Identifier = "int"
+	JSONTypeIdentifier       Identifier = "json"
 	SetTypeIdentifier        Identifier = "set"
+	SpatialTypeIdentifier    Identifier = "spatial"
 	TimeTypeIdentifier       Identifier = "time"
 	TupleTypeIdentifier      Identifier = "tuple"
 	UintTypeIdentifier       Identifier = "uint"
@@ -48,6 +53,8 @@ const (
 var Identifiers = map[Identifier]struct{}{
 	UnknownTypeIdentifier:    {},
 	BitTypeIdentifier:        {},
+	BlobBinaryTypeIdentifier: {},
+	BlobStringTypeIdentifier: {},
 	BoolTypeIdentifier:       {},
 	DatetimeTypeIdentifier:   {},
 	DecimalTypeIdentifier:    {},
@@ -55,7 +62,9 @@ var Identifiers = map[Identifier]struct{}{
 	FloatTypeIdentifier:      {},
 	InlineBlobTypeIdentifier: {},
 	IntTypeIdentifier:        {},
+	JSONTypeIdentifier:       {},
 	SetTypeIdentifier:        {},
+	SpatialTypeIdentifier:    {},
 	TimeTypeIdentifier:       {},
 	TupleTypeIdentifier:      {},
 	UintTypeIdentifier:       {},
@@ -71,17 +80,18 @@ type TypeInfo interface {
 	// parameter is equivalent to the NomsKind returned by this type info. This is intended for retrieval
 	// from storage, thus we do no validation as we assume the stored value is already validated against
 	// the given type.
-	ConvertNomsValueToValue(v types.Value) (interface{}, error)
+	ConvertNomsValueToValue(ctx context.Context, v types.Value) (interface{}, error)
 
 	// ConvertValueToNomsValue converts a go value or Noms value to a Noms value. The type of the Noms
-	// value will be equivalent to the NomsKind returned from NomsKind.
-	ConvertValueToNomsValue(v interface{}) (types.Value, error)
+	// value will be equivalent to the NomsKind returned from NomsKind. Types whose values are stored in
+	// their own chunks write them to the given ValueReadWriter.
+	ConvertValueToNomsValue(ctx context.Context, vrw types.ValueReadWriter, v interface{}) (types.Value, error)
 
 	// Equals returns whether the given TypeInfo is equivalent to this TypeInfo.
 	Equals(other TypeInfo) bool
 
 	// FormatValue returns the stringified version of the value.
-	FormatValue(v types.Value) (*string, error)
+	FormatValue(ctx context.Context, v types.Value) (*string, error)
 
 	// GetTypeIdentifier returns an identifier for this type used for serialization.
 	GetTypeIdentifier() Identifier
@@ -97,7 +107,8 @@ type TypeInfo interface {
 	NomsKind() types.NomsKind
 
 	// ParseValue parses a string and returns a go value that represents it according to this type.
-	ParseValue(str *string) (types.Value, error)
+	// Types whose values are stored in their own chunks write them to the given ValueReadWriter.
+	ParseValue(ctx context.Context, vrw types.ValueReadWriter, str *string) (types.Value, error)
 
 	// ToSqlType returns the TypeInfo as a sql.Type. If an exact match is able to be made then that is
 	// the one returned, otherwise the sql.Type is the closest match possible.
@@ -157,17 +168,16 @@ func FromSqlType(sqlType sql.Type) (TypeInfo, error) {
 		if !ok {
 			return nil, fmt.Errorf(`expected "StringType" from SQL basetype "Text"`)
 		}
+		if stringType.MaxCharacterLength() == sql.LongText.MaxCharacterLength() {
+			return &blobStringType{stringType}, nil
+		}
 		return &varStringType{stringType}, nil
 	case sqltypes.Blob:
-		//TODO: determine the storage format
-		if fmt.Sprintf("a") != "" { // always evaluates to true, compiler won't complain about unreachable code
-			return nil, fmt.Errorf(`"%v" has not yet been implemented`, sqlType.String())
-		}
 		stringType, ok := sqlType.(sql.StringType)
 		if !ok {
 			return nil, fmt.Errorf(`expected "StringType" from SQL basetype "Blob"`)
 		}
-		return &varBinaryType{stringType}, nil
+		return &blobBinaryType{stringType}, nil
 	case sqltypes.VarChar:
 		stringType, ok := sqlType.(sql.StringType)
 		if !ok {
@@ -212,12 +222,20 @@ func FromSqlType(sqlType sql.Type) (TypeInfo, error) {
 			return nil, fmt.Errorf(`expected "EnumTypeIdentifier" from SQL basetype "Enum"`)
 		}
 		return &enumType{enumSQLType}, nil
+	case sqltypes.TypeJSON:
+		return JSONType, nil
 	case sqltypes.Set:
 		setSQLType, ok := sqlType.(sql.SetType)
 		if !ok {
 			return nil, fmt.Errorf(`expected "SetTypeIdentifier" from SQL basetype "Set"`)
 		}
 		return &setType{setSQLType}, nil
+	case sqltypes.Geometry:
+		spatialSQLType, ok := sqlType.(sqlSpatialType)
+		if !ok {
+			return nil, fmt.Errorf(`expected "SpatialTypeIdentifier" from SQL basetype "Geometry"`)
+		}
+		return &spatialType{spatialSQLType}, nil
 	default:
 		return nil, fmt.Errorf(`no type info can be created from SQL base type "%v"`, sqlType.String())
 	}
@@ -228,6 +246,10 @@ func FromTypeParams(id Identifier, params map[string]string) (TypeInfo, error) {
 	switch id {
 	case BitTypeIdentifier:
 		return CreateBitTypeFromParams(params)
+	case BlobBinaryTypeIdentifier:
+		return CreateBlobBinaryTypeFromParams(params)
+	case BlobStringTypeIdentifier:
+		return CreateBlobStringTypeFromParams(params)
 	case BoolTypeIdentifier:
 		return BoolType, nil
 	case DatetimeTypeIdentifier:
@@ -242,8 +264,12 @@ func FromTypeParams(id Identifier, params map[string]string) (TypeInfo, error) {
 		return InlineBlobType, nil
 	case IntTypeIdentifier:
 		return CreateIntTypeFromParams(params)
+	case JSONTypeIdentifier:
+		return JSONType, nil
 	case SetTypeIdentifier:
 		return CreateSetTypeFromParams(params)
+	case SpatialTypeIdentifier:
+		return CreateSpatialTypeFromParams(params)
 	case TimeTypeIdentifier:
 		return TimeType, nil
 	case TupleTypeIdentifier:
@@ -266,6 +292,8 @@ func FromTypeParams(id Identifier, params map[string]string) (TypeInfo, error) {
 // FromKind returns the default TypeInfo for a given types.Value.
 func FromKind(kind types.NomsKind) TypeInfo {
 	switch kind {
+	case types.BlobKind:
+		return LongBlobType
 	case types.BoolKind:
 		return BoolType
 	case types.FloatKind:
@@ -293,22 +321,43 @@ func FromKind(kind types.NomsKind) TypeInfo {
 
 // Convert takes in a types.Value, as well as the source and destination TypeInfos, and
 // converts the TypeInfo into the applicable types.Value.
-func Convert(v types.Value, srcTi TypeInfo, destTi TypeInfo) (types.Value, error) {
-	str, err := srcTi.FormatValue(v)
+func Convert(ctx context.Context, vrw types.ValueReadWriter, v types.Value, srcTi TypeInfo, destTi TypeInfo) (types.Value, error) {
+	str, err := srcTi.FormatValue(ctx, v)
 	if err != nil {
 		return nil, err
 	}
-	val, err := destTi.ParseValue(str)
+	val, err := destTi.ParseValue(ctx, vrw, str)
 	if err != nil {
 		return nil, err
 	}
 	return val, nil
 }
 
-// IsStringType returns whether the given TypeInfo represents a CHAR, VARCHAR, or TEXT-derivative.
+// InlineType returns a TypeInfo for the same SQL type as the one given whose values are stored inline in the row, for
+// types whose values are otherwise stored as blobs. Other types are returned unchanged.
+func InlineType(ti TypeInfo) TypeInfo {
+	if bst, ok := ti.(*blobStringType); ok {
+		return &varStringType{bst.sqlStringType}
+	}
+	return ti
+}
+
+// TagKind returns the NomsKind that column tags are generated from for the given TypeInfo. LONGTEXT values were stored
+// inline as strings before they were stored as blobs, so their tags are still generated from StringKind to keep tag
+// generation deterministic across versions.
+func TagKind(ti TypeInfo) types.NomsKind {
+	return InlineType(ti).NomsKind()
+}
+
+// IsStringType returns whether the given TypeInfo represents a CHAR, VARCHAR, or TEXT-derivative. The values of
+// LONGTEXT columns are stored as Blobs rather than Strings.
 func IsStringType(ti TypeInfo) bool {
-	_, ok := ti.(*varStringType)
-	return ok
+	switch ti.(type) {
+	case *varStringType, *blobStringType:
+		return true
+	default:
+		return false
+	}
 }
 
 // ParseIdentifier takes in an Identifier in string form and returns the matching Identifier.
@@ -15,7 +15,9 @@
 package typeinfo
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -28,12 +30,13 @@ import (
 )
 
 func TestTypeInfoSuite(t *testing.T) {
-	typeInfoArrays, validTypeValues := generateTypeInfoArrays(t)
+	vrw := types.NewMemoryValueStore()
+	typeInfoArrays, validTypeValues := generateTypeInfoArrays(t, vrw)
 	t.Run("VerifyArray", func(t *testing.T) {
 		verifyTypeInfoArrays(t, typeInfoArrays, validTypeValues)
 	})
 	t.Run("ConvertRoundTrip", func(t *testing.T) {
-		testTypeInfoConvertRoundTrip(t, typeInfoArrays, validTypeValues)
+		testTypeInfoConvertRoundTrip(t, vrw, typeInfoArrays, validTypeValues)
 	})
 	t.Run("Equals", func(t *testing.T) {
 		testTypeInfoEquals(t, typeInfoArrays)
@@ -42,13 +45,13 @@ func TestTypeInfoSuite(t *testing.T) {
 		testTypeInfoForeignKindHandling(t, typeInfoArrays, validTypeValues)
 	})
 	t.Run("FormatParseRoundTrip", func(t *testing.T) {
-		testTypeInfoFormatParseRoundTrip(t, typeInfoArrays, validTypeValues)
+		testTypeInfoFormatParseRoundTrip(t, vrw, typeInfoArrays, validTypeValues)
 	})
 	t.Run("GetTypeParams", func(t *testing.T) {
 		testTypeInfoGetTypeParams(t, typeInfoArrays)
 	})
 	t.Run("NullHandling", func(t *testing.T) {
-		testTypeInfoNullHandling(t, typeInfoArrays)
+		testTypeInfoNullHandling(t, vrw, typeInfoArrays)
 	})
 	t.Run("NomsKind", func(t *testing.T) {
 		testTypeInfoNomsKind(t, typeInfoArrays, validTypeValues)
@@ -58,6 +61,21 @@ func TestTypeInfoSuite(t *testing.T) {
 	})
 }
 
+// Tags are generated from the NomsKinds of columns, so LONGTEXT columns must keep generating them from StringKind
+func TestTagKind(t *testing.T) {
+	assert.Equal(t, types.StringKind, TagKind(LongTextType))
+	assert.Equal(t, types.StringKind, TagKind(StringDefaultType))
+	assert.Equal(t, types.BlobKind, TagKind(LongBlobType))
+	assert.Equal(t, types.IntKind, TagKind(Int64Type))
+}
+
+func TestIsStringType(t *testing.T) {
+	assert.True(t, IsStringType(StringDefaultType))
+	assert.True(t, IsStringType(LongTextType))
+	assert.False(t, IsStringType(LongBlobType))
+	assert.False(t, IsStringType(Int64Type))
+}
+
 // verify that the TypeInfos and values are all consistent with each other, and cover the full range of types
 func verifyTypeInfoArrays(t *testing.T, tiArrays [][]TypeInfo, vaArrays [][]types.Value) {
 	require.Equal(t, len(tiArrays), len(vaArrays))
@@ -103,19 +121,19 @@ func verifyTypeInfoArrays(t *testing.T, tiArrays [][]TypeInfo, vaArrays [][]type
 }
 
 // assuming valid data, verifies that the To-From interface{} functions can round trip
-func testTypeInfoConvertRoundTrip(t *testing.T, tiArrays [][]TypeInfo, vaArrays [][]types.Value) {
+func testTypeInfoConvertRoundTrip(t *testing.T, vrw types.ValueReadWriter, tiArrays [][]TypeInfo, vaArrays [][]types.Value) {
 	for rowIndex, tiArray := range tiArrays {
 		t.Run(tiArray[0].GetTypeIdentifier().String(), func(t *testing.T) {
 			for _, ti := range tiArray {
 				atLeastOneValid := false
 				t.Run(ti.String(), func(t *testing.T) {
 					for _, val := range vaArrays[rowIndex] {
-						t.Run(fmt.Sprintf(`types.%v(%v)`, val.Kind().String(), val.HumanReadableString()), func(t *testing.T) {
-							vInterface, err := ti.ConvertNomsValueToValue(val)
+						t.Run(fmt.Sprintf(`types.%v(%v)`, val.Kind().String(), humanReadableString(val)), func(t *testing.T) {
+							vInterface, err := ti.ConvertNomsValueToValue(context.Background(), val)
 							if ti.IsValid(val) {
 								atLeastOneValid = true
 								require.NoError(t, err)
-								outVal, err := ti.ConvertValueToNomsValue(vInterface)
+								outVal, err := ti.ConvertValueToNomsValue(context.Background(), vrw, vInterface)
 								require.NoError(t, err)
 								if ti == DateType { // Special case as DateType removes the hh:mm:ss
 									val = types.Timestamp(time.Time(val.(types.Timestamp)).Truncate(24 * time.Hour))
@@ -184,11 +202,11 @@ func testTypeInfoForeignKindHandling(t *testing.T, tiArrays [][]TypeInfo, vaArra
 				t.Run(ti.String(), func(t *testing.T) {
 					for _, vaArray := range vaArrays {
 						for _, val := range vaArray {
-							t.Run(fmt.Sprintf(`types.%v(%v)`, val.Kind().String(), val.HumanReadableString()), func(t *testing.T) {
+							t.Run(fmt.Sprintf(`types.%v(%v)`, val.Kind().String(), humanReadableString(val)), func(t *testing.T) {
 								if ti.NomsKind() != val.Kind() {
-									_, err := ti.ConvertNomsValueToValue(val)
+									_, err := ti.ConvertNomsValueToValue(context.Background(), val)
 									assert.Error(t, err)
-									_, err = ti.FormatValue(val)
+									_, err = ti.FormatValue(context.Background(), val)
 									assert.Error(t, err)
 								}
 							})
@@ -201,19 +219,19 @@ func testTypeInfoForeignKindHandling(t *testing.T, tiArrays [][]TypeInfo, vaArra
 }
 
 // assuming valid data, verifies that the To-From string functions can round trip
-func testTypeInfoFormatParseRoundTrip(t *testing.T, tiArrays [][]TypeInfo, vaArrays [][]types.Value) {
+func testTypeInfoFormatParseRoundTrip(t *testing.T, vrw types.ValueReadWriter, tiArrays [][]TypeInfo, vaArrays [][]types.Value) {
 	for rowIndex, tiArray := range tiArrays {
 		t.Run(tiArray[0].GetTypeIdentifier().String(), func(t *testing.T) {
 			for _, ti := range tiArray {
 				atLeastOneValid := false
 				t.Run(ti.String(), func(t *testing.T) {
 					for _, val := range vaArrays[rowIndex] {
-						t.Run(fmt.Sprintf(`types.%v(%v)`, val.Kind().String(), val.HumanReadableString()), func(t *testing.T) {
-							str, err := ti.FormatValue(val)
+						t.Run(fmt.Sprintf(`types.%v(%v)`, val.Kind().String(), humanReadableString(val)), func(t *testing.T) {
+							str, err := ti.FormatValue(context.Background(), val)
 							if ti.IsValid(val) {
 								atLeastOneValid = true
 								require.NoError(t, err)
-								outVal, err := ti.ParseValue(str)
+								outVal, err := ti.ParseValue(context.Background(), vrw, str)
 								require.NoError(t, err)
 								if ti == DateType { // special case as DateType removes the hh:mm:ss
 									val = types.Timestamp(time.Time(val.(types.Timestamp)).Truncate(24 * time.Hour))
@@ -247,29 +265,29 @@ func testTypeInfoGetTypeParams(t *testing.T, tiArrays [][]TypeInfo) {
 }
 
 // makes sure that everything can handle nil and NullValue (if applicable)
-func testTypeInfoNullHandling(t *testing.T, tiArrays [][]TypeInfo) {
+func testTypeInfoNullHandling(t *testing.T, vrw types.ValueReadWriter, tiArrays [][]TypeInfo) {
 	for _, tiArray := range tiArrays {
 		t.Run(tiArray[0].GetTypeIdentifier().String(), func(t *testing.T) {
 			for _, ti := range tiArray {
 				t.Run(ti.String(), func(t *testing.T) {
 					t.Run("ConvertNomsValueToValue", func(t *testing.T) {
-						val, err := ti.ConvertNomsValueToValue(types.NullValue)
+						val, err := ti.ConvertNomsValueToValue(context.Background(), types.NullValue)
 						require.NoError(t, err)
 						require.Nil(t, val)
-						val, err = ti.ConvertNomsValueToValue(nil)
+						val, err = ti.ConvertNomsValueToValue(context.Background(), nil)
 						require.NoError(t, err)
 						require.Nil(t, val)
 					})
 					t.Run("ConvertValueToNomsValue", func(t *testing.T) {
-						tVal, err := ti.ConvertValueToNomsValue(nil)
+						tVal, err := ti.ConvertValueToNomsValue(context.Background(), vrw, nil)
 						require.NoError(t, err)
 						require.Equal(t, types.NullValue, tVal)
 					})
 					t.Run("FormatValue", func(t *testing.T) {
-						tVal, err := ti.FormatValue(types.NullValue)
+						tVal, err := ti.FormatValue(context.Background(), types.NullValue)
 						require.NoError(t, err)
 						require.Nil(t, tVal)
-						tVal, err = ti.FormatValue(nil)
+						tVal, err = ti.FormatValue(context.Background(), nil)
 						require.NoError(t, err)
 						require.Nil(t, tVal)
 					})
@@ -278,7 +296,7 @@ func testTypeInfoNullHandling(t *testing.T, tiArrays [][]TypeInfo) {
 						require.True(t, ti.IsValid(nil))
 					})
 					t.Run("ParseValue", func(t *testing.T) {
-						tVal, err := ti.ParseValue(nil)
+						tVal, err := ti.ParseValue(context.Background(), vrw, nil)
 						require.NoError(t, err)
 						require.Equal(t, types.NullValue, tVal)
 					})
@@ -321,9 +339,11 @@ func testTypeInfoToSqlType(t *testing.T, tiArrays [][]TypeInfo) {
 }
 
 // generate unique TypeInfos for each type, and also values that are valid for at least one of the TypeInfos for the matching row
-func generateTypeInfoArrays(t *testing.T) ([][]TypeInfo, [][]types.Value) {
+func generateTypeInfoArrays(t *testing.T, vrw types.ValueReadWriter) ([][]TypeInfo, [][]types.Value) {
 	return [][]TypeInfo{
 			generateBitTypes(t, 16),
+			{&blobBinaryType{sql.TinyBlob}, &blobBinaryType{sql.Blob}, &blobBinaryType{sql.MediumBlob}, LongBlobType},
+			{LongTextType, &blobStringType{sql.CreateLongText(sql.Collation_utf8mb4_bin)}},
 			{BoolType},
 			{DateType, DatetimeType, TimestampType},
 			generateDecimalTypes(t, 16),
@@ -331,7 +351,9 @@ func generateTypeInfoArrays(t *testing.T) ([][]TypeInfo, [][]types.Value) {
 			{Float32Type, Float64Type},
 			{InlineBlobType},
 			{Int8Type, Int16Type, Int24Type, Int32Type, Int64Type},
+			{JSONType},
 			generateSetTypes(t, 16),
+			{GeometryType, PointType, LineStringType, PolygonType},
 			{TimeType},
 			{Uint8Type, Uint16Type, Uint24Type, Uint32Type, Uint64Type},
 			{UuidType},
@@ -345,6 +367,10 @@ func generateTypeInfoArrays(t *testing.T) ([][]TypeInfo, [][]types.Value) {
 		},
 		[][]types.Value{
 			{types.Uint(1), types.Uint(207), types.Uint(79147), types.Uint(34845728), types.Uint(9274618927)}, //Bit
+			{generateBlob(t, vrw, ""), generateBlob(t, vrw, "a"), generateBlob(t, vrw, string([]byte{0, 1, 254, 255})), //BlobBinary
+				generateBlob(t, vrw, strings.Repeat("abcdefghijklmnopqrstuvwxyz", 20))},
+			{generateBlob(t, vrw, ""), generateBlob(t, vrw, "abc"), //BlobString
+				generateBlob(t, vrw, "هذا هو بعض نماذج النص التي أستخدمها لاختبار عناصر"), generateBlob(t, vrw, strings.Repeat("0123456789", 1000))},
 			{types.Bool(false), types.Bool(true)}, //Bool
 			{types.Timestamp(time.Date(1000, 1, 1, 0, 0, 0, 0, time.UTC)), //Datetime
 				types.Timestamp(time.Date(1970, 1, 1, 0, 0, 1, 0, time.UTC)),
@@ -356,11 +382,14 @@ func generateTypeInfoArrays(t *testing.T) ([][]TypeInfo, [][]types.Value) {
 				types.Decimal(decimal.RequireFromString("4723245")),
 				types.Decimal(decimal.RequireFromString("-1076416.875")),
 				types.Decimal(decimal.RequireFromString("198728394234798423466321.27349757"))},
-			{types.Uint(1), types.Uint(3), types.Uint(5), types.Uint(7), types.Uint(8)},                                                                                                    //Enum
-			{types.Float(1.0), types.Float(65513.75), types.Float(4293902592), types.Float(4.58e71), types.Float(7.172e285)},                                                               //Float
-			{types.InlineBlob{0}, types.InlineBlob{21}, types.InlineBlob{1, 17}, types.InlineBlob{72, 42}, types.InlineBlob{21, 122, 236}},                                                 //InlineBlob
-			{types.Int(20), types.Int(215), types.Int(237493), types.Int(2035753568), types.Int(2384384576063)},                                                                            //Int
-			{types.Uint(1), types.Uint(5), types.Uint(64), types.Uint(42), types.Uint(192)},                                                                                                //Set
+			{types.Uint(1), types.Uint(3), types.Uint(5), types.Uint(7), types.Uint(8)},                                                                                                                         //Enum
+			{types.Float(1.0), types.Float(65513.75), types.Float(4293902592), types.Float(4.58e71), types.Float(7.172e285)},                                                                                    //Float
+			{types.InlineBlob{0}, types.InlineBlob{21}, types.InlineBlob{1, 17}, types.InlineBlob{72, 42}, types.InlineBlob{21, 122, 236}},                                                                      //InlineBlob
+			{types.Int(20), types.Int(215), types.Int(237493), types.Int(2035753568), types.Int(2384384576063)},                                                                                                 //Int
+			{generateJSONDocument(t, `{"a": 1}`), generateJSONDocument(t, `[1, "2", 3.5]`), generateJSONDocument(t, `"abc"`), generateJSONDocument(t, `null`), generateJSONDocument(t, `{"a": {"b": [true]}}`)}, //JSON
+			{types.Uint(1), types.Uint(5), types.Uint(64), types.Uint(42), types.Uint(192)},                                                                                                                     //Set
+			{generateGeometry(t, "POINT(1 2)"), generateGeometry(t, "POINT(-71.064544 42.28787)"), //Spatial
+				generateGeometry(t, "LINESTRING(0 0,1 1,2 1)"), generateGeometry(t, "POLYGON((0 0,4 0,4 4,0 4,0 0),(1 1,2 1,2 2,1 1))")},
 			{types.Int(0), types.Int(1000000 /*"00:00:01"*/), types.Int(113000000 /*"00:01:53"*/), types.Int(247019000000 /*"68:36:59"*/), types.Int(458830485214 /*"127:27:10.485214"*/)}, //Time
 			{types.Uint(20), types.Uint(275), types.Uint(328395), types.Uint(630257298), types.Uint(93897259874)},                                                                          //Uint
 			{types.UUID{3}, types.UUID{3, 13}, types.UUID{128, 238, 82, 12}, types.UUID{31, 54, 23, 13, 63, 43}, types.UUID{83, 64, 21, 14, 42, 6, 35, 7, 54, 234, 6, 32, 1, 4, 2, 4}},     //Uuid
@@ -15,6 +15,7 @@
 package typeinfo
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -46,7 +47,7 @@ func TestUuidConvertNomsValueToValue(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(fmt.Sprintf(`%v %v`, UuidType.String(), test.input), func(t *testing.T) {
-			output, err := UuidType.ConvertNomsValueToValue(test.input)
+			output, err := UuidType.ConvertNomsValueToValue(context.Background(), test.input)
 			require.NoError(t, err)
 			require.Equal(t, test.output, output)
 		})
@@ -128,7 +129,7 @@ func TestUuidConvertValueToNomsValue(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(fmt.Sprintf(`%v %v`, UuidType.String(), test.input), func(t *testing.T) {
-			output, err := UuidType.ConvertValueToNomsValue(test.input)
+			output, err := UuidType.ConvertValueToNomsValue(context.Background(), nil, test.input)
 			if !test.expectedErr {
 				require.NoError(t, err)
 				assert.Equal(t, test.output, output, "%v\n%v", test.output, output)
@@ -160,7 +161,7 @@ func TestUuidFormatValue(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(fmt.Sprintf(`%v %v`, UuidType.String(), test.input), func(t *testing.T) {
-			output, err := UuidType.FormatValue(test.input)
+			output, err := UuidType.FormatValue(context.Background(), test.input)
 			require.NoError(t, err)
 			require.Equal(t, test.output, *output)
 		})
@@ -192,7 +193,7 @@ func TestUuidParseValue(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(fmt.Sprintf(`%v %v`, UuidType.String(), test.input), func(t *testing.T) {
-			output, err := UuidType.ParseValue(&test.input)
+			output, err := UuidType.ParseValue(context.Background(), nil, &test.input)
 			if !test.expectedErr {
 				require.NoError(t, err)
 				assert.Equal(t, test.output, output)
@@ -15,6 +15,7 @@
 package typeinfo
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 
@@ -75,7 +76,7 @@ func CreateVarBinaryTypeFromParams(params map[string]string) (TypeInfo, error) {
 }
 
 // ConvertNomsValueToValue implements TypeInfo interface.
-func (ti *varBinaryType) ConvertNomsValueToValue(v types.Value) (interface{}, error) {
+func (ti *varBinaryType) ConvertNomsValueToValue(ctx context.Context, v types.Value) (interface{}, error) {
 	if val, ok := v.(types.String); ok {
 		return string(val), nil
 	}
@@ -86,7 +87,7 @@ func (ti *varBinaryType) ConvertNomsValueToValue(v types.Value) (interface{}, er
 }
 
 // ConvertValueToNomsValue implements TypeInfo interface.
-func (ti *varBinaryType) ConvertValueToNomsValue(v interface{}) (types.Value, error) {
+func (ti *varBinaryType) ConvertValueToNomsValue(ctx context.Context, vrw types.ValueReadWriter, v interface{}) (types.Value, error) {
 	if v == nil {
 		return types.NullValue, nil
 	}
@@ -114,7 +115,7 @@ func (ti *varBinaryType) Equals(other TypeInfo) bool {
 }
 
 // FormatValue implements TypeInfo interface.
-func (ti *varBinaryType) FormatValue(v types.Value) (*string, error) {
+func (ti *varBinaryType) FormatValue(ctx context.Context, v types.Value) (*string, error) {
 	if val, ok := v.(types.String); ok {
 		res, err := ti.sqlBinaryType.Convert(string(val))
 		if err != nil {
@@ -175,7 +176,7 @@ func (ti *varBinaryType) NomsKind() types.NomsKind {
 }
 
 // ParseValue implements TypeInfo interface.
-func (ti *varBinaryType) ParseValue(str *string) (types.Value, error) {
+func (ti *varBinaryType) ParseValue(ctx context.Context, vrw types.ValueReadWriter, str *string) (types.Value, error) {
 	if str == nil {
 		return types.NullValue, nil
 	}
@@ -15,6 +15,7 @@
 package typeinfo
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
@@ -81,7 +82,7 @@ func CreateVarStringTypeFromParams(params map[string]string) (TypeInfo, error) {
 }
 
 // ConvertNomsValueToValue implements TypeInfo interface.
-func (ti *varStringType) ConvertNomsValueToValue(v types.Value) (interface{}, error) {
+func (ti *varStringType) ConvertNomsValueToValue(ctx context.Context, v types.Value) (interface{}, error) {
 	if val, ok := v.(types.String); ok {
 		res := string(val)
 		// As per the MySQL documentation, trailing spaces are removed when retrieved for CHAR types only.
@@ -99,7 +100,7 @@ func (ti *varStringType) ConvertNomsValueToValue(v types.Value) (interface{}, er
 }
 
 // ConvertValueToNomsValue implements TypeInfo interface.
-func (ti *varStringType) ConvertValueToNomsValue(v interface{}) (types.Value, error) {
+func (ti *varStringType) ConvertValueToNomsValue(ctx context.Context, vrw types.ValueReadWriter, v interface{}) (types.Value, error) {
 	if v == nil {
 		return types.NullValue, nil
 	}
@@ -128,9 +129,9 @@ func (ti *varStringType) Equals(other TypeInfo) bool {
 }
 
 // FormatValue implements TypeInfo interface.
-func (ti *varStringType) FormatValue(v types.Value) (*string, error) {
+func (ti *varStringType) FormatValue(ctx context.Context, v types.Value) (*string, error) {
 	if val, ok := v.(types.String); ok {
-		res, err := ti.ConvertNomsValueToValue(val)
+		res, err := ti.ConvertNomsValueToValue(ctx, val)
 		if err != nil {
 			return nil, err
 		}
@@ -190,11 +191,11 @@ func (ti *varStringType) NomsKind() types.NomsKind {
 }
 
 // ParseValue implements TypeInfo interface.
-func (ti *varStringType) ParseValue(str *string) (types.Value, error) {
+func (ti *varStringType) ParseValue(ctx context.Context, vrw types.ValueReadWriter, str *string) (types.Value, error) {
 	if str == nil {
 		return types.NullValue, nil
 	}
-	return ti.ConvertValueToNomsValue(*str)
+	return ti.ConvertValueToNomsValue(ctx, vrw, *str)
 }
 
 // String implements TypeInfo interface.
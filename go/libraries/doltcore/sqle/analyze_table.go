@@ -0,0 +1,148 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+
+	sqle "github.com/dolthub/go-mysql-server"
+	"github.com/dolthub/go-mysql-server/auth"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/vitess/go/vt/sqlparser"
+)
+
+// AnalyzeTableSchema is the schema of the results of an ANALYZE TABLE statement, which has a row for each table.
+var AnalyzeTableSchema = sql.Schema{
+	{Name: "Table", Type: sql.LongText},
+	{Name: "Op", Type: sql.LongText},
+	{Name: "Msg_type", Type: sql.LongText},
+	{Name: "Msg_text", Type: sql.LongText},
+}
+
+// AnalyzeTable is an ANALYZE TABLE statement, which collects the statistics of the tables named from their databases'
+// working sets and stores them outside of the working sets, where they're shown by the dolt_statistics table. The
+// statistics are used to choose between the indexes of a table, and between an index and a scan of the table, when a
+// query is run.
+type AnalyzeTable struct {
+	// Tables are the tables to analyze.
+	Tables []AnalyzeTableName
+}
+
+// AnalyzeTableName is the name of a table in an ANALYZE TABLE statement.
+type AnalyzeTableName struct {
+	// Database is the database of the table, if the statement qualified its name.
+	Database string
+	// Table is the name of the table.
+	Table string
+}
+
+// ParseAnalyzeTable parses an ANALYZE TABLE statement, returning nil if |query| isn't one.
+func ParseAnalyzeTable(query string) (*AnalyzeTable, error) {
+	if firstTokenType(query) != sqlparser.ANALYZE {
+		return nil, nil
+	}
+
+	p, ok := newDDLParser(query)
+	if !ok || p.tok(0).typ != sqlparser.ANALYZE || p.tok(1).typ != sqlparser.TABLE {
+		return nil, nil
+	}
+
+	at := &AnalyzeTable{}
+	for i := 2; ; i += 2 {
+		var name AnalyzeTableName
+		if p.tok(i).typ != sqlparser.ID {
+			return nil, fmt.Errorf("invalid ANALYZE TABLE statement: %s", query)
+		} else if p.tok(i+1).typ == '.' && p.tok(i+2).typ == sqlparser.ID {
+			name.Database = p.tok(i).val
+			i += 2
+		}
+		name.Table = p.tok(i).val
+		at.Tables = append(at.Tables, name)
+
+		switch p.tok(i + 1).typ {
+		case ',':
+		case 0, ';':
+			return at, nil
+		default:
+			return nil, fmt.Errorf("invalid ANALYZE TABLE statement: %s", query)
+		}
+	}
+}
+
+// Exec analyzes the tables and returns a row for each of them with the outcome, like MySQL. A table which doesn't
+// exist is reported in its row rather than failing the statement.
+func (at *AnalyzeTable) Exec(ctx *sql.Context, e *sqle.Engine) (sql.Schema, sql.RowIter, error) {
+	err := e.Auth.Allowed(ctx, auth.ReadPerm|auth.WritePerm)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var rows []sql.Row
+	for _, name := range at.Tables {
+		dbName := name.Database
+		if dbName == "" {
+			dbName = ctx.GetCurrentDatabase()
+		}
+
+		sqlDB, err := e.Catalog.Database(dbName)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		db, ok := sqlDB.(Database)
+		if !ok {
+			rows = append(rows, sql.NewRow(sqlDB.Name()+"."+name.Table, "analyze", "note", "The storage engine for the table doesn't support analyze"))
+			continue
+		}
+
+		tableName, err := analyzeTable(ctx, db, name.Table)
+		if err != nil {
+			return nil, nil, err
+		} else if tableName == "" {
+			rows = append(rows, sql.NewRow(db.Name()+"."+name.Table, "analyze", "Error", fmt.Sprintf("Table '%s.%s' doesn't exist", db.Name(), name.Table)))
+		} else {
+			rows = append(rows, sql.NewRow(db.Name()+"."+tableName, "analyze", "status", "OK"))
+		}
+	}
+
+	return AnalyzeTableSchema, sql.RowsToRowIter(rows...), nil
+}
+
+// analyzeTable collects the statistics of the table named in the working root of |db| and stores them in the
+// statistics of |db|, returning the exact name of the table, or an empty name if there is no such table.
+func analyzeTable(ctx *sql.Context, db Database, name string) (string, error) {
+	// the statistics are collected from the table with any batched edits
+	err := db.Flush(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	root, err := db.GetRoot(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	tbl, tableName, ok, err := root.GetTableInsensitive(ctx, name)
+	if err != nil || !ok {
+		return "", err
+	}
+
+	stats, err := collectTableStatistics(ctx, tbl)
+	if err != nil {
+		return "", err
+	}
+
+	return tableName, db.stats.put(tableName, stats)
+}
@@ -0,0 +1,171 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
+)
+
+func TestParseAnalyzeTable(t *testing.T) {
+	tests := []struct {
+		query       string
+		expected    *AnalyzeTable
+		expectedErr bool
+	}{
+		{"analyze table people", &AnalyzeTable{Tables: []AnalyzeTableName{{Table: "people"}}}, false},
+		{"ANALYZE TABLE db.people, `pets`;", &AnalyzeTable{Tables: []AnalyzeTableName{{Database: "db", Table: "people"}, {Table: "pets"}}}, false},
+		{"analyze table", nil, true},
+		{"analyze table people pets", nil, true},
+		{"analyze table people,", nil, true},
+		{"explain analyze select * from people", nil, false},
+		{"select * from people", nil, false},
+		{"/* comment */ analyze table people", &AnalyzeTable{Tables: []AnalyzeTableName{{Table: "people"}}}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			analyze, err := ParseAnalyzeTable(test.query)
+			if test.expectedErr {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, test.expected, analyze)
+			}
+		})
+	}
+}
+
+func TestAnalyzeTable(t *testing.T) {
+	dEnv := dtestutils.CreateTestEnv()
+	root, err := dEnv.WorkingRoot(context.Background())
+	require.NoError(t, err)
+
+	var vals []string
+	for i := 0; i < 300; i++ {
+		vals = append(vals, fmt.Sprintf("(%d, %d, 'v%d')", i, i%2, i))
+	}
+
+	root, err = ExecuteSql(dEnv, root, `
+CREATE TABLE t (pk BIGINT PRIMARY KEY, a BIGINT, b VARCHAR(20), INDEX idx_a (a), INDEX idx_b (b));
+INSERT INTO t VALUES `+strings.Join(vals, ", ")+`;
+INSERT INTO t (pk) VALUES (300);
+`)
+	require.NoError(t, err)
+
+	explainLines := func(root *doltdb.RootValue, query string) []string {
+		db := NewDatabaseFromEnv("dolt", dEnv)
+		engine, sqlCtx, err := NewTestEngine(context.Background(), db, root)
+		require.NoError(t, err)
+
+		explain, err := ParseExplainAnalyze(query)
+		require.NoError(t, err)
+		_, iter, err := explain.Exec(sqlCtx, engine)
+		require.NoError(t, err)
+		rows, err := sql.RowIterToRows(iter)
+		require.NoError(t, err)
+
+		var lines []string
+		for _, row := range rows {
+			lines = append(lines, actualTimeRegex.ReplaceAllString(row[0].(string), "actual time=Xms"))
+		}
+		return lines
+	}
+
+	// without statistics, the first index is used
+	assert.Equal(t, []string{
+		`Indexed table access on indexes [t.a], [t.b] (actual time=Xms rows=1 loops=1)`,
+		` └─ Filter(t.a = 1 AND t.b = "v7") (actual time=Xms rows=1 loops=1)`,
+		`     └─ Table(t) (actual time=Xms rows=150 loops=1) [index idx_a: [1, 1]]`,
+	}, explainLines(root, `explain analyze select * from t where a = 1 and b = 'v7'`))
+
+	db := NewDatabaseFromEnv("dolt", dEnv)
+	engine, sqlCtx, err := NewTestEngine(context.Background(), db, root)
+	require.NoError(t, err)
+	engine.AddDatabase(NewInformationSchemaDatabase(engine.Catalog))
+
+	analyze, err := ParseAnalyzeTable("analyze table t, dolt.missing")
+	require.NoError(t, err)
+	sch, iter, err := analyze.Exec(sqlCtx, engine)
+	require.NoError(t, err)
+	assert.Equal(t, AnalyzeTableSchema, sch)
+	rows, err := sql.RowIterToRows(iter)
+	require.NoError(t, err)
+	assert.Equal(t, []sql.Row{
+		{"dolt.t", "analyze", "status", "OK"},
+		{"dolt.missing", "analyze", "Error", "Table 'dolt.missing' doesn't exist"},
+	}, rows)
+
+	query := func(query string) []sql.Row {
+		_, iter, err := engine.Query(sqlCtx, query)
+		require.NoError(t, err)
+		rows, err := sql.RowIterToRows(iter)
+		require.NoError(t, err)
+		return rows
+	}
+
+	assert.Equal(t, []sql.Row{
+		{"t", "a", uint64(301), uint64(1), uint64(2)},
+		{"t", "b", uint64(301), uint64(1), uint64(300)},
+		{"t", "pk", uint64(301), uint64(0), uint64(301)},
+	}, query("select table_name, column_name, row_count, null_count, distinct_count from dolt_statistics order by column_name"))
+
+	assert.Equal(t, []sql.Row{{uint64(301)}}, query("select table_rows from information_schema.tables where table_schema = 'dolt' and table_name = 't'"))
+
+	rows = query("select table_name, column_name, histogram from information_schema.column_statistics where schema_name = 'dolt' and column_name = 'a'")
+	require.Len(t, rows, 1)
+	assert.Equal(t, "t", rows[0][0])
+	assert.JSONEq(t, `{"buckets": [["0", "0", 0.4983388704318937, 1], ["1", "1", 0.9966777408637874, 1]], "null-values": 0.0033222591362126247, "sampling-rate": 1, "histogram-type": "equi-height", "number-of-buckets-specified": 64}`, string(rows[0][2].([]byte)))
+
+	// the statistics aren't stored in the working root
+	analyzedRoot, err := db.GetRoot(sqlCtx)
+	require.NoError(t, err)
+	rootHash, err := root.HashOf()
+	require.NoError(t, err)
+	analyzedRootHash, err := analyzedRoot.HashOf()
+	require.NoError(t, err)
+	assert.Equal(t, rootHash, analyzedRootHash)
+	_, iter, err = engine.Query(sqlCtx, "delete from dolt_statistics")
+	if err == nil {
+		_, err = sql.RowIterToRows(iter)
+	}
+	assert.Error(t, err)
+
+	// with statistics, the more selective index is used, and a scan is preferred to reading half the table by index
+	assert.Equal(t, []string{
+		`Indexed table access on indexes [t.a], [t.b] (actual time=Xms rows=1 loops=1)`,
+		` └─ Filter(t.a = 1 AND t.b = "v7") (actual time=Xms rows=1 loops=1)`,
+		`     └─ Table(t) (actual time=Xms rows=1 loops=1) [index idx_b: ["v7", "v7"]]`,
+	}, explainLines(root, `explain analyze select * from t where a = 1 and b = 'v7'`))
+	assert.Equal(t, []string{
+		`Indexed table access on index [t.a] (actual time=Xms rows=150 loops=1)`,
+		` └─ Filter(t.a = 1) (actual time=Xms rows=150 loops=1)`,
+		`     └─ Table(t) (actual time=Xms rows=301 loops=1) [full scan]`,
+	}, explainLines(root, `explain analyze select * from t where a = 1`))
+	assert.Equal(t, []string{
+		`Indexed table access on index [t.b] (actual time=Xms rows=2 loops=1)`,
+		` └─ Filter(t.b IN ("v1", "v2")) (actual time=Xms rows=2 loops=1)`,
+		`     └─ Table(t) (actual time=Xms rows=2 loops=1) [index idx_b: ["v1", "v1"], ["v2", "v2"] (2 reads)]`,
+	}, explainLines(root, `explain analyze select * from t where b in ('v1', 'v2')`))
+}
@@ -0,0 +1,145 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/analyzer"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+)
+
+const autoIncrementCounterRule = "auto_increment_counter"
+
+// useAutoIncrementCounter replaces the base value the engine uses for AUTO_INCREMENT columns, which is the largest
+// value in the column, with the counter Dolt tables record. This keeps values from being reused once the rows holding
+// them are deleted.
+func useAutoIncrementCounter(ctx *sql.Context, a *analyzer.Analyzer, n sql.Node, scope *analyzer.Scope) (sql.Node, error) {
+	return plan.TransformUp(n, func(n sql.Node) (sql.Node, error) {
+		insert, ok := n.(*plan.InsertInto)
+		if !ok {
+			return n, nil
+		}
+
+		insertable, err := plan.GetInsertable(insert.Left)
+		if err != nil {
+			return n, nil
+		}
+
+		var t *WritableDoltTable
+		switch insertable := insertable.(type) {
+		case *WritableDoltTable:
+			t = insertable
+		case *AlterableDoltTable:
+			t = &insertable.WritableDoltTable
+		default:
+			return n, nil
+		}
+
+		proj, ok := insert.Right.(*plan.Project)
+		if !ok {
+			return n, nil
+		}
+
+		modified := false
+		exprs := make([]sql.Expression, len(proj.Projections))
+		for i, e := range proj.Projections {
+			exprs[i] = e
+			ai, ok := e.(*expression.AutoIncrement)
+			if !ok {
+				continue
+			}
+			if _, ok := ai.Left.(*autoIncrementBase); ok {
+				continue
+			}
+
+			exprs[i], err = ai.WithChildren(&autoIncrementBase{t: t, typ: ai.Left.Type()}, ai.Right)
+			if err != nil {
+				return nil, err
+			}
+			modified = true
+		}
+
+		if !modified {
+			return n, nil
+		}
+
+		newProj, err := proj.WithExpressions(exprs...)
+		if err != nil {
+			return nil, err
+		}
+
+		return insert.WithChildren(insert.Left, newProj)
+	})
+}
+
+// autoIncrementBase evaluates to the last value given to the AUTO_INCREMENT column of a table, according to the
+// table's editor.
+type autoIncrementBase struct {
+	t   *WritableDoltTable
+	typ sql.Type
+}
+
+var _ sql.Expression = (*autoIncrementBase)(nil)
+
+// Resolved implements sql.Expression.
+func (b *autoIncrementBase) Resolved() bool {
+	return true
+}
+
+// String implements sql.Expression.
+func (b *autoIncrementBase) String() string {
+	return fmt.Sprintf("AUTO_INCREMENT(%s)", b.t.name)
+}
+
+// Type implements sql.Expression.
+func (b *autoIncrementBase) Type() sql.Type {
+	return b.typ
+}
+
+// IsNullable implements sql.Expression.
+func (b *autoIncrementBase) IsNullable() bool {
+	return false
+}
+
+// Eval implements sql.Expression.
+func (b *autoIncrementBase) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	ed, err := b.t.getTableEditor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	next, err := ed.tableEditor.NextAutoIncrementValue(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.typ.Convert(next - 1)
+}
+
+// Children implements sql.Expression.
+func (b *autoIncrementBase) Children() []sql.Expression {
+	return nil
+}
+
+// WithChildren implements sql.Expression.
+func (b *autoIncrementBase) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	if len(children) != 0 {
+		return nil, sql.ErrInvalidChildrenNumber.New(b, len(children), 0)
+	}
+	return b, nil
+}
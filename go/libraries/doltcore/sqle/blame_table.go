@@ -0,0 +1,229 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/blame"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/utils/set"
+)
+
+const (
+	// BlameEmailCol is the name of the column of a blame table containing the email of the committer
+	BlameEmailCol = "email"
+	// BlameMessageCol is the name of the column of a blame table containing the commit message
+	BlameMessageCol = "message"
+	// BlameColumnCommitHashSuffix is the suffix of the names of the columns of a blame table containing the hash of
+	// the commit which last modified each column of the table
+	BlameColumnCommitHashSuffix = "_" + CommitHashCol
+)
+
+var _ sql.FilteredTable = (*BlameTable)(nil)
+
+// BlameTable is a system table which has a row for each row of a table at the HEAD commit, with its primary key, the
+// commit which last modified it, and the commits which last modified each of its other columns.
+type BlameTable struct {
+	name      string
+	ddb       *doltdb.DoltDB
+	head      *doltdb.Commit
+	sch       schema.Schema
+	sqlSch    sql.Schema
+	pkFilters []sql.Expression
+}
+
+// NewBlameTable creates a blame table
+func NewBlameTable(ctx *sql.Context, db Database, tblName string) (sql.Table, error) {
+	sess := DSessFromSess(ctx.Session)
+	dbName := db.Name()
+
+	ddb, ok := sess.GetDoltDB(dbName)
+
+	if !ok {
+		return nil, sql.ErrDatabaseNotFound.New(dbName)
+	}
+
+	head, _, err := sess.GetParentCommit(ctx, dbName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := head.GetRootValue()
+
+	if err != nil {
+		return nil, err
+	}
+
+	tbl, name, ok, err := root.GetTableInsensitive(ctx, tblName)
+
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, sql.ErrTableNotFound.New(doltdb.DoltBlameTablePrefix + tblName)
+	}
+
+	sch, err := tbl.GetSchema(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	tableName := doltdb.DoltBlameTablePrefix + name
+	var sqlSch sql.Schema
+	_ = sch.GetPKCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		sqlSch = append(sqlSch, &sql.Column{Name: col.Name, Type: col.TypeInfo.ToSqlType(), Source: tableName, PrimaryKey: true})
+		return false, nil
+	})
+
+	sqlSch = append(sqlSch,
+		&sql.Column{Name: CommitHashCol, Type: sql.Text, Source: tableName},
+		&sql.Column{Name: CommitterCol, Type: sql.Text, Source: tableName},
+		&sql.Column{Name: BlameEmailCol, Type: sql.Text, Source: tableName},
+		&sql.Column{Name: CommitDateCol, Type: sql.Datetime, Source: tableName},
+		&sql.Column{Name: BlameMessageCol, Type: sql.Text, Source: tableName},
+	)
+
+	_ = sch.GetNonPKCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		sqlSch = append(sqlSch, &sql.Column{Name: col.Name + BlameColumnCommitHashSuffix, Type: sql.Text, Source: tableName, Nullable: true})
+		return false, nil
+	})
+
+	return &BlameTable{
+		name:   name,
+		ddb:    ddb,
+		head:   head,
+		sch:    sch,
+		sqlSch: sqlSch,
+	}, nil
+}
+
+// Name returns the name of the blame table
+func (bt *BlameTable) Name() string {
+	return doltdb.DoltBlameTablePrefix + bt.name
+}
+
+// String returns the name of the blame table
+func (bt *BlameTable) String() string {
+	return doltdb.DoltBlameTablePrefix + bt.name
+}
+
+// Schema returns the schema of the blame table, which has the primary key columns of the table, the columns of the
+// commit which last modified each row, and a column for the hash of the commit which last modified each of its other
+// columns.
+func (bt *BlameTable) Schema() sql.Schema {
+	return bt.sqlSch
+}
+
+// HandledFilters returns the filters on the primary key columns, which are applied before blame is found for a row
+func (bt *BlameTable) HandledFilters(filters []sql.Expression) []sql.Expression {
+	pkCols := set.NewStrSet(nil)
+	for _, name := range bt.sch.GetPKCols().GetColumnNames() {
+		pkCols.Add(strings.ToLower(name))
+	}
+
+	pkFilters, _ := splitFilters(filters, getColumnFilterCheck(pkCols))
+	return pkFilters
+}
+
+// Filters returns the list of filters that are applied to this table.
+func (bt *BlameTable) Filters() []sql.Expression {
+	return bt.pkFilters
+}
+
+// WithFilters returns a new sql.Table instance with the filters applied
+func (bt *BlameTable) WithFilters(filters []sql.Expression) sql.Table {
+	nbt := *bt
+	nbt.pkFilters = filters
+	return &nbt
+}
+
+// Partitions returns a single partition, as blame is found for all of the rows at once
+func (bt *BlameTable) Partitions(*sql.Context) (sql.PartitionIter, error) {
+	return newSinglePartitionIter(), nil
+}
+
+// PartitionRows returns the rows of the blame table
+func (bt *BlameTable) PartitionRows(ctx *sql.Context, _ sql.Partition) (sql.RowIter, error) {
+	var filter blame.RowFilter
+	if len(bt.pkFilters) > 0 {
+		filter = func(r row.Row) (bool, error) {
+			sqlRow, err := bt.keyRow(ctx, r)
+			if err != nil {
+				return false, err
+			}
+
+			for _, f := range bt.pkFilters {
+				res, err := f.Eval(ctx, sqlRow)
+				if err != nil {
+					return false, err
+				}
+				if b, ok := res.(bool); !ok || !b {
+					return false, nil
+				}
+			}
+
+			return true, nil
+		}
+	}
+
+	_, blames, err := blame.ForTable(ctx, bt.ddb, bt.head, bt.name, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	pkLen := bt.sch.GetPKCols().Size()
+	nonPKTags := bt.sch.GetNonPKCols().Tags
+	rows := make([]sql.Row, len(blames))
+	for i, rb := range blames {
+		r, err := bt.keyRow(ctx, rb.Row)
+		if err != nil {
+			return nil, err
+		}
+
+		meta := rb.Commit.Meta
+		r[pkLen], r[pkLen+1], r[pkLen+2], r[pkLen+3], r[pkLen+4] = rb.Commit.Hash.String(), meta.Name, meta.Email, meta.Time(), meta.Description
+		for j, tag := range nonPKTags {
+			if cm, ok := rb.Columns[tag]; ok {
+				r[pkLen+5+j] = cm.Hash.String()
+			}
+		}
+
+		rows[i] = r
+	}
+
+	return sql.RowsToRowIter(rows...), nil
+}
+
+// keyRow returns a row of the blame table with the primary key of |r|, and no other values.
+func (bt *BlameTable) keyRow(ctx context.Context, r row.Row) (sql.Row, error) {
+	sqlRow := make(sql.Row, len(bt.sqlSch))
+
+	i := 0
+	err := bt.sch.GetPKCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		val, _ := r.GetColVal(tag)
+		sqlRow[i], err = col.TypeInfo.ConvertNomsValueToValue(ctx, val)
+		i++
+		return err != nil, err
+	})
+
+	return sqlRow, err
+}
@@ -0,0 +1,632 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+	"strings"
+
+	sqle "github.com/dolthub/go-mysql-server"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/analyzer"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+	"github.com/dolthub/vitess/go/vt/sqlparser"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlfmt"
+)
+
+// CheckTable is a table with CHECK constraints.
+type CheckTable interface {
+	sql.Table
+	// GetChecks returns the CHECK constraints on this table.
+	GetChecks(ctx *sql.Context) ([]schema.Check, error)
+}
+
+// CheckAlterableTable is a table whose CHECK constraints can be modified.
+type CheckAlterableTable interface {
+	CheckTable
+	// CreateCheck adds the check given to this table. Returns an error if any existing row violates it.
+	CreateCheck(ctx *sql.Context, check schema.Check) error
+	// DropCheck removes the check with the given name from this table.
+	DropCheck(ctx *sql.Context, checkName string) error
+}
+
+// CheckDDL is a DDL statement that defines or drops CHECK constraints. The SQL parser doesn't support these, so their
+// clauses are removed from these statements before the engine executes them, and the checks are applied to the table
+// afterwards.
+type CheckDDL struct {
+	// Query is the statement with its CHECK constraint clauses removed. It's empty if nothing else is left to execute.
+	Query string
+	// Database is the database of the table, if the statement qualified its name.
+	Database string
+	// TableName is the name of the table the checks are defined on.
+	TableName string
+	// CreatesTable is true for CREATE TABLE statements.
+	CreatesTable bool
+	// IfNotExists is true for CREATE TABLE IF NOT EXISTS statements.
+	IfNotExists bool
+	// Add holds the checks to add to the table. Unnamed checks are named when they are added.
+	Add []schema.Check
+	// Drop holds the names of the checks to drop from the table.
+	Drop []string
+}
+
+// ParseCheckDDL returns the CheckDDL for the query given, or nil if the query is not a CREATE TABLE or ALTER TABLE
+// statement that defines or drops CHECK constraints.
+func ParseCheckDDL(query string) (*CheckDDL, error) {
+	// most queries aren't DDL, and don't need to be tokenized completely
+	if typ := firstTokenType(query); typ != sqlparser.CREATE && typ != sqlparser.ALTER {
+		return nil, nil
+	}
+
+	p, ok := newDDLParser(query)
+	if !ok {
+		return nil, nil
+	}
+
+	switch p.tok(0).typ {
+	case sqlparser.CREATE:
+		return p.parseCreateTableChecks()
+	case sqlparser.ALTER:
+		return p.parseAlterTableChecks()
+	default:
+		return nil, nil
+	}
+}
+
+// Exec executes the statement. |execQuery| is called to execute Query with the engine, after which the checks are
+// added to and dropped from the table. A table created by the statement is dropped again if its checks can't be added.
+func (cd *CheckDDL) Exec(ctx *sql.Context, catalog *sql.Catalog, execQuery func(query string) error) error {
+	ddl := tableDDL{
+		query:        cd.Query,
+		database:     cd.Database,
+		tableName:    cd.TableName,
+		createsTable: cd.CreatesTable,
+		ifNotExists:  cd.IfNotExists,
+	}
+	return ddl.exec(ctx, catalog, execQuery, cd.applyChecks)
+}
+
+// ExecCheckDDL executes |query| with |execQuery|. If the query defines or drops CHECK constraints, they're removed
+// from it before it's executed and applied to the table afterwards. Used to execute the Query of an IndexDDL.
+func ExecCheckDDL(ctx *sql.Context, catalog *sql.Catalog, query string, execQuery func(query string) error) error {
+	checkDDL, err := ParseCheckDDL(query)
+	if err != nil {
+		return err
+	} else if checkDDL == nil {
+		return execQuery(query)
+	}
+
+	return checkDDL.Exec(ctx, catalog, execQuery)
+}
+
+// tableDDL is a DDL statement on a table with clauses the SQL parser doesn't support, which are applied to the table
+// once the rest of the statement, |query|, is executed.
+type tableDDL struct {
+	query        string
+	database     string
+	tableName    string
+	createsTable bool
+	ifNotExists  bool
+}
+
+// exec executes the statement, calling |execQuery| to execute query with the engine and |apply| to apply the clauses
+// the engine doesn't support to the table. If they can't be applied the database's root is restored, so that the
+// statement has no effect; in databases without roots a table created by the statement is dropped again.
+func (td tableDDL) exec(ctx *sql.Context, catalog *sql.Catalog, execQuery func(query string) error, apply func(ctx *sql.Context, tbl sql.Table) error) (err error) {
+	dbName := td.database
+	if dbName == "" {
+		dbName = ctx.GetCurrentDatabase()
+	}
+	db, err := catalog.Database(dbName)
+	if err != nil {
+		return err
+	}
+
+	if doltDB, ok := db.(Database); ok {
+		root, rootErr := doltDB.GetRoot(ctx)
+		if rootErr != nil {
+			return rootErr
+		}
+
+		defer func() {
+			if err != nil {
+				_ = doltDB.restoreRoot(ctx, root)
+			}
+		}()
+	}
+
+	if td.createsTable && td.ifNotExists {
+		_, exists, err := db.GetTableInsensitive(ctx, td.tableName)
+		if err != nil {
+			return err
+		} else if exists {
+			return execQuery(td.query)
+		}
+	}
+
+	if td.query != "" {
+		err = execQuery(td.query)
+		if err != nil {
+			return err
+		}
+	}
+
+	tbl, ok, err := db.GetTableInsensitive(ctx, td.tableName)
+	if err != nil {
+		return err
+	} else if !ok {
+		return sql.ErrTableNotFound.New(td.tableName)
+	}
+
+	err = apply(ctx, tbl)
+	if err != nil && td.createsTable {
+		if _, ok := db.(Database); !ok {
+			if dropper, ok := db.(sql.TableDropper); ok {
+				_ = dropper.DropTable(ctx, td.tableName)
+			}
+		}
+	}
+
+	return err
+}
+
+func (cd *CheckDDL) applyChecks(ctx *sql.Context, tbl sql.Table) error {
+	checkTbl, ok := tbl.(CheckAlterableTable)
+	if !ok {
+		return fmt.Errorf("table %s does not support CHECK constraints", cd.TableName)
+	}
+
+	var err error
+	for _, name := range cd.Drop {
+		err = checkTbl.DropCheck(ctx, name)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, check := range cd.Add {
+		if check.Name == "" {
+			check.Name, err = generateCheckName(ctx, checkTbl)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = checkTbl.CreateCheck(ctx, check)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// generateCheckName returns the name MySQL gives to an unnamed check on the table given: the table name followed by
+// _chk_ and the lowest number that isn't already used.
+func generateCheckName(ctx *sql.Context, tbl CheckTable) (string, error) {
+	checks, err := tbl.GetChecks(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return unusedCheckName(tbl.Name(), checks), nil
+}
+
+func unusedCheckName(tableName string, checks []schema.Check) string {
+	used := make(map[string]bool)
+	for _, check := range checks {
+		used[strings.ToLower(check.Name)] = true
+	}
+
+	for i := 1; ; i++ {
+		name := fmt.Sprintf("%s_chk_%d", tableName, i)
+		if !used[strings.ToLower(name)] {
+			return name
+		}
+	}
+}
+
+// AddToSchema adds the checks defined by the statement to the schema of the table given, which was created from Query. Used when a schema is created from a statement without executing it.
+func (cd *CheckDDL) AddToSchema(tableName string, sch schema.Schema) error {
+	for _, check := range cd.Add {
+		names, err := check.ColumnNames()
+		if err != nil {
+			return err
+		}
+
+		for _, name := range names {
+			if _, ok := sch.GetAllCols().GetByNameCaseInsensitive(name); !ok {
+				return fmt.Errorf("check constraint '%s' refers to non-existing column '%s'", check.Name, name)
+			}
+		}
+
+		if check.Name == "" {
+			check.Name = unusedCheckName(tableName, sch.Checks().AllChecks())
+		}
+
+		_, err = sch.Checks().AddCheck(check.Name, check.Expression, check.Enforced)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *ddlParser) parseCreateTableChecks() (*CheckDDL, error) {
+	cd := &CheckDDL{CreatesTable: true}
+
+	i := 1
+	if p.tok(i).isWord("temporary") {
+		i++
+	}
+	if p.tok(i).typ != sqlparser.TABLE {
+		return nil, nil
+	}
+	i++
+
+	if p.tok(i).typ == sqlparser.IF && p.tok(i+1).typ == sqlparser.NOT && p.tok(i+2).typ == sqlparser.EXISTS {
+		cd.IfNotExists = true
+		i += 3
+	}
+
+	var ok bool
+	cd.Database, cd.TableName, i, ok = p.parseTableName(i)
+	if !ok || p.tok(i).typ != '(' {
+		return nil, nil
+	}
+
+	// |sep| is the '(' or ',' preceding the current table element, which is a table level constraint if it starts
+	// with CONSTRAINT or CHECK. Anywhere else, it's a column level check.
+	sep := i
+	elemStart := true
+	depth := 1
+	for i++; depth > 0 && i < len(p.toks); {
+		t := p.tok(i)
+		switch {
+		case t.typ == '(':
+			depth++
+		case t.typ == ')':
+			depth--
+		case depth == 1 && t.typ == ',':
+			sep, elemStart = i, true
+			i++
+			continue
+		case depth == 1 && (t.typ == sqlparser.CONSTRAINT || t.typ == sqlparser.CHECK):
+			check, next, ok, err := p.parseCheck(i)
+			if err != nil {
+				return nil, err
+			} else if !ok {
+				break
+			}
+
+			cd.Add = append(cd.Add, check)
+			i = p.cutElement(i, next, sep, elemStart)
+			continue
+		}
+
+		elemStart = false
+		i++
+	}
+
+	if len(cd.Add) == 0 {
+		return nil, nil
+	}
+
+	cd.Query = p.queryWithoutCuts()
+	return cd, nil
+}
+
+func (p *ddlParser) parseAlterTableChecks() (*CheckDDL, error) {
+	cd := &CheckDDL{}
+
+	i := 1
+	if p.tok(i).typ == sqlparser.IGNORE {
+		i++
+	}
+	if p.tok(i).typ != sqlparser.TABLE {
+		return nil, nil
+	}
+
+	var ok bool
+	cd.Database, cd.TableName, i, ok = p.parseTableName(i + 1)
+	if !ok {
+		return nil, nil
+	}
+
+	otherSpecs := false
+	for {
+		var isCheckSpec bool
+		switch t := p.tok(i); {
+		case t.typ == sqlparser.ADD:
+			check, next, ok, err := p.parseCheck(i + 1)
+			if err != nil {
+				return nil, err
+			} else if ok {
+				cd.Add = append(cd.Add, check)
+				i, isCheckSpec = next, true
+			}
+		case t.typ == sqlparser.DROP:
+			next := p.tok(i + 1)
+			if (next.typ == sqlparser.CHECK || next.typ == sqlparser.CONSTRAINT) && p.tok(i+2).typ == sqlparser.ID {
+				cd.Drop = append(cd.Drop, p.tok(i+2).val)
+				i, isCheckSpec = i+3, true
+			}
+		}
+
+		if !isCheckSpec {
+			otherSpecs = true
+			// skip to the next alter specification
+			for depth := 0; i < len(p.toks) && (depth > 0 || p.tok(i).typ != ','); i++ {
+				switch p.tok(i).typ {
+				case '(':
+					depth++
+				case ')':
+					depth--
+				}
+			}
+		}
+
+		switch p.tok(i).typ {
+		case ',':
+			i++
+			continue
+		case 0, ';':
+		default:
+			return nil, fmt.Errorf("syntax error near '%s'", p.query[p.tok(i).start():])
+		}
+		break
+	}
+
+	if len(cd.Add) == 0 && len(cd.Drop) == 0 {
+		return nil, nil
+	} else if otherSpecs {
+		return nil, fmt.Errorf("CHECK constraints cannot be altered along with other table definitions in one statement")
+	}
+
+	return cd, nil
+}
+
+// parseCheck parses a check constraint definition of the form [CONSTRAINT [name]] CHECK (expr) [[NOT] ENFORCED]
+// starting at index |i|. It returns the check, the index of the token following it, and false if there is no check
+// definition at |i|.
+func (p *ddlParser) parseCheck(i int) (check schema.Check, next int, ok bool, err error) {
+	if p.tok(i).typ == sqlparser.CONSTRAINT {
+		i++
+		if p.tok(i).typ == sqlparser.ID {
+			check.Name = p.tok(i).val
+			i++
+		}
+	}
+
+	if p.tok(i).typ != sqlparser.CHECK {
+		return schema.Check{}, 0, false, nil
+	}
+	i++
+
+	if p.tok(i).typ != '(' {
+		return schema.Check{}, 0, false, fmt.Errorf("syntax error: expected '(' after CHECK")
+	}
+
+	exprStart := p.tok(i).end
+	depth := 1
+	for i++; depth > 0; i++ {
+		switch p.tok(i).typ {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case 0:
+			return schema.Check{}, 0, false, fmt.Errorf("syntax error: unterminated CHECK expression")
+		}
+	}
+	exprEnd := p.tok(i - 1).start()
+
+	check.Expression, err = normalizeCheckExpression(p.query[exprStart:exprEnd])
+	if err != nil {
+		return schema.Check{}, 0, false, err
+	}
+
+	check.Enforced = true
+	if p.tok(i).typ == sqlparser.NOT && p.tok(i+1).isWord("enforced") {
+		check.Enforced = false
+		i += 2
+	} else if p.tok(i).isWord("enforced") {
+		i++
+	}
+
+	return check, i, true, nil
+}
+
+// normalizeCheckExpression parses the expression given, returning it in the canonical form the parser formats it in.
+func normalizeCheckExpression(expr string) (string, error) {
+	return normalizeExpression(expr, "CHECK")
+}
+
+// FmtCheck returns the definition of the check given, as it appears in a CREATE TABLE statement.
+func FmtCheck(check schema.Check) string {
+	def := fmt.Sprintf("CONSTRAINT `%s` CHECK (%s)", check.Name, check.Expression)
+	if !check.Enforced {
+		def += " NOT ENFORCED"
+	}
+	return def
+}
+
+const showCreateTableChecksRule = "show_create_table_checks"
+
+// NewAnalyzerBuilder returns an analyzer.Builder with the rules Dolt databases require added.
+func NewAnalyzerBuilder(c *sql.Catalog) *analyzer.Builder {
+	return analyzer.NewBuilder(c).
+		AddPostAnalyzeRule(autoIncrementCounterRule, useAutoIncrementCounter).
+		AddPostValidationRule(showCreateTableChecksRule, addChecksToShowCreateTable)
+}
+
+// NewDefaultEngine returns a new engine with the default configuration and the rules Dolt databases require.
+func NewDefaultEngine() *sqle.Engine {
+	c := sql.NewCatalog()
+	return sqle.New(c, NewAnalyzerBuilder(c).Build(), nil)
+}
+
+func addChecksToShowCreateTable(ctx *sql.Context, a *analyzer.Analyzer, n sql.Node, scope *analyzer.Scope) (sql.Node, error) {
+	return plan.TransformUp(n, func(n sql.Node) (sql.Node, error) {
+		if sct, ok := n.(*plan.ShowCreateTable); ok {
+			return &showCreateTableWithChecks{sct}, nil
+		}
+		return n, nil
+	})
+}
+
+// showCreateTableWithChecks adds the CHECK constraints of a table to the statement returned by SHOW CREATE TABLE,
+// which the engine doesn't know about, and fills in the key parts of its expression, prefix, FULLTEXT and SPATIAL
+// indexes, which the engine can't display. It is added once analysis is complete, and has no children so that the rules
+// which run after validation, such as parallelization, leave the table it shows untouched.
+type showCreateTableWithChecks struct {
+	*plan.ShowCreateTable
+}
+
+func (n *showCreateTableWithChecks) Children() []sql.Node {
+	return nil
+}
+
+func (n *showCreateTableWithChecks) WithChildren(children ...sql.Node) (sql.Node, error) {
+	if len(children) != 0 {
+		return nil, sql.ErrInvalidChildrenNumber.New(n, len(children), 0)
+	}
+	return n, nil
+}
+
+func (n *showCreateTableWithChecks) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	iter, err := n.ShowCreateTable.RowIter(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+
+	rt, ok := n.Child.(*plan.ResolvedTable)
+	if !ok || n.IsView {
+		return iter, nil
+	}
+
+	var checks []schema.Check
+	if checkTbl := getCheckTable(rt.Table); checkTbl != nil {
+		checks, err = checkTbl.GetChecks(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var indexes []schema.Index
+	if indexTbl := getIndexDefTable(rt.Table); indexTbl != nil {
+		allIndexes, err := indexTbl.GetIndexDefinitions(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, index := range allIndexes {
+			if len(index.Expressions()) > 0 || index.PrefixLengths() != nil || index.IsFullText() || index.IsSpatial() {
+				indexes = append(indexes, index)
+			}
+		}
+	}
+
+	if len(checks) == 0 && len(indexes) == 0 {
+		return iter, nil
+	}
+	return &showCreateTableChecksIter{iter, checks, indexes}, nil
+}
+
+// getCheckTable returns the underlying CheckTable for the table given, or nil if it isn't a CheckTable
+func getCheckTable(t sql.Table) CheckTable {
+	switch t := t.(type) {
+	case CheckTable:
+		return t
+	case sql.TableWrapper:
+		return getCheckTable(t.Underlying())
+	default:
+		return nil
+	}
+}
+
+// getIndexDefTable returns the underlying IndexDefTable for the table given, or nil if it isn't an IndexDefTable
+func getIndexDefTable(t sql.Table) IndexDefTable {
+	switch t := t.(type) {
+	case IndexDefTable:
+		return t
+	case sql.TableWrapper:
+		return getIndexDefTable(t.Underlying())
+	default:
+		return nil
+	}
+}
+
+type showCreateTableChecksIter struct {
+	sql.RowIter
+	checks  []schema.Check
+	indexes []schema.Index
+}
+
+func (i *showCreateTableChecksIter) Next() (sql.Row, error) {
+	row, err := i.RowIter.Next()
+	if err != nil {
+		return row, err
+	}
+
+	stmt, ok := row[1].(string)
+	if !ok {
+		return row, nil
+	}
+
+	for _, index := range i.indexes {
+		stmt = withIndexKeyParts(stmt, index)
+	}
+
+	// the table options follow the closing parenthesis of the table definition
+	end := strings.LastIndex(stmt, "\n)")
+	if end < 0 {
+		return sql.NewRow(row[0], stmt), nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString(stmt[:end])
+	for _, check := range i.checks {
+		sb.WriteString(",\n  ")
+		sb.WriteString(FmtCheck(check))
+	}
+	sb.WriteString(stmt[end:])
+
+	return sql.NewRow(row[0], sb.String()), nil
+}
+
+// withIndexKeyParts replaces the key parts of the index given in a CREATE TABLE statement with its definition's, and
+// marks it as a FULLTEXT or SPATIAL key if it is one.
+func withIndexKeyParts(stmt string, index schema.Index) string {
+	key := "KEY " + sqlfmt.QuoteIdentifier(index.Name()) + " ("
+	keyStart := strings.Index(stmt, key)
+	if keyStart < 0 {
+		return stmt
+	}
+	start := keyStart + len(key)
+	end := strings.IndexByte(stmt[start:], ')')
+	if end < 0 {
+		return stmt
+	}
+	stmt = stmt[:start] + sqlfmt.FmtIndexKeyParts(index) + stmt[start+end:]
+	if index.IsFullText() {
+		stmt = stmt[:keyStart] + "FULLTEXT " + stmt[keyStart:]
+	} else if index.IsSpatial() {
+		stmt = stmt[:keyStart] + "SPATIAL " + stmt[keyStart:]
+	}
+	return stmt
+}
@@ -0,0 +1,256 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+func TestParseCheckDDL(t *testing.T) {
+	tests := []struct {
+		name        string
+		query       string
+		expected    *CheckDDL
+		expectedErr bool
+	}{
+		{
+			name:     "no checks",
+			query:    "create table t (pk int primary key, c1 int)",
+			expected: nil,
+		},
+		{
+			name:     "not ddl",
+			query:    "select * from t where c1 > 0",
+			expected: nil,
+		},
+		{
+			name:  "leading comment",
+			query: "/* comment */ create table t (pk int primary key, c1 int check (c1>0))",
+			expected: &CheckDDL{
+				Query:        "/* comment */ create table t (pk int primary key, c1 int )",
+				TableName:    "t",
+				CreatesTable: true,
+				Add:          []schema.Check{{Expression: "c1 > 0", Enforced: true}},
+			},
+		},
+		{
+			name:  "column check",
+			query: "create table t (pk int primary key, c1 int check (c1>0))",
+			expected: &CheckDDL{
+				Query:        "create table t (pk int primary key, c1 int )",
+				TableName:    "t",
+				CreatesTable: true,
+				Add:          []schema.Check{{Expression: "c1 > 0", Enforced: true}},
+			},
+		},
+		{
+			name:  "table checks",
+			query: "create table if not exists db.t (constraint chk1 check (pk < c1), pk int primary key, c1 int, check (c1 in (1, 2)) not enforced)",
+			expected: &CheckDDL{
+				Query:        "create table if not exists db.t ( pk int primary key, c1 int)",
+				Database:     "db",
+				TableName:    "t",
+				CreatesTable: true,
+				IfNotExists:  true,
+				Add: []schema.Check{
+					{Name: "chk1", Expression: "pk < c1", Enforced: true},
+					{Expression: "c1 in (1, 2)", Enforced: false},
+				},
+			},
+		},
+		{
+			name:  "alter add check",
+			query: "alter table t add constraint chk1 check (c1 > 0) enforced, add check (c1 < 10)",
+			expected: &CheckDDL{
+				TableName: "t",
+				Add: []schema.Check{
+					{Name: "chk1", Expression: "c1 > 0", Enforced: true},
+					{Expression: "c1 < 10", Enforced: true},
+				},
+			},
+		},
+		{
+			name:  "alter drop check",
+			query: "alter table t drop check chk1, drop constraint chk2",
+			expected: &CheckDDL{
+				TableName: "t",
+				Drop:      []string{"chk1", "chk2"},
+			},
+		},
+		{
+			name:        "alter check with other specs",
+			query:       "alter table t add column c2 int, drop check chk1",
+			expectedErr: true,
+		},
+		{
+			name:        "invalid expression",
+			query:       "create table t (pk int primary key check (pk >))",
+			expectedErr: true,
+		},
+		{
+			name:  "table with checks and indexes",
+			query: "create table t (pk int primary key check (pk > 0), c1 varchar(20), index ((lower(c1))))",
+			expected: &CheckDDL{
+				Query:        "create table t (pk int primary key , c1 varchar(20), index ((lower(c1))))",
+				TableName:    "t",
+				CreatesTable: true,
+				Add:          []schema.Check{{Expression: "pk > 0", Enforced: true}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := ParseCheckDDL(test.query)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestCheckConstraints(t *testing.T) {
+	dEnv := dtestutils.CreateTestEnv()
+	root, err := dEnv.WorkingRoot(context.Background())
+	require.NoError(t, err)
+
+	root, err = ExecuteSql(dEnv, root, `
+CREATE TABLE test (
+  pk BIGINT PRIMARY KEY,
+  c1 BIGINT CHECK (c1 > 0),
+  c2 BIGINT,
+  CONSTRAINT c2_small CHECK (c2 < 100) NOT ENFORCED
+);
+INSERT INTO test VALUES (1, 1, 1), (2, NULL, 200);
+`)
+	require.NoError(t, err)
+
+	tbl, ok, err := root.GetTable(context.Background(), "test")
+	require.NoError(t, err)
+	require.True(t, ok)
+	sch, err := tbl.GetSchema(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []schema.Check{
+		{Name: "test_chk_1", Expression: "c1 > 0", Enforced: true},
+		{Name: "c2_small", Expression: "c2 < 100", Enforced: false},
+	}, sch.Checks().AllChecks())
+
+	_, err = ExecuteSql(dEnv, root, "INSERT INTO test VALUES (3, 0, 1)")
+	assert.EqualError(t, err, "Check constraint 'test_chk_1' is violated.")
+	_, err = ExecuteSql(dEnv, root, "ALTER TABLE test ADD CONSTRAINT c2_positive CHECK (c2 > 1)")
+	assert.EqualError(t, err, "Check constraint 'c2_positive' is violated.")
+	_, err = ExecuteSql(dEnv, root, "ALTER TABLE test ADD CONSTRAINT c2_small CHECK (c2 > 0)")
+	assert.Error(t, err)
+	_, err = ExecuteSql(dEnv, root, "ALTER TABLE test DROP COLUMN c1")
+	assert.Error(t, err)
+
+	root, err = ExecuteSql(dEnv, root, `
+ALTER TABLE test DROP CHECK test_chk_1;
+INSERT INTO test VALUES (3, 0, 1);
+ALTER TABLE test ADD CHECK (pk < 10);
+`)
+	require.NoError(t, err)
+
+	rows, err := ExecuteSelect(dEnv, dEnv.DoltDB, root, "SHOW CREATE TABLE test")
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, "CREATE TABLE `test` (\n"+
+		"  `pk` bigint NOT NULL,\n"+
+		"  `c1` bigint,\n"+
+		"  `c2` bigint,\n"+
+		"  PRIMARY KEY (`pk`),\n"+
+		"  CONSTRAINT `c2_small` CHECK (c2 < 100) NOT ENFORCED,\n"+
+		"  CONSTRAINT `test_chk_1` CHECK (pk < 10)\n"+
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4", rows[0][1])
+}
+
+func TestCheckConstraintsWithNonIntegerResults(t *testing.T) {
+	dEnv := dtestutils.CreateTestEnv()
+	root, err := dEnv.WorkingRoot(context.Background())
+	require.NoError(t, err)
+
+	root, err = ExecuteSql(dEnv, root, `
+CREATE TABLE test (
+  pk BIGINT PRIMARY KEY,
+  f DOUBLE CHECK (f),
+  d DECIMAL(5,2) CHECK (d)
+);
+INSERT INTO test VALUES (1, 0.1, 0.01);
+`)
+	require.NoError(t, err)
+
+	_, err = ExecuteSql(dEnv, root, "INSERT INTO test VALUES (2, 0.0, 1)")
+	assert.EqualError(t, err, "Check constraint 'test_chk_1' is violated.")
+	_, err = ExecuteSql(dEnv, root, "INSERT INTO test VALUES (2, 1, 0.00)")
+	assert.EqualError(t, err, "Check constraint 'test_chk_2' is violated.")
+	_, err = ExecuteSql(dEnv, root, "INSERT INTO test VALUES (2, NULL, NULL)")
+	assert.NoError(t, err)
+}
+
+func TestCheckDDLFailureRestoresRoot(t *testing.T) {
+	dEnv := dtestutils.CreateTestEnv()
+	root, err := dEnv.WorkingRoot(context.Background())
+	require.NoError(t, err)
+
+	root, err = ExecuteSql(dEnv, root, `
+CREATE TABLE test (pk BIGINT PRIMARY KEY, c1 BIGINT);
+INSERT INTO test VALUES (1, 0);
+`)
+	require.NoError(t, err)
+
+	db := NewDatabaseFromEnv("dolt", dEnv)
+	engine, sqlCtx, err := NewTestEngine(context.Background(), db, root)
+	require.NoError(t, err)
+	execQuery := func(query string) error {
+		_, rowIter, err := engine.Query(sqlCtx, query)
+		if err != nil {
+			return err
+		}
+		return drainIter(rowIter)
+	}
+
+	for _, query := range []string{
+		"ALTER TABLE test ADD CHECK (pk > 0), ADD CHECK (c1 > 0)",
+		"CREATE TABLE test2 (pk BIGINT PRIMARY KEY CHECK (missing > 0))",
+	} {
+		checkDDL, err := ParseCheckDDL(query)
+		require.NoError(t, err)
+		require.NotNil(t, checkDDL)
+		assert.Error(t, checkDDL.Exec(sqlCtx, engine.Catalog, execQuery), query)
+
+		// the parts of the statement which were applied are undone
+		newRoot, err := db.GetRoot(sqlCtx)
+		require.NoError(t, err)
+		assert.Equal(t, hashOf(t, root), hashOf(t, newRoot), query)
+	}
+}
+
+func hashOf(t *testing.T, root *doltdb.RootValue) hash.Hash {
+	h, err := root.HashOf()
+	require.NoError(t, err)
+	return h
+}
@@ -105,7 +105,7 @@ func ToSqlRows(sch schema.Schema, rs ...row.Row) []sql.Row {
 	sqlRows := make([]sql.Row, len(rs))
 	compressedSch := CompressSchema(sch)
 	for i := range rs {
-		sqlRows[i], _ = doltRowToSqlRow(CompressRow(sch, rs[i]), compressedSch)
+		sqlRows[i], _ = doltRowToSqlRow(context.Background(), CompressRow(sch, rs[i]), compressedSch)
 	}
 	return sqlRows
 }
@@ -115,7 +115,7 @@ func (itr conflictRowIter) Next() (sql.Row, error) {
 		return nil, err
 	}
 
-	return doltRowToSqlRow(cnf, itr.rd.GetSchema())
+	return doltRowToSqlRow(itr.ctx, cnf, itr.rd.GetSchema())
 }
 
 // Close the iterator.
@@ -135,7 +135,7 @@ type conflictDeleter struct {
 // Close is called.
 func (cd *conflictDeleter) Delete(ctx *sql.Context, r sql.Row) error {
 	cnfSch := cd.ct.rd.GetSchema()
-	cnfRow, err := SqlRowToDoltRow(cd.ct.tbl.Format(), r, cnfSch)
+	cnfRow, err := SqlRowToDoltRow(ctx, cd.ct.tbl.ValueReadWriter(), r, cnfSch)
 
 	if err != nil {
 		return err
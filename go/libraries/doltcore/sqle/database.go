@@ -31,6 +31,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions/commitwalk"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
 	"github.com/dolthub/dolt/go/libraries/doltcore/row"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema/alterschema"
@@ -53,9 +54,13 @@ const (
 
 const (
 	HeadKeySuffix    = "_head"
+	HeadRefKeySuffix = "_head_ref"
 	WorkingKeySuffix = "_working"
 )
 
+// BranchDatabaseSeparator separates the name of a database from the name of a branch in the names of branch databases
+const BranchDatabaseSeparator = "/"
+
 func IsHeadKey(key string) (bool, string) {
 	if strings.HasSuffix(key, HeadKeySuffix) {
 		return true, key[:len(key)-len(HeadKeySuffix)]
@@ -64,6 +69,14 @@ func IsHeadKey(key string) (bool, string) {
 	return false, ""
 }
 
+func IsHeadRefKey(key string) (bool, string) {
+	if strings.HasSuffix(key, HeadRefKeySuffix) {
+		return true, key[:len(key)-len(HeadRefKeySuffix)]
+	}
+
+	return false, ""
+}
+
 func IsWorkingKey(key string) (bool, string) {
 	if strings.HasSuffix(key, WorkingKeySuffix) {
 		return true, key[:len(key)-len(WorkingKeySuffix)]
@@ -106,6 +119,14 @@ func (tc *tableCache) Put(tableName string, root *doltdb.RootValue, tbl sql.Tabl
 	tablesForRoot[tableName] = tbl
 }
 
+// Clear removes the tables cached for the root given.
+func (tc *tableCache) Clear(root *doltdb.RootValue) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	delete(tc.tables, root)
+}
+
 func (tc *tableCache) AllForRoot(root *doltdb.RootValue) (map[string]sql.Table, bool) {
 	tc.mu.Lock()
 	defer tc.mu.Unlock()
@@ -137,6 +158,10 @@ type Database struct {
 	rsw       env.RepoStateWriter
 	batchMode commitBehavior
 	tc        *tableCache
+	commitMu  *sync.Mutex
+	branch    ref.DoltRef
+	dEnv      *env.DoltEnv
+	stats     *statisticsStore
 }
 
 var _ SqlDatabase = Database{}
@@ -155,9 +180,21 @@ func NewDatabase(name string, ddb *doltdb.DoltDB, rsr env.RepoStateReader, rsw e
 		rsw:       rsw,
 		batchMode: single,
 		tc:        &tableCache{&sync.Mutex{}, make(map[*doltdb.RootValue]map[string]sql.Table)},
+		commitMu:  &sync.Mutex{},
+		stats:     newStatisticsStore(nil, ""),
 	}
 }
 
+// NewDatabaseFromEnv returns a new dolt database for the repository of |dEnv|.  Unlike databases created with
+// NewDatabase, it can be used to work with the repository's remotes, and the table statistics collected by ANALYZE
+// TABLE are kept in the repository rather than in memory.
+func NewDatabaseFromEnv(name string, dEnv *env.DoltEnv) Database {
+	db := NewDatabase(name, dEnv.DoltDB, dEnv.RepoState, dEnv.RepoStateWriter())
+	db.dEnv = dEnv
+	db.stats = newStatisticsStore(dEnv.FS, dEnv.StatisticsFile())
+	return db
+}
+
 // NewBatchedDatabase returns a new dolt database executing in batch insert mode. Integrators must call Flush() to
 // commit any outstanding edits.
 func NewBatchedDatabase(name string, ddb *doltdb.DoltDB, rsr env.RepoStateReader, rsw env.RepoStateWriter) Database {
@@ -168,9 +205,51 @@ func NewBatchedDatabase(name string, ddb *doltdb.DoltDB, rsr env.RepoStateReader
 		rsw:       rsw,
 		batchMode: batched,
 		tc:        &tableCache{&sync.Mutex{}, make(map[*doltdb.RootValue]map[string]sql.Table)},
+		commitMu:  &sync.Mutex{},
+		stats:     newStatisticsStore(nil, ""),
 	}
 }
 
+// NewBatchedDatabaseFromEnv returns a new dolt database for the repository of |dEnv| executing in batch insert mode,
+// like the databases created with NewDatabaseFromEnv.
+func NewBatchedDatabaseFromEnv(name string, dEnv *env.DoltEnv) Database {
+	db := NewBatchedDatabase(name, dEnv.DoltDB, dEnv.RepoState, dEnv.RepoStateWriter())
+	db.dEnv = dEnv
+	db.stats = newStatisticsStore(dEnv.FS, dEnv.StatisticsFile())
+	return db
+}
+
+// NewBranchDatabase returns a database which sessions use to work on |branch| of the repository, rather than the branch
+// which is checked out.  Its name is the name of |db| and the branch joined by BranchDatabaseSeparator.
+func NewBranchDatabase(db Database, branch ref.DoltRef) Database {
+	return Database{
+		name:      db.name + BranchDatabaseSeparator + branch.GetPath(),
+		ddb:       db.ddb,
+		rsr:       db.rsr,
+		rsw:       db.rsw,
+		batchMode: db.batchMode,
+		tc:        db.tc,
+		commitMu:  db.commitMu,
+		branch:    branch,
+		dEnv:      db.dEnv,
+		stats:     db.stats,
+	}
+}
+
+// IsBranchDatabase returns true if this database was created using NewBranchDatabase
+func (db Database) IsBranchDatabase() bool {
+	return db.branch != nil
+}
+
+// headRef returns the ref which sessions using this database start on
+func (db Database) headRef() ref.DoltRef {
+	if db.branch != nil {
+		return db.branch
+	}
+
+	return db.rsr.CWBHeadRef()
+}
+
 // Name returns the name of this database, set at creation time.
 func (db Database) Name() string {
 	return db.name
@@ -210,6 +289,7 @@ func (db Database) GetTableInsensitiveWithRoot(ctx *sql.Context, root *doltdb.Ro
 		doltdb.DoltDiffTablePrefix:    NewDiffTable,
 		doltdb.DoltHistoryTablePrefix: NewHistoryTable,
 		doltdb.DoltConfTablePrefix:    NewConflictsTable,
+		doltdb.DoltBlameTablePrefix:   NewBlameTable,
 	}
 
 	for prefix, newFunc := range prefixToNew {
@@ -255,6 +335,24 @@ func (db Database) GetTableInsensitiveWithRoot(ctx *sql.Context, root *doltdb.Ro
 		return bt, true, nil
 	}
 
+	if lwrName == doltdb.PushStatusTableName {
+		pt, err := NewPushStatusTable(ctx, db.Name())
+
+		if err != nil {
+			return nil, false, err
+		}
+
+		return pt, true, nil
+	}
+
+	if lwrName == doltdb.QueryStatsTableName {
+		return NewQueryStatsTable(ctx), true, nil
+	}
+
+	if lwrName == doltdb.StatisticsTableName {
+		return NewStatisticsTable(ctx, db), true, nil
+	}
+
 	return db.getTable(ctx, root, tblName)
 }
 
@@ -505,42 +603,80 @@ func (db Database) GetRoot(ctx *sql.Context) (*doltdb.RootValue, error) {
 // Set a new root value for the database. Can be used if the dolt working
 // set value changes outside of the basic SQL execution engine.
 func (db Database) SetRoot(ctx *sql.Context, newRoot *doltdb.RootValue) error {
-	h, err := newRoot.HashOf()
+	return DSessFromSess(ctx.Session).setRoot(ctx, db.name, newRoot)
+}
+
+// restoreRoot sets the root of the database back to |root|, a root it had before. Altering a table updates the table
+// cached for the root it was read from, so the tables cached for |root| are discarded.
+func (db Database) restoreRoot(ctx *sql.Context, root *doltdb.RootValue) error {
+	db.tc.Clear(root)
+	return db.SetRoot(ctx, root)
+}
+
+// LoadRootFromRepoState loads the root value from the repo state's working hash, and starts a new transaction from the
+// loaded root value.  Sessions which are not on the checked out branch keep their own working root, which is not
+// changed.
+func (db Database) LoadRootFromRepoState(ctx *sql.Context) error {
+	dsess := DSessFromSess(ctx.Session)
+	if !dsess.UsesRepoWorkingSet(db.name) {
+		return nil
+	}
 
+	workingHash := db.rsr.WorkingHash()
+	root, err := db.ddb.ReadRootValue(ctx, workingHash)
 	if err != nil {
 		return err
 	}
 
-	hashStr := h.String()
-	key := db.WorkingKey()
+	return dsess.startTransaction(ctx, db.name, root)
+}
 
-	err = ctx.Session.Set(ctx, key, hashType, hashStr)
+// FastForwardBranch moves |branch| to |cm|, which must be a descendant of its head.  If |branch| is checked out in the
+// repository, its working set and staged root are replaced by the root of |cm|, discarding any uncommitted changes.
+// Sessions move to the new head with DoltSession.RefreshHead.
+func (db Database) FastForwardBranch(ctx context.Context, branch ref.DoltRef, cm *doltdb.Commit) error {
+	db.commitMu.Lock()
+	defer db.commitMu.Unlock()
 
+	head, err := db.ddb.ResolveRef(ctx, branch)
+	if err != nil && err != doltdb.ErrBranchNotFound {
+		return err
+	}
+
+	if head != nil {
+		_, err = head.CanFastForwardTo(ctx, cm)
+		if err == doltdb.ErrUpToDate {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+
+	err = db.ddb.FastForward(ctx, branch, cm)
 	if err != nil {
 		return err
 	}
 
-	dsess := DSessFromSess(ctx.Session)
-	dsess.dbRoots[db.name] = dbRoot{hashStr, newRoot}
+	if !ref.Equals(branch, db.rsr.CWBHeadRef()) {
+		return nil
+	}
 
-	err = dsess.dbEditors[db.name].SetRoot(ctx, newRoot)
+	root, err := cm.GetRootValue()
 	if err != nil {
 		return err
 	}
 
-	return nil
-}
+	h, err := db.ddb.WriteRootValue(ctx, root)
+	if err != nil {
+		return err
+	}
 
-// LoadRootFromRepoState loads the root value from the repo state's working hash, then calls SetRoot with the loaded
-// root value.
-func (db Database) LoadRootFromRepoState(ctx *sql.Context) error {
-	workingHash := db.rsr.WorkingHash()
-	root, err := db.ddb.ReadRootValue(ctx, workingHash)
+	err = db.rsw.SetStagedHash(ctx, h)
 	if err != nil {
 		return err
 	}
 
-	return db.SetRoot(ctx, root)
+	return db.rsw.SetWorkingHash(ctx, h)
 }
 
 // DropTable drops the table with the name given
@@ -15,9 +15,14 @@
 package sqle
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
 )
 
 func testKeyFunc(t *testing.T, keyFunc func(string) (bool, string), testVal string, expectedIsKey bool, expectedDBName string) {
@@ -34,3 +39,57 @@ func TestIsKeyFuncs(t *testing.T) {
 	testKeyFunc(t, IsHeadKey, "dolt_working", false, "")
 	testKeyFunc(t, IsWorkingKey, "dolt_working", true, "dolt")
 }
+
+func TestFastForwardBranch(t *testing.T) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	db := NewDatabase("dolt", dEnv.DoltDB, dEnv.RepoState, dEnv.RepoStateWriter())
+
+	sqlCtx := NewTestSQLCtx(ctx)
+	dSess := DSessFromSess(sqlCtx.Session)
+	require.NoError(t, dSess.AddDB(ctx, db))
+
+	master := dEnv.RepoState.CWBHeadRef()
+	head, err := dEnv.DoltDB.ResolveRef(ctx, master)
+	require.NoError(t, err)
+	root, err := head.GetRootValue()
+	require.NoError(t, err)
+
+	root, err = executeModify(ctx, dEnv, root, "create table t (pk int primary key)")
+	require.NoError(t, err)
+	h, err := dEnv.DoltDB.WriteRootValue(ctx, root)
+	require.NoError(t, err)
+	meta, err := doltdb.NewCommitMeta("Bill Billerson", "bigbillieb@fake.horse", "create t")
+	require.NoError(t, err)
+	cm, err := dEnv.DoltDB.WriteDanglingCommit(ctx, h, []*doltdb.Commit{head}, meta)
+	require.NoError(t, err)
+
+	require.NoError(t, db.FastForwardBranch(ctx, master, cm))
+	require.NoError(t, db.FastForwardBranch(ctx, master, cm))
+	assert.Equal(t, h, dEnv.RepoState.WorkingHash())
+	assert.Equal(t, h, dEnv.RepoState.StagedHash())
+
+	newHead, err := dEnv.DoltDB.ResolveRef(ctx, master)
+	require.NoError(t, err)
+	cmHash, err := cm.HashOf()
+	require.NoError(t, err)
+	newHeadHash, err := newHead.HashOf()
+	require.NoError(t, err)
+	assert.Equal(t, cmHash, newHeadHash)
+
+	assert.Error(t, db.FastForwardBranch(ctx, master, head))
+
+	sessRoot, _ := dSess.GetRoot("dolt")
+	hasTable, err := sessRoot.HasTable(ctx, "t")
+	require.NoError(t, err)
+	assert.False(t, hasTable)
+
+	require.NoError(t, dSess.RefreshHead(sqlCtx, "dolt"))
+	sessRoot, _ = dSess.GetRoot("dolt")
+	hasTable, err = sessRoot.HasTable(ctx, "t")
+	require.NoError(t, err)
+	assert.True(t, hasTable)
+
+	_, sessHead := dSess.Get("dolt" + HeadKeySuffix)
+	assert.Equal(t, cmHash.String(), sessHead)
+}
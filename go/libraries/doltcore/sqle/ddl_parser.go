@@ -0,0 +1,210 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/dolthub/vitess/go/vt/sqlparser"
+)
+
+type ddlToken struct {
+	typ int
+	val string
+	// end is the offset in the query just past the token.
+	end int
+}
+
+// start returns the offset of the start of the token. It's only accurate for keywords, unquoted identifiers and
+// single character tokens.
+func (t ddlToken) start() int {
+	if len(t.val) == 0 {
+		return t.end - 1
+	}
+	return t.end - len(t.val)
+}
+
+func (t ddlToken) isWord(word string) bool {
+	return t.typ == sqlparser.ID && strings.EqualFold(t.val, word)
+}
+
+// ddlParser tokenizes statements the SQL parser doesn't completely support, so that the clauses it doesn't support can
+// be parsed and cut from them, or replaced with ones it does.
+type ddlParser struct {
+	query string
+	toks  []ddlToken
+	cuts  []ddlCut
+}
+
+// ddlCut is a part of a query which is removed from it, or replaced with |repl|.
+type ddlCut struct {
+	start, end int
+	repl       string
+}
+
+func newDDLParser(query string) (*ddlParser, bool) {
+	tkn := sqlparser.NewStringTokenizer(query)
+	p := &ddlParser{query: query}
+	for {
+		typ, val := tkn.Scan()
+		switch typ {
+		case 0:
+			return p, true
+		case sqlparser.LEX_ERROR:
+			// leave reporting the error to the parser
+			return nil, false
+		case sqlparser.COMMENT:
+			continue
+		}
+
+		end := tkn.Position - 1
+		if end > len(query) {
+			end = len(query)
+		}
+		p.toks = append(p.toks, ddlToken{typ: typ, val: string(val), end: end})
+	}
+}
+
+// firstTokenType returns the type of the first token of the query given which isn't a comment.
+func firstTokenType(query string) int {
+	tkn := sqlparser.NewStringTokenizer(query)
+	for {
+		typ, _ := tkn.Scan()
+		if typ != sqlparser.COMMENT {
+			return typ
+		}
+	}
+}
+
+// Identifiers returns the identifiers of |query|, without their quotes, or nil if the query can't be tokenized.  Unlike
+// a search of the query's text, it doesn't return the contents of strings and comments.
+func Identifiers(query string) []string {
+	p, ok := newDDLParser(query)
+	if !ok {
+		return nil
+	}
+
+	var ids []string
+	for _, t := range p.toks {
+		if t.typ == sqlparser.ID {
+			ids = append(ids, t.val)
+		}
+	}
+
+	return ids
+}
+
+// tokenStarts returns the offsets of the starts of the tokens, which are accurate for every token not preceded by a
+// comment.
+func (p *ddlParser) tokenStarts() []int {
+	starts := make([]int, len(p.toks))
+	for i := range p.toks {
+		pos := 0
+		if i > 0 {
+			pos = p.toks[i-1].end
+		}
+		for pos < len(p.query) && unicode.IsSpace(rune(p.query[pos])) {
+			pos++
+		}
+		starts[i] = pos
+	}
+	return starts
+}
+
+// tok returns the token at index |i|, or a token with type 0 past the end of the query.
+func (p *ddlParser) tok(i int) ddlToken {
+	if i < len(p.toks) {
+		return p.toks[i]
+	}
+	return ddlToken{end: len(p.query)}
+}
+
+// parseTableName parses a table name starting at index |i|, returning the database qualifying it, if any, and the
+// index of the following token.
+func (p *ddlParser) parseTableName(i int) (database, tableName string, next int, ok bool) {
+	if p.tok(i).typ != sqlparser.ID {
+		return "", "", i, false
+	}
+
+	if p.tok(i+1).typ == '.' && p.tok(i+2).typ == sqlparser.ID {
+		return p.tok(i).val, p.tok(i + 2).val, i + 3, true
+	}
+
+	return "", p.tok(i).val, i + 1, true
+}
+
+// cutElement cuts the clause of a table element or alter specification from index |i| up to index |next|, along with
+// the comma separating it from the other elements if it's a whole element. |sep| is the index of the token preceding
+// the element the clause is part of, and |elemStart| is true if the clause starts the element. It returns the index of
+// the token following the cut.
+func (p *ddlParser) cutElement(i, next, sep int, elemStart bool) int {
+	clauseStart, clauseEnd := p.tok(i).start(), p.tok(next-1).end
+	if !elemStart {
+		p.cut(clauseStart, clauseEnd)
+	} else if p.tok(sep).typ == ',' {
+		p.cut(p.tok(sep).start(), clauseEnd)
+	} else if p.tok(next).typ == ',' {
+		// the first element, which takes the following comma with it
+		p.cut(clauseStart, p.tok(next).end)
+		next++
+	} else {
+		p.cut(clauseStart, clauseEnd)
+	}
+	return next
+}
+
+func (p *ddlParser) cut(start, end int) {
+	p.cuts = append(p.cuts, ddlCut{start: start, end: end})
+}
+
+// replace replaces the part of the query from |start| to |end| with |repl|. Like cuts, replacements must be made in
+// the order they appear in the query.
+func (p *ddlParser) replace(start, end int, repl string) {
+	p.cuts = append(p.cuts, ddlCut{start: start, end: end, repl: repl})
+}
+
+func (p *ddlParser) queryWithoutCuts() string {
+	var sb strings.Builder
+	pos := 0
+	for _, c := range p.cuts {
+		sb.WriteString(p.query[pos:c.start])
+		sb.WriteString(c.repl)
+		pos = c.end
+	}
+	sb.WriteString(p.query[pos:])
+	return sb.String()
+}
+
+// normalizeExpression parses the expression given, returning it in the canonical form the parser formats it in.
+// |kind| describes what the expression is part of in errors.
+func normalizeExpression(expr, kind string) (string, error) {
+	stmt, err := sqlparser.Parse("SELECT " + expr)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s expression '%s': %w", kind, expr, err)
+	}
+
+	sel, ok := stmt.(*sqlparser.Select)
+	if !ok || len(sel.SelectExprs) != 1 {
+		return "", fmt.Errorf("invalid %s expression '%s'", kind, expr)
+	}
+	aliased, ok := sel.SelectExprs[0].(*sqlparser.AliasedExpr)
+	if !ok {
+		return "", fmt.Errorf("invalid %s expression '%s'", kind, expr)
+	}
+
+	return sqlparser.String(aliased.Expr), nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dfunctions
+
+import (
+	"errors"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+)
+
+const AddFuncName = "dolt_add"
+
+const addAllFlag = "all"
+
+// AddFunc stages tables of the session's current database, like dolt add.  Only sessions on the branch checked out in
+// the repository have a staging area.
+type AddFunc struct {
+	doltFunc
+}
+
+// NewAddFunc creates a new AddFunc expression.
+func NewAddFunc(args ...sql.Expression) (sql.Expression, error) {
+	return &AddFunc{doltFunc{AddFuncName, args}}, nil
+}
+
+func addArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParser()
+	ap.SupportsFlag(addAllFlag, "A", "Stages any and all changes (adds, deletes, and modifications).")
+	return ap
+}
+
+// Eval implements the Expression interface.
+func (af *AddFunc) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	apr, err := af.parseArgs(ctx, row, addArgParser())
+
+	if err != nil {
+		return nil, err
+	}
+
+	dSess, dbName, _, err := currentDatabase(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !dSess.UsesRepoWorkingSet(dbName) {
+		return nil, sqle.ErrNoStagingArea.New(dbName)
+	}
+
+	working, staged, _, err := dSess.GetRoots(ctx, dbName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	tbls := apr.Args()
+
+	if len(tbls) == 0 && !apr.Contains(addAllFlag) {
+		return nil, errors.New("nothing specified, nothing added; maybe you wanted to say dolt_add('.')?")
+	}
+
+	if apr.Contains(addAllFlag) || (len(tbls) == 1 && tbls[0] == ".") {
+		tbls, err = doltdb.UnionTableNames(ctx, staged, working)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	staged, working, err = actions.StageTablesInRoots(ctx, tbls, staged, working)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return 0, dSess.UpdateRoots(ctx, dbName, working, staged)
+}
+
+// WithChildren implements the Expression interface.
+func (af *AddFunc) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	return NewAddFunc(children...)
+}
@@ -0,0 +1,200 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dfunctions
+
+import (
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+)
+
+const BranchFuncName = "dolt_branch"
+
+const (
+	branchForceFlag       = "force"
+	branchCopyFlag        = "copy"
+	branchMoveFlag        = "move"
+	branchDeleteFlag      = "delete"
+	branchDeleteForceFlag = "D"
+)
+
+// BranchFunc creates, copies, renames and deletes branches, like dolt branch.  Branches are listed by the dolt_branches
+// system table.
+type BranchFunc struct {
+	doltFunc
+}
+
+// NewBranchFunc creates a new BranchFunc expression.
+func NewBranchFunc(args ...sql.Expression) (sql.Expression, error) {
+	return &BranchFunc{doltFunc{BranchFuncName, args}}, nil
+}
+
+func branchArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParser()
+	ap.SupportsFlag(branchForceFlag, "f", "Reset <branchname> to <startpoint>, even if <branchname> exists already.")
+	ap.SupportsFlag(branchCopyFlag, "c", "Create a copy of a branch.")
+	ap.SupportsFlag(branchMoveFlag, "m", "Move/rename a branch")
+	ap.SupportsFlag(branchDeleteFlag, "d", "Delete a branch. The branch must be fully merged in its upstream branch.")
+	ap.SupportsFlag(branchDeleteForceFlag, "", "Shortcut for --delete --force.")
+	return ap
+}
+
+// Eval implements the Expression interface.
+func (bf *BranchFunc) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	apr, err := bf.parseArgs(ctx, row, branchArgParser())
+
+	if err != nil {
+		return nil, err
+	}
+
+	dSess, dbName, ddb, err := currentDatabase(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	force := apr.Contains(branchForceFlag)
+
+	switch {
+	case apr.Contains(branchDeleteFlag) || apr.Contains(branchDeleteForceFlag):
+		if apr.NArg() == 0 {
+			return nil, fmt.Errorf("%s: expected the names of the branches to delete", BranchFuncName)
+		}
+
+		for _, brName := range apr.Args() {
+			err = checkBranchNotInUse(dSess, dbName, brName)
+
+			if err != nil {
+				return nil, err
+			}
+
+			opts := actions.DeleteOptions{Force: force || apr.Contains(branchDeleteForceFlag)}
+			err = actions.DeleteBranchOnDB(ctx, ddb, ref.NewBranchRef(brName), opts)
+
+			if err != nil {
+				return nil, err
+			}
+		}
+
+	case apr.Contains(branchCopyFlag):
+		if apr.NArg() != 2 {
+			return nil, fmt.Errorf("%s: copying a branch requires the names of the branch and its copy", BranchFuncName)
+		}
+
+		err = actions.CopyBranchOnDB(ctx, ddb, apr.Arg(0), apr.Arg(1), force)
+
+		if err != nil {
+			return nil, err
+		}
+
+	case apr.Contains(branchMoveFlag):
+		if apr.NArg() != 2 {
+			return nil, fmt.Errorf("%s: renaming a branch requires its current and new names", BranchFuncName)
+		}
+
+		err = renameBranch(ctx, dSess, dbName, apr.Arg(0), apr.Arg(1), force)
+
+		if err != nil {
+			return nil, err
+		}
+
+	default:
+		if apr.NArg() == 0 || apr.NArg() > 2 {
+			return nil, fmt.Errorf("%s: expected a branch name and an optional start point", BranchFuncName)
+		}
+
+		startPt := "head"
+		if apr.NArg() == 2 {
+			startPt = apr.Arg(1)
+		}
+
+		startPt, headRef, err := resolveStartPoint(ctx, dSess, dbName, startPt)
+
+		if err != nil {
+			return nil, err
+		}
+
+		err = actions.CreateBranchOnDB(ctx, ddb, apr.Arg(0), startPt, force, headRef)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return 0, nil
+}
+
+// renameBranch renames the branch |oldBranch|, and moves the session to the new branch if it was working on it.  The
+// branch checked out in the repository can't be renamed.
+func renameBranch(ctx *sql.Context, dSess *sqle.DoltSession, dbName, oldBranch, newBranch string, force bool) error {
+	rsr := dSess.GetStateReader(dbName)
+
+	if rsr != nil && ref.Equals(rsr.CWBHeadRef(), ref.NewBranchRef(oldBranch)) {
+		return actions.ErrCOBranchDelete
+	}
+
+	ddb, _ := dSess.GetDoltDB(dbName)
+	err := actions.CopyBranchOnDB(ctx, ddb, oldBranch, newBranch, force)
+
+	if err != nil {
+		return err
+	}
+
+	err = actions.DeleteBranchOnDB(ctx, ddb, ref.NewBranchRef(oldBranch), actions.DeleteOptions{Force: true})
+
+	if err != nil {
+		return err
+	}
+
+	if headRef, ok := dSess.GetHeadRef(dbName); ok && ref.Equals(headRef, ref.NewBranchRef(oldBranch)) {
+		// switching branches resets the session's working root, so the changes it has made are put back afterwards
+		working, _ := dSess.GetRoot(dbName)
+		err = dSess.Set(ctx, dbName+sqle.HeadRefKeySuffix, sql.Text, newBranch)
+
+		if err != nil {
+			return err
+		}
+
+		return dSess.UpdateRoots(ctx, dbName, working, nil)
+	}
+
+	return nil
+}
+
+// checkBranchNotInUse returns ErrCOBranchDelete if |brName| is checked out in the repository, or is the branch the
+// session is working on.
+func checkBranchNotInUse(dSess *sqle.DoltSession, dbName, brName string) error {
+	brRef := ref.NewBranchRef(brName)
+
+	if rsr := dSess.GetStateReader(dbName); rsr != nil && ref.Equals(rsr.CWBHeadRef(), brRef) {
+		return actions.ErrCOBranchDelete
+	}
+
+	if headRef, ok := dSess.GetHeadRef(dbName); ok && ref.Equals(headRef, brRef) {
+		return actions.ErrCOBranchDelete
+	}
+
+	return nil
+}
+
+// WithChildren implements the Expression interface.
+func (bf *BranchFunc) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	return NewBranchFunc(children...)
+}
@@ -0,0 +1,55 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dfunctions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+)
+
+func TestBranchDeleteCheckedOut(t *testing.T) {
+	dEnv := dtestutils.CreateTestEnv()
+	engine, sqlCtx := newTestEngine(t, dEnv)
+
+	require.NoError(t, execQuery(engine, sqlCtx, "select dolt_branch('other')"))
+	require.NoError(t, execQuery(engine, sqlCtx, "select dolt_checkout('-b', 'feature')"))
+
+	// the branch the session is working on
+	err := execQuery(engine, sqlCtx, "select dolt_branch('-d', 'feature')")
+	assert.Equal(t, actions.ErrCOBranchDelete.Error(), err.Error())
+
+	// the branch checked out in the repository, even when forced
+	err = execQuery(engine, sqlCtx, "select dolt_branch('-D', 'master')")
+	assert.Equal(t, actions.ErrCOBranchDelete.Error(), err.Error())
+
+	require.NoError(t, execQuery(engine, sqlCtx, "select dolt_branch('-d', 'other')"))
+
+	for _, branch := range []string{"master", "feature"} {
+		hasRef, err := dEnv.DoltDB.HasRef(context.Background(), ref.NewBranchRef(branch))
+		require.NoError(t, err)
+		assert.True(t, hasRef, branch)
+	}
+
+	hasRef, err := dEnv.DoltDB.HasRef(context.Background(), ref.NewBranchRef("other"))
+	require.NoError(t, err)
+	assert.False(t, hasRef)
+}
@@ -0,0 +1,135 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dfunctions
+
+import (
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+)
+
+const CheckoutFuncName = "dolt_checkout"
+
+const checkoutBranchArg = "b"
+
+// CheckoutFunc switches the session to another branch, like dolt checkout.  It only changes the branch of the calling
+// session; the branch checked out in the repository is unchanged.  Given table names it discards the session's
+// changes to those tables.
+type CheckoutFunc struct {
+	doltFunc
+}
+
+// NewCheckoutFunc creates a new CheckoutFunc expression.
+func NewCheckoutFunc(args ...sql.Expression) (sql.Expression, error) {
+	return &CheckoutFunc{doltFunc{CheckoutFuncName, args}}, nil
+}
+
+func checkoutArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParser()
+	ap.SupportsString(checkoutBranchArg, "", "branch", "Create a new branch named <branch> and switch to it.")
+	return ap
+}
+
+// Eval implements the Expression interface.
+func (cf *CheckoutFunc) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	apr, err := cf.parseArgs(ctx, row, checkoutArgParser())
+
+	if err != nil {
+		return nil, err
+	}
+
+	dSess, dbName, ddb, err := currentDatabase(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if newBranch, ok := apr.GetValue(checkoutBranchArg); ok {
+		if apr.NArg() > 1 {
+			return nil, fmt.Errorf("%s: -b takes at most one start point", CheckoutFuncName)
+		}
+
+		startPt := "head"
+		if apr.NArg() == 1 {
+			startPt = apr.Arg(0)
+		}
+
+		startPt, headRef, err := resolveStartPoint(ctx, dSess, dbName, startPt)
+
+		if err != nil {
+			return nil, err
+		}
+
+		err = actions.CreateBranchOnDB(ctx, ddb, newBranch, startPt, false, headRef)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return 0, checkoutBranch(ctx, dSess, dbName, newBranch)
+	}
+
+	if apr.NArg() == 0 {
+		return nil, fmt.Errorf("%s: expected a branch or table names", CheckoutFuncName)
+	}
+
+	if apr.NArg() == 1 {
+		isBranch, err := ddb.HasRef(ctx, ref.NewBranchRef(apr.Arg(0)))
+
+		if err != nil {
+			return nil, err
+		} else if isBranch {
+			return 0, checkoutBranch(ctx, dSess, dbName, apr.Arg(0))
+		}
+	}
+
+	working, staged, head, err := dSess.GetRoots(ctx, dbName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	working, err = actions.CheckoutTablesInRoots(ctx, apr.Args(), working, staged, head)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return 0, dSess.UpdateRoots(ctx, dbName, working, nil)
+}
+
+// checkoutBranch switches the session to |branch|.  Sessions which don't share the repository's working set would lose
+// their changes, so they can only switch branches when they have none.
+func checkoutBranch(ctx *sql.Context, dSess *sqle.DoltSession, dbName, branch string) error {
+	if !dSess.UsesRepoWorkingSet(dbName) {
+		err := checkNoUncommittedChanges(ctx, dSess, dbName)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return dSess.Set(ctx, dbName+sqle.HeadRefKeySuffix, sql.Text, branch)
+}
+
+// WithChildren implements the Expression interface.
+func (cf *CheckoutFunc) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	return NewCheckoutFunc(children...)
+}
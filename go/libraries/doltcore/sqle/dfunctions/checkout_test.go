@@ -0,0 +1,55 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dfunctions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+)
+
+func TestCheckoutNewBranch(t *testing.T) {
+	dEnv := dtestutils.CreateTestEnv()
+	engine, sqlCtx := newTestEngine(t, dEnv)
+	dSess := sqle.DSessFromSess(sqlCtx.Session)
+
+	require.NoError(t, execQuery(engine, sqlCtx, "select dolt_checkout('-b', 'feature')"))
+
+	hasRef, err := dEnv.DoltDB.HasRef(context.Background(), ref.NewBranchRef("feature"))
+	require.NoError(t, err)
+	assert.True(t, hasRef)
+
+	// only the session switches branches, the repository stays on master
+	headRef, ok := dSess.GetHeadRef("dolt")
+	require.True(t, ok)
+	assert.Equal(t, ref.NewBranchRef("feature"), headRef)
+	assert.Equal(t, ref.NewBranchRef("master"), dEnv.RepoState.CWBHeadRef())
+	assert.False(t, dSess.UsesRepoWorkingSet("dolt"))
+
+	// the branch exists now
+	err = execQuery(engine, sqlCtx, "select dolt_checkout('-b', 'feature')")
+	assert.Error(t, err)
+
+	require.NoError(t, execQuery(engine, sqlCtx, "select dolt_checkout('master')"))
+	headRef, _ = dSess.GetHeadRef("dolt")
+	assert.Equal(t, ref.NewBranchRef("master"), headRef)
+	assert.True(t, dSess.UsesRepoWorkingSet("dolt"))
+}
@@ -0,0 +1,167 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dfunctions
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+)
+
+var ErrDetachedHead = errors.New("the session's head is not on a branch")
+var ErrUncommittedChanges = errors.New("the session has uncommitted changes")
+
+// doltFunc is embedded by the functions which are equivalent to a dolt command.  They take the command's arguments as
+// string arguments, e.g. DOLT_BRANCH('-d', 'feature'), and return 0 on success.
+type doltFunc struct {
+	name     string
+	children []sql.Expression
+}
+
+// Resolved implements the Expression interface.
+func (df doltFunc) Resolved() bool {
+	for _, child := range df.children {
+		if !child.Resolved() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Children implements the Expression interface.
+func (df doltFunc) Children() []sql.Expression {
+	return df.children
+}
+
+// IsNullable implements the Expression interface.
+func (df doltFunc) IsNullable() bool {
+	return false
+}
+
+// Type implements the Expression interface.
+func (df doltFunc) Type() sql.Type {
+	return sql.Int8
+}
+
+// String implements the Stringer interface.
+func (df doltFunc) String() string {
+	args := make([]string, len(df.children))
+	for i, child := range df.children {
+		args[i] = child.String()
+	}
+
+	return fmt.Sprintf("%s(%s)", strings.ToUpper(df.name), strings.Join(args, ", "))
+}
+
+// parseArgs evaluates the arguments of the function and parses them with |ap|.
+func (df doltFunc) parseArgs(ctx *sql.Context, row sql.Row, ap *argparser.ArgParser) (*argparser.ArgParseResults, error) {
+	args := make([]string, len(df.children))
+	for i, child := range df.children {
+		val, err := child.Eval(ctx, row)
+
+		if err != nil {
+			return nil, err
+		}
+
+		str, ok := val.(string)
+
+		if !ok {
+			return nil, fmt.Errorf("%s: arguments must be strings", df.name)
+		}
+
+		args[i] = str
+	}
+
+	apr, err := ap.Parse(args)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", df.name, err)
+	}
+
+	return apr, nil
+}
+
+// currentDatabase returns the dolt session and the *DoltDB of the session's current database.
+func currentDatabase(ctx *sql.Context) (*sqle.DoltSession, string, *doltdb.DoltDB, error) {
+	dSess := sqle.DSessFromSess(ctx.Session)
+	dbName := ctx.GetCurrentDatabase()
+
+	ddb, ok := dSess.GetDoltDB(dbName)
+
+	if !ok {
+		return nil, "", nil, sql.ErrDatabaseNotFound.New(dbName)
+	}
+
+	return dSess, dbName, ddb, nil
+}
+
+// resolveStartPoint returns the commit spec string which refers to |startPt| for the database |dbName| of the session,
+// and the branch that HEAD refers to.  If the session's head was set to a commit, HEAD is replaced by the hash of the
+// commit.
+func resolveStartPoint(ctx *sql.Context, dSess *sqle.DoltSession, dbName, startPt string) (string, ref.DoltRef, error) {
+	headRef, ok := dSess.GetHeadRef(dbName)
+
+	if ok {
+		return startPt, headRef, nil
+	}
+
+	if strings.EqualFold(startPt, "head") {
+		_, h, err := dSess.GetParentCommit(ctx, dbName)
+
+		if err != nil {
+			return "", nil, err
+		}
+
+		return h.String(), nil, nil
+	}
+
+	return startPt, nil, nil
+}
+
+// checkNoUncommittedChanges returns ErrUncommittedChanges if the working root of the database |dbName| differs from
+// the root of the session's head commit.
+func checkNoUncommittedChanges(ctx *sql.Context, dSess *sqle.DoltSession, dbName string) error {
+	working, _, head, err := dSess.GetRoots(ctx, dbName)
+
+	if err != nil {
+		return err
+	}
+
+	wh, err := working.HashOf()
+
+	if err != nil {
+		return err
+	}
+
+	hh, err := head.HashOf()
+
+	if err != nil {
+		return err
+	}
+
+	if wh != hh {
+		return ErrUncommittedChanges
+	}
+
+	return nil
+}
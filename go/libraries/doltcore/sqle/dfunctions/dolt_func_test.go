@@ -0,0 +1,86 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dfunctions
+
+import (
+	"context"
+	"testing"
+
+	sqle "github.com/dolthub/go-mysql-server"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	dsqle "github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+)
+
+// newTestEngine returns an engine with the dolt functions registered, and a context whose session is working on the
+// branch checked out in the repository of |dEnv|.
+func newTestEngine(t *testing.T, dEnv *env.DoltEnv) (*sqle.Engine, *sql.Context) {
+	root, err := dEnv.WorkingRoot(context.Background())
+	require.NoError(t, err)
+
+	db := dsqle.NewDatabaseFromEnv("dolt", dEnv)
+	engine, sqlCtx, err := dsqle.NewTestEngine(context.Background(), db, root)
+	require.NoError(t, err)
+	require.NoError(t, engine.Catalog.Register(DoltFunctions...))
+
+	return engine, sqlCtx
+}
+
+// execQuery runs |query| and reads all of its rows, which is when the functions it calls are evaluated.  The iterator
+// is closed on errors as well, so that the session's process can run the next query.
+func execQuery(engine *sqle.Engine, sqlCtx *sql.Context, query string) error {
+	_, iter, err := engine.Query(sqlCtx, query)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = sql.RowIterToRows(iter)
+
+	if err != nil {
+		_ = iter.Close()
+	}
+
+	return err
+}
+
+func TestDoltFuncArgErrors(t *testing.T) {
+	tests := []struct {
+		query       string
+		expectedErr string
+	}{
+		{"select dolt_reset('--bogus')", "dolt_reset: error: unknown option `bogus'"},
+		{"select dolt_branch('-x', 'feature')", "dolt_branch: error: unknown option `x'"},
+		{"select dolt_checkout('-b')", "dolt_checkout: error: no value for option `b'"},
+		{"select dolt_branch(1)", "dolt_branch: arguments must be strings"},
+		{"select dolt_reset('--hard', '--soft')", "dolt_reset: --hard and --soft are mutually exclusive options"},
+		{"select dolt_reset('--hard', 't')", "dolt_reset: --hard does not support additional params"},
+		{"select dolt_checkout()", "dolt_checkout: expected a branch or table names"},
+		{"select dolt_branch('-d')", "dolt_branch: expected the names of the branches to delete"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			engine, sqlCtx := newTestEngine(t, dtestutils.CreateTestEnv())
+			err := execQuery(engine, sqlCtx, test.query)
+			require.Error(t, err)
+			assert.Equal(t, test.expectedErr, err.Error())
+		})
+	}
+}
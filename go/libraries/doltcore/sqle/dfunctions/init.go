@@ -14,10 +14,35 @@
 
 package dfunctions
 
-import "github.com/dolthub/go-mysql-server/sql"
+import (
+	"github.com/dolthub/go-mysql-server/sql"
 
-var DoltFunctions = []sql.Function{
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+)
+
+var DoltFunctions = append([]sql.Function{
 	sql.Function1{Name: HashOfFuncName, Fn: NewHashOf},
 	sql.Function1{Name: CommitFuncName, Fn: NewCommitFunc},
 	sql.Function1{Name: MergeFuncName, Fn: NewMergeFunc},
+	sql.FunctionN{Name: CheckoutFuncName, Fn: NewCheckoutFunc},
+	sql.FunctionN{Name: BranchFuncName, Fn: NewBranchFunc},
+	sql.FunctionN{Name: TagFuncName, Fn: NewTagFunc},
+	sql.FunctionN{Name: ResetFuncName, Fn: NewResetFunc},
+	sql.FunctionN{Name: AddFuncName, Fn: NewAddFunc},
+	sql.FunctionN{Name: FetchFuncName, Fn: NewFetchFunc},
+	sql.FunctionN{Name: PullFuncName, Fn: NewPullFunc},
+	sql.FunctionN{Name: PushFuncName, Fn: NewPushFunc},
+	sql.FunctionN{Name: sqle.MatchAgainstFuncName, Fn: NewMatchAgainst},
+}, SpatialFunctions...)
+
+// DoltFunctionOverrides are functions which replace the engine's own functions of the same name.
+var DoltFunctionOverrides = []sql.FunctionN{
+	{Name: JSONExtractFuncName, Fn: NewJSONExtract},
+}
+
+// RegisterOverrides replaces the engine's functions in the registry given with DoltFunctionOverrides.
+func RegisterOverrides(r sql.FunctionRegistry) {
+	for _, fn := range DoltFunctionOverrides {
+		r[fn.Name] = fn
+	}
 }
@@ -0,0 +1,294 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dfunctions
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+)
+
+const JSONExtractFuncName = "json_extract"
+
+// JSONExtract replaces the engine's JSON_EXTRACT, returning the values found as JSON text, which is how the values of
+// JSON columns are represented, rather than as decoded values. Paths which aren't found return NULL, as in MySQL.
+type JSONExtract struct {
+	JSON  sql.Expression
+	Paths []sql.Expression
+}
+
+var _ sql.FunctionExpression = (*JSONExtract)(nil)
+
+// NewJSONExtract creates a new JSONExtract expression.
+func NewJSONExtract(args ...sql.Expression) (sql.Expression, error) {
+	if len(args) < 2 {
+		return nil, sql.ErrInvalidArgumentNumber.New("JSON_EXTRACT", 2, len(args))
+	}
+
+	return &JSONExtract{args[0], args[1:]}, nil
+}
+
+// FunctionName implements sql.FunctionExpression
+func (j *JSONExtract) FunctionName() string {
+	return JSONExtractFuncName
+}
+
+// Resolved implements the Expression interface.
+func (j *JSONExtract) Resolved() bool {
+	for _, p := range j.Paths {
+		if !p.Resolved() {
+			return false
+		}
+	}
+	return j.JSON.Resolved()
+}
+
+// Type implements the Expression interface.
+func (j *JSONExtract) Type() sql.Type {
+	return typeinfo.JSONSqlType
+}
+
+// IsNullable implements the Expression interface.
+func (j *JSONExtract) IsNullable() bool {
+	return true
+}
+
+// Eval implements the Expression interface.
+func (j *JSONExtract) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	js, err := j.JSON.Eval(ctx, row)
+	if err != nil || js == nil {
+		return nil, err
+	}
+
+	doc, err := unmarshalJSONArg(js)
+	if err != nil {
+		return nil, err
+	}
+
+	var found []interface{}
+	wrap := len(j.Paths) > 1
+	for _, p := range j.Paths {
+		path, err := p.Eval(ctx, row)
+		if err != nil || path == nil {
+			return nil, err
+		}
+
+		path, err = sql.LongText.Convert(path)
+		if err != nil {
+			return nil, err
+		}
+
+		legs, err := parseJSONPath(path.(string))
+		if err != nil {
+			return nil, err
+		}
+
+		vals := []interface{}{doc}
+		for _, leg := range legs {
+			vals = leg.apply(vals)
+			wrap = wrap || leg.wildcard
+		}
+		found = append(found, vals...)
+	}
+
+	if len(found) == 0 {
+		return nil, nil
+	}
+
+	var res interface{} = found
+	if !wrap {
+		res = found[0]
+	}
+
+	text, err := json.Marshal(res)
+	if err != nil {
+		return nil, err
+	}
+	return typeinfo.JSONSqlType.Convert(text)
+}
+
+// Children implements the Expression interface.
+func (j *JSONExtract) Children() []sql.Expression {
+	return append([]sql.Expression{j.JSON}, j.Paths...)
+}
+
+// WithChildren implements the Expression interface.
+func (j *JSONExtract) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	return NewJSONExtract(children...)
+}
+
+// String implements the Stringer interface.
+func (j *JSONExtract) String() string {
+	children := j.Children()
+	var parts = make([]string, len(children))
+	for i, c := range children {
+		parts[i] = c.String()
+	}
+	return fmt.Sprintf("JSON_EXTRACT(%s)", strings.Join(parts, ", "))
+}
+
+func unmarshalJSONArg(js interface{}) (interface{}, error) {
+	var text []byte
+	switch js := js.(type) {
+	case []byte:
+		text = js
+	case string:
+		text = []byte(js)
+	default:
+		return js, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(text))
+	dec.UseNumber()
+	var doc interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("Invalid JSON text in argument 1 to function json_extract: %s", err.Error())
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("Invalid JSON text in argument 1 to function json_extract: unexpected data after the document")
+	}
+	return doc, nil
+}
+
+// jsonPathLeg is a single step of a JSON path, such as .key, [2] or .*
+type jsonPathLeg struct {
+	key      *string
+	index    int
+	wildcard bool
+}
+
+// apply returns the values reached by taking this step from each of the values given.
+func (leg jsonPathLeg) apply(vals []interface{}) []interface{} {
+	var res []interface{}
+	for _, val := range vals {
+		switch val := val.(type) {
+		case map[string]interface{}:
+			if leg.wildcard && leg.key != nil {
+				keys := make([]string, 0, len(val))
+				for k := range val {
+					keys = append(keys, k)
+				}
+				sort.Strings(keys)
+				for _, k := range keys {
+					res = append(res, val[k])
+				}
+			} else if leg.key != nil {
+				if v, ok := val[*leg.key]; ok {
+					res = append(res, v)
+				}
+			}
+		case []interface{}:
+			if leg.key != nil {
+				continue
+			}
+			if leg.wildcard {
+				res = append(res, val...)
+			} else if leg.index < len(val) {
+				res = append(res, val[leg.index])
+			}
+		default:
+			// a scalar is treated as an array holding only itself
+			if leg.key == nil && !leg.wildcard && leg.index == 0 {
+				res = append(res, val)
+			}
+		}
+	}
+	return res
+}
+
+// parseJSONPath parses a path in MySQL's JSON path syntax, without support for the ** wildcard.
+func parseJSONPath(path string) ([]jsonPathLeg, error) {
+	invalid := fmt.Errorf("Invalid JSON path expression: %s", path)
+
+	p := strings.TrimSpace(path)
+	if !strings.HasPrefix(p, "$") {
+		return nil, invalid
+	}
+	p = p[1:]
+
+	var legs []jsonPathLeg
+	for len(p) > 0 {
+		switch p[0] {
+		case '.':
+			p = strings.TrimLeft(p[1:], " ")
+			if strings.HasPrefix(p, "*") {
+				empty := ""
+				legs = append(legs, jsonPathLeg{key: &empty, wildcard: true})
+				p = p[1:]
+				continue
+			}
+
+			var key string
+			if strings.HasPrefix(p, `"`) {
+				end := 1
+				for end < len(p) && (p[end] != '"' || p[end-1] == '\\') {
+					end++
+				}
+				if end == len(p) {
+					return nil, invalid
+				}
+				var err error
+				key, err = strconv.Unquote(p[:end+1])
+				if err != nil {
+					return nil, invalid
+				}
+				p = p[end+1:]
+			} else {
+				end := strings.IndexAny(p, ".[ ")
+				if end == -1 {
+					end = len(p)
+				}
+				key = p[:end]
+				p = p[end:]
+			}
+
+			if key == "" {
+				return nil, invalid
+			}
+			legs = append(legs, jsonPathLeg{key: &key})
+		case '[':
+			end := strings.IndexByte(p, ']')
+			if end == -1 {
+				return nil, invalid
+			}
+			idx := strings.TrimSpace(p[1:end])
+			p = p[end+1:]
+
+			if idx == "*" {
+				legs = append(legs, jsonPathLeg{wildcard: true})
+				continue
+			}
+			n, err := strconv.Atoi(idx)
+			if err != nil || n < 0 {
+				return nil, invalid
+			}
+			legs = append(legs, jsonPathLeg{index: n})
+		case ' ':
+			p = p[1:]
+		default:
+			return nil, invalid
+		}
+	}
+
+	return legs, nil
+}
@@ -0,0 +1,279 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dfunctions
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/fulltext"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// MatchAgainst is MATCH (col1, col2, ...) AGAINST ('search'), which sqle.RewriteMatchAgainst rewrites as
+// MATCH_AGAINST(col1, col2, ..., 'search', 'modifier'). It returns the relevance of each row to the search, which is
+// zero for rows containing none of the search's words, and requires a FULLTEXT index over exactly the columns given.
+// Only natural language mode searches are supported.
+type MatchAgainst struct {
+	cols     []sql.Expression
+	search   sql.Expression
+	modifier sql.Expression
+
+	mu    *sync.Mutex
+	stats *matchStats
+}
+
+// matchStats are the numbers the relevance of every row is computed from, which are read from the table and its
+// FULLTEXT index the first time the function is evaluated.
+type matchStats struct {
+	tokens       []string
+	matchingRows map[string]uint64
+	totalRows    uint64
+}
+
+var _ sql.FunctionExpression = (*MatchAgainst)(nil)
+
+// NewMatchAgainst creates a new MatchAgainst expression.
+func NewMatchAgainst(args ...sql.Expression) (sql.Expression, error) {
+	if len(args) < 3 {
+		return nil, sql.ErrInvalidArgumentNumber.New(strings.ToUpper(sqle.MatchAgainstFuncName), "3 or more", len(args))
+	}
+
+	modifier := args[len(args)-1]
+	lit, ok := modifier.(*expression.Literal)
+	if !ok || lit.Value() != sqle.NaturalLanguageMode {
+		return nil, fmt.Errorf("MATCH ... AGAINST only supports searches %s, found %s", sqle.NaturalLanguageMode, modifier.String())
+	}
+
+	return &MatchAgainst{cols: args[:len(args)-2], search: args[len(args)-2], modifier: modifier, mu: &sync.Mutex{}}, nil
+}
+
+// FunctionName implements sql.FunctionExpression
+func (m *MatchAgainst) FunctionName() string {
+	return sqle.MatchAgainstFuncName
+}
+
+// Resolved implements the Expression interface.
+func (m *MatchAgainst) Resolved() bool {
+	for _, col := range m.cols {
+		if !col.Resolved() {
+			return false
+		}
+	}
+	return m.search.Resolved()
+}
+
+// String implements the Stringer interface.
+func (m *MatchAgainst) String() string {
+	cols := make([]string, len(m.cols))
+	for i, col := range m.cols {
+		cols[i] = col.String()
+	}
+	return fmt.Sprintf("MATCH (%s) AGAINST (%s)", strings.Join(cols, ", "), m.search.String())
+}
+
+// Type implements the Expression interface.
+func (m *MatchAgainst) Type() sql.Type {
+	return sql.Float64
+}
+
+// IsNullable implements the Expression interface.
+func (m *MatchAgainst) IsNullable() bool {
+	return false
+}
+
+// Eval implements the Expression interface.
+func (m *MatchAgainst) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	stats, err := m.getStats(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+	if len(stats.tokens) == 0 {
+		return float64(0), nil
+	}
+
+	var text strings.Builder
+	for _, col := range m.cols {
+		val, err := col.Eval(ctx, row)
+		if err != nil {
+			return nil, err
+		}
+		if val == nil {
+			continue
+		}
+		str, err := sql.LongText.Convert(val)
+		if err != nil {
+			return nil, err
+		}
+		text.WriteString(str.(string))
+		text.WriteRune(' ')
+	}
+
+	termFrequencies := fulltext.TermFrequencies(text.String())
+	relevance := 0.0
+	for _, token := range stats.tokens {
+		relevance += fulltext.Relevance(termFrequencies[token], stats.matchingRows[token], stats.totalRows)
+	}
+	return relevance, nil
+}
+
+// Children implements the Expression interface.
+func (m *MatchAgainst) Children() []sql.Expression {
+	return append(append([]sql.Expression{}, m.cols...), m.search, m.modifier)
+}
+
+// WithChildren implements the Expression interface.
+func (m *MatchAgainst) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	if len(children) != len(m.cols)+2 {
+		return nil, sql.ErrInvalidChildrenNumber.New(m, len(children), len(m.cols)+2)
+	}
+	return NewMatchAgainst(children...)
+}
+
+func (m *MatchAgainst) getStats(ctx *sql.Context, row sql.Row) (*matchStats, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.stats != nil {
+		return m.stats, nil
+	}
+
+	search, err := m.search.Eval(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+	stats := &matchStats{matchingRows: make(map[string]uint64)}
+	if search != nil {
+		str, err := sql.LongText.Convert(search)
+		if err != nil {
+			return nil, err
+		}
+		stats.tokens = fulltext.Tokenize(str.(string))
+	}
+
+	var tableName string
+	colNames := make([]string, len(m.cols))
+	for i, col := range m.cols {
+		gf, ok := col.(*expression.GetField)
+		if !ok {
+			return nil, fmt.Errorf("MATCH expects columns, found %s", col.String())
+		}
+		if i == 0 {
+			tableName = gf.Table()
+		} else if gf.Table() != tableName {
+			return nil, fmt.Errorf("MATCH columns must all belong to the same table")
+		}
+		colNames[i] = gf.Name()
+	}
+	dbName := ctx.GetCurrentDatabase()
+	root, ok := sqle.DSessFromSess(ctx.Session).GetRoot(dbName)
+	if !ok {
+		return nil, sql.ErrDatabaseNotFound.New(dbName)
+	}
+	tbl, _, ok, err := root.GetTableInsensitive(ctx, tableName)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, sql.ErrTableNotFound.New(tableName)
+	}
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	index, err := findFullTextIndex(sch, colNames)
+	if err != nil {
+		return nil, err
+	}
+
+	rowData, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	stats.totalRows = rowData.Len()
+	indexData, err := tbl.GetIndexRowData(ctx, index.Name())
+	if err != nil {
+		return nil, err
+	}
+	for _, token := range stats.tokens {
+		stats.matchingRows[token], err = countTokenRows(ctx, indexData, token)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	m.stats = stats
+	return stats, nil
+}
+
+// findFullTextIndex returns the FULLTEXT index over exactly the given columns, in any order.
+func findFullTextIndex(sch schema.Schema, colNames []string) (schema.Index, error) {
+	for i := range colNames {
+		colNames[i] = strings.ToLower(colNames[i])
+	}
+	sort.Strings(colNames)
+
+	for _, index := range sch.Indexes().AllIndexes() {
+		if !index.IsFullText() || index.Count() != len(colNames) {
+			continue
+		}
+		indexColNames := index.ColumnNames()
+		for i := range indexColNames {
+			indexColNames[i] = strings.ToLower(indexColNames[i])
+		}
+		sort.Strings(indexColNames)
+		matches := true
+		for i := range colNames {
+			if colNames[i] != indexColNames[i] {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return index, nil
+		}
+	}
+	return nil, fmt.Errorf("can't find FULLTEXT index matching the column list")
+}
+
+// countTokenRows returns the number of rows containing the given token, which each have an entry in the index map
+// starting with the token.
+func countTokenRows(ctx *sql.Context, indexData types.Map, token string) (uint64, error) {
+	prefix, err := types.NewTuple(indexData.Format(), types.Uint(schema.FullTextTokenTag), types.String(token))
+	if err != nil {
+		return 0, err
+	}
+	iter, err := indexData.IteratorFrom(ctx, prefix)
+	if err != nil {
+		return 0, err
+	}
+	var count uint64
+	for {
+		key, _, err := iter.Next(ctx)
+		if err != nil {
+			return 0, err
+		}
+		if key == nil || !key.(types.Tuple).StartsWith(prefix) {
+			return count, nil
+		}
+		count++
+	}
+}
@@ -0,0 +1,517 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dfunctions
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/merge"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+	"github.com/dolthub/dolt/go/store/datas"
+)
+
+const (
+	FetchFuncName = "dolt_fetch"
+	PullFuncName  = "dolt_pull"
+	PushFuncName  = "dolt_push"
+)
+
+const (
+	remoteForceFlag       = "force"
+	remoteSetUpstreamFlag = "set-upstream"
+	remoteResumeFlag      = "resume"
+	defaultRemoteName     = "origin"
+)
+
+var ErrPullConflicts = errors.New("merging the fetched changes resulted in conflicts; the pull was aborted")
+
+// remoteEnv returns the environment of the session's current database, which is needed to use its remotes.
+func remoteEnv(ctx *sql.Context, funcName string) (*sqle.DoltSession, string, *env.DoltEnv, error) {
+	dSess := sqle.DSessFromSess(ctx.Session)
+	dbName := ctx.GetCurrentDatabase()
+
+	dEnv, ok := dSess.GetDoltEnv(dbName)
+
+	if !ok {
+		return nil, "", nil, fmt.Errorf("%s: remotes are not available for database '%s'", funcName, dbName)
+	}
+
+	return dSess, dbName, dEnv, nil
+}
+
+// resumableContext returns a context which lets the pulls of chunks run with it resume from the progress saved by an
+// interrupted pull, if the resume flag is in |apr|.  Otherwise the progress of an interrupted pull is discarded.
+func resumableContext(ctx *sql.Context, apr *argparser.ArgParseResults) *sql.Context {
+	if apr.Contains(remoteResumeFlag) {
+		return ctx.WithContext(datas.WithPullResume(ctx))
+	}
+
+	return ctx
+}
+
+// getRemote returns the remote named |remoteName| and its database.
+func getRemote(ctx *sql.Context, dEnv *env.DoltEnv, remoteName string) (env.Remote, *doltdb.DoltDB, error) {
+	remotes, err := dEnv.GetRemotes()
+
+	if err != nil {
+		return env.NoRemote, nil, err
+	}
+
+	remote, ok := remotes[remoteName]
+
+	if !ok {
+		return env.NoRemote, nil, fmt.Errorf("unknown remote '%s'", remoteName)
+	}
+
+	remoteDB, err := remote.GetRemoteDB(ctx, dEnv.DoltDB.ValueReadWriter().Format())
+
+	if err != nil {
+		return env.NoRemote, nil, err
+	}
+
+	return remote, remoteDB, nil
+}
+
+// FetchFunc fetches branches and tags from a remote, like dolt fetch.
+type FetchFunc struct {
+	doltFunc
+}
+
+// NewFetchFunc creates a new FetchFunc expression.
+func NewFetchFunc(args ...sql.Expression) (sql.Expression, error) {
+	return &FetchFunc{doltFunc{FetchFuncName, args}}, nil
+}
+
+func fetchArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParser()
+	ap.SupportsFlag(remoteForceFlag, "f", "Update refs to remote branches with the current state of the remote, overwriting any conflicting history.")
+	ap.SupportsFlag(remoteResumeFlag, "", "Resume an interrupted fetch from its last saved progress.")
+	return ap
+}
+
+// Eval implements the Expression interface.
+func (ff *FetchFunc) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	apr, err := ff.parseArgs(ctx, row, fetchArgParser())
+
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = resumableContext(ctx, apr)
+	_, _, dEnv, err := remoteEnv(ctx, FetchFuncName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	remoteName := defaultRemoteName
+	args := apr.Args()
+
+	if len(args) > 0 {
+		remoteName = args[0]
+		args = args[1:]
+	}
+
+	remote, srcDB, err := getRemote(ctx, dEnv, remoteName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var refSpecs []ref.RemoteRefSpec
+	if len(args) > 0 {
+		refSpecs, err = actions.ParseRemoteRefSpecs(remoteName, args)
+
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		var verr errhand.VerboseError
+		refSpecs, verr = dEnv.GetRefSpecs(remoteName)
+
+		if verr != nil {
+			return nil, verr
+		}
+	}
+
+	mode := ref.RefUpdateMode{Force: apr.Contains(remoteForceFlag)}
+
+	progChan, pullerEventCh, done := actions.DiscardProgress()
+	err = actions.FetchRefSpecs(ctx, dEnv, mode, remote, srcDB, refSpecs, progChan, pullerEventCh)
+	done()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return 0, nil
+}
+
+// WithChildren implements the Expression interface.
+func (ff *FetchFunc) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	return NewFetchFunc(children...)
+}
+
+// PullFunc fetches the session's branch from a remote and merges it into the branch, like dolt pull.  The session must
+// not have uncommitted changes, and a merge which results in conflicts is aborted.
+type PullFunc struct {
+	doltFunc
+}
+
+// NewPullFunc creates a new PullFunc expression.
+func NewPullFunc(args ...sql.Expression) (sql.Expression, error) {
+	return &PullFunc{doltFunc{PullFuncName, args}}, nil
+}
+
+func pullArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParser()
+	ap.SupportsFlag(remoteResumeFlag, "", "Resume an interrupted pull from its last saved progress.")
+	return ap
+}
+
+// Eval implements the Expression interface.
+func (pf *PullFunc) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	apr, err := pf.parseArgs(ctx, row, pullArgParser())
+
+	if err != nil {
+		return nil, err
+	} else if apr.NArg() > 1 {
+		return nil, fmt.Errorf("%s takes at most one arg", PullFuncName)
+	}
+
+	ctx = resumableContext(ctx, apr)
+	dSess, dbName, dEnv, err := remoteEnv(ctx, PullFuncName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	branch, ok := dSess.GetHeadRef(dbName)
+
+	if !ok {
+		return nil, ErrDetachedHead
+	}
+
+	err = checkNoUncommittedChanges(ctx, dSess, dbName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var remoteName string
+	if apr.NArg() == 1 {
+		remoteName = apr.Arg(0)
+	}
+
+	refSpecs, verr := dEnv.GetRefSpecs(remoteName)
+
+	if verr != nil {
+		return nil, verr
+	} else if len(refSpecs) == 0 {
+		return nil, errors.New("no refspec for remote")
+	}
+
+	remote, srcDB, err := getRemote(ctx, dEnv, refSpecs[0].GetRemote())
+
+	if err != nil {
+		return nil, err
+	}
+
+	progChan, pullerEventCh, done := actions.DiscardProgress()
+	defer done()
+
+	for _, refSpec := range refSpecs {
+		remoteTrackRef := refSpec.DestRef(branch)
+
+		if remoteTrackRef == nil {
+			continue
+		}
+
+		srcDBCommit, err := actions.FetchRemoteBranch(ctx, dEnv, remote, srcDB, dEnv.DoltDB, branch, progChan, pullerEventCh)
+
+		if err != nil {
+			return nil, err
+		}
+
+		err = dEnv.DoltDB.FastForward(ctx, remoteTrackRef, srcDBCommit)
+
+		if err != nil {
+			return nil, err
+		}
+
+		err = mergeIntoBranch(ctx, dSess, dbName, branch, srcDBCommit)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = actions.FetchFollowTags(ctx, dEnv, srcDB, dEnv.DoltDB, progChan, pullerEventCh)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return 0, nil
+}
+
+// mergeIntoBranch merges the commit |cm| into |branch|, which is the session's branch, and updates the session's
+// working root.  If the merge isn't a fast forward, a merge commit is created.
+func mergeIntoBranch(ctx *sql.Context, dSess *sqle.DoltSession, dbName string, branch ref.DoltRef, cm *doltdb.Commit) error {
+	ddb, _ := dSess.GetDoltDB(dbName)
+	head, err := ddb.ResolveRef(ctx, branch)
+
+	if err != nil {
+		return err
+	}
+
+	canFF, err := head.CanFastForwardTo(ctx, cm)
+
+	if err == doltdb.ErrUpToDate || err == doltdb.ErrIsAhead {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if !canFF {
+		cm, err = writeMergeCommit(ctx, dSess, ddb, head, cm)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	err = ddb.FastForward(ctx, branch, cm)
+
+	if err != nil {
+		return err
+	}
+
+	root, err := cm.GetRootValue()
+
+	if err != nil {
+		return err
+	}
+
+	if dSess.UsesRepoWorkingSet(dbName) {
+		err = dSess.UpdateRoots(ctx, dbName, root, root)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	// moves the session's head to the new head of the branch
+	return dSess.Set(ctx, dbName+sqle.HeadRefKeySuffix, sql.Text, branch.String())
+}
+
+// writeMergeCommit merges |theirs| into |ours| and commits the result, which must not have conflicts.
+func writeMergeCommit(ctx *sql.Context, dSess *sqle.DoltSession, ddb *doltdb.DoltDB, ours, theirs *doltdb.Commit) (*doltdb.Commit, error) {
+	if dSess.Username == "" || dSess.Email == "" {
+		return nil, errors.New("pull function failure: Username and/or email not configured")
+	}
+
+	mergedRoot, stats, err := merge.MergeCommits(ctx, ours, theirs)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, stat := range stats {
+		if stat.Conflicts > 0 {
+			return nil, ErrPullConflicts
+		}
+	}
+
+	h, err := ddb.WriteRootValue(ctx, mergedRoot)
+
+	if err != nil {
+		return nil, err
+	}
+
+	oursHash, err := ours.HashOf()
+
+	if err != nil {
+		return nil, err
+	}
+
+	theirsHash, err := theirs.HashOf()
+
+	if err != nil {
+		return nil, err
+	}
+
+	commitMessage := fmt.Sprintf("SQL Generated commit merging %s into %s", theirsHash.String(), oursHash.String())
+	meta, err := doltdb.NewCommitMeta(dSess.Username, dSess.Email, commitMessage)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ddb.WriteDanglingCommit(ctx, h, []*doltdb.Commit{ours, theirs}, meta)
+}
+
+// WithChildren implements the Expression interface.
+func (pf *PullFunc) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	return NewPullFunc(children...)
+}
+
+// PushFunc pushes a branch or a tag to a remote, like dolt push.  By default the session's branch is pushed to the
+// branch of the same name on the remote.
+type PushFunc struct {
+	doltFunc
+}
+
+// NewPushFunc creates a new PushFunc expression.
+func NewPushFunc(args ...sql.Expression) (sql.Expression, error) {
+	return &PushFunc{doltFunc{PushFuncName, args}}, nil
+}
+
+func pushArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParser()
+	ap.SupportsFlag(remoteSetUpstreamFlag, "u", "For every branch that is up to date or successfully pushed, add upstream (tracking) reference.")
+	ap.SupportsFlag(remoteForceFlag, "f", "Update the remote with local history, overwriting any conflicting history in the remote.")
+	ap.SupportsFlag(remoteResumeFlag, "", "Resume an interrupted push from its last saved progress.")
+	return ap
+}
+
+// Eval implements the Expression interface.
+func (pf *PushFunc) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	apr, err := pf.parseArgs(ctx, row, pushArgParser())
+
+	if err != nil {
+		return nil, err
+	} else if apr.NArg() > 2 {
+		return nil, fmt.Errorf("%s: expected a remote and an optional refspec", PushFuncName)
+	}
+
+	ctx = resumableContext(ctx, apr)
+	dSess, dbName, dEnv, err := remoteEnv(ctx, PushFuncName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	remoteName := defaultRemoteName
+	if apr.NArg() > 0 {
+		remoteName = apr.Arg(0)
+	}
+
+	branch, hasBranch := dSess.GetHeadRef(dbName)
+	refSpecStr := ""
+
+	if apr.NArg() == 2 {
+		refSpecStr, err = actions.DisambiguateRefSpecStr(ctx, dEnv.DoltDB, apr.Arg(1))
+
+		if err != nil {
+			return nil, err
+		}
+	} else if hasBranch {
+		refSpecStr = branch.String()
+	} else {
+		return nil, ErrDetachedHead
+	}
+
+	refSpec, err := ref.ParseRefSpec(refSpecStr)
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid refspec '%s': %w", refSpecStr, err)
+	}
+
+	remote, destDB, err := getRemote(ctx, dEnv, remoteName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	src := refSpec.SrcRef(branch)
+	dest := refSpec.DestRef(src)
+
+	progChan, pullerEventCh, done := actions.DiscardProgress()
+	defer done()
+
+	switch src.GetType() {
+	case ref.BranchRefType:
+		remoteRef, err := actions.GetTrackingRef(dest, remote)
+
+		if err != nil {
+			return nil, err
+		} else if remoteRef == nil {
+			return nil, fmt.Errorf("remote '%s' has no fetch spec for '%s'", remote.Name, dest.String())
+		}
+
+		cm, err := dEnv.DoltDB.ResolveRef(ctx, src)
+
+		if err != nil {
+			return nil, fmt.Errorf("refspec '%s' not found", src.GetPath())
+		}
+
+		mode := ref.RefUpdateMode{Force: apr.Contains(remoteForceFlag)}
+		err = actions.Push(ctx, dEnv, mode, dest.(ref.BranchRef), remoteRef.(ref.RemoteRef), dEnv.DoltDB, destDB, cm, progChan, pullerEventCh)
+
+		if err != nil && err != doltdb.ErrUpToDate {
+			return nil, err
+		}
+
+	case ref.TagRefType:
+		if apr.Contains(remoteSetUpstreamFlag) {
+			return nil, errors.New("cannot set upstream for tag")
+		}
+
+		tg, err := dEnv.DoltDB.ResolveTag(ctx, src.(ref.TagRef))
+
+		if err != nil {
+			return nil, err
+		}
+
+		err = actions.PushTag(ctx, dEnv, dest.(ref.TagRef), dEnv.DoltDB, destDB, tg, progChan, pullerEventCh)
+
+		if err != nil && err != doltdb.ErrUpToDate {
+			return nil, err
+		}
+
+	default:
+		return nil, fmt.Errorf("cannot push ref %s of type %s", src.String(), src.GetType())
+	}
+
+	if apr.Contains(remoteSetUpstreamFlag) {
+		dEnv.RepoState.Branches[src.GetPath()] = env.BranchConfig{
+			Merge:  ref.MarshalableRef{Ref: dest},
+			Remote: remote.Name,
+		}
+
+		err = dEnv.RepoState.Save(dEnv.FS)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return 0, nil
+}
+
+// WithChildren implements the Expression interface.
+func (pf *PushFunc) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	return NewPushFunc(children...)
+}
@@ -0,0 +1,121 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dfunctions
+
+import (
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+)
+
+const ResetFuncName = "dolt_reset"
+
+const (
+	resetHardFlag = "hard"
+	resetSoftFlag = "soft"
+)
+
+// ResetFunc resets the working root or the staged root of the session's current database, like dolt reset.
+type ResetFunc struct {
+	doltFunc
+}
+
+// NewResetFunc creates a new ResetFunc expression.
+func NewResetFunc(args ...sql.Expression) (sql.Expression, error) {
+	return &ResetFunc{doltFunc{ResetFuncName, args}}, nil
+}
+
+func resetArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParser()
+	ap.SupportsFlag(resetHardFlag, "", "Resets the working tables and staged tables. Any changes to tracked tables in the working tree since <commit> are discarded.")
+	ap.SupportsFlag(resetSoftFlag, "", "Does not touch the working tables, but removes all tables staged to be committed.")
+	return ap
+}
+
+// Eval implements the Expression interface.
+func (rf *ResetFunc) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	apr, err := rf.parseArgs(ctx, row, resetArgParser())
+
+	if err != nil {
+		return nil, err
+	}
+
+	dSess, dbName, _, err := currentDatabase(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	working, staged, head, err := dSess.GetRoots(ctx, dbName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if apr.ContainsAll(resetHardFlag, resetSoftFlag) {
+		return nil, fmt.Errorf("%s: --%s and --%s are mutually exclusive options", ResetFuncName, resetHardFlag, resetSoftFlag)
+	}
+
+	if apr.Contains(resetHardFlag) {
+		if apr.NArg() != 0 {
+			return nil, fmt.Errorf("%s: --%s does not support additional params", ResetFuncName, resetHardFlag)
+		}
+
+		working, err = actions.ResetHardTables(ctx, working, staged, head)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !dSess.UsesRepoWorkingSet(dbName) {
+			return 0, dSess.UpdateRoots(ctx, dbName, working, nil)
+		}
+
+		return 0, dSess.UpdateRoots(ctx, dbName, working, head)
+	}
+
+	tbls := apr.Args()
+
+	if len(tbls) == 0 || (len(tbls) == 1 && tbls[0] == ".") {
+		tbls, err = doltdb.UnionTableNames(ctx, staged, head)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = actions.ValidateTables(ctx, tbls, staged, head)
+
+	if err != nil {
+		return nil, err
+	}
+
+	staged, err = actions.MoveTablesBetweenRoots(ctx, tbls, head, staged)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return 0, dSess.UpdateRoots(ctx, dbName, nil, staged)
+}
+
+// WithChildren implements the Expression interface.
+func (rf *ResetFunc) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	return NewResetFunc(children...)
+}
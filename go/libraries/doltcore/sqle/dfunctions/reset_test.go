@@ -0,0 +1,70 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dfunctions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+)
+
+func TestReset(t *testing.T) {
+	tableNames := func(t *testing.T, root *doltdb.RootValue) []string {
+		names, err := root.GetTableNames(context.Background())
+		require.NoError(t, err)
+		return names
+	}
+
+	tests := []struct {
+		name            string
+		query           string
+		expectedWorking []string
+	}{
+		// the staged table is reset in the working root too, the table which was never staged is kept
+		{"hard", "select dolt_reset('--hard')", []string{"untracked"}},
+		// only the staged root is reset
+		{"soft", "select dolt_reset('--soft')", []string{"t", "untracked"}},
+		{"default", "select dolt_reset()", []string{"t", "untracked"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			engine, sqlCtx := newTestEngine(t, dtestutils.CreateTestEnv())
+			dSess := sqle.DSessFromSess(sqlCtx.Session)
+
+			require.NoError(t, execQuery(engine, sqlCtx, "create table t (pk int primary key)"))
+			require.NoError(t, execQuery(engine, sqlCtx, "select dolt_add('t')"))
+			require.NoError(t, execQuery(engine, sqlCtx, "create table untracked (pk int primary key)"))
+
+			_, staged, _, err := dSess.GetRoots(sqlCtx, "dolt")
+			require.NoError(t, err)
+			assert.Equal(t, []string{"t"}, tableNames(t, staged))
+
+			require.NoError(t, execQuery(engine, sqlCtx, test.query))
+
+			working, staged, head, err := dSess.GetRoots(sqlCtx, "dolt")
+			require.NoError(t, err)
+			assert.ElementsMatch(t, test.expectedWorking, tableNames(t, working))
+			assert.Empty(t, tableNames(t, staged))
+			assert.Empty(t, tableNames(t, head))
+		})
+	}
+}
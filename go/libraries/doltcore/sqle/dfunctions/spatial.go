@@ -0,0 +1,341 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dfunctions
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/geometry"
+)
+
+// spatialFuncDef describes one of the spatial functions, which take geometries in MySQL's internal format or as WKT,
+// as well as the values of geometry columns, and return NULL when any of their arguments are NULL. Like MySQL,
+// functions returning geometries return them in MySQL's internal format.
+type spatialFuncDef struct {
+	name    string
+	minArgs int
+	maxArgs int
+	typ     sql.Type
+	eval    func(args []interface{}) (interface{}, error)
+}
+
+var spatialFuncDefs = []spatialFuncDef{
+	{"st_geomfromtext", 1, 1, sql.LongBlob, geomFromText("")},
+	{"st_geometryfromtext", 1, 1, sql.LongBlob, geomFromText("")},
+	{"st_pointfromtext", 1, 1, sql.LongBlob, geomFromText(geometry.PointType)},
+	{"st_linefromtext", 1, 1, sql.LongBlob, geomFromText(geometry.LineStringType)},
+	{"st_linestringfromtext", 1, 1, sql.LongBlob, geomFromText(geometry.LineStringType)},
+	{"st_polyfromtext", 1, 1, sql.LongBlob, geomFromText(geometry.PolygonType)},
+	{"st_polygonfromtext", 1, 1, sql.LongBlob, geomFromText(geometry.PolygonType)},
+	{"st_geomfromwkb", 1, 1, sql.LongBlob, geomFromWKB},
+	{"st_geometryfromwkb", 1, 1, sql.LongBlob, geomFromWKB},
+	{"st_astext", 1, 1, sql.LongText, asText},
+	{"st_aswkt", 1, 1, sql.LongText, asText},
+	{"st_asbinary", 1, 1, sql.LongBlob, asBinary},
+	{"st_aswkb", 1, 1, sql.LongBlob, asBinary},
+	{"point", 2, 2, sql.LongBlob, newPoint},
+	{"st_x", 1, 1, sql.Float64, pointCoord(func(p geometry.Point) float64 { return p.X })},
+	{"st_y", 1, 1, sql.Float64, pointCoord(func(p geometry.Point) float64 { return p.Y })},
+	{"st_distance", 2, 2, sql.Float64, distance},
+	{"st_contains", 2, 2, sql.Boolean, relation(geometry.Contains)},
+	{"st_within", 2, 2, sql.Boolean, relation(geometry.Within)},
+	{"st_intersects", 2, 2, sql.Boolean, relation(geometry.Intersects)},
+	{"st_geohash", 2, 3, sql.LongText, geohash},
+	{"st_pointfromgeohash", 1, 2, sql.LongBlob, pointFromGeohash},
+}
+
+// SpatialFunctions are the functions for creating geometries and relating them to one another.
+var SpatialFunctions = func() []sql.Function {
+	fns := make([]sql.Function, len(spatialFuncDefs))
+	for i := range spatialFuncDefs {
+		def := &spatialFuncDefs[i]
+		fns[i] = sql.FunctionN{Name: def.name, Fn: func(args ...sql.Expression) (sql.Expression, error) {
+			if len(args) < def.minArgs || len(args) > def.maxArgs {
+				expected := fmt.Sprint(def.minArgs)
+				if def.maxArgs != def.minArgs {
+					expected = fmt.Sprintf("%d or %d", def.minArgs, def.maxArgs)
+				}
+				return nil, sql.ErrInvalidArgumentNumber.New(strings.ToUpper(def.name), expected, len(args))
+			}
+			return &SpatialFunc{def, args}, nil
+		}}
+	}
+	return fns
+}()
+
+// SpatialFunc is an expression calling one of the spatial functions.
+type SpatialFunc struct {
+	def  *spatialFuncDef
+	args []sql.Expression
+}
+
+var _ sql.FunctionExpression = (*SpatialFunc)(nil)
+
+// FunctionName implements sql.FunctionExpression
+func (f *SpatialFunc) FunctionName() string {
+	return f.def.name
+}
+
+// Resolved implements the Expression interface.
+func (f *SpatialFunc) Resolved() bool {
+	for _, arg := range f.args {
+		if !arg.Resolved() {
+			return false
+		}
+	}
+	return true
+}
+
+// String implements the Stringer interface.
+func (f *SpatialFunc) String() string {
+	args := make([]string, len(f.args))
+	for i, arg := range f.args {
+		args[i] = arg.String()
+	}
+	return fmt.Sprintf("%s(%s)", strings.ToUpper(f.def.name), strings.Join(args, ", "))
+}
+
+// Type implements the Expression interface.
+func (f *SpatialFunc) Type() sql.Type {
+	return f.def.typ
+}
+
+// IsNullable implements the Expression interface.
+func (f *SpatialFunc) IsNullable() bool {
+	return true
+}
+
+// Eval implements the Expression interface.
+func (f *SpatialFunc) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	vals := make([]interface{}, len(f.args))
+	for i, arg := range f.args {
+		val, err := arg.Eval(ctx, row)
+		if err != nil {
+			return nil, err
+		}
+		if val == nil {
+			return nil, nil
+		}
+		vals[i] = val
+	}
+
+	res, err := f.def.eval(vals)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", strings.ToUpper(f.def.name), err)
+	}
+	return res, nil
+}
+
+// Children implements the Expression interface.
+func (f *SpatialFunc) Children() []sql.Expression {
+	return f.args
+}
+
+// WithChildren implements the Expression interface.
+func (f *SpatialFunc) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	if len(children) != len(f.args) {
+		return nil, sql.ErrInvalidChildrenNumber.New(f, len(children), len(f.args))
+	}
+	return &SpatialFunc{f.def, children}, nil
+}
+
+// toGeometry converts a geometry given in MySQL's internal format or as WKT, or read from a geometry column. When
+// subtype isn't empty, the geometry must be of that subtype.
+func toGeometry(v interface{}, subtype string) (geometry.Geometry, error) {
+	var g geometry.Geometry
+	var err error
+	switch val := v.(type) {
+	case geometry.Geometry:
+		g = val
+	case []byte:
+		g, err = geometry.UnmarshalMySQL(val)
+	case string:
+		g, err = geometry.UnmarshalMySQL([]byte(val))
+		if err != nil {
+			g, err = geometry.ParseWKT(val)
+		}
+	default:
+		return nil, fmt.Errorf(`cannot convert value "%v" of type "%T" to a geometry`, v, v)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSubtype(g, subtype); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+func checkSubtype(g geometry.Geometry, subtype string) error {
+	if subtype != "" && g.GeometryType() != subtype {
+		return fmt.Errorf("cannot convert %v to %v", g.GeometryType(), subtype)
+	}
+	return nil
+}
+
+func fromGeometry(g geometry.Geometry) interface{} {
+	return string(geometry.MarshalMySQL(g))
+}
+
+func toFloat(v interface{}) (float64, error) {
+	f, err := sql.Float64.Convert(v)
+	if err != nil {
+		return 0, err
+	}
+	return f.(float64), nil
+}
+
+func geomFromText(subtype string) func(args []interface{}) (interface{}, error) {
+	return func(args []interface{}) (interface{}, error) {
+		wkt, err := sql.LongText.Convert(args[0])
+		if err != nil {
+			return nil, err
+		}
+		g, err := geometry.ParseWKT(wkt.(string))
+		if err != nil {
+			return nil, err
+		}
+		if err := checkSubtype(g, subtype); err != nil {
+			return nil, err
+		}
+		return fromGeometry(g), nil
+	}
+}
+
+func geomFromWKB(args []interface{}) (interface{}, error) {
+	wkb, err := sql.LongBlob.Convert(args[0])
+	if err != nil {
+		return nil, err
+	}
+	g, err := geometry.UnmarshalWKB([]byte(wkb.(string)))
+	if err != nil {
+		return nil, err
+	}
+	return fromGeometry(g), nil
+}
+
+func asText(args []interface{}) (interface{}, error) {
+	g, err := toGeometry(args[0], "")
+	if err != nil {
+		return nil, err
+	}
+	return g.String(), nil
+}
+
+func asBinary(args []interface{}) (interface{}, error) {
+	g, err := toGeometry(args[0], "")
+	if err != nil {
+		return nil, err
+	}
+	return string(geometry.MarshalWKB(g)), nil
+}
+
+func newPoint(args []interface{}) (interface{}, error) {
+	x, err := toFloat(args[0])
+	if err != nil {
+		return nil, err
+	}
+	y, err := toFloat(args[1])
+	if err != nil {
+		return nil, err
+	}
+	return fromGeometry(geometry.Point{X: x, Y: y}), nil
+}
+
+func pointCoord(coord func(geometry.Point) float64) func(args []interface{}) (interface{}, error) {
+	return func(args []interface{}) (interface{}, error) {
+		p, err := toGeometry(args[0], geometry.PointType)
+		if err != nil {
+			return nil, err
+		}
+		return coord(p.(geometry.Point)), nil
+	}
+}
+
+func distance(args []interface{}) (interface{}, error) {
+	g1, err := toGeometry(args[0], "")
+	if err != nil {
+		return nil, err
+	}
+	g2, err := toGeometry(args[1], "")
+	if err != nil {
+		return nil, err
+	}
+	return geometry.Distance(g1, g2), nil
+}
+
+func relation(rel func(g1, g2 geometry.Geometry) bool) func(args []interface{}) (interface{}, error) {
+	return func(args []interface{}) (interface{}, error) {
+		g1, err := toGeometry(args[0], "")
+		if err != nil {
+			return nil, err
+		}
+		g2, err := toGeometry(args[1], "")
+		if err != nil {
+			return nil, err
+		}
+		return rel(g1, g2), nil
+	}
+}
+
+// geohash takes either a point and a length, or a longitude, latitude and length.
+func geohash(args []interface{}) (interface{}, error) {
+	var p geometry.Point
+	if len(args) == 2 {
+		g, err := toGeometry(args[0], geometry.PointType)
+		if err != nil {
+			return nil, err
+		}
+		p = g.(geometry.Point)
+	} else {
+		var err error
+		if p.X, err = toFloat(args[0]); err != nil {
+			return nil, err
+		}
+		if p.Y, err = toFloat(args[1]); err != nil {
+			return nil, err
+		}
+	}
+	length, err := sql.Int64.Convert(args[len(args)-1])
+	if err != nil {
+		return nil, err
+	}
+	return geometry.Geohash(p, int(length.(int64)))
+}
+
+// pointFromGeohash returns the center of the area described by a geohash. Only SRID 0 may be given.
+func pointFromGeohash(args []interface{}) (interface{}, error) {
+	if len(args) == 2 {
+		srid, err := sql.Int64.Convert(args[1])
+		if err != nil {
+			return nil, err
+		}
+		if srid.(int64) != 0 {
+			return nil, fmt.Errorf("SRID %d is not supported", srid)
+		}
+	}
+	gh, err := sql.LongText.Convert(args[0])
+	if err != nil {
+		return nil, err
+	}
+	r, err := geometry.GeohashBounds(gh.(string))
+	if err != nil {
+		return nil, err
+	}
+	return fromGeometry(geometry.Point{X: (r.MinX + r.MaxX) / 2, Y: (r.MinY + r.MaxY) / 2}), nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dfunctions
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+)
+
+const TagFuncName = "dolt_tag"
+
+const (
+	tagMessageArg = "message"
+	tagDeleteFlag = "delete"
+)
+
+// TagFunc creates and deletes tags, like dolt tag.
+type TagFunc struct {
+	doltFunc
+}
+
+// NewTagFunc creates a new TagFunc expression.
+func NewTagFunc(args ...sql.Expression) (sql.Expression, error) {
+	return &TagFunc{doltFunc{TagFuncName, args}}, nil
+}
+
+func tagArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParser()
+	ap.SupportsString(tagMessageArg, "m", "msg", "Use the given <msg> as the tag message.")
+	ap.SupportsFlag(tagDeleteFlag, "d", "Delete a tag.")
+	return ap
+}
+
+// Eval implements the Expression interface.
+func (tf *TagFunc) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	apr, err := tf.parseArgs(ctx, row, tagArgParser())
+
+	if err != nil {
+		return nil, err
+	}
+
+	dSess, dbName, ddb, err := currentDatabase(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if apr.Contains(tagDeleteFlag) {
+		if apr.NArg() == 0 {
+			return nil, fmt.Errorf("%s: expected the names of the tags to delete", TagFuncName)
+		}
+
+		return 0, actions.DeleteTagsOnDB(ctx, ddb, apr.Args()...)
+	}
+
+	if apr.NArg() == 0 || apr.NArg() > 2 {
+		return nil, fmt.Errorf("%s: expected a tag name and an optional start point", TagFuncName)
+	}
+
+	if dSess.Username == "" || dSess.Email == "" {
+		return nil, errors.New("tag function failure: Username and/or email not configured")
+	}
+
+	startPt := "head"
+	if apr.NArg() == 2 {
+		startPt = apr.Arg(1)
+	}
+
+	startPt, headRef, err := resolveStartPoint(ctx, dSess, dbName, startPt)
+
+	if err != nil {
+		return nil, err
+	}
+
+	props := actions.TagProps{
+		TaggerName:  dSess.Username,
+		TaggerEmail: dSess.Email,
+		Description: apr.GetValueOrDefault(tagMessageArg, ""),
+	}
+
+	return 0, actions.CreateTagOnDB(ctx, ddb, apr.Arg(0), startPt, props, headRef)
+}
+
+// WithChildren implements the Expression interface.
+func (tf *TagFunc) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	return NewTagFunc(children...)
+}
@@ -215,13 +215,13 @@ func (dt *DiffTable) PartitionRows(ctx *sql.Context, part sql.Partition) (sql.Ro
 		return nil, err
 	}
 
-	fromConv, err := rowConvForSchema(dt.ss, fromSch)
+	fromConv, err := rowConvForSchema(ctx, dt.ddb.ValueReadWriter(), dt.ss, fromSch)
 
 	if err != nil {
 		return nil, err
 	}
 
-	toConv, err := rowConvForSchema(dt.ss, toSch)
+	toConv, err := rowConvForSchema(ctx, dt.ddb.ValueReadWriter(), dt.ss, toSch)
 
 	if err != nil {
 		return nil, err
@@ -278,6 +278,7 @@ func tableData(ctx *sql.Context, tbl *doltdb.Table, ddb *doltdb.DoltDB) (types.M
 var _ sql.RowIter = (*diffRowItr)(nil)
 
 type diffRowItr struct {
+	ctx            context.Context
 	ad             *diff.AsyncDiffer
 	diffSrc        *diff.RowDiffSource
 	joiner         *rowconv.Joiner
@@ -300,7 +301,7 @@ func newDiffRowItr(ctx context.Context, joiner *rowconv.Joiner, rowDataFrom, row
 	src := diff.NewRowDiffSource(ad, joiner)
 	src.AddInputRowConversion(convFrom, convTo)
 
-	return &diffRowItr{ad, src, joiner, joiner.GetSchema(), from, to}
+	return &diffRowItr{ctx, ad, src, joiner, joiner.GetSchema(), from, to}
 }
 
 // Next returns the next row
@@ -343,7 +344,7 @@ func (itr *diffRowItr) Next() (sql.Row, error) {
 		}
 	}
 
-	sqlRow, err := doltRowToSqlRow(r, itr.sch)
+	sqlRow, err := doltRowToSqlRow(itr.ctx, r, itr.sch)
 
 	if err != nil {
 		return nil, err
@@ -577,7 +578,7 @@ func (dp *diffPartitions) Close() error {
 }
 
 // creates a RowConverter for transforming rows with the the given schema to this super schema.
-func rowConvForSchema(ss *schema.SuperSchema, sch schema.Schema) (*rowconv.RowConverter, error) {
+func rowConvForSchema(ctx context.Context, vrw types.ValueReadWriter, ss *schema.SuperSchema, sch schema.Schema) (*rowconv.RowConverter, error) {
 	eq, err := schema.SchemasAreEqual(sch, schema.EmptySchema)
 	if err != nil {
 		return nil, err
@@ -604,5 +605,5 @@ func rowConvForSchema(ss *schema.SuperSchema, sch schema.Schema) (*rowconv.RowCo
 		return nil, err
 	}
 
-	return rowconv.NewRowConverter(fm)
+	return rowconv.NewRowConverter(ctx, vrw, fm)
 }
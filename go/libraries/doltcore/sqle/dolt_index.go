@@ -15,9 +15,16 @@
 package sqle
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
 
 	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
@@ -32,23 +39,36 @@ type DoltIndex interface {
 	sql.DescendIndex
 	Schema() schema.Schema
 	TableData() types.Map
+	// estimateRows returns the estimated number of rows read by looking up the keys in |keyRange|
+	estimateRows(keyRange indexRange) float64
+	// hasStatistics returns whether the estimates of the rows read are based on statistics collected by ANALYZE TABLE
+	hasStatistics() bool
 }
 
 type doltIndex struct {
-	cols         []schema.Column
-	db           sql.Database
-	id           string
-	indexRowData types.Map
-	indexSch     schema.Schema
-	table        *doltdb.Table
-	tableData    types.Map
-	tableName    string
-	tableSch     schema.Schema
-	unique       bool
-	comment      string
-}
-
-//TODO: have queries using IS NULL make use of indexes
+	cols []schema.Column
+	// exprs are the expressions of an expression index, in the form the engine matches query filters against. The
+	// index is looked up by their values, which are held by |cols|.
+	exprs []string
+	// prefixLengths are the lengths of the prefixes of |cols| held by the index, if it's indexing any prefixes
+	prefixLengths []uint16
+	db            sql.Database
+	id            string
+	indexRowData  types.Map
+	indexSch      schema.Schema
+	table         *doltdb.Table
+	tableData     types.Map
+	tableName     string
+	tableSch      schema.Schema
+	unique        bool
+	fullText      bool
+	spatial       bool
+	comment       string
+	// stats are the statistics of the table, which are shared by all of its indexes
+	stats *lazyStatistics
+}
+
+// TODO: have queries using IS NULL make use of indexes
 var _ DoltIndex = (*doltIndex)(nil)
 
 var alwaysContinueRangeCheck noms.InRangeCheck = func(tuple types.Tuple) (bool, error) {
@@ -62,7 +82,7 @@ func (di *doltIndex) AscendGreaterOrEqual(keys ...interface{}) (sql.IndexLookup,
 		return nil, err
 	}
 	readRange := &noms.ReadRange{Start: tpl, Inclusive: true, Reverse: false, Check: alwaysContinueRangeCheck}
-	return di.rangeToIndexLookup(readRange)
+	return di.rangeToIndexLookup(readRange, indexRange{lower: keys, lowerInclusive: true})
 }
 
 // AscendLessThan implements sql.AscendIndex
@@ -72,7 +92,15 @@ func (di *doltIndex) AscendLessThan(keys ...interface{}) (sql.IndexLookup, error
 		return nil, err
 	}
 	readRange := &noms.ReadRange{Start: tpl, Inclusive: false, Reverse: true, Check: alwaysContinueRangeCheck}
-	return di.rangeToIndexLookup(readRange)
+	if di.prefixLengths != nil {
+		// values sharing the key's prefix may be less than the key
+		tpl, err = di.keysToTuple(keys, true)
+		if err != nil {
+			return nil, err
+		}
+		readRange = &noms.ReadRange{Start: tpl, Inclusive: true, Reverse: true, Check: alwaysContinueRangeCheck}
+	}
+	return di.rangeToIndexLookup(readRange, indexRange{upper: keys})
 }
 
 // AscendRange implements sql.AscendIndex
@@ -90,17 +118,18 @@ func (di *doltIndex) AscendRange(greaterOrEqual, lessThanOrEqual []interface{})
 	readRange := &noms.ReadRange{Start: greaterTpl, Inclusive: true, Reverse: false, Check: func(tuple types.Tuple) (bool, error) {
 		return tuple.Less(nbf, lessTpl)
 	}}
-	return di.rangeToIndexLookup(readRange)
+	return di.rangeToIndexLookup(readRange, indexRange{lower: greaterOrEqual, lowerInclusive: true, upper: lessThanOrEqual, upperInclusive: true})
 }
 
 // DescendGreater implements sql.DescendIndex
 func (di *doltIndex) DescendGreater(keys ...interface{}) (sql.IndexLookup, error) {
-	tpl, err := di.keysToTuple(keys, true)
+	// values sharing the key's prefix may be greater than the key
+	tpl, err := di.keysToTuple(keys, di.prefixLengths == nil)
 	if err != nil {
 		return nil, err
 	}
 	readRange := &noms.ReadRange{Start: tpl, Inclusive: true, Reverse: false, Check: alwaysContinueRangeCheck}
-	return di.rangeToIndexLookup(readRange)
+	return di.rangeToIndexLookup(readRange, indexRange{lower: keys})
 }
 
 // DescendLessOrEqual implements sql.DescendIndex
@@ -110,7 +139,7 @@ func (di *doltIndex) DescendLessOrEqual(keys ...interface{}) (sql.IndexLookup, e
 		return nil, err
 	}
 	readRange := &noms.ReadRange{Start: tpl, Inclusive: true, Reverse: true, Check: alwaysContinueRangeCheck}
-	return di.rangeToIndexLookup(readRange)
+	return di.rangeToIndexLookup(readRange, indexRange{upper: keys, upperInclusive: true})
 }
 
 // DescendRange implements sql.DescendIndex
@@ -126,6 +155,9 @@ func (di *doltIndex) Database() string {
 
 // Expressions implements sql.Index
 func (di *doltIndex) Expressions() []string {
+	if di.exprs != nil {
+		return di.exprs
+	}
 	strs := make([]string, len(di.cols))
 	for i, col := range di.cols {
 		strs[i] = di.tableName + "." + col.Name
@@ -142,7 +174,7 @@ func (di *doltIndex) Get(keys ...interface{}) (sql.IndexLookup, error) {
 	readRange := &noms.ReadRange{Start: tpl, Inclusive: true, Reverse: false, Check: func(tuple types.Tuple) (bool, error) {
 		return tuple.StartsWith(tpl), nil
 	}}
-	return di.rangeToIndexLookup(readRange)
+	return di.rangeToIndexLookup(readRange, indexRange{lower: keys, lowerInclusive: true, upper: keys, upperInclusive: true})
 }
 
 // Has implements sql.Index
@@ -167,6 +199,12 @@ func (di *doltIndex) Comment() string {
 
 // IndexType implements sql.Index
 func (di *doltIndex) IndexType() string {
+	if di.fullText {
+		return "FULLTEXT"
+	}
+	if di.spatial {
+		return "SPATIAL"
+	}
 	return "BTREE"
 }
 
@@ -192,7 +230,13 @@ func (di *doltIndex) keysToTuple(keys []interface{}, appendMaxValue bool) (types
 	}
 	var vals []types.Value
 	for i, col := range di.cols {
-		val, err := col.TypeInfo.ConvertValueToNomsValue(keys[i])
+		var val types.Value
+		var err error
+		if i < len(di.prefixLengths) && di.prefixLengths[i] > 0 {
+			val, err = prefixKey(col, keys[i], di.prefixLengths[i])
+		} else {
+			val, err = col.TypeInfo.ConvertValueToNomsValue(context.Background(), di.table.ValueReadWriter(), keys[i])
+		}
 		if err != nil {
 			return types.EmptyTuple(nbf), err
 		}
@@ -206,12 +250,190 @@ func (di *doltIndex) keysToTuple(keys []interface{}, appendMaxValue bool) (types
 	return types.NewTuple(nbf, vals...)
 }
 
-func (di *doltIndex) rangeToIndexLookup(readRange *noms.ReadRange) (sql.IndexLookup, error) {
+// estimateRows implements DoltIndex.  Lookups of a value of every column of a unique index read at most one row.
+// Lookups of values of every column of other indexes are estimated with the selectivity of the value of each column,
+// assuming that the columns are independent, and lookups of ranges are estimated with the range of the first column.
+func (di *doltIndex) estimateRows(keyRange indexRange) float64 {
+	rowCount := float64(di.tableData.Len())
+	if rowCount == 0 {
+		return 0
+	}
+
+	isPoint := keyRange.isPoint() && len(keyRange.lower) == len(di.cols)
+	if isPoint && di.unique {
+		return 1
+	}
+
+	stats := di.stats.get()
+	if di.exprs != nil {
+		// there are no statistics of the values of expressions
+		stats = nil
+	}
+
+	ctx := context.Background()
+	vrw := di.table.ValueReadWriter()
+	selectivity := 1.0
+	if isPoint {
+		for i, col := range di.cols {
+			val, err := col.TypeInfo.ConvertValueToNomsValue(ctx, vrw, keyRange.lower[i])
+			if err != nil {
+				return rowCount * defaultEqualitySelectivity
+			}
+			selectivity *= stats.equalitySelectivity(col, val)
+		}
+	} else {
+		col := di.cols[0]
+		// values of the following columns only narrow the range of the first column's values, which is inclusive
+		var lower, upper types.Value
+		var err error
+		if keyRange.lower != nil {
+			lower, err = col.TypeInfo.ConvertValueToNomsValue(ctx, vrw, keyRange.lower[0])
+		}
+		if err == nil && keyRange.upper != nil {
+			upper, err = col.TypeInfo.ConvertValueToNomsValue(ctx, vrw, keyRange.upper[0])
+		}
+		if err != nil {
+			return rowCount * defaultRangeSelectivity
+		}
+
+		lowerInclusive := keyRange.lowerInclusive || len(di.cols) > 1
+		upperInclusive := keyRange.upperInclusive || len(di.cols) > 1
+		selectivity = stats.rangeSelectivity(col, lower, lowerInclusive, upper, upperInclusive)
+	}
+
+	// a value missing from the sample the statistics were collected from may still be in the table
+	return math.Max(rowCount*selectivity, 1)
+}
+
+// hasStatistics implements DoltIndex
+func (di *doltIndex) hasStatistics() bool {
+	return di.stats.get() != nil
+}
+
+// rangeToIndexLookup returns the lookup of the keys in |readRange|, which is described by |keyRange|.
+func (di *doltIndex) rangeToIndexLookup(readRange *noms.ReadRange, keyRange indexRange) (sql.IndexLookup, error) {
 	var mapIter table.TableReadCloser = noms.NewNomsRangeReader(di.indexSch, di.indexRowData, []*noms.ReadRange{readRange})
 	return &doltIndexLookup{
-		di,
-		&doltIndexKeyIter{
+		idx: di,
+		keyIter: &doltIndexKeyIter{
 			indexMapIter: mapIter,
 		},
+		keyRange: keyRange,
 	}, nil
 }
+
+// indexRange is a range of the keys of an index.  A nil bound is unbounded.
+type indexRange struct {
+	lower          []interface{}
+	upper          []interface{}
+	lowerInclusive bool
+	upperInclusive bool
+}
+
+// isPoint returns whether the range is the single key of its bounds.
+func (r indexRange) isPoint() bool {
+	return r.lower != nil && r.lowerInclusive && r.upperInclusive && reflect.DeepEqual(r.lower, r.upper)
+}
+
+// String returns the range in interval notation, such as [1, 10).
+func (r indexRange) String() string {
+	lower := "(-inf"
+	if r.lower != nil {
+		lower = "(" + formatIndexKeys(r.lower)
+		if r.lowerInclusive {
+			lower = "[" + formatIndexKeys(r.lower)
+		}
+	}
+
+	upper := "+inf)"
+	if r.upper != nil {
+		upper = formatIndexKeys(r.upper) + ")"
+		if r.upperInclusive {
+			upper = formatIndexKeys(r.upper) + "]"
+		}
+	}
+
+	return lower + ", " + upper
+}
+
+// formatIndexKeys formats the values of the index columns of a key, which are enclosed in parentheses for a key
+// with multiple columns.
+func formatIndexKeys(keys []interface{}) string {
+	strs := make([]string, len(keys))
+	for i, key := range keys {
+		switch key := key.(type) {
+		case nil:
+			strs[i] = "NULL"
+		case string:
+			strs[i] = strconv.Quote(key)
+		default:
+			strs[i] = fmt.Sprint(key)
+		}
+	}
+
+	if len(strs) == 1 {
+		return strs[0]
+	}
+
+	return "(" + strings.Join(strs, ", ") + ")"
+}
+
+// prefixKey returns the prefix of a key for a column indexed by its prefix, as it's stored in the index.
+func prefixKey(col schema.Column, key interface{}, length uint16) (types.Value, error) {
+	val, err := col.TypeInfo.ToSqlType().Convert(key)
+	if err != nil || val == nil {
+		return types.NullValue, err
+	}
+	str, ok := val.(string)
+	if !ok {
+		return nil, fmt.Errorf("unexpected key of type %T for column `%s`", val, col.Name)
+	}
+	return types.String(schema.PrefixOf(str, length)), nil
+}
+
+// indexLookupColumns returns the columns an index is looked up by, along with the expressions the engine matches query
+// filters against for an expression index. These are compiled for the table with the name given. FULLTEXT and SPATIAL
+// indexes can't be looked up by their columns' values, so their expressions are ones that no filter matches.
+func indexLookupColumns(tableName string, tableSch schema.Schema, index schema.Index) ([]schema.Column, []string, error) {
+	if len(index.Expressions()) == 0 {
+		cols := make([]schema.Column, index.Count())
+		var exprs []string
+		for i, tag := range index.IndexedColumnTags() {
+			cols[i], _ = index.GetColumn(tag)
+			if index.IsFullText() {
+				exprs = append(exprs, fmt.Sprintf("FULLTEXT(%s.%s)", tableName, cols[i].Name))
+			} else if index.IsSpatial() {
+				exprs = append(exprs, fmt.Sprintf("SPATIAL(%s.%s)", tableName, cols[i].Name))
+			}
+		}
+		return cols, exprs, nil
+	}
+
+	exprStrs := make([]string, len(index.Expressions()))
+	for i, expr := range index.Expressions() {
+		exprStrs[i] = expr.Expression
+	}
+	compiled, err := compileExpressions(tableName, tableSch.GetAllCols().GetColumns(), exprStrs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	indexCols := index.Schema().GetAllCols()
+	cols := make([]schema.Column, len(compiled))
+	exprs := make([]string, len(compiled))
+	for i, expr := range compiled {
+		cols[i], _ = indexCols.GetByTag(schema.IndexExpressionTag(i))
+		// the engine qualifies the columns in filters with their table
+		expr, err = expression.TransformUp(expr, func(e sql.Expression) (sql.Expression, error) {
+			if gf, ok := e.(*expression.GetField); ok {
+				return gf.WithTable(tableName), nil
+			}
+			return e, nil
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		exprs[i] = expr.String()
+	}
+	return cols, exprs, nil
+}
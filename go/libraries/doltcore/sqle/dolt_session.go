@@ -17,46 +17,85 @@ package sqle
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/dolthub/go-mysql-server/sql"
+	"gopkg.in/src-d/go-errors.v1"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/merge"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
 	"github.com/dolthub/dolt/go/store/hash"
 )
 
+// ErrNoStagingArea is returned when staging changes in a session which is not working on the branch checked out in the
+// repository.  The staging area belongs to the checked out branch.
+var ErrNoStagingArea = errors.NewKind("database %s has no staging area; only sessions on the checked out branch can stage changes")
+
+// ErrSerializationFailure is returned when a transaction can't be committed because it conflicts with a transaction
+// committed by another session after it started.  The transaction is rolled back, and can be retried.
+var ErrSerializationFailure = errors.NewKind("serialization failure: %s, try restarting transaction")
+
 type dbRoot struct {
 	hashStr string
 	root    *doltdb.RootValue
 }
 
 type dbData struct {
-	ddb *doltdb.DoltDB
-	rsw env.RepoStateWriter
+	ddb      *doltdb.DoltDB
+	rsr      env.RepoStateReader
+	rsw      env.RepoStateWriter
+	commitMu *sync.Mutex
+	dEnv     *env.DoltEnv
 }
 
 var _ sql.Session = &DoltSession{}
 
 // DoltSession is the sql.Session implementation used by dolt.  It is accessible through a *sql.Context instance
 type DoltSession struct {
+	// rowsExamined is the number of table rows read by the queries of the session.  It is updated atomically, and is
+	// the first field of the struct to be 64 bit aligned.
+	rowsExamined uint64
+
 	sql.Session
 	dbRoots   map[string]dbRoot
 	dbDatas   map[string]dbData
 	dbEditors map[string]*doltdb.TableEditSession
 
+	// txStartRoots are the working roots of each database at the start of the session's current transaction
+	txStartRoots map[string]*doltdb.RootValue
+	// txOpen holds the databases whose current transaction spans several statements, because it was started with
+	// autocommit disabled and hasn't been committed yet
+	txOpen map[string]bool
+
+	// headRefs are the branches the session is working on for each database.  Databases whose head has been set to a
+	// commit hash have no entry.
+	headRefs map[string]ref.DoltRef
+
 	Username string
 	Email    string
+
+	// QueryStats are the statistics of the queries run by the server the session belongs to.  It is nil when the
+	// session doesn't belong to a server.
+	QueryStats *QueryStats
 }
 
 // DefaultDoltSession creates a DoltSession object with default values
 func DefaultDoltSession() *DoltSession {
 	sess := &DoltSession{
-		Session:   sql.NewBaseSession(),
-		dbRoots:   make(map[string]dbRoot),
-		dbDatas:   make(map[string]dbData),
-		dbEditors: make(map[string]*doltdb.TableEditSession),
-		Username:  "",
-		Email:     "",
+		Session:      sql.NewBaseSession(),
+		dbRoots:      make(map[string]dbRoot),
+		dbDatas:      make(map[string]dbData),
+		dbEditors:    make(map[string]*doltdb.TableEditSession),
+		txStartRoots: make(map[string]*doltdb.RootValue),
+		txOpen:       make(map[string]bool),
+		headRefs:     make(map[string]ref.DoltRef),
+		Username:     "",
+		Email:        "",
 	}
 	return sess
 }
@@ -67,11 +106,21 @@ func NewDoltSession(ctx context.Context, sqlSess sql.Session, username, email st
 	dbDatas := make(map[string]dbData)
 	dbEditors := make(map[string]*doltdb.TableEditSession)
 	for _, db := range dbs {
-		dbDatas[db.Name()] = dbData{rsw: db.rsw, ddb: db.ddb}
+		dbDatas[db.Name()] = dbData{ddb: db.ddb, rsr: db.rsr, rsw: db.rsw, commitMu: db.commitMu}
 		dbEditors[db.Name()] = doltdb.CreateTableEditSession(nil, doltdb.TableEditSessionProps{})
 	}
 
-	sess := &DoltSession{sqlSess, dbRoots, dbDatas, dbEditors, username, email}
+	sess := &DoltSession{
+		Session:      sqlSess,
+		dbRoots:      dbRoots,
+		dbDatas:      dbDatas,
+		dbEditors:    dbEditors,
+		txStartRoots: make(map[string]*doltdb.RootValue),
+		txOpen:       make(map[string]bool),
+		headRefs:     make(map[string]ref.DoltRef),
+		Username:     username,
+		Email:        email,
+	}
 	for _, db := range dbs {
 		err := sess.AddDB(ctx, db)
 
@@ -88,6 +137,11 @@ func DSessFromSess(sess sql.Session) *DoltSession {
 	return sess.(*DoltSession)
 }
 
+// CommitTransaction commits the changes made by the current transaction to the working set of the current database.
+// If the working set has been changed by another session since the transaction started, the changes are merged.  If
+// the changes conflict the transaction is rolled back and ErrSerializationFailure is returned.  Sessions which are not
+// working on the branch checked out in the repository keep their own working root, which is only visible to them
+// until it is committed.
 func (sess *DoltSession) CommitTransaction(ctx *sql.Context) error {
 	currentDb := sess.GetCurrentDatabase()
 	if currentDb == "" {
@@ -101,13 +155,198 @@ func (sess *DoltSession) CommitTransaction(ctx *sql.Context) error {
 
 	dbData := sess.dbDatas[currentDb]
 
+	if !sess.UsesRepoWorkingSet(currentDb) {
+		return sess.startTransaction(ctx, currentDb, dbRoot.root)
+	}
+
+	if dbData.commitMu != nil {
+		dbData.commitMu.Lock()
+		defer dbData.commitMu.Unlock()
+	}
+
 	root := dbRoot.root
+	if startRoot, ok := sess.txStartRoots[currentDb]; ok {
+		workingRoot, err := dbData.ddb.ReadRootValue(ctx, dbData.rsr.WorkingHash())
+		if err != nil {
+			return err
+		}
+
+		root, err = mergeTransaction(ctx, root, workingRoot, startRoot)
+		if err != nil {
+			// roll back the transaction so that it can be retried against the current working set
+			if rbErr := sess.startTransaction(ctx, currentDb, workingRoot); rbErr != nil {
+				return rbErr
+			}
+
+			return err
+		}
+	}
+
 	h, err := dbData.ddb.WriteRootValue(ctx, root)
 	if err != nil {
 		return err
 	}
 
-	return dbData.rsw.SetWorkingHash(ctx, h)
+	err = dbData.rsw.SetWorkingHash(ctx, h)
+	if err != nil {
+		return err
+	}
+
+	return sess.startTransaction(ctx, currentDb, root)
+}
+
+// mergeTransaction merges the changes made by a transaction since it started at |startRoot| into |workingRoot|, the
+// current working root of the database.
+func mergeTransaction(ctx context.Context, txRoot, workingRoot, startRoot *doltdb.RootValue) (*doltdb.RootValue, error) {
+	startHash, err := startRoot.HashOf()
+	if err != nil {
+		return nil, err
+	}
+
+	workingHash, err := workingRoot.HashOf()
+	if err != nil {
+		return nil, err
+	}
+
+	if workingHash == startHash {
+		// nothing has been committed since the transaction started
+		return txRoot, nil
+	}
+
+	txHash, err := txRoot.HashOf()
+	if err != nil {
+		return nil, err
+	}
+
+	if txHash == startHash {
+		// the transaction didn't make any changes
+		return workingRoot, nil
+	}
+
+	mergedRoot, tblToStats, err := merge.MergeRoots(ctx, txRoot, workingRoot, startRoot)
+	if err == merge.ErrSameTblAddedTwice {
+		return nil, ErrSerializationFailure.New(err.Error())
+	} else if err != nil {
+		return nil, err
+	}
+
+	var conflicted []string
+	for tblName, stats := range tblToStats {
+		if stats.Conflicts > 0 {
+			conflicted = append(conflicted, tblName)
+		}
+	}
+
+	if len(conflicted) > 0 {
+		sort.Strings(conflicted)
+		return nil, ErrSerializationFailure.New("conflicting changes to " + strings.Join(conflicted, ", "))
+	}
+
+	return mergedRoot, nil
+}
+
+// UsesRepoWorkingSet returns true if the session is working on the branch of the database |dbName| which is checked out
+// in the repository, and so shares the repository's working set and staged root.
+func (sess *DoltSession) UsesRepoWorkingSet(dbName string) bool {
+	headRef, ok := sess.headRefs[dbName]
+	dbd, dbFound := sess.dbDatas[dbName]
+
+	if !ok || !dbFound || dbd.rsr == nil {
+		return false
+	}
+
+	return ref.Equals(headRef, dbd.rsr.CWBHeadRef())
+}
+
+// StartStatement is called before the session runs a statement.  The databases using the repository's working set
+// which have no open transaction start a new one from the current working set, so that the statement sees the changes
+// committed by other sessions before it started, and only the changes committed while its transaction is open are
+// merged when it's committed.  With |autocommit| every statement is its own transaction, otherwise the transaction
+// stays open until it's committed.
+func (sess *DoltSession) StartStatement(ctx context.Context, autocommit bool) error {
+	for dbName, dbd := range sess.dbDatas {
+		if sess.txOpen[dbName] || !sess.UsesRepoWorkingSet(dbName) {
+			continue
+		}
+
+		err := sess.refreshWorkingRoot(ctx, dbName, dbd)
+		if err != nil {
+			return err
+		}
+
+		sess.txOpen[dbName] = !autocommit
+	}
+
+	return nil
+}
+
+// refreshWorkingRoot starts a new transaction from the repository's working set for the database |dbName|, unless the
+// session has changes which haven't been committed yet.
+func (sess *DoltSession) refreshWorkingRoot(ctx context.Context, dbName string, dbd dbData) error {
+	startRoot, ok := sess.txStartRoots[dbName]
+	if !ok || sess.dbRoots[dbName].root != startRoot {
+		return nil
+	}
+
+	if dbd.commitMu != nil {
+		dbd.commitMu.Lock()
+		defer dbd.commitMu.Unlock()
+	}
+
+	workingHash := dbd.rsr.WorkingHash()
+	if workingHash.String() == sess.dbRoots[dbName].hashStr {
+		return nil
+	}
+
+	workingRoot, err := dbd.ddb.ReadRootValue(ctx, workingHash)
+	if err != nil {
+		return err
+	}
+
+	return sess.startTransaction(ctx, dbName, workingRoot)
+}
+
+// startTransaction sets the root of the database |dbName| to |root|, and starts a new transaction from it.  The
+// transaction isn't open until a statement is run in it.
+func (sess *DoltSession) startTransaction(ctx context.Context, dbName string, root *doltdb.RootValue) error {
+	err := sess.setRoot(ctx, dbName, root)
+	if err != nil {
+		return err
+	}
+
+	sess.txStartRoots[dbName] = root
+	delete(sess.txOpen, dbName)
+	return nil
+}
+
+// setRoot sets the working root of the database |dbName| in this session.
+func (sess *DoltSession) setRoot(ctx context.Context, dbName string, newRoot *doltdb.RootValue) error {
+	h, err := newRoot.HashOf()
+	if err != nil {
+		return err
+	}
+
+	hashStr := h.String()
+	err = sess.Session.Set(ctx, dbName+WorkingKeySuffix, hashType, hashStr)
+	if err != nil {
+		return err
+	}
+
+	sess.dbRoots[dbName] = dbRoot{hashStr, newRoot}
+
+	return sess.dbEditors[dbName].SetRoot(ctx, newRoot)
+}
+
+// RowsExamined returns the number of table rows read by the queries of the session so far.
+func (sess *DoltSession) RowsExamined() uint64 {
+	return atomic.LoadUint64(&sess.rowsExamined)
+}
+
+// countRowExamined counts a table row read by the query of |ctx|.
+func countRowExamined(ctx *sql.Context) {
+	if sess, ok := ctx.Session.(*DoltSession); ok {
+		atomic.AddUint64(&sess.rowsExamined, 1)
+	}
 }
 
 // GetDoltDB returns the *DoltDB for a given database by name
@@ -132,6 +371,156 @@ func (sess *DoltSession) GetRoot(dbName string) (*doltdb.RootValue, bool) {
 	return dbRoot.root, true
 }
 
+// GetRoots returns the working, staged and head roots of the database |dbName| in this session.  Sessions which are not
+// on the branch checked out in the repository have no staging area, so their staged root is their head root.
+func (sess *DoltSession) GetRoots(ctx context.Context, dbName string) (working, staged, head *doltdb.RootValue, err error) {
+	working, ok := sess.GetRoot(dbName)
+
+	if !ok {
+		return nil, nil, nil, sql.ErrDatabaseNotFound.New(dbName)
+	}
+
+	parent, _, err := sess.GetParentCommit(ctx, dbName)
+
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	head, err = parent.GetRootValue()
+
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if !sess.UsesRepoWorkingSet(dbName) {
+		return working, head, head, nil
+	}
+
+	dbd := sess.dbDatas[dbName]
+	staged, err = dbd.ddb.ReadRootValue(ctx, dbd.rsr.StagedHash())
+
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return working, staged, head, nil
+}
+
+// UpdateRoots replaces the working root of the database |dbName| in this session with |working|, and the staged root
+// with |staged|.  Either may be nil to leave it unchanged.  A new transaction is started from the new working root.  If
+// the session uses the repository's working set it is replaced as well, rather than merged with changes made by other
+// sessions.
+func (sess *DoltSession) UpdateRoots(ctx context.Context, dbName string, working, staged *doltdb.RootValue) error {
+	dbd, ok := sess.dbDatas[dbName]
+
+	if !ok {
+		return sql.ErrDatabaseNotFound.New(dbName)
+	}
+
+	if !sess.UsesRepoWorkingSet(dbName) {
+		if staged != nil {
+			return ErrNoStagingArea.New(dbName)
+		}
+
+		if working == nil {
+			return nil
+		}
+
+		return sess.startTransaction(ctx, dbName, working)
+	}
+
+	if dbd.commitMu != nil {
+		dbd.commitMu.Lock()
+		defer dbd.commitMu.Unlock()
+	}
+
+	if staged != nil {
+		h, err := dbd.ddb.WriteRootValue(ctx, staged)
+
+		if err != nil {
+			return err
+		}
+
+		err = dbd.rsw.SetStagedHash(ctx, h)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if working == nil {
+		return nil
+	}
+
+	h, err := dbd.ddb.WriteRootValue(ctx, working)
+
+	if err != nil {
+		return err
+	}
+
+	err = dbd.rsw.SetWorkingHash(ctx, h)
+
+	if err != nil {
+		return err
+	}
+
+	return sess.startTransaction(ctx, dbName, working)
+}
+
+// RefreshHead moves the session's head of the database |dbName| to the current head of the branch it is working on, if
+// the branch has moved since.  The session's working root is reset to the new head, or to the repository's working
+// set if the branch is checked out, which discards any uncommitted changes made by the session.
+func (sess *DoltSession) RefreshHead(ctx context.Context, dbName string) error {
+	headRef, ok := sess.headRefs[dbName]
+	dbd, dbFound := sess.dbDatas[dbName]
+
+	if !ok || !dbFound {
+		return nil
+	}
+
+	cm, err := dbd.ddb.ResolveRef(ctx, headRef)
+
+	if err != nil {
+		return err
+	}
+
+	h, err := cm.HashOf()
+
+	if err != nil {
+		return err
+	}
+
+	if _, value := sess.Session.Get(dbName + HeadKeySuffix); value == h.String() {
+		return nil
+	}
+
+	return sess.setHeadRef(ctx, dbName, headRef.String())
+}
+
+// GetStateReader returns the env.RepoStateReader of the database |dbName|, or nil if there is no such database.
+func (sess *DoltSession) GetStateReader(dbName string) env.RepoStateReader {
+	return sess.dbDatas[dbName].rsr
+}
+
+// GetHeadRef returns the branch of the database |dbName| this session is working on.  It returns false if the session's
+// head was set to a commit rather than a branch.
+func (sess *DoltSession) GetHeadRef(dbName string) (ref.DoltRef, bool) {
+	headRef, ok := sess.headRefs[dbName]
+	return headRef, ok
+}
+
+// GetDoltEnv returns the environment of the database |dbName|, which is only available for databases created with
+// NewDatabaseFromEnv.
+func (sess *DoltSession) GetDoltEnv(dbName string) (*env.DoltEnv, bool) {
+	dbd, ok := sess.dbDatas[dbName]
+
+	if !ok || dbd.dEnv == nil {
+		return nil, false
+	}
+
+	return dbd.dEnv, true
+}
+
 // GetParentCommit returns the parent commit of the current session.
 func (sess *DoltSession) GetParentCommit(ctx context.Context, dbName string) (*doltdb.Commit, hash.Hash, error) {
 	dbd, dbFound := sess.dbDatas[dbName]
@@ -215,13 +604,21 @@ func (sess *DoltSession) Set(ctx context.Context, key string, typ sql.Type, valu
 		}
 
 		sess.dbRoots[dbName] = dbRoot{hashStr, root}
+		sess.txStartRoots[dbName] = root
+		delete(sess.txOpen, dbName)
 
 		err = sess.dbEditors[dbName].SetRoot(ctx, root)
 		if err != nil {
 			return err
 		}
 
-		return nil
+		// the head was set to a specific commit, so the session is no longer working on a branch
+		delete(sess.headRefs, dbName)
+		return sess.Session.Set(ctx, dbName+HeadRefKeySuffix, sql.Text, "")
+	}
+
+	if isHeadRef, dbName := IsHeadRefKey(key); isHeadRef {
+		return sess.setHeadRef(ctx, dbName, value)
 	}
 
 	if key == "foreign_key_checks" {
@@ -246,19 +643,45 @@ func (sess *DoltSession) Set(ctx context.Context, key string, typ sql.Type, valu
 	return sess.Session.Set(ctx, key, typ, value)
 }
 
-func (sess *DoltSession) AddDB(ctx context.Context, db Database) error {
-	name := db.Name()
-	rsr := db.GetStateReader()
-	rsw := db.GetStateWriter()
-	ddb := db.GetDoltDB()
+// setHeadRef switches the session to the branch |value| of the database |dbName|.  If the branch is checked out in the
+// repository the session uses the repository's working set, otherwise the session's working root starts at the head
+// of the branch.
+func (sess *DoltSession) setHeadRef(ctx context.Context, dbName string, value interface{}) error {
+	dbd, dbFound := sess.dbDatas[dbName]
 
-	sess.dbDatas[db.Name()] = dbData{rsw: rsw, ddb: ddb}
+	if !dbFound {
+		return sql.ErrDatabaseNotFound.New(dbName)
+	}
 
-	sess.dbEditors[db.Name()] = doltdb.CreateTableEditSession(nil, doltdb.TableEditSessionProps{})
+	refStr, isStr := value.(string)
 
-	cs := rsr.CWBHeadSpec()
+	if !isStr {
+		return doltdb.ErrInvBranchName
+	}
 
-	cm, err := ddb.Resolve(ctx, cs, rsr.CWBHeadRef())
+	var headRef ref.DoltRef = ref.NewBranchRef(refStr)
+	if ref.IsRef(refStr) {
+		var err error
+		headRef, err = ref.Parse(refStr)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if headRef.GetType() != ref.BranchRefType {
+		return doltdb.ErrInvBranchName
+	}
+
+	hasRef, err := dbd.ddb.HasRef(ctx, headRef)
+
+	if err != nil {
+		return err
+	} else if !hasRef {
+		return doltdb.ErrBranchNotFound
+	}
+
+	cm, err := dbd.ddb.ResolveRef(ctx, headRef)
 
 	if err != nil {
 		return err
@@ -270,5 +693,40 @@ func (sess *DoltSession) AddDB(ctx context.Context, db Database) error {
 		return err
 	}
 
-	return sess.Set(ctx, name+HeadKeySuffix, sql.Text, h.String())
+	err = sess.Set(ctx, dbName+HeadKeySuffix, sql.Text, h.String())
+
+	if err != nil {
+		return err
+	}
+
+	sess.headRefs[dbName] = headRef
+
+	if sess.UsesRepoWorkingSet(dbName) {
+		workingRoot, err := dbd.ddb.ReadRootValue(ctx, dbd.rsr.WorkingHash())
+
+		if err != nil {
+			return err
+		}
+
+		err = sess.startTransaction(ctx, dbName, workingRoot)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return sess.Session.Set(ctx, dbName+HeadRefKeySuffix, sql.Text, headRef.String())
+}
+
+func (sess *DoltSession) AddDB(ctx context.Context, db Database) error {
+	name := db.Name()
+	rsr := db.GetStateReader()
+	rsw := db.GetStateWriter()
+	ddb := db.GetDoltDB()
+
+	sess.dbDatas[db.Name()] = dbData{ddb: ddb, rsr: rsr, rsw: rsw, commitMu: db.commitMu, dEnv: db.dEnv}
+
+	sess.dbEditors[db.Name()] = doltdb.CreateTableEditSession(nil, doltdb.TableEditSessionProps{})
+
+	return sess.setHeadRef(ctx, name, db.headRef().String())
 }
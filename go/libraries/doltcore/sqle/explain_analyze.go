@@ -0,0 +1,473 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	sqle "github.com/dolthub/go-mysql-server"
+	"github.com/dolthub/go-mysql-server/auth"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/parse"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+	"github.com/dolthub/vitess/go/vt/sqlparser"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+)
+
+// maxRangesShown is the number of distinct key ranges shown for each way a table was read
+const maxRangesShown = 3
+
+// ExplainAnalyze is an EXPLAIN ANALYZE statement, which runs a query and returns its plan annotated with the number
+// of rows returned by each node, the number of times each node was run, and the time spent in each node.  The nodes
+// reading Dolt tables also report whether the table was scanned or read through an index, the key ranges which were
+// read, and the number of chunks read from the chunk store while the node was running. The chunk store counts the reads
+// of the whole database, so that number includes the chunks read by the other queries running at the same time.
+type ExplainAnalyze struct {
+	// Query is the query which is explained
+	Query string
+}
+
+// ParseExplainAnalyze parses an EXPLAIN ANALYZE statement, returning nil if |query| isn't one.
+func ParseExplainAnalyze(query string) (*ExplainAnalyze, error) {
+	stmt, err := sqlparser.Parse(query)
+	if err != nil {
+		// leave reporting the error to the engine
+		return nil, nil
+	}
+
+	explain, ok := stmt.(*sqlparser.Explain)
+	if !ok || !explain.Analyze {
+		return nil, nil
+	}
+
+	// the grammar only accepts a select statement after EXPLAIN ANALYZE, which is the rest of the query
+	tkn := sqlparser.NewStringTokenizer(query)
+	for {
+		typ, _ := tkn.Scan()
+		switch typ {
+		case 0, sqlparser.LEX_ERROR:
+			return nil, fmt.Errorf("invalid EXPLAIN ANALYZE statement: %s", query)
+		case sqlparser.ANALYZE:
+			return &ExplainAnalyze{Query: strings.TrimSpace(query[tkn.Position-1:])}, nil
+		}
+	}
+}
+
+// Exec runs the query with |e|, discarding its rows, and returns the annotated plan of the query.
+func (ea *ExplainAnalyze) Exec(ctx *sql.Context, e *sqle.Engine) (sql.Schema, sql.RowIter, error) {
+	parsed, err := parse.Parse(ctx, ea.Query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = e.Auth.Allowed(ctx, auth.ReadPerm)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, err = e.Catalog.AddProcess(ctx, sql.QueryProcess, ea.Query)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer e.Catalog.Done(ctx.Pid())
+
+	analyzed, err := e.Analyzer.Analyze(ctx, parsed, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	analyzed, stats, err := withNodeStats(ctx, analyzed)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	iter, err := analyzed.RowIter(ctx, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for {
+		_, err = iter.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			_ = iter.Close()
+			return nil, nil, err
+		}
+	}
+
+	err = iter.Close()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var rows []sql.Row
+	for _, stat := range stats {
+		for _, l := range strings.Split(stat.String(), "\n") {
+			if strings.TrimSpace(l) != "" {
+				rows = append(rows, sql.NewRow(l))
+			}
+		}
+	}
+
+	return plan.DescribeSchema, sql.RowsToRowIter(rows...), nil
+}
+
+// nodeStats are the statistics of a node of a query run by EXPLAIN ANALYZE.
+type nodeStats struct {
+	// rows, loops, elapsed and chunksRead are accessed atomically, and kept first for their alignment
+	rows       uint64
+	loops      uint64
+	elapsed    int64
+	chunksRead uint64
+
+	label    string
+	children []*nodeStats
+
+	// reads is only set for the nodes reading Dolt tables, and ddb for those whose chunk store counts the chunks read
+	ddb   *doltdb.DoltDB
+	reads *doltReads
+}
+
+// withNodeStats returns |n| with all its nodes wrapped so that their statistics are collected, and the statistics of
+// its top level nodes.
+func withNodeStats(ctx *sql.Context, n sql.Node) (sql.Node, []*nodeStats, error) {
+	switch n := n.(type) {
+	case *plan.QueryProcess:
+		// the process tracking the query isn't part of its plan
+		child, stats, err := withNodeStats(ctx, n.Child)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		node, err := n.WithChildren(child)
+		return node, stats, err
+
+	case *plan.ResolvedTable:
+		stats := newTableStats(ctx, n.String(), n.Table)
+		return plan.NewResolvedTable(&analyzedTable{n.Table, stats}), []*nodeStats{stats}, nil
+
+	case *plan.IndexedTableAccess:
+		// the table is wrapped rather than the node, as indexed joins access the node directly
+		stats := newTableStats(ctx, fmt.Sprintf("IndexedTableAccess(%s)", n.Name()), n.Table)
+		node, err := n.WithChildren(plan.NewResolvedTable(&analyzedTable{n.Table, stats}))
+		return node, []*nodeStats{stats}, err
+	}
+
+	stats := &nodeStats{label: strings.SplitN(n.String(), "\n", 2)[0]}
+
+	children := n.Children()
+	if len(children) > 0 {
+		newChildren := make([]sql.Node, len(children))
+		for i, child := range children {
+			var childStats []*nodeStats
+			var err error
+			newChildren[i], childStats, err = withNodeStats(ctx, child)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			stats.children = append(stats.children, childStats...)
+		}
+
+		var err error
+		n, err = n.WithChildren(newChildren...)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return &analyzedNode{n, stats}, []*nodeStats{stats}, nil
+}
+
+func newTableStats(ctx *sql.Context, label string, tbl sql.Table) *nodeStats {
+	stats := &nodeStats{label: label}
+
+	if ddb, ok := doltDBForTable(ctx, tbl); ok {
+		stats.reads = newDoltReads()
+		if _, ok := ddb.ChunksRead(); ok {
+			stats.ddb = ddb
+		}
+	}
+
+	return stats
+}
+
+// doltDBTable is implemented by the tables which are read from a DoltDB
+type doltDBTable interface {
+	doltDB(ctx *sql.Context) (*doltdb.DoltDB, bool)
+}
+
+func doltDBForTable(ctx *sql.Context, tbl sql.Table) (*doltdb.DoltDB, bool) {
+	switch tbl := tbl.(type) {
+	case *plan.ProcessTable:
+		return doltDBForTable(ctx, tbl.Table)
+	case *plan.ProcessIndexableTable:
+		return doltDBForTable(ctx, tbl.DriverIndexableTable)
+	case doltDBTable:
+		return tbl.doltDB(ctx)
+	}
+
+	return nil, false
+}
+
+// start records that the node started running, returning the number of chunks read from its DoltDB so far, by every
+// session of the database.
+func (stats *nodeStats) start() (time.Time, uint64) {
+	var chunksRead uint64
+	if stats.ddb != nil {
+		chunksRead, _ = stats.ddb.ChunksRead()
+	}
+
+	return time.Now(), chunksRead
+}
+
+// stop records the time spent in the node since the matching call to start, and the chunks read from its DoltDB while it
+// was running, by this query or any other.
+func (stats *nodeStats) stop(start time.Time, chunksRead uint64) {
+	atomic.AddInt64(&stats.elapsed, int64(time.Since(start)))
+
+	if stats.ddb != nil {
+		after, _ := stats.ddb.ChunksRead()
+		atomic.AddUint64(&stats.chunksRead, after-chunksRead)
+	}
+}
+
+// withReads returns a context through which the Dolt tables read by the node record how they were read.
+func (stats *nodeStats) withReads(ctx *sql.Context) *sql.Context {
+	if stats.reads == nil {
+		return ctx
+	}
+
+	return ctx.WithContext(context.WithValue(ctx, doltReadsKey{}, stats.reads))
+}
+
+// String returns the node and its children as a tree, each node being followed by its statistics.
+func (stats *nodeStats) String() string {
+	var sb strings.Builder
+	sb.WriteString(stats.label)
+
+	loops := atomic.LoadUint64(&stats.loops)
+	if loops == 0 {
+		sb.WriteString(" (never executed)")
+	} else {
+		elapsed := time.Duration(atomic.LoadInt64(&stats.elapsed))
+		rows := atomic.LoadUint64(&stats.rows)
+		sb.WriteString(fmt.Sprintf(" (actual time=%.3fms rows=%d loops=%d)", millis(elapsed), rows, loops))
+	}
+
+	if stats.reads != nil {
+		details := stats.reads.descriptions()
+		if stats.ddb != nil {
+			details = append(details, fmt.Sprintf("database-wide chunks read: %d", atomic.LoadUint64(&stats.chunksRead)))
+		}
+		if len(details) > 0 {
+			sb.WriteString(" [" + strings.Join(details, "; ") + "]")
+		}
+	}
+
+	p := sql.NewTreePrinter()
+	_ = p.WriteNode("%s", sb.String())
+
+	children := make([]string, len(stats.children))
+	for i, child := range stats.children {
+		children[i] = child.String()
+	}
+	_ = p.WriteChildren(children...)
+
+	return p.String()
+}
+
+// analyzedNode is a sql.Node which collects the statistics of the node it wraps.
+type analyzedNode struct {
+	sql.Node
+	stats *nodeStats
+}
+
+var _ sql.Node = (*analyzedNode)(nil)
+
+// RowIter implements sql.Node
+func (n *analyzedNode) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	atomic.AddUint64(&n.stats.loops, 1)
+
+	start, chunksRead := n.stats.start()
+	iter, err := n.Node.RowIter(ctx, row)
+	n.stats.stop(start, chunksRead)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &analyzedRowIter{iter, n.stats}, nil
+}
+
+// WithChildren implements sql.Node
+func (n *analyzedNode) WithChildren(children ...sql.Node) (sql.Node, error) {
+	node, err := n.Node.WithChildren(children...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &analyzedNode{node, n.stats}, nil
+}
+
+// analyzedTable is a sql.Table which collects the statistics of the reads of the table it wraps.
+type analyzedTable struct {
+	sql.Table
+	stats *nodeStats
+}
+
+var _ sql.IndexAddressableTable = (*analyzedTable)(nil)
+
+// Partitions implements sql.Table
+func (t *analyzedTable) Partitions(ctx *sql.Context) (sql.PartitionIter, error) {
+	atomic.AddUint64(&t.stats.loops, 1)
+
+	start, chunksRead := t.stats.start()
+	defer t.stats.stop(start, chunksRead)
+
+	return t.Table.Partitions(t.stats.withReads(ctx))
+}
+
+// PartitionRows implements sql.Table
+func (t *analyzedTable) PartitionRows(ctx *sql.Context, part sql.Partition) (sql.RowIter, error) {
+	start, chunksRead := t.stats.start()
+	iter, err := t.Table.PartitionRows(t.stats.withReads(ctx), part)
+	t.stats.stop(start, chunksRead)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &analyzedRowIter{iter, t.stats}, nil
+}
+
+// WithIndexLookup implements sql.IndexAddressableTable
+func (t *analyzedTable) WithIndexLookup(lookup sql.IndexLookup) sql.Table {
+	return &analyzedTable{t.Table.(sql.IndexAddressableTable).WithIndexLookup(lookup), t.stats}
+}
+
+// analyzedRowIter is a sql.RowIter which counts the rows returned by the iterator it wraps, and the time spent in it.
+type analyzedRowIter struct {
+	sql.RowIter
+	stats *nodeStats
+}
+
+// Next implements sql.RowIter
+func (itr *analyzedRowIter) Next() (sql.Row, error) {
+	start, chunksRead := itr.stats.start()
+	row, err := itr.RowIter.Next()
+	itr.stats.stop(start, chunksRead)
+
+	if err == nil {
+		atomic.AddUint64(&itr.stats.rows, 1)
+	}
+
+	return row, err
+}
+
+// Close implements sql.RowIter
+func (itr *analyzedRowIter) Close() error {
+	start, chunksRead := itr.stats.start()
+	defer itr.stats.stop(start, chunksRead)
+
+	return itr.RowIter.Close()
+}
+
+type doltReadsKey struct{}
+
+// doltReads records how a Dolt table was read: the ways it was accessed, such as a full scan or the lookup of an
+// index, in the order they were first used, and the key ranges read by each.
+type doltReads struct {
+	mu       *sync.Mutex
+	accesses []string
+	byAccess map[string]*doltAccessReads
+}
+
+type doltAccessReads struct {
+	count  int
+	ranges []string
+	seen   map[string]struct{}
+}
+
+func newDoltReads() *doltReads {
+	return &doltReads{mu: &sync.Mutex{}, byAccess: make(map[string]*doltAccessReads)}
+}
+
+// recordDoltRead records that a Dolt table is read with |access|, over the key range |keyRange| if it isn't empty,
+// when the table is read by EXPLAIN ANALYZE.
+func recordDoltRead(ctx context.Context, access, keyRange string) {
+	reads, ok := ctx.Value(doltReadsKey{}).(*doltReads)
+	if !ok {
+		return
+	}
+
+	reads.mu.Lock()
+	defer reads.mu.Unlock()
+
+	ar, ok := reads.byAccess[access]
+	if !ok {
+		ar = &doltAccessReads{seen: make(map[string]struct{})}
+		reads.byAccess[access] = ar
+		reads.accesses = append(reads.accesses, access)
+	}
+
+	ar.count++
+	if _, ok := ar.seen[keyRange]; !ok && keyRange != "" {
+		ar.seen[keyRange] = struct{}{}
+		ar.ranges = append(ar.ranges, keyRange)
+	}
+}
+
+// isRecordingDoltReads returns whether the Dolt tables read with |ctx| record how they're read.
+func isRecordingDoltReads(ctx context.Context) bool {
+	_, ok := ctx.Value(doltReadsKey{}).(*doltReads)
+	return ok
+}
+
+// descriptions returns a description of each way the table was read, such as "index idx_age: [32, 32]".
+func (reads *doltReads) descriptions() []string {
+	reads.mu.Lock()
+	defer reads.mu.Unlock()
+
+	descs := make([]string, 0, len(reads.accesses))
+	for _, access := range reads.accesses {
+		ar := reads.byAccess[access]
+
+		desc := access
+		if len(ar.ranges) > 0 {
+			ranges := ar.ranges
+			if len(ranges) > maxRangesShown {
+				ranges = append(ranges[:maxRangesShown:maxRangesShown], "...")
+			}
+			desc += ": " + strings.Join(ranges, ", ")
+		}
+
+		if ar.count > 1 {
+			desc += fmt.Sprintf(" (%d reads)", ar.count)
+		}
+
+		descs = append(descs, desc)
+	}
+
+	return descs
+}
@@ -0,0 +1,137 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
+)
+
+func TestParseExplainAnalyze(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected *ExplainAnalyze
+	}{
+		{"explain analyze select * from people", &ExplainAnalyze{Query: "select * from people"}},
+		{"EXPLAIN  ANALYZE\nSELECT a FROM t WHERE b = 'analyze';", &ExplainAnalyze{Query: "SELECT a FROM t WHERE b = 'analyze';"}},
+		{"describe analyze select 1 union select 2", &ExplainAnalyze{Query: "select 1 union select 2"}},
+		{"explain select * from people", nil},
+		{"select * from people", nil},
+		{"analyze table people", nil},
+		{"explain analyze", nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			explain, err := ParseExplainAnalyze(test.query)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, explain)
+		})
+	}
+}
+
+var actualTimeRegex = regexp.MustCompile(`actual time=[0-9.]+ms`)
+
+func TestExplainAnalyze(t *testing.T) {
+	dEnv := dtestutils.CreateTestEnv()
+	root, err := dEnv.WorkingRoot(context.Background())
+	require.NoError(t, err)
+
+	root, err = ExecuteSql(dEnv, root, `
+CREATE TABLE people (id BIGINT PRIMARY KEY, name VARCHAR(20), age BIGINT, INDEX idx_age (age));
+CREATE TABLE pets (id BIGINT PRIMARY KEY, owner BIGINT, name VARCHAR(20));
+INSERT INTO people VALUES (1, 'Bill', 32), (2, 'John', 25), (3, 'Rob', 32), (4, 'Jane', 40);
+INSERT INTO pets VALUES (1, 1, 'Rex'), (2, 3, 'Tom'), (3, 4, 'Fido');
+`)
+	require.NoError(t, err)
+
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{
+			"explain analyze select * from people where name = 'Bill'",
+			[]string{
+				`Filter(people.name = "Bill") (actual time=Xms rows=1 loops=1)`,
+				` └─ Table(people) (actual time=Xms rows=4 loops=1) [full scan]`,
+			},
+		},
+		{
+			"explain analyze select name from people where age = 32",
+			[]string{
+				`Project(people.name) (actual time=Xms rows=2 loops=1)`,
+				` └─ Indexed table access on index [people.age] (actual time=Xms rows=2 loops=1)`,
+				`     └─ Filter(people.age = 32) (actual time=Xms rows=2 loops=1)`,
+				`         └─ Table(people) (actual time=Xms rows=2 loops=1) [index idx_age: [32, 32]]`,
+			},
+		},
+		{
+			"explain analyze select p.name, q.name from people p join pets q on p.id = q.owner where q.id > 5",
+			[]string{
+				`Project(p.name, q.name) (actual time=Xms rows=0 loops=1)`,
+				` └─ IndexedJoin(p.id = q.owner) (actual time=Xms rows=0 loops=1)`,
+				`     ├─ Filter(q.id > 5) (actual time=Xms rows=0 loops=1)`,
+				`     │   └─ TableAlias(q) (actual time=Xms rows=0 loops=1)`,
+				`     │       └─ Indexed table access on index [pets.id] (actual time=Xms rows=0 loops=1)`,
+				`     │           └─ Table(pets) (actual time=Xms rows=0 loops=1) [index PRIMARY: (5, +inf)]`,
+				`     └─ TableAlias(p) (never executed)`,
+				`         └─ IndexedTableAccess(people) (never executed)`,
+			},
+		},
+		{
+			"explain analyze select p.name, q.name from people p join pets q on p.id = q.owner",
+			[]string{
+				`Project(p.name, q.name) (actual time=Xms rows=3 loops=1)`,
+				` └─ IndexedJoin(p.id = q.owner) (actual time=Xms rows=3 loops=1)`,
+				`     ├─ TableAlias(q) (actual time=Xms rows=3 loops=1)`,
+				`     │   └─ Table(pets) (actual time=Xms rows=3 loops=1) [full scan]`,
+				`     └─ TableAlias(p) (actual time=Xms rows=3 loops=3)`,
+				`         └─ IndexedTableAccess(people) (actual time=Xms rows=3 loops=3) [index PRIMARY: [1, 1], [3, 3], [4, 4] (3 reads)]`,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			db := NewDatabase("dolt", dEnv.DoltDB, dEnv.RepoState, dEnv.RepoStateWriter())
+			engine, sqlCtx, err := NewTestEngine(context.Background(), db, root)
+			require.NoError(t, err)
+
+			explain, err := ParseExplainAnalyze(test.query)
+			require.NoError(t, err)
+			require.NotNil(t, explain)
+
+			sch, iter, err := explain.Exec(sqlCtx, engine)
+			require.NoError(t, err)
+			assert.Equal(t, "plan", sch[0].Name)
+
+			rows, err := sql.RowIterToRows(iter)
+			require.NoError(t, err)
+
+			var lines []string
+			for _, row := range rows {
+				lines = append(lines, actualTimeRegex.ReplaceAllString(row[0].(string), "actual time=Xms"))
+			}
+			assert.Equal(t, test.expected, lines)
+		})
+	}
+}
@@ -246,34 +246,40 @@ func (cache *ThreadSafeCRFuncCache) GetOrCreate(h hash.Hash, nbf *types.NomsBinF
 // CreateReaderFuncLimitedByExpressions takes a table schema and a slice of sql filters and returns a CreateReaderFunc
 // which limits the rows read based on the filters supplied.
 func CreateReaderFuncLimitedByExpressions(nbf *types.NomsBinFormat, tblSch schema.Schema, filters []sql.Expression) (CreateReaderFunc, error) {
+	keySet, err := keySetForFilters(nbf, tblSch, filters)
+
+	if err != nil {
+		// should probably log this to some debug logger. don't fail, just fall back on a full table
+		// scan.
+	} else {
+		return getCreateFuncForKeySet(nbf, keySet, tblSch)
+	}
+
+	return func(ctx context.Context, m types.Map) (table.TableReadCloser, error) {
+		return noms.NewNomsMapReader(ctx, m, tblSch)
+	}, nil
+}
+
+// keySetForFilters returns the set of the values of the first primary key column of the rows which may satisfy all
+// the filters supplied.
+func keySetForFilters(nbf *types.NomsBinFormat, tblSch schema.Schema, filters []sql.Expression) (setalgebra.Set, error) {
 	pkCols := tblSch.GetPKCols()
 	var keySet setalgebra.Set = setalgebra.UniversalSet{}
-	var err error
 	for _, filter := range filters {
-		var setForFilter setalgebra.Set
-		setForFilter, err = getSetForKeyColumn(nbf, pkCols.GetByIndex(0), filter)
+		setForFilter, err := getSetForKeyColumn(nbf, pkCols.GetByIndex(0), filter)
 
 		if err != nil {
-			break
+			return nil, err
 		}
 
 		keySet, err = keySet.Intersect(setForFilter)
 
 		if err != nil {
-			break
+			return nil, err
 		}
 	}
 
-	if err != nil {
-		// should probably log this to some debug logger. don't fail, just fall back on a full table
-		// scan.
-	} else {
-		return getCreateFuncForKeySet(nbf, keySet, tblSch)
-	}
-
-	return func(ctx context.Context, m types.Map) (table.TableReadCloser, error) {
-		return noms.NewNomsMapReader(ctx, m, tblSch)
-	}, nil
+	return keySet, nil
 }
 
 // finiteSetToKeySlice takes a setalgebra.FiniteSet instance and converts it to a slice of types.Tuple which can
@@ -250,6 +250,11 @@ func (ht *HistoryTable) Projection() []string {
 	return []string{}
 }
 
+// doltDB returns the DoltDB the history of the table is read from
+func (ht *HistoryTable) doltDB(*sql.Context) (*doltdb.DoltDB, bool) {
+	return ht.ddb, true
+}
+
 // Name returns the name of the history table
 func (ht *HistoryTable) Name() string {
 	return doltdb.DoltHistoryTablePrefix + ht.name
@@ -362,7 +367,7 @@ func newRowItrForTableAtCommit(
 		return nil, err
 	}
 
-	toSuperSchConv, err := rowConvForSchema(ss, tblSch)
+	toSuperSchConv, err := rowConvForSchema(ctx, root.VRW(), ss, tblSch)
 
 	if err != nil {
 		return nil, err
@@ -374,6 +379,17 @@ func newRowItrForTableAtCommit(
 		return nil, err
 	}
 
+	if isRecordingDoltReads(ctx) {
+		// the reader falls back on a full scan when the filters can't be converted to a set of keys
+		keySet, err := keySetForFilters(tbl.Format(), tblSch, filters)
+
+		if err != nil {
+			recordDoltRead(ctx, "full scan", "")
+		} else {
+			recordDoltRead(ctx, "primary key", keySet.String())
+		}
+	}
+
 	rd, err := createReaderFunc(ctx, m)
 
 	if err != nil {
@@ -441,7 +457,7 @@ func (tblItr *rowItrForTableAtCommit) Next() (sql.Row, error) {
 		}
 	}
 
-	return doltRowToSqlRow(r, tblItr.sch)
+	return doltRowToSqlRow(tblItr.ctx, r, tblItr.sch)
 }
 
 // Close the iterator.
@@ -0,0 +1,488 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+	"github.com/dolthub/vitess/go/vt/sqlparser"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlfmt"
+)
+
+// IndexDDL is a DDL statement that defines indexes on expressions or column prefixes. The SQL parser doesn't support
+// these, so their clauses are removed from these statements before the engine executes them, and the indexes are added
+// to the table afterwards.
+type IndexDDL struct {
+	// Query is the statement with its index clauses removed. It's empty if nothing else is left to execute. It may
+	// still define CHECK constraints, see ParseCheckDDL.
+	Query string
+	// Database is the database of the table, if the statement qualified its name.
+	Database string
+	// TableName is the name of the table the indexes are defined on.
+	TableName string
+	// CreatesTable is true for CREATE TABLE statements.
+	CreatesTable bool
+	// IfNotExists is true for CREATE TABLE IF NOT EXISTS statements.
+	IfNotExists bool
+	// Indexes holds the expression and prefix indexes to add to the table.
+	Indexes []IndexDef
+}
+
+// ParseIndexDDL returns the IndexDDL for the query given, or nil if the query is not a CREATE INDEX, CREATE TABLE or
+// ALTER TABLE statement that defines expression or prefix indexes. It also returns an error for CREATE TABLE statements
+// defining FULLTEXT indexes, which the engine would create as ordinary indexes.
+func ParseIndexDDL(query string) (*IndexDDL, error) {
+	// most queries aren't DDL, and don't need to be tokenized completely
+	if typ := firstTokenType(query); typ != sqlparser.CREATE && typ != sqlparser.ALTER {
+		return nil, nil
+	}
+
+	p, ok := newDDLParser(query)
+	if !ok {
+		return nil, nil
+	}
+
+	switch p.tok(0).typ {
+	case sqlparser.CREATE:
+		if t := p.tok(1); t.typ == sqlparser.UNIQUE || t.typ == sqlparser.INDEX {
+			return p.parseCreateIndex()
+		}
+		return p.parseCreateTableIndexes()
+	case sqlparser.ALTER:
+		return p.parseAlterTableIndexes()
+	default:
+		return nil, nil
+	}
+}
+
+// Exec executes the statement. |execQuery| is called to execute Query, after which the indexes are added to the table.
+// A table created by the statement is dropped again if its indexes can't be added.
+func (id *IndexDDL) Exec(ctx *sql.Context, catalog *sql.Catalog, execQuery func(query string) error) error {
+	ddl := tableDDL{
+		query:        id.Query,
+		database:     id.Database,
+		tableName:    id.TableName,
+		createsTable: id.CreatesTable,
+		ifNotExists:  id.IfNotExists,
+	}
+	return ddl.exec(ctx, catalog, execQuery, id.createIndexes)
+}
+
+// ExecIndexDDL executes |query| with |execQuery|. If the query defines expression or prefix indexes or CHECK
+// constraints, they're removed from it before it's executed and applied to the table afterwards. Used to execute the
+// Query of a SpatialDDL.
+func ExecIndexDDL(ctx *sql.Context, catalog *sql.Catalog, query string, execQuery func(query string) error) error {
+	indexDDL, err := ParseIndexDDL(query)
+	if err != nil {
+		return err
+	} else if indexDDL == nil {
+		return ExecCheckDDL(ctx, catalog, query, execQuery)
+	}
+
+	return indexDDL.Exec(ctx, catalog, func(query string) error {
+		return ExecCheckDDL(ctx, catalog, query, execQuery)
+	})
+}
+
+func (id *IndexDDL) createIndexes(ctx *sql.Context, tbl sql.Table) error {
+	for _, index := range id.Indexes {
+		err := createIndex(ctx, tbl, index)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func createIndex(ctx *sql.Context, tbl sql.Table, index IndexDef) error {
+	if len(index.Expressions) > 0 {
+		exprTbl, ok := tbl.(ExpressionIndexAlterableTable)
+		if !ok {
+			return fmt.Errorf("table %s does not support expression indexes", tbl.Name())
+		}
+		return exprTbl.CreateExpressionIndex(ctx, index)
+	}
+
+	indexTbl, ok := tbl.(sql.IndexAlterableTable)
+	if !ok {
+		return plan.ErrNotIndexable.New()
+	}
+	constraint := sql.IndexConstraint_None
+	if index.Unique {
+		constraint = sql.IndexConstraint_Unique
+	}
+	return indexTbl.CreateIndex(ctx, index.Name, sql.IndexUsing_Default, constraint, index.Columns, index.Comment)
+}
+
+// AddToSchema adds the indexes defined by the statement to the schema of the table given, which was created from
+// Query. Used when a schema is created from a statement without executing it.
+func (id *IndexDDL) AddToSchema(tableName string, sch schema.Schema) error {
+	for _, index := range id.Indexes {
+		var err error
+		if len(index.Expressions) > 0 {
+			_, err = addExpressionIndex(tableName, sch, index)
+		} else {
+			_, err = addPrefixIndex(sch, index)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// parseCreateIndex parses a CREATE [UNIQUE] INDEX name ON table (key_parts) statement, returning nil if its key parts
+// are all whole columns.
+func (p *ddlParser) parseCreateIndex() (*IndexDDL, error) {
+	id := &IndexDDL{}
+
+	i := 1
+	unique := p.tok(i).typ == sqlparser.UNIQUE
+	if unique {
+		i++
+	}
+	if p.tok(i).typ != sqlparser.INDEX || p.tok(i+1).typ != sqlparser.ID || p.tok(i+2).typ != sqlparser.ON {
+		return nil, nil
+	}
+	name := p.tok(i + 1).val
+
+	var ok bool
+	id.Database, id.TableName, i, ok = p.parseTableName(i + 3)
+	if !ok {
+		return nil, nil
+	}
+
+	index, next, ok, err := p.parseKeyParts(i)
+	if err != nil || !ok {
+		return nil, err
+	}
+	index.Name, index.Unique = name, unique
+
+	next, err = p.parseIndexOptions(&index, next)
+	if err != nil {
+		return nil, err
+	}
+	if t := p.tok(next); t.typ != 0 && t.typ != ';' {
+		return nil, fmt.Errorf("syntax error near '%s'", p.query[t.start():])
+	}
+
+	id.Indexes = append(id.Indexes, index)
+	return id, nil
+}
+
+func (p *ddlParser) parseCreateTableIndexes() (*IndexDDL, error) {
+	id := &IndexDDL{CreatesTable: true}
+
+	i := 1
+	if p.tok(i).isWord("temporary") {
+		i++
+	}
+	if p.tok(i).typ != sqlparser.TABLE {
+		return nil, nil
+	}
+	i++
+
+	if p.tok(i).typ == sqlparser.IF && p.tok(i+1).typ == sqlparser.NOT && p.tok(i+2).typ == sqlparser.EXISTS {
+		id.IfNotExists = true
+		i += 3
+	}
+
+	var ok bool
+	id.Database, id.TableName, i, ok = p.parseTableName(i)
+	if !ok || p.tok(i).typ != '(' {
+		return nil, nil
+	}
+
+	// |sep| is the '(' or ',' preceding the current table element, which is an index definition if it starts with
+	// UNIQUE, INDEX or KEY.
+	sep := i
+	elemStart := true
+	depth := 1
+	for i++; depth > 0 && i < len(p.toks); {
+		t := p.tok(i)
+		switch {
+		case t.typ == '(':
+			depth++
+		case t.typ == ')':
+			depth--
+		case depth == 1 && t.typ == ',':
+			sep, elemStart = i, true
+			i++
+			continue
+		case depth == 1 && elemStart && t.typ == sqlparser.FULLTEXT:
+			// the engine would create these as ordinary indexes
+			return nil, fmt.Errorf("FULLTEXT indexes cannot be defined in CREATE TABLE, use CREATE FULLTEXT INDEX once the table is created")
+		case depth == 1 && elemStart && (t.typ == sqlparser.UNIQUE || t.typ == sqlparser.INDEX || t.typ == sqlparser.KEY):
+			index, next, ok, err := p.parseIndexDef(i)
+			if err != nil {
+				return nil, err
+			} else if !ok {
+				break
+			}
+
+			id.Indexes = append(id.Indexes, index)
+			i = p.cutElement(i, next, sep, elemStart)
+			continue
+		}
+
+		elemStart = false
+		i++
+	}
+
+	if len(id.Indexes) == 0 {
+		return nil, nil
+	}
+
+	id.Query = p.queryWithoutCuts()
+	return id, nil
+}
+
+// parseAlterTableIndexes parses an ALTER TABLE statement adding expression or prefix indexes. Specifications adding or
+// dropping CHECK constraints are left in Query, but any other specification is an error.
+func (p *ddlParser) parseAlterTableIndexes() (*IndexDDL, error) {
+	id := &IndexDDL{}
+
+	i := 1
+	if p.tok(i).typ == sqlparser.IGNORE {
+		i++
+	}
+	if p.tok(i).typ != sqlparser.TABLE {
+		return nil, nil
+	}
+
+	var ok bool
+	id.Database, id.TableName, i, ok = p.parseTableName(i + 1)
+	if !ok {
+		return nil, nil
+	}
+
+	// |sep| is the ',' preceding the current alter specification, or the table name preceding the first one
+	sep := i - 1
+	checkSpecs, otherSpecs := false, false
+	for {
+		var isSpec bool
+		switch t := p.tok(i); {
+		case t.typ == sqlparser.ADD:
+			index, next, ok, err := p.parseIndexDef(i + 1)
+			if err != nil {
+				return nil, err
+			} else if ok {
+				id.Indexes = append(id.Indexes, index)
+				if after := p.cutElement(i, next, sep, true); after > next {
+					// the comma following the first specification is cut along with it
+					i = after
+					continue
+				}
+				i, isSpec = next, true
+				break
+			}
+
+			_, next, ok, err = p.parseCheck(i + 1)
+			if err != nil {
+				return nil, err
+			} else if ok {
+				i, isSpec, checkSpecs = next, true, true
+			}
+		case t.typ == sqlparser.DROP:
+			next := p.tok(i + 1)
+			if (next.typ == sqlparser.CHECK || next.typ == sqlparser.CONSTRAINT) && p.tok(i+2).typ == sqlparser.ID {
+				i, isSpec, checkSpecs = i+3, true, true
+			}
+		}
+
+		if !isSpec {
+			otherSpecs = true
+			// skip to the next alter specification
+			for depth := 0; i < len(p.toks) && (depth > 0 || p.tok(i).typ != ','); i++ {
+				switch p.tok(i).typ {
+				case '(':
+					depth++
+				case ')':
+					depth--
+				}
+			}
+		}
+
+		switch p.tok(i).typ {
+		case ',':
+			sep = i
+			i++
+			continue
+		case 0, ';':
+		default:
+			return nil, fmt.Errorf("syntax error near '%s'", p.query[p.tok(i).start():])
+		}
+		break
+	}
+
+	if len(id.Indexes) == 0 {
+		return nil, nil
+	} else if otherSpecs {
+		return nil, fmt.Errorf("expression and prefix indexes cannot be added along with other table definitions in one statement")
+	}
+
+	if checkSpecs {
+		id.Query = p.queryWithoutCuts()
+	}
+	return id, nil
+}
+
+// parseIndexDef parses an index definition of the form {INDEX | KEY | UNIQUE [INDEX | KEY]} [name] (key_parts)
+// [COMMENT 'string'] starting at index |i|. It returns the index, the index of the token following it, and false if
+// there is no index definition at |i| or its key parts are all whole columns.
+func (p *ddlParser) parseIndexDef(i int) (index IndexDef, next int, ok bool, err error) {
+	switch p.tok(i).typ {
+	case sqlparser.UNIQUE:
+		index.Unique = true
+		i++
+		if t := p.tok(i); t.typ == sqlparser.INDEX || t.typ == sqlparser.KEY {
+			i++
+		}
+	case sqlparser.INDEX, sqlparser.KEY:
+		i++
+	default:
+		return IndexDef{}, 0, false, nil
+	}
+
+	var name string
+	if p.tok(i).typ == sqlparser.ID {
+		name = p.tok(i).val
+		i++
+	}
+
+	def, i, ok, err := p.parseKeyParts(i)
+	if err != nil || !ok {
+		return IndexDef{}, 0, false, err
+	}
+	index.Name, index.Expressions, index.Columns = name, def.Expressions, def.Columns
+
+	i, err = p.parseIndexOptions(&index, i)
+	if err != nil {
+		return IndexDef{}, 0, false, err
+	}
+
+	return index, i, true, nil
+}
+
+// parseKeyParts parses the parenthesized key parts of an index starting at index |i|, each of which is either a
+// parenthesized expression or a column name, optionally followed by a prefix length and a direction. It returns an
+// index with the key parts as its expressions if any of them are expressions, or as its columns if any of them are
+// prefixes, along with the index of the token following them. It returns false if the key parts are all whole columns.
+func (p *ddlParser) parseKeyParts(i int) (index IndexDef, next int, ok bool, err error) {
+	if p.tok(i).typ != '(' {
+		return IndexDef{}, 0, false, nil
+	}
+	i++
+
+	var exprs []string
+	var cols []sql.IndexColumn
+	var hasExpr, hasPrefix bool
+	for {
+		switch t := p.tok(i); t.typ {
+		case '(':
+			exprStart := t.end
+			depth := 1
+			for i++; depth > 0; i++ {
+				switch p.tok(i).typ {
+				case '(':
+					depth++
+				case ')':
+					depth--
+				case 0:
+					return IndexDef{}, 0, false, fmt.Errorf("syntax error: unterminated index expression")
+				}
+			}
+			expr, err := normalizeExpression(p.query[exprStart:p.tok(i-1).start()], "index")
+			if err != nil {
+				return IndexDef{}, 0, false, err
+			}
+			exprs = append(exprs, expr)
+			hasExpr = true
+		case sqlparser.ID:
+			expr, err := normalizeExpression(sqlfmt.QuoteIdentifier(t.val), "index")
+			if err != nil {
+				return IndexDef{}, 0, false, err
+			}
+			exprs = append(exprs, expr)
+			col := sql.IndexColumn{Name: t.val}
+			i++
+
+			if p.tok(i).typ == '(' {
+				if p.tok(i+1).typ != sqlparser.INTEGRAL || p.tok(i+2).typ != ')' {
+					return IndexDef{}, 0, false, fmt.Errorf("syntax error: invalid prefix length for column `%s`", t.val)
+				}
+				col.Length, err = strconv.ParseInt(p.tok(i+1).val, 10, 64)
+				if err != nil || col.Length < 1 {
+					return IndexDef{}, 0, false, fmt.Errorf("invalid prefix length '%s' for column `%s`", p.tok(i+1).val, t.val)
+				}
+				hasPrefix = true
+				i += 3
+			}
+			cols = append(cols, col)
+		default:
+			return IndexDef{}, 0, false, nil
+		}
+
+		if t := p.tok(i); t.typ == sqlparser.ASC || t.typ == sqlparser.DESC {
+			i++
+		}
+
+		if p.tok(i).typ == ',' {
+			i++
+			continue
+		} else if p.tok(i).typ != ')' {
+			return IndexDef{}, 0, false, nil
+		}
+		i++
+		break
+	}
+
+	switch {
+	case hasExpr && hasPrefix:
+		return IndexDef{}, 0, false, fmt.Errorf("an index with expressions cannot have column prefix lengths")
+	case hasExpr:
+		index.Expressions = exprs
+	case hasPrefix:
+		index.Columns = cols
+	default:
+		return IndexDef{}, 0, false, nil
+	}
+	return index, i, true, nil
+}
+
+// parseIndexOptions parses the COMMENT option of an index starting at index |i|, returning the index of the token
+// following the index's options.
+func (p *ddlParser) parseIndexOptions(index *IndexDef, i int) (int, error) {
+	for {
+		switch t := p.tok(i); {
+		case t.typ == sqlparser.COMMENT_KEYWORD && p.tok(i+1).typ == sqlparser.STRING:
+			index.Comment = p.tok(i + 1).val
+			i += 2
+		case t.typ == sqlparser.USING && p.tok(i+1).typ == sqlparser.ID:
+			i += 2
+		case t.typ == sqlparser.COMMENT_KEYWORD || t.typ == sqlparser.USING:
+			return 0, fmt.Errorf("syntax error near '%s'", p.query[t.start():])
+		default:
+			return i, nil
+		}
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIndexDDL(t *testing.T) {
+	tests := []struct {
+		name        string
+		query       string
+		expected    *IndexDDL
+		expectedErr bool
+	}{
+		{
+			name:     "not ddl",
+			query:    "select * from t where c1 > 0",
+			expected: nil,
+		},
+		{
+			name:     "index on columns",
+			query:    "create unique index idx on t (c1, c2 desc)",
+			expected: nil,
+		},
+		{
+			name:     "table with checks",
+			query:    "create table t (pk int primary key, c1 int check (c1 > 0), key (c1))",
+			expected: nil,
+		},
+		{
+			name:  "create expression index",
+			query: "create unique index idx on t ((lower(c1)), c2) comment 'lowered'",
+			expected: &IndexDDL{
+				TableName: "t",
+				Indexes:   []IndexDef{{Name: "idx", Unique: true, Expressions: []string{"lower(c1)", "c2"}, Comment: "lowered"}},
+			},
+		},
+		{
+			name:  "table with expression and prefix indexes",
+			query: "create table t (pk int primary key, c1 varchar(20), index ((c1 + 1)), unique key c1_prefix (c1(3)), key (c1))",
+			expected: &IndexDDL{
+				Query:        "create table t (pk int primary key, c1 varchar(20), key (c1))",
+				TableName:    "t",
+				CreatesTable: true,
+				Indexes: []IndexDef{
+					{Expressions: []string{"c1 + 1"}},
+					{Name: "c1_prefix", Unique: true, Columns: []sql.IndexColumn{{Name: "c1", Length: 3}}},
+				},
+			},
+		},
+		{
+			name:  "table with checks and indexes",
+			query: "create table if not exists db.t (pk int primary key check (pk > 0), c1 varchar(20), index ((lower(c1))))",
+			expected: &IndexDDL{
+				Query:        "create table if not exists db.t (pk int primary key check (pk > 0), c1 varchar(20))",
+				Database:     "db",
+				TableName:    "t",
+				CreatesTable: true,
+				IfNotExists:  true,
+				Indexes:      []IndexDef{{Expressions: []string{"lower(c1)"}}},
+			},
+		},
+		{
+			name:  "alter add expression index",
+			query: "alter table t add index idx ((upper(c1)))",
+			expected: &IndexDDL{
+				TableName: "t",
+				Indexes:   []IndexDef{{Name: "idx", Expressions: []string{"upper(c1)"}}},
+			},
+		},
+		{
+			name:  "alter add indexes and checks",
+			query: "alter table t add index ((upper(c1))), add key (c1(2)), add check (c1 <> ''), drop check chk1",
+			expected: &IndexDDL{
+				Query:     "alter table t   add check (c1 <> ''), drop check chk1",
+				TableName: "t",
+				Indexes: []IndexDef{
+					{Expressions: []string{"upper(c1)"}},
+					{Columns: []sql.IndexColumn{{Name: "c1", Length: 2}}},
+				},
+			},
+		},
+		{
+			name:        "alter index with other specs",
+			query:       "alter table t add column c2 int, add index ((upper(c1)))",
+			expectedErr: true,
+		},
+		{
+			name:        "table with fulltext index",
+			query:       "create table t (pk int primary key, c1 text, fulltext key ft (c1))",
+			expectedErr: true,
+		},
+		{
+			name:        "expression index with prefix",
+			query:       "create index idx on t ((lower(c1)), c2(3))",
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := ParseIndexDDL(test.query)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,214 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	sqleSchema "github.com/dolthub/dolt/go/libraries/doltcore/sqle/schema"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// IndexDef is the definition of an index the SQL parser doesn't support: one with expressions as key parts, such as
+// LOWER(email), or with column prefixes as key parts, such as bio(10).
+type IndexDef struct {
+	// Name is the name of the index. Unnamed indexes are named when they are added.
+	Name   string
+	Unique bool
+	// Expressions are the key parts of an expression index, in order. Any columns among the key parts of an index with
+	// expressions are indexed as expressions too.
+	Expressions []string
+	// Columns are the key parts of a prefix index, in order.
+	Columns []sql.IndexColumn
+	Comment string
+}
+
+// ExpressionIndexAlterableTable is a table which can be given expression indexes.
+type ExpressionIndexAlterableTable interface {
+	sql.Table
+	// CreateExpressionIndex adds the index given to this table, and builds its data from the table's rows.
+	CreateExpressionIndex(ctx *sql.Context, index IndexDef) error
+}
+
+// IndexDefTable is a table whose indexes may include expression and prefix indexes, which the engine can't display.
+type IndexDefTable interface {
+	sql.Table
+	// GetIndexDefinitions returns the definitions of all of the table's indexes.
+	GetIndexDefinitions(ctx *sql.Context) ([]schema.Index, error)
+}
+
+var _ ExpressionIndexAlterableTable = (*AlterableDoltTable)(nil)
+var _ IndexDefTable = (*DoltTable)(nil)
+var _ IndexDefTable = (*SingleTableInfoDatabase)(nil)
+
+// GetIndexDefinitions implements IndexDefTable.
+func (t *DoltTable) GetIndexDefinitions(ctx *sql.Context) ([]schema.Index, error) {
+	return t.sch.Indexes().AllIndexes(), nil
+}
+
+// GetIndexDefinitions implements IndexDefTable.
+func (db *SingleTableInfoDatabase) GetIndexDefinitions(ctx *sql.Context) ([]schema.Index, error) {
+	return db.sch.Indexes().AllIndexes(), nil
+}
+
+// CreateExpressionIndex implements ExpressionIndexAlterableTable.
+func (t *AlterableDoltTable) CreateExpressionIndex(ctx *sql.Context, def IndexDef) error {
+	sch, err := t.table.GetSchema(ctx)
+	if err != nil {
+		return err
+	}
+
+	index, err := addExpressionIndex(t.name, sch, def)
+	if err != nil {
+		return err
+	}
+
+	newTable, err := tableWithNewIndex(ctx, t.table, sch, index, nil)
+	if err != nil {
+		return err
+	}
+
+	root, err := t.db.GetRoot(ctx)
+	if err != nil {
+		return err
+	}
+	newRoot, err := root.PutTable(ctx, t.name, newTable)
+	if err != nil {
+		return err
+	}
+	err = t.db.SetRoot(ctx, newRoot)
+	if err != nil {
+		return err
+	}
+	return t.updateFromRoot(ctx, newRoot)
+}
+
+// addExpressionIndex adds the index defined by |def| to the schema given, which belongs to the table named
+// |tableName|. The indexed columns of the added index are the columns its expressions reference.
+func addExpressionIndex(tableName string, sch schema.Schema, def IndexDef) (schema.Index, error) {
+	compiled, err := compileExpressions(tableName, sch.GetAllCols().GetColumns(), def.Expressions)
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []uint64
+	seen := make(map[uint64]bool)
+	exprs := make([]schema.IndexExpression, len(compiled))
+	for i, expr := range compiled {
+		var refsColumn bool
+		sql.Inspect(expr, func(e sql.Expression) bool {
+			if gf, ok := e.(*expression.GetField); ok {
+				if col, ok := sch.GetAllCols().GetByNameCaseInsensitive(gf.Name()); ok {
+					refsColumn = true
+					if !seen[col.Tag] {
+						seen[col.Tag] = true
+						tags = append(tags, col.Tag)
+					}
+				}
+			}
+			return true
+		})
+		if !refsColumn {
+			return nil, fmt.Errorf("expression '%s' of index `%s` does not reference any column", def.Expressions[i], def.Name)
+		}
+
+		ti, err := indexExpressionTypeInfo(expr.Type())
+		if err != nil {
+			return nil, fmt.Errorf("expression '%s' cannot be indexed: %w", def.Expressions[i], err)
+		}
+		exprs[i] = schema.IndexExpression{Expression: def.Expressions[i], TypeInfo: ti}
+	}
+
+	name := def.Name
+	if name == "" {
+		name = unusedExpressionIndexName(sch)
+	} else if sch.Indexes().Contains(name) {
+		return nil, fmt.Errorf("duplicate key name `%s`", name)
+	}
+
+	return sch.Indexes().AddIndexByColTags(name, tags, schema.IndexProperties{
+		IsUnique:      def.Unique,
+		IsUserDefined: true,
+		Expressions:   exprs,
+		Comment:       def.Comment,
+	})
+}
+
+// indexExpressionTypeInfo returns the type an expression of the given type is stored as in an index. Strings are all
+// stored the same way, so that changing the length of a column referenced by the expression doesn't invalidate the
+// index.
+func indexExpressionTypeInfo(typ sql.Type) (typeinfo.TypeInfo, error) {
+	if sql.IsText(typ) {
+		return typeinfo.StringDefaultType, nil
+	}
+
+	ti, err := typeinfo.FromSqlType(typ)
+	if err != nil {
+		return nil, err
+	}
+	if ti.NomsKind() == types.BlobKind {
+		return nil, fmt.Errorf("values of type %s cannot be indexed", typ.String())
+	}
+	return ti, nil
+}
+
+// unusedExpressionIndexName returns the name MySQL gives to an unnamed index with expressions: functional_index,
+// followed by the lowest number that isn't already used if that name is taken.
+func unusedExpressionIndexName(sch schema.Schema) string {
+	name := "functional_index"
+	for i := 2; sch.Indexes().Contains(name); i++ {
+		name = fmt.Sprintf("functional_index_%d", i)
+	}
+	return name
+}
+
+// addPrefixIndex adds the prefix index defined by |def| to the schema given.
+func addPrefixIndex(sch schema.Schema, def IndexDef) (schema.Index, error) {
+	colNames := make([]string, len(def.Columns))
+	prefixLengths := make([]uint16, len(def.Columns))
+	for i, indexCol := range def.Columns {
+		col, ok := sch.GetAllCols().GetByNameCaseInsensitive(indexCol.Name)
+		if !ok {
+			return nil, fmt.Errorf("column `%s` does not exist for the table", indexCol.Name)
+		}
+		if indexCol.Length > 0 {
+			err := validatePrefixLength(col, indexCol.Length)
+			if err != nil {
+				return nil, err
+			}
+			prefixLengths[i] = uint16(indexCol.Length)
+		} else if col.Kind == types.BlobKind {
+			return nil, sqleSchema.ErrBlobInKey.New(col.Name)
+		}
+		colNames[i] = col.Name
+	}
+
+	name := def.Name
+	if name == "" {
+		name = unusedIndexName(sch, colNames)
+	}
+
+	return sch.Indexes().AddIndexByColNames(name, colNames, schema.IndexProperties{
+		IsUnique:      def.Unique,
+		IsUserDefined: true,
+		PrefixLengths: prefixLengths,
+		Comment:       def.Comment,
+	})
+}
@@ -0,0 +1,92 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
+)
+
+func TestExpressionAndPrefixIndexes(t *testing.T) {
+	dEnv := dtestutils.CreateTestEnv()
+	root, err := dEnv.WorkingRoot(context.Background())
+	require.NoError(t, err)
+
+	root, err = ExecuteSql(dEnv, root, `
+CREATE TABLE test (
+  pk BIGINT PRIMARY KEY,
+  email VARCHAR(100),
+  bio LONGTEXT,
+  INDEX (bio(4))
+);
+INSERT INTO test VALUES (1, 'Bob@Example.com', 'hello world'), (2, 'alice@x.org', 'help'), (3, 'Dave@x.org', 'other');
+CREATE UNIQUE INDEX lower_email ON test ((LOWER(email)));
+`)
+	require.NoError(t, err)
+
+	_, err = ExecuteSql(dEnv, root, "INSERT INTO test VALUES (4, 'BOB@example.com', NULL)")
+	assert.Error(t, err)
+	_, err = ExecuteSql(dEnv, root, "CREATE INDEX no_cols ON test ((1 + 1))")
+	assert.Error(t, err)
+	_, err = ExecuteSql(dEnv, root, "CREATE INDEX pk_prefix ON test (pk(2))")
+	assert.Error(t, err)
+	_, err = ExecuteSql(dEnv, root, "ALTER TABLE test RENAME COLUMN email TO mail")
+	assert.Error(t, err)
+
+	tests := []struct {
+		query    string
+		index    string
+		expected []sql.Row
+	}{
+		{"SELECT pk FROM test WHERE LOWER(email) = 'bob@example.com'", "LOWER(test.email)", []sql.Row{{int64(1)}}},
+		{"SELECT pk FROM test WHERE LOWER(email) = 'carol@x.org'", "LOWER(test.email)", nil},
+		{"SELECT pk FROM test WHERE LOWER(email) > 'bob' ORDER BY pk", "LOWER(test.email)", []sql.Row{{int64(1)}, {int64(3)}}},
+		{"SELECT pk FROM test WHERE bio = 'hello world'", "test.bio", []sql.Row{{int64(1)}}},
+		{"SELECT pk FROM test WHERE bio < 'help' ORDER BY pk", "test.bio", []sql.Row{{int64(1)}}},
+		{"SELECT pk FROM test WHERE bio > 'hello' ORDER BY pk", "test.bio", []sql.Row{{int64(1)}, {int64(2)}, {int64(3)}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			rows, err := ExecuteSelect(dEnv, dEnv.DoltDB, root, test.query)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, rows)
+
+			plan, err := ExecuteSelect(dEnv, dEnv.DoltDB, root, "EXPLAIN "+test.query)
+			require.NoError(t, err)
+			require.NotEmpty(t, plan)
+			assert.Contains(t, fmt.Sprint(plan), "on index ["+test.index+"]")
+		})
+	}
+
+	rows, err := ExecuteSelect(dEnv, dEnv.DoltDB, root, "SHOW CREATE TABLE test")
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, "CREATE TABLE `test` (\n"+
+		"  `pk` bigint NOT NULL,\n"+
+		"  `email` varchar(100),\n"+
+		"  `bio` longtext,\n"+
+		"  PRIMARY KEY (`pk`),\n"+
+		"  KEY `bio` (`bio`(4)),\n"+
+		"  UNIQUE KEY `lower_email` ((LOWER(email)))\n"+
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4", rows[0][1])
+}
@@ -0,0 +1,112 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+)
+
+// indexExpressionTableName is the name the columns of a table are resolved against when compiling the expressions of
+// its expression indexes, which may only reference the columns of that table.
+const indexExpressionTableName = "dolt_index_table"
+
+func init() {
+	doltdb.IndexExpressionsFactory = func(idx schema.Index) (doltdb.IndexExpressions, error) {
+		return newIndexExpressions(idx)
+	}
+}
+
+// indexExpressions evaluates the expressions of an expression index against table rows.
+type indexExpressions struct {
+	idx    schema.Index
+	cols   []schema.Column
+	exprs  []sql.Expression
+	sqlCtx *sql.Context
+}
+
+var _ doltdb.IndexExpressions = (*indexExpressions)(nil)
+
+// newIndexExpressions compiles the expressions of the expression index given.
+func newIndexExpressions(idx schema.Index) (*indexExpressions, error) {
+	cols := make([]schema.Column, len(idx.IndexedColumnTags()))
+	for i, tag := range idx.IndexedColumnTags() {
+		var ok bool
+		cols[i], ok = idx.GetColumn(tag)
+		if !ok {
+			return nil, fmt.Errorf("index `%s` has column with tag `%d` which cannot be found", idx.Name(), tag)
+		}
+	}
+
+	exprStrs := make([]string, len(idx.Expressions()))
+	for i, expr := range idx.Expressions() {
+		exprStrs[i] = expr.Expression
+	}
+
+	exprs, err := compileExpressions(indexExpressionTableName, cols, exprStrs)
+	if err != nil {
+		return nil, fmt.Errorf("index `%s` has an invalid expression: %w", idx.Name(), err)
+	}
+
+	return &indexExpressions{idx: idx, cols: cols, exprs: exprs, sqlCtx: sql.NewEmptyContext()}, nil
+}
+
+// IndexRow implements doltdb.IndexExpressions.
+func (ie *indexExpressions) IndexRow(ctx context.Context, tblRow row.Row, idxSch schema.Schema) (row.Row, error) {
+	sqlCtx, ok := ctx.(*sql.Context)
+	if !ok {
+		sqlCtx = ie.sqlCtx
+	}
+
+	sqlRow := make(sql.Row, len(ie.cols))
+	for i, col := range ie.cols {
+		val, ok := tblRow.GetColVal(col.Tag)
+		if !ok {
+			continue
+		}
+		var err error
+		sqlRow[i], err = col.TypeInfo.ConvertNomsValueToValue(ctx, val)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	indexRow, err := tblRow.ReduceToIndex(ctx, ie.idx)
+	if err != nil {
+		return nil, err
+	}
+	for i, expr := range ie.exprs {
+		res, err := expr.Eval(sqlCtx, sqlRow)
+		if err != nil {
+			return nil, err
+		}
+		val, err := ie.idx.Expressions()[i].TypeInfo.ConvertValueToNomsValue(ctx, nil, res)
+		if err != nil {
+			return nil, err
+		}
+		indexRow, err = indexRow.SetColVal(schema.IndexExpressionTag(i), val, idxSch)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return indexRow, nil
+}
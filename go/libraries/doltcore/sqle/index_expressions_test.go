@@ -0,0 +1,74 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestIndexEditorExpressions(t *testing.T) {
+	format := types.Format_7_18
+	db, err := dbfactory.MemFactory{}.CreateDB(context.Background(), format, nil, nil)
+	require.NoError(t, err)
+	colColl, err := schema.NewColCollection(
+		schema.NewColumn("pk", 0, types.IntKind, true),
+		schema.NewColumn("email", 1, types.StringKind, false))
+	require.NoError(t, err)
+	tableSch, err := schema.SchemaFromCols(colColl)
+	require.NoError(t, err)
+	exprIndex, err := tableSch.Indexes().AddIndexByColNames("idx_lower_email", []string{"email"}, schema.IndexProperties{
+		Expressions: []schema.IndexExpression{{Expression: "lower(`email`)", TypeInfo: typeinfo.StringDefaultType}},
+	})
+	require.NoError(t, err)
+	emptyMap, err := types.NewMap(context.Background(), db)
+	require.NoError(t, err)
+
+	originalRow, err := row.New(format, tableSch, row.TaggedValues{
+		0: types.Int(1),
+		1: types.String("Alice@Example.com"),
+	})
+	require.NoError(t, err)
+	updatedRow, err := originalRow.SetColVal(1, types.String("Bob@Example.com"), tableSch)
+	require.NoError(t, err)
+
+	exprEditor := doltdb.NewIndexEditor(exprIndex, emptyMap)
+	require.NoError(t, exprEditor.UpdateExpressionIndex(context.Background(), nil, originalRow))
+	require.NoError(t, exprEditor.UpdateExpressionIndex(context.Background(), originalRow, updatedRow))
+	indexData, err := exprEditor.Map(context.Background())
+	require.NoError(t, err)
+
+	var vals []row.TaggedValues
+	err = indexData.IterAll(context.Background(), func(key, value types.Value) error {
+		dReadRow, err := row.FromNoms(exprIndex.Schema(), key.(types.Tuple), value.(types.Tuple))
+		require.NoError(t, err)
+		dReadVals, err := row.GetTaggedVals(dReadRow)
+		require.NoError(t, err)
+		vals = append(vals, dReadVals)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []row.TaggedValues{{schema.IndexExpressionTag(0): types.String("bob@example.com"), 0: types.Int(1)}}, vals)
+}
@@ -16,11 +16,15 @@ package sqle
 
 import (
 	"fmt"
+	"io"
 
 	"github.com/dolthub/go-mysql-server/sql"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
 )
 
 type IndexLookupKeyIterator interface {
@@ -31,6 +35,10 @@ type IndexLookupKeyIterator interface {
 type doltIndexLookup struct {
 	idx     DoltIndex
 	keyIter IndexLookupKeyIterator
+	// keyRange is the range of the index keys which are looked up, such as [1, 10)
+	keyRange indexRange
+	// unioned are the lookups whose keys are read by |keyIter|, when this lookup is their union
+	unioned []*doltIndexLookup
 }
 
 func (il *doltIndexLookup) String() string {
@@ -38,13 +46,95 @@ func (il *doltIndexLookup) String() string {
 	return fmt.Sprintf("doltIndexLookup:%s", il.idx.ID())
 }
 
-var _ sql.IndexLookup = (*doltIndexLookup)(nil)
+var _ sql.MergeableIndexLookup = (*doltIndexLookup)(nil)
 
 // RowIter returns a row iterator for this index lookup. The iterator will return the single matching row for the index.
 func (il *doltIndexLookup) RowIter(ctx *sql.Context) (sql.RowIter, error) {
+	for _, lookup := range il.lookups() {
+		recordDoltRead(ctx, "index "+lookup.idx.ID(), lookup.keyRange.String())
+	}
 	return &indexLookupRowIterAdapter{indexLookup: il, ctx: ctx}, nil
 }
 
+// IsMergeable implements sql.MergeableIndexLookup.  Lookups of the indexes of the same table can be merged.
+func (il *doltIndexLookup) IsMergeable(lookup sql.IndexLookup) bool {
+	other, ok := lookup.(*doltIndexLookup)
+	return ok && il.idx.Table() == other.idx.Table() && il.idx.TableData().Equals(other.idx.TableData())
+}
+
+// Intersection implements sql.MergeableIndexLookup.  Rather than reading the keys of every lookup, the lookup which is
+// estimated to read the fewest rows is returned, as the rows read are still filtered by the query.
+func (il *doltIndexLookup) Intersection(lookups ...sql.IndexLookup) (sql.IndexLookup, error) {
+	best, bestRows := il, il.estimateRows()
+	for _, lookup := range lookups {
+		other, ok := lookup.(*doltIndexLookup)
+		if !ok {
+			return nil, fmt.Errorf("Unrecognized indexLookup %T", lookup)
+		}
+
+		if rows := other.estimateRows(); rows < bestRows {
+			best, bestRows = other, rows
+		}
+	}
+
+	return best, nil
+}
+
+// Union implements sql.MergeableIndexLookup.  The keys of each lookup are read in turn, skipping the rows which have
+// already been read.
+func (il *doltIndexLookup) Union(lookups ...sql.IndexLookup) (sql.IndexLookup, error) {
+	unioned := il.lookups()
+	for _, lookup := range lookups {
+		other, ok := lookup.(*doltIndexLookup)
+		if !ok {
+			return nil, fmt.Errorf("Unrecognized indexLookup %T", lookup)
+		}
+		unioned = append(unioned, other.lookups()...)
+	}
+
+	iters := make([]IndexLookupKeyIterator, len(unioned))
+	for i, lookup := range unioned {
+		iters[i] = lookup.keyIter
+	}
+
+	return &doltIndexLookup{
+		idx: il.idx,
+		keyIter: &unionKeyIter{
+			iters:  iters,
+			nbf:    il.idx.TableData().Format(),
+			pkCols: il.idx.Schema().GetPKCols(),
+			seen:   make(map[hash.Hash]struct{}),
+		},
+		unioned: unioned,
+	}, nil
+}
+
+// lookups returns the lookups whose keys are read by this lookup.
+func (il *doltIndexLookup) lookups() []*doltIndexLookup {
+	if il.unioned != nil {
+		return il.unioned
+	}
+	return []*doltIndexLookup{il}
+}
+
+// estimateRows returns the estimated number of rows read by this lookup.
+func (il *doltIndexLookup) estimateRows() float64 {
+	var rows float64
+	for _, lookup := range il.lookups() {
+		rows += lookup.idx.estimateRows(lookup.keyRange)
+	}
+	return rows
+}
+
+// preferFullScan returns whether scanning the whole table is estimated to be cheaper than reading the rows of this
+// lookup, which is only estimated for tables whose statistics have been collected by ANALYZE TABLE.
+func (il *doltIndexLookup) preferFullScan() bool {
+	if !il.idx.hasStatistics() {
+		return false
+	}
+	return il.estimateRows()*indexReadCost > float64(il.idx.TableData().Len())
+}
+
 type doltIndexKeyIter struct {
 	indexMapIter table.TableReadCloser
 }
@@ -58,3 +148,42 @@ func (iter *doltIndexKeyIter) NextKey(ctx *sql.Context) (row.TaggedValues, error
 	}
 	return row.GetTaggedVals(indexRow)
 }
+
+// unionKeyIter reads the keys of several lookups in turn, skipping the keys of rows which have already been read.
+type unionKeyIter struct {
+	iters  []IndexLookupKeyIterator
+	nbf    *types.NomsBinFormat
+	pkCols *schema.ColCollection
+	seen   map[hash.Hash]struct{}
+}
+
+var _ IndexLookupKeyIterator = (*unionKeyIter)(nil)
+
+func (iter *unionKeyIter) NextKey(ctx *sql.Context) (row.TaggedValues, error) {
+	for len(iter.iters) > 0 {
+		key, err := iter.iters[0].NextKey(ctx)
+		if err == io.EOF {
+			iter.iters = iter.iters[1:]
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		pk := key.NomsTupleForPKCols(iter.nbf, iter.pkCols)
+		pkVal, err := pk.Value(ctx)
+		if err != nil {
+			return nil, err
+		}
+		h, err := pkVal.Hash(iter.nbf)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := iter.seen[h]; !ok {
+			iter.seen[h] = struct{}{}
+			return key, nil
+		}
+	}
+
+	return nil, io.EOF
+}
@@ -49,12 +49,14 @@ func (i *indexLookupRowIterAdapter) Next() (sql.Row, error) {
 		return nil, io.EOF
 	}
 
+	countRowExamined(i.ctx)
+
 	r, err := row.FromNoms(i.indexLookup.idx.Schema(), pkTupleVal.(types.Tuple), fieldsVal.(types.Tuple))
 	if err != nil {
 		return nil, err
 	}
 
-	return doltRowToSqlRow(r, i.indexLookup.idx.Schema())
+	return doltRowToSqlRow(i.ctx, r, i.indexLookup.idx.Schema())
 }
 
 func (*indexLookupRowIterAdapter) Close() error {
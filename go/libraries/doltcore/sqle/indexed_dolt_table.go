@@ -16,6 +16,8 @@ package sqle
 
 import (
 	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 )
 
 // IndexedDoltTable is a wrapper for a DoltTable and a doltIndexLookup. It implements the sql.Table interface like
@@ -45,6 +47,10 @@ func (idt *IndexedDoltTable) String() string {
 	return idt.table.String()
 }
 
+func (idt *IndexedDoltTable) doltDB(ctx *sql.Context) (*doltdb.DoltDB, bool) {
+	return idt.table.doltDB(ctx)
+}
+
 func (idt *IndexedDoltTable) Schema() sql.Schema {
 	return idt.table.Schema()
 }
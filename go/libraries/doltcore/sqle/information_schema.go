@@ -0,0 +1,200 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/information_schema"
+)
+
+// tableRowsIdx is the index of the table_rows column in the information_schema.tables table.
+const tableRowsIdx = 7
+
+// NewInformationSchemaDatabase returns the information_schema database of |cat|, with the statistics collected by
+// ANALYZE TABLE for the tables of Dolt databases in its column_statistics table and the table_rows column of its
+// tables table.
+func NewInformationSchemaDatabase(cat *sql.Catalog) sql.Database {
+	return &informationSchemaDatabase{
+		Database: information_schema.NewInformationSchemaDatabase(cat),
+		catalog:  cat,
+	}
+}
+
+type informationSchemaDatabase struct {
+	sql.Database
+	catalog *sql.Catalog
+}
+
+var _ sql.Database = (*informationSchemaDatabase)(nil)
+
+// GetTableInsensitive implements sql.Database
+func (db *informationSchemaDatabase) GetTableInsensitive(ctx *sql.Context, tblName string) (sql.Table, bool, error) {
+	tbl, ok, err := db.Database.GetTableInsensitive(ctx, tblName)
+	if err != nil || !ok {
+		return tbl, ok, err
+	}
+
+	switch strings.ToLower(tblName) {
+	case information_schema.ColumnStatisticsTableName:
+		return &columnStatisticsTable{Table: tbl, catalog: db.catalog}, true, nil
+	case information_schema.TablesTableName:
+		return &tablesTable{Table: tbl, catalog: db.catalog}, true, nil
+	}
+
+	return tbl, true, nil
+}
+
+// columnStatisticsTable is the information_schema.column_statistics table, which has the histograms of the columns
+// of the analyzed tables of Dolt databases.
+type columnStatisticsTable struct {
+	sql.Table
+	catalog *sql.Catalog
+}
+
+// PartitionRows implements sql.Table
+func (t *columnStatisticsTable) PartitionRows(ctx *sql.Context, _ sql.Partition) (sql.RowIter, error) {
+	var rows []sql.Row
+	for _, sqlDB := range t.catalog.AllDatabases() {
+		db, ok := sqlDB.(Database)
+		if !ok {
+			continue
+		}
+
+		stats, err := readDatabaseStatistics(ctx, db)
+		if err != nil {
+			return nil, err
+		}
+
+		for tableName, colStats := range stats {
+			for _, cs := range colStats {
+				if cs.histogram == nil {
+					continue
+				}
+
+				h, err := json.Marshal(cs.histogram)
+				if err != nil {
+					return nil, err
+				}
+
+				rows = append(rows, sql.NewRow(db.Name(), tableName, cs.column, h))
+			}
+		}
+	}
+
+	return sql.RowsToRowIter(rows...), nil
+}
+
+// tablesTable is the information_schema.tables table, with the row counts of the analyzed tables of Dolt databases
+// in its table_rows column.
+type tablesTable struct {
+	sql.Table
+	catalog *sql.Catalog
+}
+
+// PartitionRows implements sql.Table
+func (t *tablesTable) PartitionRows(ctx *sql.Context, partition sql.Partition) (sql.RowIter, error) {
+	iter, err := t.Table.PartitionRows(ctx, partition)
+	if err != nil {
+		return nil, err
+	}
+
+	rowCounts := make(map[string]map[string]uint64)
+	for _, sqlDB := range t.catalog.AllDatabases() {
+		db, ok := sqlDB.(Database)
+		if !ok {
+			continue
+		}
+
+		stats, err := readDatabaseStatistics(ctx, db)
+		if err != nil {
+			iter.Close()
+			return nil, err
+		}
+
+		counts := make(map[string]uint64)
+		for tableName, colStats := range stats {
+			if len(colStats) > 0 {
+				counts[strings.ToLower(tableName)] = colStats[0].rowCount
+			}
+		}
+		rowCounts[strings.ToLower(db.Name())] = counts
+	}
+
+	return &tablesRowIter{iter: iter, rowCounts: rowCounts}, nil
+}
+
+type tablesRowIter struct {
+	iter      sql.RowIter
+	rowCounts map[string]map[string]uint64
+}
+
+// Next implements sql.RowIter
+func (itr *tablesRowIter) Next() (sql.Row, error) {
+	r, err := itr.iter.Next()
+	if err != nil {
+		return nil, err
+	}
+
+	dbName, _ := r[1].(string)
+	tableName, _ := r[2].(string)
+	if count, ok := itr.rowCounts[strings.ToLower(dbName)][strings.ToLower(tableName)]; ok {
+		r = r.Copy()
+		r[tableRowsIdx] = count
+	}
+
+	return r, nil
+}
+
+// Close implements sql.RowIter
+func (itr *tablesRowIter) Close() error {
+	return itr.iter.Close()
+}
+
+// readDatabaseStatistics returns the statistics of the tables of |db| which exist in its working root, keyed by the
+// names of the tables.
+func readDatabaseStatistics(ctx *sql.Context, db Database) (map[string][]*columnStatistics, error) {
+	root, err := db.GetRoot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tableNames, err := root.GetTableNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]string, len(tableNames))
+	for _, name := range tableNames {
+		names[strings.ToLower(name)] = name
+	}
+
+	stats, err := db.stats.read(func(tableName string) bool {
+		_, ok := names[tableName]
+		return ok
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string][]*columnStatistics, len(stats))
+	for lwr, colStats := range stats {
+		byName[names[lwr]] = colStats
+	}
+
+	return byName, nil
+}
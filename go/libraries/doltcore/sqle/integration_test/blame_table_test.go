@@ -0,0 +1,110 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/commands"
+	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+)
+
+func TestBlameTable(t *testing.T) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+
+	setup := []testCommand{
+		{commands.SqlCmd{}, []string{"-q", "create table test (pk int not null primary key, c0 int, c1 int);"}},
+		{commands.AddCmd{}, []string{"."}},
+		{commands.CommitCmd{}, []string{"-m", "first"}},
+		{commands.SqlCmd{}, []string{"-q", "insert into test values (0,0,0), (1,1,1), (2,2,2);"}},
+		{commands.AddCmd{}, []string{"."}},
+		{commands.CommitCmd{}, []string{"-m", "second"}},
+		{commands.SqlCmd{}, []string{"-q", "update test set c0 = 10 where pk = 1;"}},
+		{commands.AddCmd{}, []string{"."}},
+		{commands.CommitCmd{}, []string{"-m", "third"}},
+		{commands.SqlCmd{}, []string{"-q", "update test set c1 = 20 where pk = 2;"}},
+		{commands.AddCmd{}, []string{"."}},
+		{commands.CommitCmd{}, []string{"-m", "fourth"}},
+		// uncommitted changes aren't blamed
+		{commands.SqlCmd{}, []string{"-q", "insert into test values (3,3,3);"}},
+	}
+	for _, c := range setup {
+		exitCode := c.cmd.Exec(ctx, c.cmd.Name(), c.args, dEnv)
+		require.Equal(t, 0, exitCode)
+	}
+
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+
+	rows, err := sqle.ExecuteSelect(dEnv, dEnv.DoltDB, root, "select commit_hash from dolt_log order by date desc;")
+	require.NoError(t, err)
+	require.Equal(t, 5, len(rows))
+	head, head1, head2 := rows[0][0].(string), rows[1][0].(string), rows[2][0].(string)
+
+	tests := []struct {
+		name  string
+		query string
+		rows  []sql.Row
+	}{
+		{
+			name:  "select rows and column blame",
+			query: "select pk, commit_hash, message, c0_commit_hash, c1_commit_hash from dolt_blame_test",
+			rows: []sql.Row{
+				{int32(0), head2, "second", head2, head2},
+				{int32(1), head1, "third", head1, head2},
+				{int32(2), head, "fourth", head2, head},
+			},
+		},
+		{
+			name:  "filter on the primary key",
+			query: "select pk, committer, message from dolt_blame_test where pk >= 1 and pk < 2",
+			rows: []sql.Row{
+				{int32(1), "billy bob", "third"},
+			},
+		},
+		{
+			name:  "filter on the commit",
+			query: "select pk from dolt_blame_test where message = 'second'",
+			rows: []sql.Row{
+				{int32(0)},
+			},
+		},
+		{
+			name:  "join with the log",
+			query: "select b.pk, l.message from dolt_blame_test b join dolt_log l on b.c1_commit_hash = l.commit_hash where b.pk = 2",
+			rows: []sql.Row{
+				{int32(2), "fourth"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actRows, err := sqle.ExecuteSelect(dEnv, dEnv.DoltDB, root, test.query)
+			require.NoError(t, err)
+			assert.Equal(t, test.rows, actRows)
+		})
+	}
+
+	_, err = sqle.ExecuteSelect(dEnv, dEnv.DoltDB, root, "select * from dolt_blame_missing")
+	assert.Error(t, err)
+}
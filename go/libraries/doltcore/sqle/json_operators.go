@@ -0,0 +1,75 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"strings"
+
+	"github.com/dolthub/vitess/go/vt/sqlparser"
+)
+
+// RewriteJSONOperators rewrites the column->'path' and column->>'path' operators in the query given, which the engine
+// doesn't support, as the JSON_EXTRACT and JSON_UNQUOTE(JSON_EXTRACT) calls they are shorthand for. Queries without
+// these operators are returned unchanged.
+func RewriteJSONOperators(query string) string {
+	if !strings.Contains(query, "->") {
+		return query
+	}
+
+	p, ok := newDDLParser(query)
+	if !ok {
+		return query
+	}
+
+	starts := p.tokenStarts()
+	var sb strings.Builder
+	pos := 0
+	for i, tok := range p.toks {
+		if tok.typ != sqlparser.JSON_EXTRACT_OP && tok.typ != sqlparser.JSON_UNQUOTE_EXTRACT_OP {
+			continue
+		}
+		if i == 0 || p.tok(i-1).typ != sqlparser.ID || p.tok(i+1).typ != sqlparser.STRING {
+			// leave reporting the error to the parser
+			return query
+		}
+
+		// the column may be qualified by its table and database
+		first := i - 1
+		for first >= 2 && p.tok(first-1).typ == '.' && p.tok(first-2).typ == sqlparser.ID {
+			first -= 2
+		}
+		if starts[first] < pos {
+			return query
+		}
+
+		col := query[starts[first]:p.tok(i-1).end]
+		path := query[starts[i+1]:p.tok(i+1).end]
+
+		sb.WriteString(query[pos:starts[first]])
+		if tok.typ == sqlparser.JSON_UNQUOTE_EXTRACT_OP {
+			sb.WriteString("JSON_UNQUOTE(JSON_EXTRACT(" + col + ", " + path + "))")
+		} else {
+			sb.WriteString("JSON_EXTRACT(" + col + ", " + path + ")")
+		}
+		pos = p.tok(i + 1).end
+	}
+
+	if pos == 0 {
+		return query
+	}
+
+	sb.WriteString(query[pos:])
+	return sb.String()
+}
@@ -0,0 +1,59 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRewriteJSONOperators(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{
+			query:    "SELECT pk FROM test",
+			expected: "SELECT pk FROM test",
+		},
+		{
+			query:    "SELECT doc->'$.a' FROM test",
+			expected: "SELECT JSON_EXTRACT(doc, '$.a') FROM test",
+		},
+		{
+			query:    `SELECT pk, doc ->> "$.b[0]" AS b FROM test WHERE doc->'$.a' = 1`,
+			expected: `SELECT pk, JSON_UNQUOTE(JSON_EXTRACT(doc, "$.b[0]")) AS b FROM test WHERE JSON_EXTRACT(doc, '$.a') = 1`,
+		},
+		{
+			query:    "SELECT t.`my doc`->'$.a' FROM db.test t",
+			expected: "SELECT JSON_EXTRACT(t.`my doc`, '$.a') FROM db.test t",
+		},
+		{
+			query:    "SELECT '->' FROM test",
+			expected: "SELECT '->' FROM test",
+		},
+		{
+			query:    "SELECT 1->'$.a'",
+			expected: "SELECT 1->'$.a'",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			assert.Equal(t, test.expected, RewriteJSONOperators(test.query))
+		})
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"strings"
+
+	"github.com/dolthub/vitess/go/vt/sqlparser"
+)
+
+// MatchAgainstFuncName is the name of the function MATCH ... AGAINST expressions are rewritten as. It's an
+// implementation detail of RewriteMatchAgainst, and isn't meant to be called directly.
+const MatchAgainstFuncName = "match_against"
+
+// NaturalLanguageMode is the search modifier of MATCH ... AGAINST expressions without one.
+const NaturalLanguageMode = "IN NATURAL LANGUAGE MODE"
+
+// searchModifiers are the search modifiers of MATCH ... AGAINST expressions.
+var searchModifiers = map[string]bool{
+	NaturalLanguageMode:                           true,
+	NaturalLanguageMode + " WITH QUERY EXPANSION": true,
+	"IN BOOLEAN MODE":                             true,
+	"WITH QUERY EXPANSION":                        true,
+}
+
+// RewriteMatchAgainst rewrites the MATCH (col1, col2, ...) AGAINST (search [modifier]) expressions in the query given,
+// which the engine doesn't support, as MATCH_AGAINST(col1, col2, ..., search, 'modifier') calls. Queries without these
+// expressions are returned unchanged.
+func RewriteMatchAgainst(query string) string {
+	if !strings.Contains(strings.ToLower(query), "against") {
+		return query
+	}
+
+	p, ok := newDDLParser(query)
+	if !ok {
+		return query
+	}
+
+	starts := p.tokenStarts()
+
+	var sb strings.Builder
+	pos := 0
+	for i, tok := range p.toks {
+		if tok.typ != sqlparser.MATCH {
+			continue
+		}
+		if starts[i] < pos {
+			// nested in the search of another MATCH
+			return query
+		}
+
+		// the columns may be qualified by their table and database
+		j := i + 1
+		if p.tok(j).typ != '(' {
+			return query
+		}
+		for j++; ; j++ {
+			if p.tok(j).typ != sqlparser.ID {
+				// leave reporting the error to the parser
+				return query
+			}
+			for p.tok(j+1).typ == '.' && p.tok(j+2).typ == sqlparser.ID {
+				j += 2
+			}
+			if p.tok(j+1).typ != ',' {
+				break
+			}
+			j++
+		}
+		colsEnd := p.tok(j).end
+		if p.tok(j+1).typ != ')' || p.tok(j+2).typ != sqlparser.AGAINST || p.tok(j+3).typ != '(' {
+			return query
+		}
+
+		// the search ends with the first IN or WITH outside of parentheses, which starts the search modifier
+		searchStart := j + 4
+		k := searchStart
+		for depth := 0; k < len(p.toks); k++ {
+			t := p.tok(k)
+			if t.typ == '(' {
+				depth++
+			} else if t.typ == ')' {
+				if depth == 0 {
+					break
+				}
+				depth--
+			} else if depth == 0 && (t.typ == sqlparser.IN || t.typ == sqlparser.WITH) {
+				break
+			}
+		}
+		if k == searchStart {
+			return query
+		}
+		searchEnd := p.tok(k - 1).end
+
+		modifier := NaturalLanguageMode
+		if p.tok(k).typ != ')' {
+			var words []string
+			for ; k < len(p.toks) && p.tok(k).typ != ')'; k++ {
+				words = append(words, strings.ToUpper(p.tok(k).val))
+			}
+			modifier = strings.Join(words, " ")
+			if !searchModifiers[modifier] {
+				return query
+			}
+		}
+		if p.tok(k).typ != ')' {
+			return query
+		}
+
+		sb.WriteString(query[pos:starts[i]])
+		sb.WriteString(strings.ToUpper(MatchAgainstFuncName) + "(")
+		sb.WriteString(query[starts[i+2]:colsEnd])
+		sb.WriteString(", ")
+		sb.WriteString(query[starts[searchStart]:searchEnd])
+		sb.WriteString(", '" + modifier + "')")
+		pos = p.tok(k).end
+	}
+
+	if pos == 0 {
+		return query
+	}
+
+	sb.WriteString(query[pos:])
+	return sb.String()
+}
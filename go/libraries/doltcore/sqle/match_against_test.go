@@ -0,0 +1,63 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRewriteMatchAgainst(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{
+			query:    "SELECT pk FROM articles",
+			expected: "SELECT pk FROM articles",
+		},
+		{
+			query:    "SELECT pk FROM articles WHERE MATCH (title, body) AGAINST ('database')",
+			expected: "SELECT pk FROM articles WHERE MATCH_AGAINST(title, body, 'database', 'IN NATURAL LANGUAGE MODE')",
+		},
+		{
+			query:    "SELECT match(a.title)against(\"db\" in natural language mode) AS score FROM articles a ORDER BY score",
+			expected: "SELECT MATCH_AGAINST(a.title, \"db\", 'IN NATURAL LANGUAGE MODE') AS score FROM articles a ORDER BY score",
+		},
+		{
+			query:    "SELECT MATCH (title) AGAINST (CONCAT('a', 'b') IN BOOLEAN MODE), MATCH (body) AGAINST ('c' WITH QUERY EXPANSION)",
+			expected: "SELECT MATCH_AGAINST(title, CONCAT('a', 'b'), 'IN BOOLEAN MODE'), MATCH_AGAINST(body, 'c', 'WITH QUERY EXPANSION')",
+		},
+		{
+			query:    "SELECT 'MATCH (title) AGAINST (x)' FROM articles",
+			expected: "SELECT 'MATCH (title) AGAINST (x)' FROM articles",
+		},
+		{
+			query:    "SELECT MATCH (title) AGAINST ('a' IN SOME MODE)",
+			expected: "SELECT MATCH (title) AGAINST ('a' IN SOME MODE)",
+		},
+		{
+			query:    "SELECT MATCH (1) AGAINST ('a')",
+			expected: "SELECT MATCH (1) AGAINST ('a')",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			assert.Equal(t, test.expected, RewriteMatchAgainst(test.query))
+		})
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package privileges
+
+import (
+	"net"
+
+	"github.com/dolthub/go-mysql-server/auth"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/vitess/go/mysql"
+)
+
+// Auth is an auth.Auth which authenticates the users of a Store.  Privileges are checked per statement by the
+// server's handler, so Allowed only enforces a read only server.
+type Auth struct {
+	store    *Store
+	readOnly bool
+}
+
+var _ auth.Auth = (*Auth)(nil)
+
+// NewAuth returns an Auth for the users of |store|.  If |readOnly| is true, no user may write.
+func NewAuth(store *Store, readOnly bool) *Auth {
+	return &Auth{store: store, readOnly: readOnly}
+}
+
+// Mysql implements auth.Auth.
+func (a *Auth) Mysql() mysql.AuthServer {
+	return storeAuthServer{a.store}
+}
+
+// Allowed implements auth.Auth.
+func (a *Auth) Allowed(ctx *sql.Context, permission auth.Permission) error {
+	if a.readOnly && permission&auth.WritePerm != 0 {
+		return auth.ErrNotAuthorized.Wrap(auth.ErrNoPermission.New(auth.WritePerm))
+	}
+	return nil
+}
+
+// storeAuthServer is a mysql.AuthServer which validates the mysql_native_password of the users of a Store.  Users
+// can be created and altered while the server runs, so each call is delegated to a static auth server holding the
+// current password of the user.
+type storeAuthServer struct {
+	store *Store
+}
+
+func (s storeAuthServer) staticServer(user string) *mysql.AuthServerStatic {
+	static := mysql.NewAuthServerStatic()
+	if u, ok := s.store.User(user); ok {
+		static.Entries[u.Name] = []*mysql.AuthServerStaticEntry{{MysqlNativePassword: u.Password, Password: u.Password}}
+	}
+	return static
+}
+
+func (s storeAuthServer) AuthMethod(user string) (string, error) {
+	return s.staticServer(user).AuthMethod(user)
+}
+
+func (s storeAuthServer) Salt() ([]byte, error) {
+	return mysql.NewSalt()
+}
+
+func (s storeAuthServer) ValidateHash(salt []byte, user string, authResponse []byte, remoteAddr net.Addr) (mysql.Getter, error) {
+	return s.staticServer(user).ValidateHash(salt, user, authResponse, remoteAddr)
+}
+
+func (s storeAuthServer) Negotiate(c *mysql.Conn, user string, remoteAddr net.Addr) (mysql.Getter, error) {
+	return s.staticServer(user).Negotiate(c, user, remoteAddr)
+}
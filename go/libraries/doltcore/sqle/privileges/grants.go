@@ -0,0 +1,340 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package privileges
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AllObjects is the name used for all databases or all tables of a privilege level, as in GRANT ... ON *.*
+const AllObjects = "*"
+
+// branchSeparator separates the database and branch of a branch database name, like "mydb/feature".
+const branchSeparator = "/"
+
+// Grant is a set of privileges at a privilege level.  The level is global when both Database and Table are
+// AllObjects, a database level when only Table is AllObjects, and a table level otherwise.
+type Grant struct {
+	// Database is a database name, which may name a branch database like "mydb/feature" and may contain the wildcards %
+	// and _, or AllObjects.
+	Database string `json:"database"`
+	// Table is a table name or AllObjects.
+	Table      string    `json:"table"`
+	Privileges Privilege `json:"privileges"`
+}
+
+// NewGlobalGrant returns a Grant of |privs| on all databases.
+func NewGlobalGrant(privs Privilege) Grant {
+	return Grant{Database: AllObjects, Table: AllObjects, Privileges: privs}
+}
+
+// sameLevel returns whether |g| and |other| are at the same privilege level.
+func (g Grant) sameLevel(other Grant) bool {
+	return strings.EqualFold(g.Database, other.Database) && strings.EqualFold(g.Table, other.Table)
+}
+
+// rank orders privilege levels from the least specific, global, to the most specific, table.
+func (g Grant) rank() int {
+	switch {
+	case g.Database == AllObjects:
+		return 0
+	case g.Table == AllObjects:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// IsGlobal returns whether the level of |g| is all databases.
+func (g Grant) IsGlobal() bool {
+	return g.rank() == 0
+}
+
+// Object returns the privilege level as it appears in a GRANT statement, like *.* or `mydb`.*
+func (g Grant) Object() string {
+	if g.IsGlobal() {
+		return "*.*"
+	}
+
+	db := quoteIdentifier(g.Database)
+	if g.Table == AllObjects {
+		return db + ".*"
+	}
+
+	return db + "." + quoteIdentifier(g.Table)
+}
+
+func (g Grant) privilegesString() string {
+	if !g.IsGlobal() && g.Privileges&^GrantOptionPriv == AllPrivileges&^GlobalOnlyPrivileges {
+		return "ALL PRIVILEGES"
+	}
+	return g.Privileges.String()
+}
+
+// covers returns whether the level of |g| includes |t|.
+func (g Grant) covers(t Target) bool {
+	if g.IsGlobal() {
+		return true
+	} else if t.Database == AllObjects {
+		return false
+	}
+
+	if !matchesPattern(g.Database, t.Database) && (t.Branch == "" || !matchesPattern(g.Database, t.Database+branchSeparator+t.Branch)) {
+		return false
+	}
+
+	return g.Table == AllObjects || (t.Table != "" && strings.EqualFold(g.Table, t.Table))
+}
+
+// Target is an object privileges are required on.  A Target with an empty Table requires privileges on the database
+// itself, and a Target with an AllObjects Database requires global privileges.
+type Target struct {
+	// Database is the name of a database, without any branch.
+	Database string
+	// Branch is the branch of the database which is accessed, if it is known.
+	Branch string
+	Table  string
+}
+
+// NewTarget returns a Target for the table |table| of the database named |dbName|.  If |dbName| names a branch
+// database, like "mydb/feature", the branch is taken from the name, otherwise it is |branch|.
+func NewTarget(dbName, branch, table string) Target {
+	if i := strings.Index(dbName, branchSeparator); i >= 0 {
+		dbName, branch = dbName[:i], dbName[i+1:]
+	}
+
+	return Target{Database: dbName, Branch: branch, Table: table}
+}
+
+// levelTarget returns the Target for the privilege level of |g|, treating any wildcards in it literally.
+func levelTarget(g Grant) Target {
+	if g.IsGlobal() {
+		return Target{Database: AllObjects}
+	}
+
+	table := g.Table
+	if table == AllObjects {
+		table = ""
+	}
+
+	return NewTarget(g.Database, "", table)
+}
+
+// String returns the target as it appears in an error message.
+func (t Target) String() string {
+	name := t.Database
+	if t.Branch != "" {
+		name += branchSeparator + t.Branch
+	}
+
+	if t.Table != "" {
+		name += "." + t.Table
+	}
+
+	return name
+}
+
+// User is a user account of the server, and the privileges granted to it.
+type User struct {
+	Name string `json:"name"`
+	// Password is the mysql_native_password hash of the user's password, or empty if it has none.
+	Password string  `json:"password"`
+	Grants   []Grant `json:"grants,omitempty"`
+	// Restrictions are privileges revoked at a level more specific than the level they are granted at, like a REVOKE
+	// on a single branch of a database the privileges are granted on.
+	Restrictions []Grant `json:"restrictions,omitempty"`
+
+	// static users are defined by the server config, and can't be altered by SQL statements.
+	static bool
+}
+
+// PrivilegesOn returns the privileges |u| has on |t|.  A privilege is held if the most specific level which grants it
+// is more specific than any level which restricts it.
+func (u *User) PrivilegesOn(t Target) Privilege {
+	held := NoPrivileges
+	for _, pn := range privilegeNames {
+		grantRank, restrictRank := -1, -1
+		for _, g := range u.Grants {
+			if g.Privileges.Has(pn.priv) && g.rank() > grantRank && g.covers(t) {
+				grantRank = g.rank()
+			}
+		}
+
+		for _, r := range u.Restrictions {
+			if r.Privileges.Has(pn.priv) && r.rank() > restrictRank && r.covers(t) {
+				restrictRank = r.rank()
+			}
+		}
+
+		if grantRank > restrictRank {
+			held |= pn.priv
+		}
+	}
+
+	return held
+}
+
+// HasAnyPrivilegeIn returns whether |u| has any privilege on the database |t| or on any of its tables, as required to
+// USE a database.
+func (u *User) HasAnyPrivilegeIn(t Target) bool {
+	if u.PrivilegesOn(t) != NoPrivileges {
+		return true
+	}
+
+	for _, g := range u.Grants {
+		if g.Table != AllObjects && g.Privileges != NoPrivileges && g.covers(Target{Database: t.Database, Branch: t.Branch, Table: g.Table}) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// grant adds |privs| at the level of |level| to the privileges of |u|, removing any restrictions of them at that
+// level.
+func (u *User) grant(privs Privilege, level Grant) {
+	level.Privileges = privs
+	u.Restrictions = removePrivileges(u.Restrictions, level)
+
+	for i := range u.Grants {
+		if u.Grants[i].sameLevel(level) {
+			u.Grants[i].Privileges |= privs
+			return
+		}
+	}
+
+	u.Grants = append(u.Grants, level)
+}
+
+// revoke removes |privs| at the level of |level| from the privileges of |u|.  Privileges granted at the level are
+// removed, and privileges held at the level through a less specific grant are restricted.  It returns false if |u|
+// holds none of |privs| at the level.
+func (u *User) revoke(privs Privilege, level Grant) bool {
+	level.Privileges = privs
+	held := u.PrivilegesOn(levelTarget(level)) & privs
+
+	removed := NoPrivileges
+	for _, g := range u.Grants {
+		if g.sameLevel(level) {
+			removed = g.Privileges & privs
+		}
+	}
+	u.Grants = removePrivileges(u.Grants, level)
+
+	restricted := u.PrivilegesOn(levelTarget(level)) & privs
+	if restricted != NoPrivileges {
+		for i := range u.Restrictions {
+			if u.Restrictions[i].sameLevel(level) {
+				u.Restrictions[i].Privileges |= restricted
+				restricted = NoPrivileges
+			}
+		}
+
+		if restricted != NoPrivileges {
+			level.Privileges = restricted
+			u.Restrictions = append(u.Restrictions, level)
+		}
+	}
+
+	return held != NoPrivileges || removed != NoPrivileges
+}
+
+// removePrivileges removes the privileges of |level| from the grant at the same level in |grants|, dropping it if no
+// privileges are left.
+func removePrivileges(grants []Grant, level Grant) []Grant {
+	var result []Grant
+	for _, g := range grants {
+		if g.sameLevel(level) {
+			g.Privileges &^= level.Privileges
+			if g.Privileges == NoPrivileges {
+				continue
+			}
+		}
+		result = append(result, g)
+	}
+
+	return result
+}
+
+// GrantStatements returns the GRANT and REVOKE statements which give |u| its privileges, as SHOW GRANTS lists them.
+func (u *User) GrantStatements() []string {
+	account := fmt.Sprintf("%s@`%%`", quoteIdentifier(u.Name))
+
+	global := Grant{Database: AllObjects, Table: AllObjects}
+	for _, g := range u.Grants {
+		if g.IsGlobal() {
+			global = g
+		}
+	}
+
+	stmts := []string{grantStatement(global, account)}
+	for _, rank := range []int{1, 2} {
+		for _, g := range u.Grants {
+			if g.rank() == rank {
+				stmts = append(stmts, grantStatement(g, account))
+			}
+		}
+	}
+
+	for _, r := range u.Restrictions {
+		stmts = append(stmts, fmt.Sprintf("REVOKE %s ON %s FROM %s", r.privilegesString(), r.Object(), account))
+	}
+
+	return stmts
+}
+
+func grantStatement(g Grant, account string) string {
+	stmt := fmt.Sprintf("GRANT %s ON %s TO %s", g.privilegesString(), g.Object(), account)
+	if g.Privileges.Has(GrantOptionPriv) {
+		stmt += " WITH GRANT OPTION"
+	}
+	return stmt
+}
+
+func quoteIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// matchesPattern returns whether |name| matches |pattern|, in which % matches any sequence of characters and _
+// matches any one character, unless escaped with a backslash.  Matching ignores case.
+func matchesPattern(pattern, name string) bool {
+	p, n := []rune(strings.ToLower(pattern)), []rune(strings.ToLower(name))
+	for len(p) > 0 {
+		switch {
+		case p[0] == '%':
+			for i := len(n); i >= 0; i-- {
+				if matchesPattern(string(p[1:]), string(n[i:])) {
+					return true
+				}
+			}
+			return false
+		case len(n) == 0:
+			return false
+		case p[0] == '_':
+		case p[0] == '\\' && len(p) > 1:
+			p = p[1:]
+			if p[0] != n[0] {
+				return false
+			}
+		case p[0] != n[0]:
+			return false
+		}
+
+		p, n = p[1:], n[1:]
+	}
+
+	return len(n) == 0
+}
@@ -0,0 +1,124 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package privileges
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+)
+
+func TestMatchesPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		matches bool
+	}{
+		{"mydb", "mydb", true},
+		{"mydb", "MyDB", true},
+		{"mydb", "mydb2", false},
+		{"mydb/%", "mydb/feature", true},
+		{"mydb/%", "mydb", false},
+		{"my_db", "myxdb", true},
+		{`my\_db`, "myxdb", false},
+		{`my\_db`, "my_db", true},
+		{"%", "", true},
+		{"m%b", "mydb", true},
+		{"m%c", "mydb", false},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.matches, matchesPattern(test.pattern, test.name), "%s matching %s", test.pattern, test.name)
+	}
+}
+
+func TestPrivilegesOn(t *testing.T) {
+	u := &User{Name: "bob"}
+	u.grant(SelectPriv, Grant{Database: "mydb", Table: AllObjects})
+	u.grant(InsertPriv|UpdatePriv, Grant{Database: "mydb/%", Table: AllObjects})
+	u.grant(DeletePriv, Grant{Database: "other", Table: "t"})
+
+	assert.Equal(t, SelectPriv, u.PrivilegesOn(NewTarget("mydb", "", "t")))
+	assert.Equal(t, SelectPriv|InsertPriv|UpdatePriv, u.PrivilegesOn(NewTarget("mydb", "main", "t")))
+	assert.Equal(t, SelectPriv|InsertPriv|UpdatePriv, u.PrivilegesOn(NewTarget("mydb/feature", "", "t")))
+	assert.Equal(t, DeletePriv, u.PrivilegesOn(NewTarget("other", "main", "t")))
+	assert.Equal(t, NoPrivileges, u.PrivilegesOn(NewTarget("other", "main", "t2")))
+	assert.Equal(t, NoPrivileges, u.PrivilegesOn(NewTarget("other", "main", "")))
+	assert.True(t, u.HasAnyPrivilegeIn(NewTarget("other", "main", "")))
+	assert.False(t, u.HasAnyPrivilegeIn(NewTarget("third", "main", "")))
+
+	// revoking from one branch of a database the privileges are granted on restricts them on that branch only
+	assert.True(t, u.revoke(InsertPriv|UpdatePriv, Grant{Database: "mydb/main", Table: AllObjects}))
+	assert.Equal(t, SelectPriv, u.PrivilegesOn(NewTarget("mydb", "main", "t")))
+	assert.Equal(t, SelectPriv|InsertPriv|UpdatePriv, u.PrivilegesOn(NewTarget("mydb", "feature", "t")))
+
+	// a table level grant is more specific than the restriction
+	u.grant(UpdatePriv, Grant{Database: "mydb/main", Table: "t"})
+	assert.Equal(t, SelectPriv|UpdatePriv, u.PrivilegesOn(NewTarget("mydb", "main", "t")))
+	assert.Equal(t, SelectPriv, u.PrivilegesOn(NewTarget("mydb", "main", "t2")))
+
+	// granting at the level of the restriction removes it
+	u.grant(InsertPriv, Grant{Database: "mydb/main", Table: AllObjects})
+	assert.Equal(t, SelectPriv|InsertPriv|UpdatePriv, u.PrivilegesOn(NewTarget("mydb", "main", "t")))
+
+	assert.True(t, u.revoke(SelectPriv, Grant{Database: "mydb", Table: AllObjects}))
+	assert.False(t, u.revoke(SelectPriv, Grant{Database: "mydb", Table: AllObjects}))
+	assert.Equal(t, InsertPriv|UpdatePriv, u.PrivilegesOn(NewTarget("mydb", "main", "t")))
+
+	assert.Equal(t, []string{
+		"GRANT USAGE ON *.* TO `bob`@`%`",
+		"GRANT INSERT, UPDATE ON `mydb/%`.* TO `bob`@`%`",
+		"GRANT INSERT ON `mydb/main`.* TO `bob`@`%`",
+		"GRANT DELETE ON `other`.`t` TO `bob`@`%`",
+		"GRANT UPDATE ON `mydb/main`.`t` TO `bob`@`%`",
+		"REVOKE UPDATE ON `mydb/main`.* FROM `bob`@`%`",
+	}, u.GrantStatements())
+}
+
+func TestStore(t *testing.T) {
+	fs := filesys.EmptyInMemFS("/")
+	path := "/home/.doltcfg/privileges.json"
+	store, err := NewStore(fs, path)
+	require.NoError(t, err)
+
+	store.AddStaticUser("root", "", []Grant{NewGlobalGrant(AllPrivileges | GrantOptionPriv)})
+	require.NoError(t, store.CreateUser("bob", "secret", false))
+	assert.True(t, ErrUserExists.Is(store.CreateUser("bob", "other", false)))
+	require.NoError(t, store.CreateUser("bob", "other", true))
+	require.NoError(t, store.Grant("bob", AllPrivileges&^GlobalOnlyPrivileges, Grant{Database: "mydb", Table: AllObjects}))
+	require.NoError(t, store.Revoke("bob", InsertPriv, Grant{Database: "mydb/main", Table: AllObjects}))
+	assert.True(t, ErrIllegalGrant.Is(store.Grant("bob", CreateUserPriv, Grant{Database: "mydb", Table: AllObjects})))
+	assert.True(t, ErrNoSuchGrant.Is(store.Revoke("bob", SelectPriv, Grant{Database: "other", Table: AllObjects})))
+	assert.True(t, ErrStaticUser.Is(store.Revoke("root", SelectPriv, NewGlobalGrant(NoPrivileges))))
+	assert.True(t, ErrUnknownUser.Is(store.Grant("alice", SelectPriv, NewGlobalGrant(NoPrivileges))))
+
+	reloaded, err := NewStore(fs, path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bob"}, reloaded.Users())
+
+	bob, ok := reloaded.User("bob")
+	require.True(t, ok)
+	assert.Equal(t, "*14E65567ABDB5135D0CFD9A70B3032C179A49EE7", bob.Password)
+	assert.Equal(t, AllPrivileges&^GlobalOnlyPrivileges&^InsertPriv, reloaded.PrivilegesOn("bob", NewTarget("mydb", "main", "t")))
+	assert.Equal(t, AllPrivileges&^GlobalOnlyPrivileges, reloaded.PrivilegesOn("bob", NewTarget("mydb", "feature", "t")))
+
+	require.NoError(t, reloaded.DropUser("bob", false))
+	require.NoError(t, reloaded.DropUser("bob", true))
+	assert.True(t, ErrUnknownUser.Is(reloaded.DropUser("bob", false)))
+	assert.Equal(t, NoPrivileges, reloaded.PrivilegesOn("bob", NewTarget("mydb", "main", "t")))
+}
@@ -0,0 +1,139 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package privileges implements the user accounts of a sql-server, and the privileges granted to them on databases,
+// branches and tables.
+package privileges
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Privilege is a set of privileges, as granted by a GRANT statement.
+type Privilege uint32
+
+const (
+	SelectPriv Privilege = 1 << iota
+	InsertPriv
+	UpdatePriv
+	DeletePriv
+	CreatePriv
+	DropPriv
+	AlterPriv
+	IndexPriv
+	// CreateUserPriv allows creating, dropping and altering users.  It can only be granted on all databases.
+	CreateUserPriv
+	// GrantOptionPriv allows granting and revoking the privileges a user holds at a level to other users.
+	GrantOptionPriv
+)
+
+// NoPrivileges is the empty set of privileges, shown as USAGE.
+const NoPrivileges Privilege = 0
+
+// AllPrivileges is the set of privileges granted by GRANT ALL at the global level.  It does not include
+// GrantOptionPriv, which must be granted explicitly.
+const AllPrivileges = SelectPriv | InsertPriv | UpdatePriv | DeletePriv | CreatePriv | DropPriv | AlterPriv | IndexPriv | CreateUserPriv
+
+// GlobalOnlyPrivileges are the privileges which can only be granted on *.*
+const GlobalOnlyPrivileges = CreateUserPriv
+
+var privilegeNames = []struct {
+	priv Privilege
+	name string
+}{
+	{SelectPriv, "SELECT"},
+	{InsertPriv, "INSERT"},
+	{UpdatePriv, "UPDATE"},
+	{DeletePriv, "DELETE"},
+	{CreatePriv, "CREATE"},
+	{DropPriv, "DROP"},
+	{AlterPriv, "ALTER"},
+	{IndexPriv, "INDEX"},
+	{CreateUserPriv, "CREATE USER"},
+	{GrantOptionPriv, "GRANT OPTION"},
+}
+
+// ParsePrivilege returns the privilege with the name given, ignoring case.
+func ParsePrivilege(name string) (Privilege, bool) {
+	name = strings.Join(strings.Fields(strings.ToUpper(name)), " ")
+	for _, pn := range privilegeNames {
+		if pn.name == name {
+			return pn.priv, true
+		}
+	}
+
+	return NoPrivileges, false
+}
+
+// Has returns whether every privilege in |p2| is in |p|.
+func (p Privilege) Has(p2 Privilege) bool {
+	return p&p2 == p2
+}
+
+// Names returns the names of the privileges in the set, in the order MySQL lists them.
+func (p Privilege) Names() []string {
+	var names []string
+	for _, pn := range privilegeNames {
+		if p.Has(pn.priv) {
+			names = append(names, pn.name)
+		}
+	}
+
+	return names
+}
+
+// String returns the privileges as they appear in a GRANT statement, excluding GRANT OPTION.
+func (p Privilege) String() string {
+	p &^= GrantOptionPriv
+	switch {
+	case p == NoPrivileges:
+		return "USAGE"
+	case p == AllPrivileges:
+		return "ALL PRIVILEGES"
+	default:
+		return strings.Join(p.Names(), ", ")
+	}
+}
+
+// MarshalJSON writes the privileges as a list of names.
+func (p Privilege) MarshalJSON() ([]byte, error) {
+	names := p.Names()
+	if names == nil {
+		names = []string{}
+	}
+
+	return json.Marshal(names)
+}
+
+// UnmarshalJSON reads privileges written by MarshalJSON.
+func (p *Privilege) UnmarshalJSON(data []byte) error {
+	var names []string
+	err := json.Unmarshal(data, &names)
+	if err != nil {
+		return err
+	}
+
+	*p = NoPrivileges
+	for _, name := range names {
+		priv, ok := ParsePrivilege(name)
+		if !ok {
+			return fmt.Errorf("unknown privilege '%s'", name)
+		}
+		*p |= priv
+	}
+
+	return nil
+}
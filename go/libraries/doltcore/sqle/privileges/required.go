@@ -0,0 +1,246 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package privileges
+
+import (
+	"strings"
+
+	"github.com/dolthub/vitess/go/vt/sqlparser"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dfunctions"
+)
+
+// informationSchemaName is the name of the information_schema database, which every user may read.
+const informationSchemaName = "information_schema"
+
+// repoWriteFunctions are the dolt functions which change the branches or working sets of a repository, and so require
+// INSERT on the database they are called in.
+var repoWriteFunctions = map[string]bool{
+	dfunctions.AddFuncName:      true,
+	dfunctions.BranchFuncName:   true,
+	dfunctions.CheckoutFuncName: true,
+	dfunctions.CommitFuncName:   true,
+	dfunctions.MergeFuncName:    true,
+	dfunctions.FetchFuncName:    true,
+	dfunctions.PullFuncName:     true,
+	dfunctions.PushFuncName:     true,
+	dfunctions.ResetFuncName:    true,
+	dfunctions.TagFuncName:      true,
+}
+
+// Requirement is a set of privileges a statement requires on a table, or on a database if Table is empty.  A
+// Requirement with no privileges requires any privilege in the database, as USE does.
+type Requirement struct {
+	Database   string
+	Table      string
+	Privileges Privilege
+}
+
+// RequiredPrivileges returns the privileges required to execute |stmt| when |currentDB| is the current database.
+// Tables of information_schema, and tables of no database, require no privileges.
+func RequiredPrivileges(stmt sqlparser.Statement, currentDB string) ([]Requirement, error) {
+	rc := &requirementCollector{currentDB: currentDB}
+	err := rc.statement(stmt)
+	if err != nil {
+		return nil, err
+	}
+
+	return rc.reqs, nil
+}
+
+type requirementCollector struct {
+	currentDB string
+	reqs      []Requirement
+}
+
+func (rc *requirementCollector) add(db, table string, privs Privilege) {
+	if db == "" {
+		db = rc.currentDB
+	}
+
+	if db == "" || strings.EqualFold(db, informationSchemaName) {
+		return
+	}
+
+	rc.reqs = append(rc.reqs, Requirement{Database: db, Table: table, Privileges: privs})
+}
+
+func (rc *requirementCollector) addTable(tn sqlparser.TableName, privs Privilege) {
+	if tn.Name.IsEmpty() || (tn.Qualifier.IsEmpty() && strings.EqualFold(tn.Name.String(), "dual")) {
+		return
+	}
+	rc.add(tn.Qualifier.String(), tn.Name.String(), privs)
+}
+
+func (rc *requirementCollector) statement(stmt sqlparser.Statement) error {
+	switch s := stmt.(type) {
+	case *sqlparser.Explain:
+		return rc.statement(s.Statement)
+	case *sqlparser.Use:
+		rc.add(s.DBName.String(), "", NoPrivileges)
+		return nil
+	case *sqlparser.DBDDL:
+		switch s.Action {
+		case sqlparser.CreateStr:
+			rc.add(s.DBName, "", CreatePriv)
+		case sqlparser.DropStr:
+			rc.add(s.DBName, "", DropPriv)
+		}
+		return nil
+	case *sqlparser.Show:
+		rc.addTable(s.Table, SelectPriv)
+		rc.addTable(s.OnTable, SelectPriv)
+		return nil
+	case *sqlparser.Insert:
+		privs := InsertPriv
+		if s.Action == sqlparser.ReplaceStr {
+			privs |= DeletePriv
+		}
+		if len(s.OnDup) > 0 {
+			privs |= UpdatePriv
+		}
+		rc.addTable(s.Table, privs)
+		return rc.reads(s.Rows, s.OnDup)
+	case *sqlparser.Update:
+		targets := make(map[string]bool)
+		for _, expr := range s.Exprs {
+			targets[strings.ToLower(expr.Name.Qualifier.Name.String())] = true
+		}
+		err := rc.modifiedTables(s.TableExprs, targets, UpdatePriv)
+		if err != nil {
+			return err
+		}
+		return rc.reads(s.Exprs, s.Where, s.OrderBy, s.Limit)
+	case *sqlparser.Delete:
+		targets := make(map[string]bool)
+		for _, target := range s.Targets {
+			targets[strings.ToLower(target.Name.String())] = true
+		}
+		if len(targets) == 0 {
+			targets[""] = true
+		}
+		err := rc.modifiedTables(s.TableExprs, targets, DeletePriv)
+		if err != nil {
+			return err
+		}
+		return rc.reads(s.Where, s.OrderBy, s.Limit)
+	case *sqlparser.DDL:
+		rc.ddl(s)
+		if s.ViewExpr != nil {
+			return rc.reads(s.ViewExpr)
+		}
+		return nil
+	default:
+		return rc.reads(stmt)
+	}
+}
+
+func (rc *requirementCollector) ddl(s *sqlparser.DDL) {
+	switch s.Action {
+	case sqlparser.CreateStr:
+		if !s.View.Name.IsEmpty() {
+			rc.addTable(s.View, CreatePriv)
+		} else if s.TriggerSpec != nil {
+			rc.addTable(s.Table, AlterPriv)
+		} else {
+			rc.addTable(s.Table, CreatePriv)
+			if s.OptLike != nil {
+				rc.addTable(s.OptLike.LikeTable, SelectPriv)
+			}
+		}
+	case sqlparser.AlterStr:
+		if s.IndexSpec != nil {
+			rc.addTable(s.Table, IndexPriv)
+		} else {
+			rc.addTable(s.Table, AlterPriv)
+		}
+	case sqlparser.DropStr:
+		for _, tn := range append(s.FromTables, s.FromViews...) {
+			rc.addTable(tn, DropPriv)
+		}
+		if len(s.FromTables)+len(s.FromViews) == 0 {
+			rc.addTable(s.Table, DropPriv)
+		}
+	case sqlparser.RenameStr:
+		for _, tn := range s.FromTables {
+			rc.addTable(tn, AlterPriv|DropPriv)
+		}
+		for _, tn := range s.ToTables {
+			rc.addTable(tn, CreatePriv|InsertPriv)
+		}
+	case sqlparser.TruncateStr:
+		rc.addTable(s.Table, DropPriv)
+	default:
+		rc.addTable(s.Table, AlterPriv)
+	}
+}
+
+// modifiedTables adds a requirement of |privs| on each table of |exprs| named or aliased by a key of |targets|, and of
+// SELECT on the others.  A target of "" matches every table.
+func (rc *requirementCollector) modifiedTables(exprs sqlparser.TableExprs, targets map[string]bool, privs Privilege) error {
+	var tables []*sqlparser.AliasedTableExpr
+	var others []sqlparser.SQLNode
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		switch n := node.(type) {
+		case *sqlparser.AliasedTableExpr:
+			if _, ok := n.Expr.(sqlparser.TableName); ok {
+				tables = append(tables, n)
+			} else {
+				others = append(others, n)
+			}
+			return false, nil
+		case *sqlparser.JoinTableExpr:
+			others = append(others, n.Condition)
+		}
+		return true, nil
+	}, exprs)
+
+	for _, t := range tables {
+		tn := t.Expr.(sqlparser.TableName)
+		name := strings.ToLower(tn.Name.String())
+		if !t.As.IsEmpty() {
+			name = strings.ToLower(t.As.String())
+		}
+
+		if targets[""] || targets[name] {
+			rc.addTable(tn, privs)
+		} else {
+			rc.addTable(tn, SelectPriv)
+		}
+	}
+
+	return rc.reads(others...)
+}
+
+// reads adds a requirement of SELECT on every table read by |nodes|, and of INSERT on the current database for every
+// call of a dolt function which changes its branches.
+func (rc *requirementCollector) reads(nodes ...sqlparser.SQLNode) error {
+	return sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		switch n := node.(type) {
+		case *sqlparser.AliasedTableExpr:
+			if tn, ok := n.Expr.(sqlparser.TableName); ok {
+				rc.addTable(tn, SelectPriv)
+			}
+		case *sqlparser.ColName:
+			// column qualifiers name tables which are already in a FROM clause
+			return false, nil
+		case *sqlparser.FuncExpr:
+			if repoWriteFunctions[n.Name.Lowered()] {
+				rc.add("", "", InsertPriv)
+			}
+		}
+		return true, nil
+	}, nodes...)
+}
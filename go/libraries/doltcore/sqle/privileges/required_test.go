@@ -0,0 +1,129 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package privileges
+
+import (
+	"testing"
+
+	"github.com/dolthub/vitess/go/vt/sqlparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequiredPrivileges(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected []Requirement
+	}{
+		{
+			query: "select * from t join `mydb/feature`.t2 on t.a = t2.a where t.b in (select b from other.t3)",
+			expected: []Requirement{
+				{"mydb", "t", SelectPriv},
+				{"mydb/feature", "t2", SelectPriv},
+				{"other", "t3", SelectPriv},
+			},
+		},
+		{
+			query:    "select * from information_schema.tables",
+			expected: nil,
+		},
+		{
+			query: "insert into t select * from t2 on duplicate key update a = 1",
+			expected: []Requirement{
+				{"mydb", "t", InsertPriv | UpdatePriv},
+				{"mydb", "t2", SelectPriv},
+			},
+		},
+		{
+			query:    "replace into t values (1)",
+			expected: []Requirement{{"mydb", "t", InsertPriv | DeletePriv}},
+		},
+		{
+			query: "update t join t2 on t.a = t2.a set t.b = t2.b",
+			expected: []Requirement{
+				{"mydb", "t", UpdatePriv},
+				{"mydb", "t2", SelectPriv},
+			},
+		},
+		{
+			query: "delete from t where a in (select a from t2)",
+			expected: []Requirement{
+				{"mydb", "t", DeletePriv},
+				{"mydb", "t2", SelectPriv},
+			},
+		},
+		{
+			query:    "create table t (a int primary key)",
+			expected: []Requirement{{"mydb", "t", CreatePriv}},
+		},
+		{
+			query:    "create index idx on t (a)",
+			expected: []Requirement{{"mydb", "t", IndexPriv}},
+		},
+		{
+			query:    "alter table t add column b int",
+			expected: []Requirement{{"mydb", "t", AlterPriv}},
+		},
+		{
+			query: "rename table t to t2",
+			expected: []Requirement{
+				{"mydb", "t", AlterPriv | DropPriv},
+				{"mydb", "t2", CreatePriv | InsertPriv},
+			},
+		},
+		{
+			query:    "drop table t, other.t2",
+			expected: []Requirement{{"mydb", "t", DropPriv}, {"other", "t2", DropPriv}},
+		},
+		{
+			query:    "create view v as select * from t",
+			expected: []Requirement{{"mydb", "v", CreatePriv}, {"mydb", "t", SelectPriv}},
+		},
+		{
+			query:    "create database db2",
+			expected: []Requirement{{"db2", "", CreatePriv}},
+		},
+		{
+			query:    "use `mydb/feature`",
+			expected: []Requirement{{"mydb/feature", "", NoPrivileges}},
+		},
+		{
+			query:    "show create table t",
+			expected: []Requirement{{"mydb", "t", SelectPriv}},
+		},
+		{
+			query:    "explain select * from t",
+			expected: []Requirement{{"mydb", "t", SelectPriv}},
+		},
+		{
+			query:    "select commit('-m', 'message'), dolt_checkout('feature')",
+			expected: []Requirement{{"mydb", "", InsertPriv}, {"mydb", "", InsertPriv}},
+		},
+		{
+			query:    "select dolt_checkout('-b', 'feature')",
+			expected: []Requirement{{"mydb", "", InsertPriv}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			stmt, err := sqlparser.Parse(test.query)
+			require.NoError(t, err)
+			actual, err := RequiredPrivileges(stmt, "mydb")
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
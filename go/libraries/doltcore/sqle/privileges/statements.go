@@ -0,0 +1,520 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package privileges
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/vitess/go/mysql"
+	"github.com/dolthub/vitess/go/vt/sqlparser"
+)
+
+// StatementKind is the kind of an AccountStatement.
+type StatementKind int
+
+const (
+	CreateUserStmt StatementKind = iota
+	DropUserStmt
+	AlterUserStmt
+	GrantStmt
+	RevokeStmt
+	ShowGrantsStmt
+)
+
+// UserSpec is a user named by an AccountStatement.  The host part of an account name is accepted for compatibility
+// with MySQL clients, but users may always connect from any host.
+type UserSpec struct {
+	Name string
+	// Password is the plaintext password given by IDENTIFIED BY.
+	Password string
+}
+
+// AccountStatement is a statement which manages users and their privileges: CREATE USER, DROP USER, ALTER USER,
+// GRANT, REVOKE or SHOW GRANTS.  The SQL engine can't parse these, so they are executed against a Store directly.
+type AccountStatement struct {
+	Kind  StatementKind
+	Users []UserSpec
+	// IfExists is set by IF EXISTS, or by IF NOT EXISTS for CREATE USER.
+	IfExists   bool
+	Privileges Privilege
+	// Level is the privilege level of a GRANT or REVOKE.  A Database of "" is the current database.
+	Level Grant
+}
+
+// ShowGrantsResult is the result of executing SHOW GRANTS.
+type ShowGrantsResult struct {
+	Column string
+	Grants []string
+}
+
+// ParseAccountStatement parses |query| if it is an AccountStatement, or returns nil if it is not.
+func ParseAccountStatement(query string) (*AccountStatement, error) {
+	p := newAccountParser(query)
+	if p == nil {
+		return nil, nil
+	}
+
+	var st *AccountStatement
+	var err error
+	switch {
+	case p.keywords("create", "user"):
+		st, err = p.parseCreateUser()
+	case p.keywords("drop", "user"):
+		st, err = p.parseDropUser()
+	case p.keywords("alter", "user"):
+		st, err = p.parseAlterUser()
+	case p.keywords("grant"):
+		st, err = p.parseGrantOrRevoke(GrantStmt, "to")
+	case p.keywords("revoke"):
+		st, err = p.parseGrantOrRevoke(RevokeStmt, "from")
+	case p.keywords("show", "grants"):
+		st, err = p.parseShowGrants()
+	default:
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if p.tok().typ == ';' {
+		p.i++
+	}
+
+	if p.tok().typ != 0 {
+		return nil, p.syntaxError()
+	}
+
+	return st, nil
+}
+
+// ParseGrant parses privileges at a privilege level, like "SELECT, INSERT ON mydb.*", as they follow GRANT in a GRANT
+// statement.  The level must name its database.
+func ParseGrant(grant string) (Grant, error) {
+	p := newAccountParser(grant)
+	if p == nil {
+		return Grant{}, fmt.Errorf("invalid grant '%s'", grant)
+	}
+
+	g, err := p.grant()
+	if err != nil {
+		return Grant{}, err
+	} else if p.tok().typ != 0 {
+		return Grant{}, p.syntaxError()
+	} else if g.Database == "" {
+		return Grant{}, fmt.Errorf("grant '%s' must name a database", grant)
+	}
+
+	return g, validateLevel(g.Privileges, g)
+}
+
+type accountToken struct {
+	typ int
+	val string
+}
+
+type accountParser struct {
+	query string
+	toks  []accountToken
+	i     int
+}
+
+func newAccountParser(query string) *accountParser {
+	tkn := sqlparser.NewStringTokenizer(query)
+	p := &accountParser{query: query}
+	for {
+		typ, val := tkn.Scan()
+		switch typ {
+		case 0:
+			return p
+		case sqlparser.LEX_ERROR:
+			return nil
+		case sqlparser.COMMENT:
+			continue
+		}
+		p.toks = append(p.toks, accountToken{typ: typ, val: string(val)})
+	}
+}
+
+// tok returns the current token, or a token with type 0 past the end of the query.
+func (p *accountParser) tok() accountToken {
+	if p.i < len(p.toks) {
+		return p.toks[p.i]
+	}
+	return accountToken{}
+}
+
+func (p *accountParser) syntaxError() error {
+	return fmt.Errorf("syntax error at position %d near '%s'", p.i+1, p.tok().val)
+}
+
+// keywords consumes the tokens |words| if they are next, ignoring case.
+func (p *accountParser) keywords(words ...string) bool {
+	for j, word := range words {
+		if p.i+j >= len(p.toks) {
+			return false
+		}
+		tok := p.toks[p.i+j]
+		if tok.typ == sqlparser.STRING || !strings.EqualFold(tok.val, word) {
+			return false
+		}
+	}
+
+	p.i += len(words)
+	return true
+}
+
+// identifier consumes an identifier, or a keyword used as one.
+func (p *accountParser) identifier() (string, bool) {
+	tok := p.tok()
+	if tok.typ < 256 || tok.typ == sqlparser.STRING || tok.val == "" {
+		return "", false
+	}
+
+	p.i++
+	return tok.val, true
+}
+
+// account consumes an account name, like bob, 'bob' or 'bob'@'%'.
+func (p *accountParser) account() (string, error) {
+	tok := p.tok()
+	if tok.typ != sqlparser.STRING && tok.typ != sqlparser.ID {
+		return "", p.syntaxError()
+	}
+	p.i++
+
+	// an unquoted name and host, like bob@localhost, is scanned as a single identifier
+	if i := strings.Index(tok.val, "@"); tok.typ == sqlparser.ID && i > 0 {
+		return tok.val[:i], nil
+	}
+
+	host := p.tok()
+	if host.typ == sqlparser.ID && strings.HasPrefix(host.val, "@") {
+		p.i++
+		if host.val == "@" {
+			if typ := p.tok().typ; typ != sqlparser.STRING && typ != sqlparser.ID {
+				return "", p.syntaxError()
+			}
+			p.i++
+		}
+	}
+
+	return tok.val, nil
+}
+
+func (p *accountParser) accounts() ([]UserSpec, error) {
+	var users []UserSpec
+	for {
+		name, err := p.account()
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, UserSpec{Name: name})
+
+		if p.tok().typ != ',' {
+			return users, nil
+		}
+		p.i++
+	}
+}
+
+func (p *accountParser) password(user *UserSpec) error {
+	if !p.keywords("identified", "by") {
+		return nil
+	}
+
+	if p.tok().typ != sqlparser.STRING {
+		return p.syntaxError()
+	}
+
+	user.Password = p.tok().val
+	p.i++
+	return nil
+}
+
+func (p *accountParser) parseCreateUser() (*AccountStatement, error) {
+	st := &AccountStatement{Kind: CreateUserStmt, IfExists: p.keywords("if", "not", "exists")}
+	for {
+		name, err := p.account()
+		if err != nil {
+			return nil, err
+		}
+
+		user := UserSpec{Name: name}
+		err = p.password(&user)
+		if err != nil {
+			return nil, err
+		}
+		st.Users = append(st.Users, user)
+
+		if p.tok().typ != ',' {
+			return st, nil
+		}
+		p.i++
+	}
+}
+
+func (p *accountParser) parseDropUser() (*AccountStatement, error) {
+	st := &AccountStatement{Kind: DropUserStmt, IfExists: p.keywords("if", "exists")}
+	users, err := p.accounts()
+	if err != nil {
+		return nil, err
+	}
+
+	st.Users = users
+	return st, nil
+}
+
+func (p *accountParser) parseAlterUser() (*AccountStatement, error) {
+	st := &AccountStatement{Kind: AlterUserStmt, IfExists: p.keywords("if", "exists")}
+	name, err := p.account()
+	if err != nil {
+		return nil, err
+	}
+
+	user := UserSpec{Name: name}
+	if p.tok().val == "" || !strings.EqualFold(p.tok().val, "identified") {
+		return nil, p.syntaxError()
+	}
+
+	err = p.password(&user)
+	if err != nil {
+		return nil, err
+	}
+
+	st.Users = []UserSpec{user}
+	return st, nil
+}
+
+func (p *accountParser) parseGrantOrRevoke(kind StatementKind, toOrFrom string) (*AccountStatement, error) {
+	g, err := p.grant()
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.keywords(toOrFrom) {
+		return nil, p.syntaxError()
+	}
+
+	st := &AccountStatement{Kind: kind, Privileges: g.Privileges}
+	g.Privileges = NoPrivileges
+	st.Level = g
+
+	st.Users, err = p.accounts()
+	if err != nil {
+		return nil, err
+	}
+
+	if kind == GrantStmt && p.keywords("with", "grant", "option") {
+		st.Privileges |= GrantOptionPriv
+	}
+
+	return st, nil
+}
+
+// grant consumes privileges at a privilege level, like SELECT ON mydb.*
+func (p *accountParser) grant() (Grant, error) {
+	privs, err := p.privileges()
+	if err != nil {
+		return Grant{}, err
+	}
+
+	if !p.keywords("on") {
+		return Grant{}, p.syntaxError()
+	}
+	p.keywords("table")
+
+	level, err := p.level()
+	if err != nil {
+		return Grant{}, err
+	}
+
+	if privs == AllPrivileges && !level.IsGlobal() {
+		privs &^= GlobalOnlyPrivileges
+	}
+
+	level.Privileges = privs
+	return level, nil
+}
+
+// privileges consumes a list of privileges, like SELECT, INSERT or ALL PRIVILEGES.
+func (p *accountParser) privileges() (Privilege, error) {
+	if p.keywords("all") {
+		p.keywords("privileges")
+		return AllPrivileges, nil
+	}
+
+	privs := NoPrivileges
+	for {
+		var priv Privilege
+		switch {
+		case p.keywords("create", "user"):
+			priv = CreateUserPriv
+		case p.keywords("grant", "option"):
+			priv = GrantOptionPriv
+		case p.keywords("usage"):
+		default:
+			name, ok := p.identifier()
+			if !ok {
+				return NoPrivileges, p.syntaxError()
+			}
+
+			priv, ok = ParsePrivilege(name)
+			if !ok || priv == CreateUserPriv || priv == GrantOptionPriv {
+				return NoPrivileges, fmt.Errorf("unknown privilege '%s'", name)
+			}
+		}
+		privs |= priv
+
+		if p.tok().typ != ',' {
+			return privs, nil
+		}
+		p.i++
+	}
+}
+
+// level consumes a privilege level: *.*, db.*, db.tbl, * or tbl.
+func (p *accountParser) level() (Grant, error) {
+	var first string
+	if p.tok().typ == '*' {
+		first = AllObjects
+		p.i++
+	} else {
+		name, ok := p.identifier()
+		if !ok {
+			return Grant{}, p.syntaxError()
+		}
+		first = name
+	}
+
+	if p.tok().typ != '.' {
+		if first == AllObjects {
+			return Grant{Table: AllObjects}, nil
+		}
+		return Grant{Table: first}, nil
+	}
+	p.i++
+
+	if p.tok().typ == '*' {
+		p.i++
+		return Grant{Database: first, Table: AllObjects}, nil
+	} else if first == AllObjects {
+		return Grant{}, p.syntaxError()
+	}
+
+	table, ok := p.identifier()
+	if !ok {
+		return Grant{}, p.syntaxError()
+	}
+
+	return Grant{Database: first, Table: table}, nil
+}
+
+func (p *accountParser) parseShowGrants() (*AccountStatement, error) {
+	st := &AccountStatement{Kind: ShowGrantsStmt}
+	if !p.keywords("for") {
+		return st, nil
+	}
+
+	name, err := p.account()
+	if err != nil {
+		return nil, err
+	}
+
+	st.Users = []UserSpec{{Name: name}}
+	return st, nil
+}
+
+// Exec executes |st| against |store| for the user named |user|, whose current database is |currentDB|.  The result
+// is nil for every statement but SHOW GRANTS.
+func (st *AccountStatement) Exec(store *Store, user, currentDB string) (*ShowGrantsResult, error) {
+	global := Target{Database: AllObjects}
+	switch st.Kind {
+	case CreateUserStmt, DropUserStmt, AlterUserStmt:
+		isSelf := st.Kind == AlterUserStmt && st.Users[0].Name == user
+		if !isSelf && !store.PrivilegesOn(user, global).Has(CreateUserPriv) {
+			return nil, mysql.NewSQLError(mysql.ERSpecifiedAccessDenied, mysql.SSUnknownSQLState,
+				"Access denied; you need (at least one of) the CREATE USER privilege(s) for this operation")
+		}
+	case GrantStmt, RevokeStmt:
+		if st.Level.Database == "" {
+			if currentDB == "" {
+				return nil, mysql.NewSQLError(mysql.ERNoDb, ssNoDB, "No database selected")
+			}
+			st.Level.Database = currentDB
+		}
+
+		if !store.PrivilegesOn(user, levelTarget(st.Level)).Has(st.Privileges | GrantOptionPriv) {
+			return nil, AccessDeniedError(user, st.Level.Database)
+		}
+	case ShowGrantsStmt:
+		if len(st.Users) == 0 {
+			st.Users = []UserSpec{{Name: user}}
+		} else if st.Users[0].Name != user && !store.PrivilegesOn(user, global).Has(SelectPriv) {
+			return nil, AccessDeniedError(user, "mysql")
+		}
+	}
+
+	for _, u := range st.Users {
+		var err error
+		switch st.Kind {
+		case CreateUserStmt:
+			err = store.CreateUser(u.Name, u.Password, st.IfExists)
+		case DropUserStmt:
+			err = store.DropUser(u.Name, st.IfExists)
+		case AlterUserStmt:
+			err = store.SetPassword(u.Name, u.Password, st.IfExists)
+		case GrantStmt:
+			err = store.Grant(u.Name, st.Privileges, st.Level)
+		case RevokeStmt:
+			err = store.Revoke(u.Name, st.Privileges, st.Level)
+		case ShowGrantsStmt:
+			found, ok := store.User(u.Name)
+			if !ok {
+				return nil, ErrNoSuchGrant.New(u.Name)
+			}
+			return &ShowGrantsResult{Column: fmt.Sprintf("Grants for %s@%%", u.Name), Grants: found.GrantStatements()}, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, nil
+}
+
+// AccessDeniedError returns the error for a user denied access to the database |dbName|.
+func AccessDeniedError(user, dbName string) error {
+	return mysql.NewSQLError(mysql.ERDBAccessDenied, mysql.SSAccessDeniedError,
+		"Access denied for user '%s'@'%%' to database '%s'", user, dbName)
+}
+
+// ssNoDB is the SQLSTATE of ER_NO_DB_ERROR, which vitess doesn't define.
+const ssNoDB = "3D000"
+
+// erTableAccessDenied is the MySQL error code for ER_TABLEACCESS_DENIED_ERROR, which vitess doesn't define.
+const erTableAccessDenied = 1142
+
+// CommandDeniedError returns the error for a user denied |privs| on |t|.
+func CommandDeniedError(user string, privs Privilege, t Target) error {
+	if t.Table == "" {
+		return AccessDeniedError(user, t.String())
+	}
+
+	return mysql.NewSQLError(erTableAccessDenied, mysql.SSAccessDeniedError,
+		"%s command denied to user '%s'@'%%' for table '%s'", strings.Join(privs.Names(), ", "), user, t.String())
+}
@@ -0,0 +1,201 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package privileges
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAccountStatement(t *testing.T) {
+	tests := []struct {
+		query       string
+		expected    *AccountStatement
+		expectedErr bool
+	}{
+		{
+			query:    "select * from t",
+			expected: nil,
+		},
+		{
+			query:    "create table user (pk int)",
+			expected: nil,
+		},
+		{
+			query: "CREATE USER IF NOT EXISTS 'bob'@'%' IDENTIFIED BY 'pw', alice@localhost",
+			expected: &AccountStatement{
+				Kind:     CreateUserStmt,
+				IfExists: true,
+				Users:    []UserSpec{{Name: "bob", Password: "pw"}, {Name: "alice"}},
+			},
+		},
+		{
+			query:    "drop user if exists `bob`;",
+			expected: &AccountStatement{Kind: DropUserStmt, IfExists: true, Users: []UserSpec{{Name: "bob"}}},
+		},
+		{
+			query:    "alter user bob identified by 'new'",
+			expected: &AccountStatement{Kind: AlterUserStmt, Users: []UserSpec{{Name: "bob", Password: "new"}}},
+		},
+		{
+			query: "grant all privileges on `mydb/%`.* to bob with grant option",
+			expected: &AccountStatement{
+				Kind:       GrantStmt,
+				Users:      []UserSpec{{Name: "bob"}},
+				Privileges: AllPrivileges&^GlobalOnlyPrivileges | GrantOptionPriv,
+				Level:      Grant{Database: "mydb/%", Table: AllObjects},
+			},
+		},
+		{
+			query: "GRANT SELECT, CREATE USER ON *.* TO 'bob'@'%', 'alice'",
+			expected: &AccountStatement{
+				Kind:       GrantStmt,
+				Users:      []UserSpec{{Name: "bob"}, {Name: "alice"}},
+				Privileges: SelectPriv | CreateUserPriv,
+				Level:      NewGlobalGrant(NoPrivileges),
+			},
+		},
+		{
+			query: "revoke insert, grant option on table t from bob",
+			expected: &AccountStatement{
+				Kind:       RevokeStmt,
+				Users:      []UserSpec{{Name: "bob"}},
+				Privileges: InsertPriv | GrantOptionPriv,
+				Level:      Grant{Table: "t"},
+			},
+		},
+		{
+			query: "revoke update on * from bob",
+			expected: &AccountStatement{
+				Kind:       RevokeStmt,
+				Users:      []UserSpec{{Name: "bob"}},
+				Privileges: UpdatePriv,
+				Level:      Grant{Table: AllObjects},
+			},
+		},
+		{
+			query:    "show grants",
+			expected: &AccountStatement{Kind: ShowGrantsStmt},
+		},
+		{
+			query:    "show grants for 'bob'@'%'",
+			expected: &AccountStatement{Kind: ShowGrantsStmt, Users: []UserSpec{{Name: "bob"}}},
+		},
+		{
+			query:       "grant fly on *.* to bob",
+			expectedErr: true,
+		},
+		{
+			query:       "grant select on *.t to bob",
+			expectedErr: true,
+		},
+		{
+			query:       "create user bob identified by pw",
+			expectedErr: true,
+		},
+		{
+			query:       "revoke select on mydb.* to bob",
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			actual, err := ParseAccountStatement(test.query)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestExecAccountStatement(t *testing.T) {
+	store, err := NewStore(nil, "")
+	require.NoError(t, err)
+	store.AddStaticUser("root", "", []Grant{NewGlobalGrant(AllPrivileges | GrantOptionPriv)})
+
+	exec := func(user, currentDB, query string) (*ShowGrantsResult, error) {
+		st, err := ParseAccountStatement(query)
+		require.NoError(t, err)
+		require.NotNil(t, st)
+		return st.Exec(store, user, currentDB)
+	}
+
+	for _, query := range []string{
+		"create user bob identified by 'pw'",
+		"create user alice",
+		"grant select, insert on mydb.* to bob with grant option",
+		"grant update on * to alice",
+	} {
+		_, err := exec("root", "mydb", query)
+		require.NoError(t, err, query)
+	}
+
+	_, err = exec("bob", "mydb", "create user carol")
+	assert.EqualError(t, err, "Access denied; you need (at least one of) the CREATE USER privilege(s) for this operation (errno 1227) (sqlstate HY000)")
+	_, err = exec("bob", "mydb", "grant select on mydb.t to alice")
+	assert.NoError(t, err)
+	_, err = exec("bob", "mydb", "grant delete on mydb.* to alice")
+	assert.EqualError(t, err, "Access denied for user 'bob'@'%' to database 'mydb' (errno 1044) (sqlstate 28000)")
+	_, err = exec("alice", "mydb", "grant select on mydb.t to bob")
+	assert.Error(t, err)
+	_, err = exec("bob", "", "grant select on t to alice")
+	assert.Error(t, err)
+	_, err = exec("bob", "mydb", "alter user bob identified by 'new'")
+	assert.NoError(t, err)
+
+	result, err := exec("alice", "", "show grants")
+	require.NoError(t, err)
+	assert.Equal(t, &ShowGrantsResult{
+		Column: "Grants for alice@%",
+		Grants: []string{
+			"GRANT USAGE ON *.* TO `alice`@`%`",
+			"GRANT UPDATE ON `mydb`.* TO `alice`@`%`",
+			"GRANT SELECT ON `mydb`.`t` TO `alice`@`%`",
+		},
+	}, result)
+
+	_, err = exec("alice", "", "show grants for bob")
+	assert.Error(t, err)
+	result, err = exec("root", "", "show grants for root")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"GRANT ALL PRIVILEGES ON *.* TO `root`@`%` WITH GRANT OPTION"}, result.Grants)
+}
+
+func TestParseGrant(t *testing.T) {
+	grant, err := ParseGrant("SELECT, INSERT ON `mydb/%`.*")
+	require.NoError(t, err)
+	assert.Equal(t, Grant{Database: "mydb/%", Table: AllObjects, Privileges: SelectPriv | InsertPriv}, grant)
+
+	grant, err = ParseGrant("all on *.*")
+	require.NoError(t, err)
+	assert.Equal(t, NewGlobalGrant(AllPrivileges), grant)
+
+	grant, err = ParseGrant("ALL PRIVILEGES ON mydb.t")
+	require.NoError(t, err)
+	assert.Equal(t, Grant{Database: "mydb", Table: "t", Privileges: AllPrivileges &^ GlobalOnlyPrivileges}, grant)
+
+	_, err = ParseGrant("SELECT ON t")
+	assert.Error(t, err)
+	_, err = ParseGrant("CREATE USER ON mydb.*")
+	assert.True(t, ErrIllegalGrant.Is(err))
+	_, err = ParseGrant("SELECT ON mydb.* TO bob")
+	assert.Error(t, err)
+}
@@ -0,0 +1,270 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package privileges
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/dolthub/go-mysql-server/auth"
+	"gopkg.in/src-d/go-errors.v1"
+
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+)
+
+var ErrUserExists = errors.NewKind("Operation CREATE USER failed for '%s'@'%%'")
+var ErrUnknownUser = errors.NewKind("Operation %s failed for '%s'@'%%'")
+var ErrStaticUser = errors.NewKind("user '%s' is defined by the server config and can't be altered")
+var ErrNoSuchGrant = errors.NewKind("There is no such grant defined for user '%s' on host '%%'")
+var ErrIllegalGrant = errors.NewKind("Illegal GRANT/REVOKE command; %s can only be granted on *.*")
+var ErrParsePrivilegeFile = errors.NewKind("error reading privilege file '%s': %s")
+
+// privilegeFile is the contents of the file the users of a Store are persisted in.
+type privilegeFile struct {
+	Users []*User `json:"users"`
+}
+
+// Store holds the user accounts of a server and their privileges.  Users created by SQL statements are persisted in
+// a file outside of any database, so that they are not versioned.  Users added by AddStaticUser come from the server
+// config, and are never persisted.
+type Store struct {
+	mu    *sync.RWMutex
+	fs    filesys.ReadWriteFS
+	path  string
+	users map[string]*User
+}
+
+// NewStore returns a Store which persists its users to the file at |path|, loading any users it already holds.  If
+// |path| is empty, users are kept in memory only.
+func NewStore(fs filesys.ReadWriteFS, path string) (*Store, error) {
+	s := &Store{mu: &sync.RWMutex{}, fs: fs, path: path, users: make(map[string]*User)}
+	if path == "" {
+		return s, nil
+	}
+
+	if exists, _ := fs.Exists(path); !exists {
+		return s, nil
+	}
+
+	data, err := fs.ReadFile(path)
+	if err != nil {
+		return nil, ErrParsePrivilegeFile.New(path, err.Error())
+	}
+
+	var pf privilegeFile
+	err = json.Unmarshal(data, &pf)
+	if err != nil {
+		return nil, ErrParsePrivilegeFile.New(path, err.Error())
+	}
+
+	for _, u := range pf.Users {
+		s.users[u.Name] = u
+	}
+
+	return s, nil
+}
+
+// AddStaticUser adds a user with the plaintext password |password| and the privileges |grants|, replacing any
+// persisted user with the same name.
+func (s *Store) AddStaticUser(name, password string, grants []Grant) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.users[name] = &User{Name: name, Password: auth.NativePassword(password), Grants: grants, static: true}
+}
+
+// User returns a copy of the user named |name|.
+func (s *Store) User(name string) (User, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	u, ok := s.users[name]
+	if !ok {
+		return User{}, false
+	}
+
+	return *u, true
+}
+
+// Users returns the names of all users, in order.
+func (s *Store) Users() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	names := make([]string, 0, len(s.users))
+	for name := range s.users {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// CreateUser adds a user with the plaintext password |password| and no privileges.
+func (s *Store) CreateUser(name, password string, ifNotExists bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.users[name]; ok {
+		if ifNotExists {
+			return nil
+		}
+		return ErrUserExists.New(name)
+	}
+
+	s.users[name] = &User{Name: name, Password: auth.NativePassword(password)}
+	return s.persist()
+}
+
+// DropUser removes the user named |name|.
+func (s *Store) DropUser(name string, ifExists bool) error {
+	return s.update("DROP USER", name, ifExists, func(u *User) error {
+		delete(s.users, name)
+		return nil
+	})
+}
+
+// SetPassword changes the password of the user named |name| to the plaintext password |password|.
+func (s *Store) SetPassword(name, password string, ifExists bool) error {
+	return s.update("ALTER USER", name, ifExists, func(u *User) error {
+		u.Password = auth.NativePassword(password)
+		return nil
+	})
+}
+
+// Grant gives |privs| at the privilege level of |level| to the user named |name|.
+func (s *Store) Grant(name string, privs Privilege, level Grant) error {
+	if err := validateLevel(privs, level); err != nil {
+		return err
+	}
+
+	return s.update("GRANT", name, false, func(u *User) error {
+		u.grant(privs, level)
+		return nil
+	})
+}
+
+// Revoke removes |privs| at the privilege level of |level| from the user named |name|.
+func (s *Store) Revoke(name string, privs Privilege, level Grant) error {
+	if err := validateLevel(privs, level); err != nil {
+		return err
+	}
+
+	return s.update("REVOKE", name, false, func(u *User) error {
+		if !u.revoke(privs, level) {
+			return ErrNoSuchGrant.New(name)
+		}
+		return nil
+	})
+}
+
+func validateLevel(privs Privilege, level Grant) error {
+	if !level.IsGlobal() && privs&GlobalOnlyPrivileges != NoPrivileges {
+		return ErrIllegalGrant.New((privs & GlobalOnlyPrivileges).String())
+	}
+	return nil
+}
+
+// update applies |f| to the user named |name| and persists the result.  Changes are made to a copy of the user, so
+// that the user is unchanged if |f| or persisting fails.
+func (s *Store) update(operation, name string, ifExists bool, f func(u *User) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.users[name]
+	if !ok {
+		if ifExists {
+			return nil
+		}
+		return ErrUnknownUser.New(operation, name)
+	} else if u.static {
+		return ErrStaticUser.New(name)
+	}
+
+	updated := *u
+	updated.Grants = append([]Grant(nil), u.Grants...)
+	updated.Restrictions = append([]Grant(nil), u.Restrictions...)
+	s.users[name] = &updated
+
+	err := f(&updated)
+	if err == nil {
+		err = s.persist()
+	}
+
+	if err != nil {
+		s.users[name] = u
+	}
+
+	return err
+}
+
+// persist writes every user which isn't static to the file of the Store.  It must be called with the lock held.
+func (s *Store) persist() error {
+	if s.path == "" {
+		return nil
+	}
+
+	var pf privilegeFile
+	for _, u := range s.users {
+		if !u.static {
+			pf.Users = append(pf.Users, u)
+		}
+	}
+	sort.Slice(pf.Users, func(i, j int) bool {
+		return pf.Users[i].Name < pf.Users[j].Name
+	})
+
+	data, err := json.MarshalIndent(pf, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	err = s.fs.MkDirs(filepath.Dir(s.path))
+	if err != nil {
+		return err
+	}
+
+	tmp := s.path + ".tmp"
+	err = s.fs.WriteFile(tmp, data)
+	if err != nil {
+		return err
+	}
+
+	return s.fs.MoveFile(tmp, s.path)
+}
+
+// PrivilegesOn returns the privileges the user named |name| has on |t|.
+func (s *Store) PrivilegesOn(name string, t Target) Privilege {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	u, ok := s.users[name]
+	if !ok {
+		return NoPrivileges
+	}
+
+	return u.PrivilegesOn(t)
+}
+
+// HasAnyPrivilegeIn returns whether the user named |name| has any privilege in the database of |t|.
+func (s *Store) HasAnyPrivilegeIn(name string, t Target) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	u, ok := s.users[name]
+	return ok && u.HasAnyPrivilegeIn(t)
+}
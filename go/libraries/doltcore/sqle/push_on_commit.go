@@ -0,0 +1,233 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+)
+
+// The states of the push of a branch reported by a PushOnCommitHook.
+const (
+	PushQueued    = "queued"
+	PushRetrying  = "retrying"
+	PushSucceeded = "succeeded"
+	PushFailed    = "failed"
+	PushDropped   = "dropped"
+)
+
+var ErrPushQueueFull = errors.New("push queue is full")
+
+var _ doltdb.CommitHook = (*PushOnCommitHook)(nil)
+
+// PushStatus is the state of the latest push of a branch to a remote by a PushOnCommitHook.
+type PushStatus struct {
+	Remote    string
+	Branch    string
+	Commit    string
+	Status    string
+	Attempts  int
+	LastError string
+	Updated   time.Time
+}
+
+// pushRequest is the push of the commit of a branch to a remote.
+type pushRequest struct {
+	branch ref.BranchRef
+	cm     *doltdb.Commit
+	hash   string
+}
+
+// PushOnCommitHook is a doltdb.CommitHook which pushes the branches of a database to a remote when they move.  Pushes
+// are queued and made by Run in the background.  A branch which moves again while its push is waiting only takes one
+// slot of the queue and is pushed once, at its latest commit.  A push which fails is retried with an exponential
+// backoff unless its branch moves again in the meantime.
+type PushOnCommitHook struct {
+	dEnv       *env.DoltEnv
+	remote     env.Remote
+	maxRetries int
+	retryDelay time.Duration
+	// queue holds the branches waiting to be pushed, whose requests are in |pending|
+	queue chan string
+
+	mu       *sync.Mutex
+	pending  map[string]pushRequest
+	statuses map[string]*PushStatus
+}
+
+// NewPushOnCommitHook returns a hook which pushes the branches of |dEnv| to |remote|.  At most |queueSize| pushes can
+// be waiting to be made, and a failed push is retried at most |maxRetries| times, waiting |retryDelay| before the
+// first retry and twice as long before each following one.
+func NewPushOnCommitHook(dEnv *env.DoltEnv, remote env.Remote, maxRetries, queueSize int, retryDelay time.Duration) *PushOnCommitHook {
+	return &PushOnCommitHook{
+		dEnv:       dEnv,
+		remote:     remote,
+		maxRetries: maxRetries,
+		retryDelay: retryDelay,
+		queue:      make(chan string, queueSize),
+		mu:         &sync.Mutex{},
+		pending:    make(map[string]pushRequest),
+		statuses:   make(map[string]*PushStatus),
+	}
+}
+
+// Execute implements doltdb.CommitHook.  It queues the push of |branch| without blocking, and drops it if the branch
+// isn't already waiting to be pushed and the queue is full.
+func (h *PushOnCommitHook) Execute(_ context.Context, branch ref.DoltRef, cm *doltdb.Commit) {
+	hash, err := cm.HashOf()
+	if err != nil {
+		return
+	}
+
+	req := pushRequest{ref.NewBranchRef(branch.GetPath()), cm, hash.String()}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	path := req.branch.GetPath()
+	if _, ok := h.pending[path]; ok {
+		h.pending[path] = req
+		h.setStatus(req, PushQueued, 0, nil)
+		return
+	}
+
+	select {
+	case h.queue <- path:
+		h.pending[path] = req
+		h.setStatus(req, PushQueued, 0, nil)
+	default:
+		h.setStatus(req, PushDropped, 0, ErrPushQueueFull)
+	}
+}
+
+// Run makes the queued pushes until |ctx| is done.
+func (h *PushOnCommitHook) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case path := <-h.queue:
+			h.mu.Lock()
+			req := h.pending[path]
+			delete(h.pending, path)
+			h.mu.Unlock()
+
+			h.pushWithRetries(ctx, req)
+		}
+	}
+}
+
+// Statuses returns the state of the latest push of every branch, ordered by branch.
+func (h *PushOnCommitHook) Statuses() []PushStatus {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	statuses := make([]PushStatus, 0, len(h.statuses))
+	for _, status := range h.statuses {
+		statuses = append(statuses, *status)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Branch < statuses[j].Branch
+	})
+
+	return statuses
+}
+
+// pushWithRetries makes the push of |req|, retrying it until it succeeds, fails |maxRetries| times, or its branch
+// moves again.
+func (h *PushOnCommitHook) pushWithRetries(ctx context.Context, req pushRequest) {
+	delay := h.retryDelay
+	for attempt := 1; ; attempt++ {
+		err := h.push(ctx, req)
+		if ctx.Err() != nil {
+			return
+		}
+
+		h.mu.Lock()
+		if _, ok := h.pending[req.branch.GetPath()]; ok {
+			// the branch moved again, and the push of its new commit is queued.
+			h.mu.Unlock()
+			return
+		} else if err == nil {
+			h.setStatus(req, PushSucceeded, attempt, nil)
+		} else if attempt > h.maxRetries || err == actions.ErrCantFF {
+			h.setStatus(req, PushFailed, attempt, err)
+		} else {
+			h.setStatus(req, PushRetrying, attempt, err)
+		}
+		h.mu.Unlock()
+
+		if err == nil || attempt > h.maxRetries || err == actions.ErrCantFF {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+			delay *= 2
+		}
+	}
+}
+
+// push pushes the commit of |req| to its branch on the remote and updates the remote tracking branch.
+func (h *PushOnCommitHook) push(ctx context.Context, req pushRequest) error {
+	remoteRef, err := actions.GetTrackingRef(req.branch, h.remote)
+	if err != nil {
+		return err
+	} else if remoteRef == nil {
+		return fmt.Errorf("remote %s does not fetch branch %s", h.remote.Name, req.branch.GetPath())
+	}
+
+	srcDB := h.dEnv.DoltDB
+	destDB, err := h.remote.GetRemoteDB(ctx, srcDB.ValueReadWriter().Format())
+	if err != nil {
+		return err
+	}
+
+	progChan, pullerEventCh, done := actions.DiscardProgress()
+	err = actions.Push(ctx, h.dEnv, ref.FastForwardOnly, req.branch, remoteRef.(ref.RemoteRef), srcDB, destDB, req.cm, progChan, pullerEventCh)
+	done()
+
+	return err
+}
+
+// setStatus records the state of the push of |req|.  The caller must hold |mu|.
+func (h *PushOnCommitHook) setStatus(req pushRequest, state string, attempts int, err error) {
+	status := &PushStatus{
+		Remote:   h.remote.Name,
+		Branch:   req.branch.GetPath(),
+		Commit:   req.hash,
+		Status:   state,
+		Attempts: attempts,
+		Updated:  time.Now(),
+	}
+
+	if err != nil {
+		status.LastError = err.Error()
+	}
+
+	h.statuses[status.Branch] = status
+}
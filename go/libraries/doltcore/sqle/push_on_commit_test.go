@@ -0,0 +1,131 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+)
+
+func TestPushOnCommitHook(t *testing.T) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+
+	hook := NewPushOnCommitHook(dEnv, env.NewRemote("origin", "mem://", nil), 0, 1, time.Millisecond)
+	dEnv.DoltDB.SetCommitHooks(hook)
+
+	master := dEnv.RepoState.CWBHeadRef()
+	head, err := dEnv.DoltDB.ResolveRef(ctx, master)
+	require.NoError(t, err)
+	cm := writeTestCommit(t, dEnv, head)
+	cmHash, err := cm.HashOf()
+	require.NoError(t, err)
+
+	// the queue only has room for other, which is pushed once at its latest commit
+	other := ref.NewBranchRef("other")
+	require.NoError(t, dEnv.DoltDB.NewBranchAtCommit(ctx, other, head))
+	require.NoError(t, dEnv.DoltDB.FastForward(ctx, master, cm))
+	require.NoError(t, dEnv.DoltDB.FastForward(ctx, other, cm))
+
+	statuses := hook.Statuses()
+	require.Len(t, statuses, 2)
+	assert.Equal(t, "master", statuses[0].Branch)
+	assert.Equal(t, PushDropped, statuses[0].Status)
+	assert.Equal(t, ErrPushQueueFull.Error(), statuses[0].LastError)
+	assert.Equal(t, "other", statuses[1].Branch)
+	assert.Equal(t, PushQueued, statuses[1].Status)
+	assert.Equal(t, cmHash.String(), statuses[1].Commit)
+
+	runCtx, stop := context.WithCancel(ctx)
+	defer stop()
+	go hook.Run(runCtx)
+
+	require.Eventually(t, func() bool {
+		return hook.Statuses()[1].Status == PushSucceeded
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, 1, hook.Statuses()[1].Attempts)
+
+	tracking, err := dEnv.DoltDB.ResolveRef(ctx, ref.NewRemoteRef("origin", "other"))
+	require.NoError(t, err)
+	trackingHash, err := tracking.HashOf()
+	require.NoError(t, err)
+	assert.Equal(t, cmHash, trackingHash)
+
+	// the tracking ref moving doesn't queue a push
+	assert.Len(t, hook.Statuses(), 2)
+}
+
+func TestPushOnCommitHookRetries(t *testing.T) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+
+	hook := NewPushOnCommitHook(dEnv, env.NewRemote("origin", "unknown://nowhere", nil), 2, 10, time.Millisecond)
+	dEnv.DoltDB.SetCommitHooks(hook)
+
+	master := dEnv.RepoState.CWBHeadRef()
+	head, err := dEnv.DoltDB.ResolveRef(ctx, master)
+	require.NoError(t, err)
+	require.NoError(t, dEnv.DoltDB.FastForward(ctx, master, writeTestCommit(t, dEnv, head)))
+
+	runCtx, stop := context.WithCancel(ctx)
+	defer stop()
+	go hook.Run(runCtx)
+
+	require.Eventually(t, func() bool {
+		return hook.Statuses()[0].Status == PushFailed
+	}, 5*time.Second, 10*time.Millisecond)
+
+	status := hook.Statuses()[0]
+	assert.Equal(t, 3, status.Attempts)
+	assert.NotEmpty(t, status.LastError)
+
+	table := &PushStatusTable{dEnv.DoltDB}
+	iter, err := table.PartitionRows(NewTestSQLCtx(ctx), nil)
+	require.NoError(t, err)
+	row, err := iter.Next()
+	require.NoError(t, err)
+	assert.Equal(t, "origin", row[0])
+	assert.Equal(t, "master", row[1])
+	assert.Equal(t, PushFailed, row[3])
+	assert.Equal(t, int64(3), row[4])
+	_, err = iter.Next()
+	assert.Equal(t, io.EOF, err)
+}
+
+func writeTestCommit(t *testing.T, dEnv *env.DoltEnv, parent *doltdb.Commit) *doltdb.Commit {
+	ctx := context.Background()
+	root, err := parent.GetRootValue()
+	require.NoError(t, err)
+	root, err = executeModify(ctx, dEnv, root, "create table t (pk int primary key)")
+	require.NoError(t, err)
+	h, err := dEnv.DoltDB.WriteRootValue(ctx, root)
+	require.NoError(t, err)
+	meta, err := doltdb.NewCommitMeta("Bill Billerson", "bigbillieb@fake.horse", "create t")
+	require.NoError(t, err)
+	cm, err := dEnv.DoltDB.WriteDanglingCommit(ctx, h, []*doltdb.Commit{parent}, meta)
+	require.NoError(t, err)
+
+	return cm
+}
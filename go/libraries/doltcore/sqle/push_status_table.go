@@ -0,0 +1,93 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+)
+
+var _ sql.Table = (*PushStatusTable)(nil)
+
+// PushStatusTable is a sql.Table implementation that implements a system table which shows the state of the latest
+// push of every branch to each remote the database pushes to on commit.  It is empty unless the database has
+// PushOnCommitHooks.
+type PushStatusTable struct {
+	ddb *doltdb.DoltDB
+}
+
+// NewPushStatusTable creates a PushStatusTable
+func NewPushStatusTable(sqlCtx *sql.Context, dbName string) (*PushStatusTable, error) {
+	ddb, ok := DSessFromSess(sqlCtx.Session).GetDoltDB(dbName)
+
+	if !ok {
+		return nil, sql.ErrDatabaseNotFound.New(dbName)
+	}
+
+	return &PushStatusTable{ddb}, nil
+}
+
+// Name is a sql.Table interface function which returns the name of the table which is defined by the constant
+// PushStatusTableName
+func (pt *PushStatusTable) Name() string {
+	return doltdb.PushStatusTableName
+}
+
+// String is a sql.Table interface function which returns the name of the table which is defined by the constant
+// PushStatusTableName
+func (pt *PushStatusTable) String() string {
+	return doltdb.PushStatusTableName
+}
+
+// Schema is a sql.Table interface function that gets the sql.Schema of the push status system table
+func (pt *PushStatusTable) Schema() sql.Schema {
+	return []*sql.Column{
+		{Name: "remote", Type: sql.Text, Source: doltdb.PushStatusTableName, PrimaryKey: true, Nullable: false},
+		{Name: "branch", Type: sql.Text, Source: doltdb.PushStatusTableName, PrimaryKey: true, Nullable: false},
+		{Name: "commit_hash", Type: sql.Text, Source: doltdb.PushStatusTableName, PrimaryKey: false, Nullable: false},
+		{Name: "status", Type: sql.Text, Source: doltdb.PushStatusTableName, PrimaryKey: false, Nullable: false},
+		{Name: "attempts", Type: sql.Int64, Source: doltdb.PushStatusTableName, PrimaryKey: false, Nullable: false},
+		{Name: "last_error", Type: sql.Text, Source: doltdb.PushStatusTableName, PrimaryKey: false, Nullable: true},
+		{Name: "updated", Type: sql.Datetime, Source: doltdb.PushStatusTableName, PrimaryKey: false, Nullable: false},
+	}
+}
+
+// Partitions is a sql.Table interface function that returns a partition of the data.  Currently the data is unpartitioned.
+func (pt *PushStatusTable) Partitions(*sql.Context) (sql.PartitionIter, error) {
+	return newSinglePartitionIter(), nil
+}
+
+// PartitionRows is a sql.Table interface function that gets a row iterator for a partition
+func (pt *PushStatusTable) PartitionRows(*sql.Context, sql.Partition) (sql.RowIter, error) {
+	var rows []sql.Row
+	for _, hook := range pt.ddb.CommitHooks() {
+		pusher, ok := hook.(*PushOnCommitHook)
+		if !ok {
+			continue
+		}
+
+		for _, status := range pusher.Statuses() {
+			var lastError interface{}
+			if status.LastError != "" {
+				lastError = status.LastError
+			}
+
+			rows = append(rows, sql.NewRow(status.Remote, status.Branch, status.Commit, status.Status, int64(status.Attempts), lastError, status.Updated))
+		}
+	}
+
+	return sql.RowsToRowIter(rows...), nil
+}
@@ -0,0 +1,208 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"container/list"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	querypb "github.com/dolthub/vitess/go/vt/proto/query"
+	"github.com/dolthub/vitess/go/vt/sqlparser"
+)
+
+// QueryStat is the statistics of the queries with the same fingerprint which were run with the same current database.
+type QueryStat struct {
+	Database     string
+	Fingerprint  string
+	Count        uint64
+	Errors       uint64
+	TotalLatency time.Duration
+	MaxLatency   time.Duration
+	RowsSent     uint64
+	RowsExamined uint64
+	LastSeen     time.Time
+}
+
+// AvgLatency returns the average latency of the queries.
+func (st QueryStat) AvgLatency() time.Duration {
+	if st.Count == 0 {
+		return 0
+	}
+
+	return st.TotalLatency / time.Duration(st.Count)
+}
+
+type queryStatKey struct {
+	database    string
+	fingerprint string
+}
+
+// QueryStats collects the statistics of the queries run by a server, grouped by their current database and their
+// fingerprint.  At most maxFingerprints groups are kept, the least recently seen group being evicted to make room
+// for a new one.
+type QueryStats struct {
+	mu    *sync.Mutex
+	stats map[queryStatKey]*list.Element
+	// lru holds the *QueryStat of every group, from the most recently seen to the least recently seen
+	lru             *list.List
+	maxFingerprints int
+}
+
+// NewQueryStats returns a QueryStats keeping the statistics of at most |maxFingerprints| groups of queries.
+func NewQueryStats(maxFingerprints int) *QueryStats {
+	return &QueryStats{
+		mu:              &sync.Mutex{},
+		stats:           make(map[queryStatKey]*list.Element),
+		lru:             list.New(),
+		maxFingerprints: maxFingerprints,
+	}
+}
+
+// Record adds a query run on |database| to the statistics, returning the query's fingerprint.
+func (qs *QueryStats) Record(database, query string, latency time.Duration, rowsSent, rowsExamined uint64, err error) string {
+	key := queryStatKey{database, QueryFingerprint(query)}
+
+	qs.mu.Lock()
+	defer qs.mu.Unlock()
+
+	elem, ok := qs.stats[key]
+	if ok {
+		qs.lru.MoveToFront(elem)
+	} else {
+		if qs.maxFingerprints <= 0 {
+			return key.fingerprint
+		}
+
+		if qs.lru.Len() >= qs.maxFingerprints {
+			leastRecent := qs.lru.Back()
+			qs.lru.Remove(leastRecent)
+			st := leastRecent.Value.(*QueryStat)
+			delete(qs.stats, queryStatKey{st.Database, st.Fingerprint})
+		}
+
+		elem = qs.lru.PushFront(&QueryStat{Database: database, Fingerprint: key.fingerprint})
+		qs.stats[key] = elem
+	}
+
+	st := elem.Value.(*QueryStat)
+	st.Count++
+	if err != nil {
+		st.Errors++
+	}
+	st.TotalLatency += latency
+	if latency > st.MaxLatency {
+		st.MaxLatency = latency
+	}
+	st.RowsSent += rowsSent
+	st.RowsExamined += rowsExamined
+	st.LastSeen = time.Now()
+
+	return key.fingerprint
+}
+
+// Stats returns a copy of the statistics, ordered from the highest total latency to the lowest.
+func (qs *QueryStats) Stats() []QueryStat {
+	qs.mu.Lock()
+	defer qs.mu.Unlock()
+
+	stats := make([]QueryStat, 0, qs.lru.Len())
+	for elem := qs.lru.Front(); elem != nil; elem = elem.Next() {
+		stats = append(stats, *elem.Value.(*QueryStat))
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].TotalLatency != stats[j].TotalLatency {
+			return stats[i].TotalLatency > stats[j].TotalLatency
+		}
+		if stats[i].Database != stats[j].Database {
+			return stats[i].Database < stats[j].Database
+		}
+		return stats[i].Fingerprint < stats[j].Fingerprint
+	})
+
+	return stats
+}
+
+// QueryFingerprint returns |query| with its literals replaced by ?, so that the queries which only differ by their
+// literals have the same fingerprint.  Queries which can't be parsed have their literal tokens replaced instead, with
+// their whitespace collapsed and their comments removed.
+func QueryFingerprint(query string) string {
+	stmt, err := sqlparser.Parse(query)
+	if err != nil {
+		return tokenFingerprint(query)
+	}
+
+	sqlparser.Normalize(stmt, make(map[string]*querypb.BindVariable), "v")
+
+	buf := sqlparser.NewTrackedBuffer(func(buf *sqlparser.TrackedBuffer, node sqlparser.SQLNode) {
+		switch node := node.(type) {
+		case *sqlparser.SQLVal:
+			if node.Type == sqlparser.ValArg {
+				buf.WriteString("?")
+				return
+			}
+		case sqlparser.ListArg:
+			buf.WriteString("(?)")
+			return
+		}
+
+		node.Format(buf)
+	})
+	stmt.Format(buf)
+
+	return buf.String()
+}
+
+// tokenFingerprint returns the fingerprint of a query which can't be parsed, replacing its literal tokens by ? so that
+// no values, such as passwords, are left in it.
+func tokenFingerprint(query string) string {
+	var sb strings.Builder
+	tkn := sqlparser.NewStringTokenizer(query)
+	pos := 0
+	for {
+		typ, _ := tkn.Scan()
+		end := tkn.Position - 1
+		if end > len(query) {
+			end = len(query)
+		}
+		text := strings.TrimSpace(query[pos:end])
+		spaced := pos < end && len(text) < end-pos
+		pos = end
+
+		switch typ {
+		case 0:
+			return sb.String()
+		case sqlparser.COMMENT:
+			continue
+		case sqlparser.LEX_ERROR:
+			// the rest of the query may be an unterminated literal
+			text = "?"
+		case sqlparser.STRING, sqlparser.INTEGRAL, sqlparser.FLOAT, sqlparser.HEX, sqlparser.HEXNUM, sqlparser.BIT_LITERAL:
+			text = "?"
+		}
+
+		if spaced && sb.Len() > 0 {
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(text)
+
+		if typ == sqlparser.LEX_ERROR {
+			return sb.String()
+		}
+	}
+}
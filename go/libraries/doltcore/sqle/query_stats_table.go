@@ -0,0 +1,99 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+)
+
+var _ sql.Table = (*QueryStatsTable)(nil)
+
+// QueryStatsTable is a sql.Table implementation that implements a system table which shows the statistics of the
+// queries run by the server, from the highest total latency to the lowest.  The statistics are those of the whole
+// server, whichever database the table is read from.  It is empty when the session doesn't belong to a server.
+type QueryStatsTable struct {
+	stats *QueryStats
+}
+
+// NewQueryStatsTable creates a QueryStatsTable
+func NewQueryStatsTable(sqlCtx *sql.Context) *QueryStatsTable {
+	return &QueryStatsTable{DSessFromSess(sqlCtx.Session).QueryStats}
+}
+
+// Name is a sql.Table interface function which returns the name of the table which is defined by the constant
+// QueryStatsTableName
+func (qt *QueryStatsTable) Name() string {
+	return doltdb.QueryStatsTableName
+}
+
+// String is a sql.Table interface function which returns the name of the table which is defined by the constant
+// QueryStatsTableName
+func (qt *QueryStatsTable) String() string {
+	return doltdb.QueryStatsTableName
+}
+
+// Schema is a sql.Table interface function that gets the sql.Schema of the query stats system table
+func (qt *QueryStatsTable) Schema() sql.Schema {
+	return []*sql.Column{
+		{Name: "database", Type: sql.Text, Source: doltdb.QueryStatsTableName, PrimaryKey: true, Nullable: false},
+		{Name: "fingerprint", Type: sql.Text, Source: doltdb.QueryStatsTableName, PrimaryKey: true, Nullable: false},
+		{Name: "count", Type: sql.Uint64, Source: doltdb.QueryStatsTableName, PrimaryKey: false, Nullable: false},
+		{Name: "errors", Type: sql.Uint64, Source: doltdb.QueryStatsTableName, PrimaryKey: false, Nullable: false},
+		{Name: "total_latency_ms", Type: sql.Float64, Source: doltdb.QueryStatsTableName, PrimaryKey: false, Nullable: false},
+		{Name: "avg_latency_ms", Type: sql.Float64, Source: doltdb.QueryStatsTableName, PrimaryKey: false, Nullable: false},
+		{Name: "max_latency_ms", Type: sql.Float64, Source: doltdb.QueryStatsTableName, PrimaryKey: false, Nullable: false},
+		{Name: "rows_sent", Type: sql.Uint64, Source: doltdb.QueryStatsTableName, PrimaryKey: false, Nullable: false},
+		{Name: "rows_examined", Type: sql.Uint64, Source: doltdb.QueryStatsTableName, PrimaryKey: false, Nullable: false},
+		{Name: "last_seen", Type: sql.Datetime, Source: doltdb.QueryStatsTableName, PrimaryKey: false, Nullable: false},
+	}
+}
+
+// Partitions is a sql.Table interface function that returns a partition of the data.  Currently the data is unpartitioned.
+func (qt *QueryStatsTable) Partitions(*sql.Context) (sql.PartitionIter, error) {
+	return newSinglePartitionIter(), nil
+}
+
+// PartitionRows is a sql.Table interface function that gets a row iterator for a partition
+func (qt *QueryStatsTable) PartitionRows(*sql.Context, sql.Partition) (sql.RowIter, error) {
+	if qt.stats == nil {
+		return sql.RowsToRowIter(), nil
+	}
+
+	var rows []sql.Row
+	for _, st := range qt.stats.Stats() {
+		rows = append(rows, sql.NewRow(
+			st.Database,
+			st.Fingerprint,
+			st.Count,
+			st.Errors,
+			millis(st.TotalLatency),
+			millis(st.AvgLatency()),
+			millis(st.MaxLatency),
+			st.RowsSent,
+			st.RowsExamined,
+			st.LastSeen,
+		))
+	}
+
+	return sql.RowsToRowIter(rows...), nil
+}
+
+func millis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,98 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryFingerprint(t *testing.T) {
+	tests := []struct {
+		query       string
+		fingerprint string
+	}{
+		{"select * from people where age = 32", "select * from people where age = ?"},
+		{"SELECT *  FROM people WHERE name = 'Bill' and age > 1.5", "select * from people where name = ? and age > ?"},
+		{"select name from people where age in (1, 2, 3)", "select name from people where age in (?)"},
+		{"insert into people (name, age) values ('Rob', 21)", "insert into people(name, age) values (?, ?)"},
+		{"update people set age = 33 where name = 'Bill'", "update people set age = ? where name = ?"},
+		{"not  a\n query", "not a query"},
+		{"create user 'bill'@'%' identified by 'secret'", "create user ?@? identified by ?"},
+		{"alter user `bill` /* comment */ identified by \"secret\"", "alter user `bill` identified by ?"},
+		{"grant all on db.* to bill identified by 'unterminated", "grant all on db.* to bill identified by ?"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			assert.Equal(t, test.fingerprint, QueryFingerprint(test.query))
+		})
+	}
+}
+
+func TestQueryStats(t *testing.T) {
+	stats := NewQueryStats(2)
+	stats.Record("db", "select * from not_a_table", 5*time.Millisecond, 0, 0, errors.New("table not found"))
+	stats.Record("db", "select * from people where age = 32", 10*time.Millisecond, 1, 3, nil)
+	stats.Record("db", "select * from people where age = 25", 30*time.Millisecond, 1, 3, nil)
+	stats.Record("other", "select * from people where age = 32", time.Millisecond, 0, 0, nil)
+
+	// the statistics of not_a_table, which were the least recently seen, were evicted to make room for other
+	all := stats.Stats()
+	require.Len(t, all, 2)
+	assert.Equal(t, QueryStat{
+		Database:     "db",
+		Fingerprint:  "select * from people where age = ?",
+		Count:        2,
+		TotalLatency: 40 * time.Millisecond,
+		MaxLatency:   30 * time.Millisecond,
+		RowsSent:     2,
+		RowsExamined: 6,
+		LastSeen:     all[0].LastSeen,
+	}, all[0])
+	assert.Equal(t, 20*time.Millisecond, all[0].AvgLatency())
+	assert.Equal(t, "other", all[1].Database)
+
+	// other was seen more recently than the statistics of people, which are evicted
+	stats.Record("db", "select * from not_a_table", 5*time.Millisecond, 0, 0, errors.New("table not found"))
+	all = stats.Stats()
+	require.Len(t, all, 2)
+	assert.Equal(t, "select * from not_a_table", all[0].Fingerprint)
+	assert.Equal(t, uint64(1), all[0].Errors)
+	assert.Equal(t, "other", all[1].Database)
+
+	sqlCtx := NewTestSQLCtx(context.Background())
+	DSessFromSess(sqlCtx.Session).QueryStats = stats
+	iter, err := NewQueryStatsTable(sqlCtx).PartitionRows(sqlCtx, nil)
+	require.NoError(t, err)
+	row, err := iter.Next()
+	require.NoError(t, err)
+	assert.Equal(t, "db", row[0])
+	assert.Equal(t, "select * from not_a_table", row[1])
+	assert.Equal(t, uint64(1), row[2])
+	assert.Equal(t, uint64(1), row[3])
+	assert.Equal(t, 5.0, row[4])
+	assert.Equal(t, 5.0, row[5])
+	_, err = iter.Next()
+	require.NoError(t, err)
+	_, err = iter.Next()
+	assert.Equal(t, io.EOF, err)
+}
@@ -0,0 +1,169 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"fmt"
+
+	sqle "github.com/dolthub/go-mysql-server"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/shopspring/decimal"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+)
+
+// checkTableName is the name the columns of a table are resolved against when compiling its checks. Check expressions
+// may only reference the columns of the table they're defined on, so the name itself is irrelevant.
+const checkTableName = "dolt_check_table"
+
+func init() {
+	doltdb.RowCheckerFactory = func(sch schema.Schema, checks []schema.Check) (doltdb.RowChecker, error) {
+		return newRowChecker(sch, checks)
+	}
+}
+
+// rowChecker evaluates the enforced CHECK constraints of a schema against rows.
+type rowChecker struct {
+	sch    schema.Schema
+	checks []schema.Check
+	exprs  []sql.Expression
+	sqlCtx *sql.Context
+}
+
+var _ doltdb.RowChecker = (*rowChecker)(nil)
+
+// newRowChecker compiles the checks given, which must be checks of |sch|.
+func newRowChecker(sch schema.Schema, checks []schema.Check) (*rowChecker, error) {
+	exprStrs := make([]string, len(checks))
+	for i, check := range checks {
+		exprStrs[i] = check.Expression
+	}
+
+	exprs, err := compileExpressions(checkTableName, sch.GetAllCols().GetColumns(), exprStrs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rowChecker{sch: sch, checks: checks, exprs: exprs, sqlCtx: sql.NewEmptyContext()}, nil
+}
+
+// compileExpressions parses and resolves the expressions given against the columns given, which belong to the table
+// named |tableName|. The returned expressions are evaluated against rows containing the values of those columns, in
+// order.
+func compileExpressions(tableName string, tableCols []schema.Column, exprs []string) ([]sql.Expression, error) {
+	cols := make([]*sqle.ColumnWithRawDefault, 0, len(tableCols)+len(exprs))
+	for _, col := range tableCols {
+		cols = append(cols, &sqle.ColumnWithRawDefault{
+			SqlColumn: &sql.Column{
+				Name:     col.Name,
+				Type:     col.TypeInfo.ToSqlType(),
+				Nullable: true,
+				Source:   tableName,
+			},
+		})
+	}
+
+	// Default values are resolved against the other columns of their table, which is exactly what's needed here, so
+	// each expression is compiled as the default of an extra column. The type of the column is only used to convert
+	// the values of the defaults, which are unwrapped below.
+	for i, expr := range exprs {
+		cols = append(cols, &sqle.ColumnWithRawDefault{
+			SqlColumn: &sql.Column{
+				Name:     fmt.Sprintf("dolt_expression_%d", i),
+				Type:     sql.LongText,
+				Nullable: true,
+				Source:   tableName,
+			},
+			Default: "(" + expr + ")",
+		})
+	}
+
+	sqlSch, err := sqle.ResolveDefaults(tableName, cols)
+	if err != nil {
+		return nil, err
+	}
+
+	compiled := make([]sql.Expression, len(exprs))
+	for i := range exprs {
+		compiled[i] = sqlSch[len(tableCols)+i].Default.Expression
+	}
+
+	return compiled, nil
+}
+
+// CheckRow implements doltdb.RowChecker.
+func (rc *rowChecker) CheckRow(ctx context.Context, dRow row.Row) error {
+	sqlCtx, ok := ctx.(*sql.Context)
+	if !ok {
+		sqlCtx = rc.sqlCtx
+	}
+
+	sqlRow, err := checkRow(sqlCtx, rc.sch, dRow)
+	if err != nil {
+		return err
+	}
+
+	for i, expr := range rc.exprs {
+		ok, err := checkSatisfied(sqlCtx, expr, sqlRow)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return doltdb.ErrCheckConstraintViolated{CheckName: rc.checks[i].Name}
+		}
+	}
+
+	return nil
+}
+
+// checkRow converts the row given to the form that compiled check expressions are evaluated against.
+func checkRow(ctx context.Context, sch schema.Schema, dRow row.Row) (sql.Row, error) {
+	allCols := sch.GetAllCols()
+	sqlRow := make(sql.Row, allCols.Size())
+	for i, tag := range allCols.Tags {
+		val, ok := dRow.GetColVal(tag)
+		if !ok {
+			continue
+		}
+		var err error
+		sqlRow[i], err = allCols.TagToCol[tag].TypeInfo.ConvertNomsValueToValue(ctx, val)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return sqlRow, nil
+}
+
+// checkSatisfied returns whether the check expression given is satisfied by |sqlRow|. As in MySQL, a check
+// is only violated when its expression evaluates to false, which is any zero value; NULL satisfies it.
+func checkSatisfied(ctx *sql.Context, expr sql.Expression, sqlRow sql.Row) (bool, error) {
+	res, err := expr.Eval(ctx, sqlRow)
+	if err != nil {
+		return false, err
+	}
+
+	switch res := res.(type) {
+	case nil:
+		return true, nil
+	case decimal.Decimal:
+		return !res.IsZero(), nil
+	default:
+		return sql.ConvertToBool(res)
+	}
+}
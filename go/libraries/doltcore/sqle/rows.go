@@ -15,6 +15,7 @@
 package sqle
 
 import (
+	"context"
 	"fmt"
 	"io"
 
@@ -115,7 +116,9 @@ func (itr *doltTableRowIter) Next() (sql.Row, error) {
 		return nil, err
 	}
 
-	return sqlRowFromNomsTupleValueSlices(keySl, valSl, itr.table.sch)
+	countRowExamined(itr.ctx)
+
+	return sqlRowFromNomsTupleValueSlices(itr.ctx, keySl, valSl, itr.table.sch)
 }
 
 // Close required by sql.RowIter interface
@@ -123,7 +126,7 @@ func (itr *doltTableRowIter) Close() error {
 	return nil
 }
 
-func sqlRowFromNomsTupleValueSlices(keySl, valSl types.TupleValueSlice, sch schema.Schema) (sql.Row, error) {
+func sqlRowFromNomsTupleValueSlices(ctx context.Context, keySl, valSl types.TupleValueSlice, sch schema.Schema) (sql.Row, error) {
 	allCols := sch.GetAllCols()
 	colVals := make(sql.Row, allCols.Size())
 
@@ -132,7 +135,7 @@ func sqlRowFromNomsTupleValueSlices(keySl, valSl types.TupleValueSlice, sch sche
 		err := sl.Iter(func(tag uint64, val types.Value) (stop bool, err error) {
 			if idx, ok := allCols.TagToIdx[tag]; ok {
 				col := allCols.GetByIndex(idx)
-				colVals[idx], convErr = col.TypeInfo.ConvertNomsValueToValue(val)
+				colVals[idx], convErr = col.TypeInfo.ConvertNomsValueToValue(ctx, val)
 
 				if convErr != nil {
 					return false, err
@@ -151,14 +154,14 @@ func sqlRowFromNomsTupleValueSlices(keySl, valSl types.TupleValueSlice, sch sche
 }
 
 // Returns a SQL row representation for the dolt row given.
-func doltRowToSqlRow(doltRow row.Row, sch schema.Schema) (sql.Row, error) {
+func doltRowToSqlRow(ctx context.Context, doltRow row.Row, sch schema.Schema) (sql.Row, error) {
 	colVals := make(sql.Row, sch.GetAllCols().Size())
 
 	i := 0
 	err := sch.GetAllCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
 		var innerErr error
 		value, _ := doltRow.GetColVal(tag)
-		colVals[i], innerErr = col.TypeInfo.ConvertNomsValueToValue(value)
+		colVals[i], innerErr = col.TypeInfo.ConvertNomsValueToValue(ctx, value)
 		if innerErr != nil {
 			return true, innerErr
 		}
@@ -174,7 +177,7 @@ func doltRowToSqlRow(doltRow row.Row, sch schema.Schema) (sql.Row, error) {
 }
 
 // Returns a Dolt row representation for SQL row given
-func SqlRowToDoltRow(nbf *types.NomsBinFormat, r sql.Row, doltSchema schema.Schema) (row.Row, error) {
+func SqlRowToDoltRow(ctx context.Context, vrw types.ValueReadWriter, r sql.Row, doltSchema schema.Schema) (row.Row, error) {
 	taggedVals := make(row.TaggedValues)
 	allCols := doltSchema.GetAllCols()
 	for i, val := range r {
@@ -182,7 +185,7 @@ func SqlRowToDoltRow(nbf *types.NomsBinFormat, r sql.Row, doltSchema schema.Sche
 		schCol := allCols.TagToCol[tag]
 		if val != nil {
 			var err error
-			taggedVals[tag], err = schCol.TypeInfo.ConvertValueToNomsValue(val)
+			taggedVals[tag], err = schCol.TypeInfo.ConvertValueToNomsValue(ctx, vrw, val)
 			if err != nil {
 				return nil, err
 			}
@@ -190,5 +193,5 @@ func SqlRowToDoltRow(nbf *types.NomsBinFormat, r sql.Row, doltSchema schema.Sche
 			return nil, fmt.Errorf("column <%v> received nil but is non-nullable", schCol.Name)
 		}
 	}
-	return row.New(nbf, doltSchema, taggedVals)
+	return row.New(vrw.Format(), doltSchema, taggedVals)
 }
@@ -22,6 +22,7 @@ import (
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/dolthub/go-mysql-server/sql/parse"
 	"github.com/dolthub/vitess/go/vt/sqlparser"
+	"gopkg.in/src-d/go-errors.v1"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/row"
@@ -30,8 +31,12 @@ import (
 	"github.com/dolthub/dolt/go/store/types"
 )
 
-// ApplyDefaults applies the default values to the given indices, returning the resulting row.
-func ApplyDefaults(ctx context.Context, doltSchema schema.Schema, sqlSchema sql.Schema, indicesOfColumns []int, dRow row.Row) (row.Row, error) {
+// ErrBlobInKey is returned when a column whose values are stored as blobs is used in a primary key or index.
+var ErrBlobInKey = errors.NewKind("BLOB/TEXT column '%s' used in key specification without a key length")
+
+// ApplyDefaults applies the default values to the given indices, returning the resulting row. Default values which are
+// stored in their own chunks are written to vrw.
+func ApplyDefaults(ctx context.Context, vrw types.ValueReadWriter, doltSchema schema.Schema, sqlSchema sql.Schema, indicesOfColumns []int, dRow row.Row) (row.Row, error) {
 	if len(indicesOfColumns) == 0 {
 		return dRow, nil
 	}
@@ -45,7 +50,7 @@ func ApplyDefaults(ctx context.Context, doltSchema schema.Schema, sqlSchema sql.
 		val, ok := dRow.GetColVal(tag)
 		if ok {
 			var err error
-			oldSqlRow[i], err = doltCols.TagToCol[tag].TypeInfo.ConvertNomsValueToValue(val)
+			oldSqlRow[i], err = doltCols.TagToCol[tag].TypeInfo.ConvertNomsValueToValue(ctx, val)
 			if err != nil {
 				return nil, err
 			}
@@ -57,12 +62,17 @@ func ApplyDefaults(ctx context.Context, doltSchema schema.Schema, sqlSchema sql.
 	if err != nil {
 		return nil, err
 	}
-	newRow := make(row.TaggedValues)
-	for i, tag := range doltCols.Tags {
+	newRow, err := row.GetTaggedVals(dRow)
+	if err != nil {
+		return nil, err
+	}
+	for _, i := range indicesOfColumns {
+		tag := doltCols.Tags[i]
 		if newSqlRow[i] == nil {
+			delete(newRow, tag)
 			continue
 		}
-		val, err := doltCols.TagToCol[tag].TypeInfo.ConvertValueToNomsValue(newSqlRow[i])
+		val, err := doltCols.TagToCol[tag].TypeInfo.ConvertValueToNomsValue(ctx, vrw, newSqlRow[i])
 		if err != nil {
 			return nil, err
 		}
@@ -159,11 +169,11 @@ func ToDoltSchema(ctx context.Context, root *doltdb.RootValue, tableName string,
 	var kinds []types.NomsKind
 	for _, col := range sqlSchema {
 		names = append(names, col.Name)
-		ti, err := typeinfo.FromSqlType(col.Type)
+		ti, err := typeInfoForCol(col)
 		if err != nil {
 			return nil, err
 		}
-		kinds = append(kinds, ti.NomsKind())
+		kinds = append(kinds, typeinfo.TagKind(ti))
 	}
 	tags, err := root.GenerateTagsForNewColumns(ctx, tableName, names, kinds)
 	if err != nil {
@@ -179,6 +189,9 @@ func ToDoltSchema(ctx context.Context, root *doltdb.RootValue, tableName string,
 		if err != nil {
 			return nil, err
 		}
+		if convertedCol.IsPartOfPK && convertedCol.Kind == types.BlobKind {
+			return nil, ErrBlobInKey.New(convertedCol.Name)
+		}
 		cols = append(cols, convertedCol)
 	}
 
@@ -201,10 +214,23 @@ func ToDoltCol(tag uint64, col *sql.Column) (schema.Column, error) {
 	if !col.Nullable {
 		constraints = append(constraints, schema.NotNullConstraint{})
 	}
-	typeInfo, err := typeinfo.FromSqlType(col.Type)
+	typeInfo, err := typeInfoForCol(col)
 	if err != nil {
 		return schema.Column{}, err
 	}
 
 	return schema.NewColumnWithTypeInfo(col.Name, tag, typeInfo, col.PrimaryKey, col.Default.String(), col.AutoIncrement, col.Comment, constraints...)
 }
+
+// typeInfoForCol returns the TypeInfo for the SQL column given. Primary key values are stored inline in the row's key,
+// so LONGTEXT keys aren't stored as blobs.
+func typeInfoForCol(col *sql.Column) (typeinfo.TypeInfo, error) {
+	ti, err := typeinfo.FromSqlType(col.Type)
+	if err != nil {
+		return nil, err
+	}
+	if col.PrimaryKey {
+		ti = typeinfo.InlineType(ti)
+	}
+	return ti, nil
+}
@@ -161,7 +161,7 @@ func migrateOldSchemasTableToNew(
 		if err != nil {
 			return err
 		}
-		sqlRow, err := doltRowToSqlRow(dRow, schemasTable.sch)
+		sqlRow, err := doltRowToSqlRow(ctx, dRow, schemasTable.sch)
 		if err != nil {
 			return err
 		}
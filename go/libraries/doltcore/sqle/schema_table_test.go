@@ -63,7 +63,7 @@ func TestSchemaTableRecreation(t *testing.T) {
 	_ = rowData.IterAll(ctx, func(keyTpl, valTpl types.Value) error {
 		dRow, err := row.FromNoms(sqlTbl.(*WritableDoltTable).sch, keyTpl.(types.Tuple), valTpl.(types.Tuple))
 		require.NoError(t, err)
-		sqlRow, err := doltRowToSqlRow(dRow, sqlTbl.(*WritableDoltTable).sch)
+		sqlRow, err := doltRowToSqlRow(context.Background(), dRow, sqlTbl.(*WritableDoltTable).sch)
 		require.NoError(t, err)
 		assert.Equal(t, expectedVals[index], sqlRow)
 		index++
@@ -82,7 +82,7 @@ func TestSchemaTableRecreation(t *testing.T) {
 	_ = rowData.IterAll(ctx, func(keyTpl, valTpl types.Value) error {
 		dRow, err := row.FromNoms(tbl.sch, keyTpl.(types.Tuple), valTpl.(types.Tuple))
 		require.NoError(t, err)
-		sqlRow, err := doltRowToSqlRow(dRow, tbl.sch)
+		sqlRow, err := doltRowToSqlRow(context.Background(), dRow, tbl.sch)
 		require.NoError(t, err)
 		assert.Equal(t, expectedVals[index], sqlRow)
 		index++
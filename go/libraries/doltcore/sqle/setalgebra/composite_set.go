@@ -14,6 +14,8 @@
 
 package setalgebra
 
+import "strings"
+
 // CompositeSet is a set which is made up of a FiniteSet and one or more non overlapping intervals such as
 // {n | n < 0 or n > 100} (set of all numbers n below 0 or greater than 100) this set contains 2 non overlapping intervals
 // and an empty finite set. Alternatively {n | n < 0 or {5,10,15}} (set of all numbers n below 0 or n equal to 5, 10 or 15)
@@ -61,3 +63,17 @@ func (cs CompositeSet) Intersect(other Set) (Set, error) {
 
 	panic("unknown set type")
 }
+
+// String returns the set as the union of its finite set and its intervals, such as {5, 10} U (-inf, 0) U (100, +inf)
+func (cs CompositeSet) String() string {
+	var strs []string
+	if len(cs.Set.HashToVal) > 0 {
+		strs = append(strs, cs.Set.String())
+	}
+
+	for _, in := range cs.Intervals {
+		strs = append(strs, in.String())
+	}
+
+	return strings.Join(strs, " U ")
+}
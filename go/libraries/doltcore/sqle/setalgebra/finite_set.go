@@ -15,6 +15,9 @@
 package setalgebra
 
 import (
+	"sort"
+	"strings"
+
 	"github.com/dolthub/dolt/go/store/hash"
 	"github.com/dolthub/dolt/go/store/types"
 )
@@ -79,3 +82,14 @@ func (fs FiniteSet) Intersect(other Set) (Set, error) {
 		panic("unknown set type")
 	}
 }
+
+// String returns the set in set notation, its values being sorted by their string representation
+func (fs FiniteSet) String() string {
+	strs := make([]string, 0, len(fs.HashToVal))
+	for _, val := range fs.HashToVal {
+		strs = append(strs, val.HumanReadableString())
+	}
+
+	sort.Strings(strs)
+	return "{" + strings.Join(strs, ", ") + "}"
+}
@@ -83,6 +83,27 @@ func (in Interval) Intersect(other Set) (Set, error) {
 
 }
 
+// String returns the interval in interval notation, such as [0, 10) or (-inf, 5]
+func (in Interval) String() string {
+	start := "(-inf"
+	if in.Start != nil {
+		start = "(" + in.Start.Val.HumanReadableString()
+		if in.Start.Inclusive {
+			start = "[" + in.Start.Val.HumanReadableString()
+		}
+	}
+
+	end := "+inf)"
+	if in.End != nil {
+		end = in.End.Val.HumanReadableString() + ")"
+		if in.End.Inclusive {
+			end = in.End.Val.HumanReadableString() + "]"
+		}
+	}
+
+	return start + ", " + end
+}
+
 // Contains returns true if the value falls within the bounds of the interval
 func (in Interval) Contains(val types.Value) (bool, error) {
 	if in.Start == nil && in.End == nil {
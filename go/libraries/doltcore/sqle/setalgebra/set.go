@@ -20,6 +20,8 @@ type Set interface {
 	Union(other Set) (Set, error)
 	// Interset takes the current set and another set and returns a set containing the values that are in both
 	Intersect(other Set) (Set, error)
+	// String returns the set in set notation, such as {1, 2, 3} or [0, 10)
+	String() string
 }
 
 // EmptySet is a Set implementation that has no values
@@ -37,6 +39,11 @@ func (es EmptySet) Intersect(other Set) (Set, error) {
 	return es, nil
 }
 
+// String returns the set in set notation
+func (es EmptySet) String() string {
+	return "{}"
+}
+
 // UniversalSet is the set containing all values
 type UniversalSet struct{}
 
@@ -51,3 +58,8 @@ func (us UniversalSet) Union(other Set) (Set, error) {
 func (us UniversalSet) Intersect(other Set) (Set, error) {
 	return other, nil
 }
+
+// String returns the set in interval notation
+func (us UniversalSet) String() string {
+	return "(-inf, +inf)"
+}
@@ -51,3 +51,51 @@ func TestUniversalSet(t *testing.T) {
 
 	assert.Equal(t, testVal, intersection)
 }
+
+func TestSetString(t *testing.T) {
+	tests := []struct {
+		name     string
+		set      Set
+		expected string
+	}{
+		{"empty set", EmptySet{}, "{}"},
+		{"universal set", UniversalSet{}, "(-inf, +inf)"},
+		{
+			"finite set",
+			mustFiniteSet(NewFiniteSet(types.Format_Default, types.Int(10), types.Int(5), types.Int(7))),
+			"{10, 5, 7}",
+		},
+		{
+			"closed interval",
+			testInterv(&IntervalEndpoint{types.Int(0), true}, &IntervalEndpoint{types.Int(10), true}),
+			"[0, 10]",
+		},
+		{
+			"half open interval",
+			testInterv(&IntervalEndpoint{types.String("a"), true}, &IntervalEndpoint{types.String("b"), false}),
+			`["a", "b")`,
+		},
+		{
+			"unbounded interval",
+			testInterv(nil, &IntervalEndpoint{types.Int(0), false}),
+			"(-inf, 0)",
+		},
+		{
+			"composite set",
+			CompositeSet{
+				mustFiniteSet(NewFiniteSet(types.Format_Default, types.Int(50))),
+				[]Interval{
+					testInterv(nil, &IntervalEndpoint{types.Int(0), false}),
+					testInterv(&IntervalEndpoint{types.Int(100), false}, nil),
+				},
+			},
+			"{50} U (-inf, 0) U (100, +inf)",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.set.String())
+		})
+	}
+}
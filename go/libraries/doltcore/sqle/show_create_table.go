@@ -17,9 +17,13 @@ package sqle
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	sqle "github.com/dolthub/go-mysql-server"
 	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlfmt"
 )
 
 // These functions cannot be in the sqlfmt package as the reliance on the sqle package creates a circular reference.
@@ -30,7 +34,7 @@ func PrepareCreateTableStmt(ctx context.Context, sqlDb sql.Database) (*sql.Conte
 		sql.WithSession(dsess),
 		sql.WithIndexRegistry(sql.NewIndexRegistry()),
 		sql.WithViewRegistry(sql.NewViewRegistry()))
-	engine := sqle.NewDefault()
+	engine := NewDefaultEngine()
 	engine.AddDatabase(sqlDb)
 	dsess.SetCurrentDatabase(sqlDb.Name())
 	return sqlCtx, engine, dsess
@@ -54,3 +58,43 @@ func GetCreateTableStmt(ctx *sql.Context, engine *sqle.Engine, tableName string)
 	}
 	return stmt + ";", nil
 }
+
+// GetExportTableStmts returns the statements that recreate the table given, for exports which are executed to import
+// the table. FULLTEXT indexes can't be defined in a CREATE TABLE statement, so they're removed from it and added by
+// the ALTER TABLE statements which follow it.
+func GetExportTableStmts(ctx *sql.Context, engine *sqle.Engine, tableName string, sch schema.Schema) (string, error) {
+	stmt, err := GetCreateTableStmt(ctx, engine, tableName)
+	if err != nil {
+		return "", err
+	}
+
+	var alterStmts []string
+	for _, index := range sch.Indexes().AllIndexes() {
+		if index.IsFullText() {
+			stmt = withoutTableElement(stmt, "FULLTEXT KEY "+sqlfmt.QuoteIdentifier(index.Name())+" (")
+			alterStmts = append(alterStmts, sqlfmt.AlterTableAddIndexStmt(tableName, index))
+		}
+	}
+	if len(alterStmts) == 0 {
+		return stmt, nil
+	}
+	return stmt + "\n" + strings.Join(alterStmts, "\n"), nil
+}
+
+// withoutTableElement removes the line of a CREATE TABLE statement which defines the table element starting with the
+// prefix given, along with the comma separating it from the other elements.
+func withoutTableElement(stmt, prefix string) string {
+	start := strings.Index(stmt, "\n  "+prefix)
+	if start < 0 {
+		return stmt
+	}
+	end := start + 1 + strings.IndexByte(stmt[start+1:], '\n')
+	if end <= start {
+		return stmt
+	}
+	if stmt[end-1] == ',' {
+		return stmt[:start] + stmt[end:]
+	}
+	// the last element, which takes the preceding element's comma with it
+	return strings.TrimSuffix(stmt[:start], ",") + stmt[end:]
+}
@@ -120,22 +120,26 @@ func (db *SingleTableInfoDatabase) WithIndexLookup(sql.IndexLookup) sql.Table {
 func (db *SingleTableInfoDatabase) GetIndexes(ctx *sql.Context) ([]sql.Index, error) {
 	var sqlIndexes []sql.Index
 	for _, index := range db.sch.Indexes().AllIndexes() {
-		cols := make([]schema.Column, index.Count())
-		for i, tag := range index.IndexedColumnTags() {
-			cols[i], _ = index.GetColumn(tag)
+		cols, exprs, err := indexLookupColumns(db.tableName, db.sch, index)
+		if err != nil {
+			return nil, err
 		}
 		sqlIndexes = append(sqlIndexes, &doltIndex{
-			cols:         cols,
-			db:           db,
-			id:           index.Name(),
-			indexRowData: types.EmptyMap,
-			indexSch:     index.Schema(),
-			table:        nil,
-			tableData:    types.EmptyMap,
-			tableName:    db.tableName,
-			tableSch:     db.sch,
-			unique:       index.IsUnique(),
-			comment:      index.Comment(),
+			cols:          cols,
+			exprs:         exprs,
+			prefixLengths: index.PrefixLengths(),
+			db:            db,
+			id:            index.Name(),
+			indexRowData:  types.EmptyMap,
+			indexSch:      index.Schema(),
+			table:         nil,
+			tableData:     types.EmptyMap,
+			tableName:     db.tableName,
+			tableSch:      db.sch,
+			unique:        index.IsUnique(),
+			fullText:      index.IsFullText(),
+			spatial:       index.IsSpatial(),
+			comment:       index.Comment(),
 		})
 	}
 	return sqlIndexes, nil
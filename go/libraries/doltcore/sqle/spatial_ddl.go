@@ -0,0 +1,460 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+	"github.com/dolthub/vitess/go/vt/sqlparser"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// spatialPlaceholderType is the type geometry columns are defined with when the engine executes a SpatialDDL. The
+// columns are empty, so their type can be changed once they're created.
+const spatialPlaceholderType = "LONGBLOB"
+
+// spatialTypes are the types of geometry columns, by the keyword declaring them.
+var spatialTypes = map[int]typeinfo.TypeInfo{
+	sqlparser.GEOMETRY:   typeinfo.GeometryType,
+	sqlparser.POINT:      typeinfo.PointType,
+	sqlparser.LINESTRING: typeinfo.LineStringType,
+	sqlparser.POLYGON:    typeinfo.PolygonType,
+}
+
+// SpatialColumnAlterableTable is a table whose new columns can be given geometry types.
+type SpatialColumnAlterableTable interface {
+	sql.Table
+	// SetSpatialColumnType gives the column named |colName|, which must not hold any values, the geometry type given.
+	SetSpatialColumnType(ctx *sql.Context, colName string, ti typeinfo.TypeInfo) error
+}
+
+var _ SpatialColumnAlterableTable = (*AlterableDoltTable)(nil)
+
+// SpatialColumn is a geometry column defined by a SpatialDDL.
+type SpatialColumn struct {
+	Name     string
+	TypeInfo typeinfo.TypeInfo
+}
+
+// SpatialDDL is a CREATE TABLE or ALTER TABLE statement that defines geometry columns or SPATIAL indexes. The SQL
+// parser doesn't support geometry column types, so these columns are defined with a placeholder type when the engine
+// executes the statement, and given their types afterwards. The SPATIAL indexes are removed from the statement and
+// added to the table once its columns have their types. CREATE SPATIAL INDEX statements are executed by the engine.
+type SpatialDDL struct {
+	// Query is the statement with its geometry column types replaced and its SPATIAL indexes removed. It's empty if
+	// nothing else is left to execute. It may still define CHECK constraints or expression and prefix indexes, see
+	// ParseCheckDDL and ParseIndexDDL.
+	Query string
+	// Database is the database of the table, if the statement qualified its name.
+	Database string
+	// TableName is the name of the table the columns and indexes are defined on.
+	TableName string
+	// CreatesTable is true for CREATE TABLE statements.
+	CreatesTable bool
+	// IfNotExists is true for CREATE TABLE IF NOT EXISTS statements.
+	IfNotExists bool
+	// Columns holds the geometry columns the statement defines.
+	Columns []SpatialColumn
+	// Indexes holds the SPATIAL indexes to add to the table, each of which has a single column.
+	Indexes []IndexDef
+}
+
+// ParseSpatialDDL returns the SpatialDDL for the query given, or nil if the query is not a CREATE TABLE or ALTER TABLE
+// statement that defines geometry columns or SPATIAL indexes.
+func ParseSpatialDDL(query string) (*SpatialDDL, error) {
+	// most queries aren't DDL, and don't need to be tokenized completely
+	if typ := firstTokenType(query); typ != sqlparser.CREATE && typ != sqlparser.ALTER {
+		return nil, nil
+	}
+
+	p, ok := newDDLParser(query)
+	if !ok {
+		return nil, nil
+	}
+
+	switch p.tok(0).typ {
+	case sqlparser.CREATE:
+		return p.parseCreateTableSpatial()
+	case sqlparser.ALTER:
+		return p.parseAlterTableSpatial()
+	default:
+		return nil, nil
+	}
+}
+
+// Exec executes the statement. |execQuery| is called to execute Query, after which the geometry columns are given
+// their types and the SPATIAL indexes are added to the table.
+func (sd *SpatialDDL) Exec(ctx *sql.Context, catalog *sql.Catalog, execQuery func(query string) error) error {
+	ddl := tableDDL{
+		query:        sd.Query,
+		database:     sd.Database,
+		tableName:    sd.TableName,
+		createsTable: sd.CreatesTable,
+		ifNotExists:  sd.IfNotExists,
+	}
+	return ddl.exec(ctx, catalog, execQuery, sd.apply)
+}
+
+func (sd *SpatialDDL) apply(ctx *sql.Context, tbl sql.Table) error {
+	if len(sd.Columns) > 0 {
+		colTbl, ok := tbl.(SpatialColumnAlterableTable)
+		if !ok {
+			return fmt.Errorf("table %s does not support geometry columns", sd.TableName)
+		}
+		for _, col := range sd.Columns {
+			err := colTbl.SetSpatialColumnType(ctx, col.Name, col.TypeInfo)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(sd.Indexes) > 0 {
+		indexTbl, ok := tbl.(sql.IndexAlterableTable)
+		if !ok {
+			return plan.ErrNotIndexable.New()
+		}
+		for _, index := range sd.Indexes {
+			err := indexTbl.CreateIndex(ctx, index.Name, sql.IndexUsing_Default, sql.IndexConstraint_Spatial, index.Columns, index.Comment)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// AddToSchema returns the schema of the table given, which was created from Query, with the geometry columns and
+// SPATIAL indexes defined by the statement. Used when a schema is created from a statement without executing it.
+func (sd *SpatialDDL) AddToSchema(tableName string, sch schema.Schema) (schema.Schema, error) {
+	var err error
+	for _, col := range sd.Columns {
+		sch, _, err = withSpatialColumnType(sch, col.Name, col.TypeInfo)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, index := range sd.Indexes {
+		col, ok := sch.GetAllCols().GetByNameCaseInsensitive(index.Columns[0].Name)
+		if !ok {
+			return nil, fmt.Errorf("column `%s` does not exist for the table", index.Columns[0].Name)
+		}
+		err = validateSpatialIndexColumn(col)
+		if err != nil {
+			return nil, err
+		}
+
+		name := index.Name
+		if name == "" {
+			name = unusedIndexName(sch, []string{col.Name})
+		}
+		_, err = sch.Indexes().AddIndexByColNames(name, []string{col.Name}, schema.IndexProperties{
+			IsUserDefined: true,
+			IsSpatial:     true,
+			Comment:       index.Comment,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return sch, nil
+}
+
+// SetSpatialColumnType implements SpatialColumnAlterableTable.
+func (t *AlterableDoltTable) SetSpatialColumnType(ctx *sql.Context, colName string, ti typeinfo.TypeInfo) error {
+	sch, err := t.table.GetSchema(ctx)
+	if err != nil {
+		return err
+	}
+
+	newSch, col, err := withSpatialColumnType(sch, colName, ti)
+	if err != nil {
+		return err
+	}
+
+	// the column was just added, so it only holds NULLs unless it was given a default value
+	rowData, err := t.table.GetRowData(ctx)
+	if err != nil {
+		return err
+	}
+	err = rowData.IterAll(ctx, func(key, value types.Value) error {
+		r, err := row.FromNoms(sch, key.(types.Tuple), value.(types.Tuple))
+		if err != nil {
+			return err
+		}
+		if val, ok := r.GetColVal(col.Tag); ok && !types.IsNull(val) {
+			return fmt.Errorf("column `%s` cannot be added with values to existing rows, as it's a %s column", col.Name, ti.ToSqlType().String())
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	newTable, err := t.table.UpdateSchema(ctx, newSch)
+	if err != nil {
+		return err
+	}
+	return t.updateTable(ctx, newTable)
+}
+
+// withSpatialColumnType returns the schema given with the column named |colName| given the geometry type |ti|, along
+// with the column as it was.
+func withSpatialColumnType(sch schema.Schema, colName string, ti typeinfo.TypeInfo) (schema.Schema, schema.Column, error) {
+	col, ok := sch.GetAllCols().GetByNameCaseInsensitive(colName)
+	if !ok {
+		return nil, schema.Column{}, fmt.Errorf("column `%s` does not exist for the table", colName)
+	}
+	if col.IsPartOfPK {
+		return nil, schema.Column{}, fmt.Errorf("column `%s` cannot be part of the primary key, as it's a %s column", col.Name, ti.ToSqlType().String())
+	}
+
+	newCol := col
+	newCol.Kind, newCol.TypeInfo = ti.NomsKind(), ti
+	cols, err := sch.GetAllCols().Replace(col, newCol)
+	if err != nil {
+		return nil, schema.Column{}, err
+	}
+	newSch, err := schema.SchemaFromCols(cols)
+	if err != nil {
+		return nil, schema.Column{}, err
+	}
+	newSch.Indexes().AddIndex(sch.Indexes().AllIndexes()...)
+	for _, check := range sch.Checks().AllChecks() {
+		_, err = newSch.Checks().AddCheck(check.Name, check.Expression, check.Enforced)
+		if err != nil {
+			return nil, schema.Column{}, err
+		}
+	}
+
+	return newSch, col, nil
+}
+
+func (p *ddlParser) parseCreateTableSpatial() (*SpatialDDL, error) {
+	sd := &SpatialDDL{CreatesTable: true}
+
+	i := 1
+	if p.tok(i).isWord("temporary") {
+		i++
+	}
+	if p.tok(i).typ != sqlparser.TABLE {
+		return nil, nil
+	}
+	i++
+
+	if p.tok(i).typ == sqlparser.IF && p.tok(i+1).typ == sqlparser.NOT && p.tok(i+2).typ == sqlparser.EXISTS {
+		sd.IfNotExists = true
+		i += 3
+	}
+
+	var ok bool
+	sd.Database, sd.TableName, i, ok = p.parseTableName(i)
+	if !ok || p.tok(i).typ != '(' {
+		return nil, nil
+	}
+
+	// |sep| is the '(' or ',' preceding the current table element, which is a SPATIAL index if it starts with SPATIAL,
+	// or a geometry column if its type is one of the spatial types.
+	sep := i
+	elemStart := true
+	depth := 1
+	for i++; depth > 0 && i < len(p.toks); {
+		t := p.tok(i)
+		switch {
+		case t.typ == '(':
+			depth++
+		case t.typ == ')':
+			depth--
+		case depth == 1 && t.typ == ',':
+			sep, elemStart = i, true
+			i++
+			continue
+		case depth == 1 && elemStart && t.typ == sqlparser.SPATIAL:
+			index, next, err := p.parseSpatialIndexDef(i)
+			if err != nil {
+				return nil, err
+			}
+
+			sd.Indexes = append(sd.Indexes, index)
+			i = p.cutElement(i, next, sep, elemStart)
+			continue
+		case depth == 1 && elemStart:
+			if p.parseSpatialColumn(sd, i) {
+				elemStart = false
+				i += 2
+				continue
+			}
+		}
+
+		elemStart = false
+		i++
+	}
+
+	if len(sd.Columns) == 0 && len(sd.Indexes) == 0 {
+		return nil, nil
+	}
+
+	sd.Query = p.queryWithoutCuts()
+	return sd, nil
+}
+
+// parseAlterTableSpatial parses an ALTER TABLE statement adding geometry columns or SPATIAL indexes. Other
+// specifications are left in Query.
+func (p *ddlParser) parseAlterTableSpatial() (*SpatialDDL, error) {
+	sd := &SpatialDDL{}
+
+	i := 1
+	if p.tok(i).typ == sqlparser.IGNORE {
+		i++
+	}
+	if p.tok(i).typ != sqlparser.TABLE {
+		return nil, nil
+	}
+
+	var ok bool
+	sd.Database, sd.TableName, i, ok = p.parseTableName(i + 1)
+	if !ok {
+		return nil, nil
+	}
+
+	// |sep| is the ',' preceding the current alter specification, or the table name preceding the first one
+	sep := i - 1
+	otherSpecs := false
+	for {
+		switch t := p.tok(i); {
+		case t.typ == sqlparser.ADD && p.tok(i+1).typ == sqlparser.SPATIAL:
+			index, next, err := p.parseSpatialIndexDef(i + 1)
+			if err != nil {
+				return nil, err
+			}
+
+			sd.Indexes = append(sd.Indexes, index)
+			if after := p.cutElement(i, next, sep, true); after > next {
+				// the comma following the first specification is cut along with it
+				i = after
+				continue
+			}
+			i = next
+		default:
+			otherSpecs = true
+			switch t.typ {
+			case sqlparser.ADD:
+				col := i + 1
+				if p.tok(col).typ == sqlparser.COLUMN {
+					col++
+				}
+				if p.parseSpatialColumn(sd, col) {
+					i = col + 2
+				}
+			case sqlparser.MODIFY, sqlparser.CHANGE:
+				typ := i + 2
+				if p.tok(i+1).typ == sqlparser.COLUMN {
+					typ++
+				}
+				if t.typ == sqlparser.CHANGE {
+					// the column's new name precedes its type
+					typ++
+				}
+				if _, ok := spatialTypes[p.tok(typ).typ]; ok {
+					return nil, fmt.Errorf("existing columns cannot be changed to %s columns", strings.ToUpper(p.tok(typ).val))
+				}
+			}
+
+			// skip to the next alter specification
+			for depth := 0; i < len(p.toks) && (depth > 0 || p.tok(i).typ != ','); i++ {
+				switch p.tok(i).typ {
+				case '(':
+					depth++
+				case ')':
+					depth--
+				}
+			}
+		}
+
+		switch p.tok(i).typ {
+		case ',':
+			sep = i
+			i++
+			continue
+		case 0, ';':
+		default:
+			return nil, fmt.Errorf("syntax error near '%s'", p.query[p.tok(i).start():])
+		}
+		break
+	}
+
+	if len(sd.Columns) == 0 && len(sd.Indexes) == 0 {
+		return nil, nil
+	}
+
+	if otherSpecs {
+		sd.Query = p.queryWithoutCuts()
+	}
+	return sd, nil
+}
+
+// parseSpatialColumn parses the start of a column definition at index |i|, adding the column to |sd| and replacing its
+// type with the placeholder type if it's a geometry column. It returns false if it isn't one.
+func (p *ddlParser) parseSpatialColumn(sd *SpatialDDL, i int) bool {
+	if p.tok(i).typ != sqlparser.ID {
+		return false
+	}
+	typ := p.tok(i + 1)
+	ti, ok := spatialTypes[typ.typ]
+	if !ok {
+		return false
+	}
+
+	sd.Columns = append(sd.Columns, SpatialColumn{Name: p.tok(i).val, TypeInfo: ti})
+	p.replace(typ.start(), typ.end, spatialPlaceholderType)
+	return true
+}
+
+// parseSpatialIndexDef parses an index definition of the form SPATIAL [INDEX | KEY] [name] (column) [COMMENT 'string']
+// starting at index |i|. It returns the index and the index of the token following it.
+func (p *ddlParser) parseSpatialIndexDef(i int) (index IndexDef, next int, err error) {
+	i++
+	if t := p.tok(i); t.typ == sqlparser.INDEX || t.typ == sqlparser.KEY {
+		i++
+	}
+
+	if p.tok(i).typ == sqlparser.ID {
+		index.Name = p.tok(i).val
+		i++
+	}
+
+	if p.tok(i).typ != '(' || p.tok(i+1).typ != sqlparser.ID || p.tok(i+2).typ != ')' {
+		return IndexDef{}, 0, fmt.Errorf("syntax error: a SPATIAL index must have exactly one column, without a prefix length")
+	}
+	index.Columns = []sql.IndexColumn{{Name: p.tok(i + 1).val}}
+	i += 3
+
+	i, err = p.parseIndexOptions(&index, i)
+	if err != nil {
+		return IndexDef{}, 0, err
+	}
+
+	return index, i, nil
+}
@@ -0,0 +1,188 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/dolthub/dolt/go/libraries/doltcore/geometry"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestParseSpatialDDL(t *testing.T) {
+	tests := []struct {
+		name        string
+		query       string
+		expected    *SpatialDDL
+		expectedErr bool
+	}{
+		{
+			name:     "not ddl",
+			query:    "select * from t where st_contains(g, st_geomfromtext('POINT(1 1)'))",
+			expected: nil,
+		},
+		{
+			name:     "table without geometry",
+			query:    "create table t (pk int primary key, c1 varchar(20), key (c1))",
+			expected: nil,
+		},
+		{
+			name:  "table with geometry columns and spatial index",
+			query: "create table if not exists db.t (pk int primary key, loc point not null, area polygon, spatial key loc_idx (loc), key (pk))",
+			expected: &SpatialDDL{
+				Query:        "create table if not exists db.t (pk int primary key, loc LONGBLOB not null, area LONGBLOB, key (pk))",
+				Database:     "db",
+				TableName:    "t",
+				CreatesTable: true,
+				IfNotExists:  true,
+				Columns: []SpatialColumn{
+					{Name: "loc", TypeInfo: typeinfo.PointType},
+					{Name: "area", TypeInfo: typeinfo.PolygonType},
+				},
+				Indexes: []IndexDef{{Name: "loc_idx", Columns: []sql.IndexColumn{{Name: "loc"}}}},
+			},
+		},
+		{
+			name:  "table with geometry and expression index",
+			query: "create table t (pk int primary key, g geometry, c1 varchar(20), index ((lower(c1))))",
+			expected: &SpatialDDL{
+				Query:        "create table t (pk int primary key, g LONGBLOB, c1 varchar(20), index ((lower(c1))))",
+				TableName:    "t",
+				CreatesTable: true,
+				Columns:      []SpatialColumn{{Name: "g", TypeInfo: typeinfo.GeometryType}},
+			},
+		},
+		{
+			name:  "alter add spatial index",
+			query: "alter table t add spatial index (loc) comment 'where'",
+			expected: &SpatialDDL{
+				TableName: "t",
+				Indexes:   []IndexDef{{Columns: []sql.IndexColumn{{Name: "loc"}}, Comment: "where"}},
+			},
+		},
+		{
+			name:  "alter add geometry column and spatial index",
+			query: "alter table t add column path linestring not null, add spatial key (path)",
+			expected: &SpatialDDL{
+				Query:     "alter table t add column path LONGBLOB not null",
+				TableName: "t",
+				Columns:   []SpatialColumn{{Name: "path", TypeInfo: typeinfo.LineStringType}},
+				Indexes:   []IndexDef{{Columns: []sql.IndexColumn{{Name: "path"}}}},
+			},
+		},
+		{
+			name:        "alter modify to geometry",
+			query:       "alter table t modify column c1 point",
+			expectedErr: true,
+		},
+		{
+			name:        "spatial index on several columns",
+			query:       "create table t (pk int primary key, a point not null, b point not null, spatial key (a, b))",
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := ParseSpatialDDL(test.query)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestSpatialColumnsAndIndexes(t *testing.T) {
+	dEnv := dtestutils.CreateTestEnv()
+	root, err := dEnv.WorkingRoot(context.Background())
+	require.NoError(t, err)
+
+	root, err = ExecuteSql(dEnv, root, `
+CREATE TABLE places (
+  pk BIGINT PRIMARY KEY,
+  loc POINT NOT NULL,
+  SPATIAL KEY loc_idx (loc)
+);
+INSERT INTO places VALUES (1, 'POINT(1 2)'), (2, 'POINT(50 50)');
+ALTER TABLE places ADD COLUMN area POLYGON;
+`)
+	require.NoError(t, err)
+
+	_, err = ExecuteSql(dEnv, root, "INSERT INTO places VALUES (3, 'LINESTRING(0 0, 1 1)', NULL)")
+	assert.Error(t, err)
+	_, err = ExecuteSql(dEnv, root, "INSERT INTO places VALUES (3, NULL, NULL)")
+	assert.Error(t, err)
+	_, err = ExecuteSql(dEnv, root, "ALTER TABLE places ADD SPATIAL INDEX (area)")
+	assert.Error(t, err)
+	_, err = ExecuteSql(dEnv, root, "CREATE TABLE bad (pk POINT PRIMARY KEY)")
+	assert.Error(t, err)
+
+	rows, err := ExecuteSelect(dEnv, dEnv.DoltDB, root, "SELECT pk, loc, area FROM places ORDER BY pk")
+	require.NoError(t, err)
+	assert.Equal(t, []sql.Row{
+		{int64(1), geometry.Point{X: 1, Y: 2}, nil},
+		{int64(2), geometry.Point{X: 50, Y: 50}, nil},
+	}, rows)
+
+	rows, err = ExecuteSelect(dEnv, dEnv.DoltDB, root, "SHOW CREATE TABLE places")
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, "CREATE TABLE `places` (\n"+
+		"  `pk` bigint NOT NULL,\n"+
+		"  `loc` point NOT NULL,\n"+
+		"  `area` polygon,\n"+
+		"  PRIMARY KEY (`pk`),\n"+
+		"  SPATIAL KEY `loc_idx` (`loc`)\n"+
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4", rows[0][1])
+
+	// the index holds the cell of each row's geometry
+	tbl, ok, err := root.GetTable(context.Background(), "places")
+	require.NoError(t, err)
+	require.True(t, ok)
+	indexData, err := tbl.GetIndexRowData(context.Background(), "loc_idx")
+	require.NoError(t, err)
+	sch, err := tbl.GetSchema(context.Background())
+	require.NoError(t, err)
+	index := sch.Indexes().GetByName("loc_idx")
+	require.NotNil(t, index)
+	assert.True(t, index.IsSpatial())
+
+	var cells []string
+	err = indexData.IterAll(context.Background(), func(key, _ types.Value) error {
+		val, err := key.(types.Tuple).Get(1)
+		if err != nil {
+			return err
+		}
+		cells = append(cells, string(val.(types.String)))
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		geometry.SpatialCell(geometry.Point{X: 1, Y: 2}.Bounds()),
+		geometry.SpatialCell(geometry.Point{X: 50, Y: 50}.Bounds()),
+	}, cells)
+	assert.Equal(t, schema.SpatialCellTag, index.Schema().GetPKCols().GetByIndex(0).Tag)
+}
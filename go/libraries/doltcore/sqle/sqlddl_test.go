@@ -1382,10 +1382,9 @@ INSERT INTO fail_unique VALUES (1, 1, 1), (2, 2, 2), (3, 2, 3);
 }
 
 func schemasTableDoltSchema() schema.Schema {
-	// this is a dummy test environment and will not be used,
-	// dolt_schema table tags will be parsed from the comments in SchemaTableSchema()
-	testEnv := dtestutils.CreateTestEnv()
-	return mustGetDoltSchema(SchemasTableSqlSchema(), doltdb.SchemasTableName, testEnv)
+	// the dolt_schemas table is created from its dolt schema directly, so its string columns are stored inline rather
+	// than as the blobs that a round trip through its sql schema would give them
+	return SchemasTableSchema()
 }
 
 func assertFails(t *testing.T, dEnv *env.DoltEnv, query, expectedErr string) {
@@ -15,8 +15,11 @@
 package sqlfmt
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/dolthub/vitess/go/sqltypes"
@@ -39,8 +42,19 @@ func QuoteComment(s string) string {
 	return `'` + strings.ReplaceAll(s, `'`, `\'`) + `'`
 }
 
-func RowAsInsertStmt(r row.Row, tableName string, tableSch schema.Schema) (string, error) {
+func RowAsInsertStmt(ctx context.Context, r row.Row, tableName string, tableSch schema.Schema) (string, error) {
 	var b strings.Builder
+	err := WriteRowAsInsertStmt(ctx, &b, r, tableName, tableSch)
+	if err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// WriteRowAsInsertStmt writes the statement which inserts the row given to wr. The values of columns which are stored as
+// blobs are escaped as they're read from the chunks they're stored in, rather than being read into memory first.
+func WriteRowAsInsertStmt(ctx context.Context, wr io.Writer, r row.Row, tableName string, tableSch schema.Schema) error {
+	b := bufio.NewWriter(wr)
 	b.WriteString("INSERT INTO ")
 	b.WriteString(QuoteIdentifier(tableName))
 	b.WriteString(" ")
@@ -57,7 +71,7 @@ func RowAsInsertStmt(r row.Row, tableName string, tableSch schema.Schema) (strin
 	})
 
 	if err != nil {
-		return "", err
+		return err
 	}
 
 	b.WriteString(")")
@@ -69,26 +83,36 @@ func RowAsInsertStmt(r row.Row, tableName string, tableSch schema.Schema) (strin
 			b.WriteRune(',')
 		}
 		col, _ := tableSch.GetAllCols().GetByTag(tag)
-		sqlString, err := valueAsSqlString(col.TypeInfo, val)
+		err = writeValueAsSqlString(ctx, b, col.TypeInfo, val)
 		if err != nil {
 			return true, err
 		}
-		b.WriteString(sqlString)
 		seenOne = true
 		return false, nil
 	})
 
 	if err != nil {
-		return "", err
+		return err
 	}
 
 	b.WriteString(");")
 
-	return b.String(), nil
+	return b.Flush()
 }
 
-func RowAsDeleteStmt(r row.Row, tableName string, tableSch schema.Schema) (string, error) {
+func RowAsDeleteStmt(ctx context.Context, r row.Row, tableName string, tableSch schema.Schema) (string, error) {
 	var b strings.Builder
+	err := WriteRowAsDeleteStmt(ctx, &b, r, tableName, tableSch)
+	if err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// WriteRowAsDeleteStmt writes the statement which deletes the row given to wr. The values of columns which are stored as
+// blobs are escaped as they're read from the chunks they're stored in, rather than being read into memory first.
+func WriteRowAsDeleteStmt(ctx context.Context, wr io.Writer, r row.Row, tableName string, tableSch schema.Schema) error {
+	b := bufio.NewWriter(wr)
 	b.WriteString("DELETE FROM ")
 	b.WriteString(QuoteIdentifier(tableName))
 
@@ -100,28 +124,38 @@ func RowAsDeleteStmt(r row.Row, tableName string, tableSch schema.Schema) (strin
 			if seenOne {
 				b.WriteString(" AND ")
 			}
-			sqlString, err := valueAsSqlString(col.TypeInfo, val)
+			b.WriteString(QuoteIdentifier(col.Name))
+			b.WriteRune('=')
+			err = writeValueAsSqlString(ctx, b, col.TypeInfo, val)
 			if err != nil {
 				return true, err
 			}
-			b.WriteString(QuoteIdentifier(col.Name))
-			b.WriteRune('=')
-			b.WriteString(sqlString)
 			seenOne = true
 		}
 		return false, nil
 	})
 
 	if err != nil {
-		return "", err
+		return err
 	}
 
 	b.WriteString(");")
-	return b.String(), nil
+	return b.Flush()
 }
 
-func RowAsUpdateStmt(r row.Row, tableName string, tableSch schema.Schema) (string, error) {
+func RowAsUpdateStmt(ctx context.Context, r row.Row, tableName string, tableSch schema.Schema) (string, error) {
 	var b strings.Builder
+	err := WriteRowAsUpdateStmt(ctx, &b, r, tableName, tableSch)
+	if err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// WriteRowAsUpdateStmt writes the statement which updates the row given to wr. The values of columns which are stored as
+// blobs are escaped as they're read from the chunks they're stored in, rather than being read into memory first.
+func WriteRowAsUpdateStmt(ctx context.Context, wr io.Writer, r row.Row, tableName string, tableSch schema.Schema) error {
+	b := bufio.NewWriter(wr)
 	b.WriteString("UPDATE ")
 	b.WriteString(QuoteIdentifier(tableName))
 	b.WriteString(" ")
@@ -134,20 +168,19 @@ func RowAsUpdateStmt(r row.Row, tableName string, tableSch schema.Schema) (strin
 			if seenOne {
 				b.WriteRune(',')
 			}
-			sqlString, err := valueAsSqlString(col.TypeInfo, val)
+			b.WriteString(QuoteIdentifier(col.Name))
+			b.WriteRune('=')
+			err = writeValueAsSqlString(ctx, b, col.TypeInfo, val)
 			if err != nil {
 				return true, err
 			}
-			b.WriteString(QuoteIdentifier(col.Name))
-			b.WriteRune('=')
-			b.WriteString(sqlString)
 			seenOne = true
 		}
 		return false, nil
 	})
 
 	if err != nil {
-		return "", err
+		return err
 	}
 
 	b.WriteString(" WHERE (")
@@ -158,32 +191,51 @@ func RowAsUpdateStmt(r row.Row, tableName string, tableSch schema.Schema) (strin
 			if seenOne {
 				b.WriteString(" AND ")
 			}
-			sqlString, err := valueAsSqlString(col.TypeInfo, val)
+			b.WriteString(QuoteIdentifier(col.Name))
+			b.WriteRune('=')
+			err = writeValueAsSqlString(ctx, b, col.TypeInfo, val)
 			if err != nil {
 				return true, err
 			}
-			b.WriteString(QuoteIdentifier(col.Name))
-			b.WriteRune('=')
-			b.WriteString(sqlString)
 			seenOne = true
 		}
 		return false, nil
 	})
 
 	if err != nil {
-		return "", err
+		return err
 	}
 
 	b.WriteString(");")
-	return b.String(), nil
+	return b.Flush()
+}
+
+// writeValueAsSqlString writes the SQL literal for a value to b. Blobs are quoted and escaped as they're read.
+func writeValueAsSqlString(ctx context.Context, b *bufio.Writer, ti typeinfo.TypeInfo, value types.Value) error {
+	if blob, ok := value.(types.Blob); ok {
+		b.WriteString(singleQuote)
+		_, err := io.Copy(sqlEscapingWriter{b}, blob.Reader(ctx))
+		if err != nil {
+			return err
+		}
+		b.WriteString(singleQuote)
+		return nil
+	}
+
+	sqlString, err := valueAsSqlString(ctx, ti, value)
+	if err != nil {
+		return err
+	}
+	b.WriteString(sqlString)
+	return nil
 }
 
-func valueAsSqlString(ti typeinfo.TypeInfo, value types.Value) (string, error) {
+func valueAsSqlString(ctx context.Context, ti typeinfo.TypeInfo, value types.Value) (string, error) {
 	if types.IsNull(value) {
 		return "NULL", nil
 	}
 
-	str, err := ti.FormatValue(value)
+	str, err := ti.FormatValue(ctx, value)
 
 	if err != nil {
 		return "", err
@@ -210,6 +262,10 @@ func valueAsSqlString(ti typeinfo.TypeInfo, value types.Value) (string, error) {
 			return "", fmt.Errorf("typeinfo.VarStringTypeIdentifier is not types.String")
 		}
 		return quoteAndEscapeString(string(s)), nil
+	case typeinfo.BlobBinaryTypeIdentifier, typeinfo.BlobStringTypeIdentifier, typeinfo.JSONTypeIdentifier:
+		return quoteAndEscapeString(*str), nil
+	case typeinfo.SpatialTypeIdentifier:
+		return "ST_GeomFromText(" + quoteAndEscapeString(*str) + ")", nil
 	default:
 		return *str, nil
 	}
@@ -225,3 +281,20 @@ func quoteAndEscapeString(s string) string {
 	v.EncodeSQL(buf)
 	return buf.String()
 }
+
+// sqlEscapingWriter escapes the bytes written to it as quoteAndEscapeString does, writing them to the wrapped Writer.
+type sqlEscapingWriter struct {
+	b *bufio.Writer
+}
+
+func (w sqlEscapingWriter) Write(p []byte) (int, error) {
+	for _, ch := range p {
+		if encodedChar := sqltypes.SQLEncodeMap[ch]; encodedChar == sqltypes.DontEscape {
+			w.b.WriteByte(ch)
+		} else {
+			w.b.WriteByte('\\')
+			w.b.WriteByte(encodedChar)
+		}
+	}
+	return len(p), nil
+}
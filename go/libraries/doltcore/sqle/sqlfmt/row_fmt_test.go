@@ -15,6 +15,8 @@
 package sqlfmt
 
 import (
+	"context"
+	"strings"
 	"testing"
 
 	"github.com/google/uuid"
@@ -116,9 +118,22 @@ func TestRowAsInsertStmt(t *testing.T) {
 		expectedOutput: "INSERT INTO `people` (`a name with spaces`,`anotherColumn`) VALUES (-3.14,-42);",
 	})
 
+	textCol, err := schema.NewColumnWithTypeInfo("text", 1, typeinfo.LongTextType, false, "", false, "")
+	require.NoError(t, err)
+	blobSch := dtestutils.CreateSchema(schema.NewColumn("id", 0, types.IntKind, true), textCol)
+	blob, err := types.NewBlob(context.Background(), types.NewMemoryValueStore(), strings.NewReader("It's a \"blob\"\n"))
+	require.NoError(t, err)
+
+	tests = append(tests, test{
+		name:           "blob values",
+		row:            dtestutils.NewRow(blobSch, types.Int(1), blob),
+		sch:            blobSch,
+		expectedOutput: "INSERT INTO `people` (`id`,`text`) VALUES (1,'It\\'s a \\\"blob\\\"\\n');",
+	})
+
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			stmt, err := RowAsInsertStmt(tt.row, tableName, tt.sch)
+			stmt, err := RowAsInsertStmt(context.Background(), tt.row, tableName, tt.sch)
 			assert.NoError(t, err)
 			assert.Equal(t, tt.expectedOutput, stmt)
 		})
@@ -143,7 +158,7 @@ func TestRowAsDeleteStmt(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			stmt, err := RowAsDeleteStmt(tt.row, tableName, tt.sch)
+			stmt, err := RowAsDeleteStmt(context.Background(), tt.row, tableName, tt.sch)
 			assert.NoError(t, err)
 			assert.Equal(t, tt.expectedOutput, stmt)
 		})
@@ -189,7 +204,7 @@ func TestRowAsUpdateStmt(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			stmt, err := RowAsUpdateStmt(tt.row, tableName, tt.sch)
+			stmt, err := RowAsUpdateStmt(context.Background(), tt.row, tableName, tt.sch)
 			assert.NoError(t, err)
 			assert.Equal(t, tt.expectedOutput, stmt)
 		})
@@ -236,17 +251,29 @@ func TestValueAsSqlString(t *testing.T) {
 			ti:   typeinfo.StringDefaultType,
 			exp:  "'\\0\\'\\\"\\b\\n\\r\\t\\Z\\\\'",
 		},
+		{
+			name: "json",
+			val:  mustJSON(t, `{"a": "it's"}`),
+			ti:   typeinfo.JSONType,
+			exp:  `'{\"a\":\"it\'s\"}'`,
+		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			act, err := valueAsSqlString(test.ti, test.val)
+			act, err := valueAsSqlString(context.Background(), test.ti, test.val)
 			require.NoError(t, err)
 			assert.Equal(t, test.exp, act)
 		})
 	}
 }
 
+func mustJSON(t *testing.T, text string) types.Value {
+	v, err := typeinfo.JSONType.ConvertValueToNomsValue(context.Background(), nil, text)
+	require.NoError(t, err)
+	return v
+}
+
 func strPointer(s string) *string {
 	return &s
 }
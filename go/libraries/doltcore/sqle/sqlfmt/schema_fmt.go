@@ -73,16 +73,15 @@ func FmtIndex(index schema.Index) string {
 	sb := strings.Builder{}
 	if index.IsUnique() {
 		sb.WriteString("UNIQUE ")
+	} else if index.IsFullText() {
+		sb.WriteString("FULLTEXT ")
+	} else if index.IsSpatial() {
+		sb.WriteString("SPATIAL ")
 	}
 	sb.WriteString("INDEX ")
 	sb.WriteString(QuoteIdentifier(index.Name()))
 	sb.WriteString(" (")
-	for i, indexColName := range index.ColumnNames() {
-		if i != 0 {
-			sb.WriteRune(',')
-		}
-		sb.WriteString(QuoteIdentifier(indexColName))
-	}
+	sb.WriteString(FmtIndexKeyParts(index))
 	sb.WriteRune(')')
 	if len(index.Comment()) > 0 {
 		sb.WriteString(" COMMENT ")
@@ -91,6 +90,28 @@ func FmtIndex(index schema.Index) string {
 	return sb.String()
 }
 
+// FmtIndexKeyParts returns the key parts of the index given as they appear in its definition, which are column names
+// optionally followed by a prefix length, or parenthesized expressions.
+func FmtIndexKeyParts(index schema.Index) string {
+	var parts []string
+	if len(index.Expressions()) > 0 {
+		for _, expr := range index.Expressions() {
+			parts = append(parts, "("+expr.Expression+")")
+		}
+		return strings.Join(parts, ",")
+	}
+
+	for _, tag := range index.IndexedColumnTags() {
+		col, _ := index.GetColumn(tag)
+		part := QuoteIdentifier(col.Name)
+		if length := schema.PrefixLength(index, tag); length > 0 {
+			part += fmt.Sprintf("(%d)", length)
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ",")
+}
+
 func FmtForeignKey(fk doltdb.ForeignKey, sch, parentSch schema.Schema) string {
 	sb := strings.Builder{}
 	sb.WriteString("CONSTRAINT ")
@@ -188,13 +209,15 @@ func AlterTableAddIndexStmt(tableName string, idx schema.Index) string {
 	var b strings.Builder
 	b.WriteString("ALTER TABLE ")
 	b.WriteString(QuoteIdentifier(tableName))
-	b.WriteString(" ADD INDEX ")
-	b.WriteString(QuoteIdentifier(idx.Name()))
-	var cols []string
-	for _, cn := range idx.ColumnNames() {
-		cols = append(cols, QuoteIdentifier(cn))
+	if idx.IsFullText() {
+		b.WriteString(" ADD FULLTEXT INDEX ")
+	} else if idx.IsSpatial() {
+		b.WriteString(" ADD SPATIAL INDEX ")
+	} else {
+		b.WriteString(" ADD INDEX ")
 	}
-	b.WriteString("(" + strings.Join(cols, ",") + ");")
+	b.WriteString(QuoteIdentifier(idx.Name()))
+	b.WriteString("(" + FmtIndexKeyParts(idx) + ");")
 	return b.String()
 }
 
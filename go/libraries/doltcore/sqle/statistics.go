@@ -0,0 +1,585 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+const (
+	// statisticsSampleSize is the maximum number of rows of a table sampled to build the histograms of its columns
+	statisticsSampleSize = 20000
+	// histogramBuckets is the maximum number of buckets in a histogram
+	histogramBuckets = 64
+	// maxHistogramBoundLength is the length string histogram bounds are truncated to, which keeps the histograms of
+	// columns with long values small
+	maxHistogramBoundLength = 42
+
+	// defaultEqualitySelectivity is the fraction of a table's rows estimated to have a value looked up in an index,
+	// when there are no statistics for the column
+	defaultEqualitySelectivity = 0.1
+	// defaultRangeSelectivity is the fraction of a table's rows estimated to be in a range looked up in an index, when
+	// there are no statistics for the column
+	defaultRangeSelectivity = 1.0 / 3
+	// indexReadCost is the cost of reading a row through an index, relative to the cost of reading a row in a scan of
+	// the table.  Every key read from an index is looked up in the table's row data.
+	indexReadCost = 3.0
+)
+
+// columnStatistics are the statistics of a column of a table.
+type columnStatistics struct {
+	column        string
+	rowCount      uint64
+	nullCount     uint64
+	distinctCount uint64
+	// histogram is the distribution of the column's values, or nil for columns whose values aren't compared in order
+	histogram *histogram
+}
+
+// storedColumnStatistics is the form columnStatistics are serialized in.
+type storedColumnStatistics struct {
+	Column        string     `json:"column"`
+	RowCount      uint64     `json:"row_count"`
+	NullCount     uint64     `json:"null_count"`
+	DistinctCount uint64     `json:"distinct_count"`
+	Histogram     *histogram `json:"histogram,omitempty"`
+}
+
+// MarshalJSON serializes the statistics as a storedColumnStatistics.
+func (cs *columnStatistics) MarshalJSON() ([]byte, error) {
+	return json.Marshal(storedColumnStatistics{cs.column, cs.rowCount, cs.nullCount, cs.distinctCount, cs.histogram})
+}
+
+// UnmarshalJSON deserializes statistics serialized by MarshalJSON.
+func (cs *columnStatistics) UnmarshalJSON(data []byte) error {
+	var stored storedColumnStatistics
+	err := json.Unmarshal(data, &stored)
+	if err != nil {
+		return err
+	}
+
+	*cs = columnStatistics{stored.Column, stored.RowCount, stored.NullCount, stored.DistinctCount, stored.Histogram}
+	return nil
+}
+
+// histogram is an equi-height histogram of the values of a column, built from a sample of its rows.  It's serialized
+// in the same form as the histograms in MySQL's information_schema.column_statistics.
+type histogram struct {
+	Buckets                  []*histogramBucket `json:"buckets"`
+	NullValues               float64            `json:"null-values"`
+	SamplingRate             float64            `json:"sampling-rate"`
+	HistogramType            string             `json:"histogram-type"`
+	NumberOfBucketsSpecified int                `json:"number-of-buckets-specified"`
+}
+
+// histogramBucket is a bucket of a histogram, holding the values from its lower bound to its upper bound.  Its
+// frequency is the cumulative fraction of all the rows of the table, including those with NULL values, which are in
+// this bucket or a preceding one.
+type histogramBucket struct {
+	Lower               string
+	Upper               string
+	CumulativeFrequency float64
+	Distinct            uint64
+
+	// lower and upper are the parsed bounds, which are compared to the values looked up
+	lower types.Value
+	upper types.Value
+}
+
+// MarshalJSON serializes the bucket as an array of its bounds, frequency and number of distinct values.
+func (b *histogramBucket) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]interface{}{b.Lower, b.Upper, b.CumulativeFrequency, b.Distinct})
+}
+
+// UnmarshalJSON deserializes a bucket serialized by MarshalJSON.
+func (b *histogramBucket) UnmarshalJSON(data []byte) error {
+	var fields []json.RawMessage
+	err := json.Unmarshal(data, &fields)
+	if err != nil {
+		return err
+	}
+	if len(fields) != 4 {
+		return fmt.Errorf("invalid histogram bucket: %s", string(data))
+	}
+
+	for i, dest := range []interface{}{&b.Lower, &b.Upper, &b.CumulativeFrequency, &b.Distinct} {
+		err = json.Unmarshal(fields[i], dest)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// tableStatistics are the statistics of the columns of a table, keyed by the lowercase names of the columns.
+type tableStatistics struct {
+	nbf     *types.NomsBinFormat
+	columns map[string]*columnStatistics
+}
+
+// column returns the statistics of the column given, or nil if there are none.
+func (ts *tableStatistics) column(name string) *columnStatistics {
+	if ts == nil {
+		return nil
+	}
+	return ts.columns[strings.ToLower(name)]
+}
+
+// equalitySelectivity returns the estimated fraction of a table's rows which have the value |val| in |col|.
+func (ts *tableStatistics) equalitySelectivity(col schema.Column, val types.Value) float64 {
+	if types.IsNull(val) {
+		return 0
+	}
+
+	stats := ts.column(col.Name)
+	if stats == nil || stats.rowCount == 0 {
+		return defaultEqualitySelectivity
+	}
+
+	if stats.histogram != nil {
+		return stats.histogram.equalFraction(ts.nbf, val)
+	} else if stats.distinctCount > 0 {
+		return float64(stats.rowCount-stats.nullCount) / float64(stats.rowCount) / float64(stats.distinctCount)
+	}
+
+	return defaultEqualitySelectivity
+}
+
+// rangeSelectivity returns the estimated fraction of a table's rows with values of |col| between |lower| and |upper|,
+// which are unbounded when nil.
+func (ts *tableStatistics) rangeSelectivity(col schema.Column, lower types.Value, lowerInclusive bool, upper types.Value, upperInclusive bool) float64 {
+	stats := ts.column(col.Name)
+	if stats == nil || stats.histogram == nil {
+		return defaultRangeSelectivity
+	}
+
+	h := stats.histogram
+	below := 0.0
+	if lower != nil {
+		below = h.lessFraction(ts.nbf, lower, !lowerInclusive)
+	}
+
+	upTo := 1 - h.NullValues
+	if upper != nil {
+		upTo = h.lessFraction(ts.nbf, upper, upperInclusive)
+	}
+
+	return math.Max(upTo-below, 0)
+}
+
+// equalFraction returns the estimated fraction of rows with the value given.
+func (h *histogram) equalFraction(nbf *types.NomsBinFormat, val types.Value) float64 {
+	prev := 0.0
+	for _, b := range h.Buckets {
+		if isLess(nbf, val, b.lower) {
+			return 0
+		} else if !isLess(nbf, b.upper, val) {
+			return (b.CumulativeFrequency - prev) / math.Max(float64(b.Distinct), 1)
+		}
+		prev = b.CumulativeFrequency
+	}
+
+	return 0
+}
+
+// lessFraction returns the estimated fraction of rows with values less than the value given, or less than or equal to
+// it if |inclusive| is true.
+func (h *histogram) lessFraction(nbf *types.NomsBinFormat, val types.Value, inclusive bool) float64 {
+	prev := 0.0
+	for _, b := range h.Buckets {
+		if isLess(nbf, val, b.lower) || (!inclusive && val.Equals(b.lower)) {
+			return prev
+		} else if !(isLess(nbf, b.upper, val) || (inclusive && val.Equals(b.upper))) {
+			// the value is inside the bucket
+			return prev + (b.CumulativeFrequency-prev)/2
+		}
+		prev = b.CumulativeFrequency
+	}
+
+	return prev
+}
+
+func isLess(nbf *types.NomsBinFormat, a, b types.Value) bool {
+	less, err := a.Less(nbf, b)
+	return err == nil && less
+}
+
+// lazyStatistics loads the statistics of a table the first time they're needed.
+type lazyStatistics struct {
+	once  sync.Once
+	load  func() (*tableStatistics, error)
+	stats *tableStatistics
+}
+
+// get returns the statistics of the table, or nil if they couldn't be loaded or the table hasn't been analyzed.  The
+// statistics only guide the choice of the way a table is read, so an error loading them isn't fatal.
+func (ls *lazyStatistics) get() *tableStatistics {
+	if ls == nil {
+		return nil
+	}
+
+	ls.once.Do(func() {
+		stats, err := ls.load()
+		if err == nil {
+			ls.stats = stats
+		}
+	})
+
+	return ls.stats
+}
+
+// collectTableStatistics reads the rows of |tbl| and returns the statistics of each of its columns.  The row and NULL
+// counts are exact, while the histograms and the number of distinct values are built from a sample of the rows.
+func collectTableStatistics(ctx context.Context, tbl *doltdb.Table) ([]*columnStatistics, error) {
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rowData, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cols := sch.GetAllCols().GetColumns()
+	nullCounts := make([]uint64, len(cols))
+	var sample [][]types.Value
+	var rowCount uint64
+
+	// the sample is chosen with a reservoir, using a fixed seed so that analyzing the same rows gives the same results
+	rnd := rand.New(rand.NewSource(1))
+	err = rowData.IterAll(ctx, func(key, value types.Value) error {
+		r, err := row.FromNoms(sch, key.(types.Tuple), value.(types.Tuple))
+		if err != nil {
+			return err
+		}
+
+		vals := make([]types.Value, len(cols))
+		for i, col := range cols {
+			val, ok := r.GetColVal(col.Tag)
+			if !ok || types.IsNull(val) {
+				nullCounts[i]++
+				val = nil
+			}
+			vals[i] = val
+		}
+
+		if len(sample) < statisticsSampleSize {
+			sample = append(sample, vals)
+		} else if j := rnd.Int63n(int64(rowCount) + 1); j < statisticsSampleSize {
+			sample[j] = vals
+		}
+		rowCount++
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make([]*columnStatistics, len(cols))
+	for i, col := range cols {
+		var vals []types.Value
+		for _, sampled := range sample {
+			if sampled[i] != nil {
+				vals = append(vals, sampled[i])
+			}
+		}
+
+		distinct, err := estimateDistinct(tbl.Format(), vals, rowCount-nullCounts[i])
+		if err != nil {
+			return nil, err
+		}
+
+		stats[i] = &columnStatistics{
+			column:        col.Name,
+			rowCount:      rowCount,
+			nullCount:     nullCounts[i],
+			distinctCount: distinct,
+		}
+
+		if col.Kind != types.BlobKind && len(vals) > 0 {
+			stats[i].histogram, err = newHistogram(ctx, tbl.Format(), col.TypeInfo, vals, len(sample), rowCount)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return stats, nil
+}
+
+// estimateDistinct estimates the number of distinct values among |total| non-NULL values from a sample of them, with
+// the Guaranteed-Error Estimator of Charikar et al: values seen once in the sample are scaled up, while values seen
+// more than once are assumed to have been seen already.
+func estimateDistinct(nbf *types.NomsBinFormat, sample []types.Value, total uint64) (uint64, error) {
+	counts := make(map[hash.Hash]int)
+	for _, val := range sample {
+		h, err := val.Hash(nbf)
+		if err != nil {
+			return 0, err
+		}
+		counts[h]++
+	}
+
+	if uint64(len(sample)) == total {
+		return uint64(len(counts)), nil
+	}
+
+	var once int
+	for _, count := range counts {
+		if count == 1 {
+			once++
+		}
+	}
+
+	estimate := math.Sqrt(float64(total)/float64(len(sample)))*float64(once) + float64(len(counts)-once)
+	return uint64(math.Min(math.Round(estimate), float64(total))), nil
+}
+
+// newHistogram builds the histogram of the non-NULL values |vals| of a column in a sample of |sampled| of a table's
+// |rowCount| rows.  Each bucket holds about the same number of values, and all of the occurrences of a value are in
+// the same bucket.
+func newHistogram(ctx context.Context, nbf *types.NomsBinFormat, ti typeinfo.TypeInfo, vals []types.Value, sampled int, rowCount uint64) (*histogram, error) {
+	var sortErr error
+	sort.Slice(vals, func(i, j int) bool {
+		less, err := vals[i].Less(nbf, vals[j])
+		if err != nil {
+			sortErr = err
+		}
+		return less
+	})
+	if sortErr != nil {
+		return nil, sortErr
+	}
+
+	h := &histogram{
+		NullValues:               float64(sampled-len(vals)) / float64(sampled),
+		SamplingRate:             float64(sampled) / float64(rowCount),
+		HistogramType:            "equi-height",
+		NumberOfBucketsSpecified: histogramBuckets,
+	}
+
+	perBucket := (len(vals) + histogramBuckets - 1) / histogramBuckets
+	for start := 0; start < len(vals); {
+		end, distinct := start+1, uint64(1)
+		for end < len(vals) {
+			same := vals[end].Equals(vals[end-1])
+			if !same && end-start >= perBucket {
+				break
+			} else if !same {
+				distinct++
+			}
+			end++
+		}
+
+		lower, err := formatHistogramBound(ctx, ti, vals[start])
+		if err != nil {
+			return nil, err
+		}
+		upper, err := formatHistogramBound(ctx, ti, vals[end-1])
+		if err != nil {
+			return nil, err
+		}
+
+		h.Buckets = append(h.Buckets, &histogramBucket{
+			Lower:               lower,
+			Upper:               upper,
+			CumulativeFrequency: float64(end) / float64(sampled),
+			Distinct:            distinct,
+		})
+		start = end
+	}
+
+	return h, nil
+}
+
+func formatHistogramBound(ctx context.Context, ti typeinfo.TypeInfo, val types.Value) (string, error) {
+	str, err := ti.FormatValue(ctx, val)
+	if err != nil || str == nil {
+		return "", err
+	}
+
+	if ti.NomsKind() == types.StringKind && len(*str) > maxHistogramBoundLength {
+		return (*str)[:maxHistogramBoundLength], nil
+	}
+	return *str, nil
+}
+
+// statisticsStore holds the statistics collected by ANALYZE TABLE for the tables of a repository, keyed by the
+// lowercase names of the tables.  Statistics describe the data of the tables rather than being part of it, so they're
+// kept outside of the root values, where they would be committed, merged and shown as changes.  They're stored in a
+// file of the repository's .dolt directory when there is one, and in memory otherwise, and are shared by every branch.
+type statisticsStore struct {
+	mu   *sync.Mutex
+	fs   filesys.ReadWriteFS
+	path string
+	// tables holds the serialized statistics of each table, so that each reader gets its own copy to parse the
+	// histogram bounds of.  It's nil until the statistics are loaded.
+	tables map[string]json.RawMessage
+}
+
+// newStatisticsStore returns a statisticsStore keeping the statistics in the file at |path| of |fs|, or in memory if
+// |fs| is nil.
+func newStatisticsStore(fs filesys.ReadWriteFS, path string) *statisticsStore {
+	return &statisticsStore{mu: &sync.Mutex{}, fs: fs, path: path}
+}
+
+// load reads the statistics from the store's file the first time they're needed.  The caller must hold the lock.
+func (ss *statisticsStore) load() error {
+	if ss.tables != nil {
+		return nil
+	}
+
+	tables := make(map[string]json.RawMessage)
+	if ss.fs != nil {
+		if exists, _ := ss.fs.Exists(ss.path); exists {
+			data, err := ss.fs.ReadFile(ss.path)
+			if err != nil {
+				return err
+			}
+
+			err = json.Unmarshal(data, &tables)
+			if err != nil {
+				return fmt.Errorf("invalid statistics in %s: %v", ss.path, err)
+			}
+		}
+	}
+
+	ss.tables = tables
+	return nil
+}
+
+// put replaces the statistics of the table named.
+func (ss *statisticsStore) put(tableName string, stats []*columnStatistics) error {
+	data, err := json.Marshal(stats)
+	if err != nil {
+		return err
+	}
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	err = ss.load()
+	if err != nil {
+		return err
+	}
+
+	ss.tables[strings.ToLower(tableName)] = data
+	if ss.fs == nil {
+		return nil
+	}
+
+	all, err := json.Marshal(ss.tables)
+	if err != nil {
+		return err
+	}
+
+	return ss.fs.WriteFile(ss.path, all)
+}
+
+// read returns the statistics of the tables accepted by |include|, keyed by the lowercase names of the tables.
+func (ss *statisticsStore) read(include func(tableName string) bool) (map[string][]*columnStatistics, error) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	err := ss.load()
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make(map[string][]*columnStatistics)
+	for lwr, data := range ss.tables {
+		if !include(lwr) {
+			continue
+		}
+
+		var colStats []*columnStatistics
+		err = json.Unmarshal(data, &colStats)
+		if err != nil {
+			return nil, fmt.Errorf("invalid statistics for %s: %v", lwr, err)
+		}
+		stats[lwr] = colStats
+	}
+
+	return stats, nil
+}
+
+// loadTableStatistics returns the statistics of the table named from |store|, with the histograms parsed for the
+// columns of |tbl|'s schema |sch|, or nil if the table hasn't been analyzed.
+func loadTableStatistics(ctx context.Context, store *statisticsStore, tbl *doltdb.Table, tableName string, sch schema.Schema) (*tableStatistics, error) {
+	stats, err := store.read(func(name string) bool {
+		return name == strings.ToLower(tableName)
+	})
+	if err != nil || len(stats) == 0 {
+		return nil, err
+	}
+
+	ts := &tableStatistics{nbf: tbl.Format(), columns: make(map[string]*columnStatistics)}
+	for _, colStats := range stats[strings.ToLower(tableName)] {
+		col, ok := sch.GetAllCols().GetByNameCaseInsensitive(colStats.column)
+		if !ok {
+			continue
+		}
+
+		if colStats.histogram != nil {
+			err = parseHistogramBounds(ctx, tbl.ValueReadWriter(), col.TypeInfo, colStats.histogram)
+			if err != nil {
+				// the column's type has changed since it was analyzed
+				colStats.histogram = nil
+			}
+		}
+
+		ts.columns[strings.ToLower(col.Name)] = colStats
+	}
+
+	return ts, nil
+}
+
+func parseHistogramBounds(ctx context.Context, vrw types.ValueReadWriter, ti typeinfo.TypeInfo, h *histogram) error {
+	for _, b := range h.Buckets {
+		lower, upper := b.Lower, b.Upper
+		var err error
+		b.lower, err = ti.ParseValue(ctx, vrw, &lower)
+		if err != nil {
+			return err
+		}
+		b.upper, err = ti.ParseValue(ctx, vrw, &upper)
+		if err != nil {
+			return err
+		}
+		if types.IsNull(b.lower) || types.IsNull(b.upper) {
+			return fmt.Errorf("invalid histogram bucket [%s, %s]", lower, upper)
+		}
+	}
+
+	return nil
+}
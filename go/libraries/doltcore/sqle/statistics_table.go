@@ -0,0 +1,100 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+)
+
+var _ sql.Table = (*StatisticsTable)(nil)
+
+// StatisticsTable is a sql.Table implementation that implements a system table which shows the statistics collected by
+// ANALYZE TABLE for the tables of a database's working set.  The statistics aren't versioned, so the table is read
+// only.
+type StatisticsTable struct {
+	db Database
+}
+
+// NewStatisticsTable creates a StatisticsTable
+func NewStatisticsTable(_ *sql.Context, db Database) *StatisticsTable {
+	return &StatisticsTable{db}
+}
+
+// Name is a sql.Table interface function which returns the name of the table which is defined by the constant
+// StatisticsTableName
+func (st *StatisticsTable) Name() string {
+	return doltdb.StatisticsTableName
+}
+
+// String is a sql.Table interface function which returns the name of the table which is defined by the constant
+// StatisticsTableName
+func (st *StatisticsTable) String() string {
+	return doltdb.StatisticsTableName
+}
+
+// Schema is a sql.Table interface function that gets the sql.Schema of the statistics system table
+func (st *StatisticsTable) Schema() sql.Schema {
+	return []*sql.Column{
+		{Name: doltdb.StatisticsTableNameCol, Type: sql.Text, Source: doltdb.StatisticsTableName, PrimaryKey: true, Nullable: false},
+		{Name: doltdb.StatisticsColumnNameCol, Type: sql.Text, Source: doltdb.StatisticsTableName, PrimaryKey: true, Nullable: false},
+		{Name: doltdb.StatisticsRowCountCol, Type: sql.Uint64, Source: doltdb.StatisticsTableName, PrimaryKey: false, Nullable: false},
+		{Name: doltdb.StatisticsNullCountCol, Type: sql.Uint64, Source: doltdb.StatisticsTableName, PrimaryKey: false, Nullable: false},
+		{Name: doltdb.StatisticsDistinctCountCol, Type: sql.Uint64, Source: doltdb.StatisticsTableName, PrimaryKey: false, Nullable: false},
+		{Name: doltdb.StatisticsHistogramCol, Type: sql.LongText, Source: doltdb.StatisticsTableName, PrimaryKey: false, Nullable: true},
+	}
+}
+
+// Partitions is a sql.Table interface function that returns a partition of the data.  Currently the data is unpartitioned.
+func (st *StatisticsTable) Partitions(*sql.Context) (sql.PartitionIter, error) {
+	return newSinglePartitionIter(), nil
+}
+
+// PartitionRows is a sql.Table interface function that gets a row iterator for a partition
+func (st *StatisticsTable) PartitionRows(ctx *sql.Context, _ sql.Partition) (sql.RowIter, error) {
+	stats, err := readDatabaseStatistics(ctx, st.db)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []sql.Row
+	for tableName, colStats := range stats {
+		for _, cs := range colStats {
+			var hist interface{}
+			if cs.histogram != nil {
+				bs, err := json.Marshal(cs.histogram)
+				if err != nil {
+					return nil, err
+				}
+				hist = string(bs)
+			}
+
+			rows = append(rows, sql.NewRow(tableName, cs.column, cs.rowCount, cs.nullCount, cs.distinctCount, hist))
+		}
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i][0] != rows[j][0] {
+			return rows[i][0].(string) < rows[j][0].(string)
+		}
+		return rows[i][1].(string) < rows[j][1].(string)
+	})
+
+	return sql.RowsToRowIter(rows...), nil
+}
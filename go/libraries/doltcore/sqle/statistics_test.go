@@ -0,0 +1,104 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestNewHistogram(t *testing.T) {
+	nbf := types.Format_Default
+
+	// 1000 rows, 100 of them NULL, and every value from 0 to 299 three times
+	var vals []types.Value
+	for i := 0; i < 900; i++ {
+		vals = append(vals, types.Int(i%300))
+	}
+
+	h, err := newHistogram(context.Background(), nbf, typeinfo.Int64Type, vals, 1000, 1000)
+	require.NoError(t, err)
+	assert.Len(t, h.Buckets, 60)
+	assert.Equal(t, 0.1, h.NullValues)
+	assert.Equal(t, 1.0, h.SamplingRate)
+	assert.Equal(t, "0", h.Buckets[0].Lower)
+	assert.Equal(t, "4", h.Buckets[0].Upper)
+	assert.Equal(t, uint64(5), h.Buckets[0].Distinct)
+	assert.InDelta(t, 0.9, h.Buckets[len(h.Buckets)-1].CumulativeFrequency, 1e-9)
+
+	bs, err := json.Marshal(h)
+	require.NoError(t, err)
+
+	parsed := &histogram{}
+	require.NoError(t, json.Unmarshal(bs, parsed))
+	assert.Equal(t, h, parsed)
+
+	require.NoError(t, parseHistogramBounds(context.Background(), nil, typeinfo.Int64Type, parsed))
+	assert.Equal(t, types.Int(0), parsed.Buckets[0].lower)
+	assert.Equal(t, types.Int(4), parsed.Buckets[0].upper)
+
+	ts := &tableStatistics{nbf: nbf, columns: map[string]*columnStatistics{
+		"v": {column: "v", rowCount: 1000, nullCount: 100, distinctCount: 300, histogram: parsed},
+	}}
+	col := schema.NewColumn("v", 1, types.IntKind, false)
+
+	assert.InDelta(t, 0.003, ts.equalitySelectivity(col, types.Int(42)), 1e-9)
+	assert.Equal(t, 0.0, ts.equalitySelectivity(col, types.Int(-1)))
+	assert.Equal(t, 0.0, ts.equalitySelectivity(col, types.NullValue))
+	assert.InDelta(t, 0.45, ts.rangeSelectivity(col, nil, false, types.Int(150), false), 0.01)
+	assert.InDelta(t, 0.9, ts.rangeSelectivity(col, nil, false, nil, false), 1e-9)
+	assert.InDelta(t, 0.3, ts.rangeSelectivity(col, types.Int(100), true, types.Int(200), false), 0.01)
+	assert.Equal(t, 0.0, ts.rangeSelectivity(col, types.Int(400), true, nil, false))
+
+	other := schema.NewColumn("w", 2, types.IntKind, false)
+	assert.Equal(t, defaultEqualitySelectivity, ts.equalitySelectivity(other, types.Int(1)))
+	assert.Equal(t, defaultRangeSelectivity, ts.rangeSelectivity(other, types.Int(1), true, nil, false))
+}
+
+func TestEstimateDistinct(t *testing.T) {
+	nbf := types.Format_Default
+
+	var all []types.Value
+	for i := 0; i < 1000; i++ {
+		all = append(all, types.Int(i%10))
+	}
+
+	distinct, err := estimateDistinct(nbf, all, 1000)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(10), distinct)
+
+	// values seen more than once in a sample are counted once
+	distinct, err = estimateDistinct(nbf, all[:100], 1000)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(10), distinct)
+
+	// values seen once in a sample are scaled up, to at most the number of values
+	var unique []types.Value
+	for i := 0; i < 100; i++ {
+		unique = append(unique, types.Int(i))
+	}
+
+	distinct, err = estimateDistinct(nbf, unique, 400)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(200), distinct)
+}
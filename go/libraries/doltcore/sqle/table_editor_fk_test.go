@@ -374,7 +374,7 @@ func assertTableEditorRows(t *testing.T, fk_dEnv *env.DoltEnv, root *doltdb.Root
 		_ = rowData.IterAll(context.Background(), func(key, value types.Value) error {
 			r, err := row.FromNoms(sch, key.(types.Tuple), value.(types.Tuple))
 			assert.NoError(t, err)
-			sqlRow, err := doltRowToSqlRow(r, sch)
+			sqlRow, err := doltRowToSqlRow(context.Background(), r, sch)
 			assert.NoError(t, err)
 			sqlRows = append(sqlRows, sqlRow)
 			return nil
@@ -437,7 +437,7 @@ func assertTableEditorRows(t *testing.T, fk_dEnv *env.DoltEnv, root *doltdb.Root
 			_ = indexRowData.IterAll(context.Background(), func(key, value types.Value) error {
 				r, err := row.FromNoms(indexSch, key.(types.Tuple), value.(types.Tuple))
 				assert.NoError(t, err)
-				sqlRow, err := doltRowToSqlRow(r, indexSch)
+				sqlRow, err := doltRowToSqlRow(context.Background(), r, indexSch)
 				assert.NoError(t, err)
 				sqlRows = append(sqlRows, sqlRow)
 				return nil
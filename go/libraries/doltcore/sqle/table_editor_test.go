@@ -188,7 +188,7 @@ func TestTableEditor(t *testing.T) {
 }
 
 func r(row row.Row, sch schema.Schema) sql.Row {
-	sqlRow, err := doltRowToSqlRow(row, sch)
+	sqlRow, err := doltRowToSqlRow(context.Background(), row, sch)
 	if err != nil {
 		panic(err)
 	}
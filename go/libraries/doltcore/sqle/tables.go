@@ -74,12 +74,16 @@ type DoltTable struct {
 var _ sql.Table = (*DoltTable)(nil)
 var _ sql.IndexedTable = (*DoltTable)(nil)
 var _ sql.ForeignKeyTable = (*DoltTable)(nil)
+var _ CheckTable = (*DoltTable)(nil)
 
-// WithIndexLookup implements sql.IndexedTable
+// WithIndexLookup implements sql.IndexedTable.  The table is scanned instead when that's estimated to be cheaper than
+// the lookup.
 func (t *DoltTable) WithIndexLookup(lookup sql.IndexLookup) sql.Table {
 	dil, ok := lookup.(*doltIndexLookup)
 	if !ok {
 		return newStaticErrorTable(t, fmt.Errorf("Unrecognized indexLookup %T", lookup))
+	} else if dil.preferFullScan() {
+		return t
 	}
 	return &IndexedDoltTable{
 		table:       t,
@@ -101,6 +105,13 @@ func (t *DoltTable) GetIndexes(ctx *sql.Context) ([]sql.Index, error) {
 		return nil, err
 	}
 
+	var stats *lazyStatistics
+	if db, ok := t.db.(Database); ok {
+		stats = &lazyStatistics{load: func() (*tableStatistics, error) {
+			return loadTableStatistics(ctx, db.stats, tbl, t.Name(), sch)
+		}}
+	}
+
 	cols := sch.GetPKCols().GetColumns()
 	sqlIndexes := []sql.Index{
 		&doltIndex{
@@ -114,6 +125,7 @@ func (t *DoltTable) GetIndexes(ctx *sql.Context) ([]sql.Index, error) {
 			tableName:    t.Name(),
 			tableSch:     sch,
 			unique:       true,
+			stats:        stats,
 		},
 	}
 
@@ -122,22 +134,27 @@ func (t *DoltTable) GetIndexes(ctx *sql.Context) ([]sql.Index, error) {
 		if err != nil {
 			return nil, err
 		}
-		cols := make([]schema.Column, index.Count())
-		for i, tag := range index.IndexedColumnTags() {
-			cols[i], _ = index.GetColumn(tag)
+		cols, exprs, err := indexLookupColumns(t.Name(), sch, index)
+		if err != nil {
+			return nil, err
 		}
 		sqlIndexes = append(sqlIndexes, &doltIndex{
-			cols:         cols,
-			db:           t.db,
-			id:           index.Name(),
-			indexRowData: indexRowData,
-			indexSch:     index.Schema(),
-			table:        tbl,
-			tableData:    rowData,
-			tableName:    t.Name(),
-			tableSch:     sch,
-			unique:       index.IsUnique(),
-			comment:      index.Comment(),
+			cols:          cols,
+			exprs:         exprs,
+			prefixLengths: index.PrefixLengths(),
+			db:            t.db,
+			id:            index.Name(),
+			indexRowData:  indexRowData,
+			indexSch:      index.Schema(),
+			table:         tbl,
+			tableData:     rowData,
+			tableName:     t.Name(),
+			tableSch:      sch,
+			unique:        index.IsUnique(),
+			fullText:      index.IsFullText(),
+			spatial:       index.IsSpatial(),
+			comment:       index.Comment(),
+			stats:         stats,
 		})
 	}
 
@@ -154,6 +171,11 @@ func (t *DoltTable) String() string {
 	return t.name
 }
 
+// doltDB returns the DoltDB the table is read from.
+func (t *DoltTable) doltDB(ctx *sql.Context) (*doltdb.DoltDB, bool) {
+	return DSessFromSess(ctx.Session).GetDoltDB(t.db.Name())
+}
+
 // Schema returns the schema for this table.
 func (t *DoltTable) Schema() sql.Schema {
 	return t.sqlSchema()
@@ -176,6 +198,8 @@ func (t *DoltTable) sqlSchema() sql.Schema {
 
 // Returns the partitions for this table.
 func (t *DoltTable) Partitions(ctx *sql.Context) (sql.PartitionIter, error) {
+	recordDoltRead(ctx, "full scan", "")
+
 	rowData, err := t.table.GetRowData(ctx)
 
 	if err != nil {
@@ -233,6 +257,8 @@ func (t *WritableDoltTable) WithIndexLookup(lookup sql.IndexLookup) sql.Table {
 	dil, ok := lookup.(*doltIndexLookup)
 	if !ok {
 		return newStaticErrorTable(t, fmt.Errorf("Unrecognized indexLookup %T", lookup))
+	} else if dil.preferFullScan() {
+		return t
 	}
 	return &WritableIndexedDoltTable{
 		WritableDoltTable: t,
@@ -297,6 +323,11 @@ func (t *WritableDoltTable) Updater(ctx *sql.Context) sql.RowUpdater {
 	return te
 }
 
+// GetChecks implements CheckTable
+func (t *DoltTable) GetChecks(ctx *sql.Context) ([]schema.Check, error) {
+	return t.sch.Checks().AllChecks(), nil
+}
+
 // GetForeignKeys implements sql.ForeignKeyTable
 func (t *DoltTable) GetForeignKeys(ctx *sql.Context) ([]sql.ForeignKeyConstraint, error) {
 	root, err := t.db.GetRoot(ctx)
@@ -432,6 +463,7 @@ var _ sql.AlterableTable = (*AlterableDoltTable)(nil)
 var _ sql.IndexAlterableTable = (*AlterableDoltTable)(nil)
 var _ sql.ForeignKeyAlterableTable = (*AlterableDoltTable)(nil)
 var _ sql.ForeignKeyTable = (*AlterableDoltTable)(nil)
+var _ CheckAlterableTable = (*AlterableDoltTable)(nil)
 
 // AddColumn implements sql.AlterableTable
 func (t *AlterableDoltTable) AddColumn(ctx *sql.Context, column *sql.Column, order *sql.ColumnOrder) error {
@@ -450,7 +482,7 @@ func (t *AlterableDoltTable) AddColumn(ctx *sql.Context, column *sql.Column, ord
 	if err != nil {
 		return err
 	}
-	tags, err := root.GenerateTagsForNewColumns(ctx, t.name, []string{column.Name}, []types.NomsKind{ti.NomsKind()})
+	tags, err := root.GenerateTagsForNewColumns(ctx, t.name, []string{column.Name}, []types.NomsKind{typeinfo.TagKind(ti)})
 	if err != nil {
 		return err
 	}
@@ -571,6 +603,10 @@ func (t *AlterableDoltTable) ModifyColumn(ctx *sql.Context, columnName string, c
 	if err != nil {
 		return err
 	}
+	// LONGTEXT columns which store their values inline, such as those in keys, continue to do so
+	if inlineTi := typeinfo.InlineType(col.TypeInfo); inlineTi.Equals(existingCol.TypeInfo) {
+		col.TypeInfo, col.Kind = inlineTi, inlineTi.NomsKind()
+	}
 
 	fkCollection, err := root.GetForeignKeyCollection(ctx)
 	if err != nil {
@@ -869,7 +905,7 @@ func (t *AlterableDoltTable) CreateForeignKey(
 	if err != nil {
 		return err
 	}
-	err = foreignKey.ConstraintIsSatisfied(ctx, tableIndexData, refTableIndexData, tableIndex, refTableIndex)
+	err = foreignKey.ConstraintIsSatisfied(ctx, t.table.ValueReadWriter(), tableIndexData, refTableIndexData, tableIndex, refTableIndex)
 	if err != nil {
 		return err
 	}
@@ -904,6 +940,77 @@ func (t *AlterableDoltTable) DropForeignKey(ctx *sql.Context, fkName string) err
 	return t.updateFromRoot(ctx, newRoot)
 }
 
+// CreateCheck implements CheckAlterableTable
+func (t *AlterableDoltTable) CreateCheck(ctx *sql.Context, check schema.Check) error {
+	sch, err := t.table.GetSchema(ctx)
+	if err != nil {
+		return err
+	}
+
+	colNames, err := check.ColumnNames()
+	if err != nil {
+		return err
+	}
+	for _, colName := range colNames {
+		if _, ok := sch.GetAllCols().GetByNameCaseInsensitive(colName); !ok {
+			return fmt.Errorf("check constraint '%s' refers to non-existing column '%s'", check.Name, colName)
+		}
+	}
+
+	_, err = sch.Checks().AddCheck(check.Name, check.Expression, check.Enforced)
+	if err != nil {
+		return err
+	}
+	newTable, err := t.table.UpdateSchema(ctx, sch)
+	if err != nil {
+		return err
+	}
+
+	// existing rows must satisfy the new check, and its expression must compile even if it isn't enforced
+	err = doltdb.ValidateCheckConstraints(ctx, newTable)
+	if err != nil {
+		return err
+	}
+
+	return t.updateTable(ctx, newTable)
+}
+
+// DropCheck implements CheckAlterableTable
+func (t *AlterableDoltTable) DropCheck(ctx *sql.Context, checkName string) error {
+	sch, err := t.table.GetSchema(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = sch.Checks().DropCheck(checkName)
+	if err != nil {
+		return err
+	}
+	newTable, err := t.table.UpdateSchema(ctx, sch)
+	if err != nil {
+		return err
+	}
+
+	return t.updateTable(ctx, newTable)
+}
+
+// updateTable puts the table given in the root of the database, and updates this table from it.
+func (t *AlterableDoltTable) updateTable(ctx *sql.Context, newTable *doltdb.Table) error {
+	root, err := t.db.GetRoot(ctx)
+	if err != nil {
+		return err
+	}
+	newRoot, err := root.PutTable(ctx, t.name, newTable)
+	if err != nil {
+		return err
+	}
+
+	err = t.db.SetRoot(ctx, newRoot)
+	if err != nil {
+		return err
+	}
+	return t.updateFromRoot(ctx, newRoot)
+}
+
 func toForeignKeyConstraint(fk doltdb.ForeignKey, childSch, parentSch schema.Schema) (cst sql.ForeignKeyConstraint, err error) {
 	cst = sql.ForeignKeyConstraint{
 		Name:              fk.Name,
@@ -990,9 +1097,14 @@ func createIndexForTable(
 	isUserDefined bool,
 	comment string,
 ) (*createIndexReturn, error) {
-	if constraint != sql.IndexConstraint_None && constraint != sql.IndexConstraint_Unique {
+	if constraint != sql.IndexConstraint_None && constraint != sql.IndexConstraint_Unique && constraint != sql.IndexConstraint_Fulltext && constraint != sql.IndexConstraint_Spatial {
 		return nil, fmt.Errorf("not yet supported")
 	}
+	isFullText := constraint == sql.IndexConstraint_Fulltext
+	isSpatial := constraint == sql.IndexConstraint_Spatial
+	if isSpatial && len(columns) != 1 {
+		return nil, fmt.Errorf("a SPATIAL index must have exactly one column")
+	}
 
 	sch, err := table.GetSchema(ctx)
 	if err != nil {
@@ -1001,24 +1113,42 @@ func createIndexForTable(
 
 	// get the real column names as CREATE INDEX columns are case-insensitive
 	var realColNames []string
+	var prefixLengths []uint16
 	allTableCols := sch.GetAllCols()
-	for _, indexCol := range columns {
+	for i, indexCol := range columns {
 		tableCol, ok := allTableCols.GetByNameCaseInsensitive(indexCol.Name)
 		if !ok {
 			return nil, fmt.Errorf("column `%s` does not exist for the table", indexCol.Name)
 		}
+		if indexCol.Length > 0 {
+			if isFullText || isSpatial {
+				return nil, fmt.Errorf("the columns of FULLTEXT and SPATIAL indexes cannot have prefix lengths")
+			}
+			err = validatePrefixLength(tableCol, indexCol.Length)
+			if err != nil {
+				return nil, err
+			}
+			if prefixLengths == nil {
+				prefixLengths = make([]uint16, len(columns))
+			}
+			prefixLengths[i] = uint16(indexCol.Length)
+		} else if isFullText {
+			if !sql.IsTextOnly(tableCol.TypeInfo.ToSqlType()) {
+				return nil, fmt.Errorf("column `%s` cannot be part of a FULLTEXT index", tableCol.Name)
+			}
+		} else if isSpatial {
+			err = validateSpatialIndexColumn(tableCol)
+			if err != nil {
+				return nil, err
+			}
+		} else if tableCol.Kind == types.BlobKind {
+			return nil, sqleSchema.ErrBlobInKey.New(tableCol.Name)
+		}
 		realColNames = append(realColNames, tableCol.Name)
 	}
 
 	if indexName == "" {
-		indexName = strings.Join(realColNames, "")
-		_, ok := sch.Indexes().GetByNameCaseInsensitive(indexName)
-		var i int
-		for ok {
-			i++
-			indexName = fmt.Sprintf("%s_%d", strings.Join(realColNames, ""), i)
-			_, ok = sch.Indexes().GetByNameCaseInsensitive(indexName)
-		}
+		indexName = unusedIndexName(sch, realColNames)
 	}
 	if !doltdb.IsValidTableName(indexName) {
 		return nil, fmt.Errorf("invalid index name `%s` as they must match the regular expression %s", indexName, doltdb.TableNameRegexStr)
@@ -1026,7 +1156,11 @@ func createIndexForTable(
 
 	// if an index was already created for the column set but was not generated by the user then we replace it
 	replacingIndex := false
-	existingIndex, ok := sch.Indexes().GetIndexByColumnNames(realColNames...)
+	var existingIndex schema.Index
+	var ok bool
+	if !isFullText && !isSpatial && prefixLengths == nil { // FULLTEXT, SPATIAL and prefix indexes may exist alongside a regular index over the same columns
+		existingIndex, ok = sch.Indexes().GetIndexByColumnNames(realColNames...)
+	}
 	if ok && !existingIndex.IsUserDefined() {
 		replacingIndex = true
 		_, err = sch.Indexes().RemoveIndex(existingIndex.Name())
@@ -1042,6 +1176,9 @@ func createIndexForTable(
 		schema.IndexProperties{
 			IsUnique:      constraint == sql.IndexConstraint_Unique,
 			IsUserDefined: isUserDefined,
+			IsFullText:    isFullText,
+			IsSpatial:     isSpatial,
+			PrefixLengths: prefixLengths,
 			Comment:       comment,
 		},
 	)
@@ -1049,6 +1186,39 @@ func createIndexForTable(
 		return nil, err
 	}
 
+	var replacedIndex schema.Index
+	if replacingIndex {
+		replacedIndex = existingIndex
+	}
+	newTable, err := tableWithNewIndex(ctx, table, sch, index, replacedIndex)
+	if err != nil {
+		return nil, err
+	}
+	return &createIndexReturn{
+		newTable: newTable,
+		sch:      sch,
+		oldIndex: existingIndex,
+		newIndex: index,
+	}, nil
+}
+
+// unusedIndexName returns the name given to an unnamed index over the columns given: the names of the columns joined
+// together, followed by the lowest number that isn't already used if that name is taken.
+func unusedIndexName(sch schema.Schema, colNames []string) string {
+	indexName := strings.Join(colNames, "")
+	_, ok := sch.Indexes().GetByNameCaseInsensitive(indexName)
+	var i int
+	for ok {
+		i++
+		indexName = fmt.Sprintf("%s_%d", strings.Join(colNames, ""), i)
+		_, ok = sch.Indexes().GetByNameCaseInsensitive(indexName)
+	}
+	return indexName
+}
+
+// tableWithNewIndex returns the table given with the schema given, which has had |index| added to it. The data of the
+// index is built from the table's rows, unless it's replacing |replacedIndex|, whose existing data is reused.
+func tableWithNewIndex(ctx *sql.Context, table *doltdb.Table, sch schema.Schema, index, replacedIndex schema.Index) (*doltdb.Table, error) {
 	// update the table schema with the new index
 	newSchemaVal, err := encoding.MarshalSchemaAsNomsValue(ctx, table.ValueReadWriter(), sch)
 	if err != nil {
@@ -1066,9 +1236,13 @@ func createIndexForTable(
 	if err != nil {
 		return nil, err
 	}
+	newTable, err = doltdb.CopyAutoIncrementValue(table, newTable)
+	if err != nil {
+		return nil, err
+	}
 
-	if replacingIndex { // verify that the pre-existing index data is valid
-		newTable, err = newTable.RenameIndexRowData(ctx, existingIndex.Name(), index.Name())
+	if replacedIndex != nil { // verify that the pre-existing index data is valid
+		newTable, err = newTable.RenameIndexRowData(ctx, replacedIndex.Name(), index.Name())
 		if err != nil {
 			return nil, err
 		}
@@ -1086,12 +1260,40 @@ func createIndexForTable(
 			return nil, err
 		}
 	}
-	return &createIndexReturn{
-		newTable: newTable,
-		sch:      sch,
-		oldIndex: existingIndex,
-		newIndex: index,
-	}, nil
+	return newTable, nil
+}
+
+// validateSpatialIndexColumn returns an error if the column given can't be indexed by a SPATIAL index. As in MySQL, it
+// must be a NOT NULL geometry column.
+func validateSpatialIndexColumn(col schema.Column) error {
+	if col.TypeInfo.GetTypeIdentifier() != typeinfo.SpatialTypeIdentifier {
+		return fmt.Errorf("column `%s` cannot be part of a SPATIAL index, as it isn't a geometry column", col.Name)
+	}
+	if col.IsNullable() {
+		return fmt.Errorf("column `%s` of a SPATIAL index must be NOT NULL", col.Name)
+	}
+	return nil
+}
+
+// maxIndexPrefixLength is the longest prefix of a column that may be indexed, which is the limit MySQL has on the length
+// of index keys.
+const maxIndexPrefixLength = 3072
+
+// validatePrefixLength returns an error if the column given can't be indexed by a prefix of the given length. Only the
+// prefixes of string columns which aren't part of the primary key can be indexed, and they can't be longer than the
+// column's values.
+func validatePrefixLength(col schema.Column, length int64) error {
+	strType, ok := col.TypeInfo.ToSqlType().(sql.StringType)
+	if !ok || !sql.IsText(strType) || length > strType.MaxCharacterLength() {
+		return fmt.Errorf("incorrect prefix key for column `%s`: the column isn't a string or is shorter than the prefix", col.Name)
+	}
+	if col.IsPartOfPK {
+		return fmt.Errorf("column `%s` is part of the primary key, and cannot be indexed by a prefix", col.Name)
+	}
+	if length > maxIndexPrefixLength {
+		return fmt.Errorf("prefix of column `%s` is too long; the maximum prefix length is %d", col.Name, maxIndexPrefixLength)
+	}
+	return nil
 }
 
 // dropIndex drops the given index on the given table with the given schema. Returns the updated table and updated schema.
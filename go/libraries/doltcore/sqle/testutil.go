@@ -44,6 +44,71 @@ func ExecuteSql(dEnv *env.DoltEnv, root *doltdb.RootValue, statements string) (*
 			continue
 		}
 
+		spatialDDL, err := ParseSpatialDDL(query)
+		if err != nil {
+			return nil, err
+		}
+		var indexDDL *IndexDDL
+		if spatialDDL == nil {
+			indexDDL, err = ParseIndexDDL(query)
+			if err != nil {
+				return nil, err
+			}
+		}
+		var checkDDL *CheckDDL
+		if spatialDDL == nil && indexDDL == nil {
+			checkDDL, err = ParseCheckDDL(query)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if spatialDDL != nil || indexDDL != nil || checkDDL != nil {
+			// the checks, indexes and column types are applied to the tables with any batched edits
+			if err = db.Flush(ctx); err != nil {
+				return nil, err
+			}
+			execQuery := func(query string) error {
+				_, rowIter, err := engine.Query(ctx, query)
+				if err != nil {
+					return err
+				}
+				return drainIter(rowIter)
+			}
+			switch {
+			case spatialDDL != nil:
+				err = spatialDDL.Exec(ctx, engine.Catalog, func(query string) error {
+					return ExecIndexDDL(ctx, engine.Catalog, query, execQuery)
+				})
+			case indexDDL != nil:
+				err = indexDDL.Exec(ctx, engine.Catalog, func(query string) error {
+					return ExecCheckDDL(ctx, engine.Catalog, query, execQuery)
+				})
+			default:
+				err = checkDDL.Exec(ctx, engine.Catalog, execQuery)
+			}
+			if err != nil {
+				return nil, err
+			}
+			if err = db.Flush(ctx); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		analyze, err := ParseAnalyzeTable(query)
+		if err != nil {
+			return nil, err
+		} else if analyze != nil {
+			_, rowIter, err := analyze.Exec(ctx, engine)
+			if err != nil {
+				return nil, err
+			}
+			if err = drainIter(rowIter); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
 		sqlStatement, err := sqlparser.Parse(query)
 		if err != nil {
 			return nil, err
@@ -100,7 +165,7 @@ func NewTestSQLCtx(ctx context.Context) *sql.Context {
 
 // NewTestEngine creates a new default engine, and a *sql.Context and initializes indexes and schema fragments.
 func NewTestEngine(ctx context.Context, db Database, root *doltdb.RootValue) (*sqle.Engine, *sql.Context, error) {
-	engine := sqle.NewDefault()
+	engine := NewDefaultEngine()
 	engine.AddDatabase(db)
 
 	sqlCtx := NewTestSQLCtx(ctx)
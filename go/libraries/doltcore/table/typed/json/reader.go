@@ -16,6 +16,7 @@ package json
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -24,6 +25,7 @@ import (
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/row"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
 	"github.com/dolthub/dolt/go/libraries/utils/filesys"
 	"github.com/dolthub/dolt/go/store/types"
 )
@@ -31,6 +33,7 @@ import (
 var ReadBufSize = 256 * 1024
 
 type JSONReader struct {
+	vrw        types.ValueReadWriter
 	nbf        *types.NomsBinFormat
 	closer     io.Closer
 	sch        schema.Schema
@@ -39,17 +42,17 @@ type JSONReader struct {
 	sampleRow  row.Row
 }
 
-func OpenJSONReader(nbf *types.NomsBinFormat, path string, fs filesys.ReadableFS, sch schema.Schema) (*JSONReader, error) {
+func OpenJSONReader(vrw types.ValueReadWriter, path string, fs filesys.ReadableFS, sch schema.Schema) (*JSONReader, error) {
 	r, err := fs.OpenForRead(path)
 
 	if err != nil {
 		return nil, err
 	}
 
-	return newJsonReader(nbf, r, fs, sch, path)
+	return newJsonReader(vrw, r, fs, sch, path)
 }
 
-func newJsonReader(nbf *types.NomsBinFormat, r io.ReadCloser, fs filesys.ReadableFS, sch schema.Schema, tblPath string) (*JSONReader, error) {
+func newJsonReader(vrw types.ValueReadWriter, r io.ReadCloser, fs filesys.ReadableFS, sch schema.Schema, tblPath string) (*JSONReader, error) {
 	if sch == nil {
 		return nil, errors.New("schema must be provided to JsonReader")
 	}
@@ -61,7 +64,7 @@ func newJsonReader(nbf *types.NomsBinFormat, r io.ReadCloser, fs filesys.Readabl
 
 	decoder := jstream.NewDecoder(tblData, 2) // extract JSON values at a depth level of 1
 
-	return &JSONReader{nbf: nbf, closer: r, sch: sch, jsonStream: decoder}, nil
+	return &JSONReader{vrw: vrw, nbf: vrw.Format(), closer: r, sch: sch, jsonStream: decoder}, nil
 }
 
 // Close should release resources being held
@@ -113,10 +116,10 @@ func (r *JSONReader) ReadRow(ctx context.Context) (row.Row, error) {
 	if !ok {
 		return nil, fmt.Errorf("Unexpected json value: %v", row.Value)
 	}
-	return r.convToRow(m)
+	return r.convToRow(ctx, m)
 }
 
-func (r *JSONReader) convToRow(rowMap map[string]interface{}) (row.Row, error) {
+func (r *JSONReader) convToRow(ctx context.Context, rowMap map[string]interface{}) (row.Row, error) {
 	allCols := r.sch.GetAllCols()
 
 	taggedVals := make(row.TaggedValues, allCols.Size())
@@ -127,9 +130,22 @@ func (r *JSONReader) convToRow(rowMap map[string]interface{}) (row.Row, error) {
 			return nil, fmt.Errorf("column %s not found in schema", k)
 		}
 
+		if col.TypeInfo.GetTypeIdentifier() == typeinfo.JSONTypeIdentifier {
+			// the value is the decoded document, which is converted from its JSON text
+			text, err := json.Marshal(v)
+			if err != nil {
+				return nil, err
+			}
+			taggedVals[col.Tag], err = col.TypeInfo.ConvertValueToNomsValue(ctx, r.vrw, text)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
 		switch v.(type) {
 		case int, string, bool, float64:
-			taggedVals[col.Tag], _ = col.TypeInfo.ConvertValueToNomsValue(v)
+			taggedVals[col.Tag], _ = col.TypeInfo.ConvertValueToNomsValue(ctx, r.vrw, v)
 		}
 
 	}
@@ -22,11 +22,12 @@ import (
 	"io"
 	"os"
 	"path/filepath"
-
-	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"sort"
+	"unicode/utf8"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/row"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
 	"github.com/dolthub/dolt/go/libraries/utils/filesys"
 	"github.com/dolthub/dolt/go/libraries/utils/iohelp"
 	"github.com/dolthub/dolt/go/store/types"
@@ -77,29 +78,46 @@ func (jsonw *JSONWriter) GetSchema() schema.Schema {
 func (jsonw *JSONWriter) WriteRow(ctx context.Context, r row.Row) error {
 	allCols := jsonw.sch.GetAllCols()
 	colValMap := make(map[string]interface{}, allCols.Size())
+	blobs := make(map[string]types.Blob)
 	err := allCols.Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
 		val, ok := r.GetColVal(tag)
 		if !ok || types.IsNull(val) {
 			return false, nil
 		}
 
+		if b, ok := val.(types.Blob); ok {
+			// blobs are written as strings, which are escaped as they're read from the chunks they're stored in
+			blobs[col.Name] = b
+			return false, nil
+		}
+
 		switch col.TypeInfo.GetTypeIdentifier() {
 		case typeinfo.DatetimeTypeIdentifier,
 			typeinfo.DecimalTypeIdentifier,
 			typeinfo.EnumTypeIdentifier,
 			typeinfo.InlineBlobTypeIdentifier,
 			typeinfo.SetTypeIdentifier,
+			typeinfo.SpatialTypeIdentifier,
 			typeinfo.TimeTypeIdentifier,
 			typeinfo.TupleTypeIdentifier,
 			typeinfo.UuidTypeIdentifier,
 			typeinfo.VarBinaryTypeIdentifier,
 			typeinfo.YearTypeIdentifier:
-			v, err := col.TypeInfo.FormatValue(val)
+			v, err := col.TypeInfo.FormatValue(ctx, val)
 			if err != nil {
 				return true, err
 			}
 			val = types.String(*v)
 
+		case typeinfo.JSONTypeIdentifier:
+			// documents are written as JSON rather than as strings
+			v, err := col.TypeInfo.FormatValue(ctx, val)
+			if err != nil {
+				return true, err
+			}
+			colValMap[col.Name] = json.RawMessage(*v)
+			return false, nil
+
 		case typeinfo.BitTypeIdentifier,
 			typeinfo.BoolTypeIdentifier,
 			typeinfo.VarStringTypeIdentifier,
@@ -114,9 +132,8 @@ func (jsonw *JSONWriter) WriteRow(ctx context.Context, r row.Row) error {
 		return false, nil
 	})
 
-	data, err := marshalToJson(colValMap)
 	if err != nil {
-		return errors.New("marshaling did not work")
+		return err
 	}
 
 	if jsonw.rowsWritten != 0 {
@@ -127,9 +144,9 @@ func (jsonw *JSONWriter) WriteRow(ctx context.Context, r row.Row) error {
 		}
 	}
 
-	newErr := iohelp.WriteAll(jsonw.bWr, data)
-	if newErr != nil {
-		return newErr
+	err = jsonw.writeRowObject(ctx, colValMap, blobs)
+	if err != nil {
+		return err
 	}
 	jsonw.rowsWritten++
 
@@ -159,13 +176,126 @@ func (jsonw *JSONWriter) Close(ctx context.Context) error {
 
 }
 
-func marshalToJson(valMap interface{}) ([]byte, error) {
-	var jsonBytes []byte
-	var err error
+// writeRowObject writes the object for a row, whose keys are written in sorted order as json.Marshal writes those of a
+// map. The values of |blobs| are written as strings, which are escaped as they're read.
+func (jsonw *JSONWriter) writeRowObject(ctx context.Context, colValMap map[string]interface{}, blobs map[string]types.Blob) error {
+	names := make([]string, 0, len(colValMap)+len(blobs))
+	for name := range colValMap {
+		names = append(names, name)
+	}
+	for name := range blobs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
 
-	jsonBytes, err = json.Marshal(valMap)
+	err := jsonw.bWr.WriteByte('{')
 	if err != nil {
-		return nil, err
+		return err
 	}
-	return jsonBytes, nil
+
+	for i, name := range names {
+		if i > 0 {
+			err = jsonw.bWr.WriteByte(',')
+			if err != nil {
+				return err
+			}
+		}
+
+		key, err := json.Marshal(name)
+		if err != nil {
+			return err
+		}
+		err = iohelp.WriteAll(jsonw.bWr, key, []byte{':'})
+		if err != nil {
+			return err
+		}
+
+		if b, ok := blobs[name]; ok {
+			err = jsonw.writeBlobString(ctx, b)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		data, err := json.Marshal(colValMap[name])
+		if err != nil {
+			return errors.New("marshaling did not work")
+		}
+		err = iohelp.WriteAll(jsonw.bWr, data)
+		if err != nil {
+			return err
+		}
+	}
+
+	return jsonw.bWr.WriteByte('}')
+}
+
+// writeBlobString writes the contents of a blob as a string, escaping them as they're read.
+func (jsonw *JSONWriter) writeBlobString(ctx context.Context, b types.Blob) error {
+	err := jsonw.bWr.WriteByte('"')
+	if err != nil {
+		return err
+	}
+
+	sw := &stringEscapingWriter{wr: jsonw.bWr}
+	_, err = io.Copy(sw, b.Reader(ctx))
+	if err != nil {
+		return err
+	}
+	err = sw.flush()
+	if err != nil {
+		return err
+	}
+
+	return jsonw.bWr.WriteByte('"')
+}
+
+// stringEscapingWriter escapes the contents of a string as json.Marshal does as they're written to it, writing them to
+// the wrapped Writer without their quotes. A character split across writes is held until the rest of it is written.
+type stringEscapingWriter struct {
+	wr      io.Writer
+	partial []byte
+}
+
+func (sw *stringEscapingWriter) Write(p []byte) (int, error) {
+	data := append(sw.partial, p...)
+
+	n := len(data)
+	for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(data[i]) {
+			if !utf8.FullRune(data[i:]) {
+				n = i
+			}
+			break
+		}
+	}
+
+	err := sw.write(data[:n])
+	if err != nil {
+		return 0, err
+	}
+
+	sw.partial = append(sw.partial[:0], data[n:]...)
+	return len(p), nil
+}
+
+// flush writes any incomplete character held from the last write, which is escaped as an invalid one.
+func (sw *stringEscapingWriter) flush() error {
+	err := sw.write(sw.partial)
+	sw.partial = nil
+	return err
+}
+
+func (sw *stringEscapingWriter) write(data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	escaped, err := json.Marshal(string(data))
+	if err != nil {
+		return err
+	}
+
+	return iohelp.WriteAll(sw.wr, escaped[1:len(escaped)-1])
 }
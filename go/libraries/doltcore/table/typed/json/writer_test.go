@@ -0,0 +1,83 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestWriterBlobs(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	colColl, err := schema.NewColCollection(
+		schema.Column{
+			Name:       "id",
+			Tag:        0,
+			Kind:       types.IntKind,
+			IsPartOfPK: true,
+			TypeInfo:   typeinfo.Int64Type,
+		},
+		schema.Column{
+			Name:     "text",
+			Tag:      1,
+			Kind:     types.BlobKind,
+			TypeInfo: typeinfo.LongTextType,
+		},
+	)
+	require.NoError(t, err)
+	sch := schema.MustSchemaFromCols(colColl)
+
+	vals := []string{
+		"",
+		`quotes " and backslashes \ and <html> & such`,
+		"control\ncharacters\t\x01",
+		"invalid \xff utf8",
+		strings.Repeat("multibyte é 世界 characters ", 10000),
+	}
+
+	fs := filesys.EmptyInMemFS("/")
+	wr, err := OpenJSONWriter("file.json", fs, sch)
+	require.NoError(t, err)
+
+	var expected []string
+	for i, val := range vals {
+		blob, err := types.NewBlob(ctx, vrw, strings.NewReader(val))
+		require.NoError(t, err)
+		r, err := row.New(types.Format_Default, sch, row.TaggedValues{0: types.Int(i), 1: blob})
+		require.NoError(t, err)
+		require.NoError(t, wr.WriteRow(ctx, r))
+
+		data, err := json.Marshal(map[string]interface{}{"id": i, "text": val})
+		require.NoError(t, err)
+		expected = append(expected, string(data))
+	}
+	require.NoError(t, wr.Close(ctx))
+
+	data, err := fs.ReadFile("file.json")
+	require.NoError(t, err)
+	require.Equal(t, jsonHeader+strings.Join(expected, ",")+jsonFooter, string(data))
+}
@@ -16,6 +16,7 @@ package csv
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"errors"
 	"io"
@@ -27,6 +28,7 @@ import (
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/row"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
 	"github.com/dolthub/dolt/go/libraries/utils/filesys"
 	"github.com/dolthub/dolt/go/store/types"
 )
@@ -106,6 +108,7 @@ func (csvw *CSVWriter) WriteRow(ctx context.Context, r row.Row) error {
 	allCols := csvw.sch.GetAllCols()
 
 	colValStrs := make([]*string, 0, allCols.Size())
+	blobs := make(map[int]types.Blob)
 	_, err := r.IterSchema(csvw.sch, func(tag uint64, val types.Value) (stop bool, err error) {
 		val, ok := r.GetColVal(tag)
 		if !ok || types.IsNull(val) {
@@ -114,8 +117,20 @@ func (csvw *CSVWriter) WriteRow(ctx context.Context, r row.Row) error {
 		}
 
 		var v string
+		col, _ := allCols.GetByTag(tag)
 		if val.Kind() == types.StringKind {
 			v = string(val.(types.String))
+		} else if val.Kind() == types.BlobKind {
+			// blobs are written as their text, which is copied from the chunks they're stored in as it's written
+			blobs[len(colValStrs)] = val.(types.Blob)
+		} else if col.TypeInfo != nil && (col.TypeInfo.GetTypeIdentifier() == typeinfo.JSONTypeIdentifier ||
+			col.TypeInfo.GetTypeIdentifier() == typeinfo.SpatialTypeIdentifier) {
+			// documents and geometries are written as their text, rather than in their encoded form
+			str, err := col.TypeInfo.FormatValue(ctx, val)
+			if err != nil {
+				return false, err
+			}
+			v = *str
 		} else {
 			v, err = types.EncodedValue(ctx, val)
 			if err != nil {
@@ -131,7 +146,7 @@ func (csvw *CSVWriter) WriteRow(ctx context.Context, r row.Row) error {
 		return err
 	}
 
-	return csvw.write(colValStrs)
+	return csvw.writeWithBlobs(ctx, colValStrs, blobs)
 }
 
 // Close should flush all writes, release resources being held
@@ -146,9 +161,15 @@ func (csvw *CSVWriter) Close(ctx context.Context) error {
 	}
 }
 
-// write is directly copied from csv.Writer.Write() with the addition of the `isNull []bool` parameter
-// this method has been adapted for Dolt's special quoting logic, ie `10,,""` -> (10,NULL,"")
+// write writes a record none of whose fields are blobs.
 func (csvw *CSVWriter) write(record []*string) error {
+	return csvw.writeWithBlobs(context.Background(), record, nil)
+}
+
+// writeWithBlobs is directly copied from csv.Writer.Write() with the addition of the `isNull []bool` parameter
+// this method has been adapted for Dolt's special quoting logic, ie `10,,""` -> (10,NULL,"")
+// The fields with an index in |blobs| are written from the contents of their blob, which are read as they're written.
+func (csvw *CSVWriter) writeWithBlobs(ctx context.Context, record []*string, blobs map[int]types.Blob) error {
 	for n, field := range record {
 		if n > 0 {
 			if _, err := csvw.wr.WriteString(csvw.info.Delim); err != nil {
@@ -156,6 +177,13 @@ func (csvw *CSVWriter) write(record []*string) error {
 			}
 		}
 
+		if b, ok := blobs[n]; ok {
+			if err := csvw.writeBlobField(ctx, b); err != nil {
+				return err
+			}
+			continue
+		}
+
 		if field == nil {
 			if _, err := csvw.wr.WriteString(""); err != nil {
 				return err
@@ -175,41 +203,8 @@ func (csvw *CSVWriter) write(record []*string) error {
 		if err := csvw.wr.WriteByte('"'); err != nil {
 			return err
 		}
-		for len(*field) > 0 {
-			// Search for special characters.
-			i := strings.IndexAny(*field, "\"\r\n")
-			if i < 0 {
-				i = len(*field)
-			}
-
-			// Copy verbatim everything before the special character.
-			if _, err := csvw.wr.WriteString((*field)[:i]); err != nil {
-				return err
-			}
-			*field = (*field)[i:]
-
-			// Encode the special character.
-			if len(*field) > 0 {
-				var err error
-				switch (*field)[0] {
-				case '"':
-					_, err = csvw.wr.WriteString(`""`)
-				case '\r':
-					if !csvw.useCRLF {
-						err = csvw.wr.WriteByte('\r')
-					}
-				case '\n':
-					if csvw.useCRLF {
-						_, err = csvw.wr.WriteString("\r\n")
-					} else {
-						err = csvw.wr.WriteByte('\n')
-					}
-				}
-				*field = (*field)[1:]
-				if err != nil {
-					return err
-				}
-			}
+		if err := csvw.writeEscaped(*field); err != nil {
+			return err
 		}
 		if err := csvw.wr.WriteByte('"'); err != nil {
 			return err
@@ -224,6 +219,121 @@ func (csvw *CSVWriter) write(record []*string) error {
 	return err
 }
 
+// writeEscaped writes the contents of a quoted field, escaping its quotes and line endings.
+func (csvw *CSVWriter) writeEscaped(field string) error {
+	for len(field) > 0 {
+		// Search for special characters.
+		i := strings.IndexAny(field, "\"\r\n")
+		if i < 0 {
+			i = len(field)
+		}
+
+		// Copy verbatim everything before the special character.
+		if _, err := csvw.wr.WriteString(field[:i]); err != nil {
+			return err
+		}
+		field = field[i:]
+
+		// Encode the special character.
+		if len(field) > 0 {
+			var err error
+			switch field[0] {
+			case '"':
+				_, err = csvw.wr.WriteString(`""`)
+			case '\r':
+				if !csvw.useCRLF {
+					err = csvw.wr.WriteByte('\r')
+				}
+			case '\n':
+				if csvw.useCRLF {
+					_, err = csvw.wr.WriteString("\r\n")
+				} else {
+					err = csvw.wr.WriteByte('\n')
+				}
+			}
+			field = field[1:]
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writeBlobField writes the contents of a blob as a field. The blob is read once to find whether it must be quoted,
+// and again as it's written, so that its contents are never held in memory.
+func (csvw *CSVWriter) writeBlobField(ctx context.Context, b types.Blob) error {
+	needsQuotes, err := csvw.blobNeedsQuotes(ctx, b)
+	if err != nil {
+		return err
+	}
+
+	if !needsQuotes {
+		_, err = io.Copy(csvw.wr, b.Reader(ctx))
+		return err
+	}
+
+	if err := csvw.wr.WriteByte('"'); err != nil {
+		return err
+	}
+	if _, err := io.Copy(escapingWriter{csvw}, b.Reader(ctx)); err != nil {
+		return err
+	}
+	return csvw.wr.WriteByte('"')
+}
+
+// blobNeedsQuotes reports whether the contents of a blob must be quoted, as fieldNeedsQuotes does for strings.
+func (csvw *CSVWriter) blobNeedsQuotes(ctx context.Context, b types.Blob) (bool, error) {
+	rd := bufio.NewReader(b.Reader(ctx))
+	start, err := rd.Peek(utf8.UTFMax)
+	if err != nil && err != io.EOF {
+		return false, err
+	}
+	if len(start) == 0 {
+		// special Dolt logic
+		return true, nil
+	} else if string(start) == `\.` {
+		return true, nil
+	} else if r1, _ := utf8.DecodeRune(start); unicode.IsSpace(r1) {
+		return true, nil
+	}
+
+	// the delimiter may be split across reads, so the end of each read is kept to be searched with the next
+	delim := []byte(csvw.info.Delim)
+	buf := make([]byte, 32*1024)
+	var tail []byte
+	for {
+		n, err := rd.Read(buf)
+		if n > 0 {
+			data := append(tail, buf[:n]...)
+			if bytes.ContainsAny(data, "\"\r\n") || bytes.Contains(data, delim) {
+				return true, nil
+			}
+			if keep := len(delim) - 1; len(data) > keep {
+				data = data[len(data)-keep:]
+			}
+			tail = append(tail[:0], data...)
+		}
+		if err == io.EOF {
+			return false, nil
+		} else if err != nil {
+			return false, err
+		}
+	}
+}
+
+// escapingWriter escapes the contents of a quoted field as they're written to its CSVWriter.
+type escapingWriter struct {
+	csvw *CSVWriter
+}
+
+func (w escapingWriter) Write(p []byte) (int, error) {
+	if err := w.csvw.writeEscaped(string(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
 // Below is the method comment from csv.Writer.fieldNeedsQuotes. It is relevant
 // to Dolt's quoting logic for NULLs and ""s, and for import/export compatibility
 //
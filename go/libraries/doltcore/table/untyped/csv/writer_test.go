@@ -16,10 +16,14 @@ package csv
 
 import (
 	"context"
+	"strings"
 	"testing"
 
+	"github.com/stretchr/testify/require"
+
 	"github.com/dolthub/dolt/go/libraries/doltcore/row"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/untyped"
 	"github.com/dolthub/dolt/go/libraries/utils/filesys"
 	"github.com/dolthub/dolt/go/store/types"
@@ -102,3 +106,57 @@ Andy Anderson,27,
 		t.Errorf(`%s != %s`, results, expected)
 	}
 }
+
+func TestWriterBlobs(t *testing.T) {
+	const root = "/"
+	const path = "/file.csv"
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	long := strings.Repeat("x", 100000)
+	tests := []struct {
+		val      string
+		expected string
+	}{
+		{"plain", "plain"},
+		{"", `""`},
+		{"a,b", `"a,b"`},
+		{`say "hi"`, `"say ""hi"""`},
+		{" leading space", `" leading space"`},
+		{"line\nbreak", "\"line\nbreak\""},
+		{`\.`, `"\."`},
+		{long, long},
+		{long + ",", `"` + long + `,"`},
+	}
+
+	pkCol, err := schema.NewColumnWithTypeInfo(nameColName, nameColTag, typeinfo.StringDefaultType, true, "", false, "")
+	require.NoError(t, err)
+	textCol, err := schema.NewColumnWithTypeInfo(titleColName, titleColTag, typeinfo.LongTextType, false, "", false, "")
+	require.NoError(t, err)
+	colColl, err := schema.NewColCollection(pkCol, textCol)
+	require.NoError(t, err)
+	sch := schema.MustSchemaFromCols(colColl)
+
+	var expected strings.Builder
+	expected.WriteString(nameColName + "," + titleColName + "\n")
+	fs := filesys.NewInMemFS(nil, nil, root)
+	csvWr, err := OpenCSVWriter(path, fs, sch, NewCSVInfo())
+	require.NoError(t, err)
+	for i, test := range tests {
+		blob, err := types.NewBlob(ctx, vrw, strings.NewReader(test.val))
+		require.NoError(t, err)
+		name := string(rune('a' + i))
+		r, err := row.New(types.Format_Default, sch, row.TaggedValues{
+			nameColTag:  types.String(name),
+			titleColTag: blob,
+		})
+		require.NoError(t, err)
+		require.NoError(t, csvWr.WriteRow(ctx, r))
+		expected.WriteString(name + "," + test.expected + "\n")
+	}
+	require.NoError(t, csvWr.Close(ctx))
+
+	results, err := fs.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, expected.String(), string(results))
+}
@@ -93,13 +93,13 @@ func (w *SqlExportWriter) WriteRow(ctx context.Context, r row.Row) error {
 		return err
 	}
 
-	stmt, err := sqlfmt.RowAsInsertStmt(r, w.tableName, w.sch)
+	err := sqlfmt.WriteRowAsInsertStmt(ctx, w.wr, r, w.tableName, w.sch)
 
 	if err != nil {
 		return err
 	}
 
-	return iohelp.WriteLine(w.wr, stmt)
+	return iohelp.WriteLine(w.wr, "")
 }
 
 func (w *SqlExportWriter) maybeWriteDropCreate(ctx context.Context) error {
@@ -108,7 +108,7 @@ func (w *SqlExportWriter) maybeWriteDropCreate(ctx context.Context) error {
 		b.WriteString(sqlfmt.DropTableIfExistsStmt(w.tableName))
 		b.WriteRune('\n')
 		sqlCtx, engine, _ := dsqle.PrepareCreateTableStmt(ctx, dsqle.NewUserSpaceDatabase(w.root))
-		createTableStmt, err := dsqle.GetCreateTableStmt(sqlCtx, engine, w.tableName)
+		createTableStmt, err := dsqle.GetExportTableStmts(sqlCtx, engine, w.tableName, w.sch)
 		if err != nil {
 			return err
 		}
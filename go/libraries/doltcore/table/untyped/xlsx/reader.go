@@ -38,7 +38,7 @@ type XLSXReader struct {
 	rows   []row.Row
 }
 
-func OpenXLSXReader(nbf *types.NomsBinFormat, path string, fs filesys.ReadableFS, info *XLSXFileInfo) (*XLSXReader, error) {
+func OpenXLSXReader(ctx context.Context, vrw types.ValueReadWriter, path string, fs filesys.ReadableFS, info *XLSXFileInfo) (*XLSXReader, error) {
 	r, err := fs.OpenForRead(path)
 
 	if err != nil {
@@ -56,7 +56,7 @@ func OpenXLSXReader(nbf *types.NomsBinFormat, path string, fs filesys.ReadableFS
 
 	_, sch := untyped.NewUntypedSchema(colStrs...)
 
-	decodedRows, err := decodeXLSXRows(nbf, data, sch)
+	decodedRows, err := decodeXLSXRows(ctx, vrw, data, sch)
 	if err != nil {
 		r.Close()
 		return nil, err
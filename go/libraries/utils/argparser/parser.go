@@ -148,18 +148,20 @@ func (ap *ArgParser) matchModalOptions(arg string) (matches []*Option, rest stri
 	for kontinue {
 		kontinue = false
 
-		// stop if we see a value option
+		// stop if we see a value option, unless a longer flag matches, as in --no-merges with -n
+		valOptLen := 0
 		for _, vo := range ap.sortedValueOptions() {
 			lv := len(vo)
 			isValOpt := len(rest) >= lv && rest[:lv] == vo
 			if isValOpt {
-				return matches, rest
+				valOptLen = lv
+				break
 			}
 		}
 
 		for i, on := range candidateFlagNames {
 			lo := len(on)
-			isMatch := len(rest) >= lo && rest[:lo] == on
+			isMatch := lo > valOptLen && len(rest) >= lo && rest[:lo] == on
 			if isMatch {
 				rest = rest[lo:]
 				m := ap.NameOrAbbrevToOpt[on]
@@ -78,6 +78,20 @@ func TestArgParser(t *testing.T) {
 			map[string]string{"param": "value"},
 			[]string{"arg1"},
 		},
+		{
+			NewArgParser().SupportsInt("number", "n", "", "").SupportsFlag("no-merges", "", ""),
+			[]string{"--no-merges", "-n", "2"},
+			nil,
+			map[string]string{"no-merges": "", "number": "2"},
+			[]string{},
+		},
+		{
+			NewArgParser().SupportsInt("number", "n", "", "").SupportsFlag("no-merges", "", ""),
+			[]string{"-n5"},
+			nil,
+			map[string]string{"number": "5"},
+			[]string{},
+		},
 	}
 
 	for _, test := range tests {
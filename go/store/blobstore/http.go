@@ -0,0 +1,172 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ErrReadOnly is returned when attempting to write to a read only Blobstore
+var ErrReadOnly = errors.New("blobstore is read only")
+
+// HTTPBlobstore is a read only Blobstore implementation which reads blobs from a plain HTTP server, such as a static
+// web server or a CDN. Blobs are read from the url formed by appending the key to the base url, and partial reads are
+// done using range requests. The version of a blob is its ETag, or its Last-Modified time if the server does not
+// provide ETags.
+type HTTPBlobstore struct {
+	baseURL string
+	client  *http.Client
+}
+
+var _ Blobstore = &HTTPBlobstore{}
+
+// NewHTTPBlobstore creates a new instance of an HTTPBlobstore which reads blobs from beneath |baseURL| using |client|.
+// If |client| is nil http.DefaultClient is used.
+func NewHTTPBlobstore(baseURL string, client *http.Client) *HTTPBlobstore {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &HTTPBlobstore{strings.TrimRight(baseURL, "/"), client}
+}
+
+func (bs *HTTPBlobstore) url(key string) string {
+	return bs.baseURL + "/" + key
+}
+
+func (bs *HTTPBlobstore) do(ctx context.Context, method, key string, br BlobRange) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, bs.url(key), nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !br.isAllRange() {
+		req.Header.Set("Range", br.httpRangeHeader())
+	}
+
+	resp, err := bs.client.Do(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusPartialContent:
+		return resp, nil
+	case http.StatusNotFound, http.StatusForbidden:
+		// static hosts frequently respond with a 403 for keys that don't exist
+		resp.Body.Close()
+		return nil, NotFound{bs.url(key)}
+	default:
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected response reading %s: %s", bs.url(key), resp.Status)
+	}
+}
+
+// Exists returns true if a blob exists for the given key, and false if it does not.
+func (bs *HTTPBlobstore) Exists(ctx context.Context, key string) (bool, error) {
+	resp, err := bs.do(ctx, http.MethodHead, key, AllRange)
+
+	if IsNotFoundError(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	resp.Body.Close()
+
+	return true, nil
+}
+
+// Get retrieves an io.reader for the portion of a blob specified by br along with its version
+func (bs *HTTPBlobstore) Get(ctx context.Context, key string, br BlobRange) (io.ReadCloser, string, error) {
+	resp, err := bs.do(ctx, http.MethodGet, key, br)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	ver := resp.Header.Get("ETag")
+	if ver == "" {
+		ver = resp.Header.Get("Last-Modified")
+	}
+
+	if br.isAllRange() || resp.StatusCode == http.StatusPartialContent {
+		if br.offset < 0 && br.length != 0 {
+			// a suffix range was requested, which may be longer than the requested length
+			return limitReadCloser(resp.Body, br.length), ver, nil
+		}
+
+		return resp.Body, ver, nil
+	}
+
+	// The server ignored the range request and responded with the entire blob.
+	if br.offset < 0 && resp.ContentLength < 0 {
+		resp.Body.Close()
+		return nil, "", fmt.Errorf("unable to read range of %s: server does not support range requests", bs.url(key))
+	}
+
+	posBr := br.positiveRange(resp.ContentLength)
+	_, err = io.CopyN(ioutil.Discard, resp.Body, posBr.offset)
+
+	if err != nil {
+		resp.Body.Close()
+		return nil, "", err
+	}
+
+	if br.length == 0 {
+		return resp.Body, ver, nil
+	}
+
+	return limitReadCloser(resp.Body, posBr.length), ver, nil
+}
+
+// Put is not supported by the HTTPBlobstore and always returns ErrReadOnly
+func (bs *HTTPBlobstore) Put(ctx context.Context, key string, reader io.Reader) (string, error) {
+	return "", ErrReadOnly
+}
+
+// CheckAndPut is not supported by the HTTPBlobstore and always returns ErrReadOnly
+func (bs *HTTPBlobstore) CheckAndPut(ctx context.Context, expectedVersion, key string, reader io.Reader) (string, error) {
+	return "", ErrReadOnly
+}
+
+type limitedReadCloser struct {
+	io.Reader
+	io.Closer
+}
+
+func limitReadCloser(rc io.ReadCloser, n int64) io.ReadCloser {
+	return limitedReadCloser{io.LimitReader(rc, n), rc}
+}
+
+// httpRangeHeader returns the value of the Range header used to request the bytes in |br|.
+func (br BlobRange) httpRangeHeader() string {
+	if br.offset < 0 {
+		return "bytes=" + strconv.FormatInt(br.offset, 10)
+	} else if br.length == 0 {
+		return "bytes=" + strconv.FormatInt(br.offset, 10) + "-"
+	}
+
+	return "bytes=" + strconv.FormatInt(br.offset, 10) + "-" + strconv.FormatInt(br.offset+br.length-1, 10)
+}
@@ -0,0 +1,88 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobstore
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPBlobstore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "http_blobstore")
+	require.NoError(t, err)
+
+	testData := randBytes(1024)
+	err = ioutil.WriteFile(filepath.Join(dir, key), testData, 0644)
+	require.NoError(t, err)
+
+	fileServer := http.FileServer(http.Dir(dir))
+	servers := map[string]http.Handler{
+		"ranges": fileServer,
+		"no ranges": http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r.Header.Del("Range")
+			fileServer.ServeHTTP(w, r)
+		}),
+	}
+
+	ranges := []struct {
+		br       BlobRange
+		expected []byte
+	}{
+		{AllRange, testData},
+		{NewBlobRange(0, 16), testData[:16]},
+		{NewBlobRange(100, 50), testData[100:150]},
+		{NewBlobRange(1000, 0), testData[1000:]},
+		{NewBlobRange(-24, 0), testData[1000:]},
+		{NewBlobRange(-24, 8), testData[1000:1008]},
+	}
+
+	for name, handler := range servers {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			server := httptest.NewServer(handler)
+			defer server.Close()
+
+			bs := NewHTTPBlobstore(server.URL+"/", nil)
+
+			exists, err := bs.Exists(ctx, key)
+			require.NoError(t, err)
+			assert.True(t, exists)
+
+			exists, err = bs.Exists(ctx, "missing")
+			require.NoError(t, err)
+			assert.False(t, exists)
+
+			_, _, err = GetBytes(ctx, bs, "missing", AllRange)
+			assert.True(t, IsNotFoundError(err))
+
+			for _, rng := range ranges {
+				data, ver, err := GetBytes(ctx, bs, key, rng.br)
+				require.NoError(t, err)
+				assert.Equal(t, rng.expected, data, "range %v", rng.br)
+				assert.NotEmpty(t, ver)
+			}
+
+			_, err = PutBytes(ctx, bs, key, testData)
+			assert.Equal(t, ErrReadOnly, err)
+		})
+	}
+}
@@ -183,3 +183,8 @@ func GetCSStatSummaryForDB(db Database) string {
 	cs := db.chunkStore()
 	return cs.StatsSummary()
 }
+
+// ChunkStoreFromDatabase returns the ChunkStore backing the given Database.
+func ChunkStoreFromDatabase(db Database) chunks.ChunkStore {
+	return db.chunkStore()
+}
@@ -61,6 +61,11 @@ type CmpChnkAndRefs struct {
 	refs    map[hash.Hash]int
 }
 
+// RefFilter is called by the Puller for every non-leaf chunk it downloads, along with the references found in that
+// chunk mapped to their heights. Any reference the filter removes from |refs| will not be walked, and the chunks it
+// points to will not be pulled.
+type RefFilter func(ctx context.Context, c chunks.Chunk, refs map[hash.Hash]int) error
+
 type NBSCompressedChunkStore interface {
 	chunks.ChunkStore
 	GetManyCompressed(context.Context, hash.HashSet, func(nbs.CompressedChunk)) error
@@ -75,11 +80,25 @@ type Puller struct {
 	sinkDB        Database
 	rootChunkHash hash.Hash
 	downloaded    hash.HashSet
+	refFilter     RefFilter
 
 	wr          *nbs.CmpChunkTableWriter
 	tempDir     string
 	chunksPerTF int
 
+	// checkpoint tracks the progress of the pull, and is written to checkpointDir so that an interrupted pull can be
+	// resumed. resumed is true if the checkpoint was loaded from a previous pull of the same root chunk.
+	checkpointMu   *sync.Mutex
+	checkpoint     *pullCheckpoint
+	checkpointDir  string
+	resumed        bool
+	pendingFlushes *sync.WaitGroup
+
+	// filledSinceCheckpoint is the number of table files filled since the last checkpoint was saved, and
+	// checkpointSaved is true once a checkpoint exists on disk.
+	filledSinceCheckpoint int
+	checkpointSaved       bool
+
 	eventCh chan PullerEvent
 }
 
@@ -92,6 +111,7 @@ const (
 	LevelDoneTWEvent
 	StartUploadTableFile
 	EndUpdateTableFile
+	ResumeTWEvent
 )
 
 type TreeWalkEventDetails struct {
@@ -166,78 +186,112 @@ func NewPuller(ctx context.Context, tempDir string, chunksPerTF int, srcDB, sink
 		return nil, err
 	}
 
+	// Checkpoints are only written when a temp dir is provided. An existing checkpoint for the same root chunk is
+	// resumed from if the context allows it, see WithPullResume, and discarded otherwise.
+	var cpDir string
+	cp := &pullCheckpoint{}
+	resumed := false
+	if tempDir != "" {
+		cpDir = checkpointDir(tempDir, rootChunkHash)
+
+		if !pullResumeAllowed(ctx) {
+			err = discardPullCheckpoint(cpDir)
+
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			loaded, ok, err := loadPullCheckpoint(cpDir)
+
+			if err != nil {
+				return nil, err
+			}
+
+			if ok {
+				cp = loaded
+				resumed = true
+			}
+		}
+	}
+
+	downloaded := hash.HashSet{}
+	if resumed {
+		downloaded = cp.downloaded
+	}
+
 	return &Puller{
-		fmt:           srcDB.Format(),
-		srcDB:         srcDB,
-		srcChunkStore: srcChunkStore,
-		sinkDB:        sinkDB,
-		rootChunkHash: rootChunkHash,
-		downloaded:    hash.HashSet{},
-		tempDir:       tempDir,
-		wr:            wr,
-		chunksPerTF:   chunksPerTF,
-		eventCh:       eventCh,
+		fmt:             srcDB.Format(),
+		srcDB:           srcDB,
+		srcChunkStore:   srcChunkStore,
+		sinkDB:          sinkDB,
+		rootChunkHash:   rootChunkHash,
+		downloaded:      downloaded,
+		tempDir:         tempDir,
+		wr:              wr,
+		chunksPerTF:     chunksPerTF,
+		eventCh:         eventCh,
+		checkpointMu:    &sync.Mutex{},
+		checkpoint:      cp,
+		checkpointDir:   cpDir,
+		resumed:         resumed,
+		pendingFlushes:  &sync.WaitGroup{},
+		checkpointSaved: resumed,
 	}, nil
 }
 
-func (p *Puller) processCompletedTables(ctx context.Context, ae *atomicerr.AtomicError, completedTables <-chan FilledWriters) {
-	type tempTblFile struct {
-		id          string
-		path        string
-		numChunks   int
-		contentLen  uint64
-		contentHash []byte
-	}
+// Resumed returns true if this Puller is resuming an interrupted pull of the same root chunk.
+func (p *Puller) Resumed() bool {
+	return p.resumed
+}
 
-	var tblFiles []tempTblFile
+// WithRefFilter sets a RefFilter which is used to prune the chunk graph walked by the Puller.  Pulling with a filter
+// leaves the sink without some of the chunks reachable from the root, so it should only be used for sinks which are
+// able to retrieve missing chunks from elsewhere.
+func (p *Puller) WithRefFilter(filter RefFilter) *Puller {
+	p.refFilter = filter
+	return p
+}
 
+func (p *Puller) processCompletedTables(ctx context.Context, ae *atomicerr.AtomicError, completedTables <-chan FilledWriters) {
 	var err error
 	for tblFile := range completedTables {
 		if err != nil {
+			p.pendingFlushes.Done()
 			continue // drain
 		}
 
-		var id string
-		id, err = tblFile.wr.Finish()
-
-		if ae.SetIfError(err) {
-			continue
-		}
-
-		path := filepath.Join(p.tempDir, id)
-		err = tblFile.wr.FlushToFile(path)
-
-		if ae.SetIfError(err) {
-			continue
-		}
-
-		tblFiles = append(tblFiles, tempTblFile{
-			id:          id,
-			path:        path,
-			numChunks:   tblFile.wr.Size(),
-			contentLen:  tblFile.wr.ContentLength(),
-			contentHash: tblFile.wr.GetMD5(),
-		})
+		err = p.flushToTempFile(tblFile.wr)
+		ae.SetIfError(err)
+		p.pendingFlushes.Done()
 	}
 
 	if ae.IsSet() {
 		return
 	}
 
-	details := &TableFileEventDetails{TableFileCount: len(tblFiles)}
+	p.checkpointMu.Lock()
+	tblFiles := p.checkpoint.TableFiles
+	uploaded := p.checkpoint.uploadedSet()
+	p.checkpointMu.Unlock()
+
+	details := &TableFileEventDetails{TableFileCount: len(tblFiles), TableFilesUploaded: len(uploaded)}
 
 	// Write tables in reverse order so that on a partial success, it will still be true that if a db has a chunk, it
 	// also has all of that chunks references.
 	for i := len(tblFiles) - 1; i >= 0; i-- {
 		tmpTblFile := tblFiles[i]
 
-		fi, err := os.Stat(tmpTblFile.path)
+		if _, ok := uploaded[tmpTblFile.ID]; ok {
+			continue
+		}
+
+		fi, err := os.Stat(tmpTblFile.Path)
 
 		if ae.SetIfError(err) {
 			return
 		}
 
-		f, err := os.Open(tmpTblFile.path)
+		f, err := os.Open(tmpTblFile.Path)
 
 		if ae.SetIfError(err) {
 			return
@@ -247,21 +301,110 @@ func (p *Puller) processCompletedTables(ctx context.Context, ae *atomicerr.Atomi
 		p.eventCh <- NewTFPullerEvent(StartUploadTableFile, details)
 
 		fWithSize := FileReaderWithSize{f, fi.Size()}
-		err = p.sinkDB.chunkStore().(nbs.TableFileStore).WriteTableFile(ctx, tmpTblFile.id, tmpTblFile.numChunks, fWithSize, tmpTblFile.contentLen, tmpTblFile.contentHash)
+		err = p.sinkDB.chunkStore().(nbs.TableFileStore).WriteTableFile(ctx, tmpTblFile.ID, tmpTblFile.NumChunks, fWithSize, tmpTblFile.ContentLen, tmpTblFile.ContentHash)
 
 		go func() {
-			_ = os.Remove(tmpTblFile.path)
+			_ = os.Remove(tmpTblFile.Path)
 		}()
 
 		if ae.SetIfError(err) {
 			return
 		}
 
+		err = p.checkpointUpload(tmpTblFile.ID)
+
+		if ae.SetIfError(err) {
+			return
+		}
+
 		details.TableFilesUploaded++
 		p.eventCh <- NewTFPullerEvent(EndUpdateTableFile, details)
 	}
 }
 
+// flushToTempFile finishes the table file being written by |wr| and writes it to the temp dir.
+func (p *Puller) flushToTempFile(wr *nbs.CmpChunkTableWriter) error {
+	id, err := wr.Finish()
+
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(p.tempDir, id)
+	err = wr.FlushToFile(path)
+
+	if err != nil {
+		return err
+	}
+
+	p.checkpointMu.Lock()
+	defer p.checkpointMu.Unlock()
+
+	p.checkpoint.TableFiles = append(p.checkpoint.TableFiles, tempTblFile{
+		ID:          id,
+		Path:        path,
+		NumChunks:   wr.Size(),
+		ContentLen:  wr.ContentLength(),
+		ContentHash: wr.GetMD5(),
+	})
+
+	return nil
+}
+
+// saveCheckpoint writes all of the chunks downloaded so far to temp table files, and then records them along with the
+// frontier of the tree walk in the checkpoint dir.  In order to avoid creating lots of small table files, nothing is
+// saved unless a table file has been filled since the last checkpoint.
+func (p *Puller) saveCheckpoint(ae *atomicerr.AtomicError, treeLevel int, leaves, absent hash.HashSet, completedTables chan FilledWriters) error {
+	if p.checkpointDir == "" || p.filledSinceCheckpoint == 0 {
+		return nil
+	}
+
+	p.filledSinceCheckpoint = 0
+
+	if p.wr.Size() > 0 {
+		p.pendingFlushes.Add(1)
+		completedTables <- FilledWriters{p.wr}
+
+		var err error
+		p.wr, err = nbs.NewCmpChunkTableWriter(p.tempDir)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	p.pendingFlushes.Wait()
+
+	if ae.IsSet() {
+		// a table file failed to flush, so not every downloaded chunk is in a temp table file.
+		return nil
+	}
+
+	p.checkpointMu.Lock()
+	defer p.checkpointMu.Unlock()
+
+	p.checkpoint.TreeLevel = treeLevel
+	p.checkpoint.absent = absent
+	p.checkpoint.leaves = leaves
+	p.checkpoint.downloaded = p.downloaded
+	p.checkpointSaved = true
+
+	return p.checkpoint.save(p.checkpointDir)
+}
+
+func (p *Puller) checkpointUpload(id string) error {
+	p.checkpointMu.Lock()
+	defer p.checkpointMu.Unlock()
+
+	p.checkpoint.Uploaded = append(p.checkpoint.Uploaded, id)
+
+	if !p.checkpointSaved {
+		return nil
+	}
+
+	return p.checkpoint.saveTableFiles(p.checkpointDir)
+}
+
 // Pull executes the sync operation
 func (p *Puller) Pull(ctx context.Context) error {
 	twDetails := &TreeWalkEventDetails{TreeLevel: -1}
@@ -270,6 +413,13 @@ func (p *Puller) Pull(ctx context.Context) error {
 	absent := make(hash.HashSet)
 	absent.Insert(p.rootChunkHash)
 
+	if p.resumed {
+		twDetails.TreeLevel = p.checkpoint.TreeLevel
+		leaves = p.checkpoint.leaves
+		absent = p.checkpoint.absent
+		p.eventCh <- NewTWPullerEvent(ResumeTWEvent, twDetails)
+	}
+
 	ae := atomicerr.New()
 	wg := &sync.WaitGroup{}
 	completedTables := make(chan FilledWriters, 8)
@@ -304,16 +454,32 @@ func (p *Puller) Pull(ctx context.Context) error {
 				break
 			}
 		}
+
+		err = p.saveCheckpoint(ae, twDetails.TreeLevel, leaves, absent, completedTables)
+
+		if ae.SetIfError(err) {
+			break
+		}
 	}
 
 	if p.wr.Size() > 0 {
+		p.pendingFlushes.Add(1)
 		completedTables <- FilledWriters{p.wr}
 	}
 
 	close(completedTables)
 
 	wg.Wait()
-	return ae.Get()
+
+	if err := ae.Get(); err != nil {
+		return err
+	}
+
+	if p.checkpointDir != "" {
+		return os.RemoveAll(p.checkpointDir)
+	}
+
+	return nil
 }
 
 func limitToNewChunks(absent hash.HashSet, downloaded hash.HashSet) {
@@ -372,6 +538,18 @@ func (p *Puller) getCmp(ctx context.Context, twDetails *TreeWalkEventDetails, le
 					return nil
 				})
 
+				if ae.SetIfError(err) {
+					return
+				}
+
+				if p.refFilter != nil {
+					err = p.refFilter(ctx, chnk, refs)
+
+					if ae.SetIfError(err) {
+						return
+					}
+				}
+
 				processed <- CmpChnkAndRefs{cmpChnk: cmpChnk, refs: refs}
 			}
 		}
@@ -402,6 +580,8 @@ func (p *Puller) getCmp(ctx context.Context, twDetails *TreeWalkEventDetails, le
 		}
 
 		if p.wr.Size() >= p.chunksPerTF {
+			p.pendingFlushes.Add(1)
+			p.filledSinceCheckpoint++
 			completedTables <- FilledWriters{p.wr}
 			p.wr, err = nbs.NewCmpChunkTableWriter(p.tempDir)
 
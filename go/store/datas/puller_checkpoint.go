@@ -0,0 +1,284 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datas
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+const (
+	checkpointDirPrefix    = "pull-checkpoint-"
+	checkpointFile         = "checkpoint.json"
+	checkpointAbsentFile   = "absent"
+	checkpointLeavesFile   = "leaves"
+	checkpointDownloadFile = "downloaded"
+)
+
+// tempTblFile is a table file which has been written to the temp dir of a Puller, but may not have been written to
+// the sink yet.
+type tempTblFile struct {
+	ID          string `json:"id"`
+	Path        string `json:"path"`
+	NumChunks   int    `json:"num_chunks"`
+	ContentLen  uint64 `json:"content_length"`
+	ContentHash []byte `json:"content_hash"`
+}
+
+// pullCheckpoint is the state of a Puller which is written to disk after each level of the tree walk, and after each
+// table file is written to the sink, so that an interrupted pull can be resumed without walking the chunk graph from
+// the root again. Every chunk in |downloaded| is stored in one of the |TableFiles|, and |absent| and |leaves| are the
+// frontier of the tree walk.
+type pullCheckpoint struct {
+	// Generation is incremented by each save.  The hash sets of each generation are written to their own files, which
+	// are only used once checkpointFile refers to them, so that a pull interrupted while saving a checkpoint leaves the
+	// previous checkpoint intact.
+	Generation int           `json:"generation"`
+	TreeLevel  int           `json:"tree_level"`
+	TableFiles []tempTblFile `json:"table_files"`
+	Uploaded   []string      `json:"uploaded"`
+
+	absent     hash.HashSet
+	leaves     hash.HashSet
+	downloaded hash.HashSet
+}
+
+type pullResumeKey struct{}
+
+// WithPullResume returns a context in which a Puller resumes an interrupted pull of the same root chunk from its last
+// checkpoint.  Without it, a Puller discards any such checkpoint and walks the chunk graph from the root.
+func WithPullResume(ctx context.Context) context.Context {
+	return context.WithValue(ctx, pullResumeKey{}, true)
+}
+
+func pullResumeAllowed(ctx context.Context) bool {
+	resume, _ := ctx.Value(pullResumeKey{}).(bool)
+	return resume
+}
+
+func checkpointDir(tempDir string, rootChunkHash hash.Hash) string {
+	return filepath.Join(tempDir, checkpointDirPrefix+rootChunkHash.String())
+}
+
+// discardPullCheckpoint deletes the checkpoint stored in |dir|, if there is one, along with the temp table files it
+// references.
+func discardPullCheckpoint(dir string) error {
+	data, err := ioutil.ReadFile(filepath.Join(dir, checkpointFile))
+
+	if os.IsNotExist(err) {
+		return os.RemoveAll(dir)
+	} else if err != nil {
+		return err
+	}
+
+	var cp pullCheckpoint
+	if json.Unmarshal(data, &cp) == nil {
+		for _, tblFile := range cp.TableFiles {
+			err = os.Remove(tblFile.Path)
+
+			if err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+
+	return os.RemoveAll(dir)
+}
+
+// loadPullCheckpoint loads the checkpoint stored in |dir|. If there is no checkpoint, or the checkpoint can't be
+// used because some of the table files it references no longer exist, false is returned.
+func loadPullCheckpoint(dir string) (*pullCheckpoint, bool, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, checkpointFile))
+
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+
+	var cp pullCheckpoint
+	err = json.Unmarshal(data, &cp)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	uploaded := cp.uploadedSet()
+	for _, tblFile := range cp.TableFiles {
+		if _, ok := uploaded[tblFile.ID]; ok {
+			continue
+		}
+
+		if _, err := os.Stat(tblFile.Path); err != nil {
+			return nil, false, os.RemoveAll(dir)
+		}
+	}
+
+	hashSets := map[string]*hash.HashSet{
+		checkpointAbsentFile:   &cp.absent,
+		checkpointLeavesFile:   &cp.leaves,
+		checkpointDownloadFile: &cp.downloaded,
+	}
+
+	for name, hs := range hashSets {
+		*hs, err = readHashSet(hashSetPath(dir, name, cp.Generation))
+
+		if os.IsNotExist(err) {
+			return nil, false, os.RemoveAll(dir)
+		} else if err != nil {
+			return nil, false, err
+		}
+	}
+
+	return &cp, true, nil
+}
+
+func (cp *pullCheckpoint) uploadedSet() map[string]struct{} {
+	uploaded := make(map[string]struct{}, len(cp.Uploaded))
+	for _, id := range cp.Uploaded {
+		uploaded[id] = struct{}{}
+	}
+
+	return uploaded
+}
+
+// save writes the entire checkpoint to |dir|.  The hash sets are written as a new generation, which replaces the
+// previous one when the checkpoint file is written.
+func (cp *pullCheckpoint) save(dir string) error {
+	err := os.MkdirAll(dir, os.ModePerm)
+
+	if err != nil {
+		return err
+	}
+
+	hashSets := map[string]hash.HashSet{
+		checkpointAbsentFile:   cp.absent,
+		checkpointLeavesFile:   cp.leaves,
+		checkpointDownloadFile: cp.downloaded,
+	}
+
+	prevGen := cp.Generation
+	for name, hs := range hashSets {
+		err = writeHashSet(hashSetPath(dir, name, prevGen+1), hs)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	cp.Generation = prevGen + 1
+	err = cp.saveTableFiles(dir)
+
+	if err != nil {
+		cp.Generation = prevGen
+		return err
+	}
+
+	for name := range hashSets {
+		err = os.Remove(hashSetPath(dir, name, prevGen))
+
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func hashSetPath(dir, name string, generation int) string {
+	return filepath.Join(dir, fmt.Sprintf("%s-%d", name, generation))
+}
+
+// saveTableFiles writes only the list of table files, and which of them have been uploaded, to |dir|.
+func (cp *pullCheckpoint) saveTableFiles(dir string) error {
+	data, err := json.Marshal(cp)
+
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomically(filepath.Join(dir, checkpointFile), data)
+}
+
+func writeHashSet(path string, hs hash.HashSet) error {
+	tmpPath := path + ".tmp"
+	f, err := os.Create(tmpPath)
+
+	if err != nil {
+		return err
+	}
+
+	wr := bufio.NewWriter(f)
+	err = binary.Write(wr, binary.BigEndian, uint32(len(hs)))
+
+	for h := range hs {
+		if err != nil {
+			break
+		}
+
+		err = serializeHash(wr, h)
+	}
+
+	if err == nil {
+		err = wr.Flush()
+	}
+
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, path)
+}
+
+func readHashSet(path string) (hash.HashSet, error) {
+	f, err := os.Open(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	hashes, err := deserializeHashes(bufio.NewReader(f))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return hashes.HashSet(), nil
+}
+
+func writeFileAtomically(path string, data []byte) error {
+	tmpPath := path + ".tmp"
+	err := ioutil.WriteFile(tmpPath, data, os.ModePerm)
+
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, path)
+}
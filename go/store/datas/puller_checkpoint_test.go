@@ -0,0 +1,70 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datas
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+func TestPullCheckpointInterruptedSave(t *testing.T) {
+	dir := filepath.Join(os.TempDir(), uuid.New().String())
+	defer os.RemoveAll(dir)
+
+	hashSet := func(strs ...string) hash.HashSet {
+		hs := hash.HashSet{}
+		for _, s := range strs {
+			hs.Insert(hash.Of([]byte(s)))
+		}
+		return hs
+	}
+
+	cp := &pullCheckpoint{TreeLevel: 1, absent: hashSet("a"), leaves: hashSet("l"), downloaded: hashSet("d")}
+	require.NoError(t, cp.save(dir))
+
+	// the hash sets of the next generation are written, but the pull is interrupted before the checkpoint file is
+	require.NoError(t, writeHashSet(hashSetPath(dir, checkpointAbsentFile, cp.Generation+1), hashSet("a2")))
+	require.NoError(t, writeHashSet(hashSetPath(dir, checkpointLeavesFile, cp.Generation+1), hashSet("l2")))
+
+	loaded, ok, err := loadPullCheckpoint(dir)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, 1, loaded.TreeLevel)
+	assert.Equal(t, hashSet("a"), loaded.absent)
+	assert.Equal(t, hashSet("l"), loaded.leaves)
+	assert.Equal(t, hashSet("d"), loaded.downloaded)
+
+	loaded.TreeLevel = 2
+	loaded.absent, loaded.leaves, loaded.downloaded = hashSet("a3"), hashSet("l3"), hashSet("d", "d3")
+	require.NoError(t, loaded.save(dir))
+
+	loaded, ok, err = loadPullCheckpoint(dir)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, 2, loaded.TreeLevel)
+	assert.Equal(t, hashSet("a3"), loaded.absent)
+	assert.Equal(t, hashSet("d", "d3"), loaded.downloaded)
+
+	// only the files of the latest generation are kept
+	_, err = os.Stat(hashSetPath(dir, checkpointDownloadFile, 1))
+	assert.True(t, os.IsNotExist(err))
+}
@@ -27,6 +27,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/hash"
 	"github.com/dolthub/dolt/go/store/nbs"
 	"github.com/dolthub/dolt/go/store/types"
 	"github.com/dolthub/dolt/go/store/util/clienttest"
@@ -352,6 +354,101 @@ func TestPuller(t *testing.T) {
 	}
 }
 
+func TestPullerResume(t *testing.T) {
+	ctx := context.Background()
+	db, err := tempDirDB(ctx)
+	require.NoError(t, err)
+	ds, err := db.GetDataset(ctx, "ds")
+	require.NoError(t, err)
+
+	// a long history makes for a deep tree walk which can be interrupted part way through
+	rootMap, err := types.NewMap(ctx, db)
+	require.NoError(t, err)
+	for i := 0; i < 64; i++ {
+		rootMap, err = addTableValues(ctx, db, rootMap, "ints", types.Int(i), types.Int(i))
+		require.NoError(t, err)
+		ds, err = db.CommitValue(ctx, ds, rootMap)
+		require.NoError(t, err)
+	}
+
+	rootRef, ok, err := ds.MaybeHeadRef()
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	sinkdb, err := tempDirDB(ctx)
+	require.NoError(t, err)
+	tmpDir := filepath.Join(os.TempDir(), uuid.New().String())
+	err = os.MkdirAll(tmpDir, os.ModePerm)
+	require.NoError(t, err)
+
+	pull := func(ctx context.Context, filter RefFilter) (*Puller, error) {
+		eventCh := make(chan PullerEvent, 128)
+		go func() {
+			for range eventCh {
+			}
+		}()
+		defer close(eventCh)
+
+		// use small table files so that checkpoints are saved before the pull is interrupted
+		plr, err := NewPuller(ctx, tmpDir, 4, db, sinkdb, rootRef.TargetHash(), eventCh)
+		require.NoError(t, err)
+
+		if filter != nil {
+			plr = plr.WithRefFilter(filter)
+		}
+
+		return plr, plr.Pull(ctx)
+	}
+
+	// interrupt the pull part way through the tree walk
+	errInterrupted := errors.New("interrupted")
+	walked := 0
+	interrupt := func(ctx context.Context, c chunks.Chunk, refs map[hash.Hash]int) error {
+		walked++
+		if walked > 32 {
+			return errInterrupted
+		}
+		return nil
+	}
+	plr, err := pull(WithPullResume(ctx), interrupt)
+	require.Equal(t, errInterrupted, err)
+	assert.False(t, plr.Resumed())
+
+	cpDir := checkpointDir(tmpDir, rootRef.TargetHash())
+	_, err = os.Stat(filepath.Join(cpDir, checkpointFile))
+	require.NoError(t, err)
+
+	// without resume allowed, the checkpoint is discarded and the pull starts over
+	walked = 0
+	plr, err = pull(ctx, interrupt)
+	require.Equal(t, errInterrupted, err)
+	assert.False(t, plr.Resumed())
+
+	walked = 0
+	plr, err = pull(WithPullResume(ctx), func(ctx context.Context, c chunks.Chunk, refs map[hash.Hash]int) error {
+		walked++
+		return nil
+	})
+	require.NoError(t, err)
+	assert.True(t, plr.Resumed())
+	assert.NotZero(t, walked)
+
+	_, err = os.Stat(cpDir)
+	assert.True(t, os.IsNotExist(err), "the checkpoint should be removed once the pull succeeds")
+
+	sinkDS, err := sinkdb.GetDataset(ctx, "ds")
+	require.NoError(t, err)
+	sinkDS, err = sinkdb.FastForward(ctx, sinkDS, rootRef)
+	require.NoError(t, err)
+	sinkRootRef, ok, err := sinkDS.MaybeHeadRef()
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	eq, err := pullerRefEquality(ctx, rootRef, sinkRootRef, db, sinkdb)
+	require.NoError(t, err)
+	assert.True(t, eq)
+}
+
 func makeABigTable(ctx context.Context, db Database) (types.Map, error) {
 	m, err := types.NewMap(ctx, db)
 
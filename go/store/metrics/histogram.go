@@ -130,6 +130,16 @@ func (h Histogram) Samples() uint64 {
 	return s
 }
 
+// Buckets returns the number of samples in each of the histogram's buckets. Bucket i holds the samples in the range
+// [2^i, 2^(i+1)).
+func (h Histogram) Buckets() []uint64 {
+	buckets := make([]uint64, bucketCount)
+	for i := 0; i < bucketCount; i++ {
+		buckets[i] = atomic.LoadUint64(&h.buckets[i])
+	}
+	return buckets
+}
+
 func (h Histogram) String() string {
 	f := h.ToString
 	if f == nil {
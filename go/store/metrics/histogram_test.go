@@ -66,6 +66,23 @@ func TestHistogramBasic(t *testing.T) {
 	assert.Equal(uint64(144), h.Mean())
 }
 
+func TestHistogramBuckets(t *testing.T) {
+	assert := assert.New(t)
+
+	h := Histogram{}
+	h.Sample(1)
+	h.Sample(3)
+	h.Sample(300)
+	h.Sample(511)
+
+	buckets := h.Buckets()
+	assert.Len(buckets, bucketCount)
+	assert.Equal(uint64(1), buckets[0])
+	assert.Equal(uint64(1), buckets[1])
+	assert.Equal(uint64(2), buckets[8])
+	assert.Equal(uint64(0), buckets[9])
+}
+
 func TestHistogramLarge(t *testing.T) {
 	assert := assert.New(t)
 	h := Histogram{}
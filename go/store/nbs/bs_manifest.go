@@ -18,6 +18,7 @@ import (
 	"bytes"
 	"context"
 	"io"
+	"io/ioutil"
 	"strings"
 
 	"github.com/dolthub/dolt/go/store/blobstore"
@@ -44,12 +45,24 @@ func manifestVersionAndContents(ctx context.Context, bs blobstore.Blobstore) (st
 	}
 
 	defer reader.Close()
-	contents, err := fileManifestV5{}.parseManifest(reader)
+	data, err := ioutil.ReadAll(reader)
 
 	if err != nil {
 		return "", manifestContents{}, err
 	}
 
+	contents, err := fileManifestV5{}.parseManifest(bytes.NewReader(data))
+
+	if err != nil {
+		// blobstores which serve the files of a file system store, such as an HTTPBlobstore, may have a v4 manifest
+		var v4Err error
+		contents, v4Err = fileManifestV4{}.parseManifest(bytes.NewReader(data))
+
+		if v4Err != nil {
+			return "", manifestContents{}, err
+		}
+	}
+
 	return ver, contents, nil
 }
 
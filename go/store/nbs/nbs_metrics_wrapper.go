@@ -81,3 +81,8 @@ func (nbsMW *NBSMetricWrapper) GetManyCompressed(ctx context.Context, hashes has
 	atomic.AddInt32(&nbsMW.TotalChunkGets, int32(len(hashes)))
 	return nbsMW.nbs.GetManyCompressed(ctx, hashes, found)
 }
+
+// ChunksRead returns the number of chunks which were requested from the wrapped store since it was opened.
+func (nbsMW *NBSMetricWrapper) ChunksRead() uint64 {
+	return nbsMW.nbs.ChunksRead()
+}
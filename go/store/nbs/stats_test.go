@@ -83,6 +83,7 @@ func TestStats(t *testing.T) {
 	assert.Equal(uint64(3), stats(store).GetLatency.Samples())
 	assert.Equal(uint64(0), stats(store).FileReadLatency.Samples())
 	assert.Equal(uint64(3), stats(store).ChunksPerGet.Sum())
+	assert.Equal(uint64(3), store.ChunksRead())
 
 	h, err := store.Root(context.Background())
 	assert.NoError(err)
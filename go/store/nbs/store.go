@@ -926,6 +926,12 @@ func (nbs *NomsBlockStore) Stats() interface{} {
 	return *nbs.stats
 }
 
+// ChunksRead returns the number of chunks which were requested from the store by Get, GetMany and GetManyCompressed
+// since it was opened.
+func (nbs *NomsBlockStore) ChunksRead() uint64 {
+	return nbs.stats.ChunksPerGet.Sum()
+}
+
 func (nbs *NomsBlockStore) StatsSummary() string {
 	nbs.mu.Lock()
 	defer nbs.mu.Unlock()
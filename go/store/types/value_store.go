@@ -101,6 +101,11 @@ const (
 // newTestValueStore creates a simple struct that satisfies ValueReadWriter
 // and is backed by a chunks.TestStore.
 func newTestValueStore() *ValueStore {
+	return NewMemoryValueStore()
+}
+
+// NewMemoryValueStore creates a ValueStore backed by an in-memory chunks.TestStore.
+func NewMemoryValueStore() *ValueStore {
 	ts := &chunks.TestStorage{}
 	return NewValueStore(ts.NewView())
 }
@@ -0,0 +1,296 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ed25519"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"gopkg.in/square/go-jose.v2/jwt"
+	"gopkg.in/yaml.v2"
+
+	remotesapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/remotesapi/v1alpha1"
+	"github.com/dolthub/dolt/go/libraries/doltcore/creds"
+)
+
+// Permission is the level of access a user has to a repository
+type Permission int
+
+const (
+	NoPermission Permission = iota
+	ReadPermission
+	WritePermission
+)
+
+// anyUser can be used in the permissions of a repository to grant access to everyone, including clients which do not
+// provide credentials.
+const anyUser = "*"
+
+const chunkStoreServicePrefix = "/dolt.services.remotesapi.v1alpha1.ChunkStoreService/"
+
+// methodPermissions is the permission required to call each method of the ChunkStoreService
+var methodPermissions = map[string]Permission{
+	"HasChunks":            ReadPermission,
+	"GetDownloadLocations": ReadPermission,
+	"GetRepoMetadata":      ReadPermission,
+	"ListTableFiles":       ReadPermission,
+	"Rebase":               ReadPermission,
+	"Root":                 ReadPermission,
+	"GetUploadLocations":   WritePermission,
+	"Commit":               WritePermission,
+	"AddTableFiles":        WritePermission,
+}
+
+var ErrInvalidToken = errors.New("invalid bearer token")
+var ErrUnknownKey = errors.New("bearer token signed by an unknown key")
+
+// UserConfig is the configuration of a single user of the server
+type UserConfig struct {
+	// Keys are the base32 encoded public keys of the user's dolt credentials, as displayed by `dolt creds ls -v`
+	Keys []string `yaml:"keys"`
+}
+
+// RepoPermissions lists the users which can read and write a set of repositories.  Users with write access are also
+// able to read.
+type RepoPermissions struct {
+	Read  []string `yaml:"read"`
+	Write []string `yaml:"write"`
+}
+
+// AuthConfig is the yaml configuration of the users of the server and their permissions.  Repos is keyed by org/repo,
+// org/* or *.  The most specific entry matching a repository determines its permissions.  For example:
+//
+//   users:
+//     alice:
+//       keys: [bkoq1nfq52bjvg90jvb04qinmobae6ifna4ss91ihd0ijrbonla0]
+//   repos:
+//     "*":
+//       read: ["*"]
+//     "alice/*":
+//       read: ["*"]
+//       write: [alice]
+type AuthConfig struct {
+	Users map[string]UserConfig      `yaml:"users"`
+	Repos map[string]RepoPermissions `yaml:"repos"`
+}
+
+// LoadAuthConfig reads an AuthConfig from the yaml file at |path|
+func LoadAuthConfig(path string) (*AuthConfig, error) {
+	data, err := ioutil.ReadFile(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg AuthConfig
+	err = yaml.UnmarshalStrict(data, &cfg)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &cfg, nil
+}
+
+type userKey struct {
+	user   string
+	pubKey ed25519.PublicKey
+}
+
+// Authorizer authenticates the bearer tokens sent by dolt clients, and checks the permissions of users
+type Authorizer struct {
+	keys  map[string]userKey
+	repos map[string]RepoPermissions
+}
+
+// NewAuthorizer returns an Authorizer for the users and permissions in |cfg|
+func NewAuthorizer(cfg *AuthConfig) (*Authorizer, error) {
+	keys := make(map[string]userKey)
+	for user, userCfg := range cfg.Users {
+		if user == anyUser {
+			return nil, fmt.Errorf("'%s' is not a valid user name", anyUser)
+		}
+
+		for _, pubKeyStr := range userCfg.Keys {
+			pubKey, err := creds.B32CredsEncoding.DecodeString(pubKeyStr)
+
+			if err != nil || len(pubKey) != ed25519.PublicKeySize {
+				return nil, fmt.Errorf("invalid public key for user '%s': '%s'", user, pubKeyStr)
+			}
+
+			keys[creds.PubKeyToKIDStr(pubKey)] = userKey{user, pubKey}
+		}
+	}
+
+	repos := make(map[string]RepoPermissions)
+	for name, perms := range cfg.Repos {
+		repos[name] = perms
+	}
+
+	return &Authorizer{keys, repos}, nil
+}
+
+// Authenticate validates a bearer token created by a dolt client, and returns the name of the user which owns the key
+// it was signed with.
+func (a *Authorizer) Authenticate(token string) (string, error) {
+	parsed, err := jwt.ParseSigned(token)
+
+	if err != nil || len(parsed.Headers) != 1 {
+		return "", ErrInvalidToken
+	}
+
+	key, ok := a.keys[parsed.Headers[0].KeyID]
+
+	if !ok {
+		return "", ErrUnknownKey
+	}
+
+	var claims jwt.Claims
+	err = parsed.Claims(key.pubKey, &claims)
+
+	if err != nil {
+		return "", ErrInvalidToken
+	}
+
+	err = claims.Validate(jwt.Expected{Time: time.Now()})
+
+	if err != nil {
+		return "", err
+	}
+
+	return key.user, nil
+}
+
+// Permission returns the permission |user| has for the repository |org|/|repo|.  Clients which do not provide
+// credentials have an empty user name.
+func (a *Authorizer) Permission(user, org, repo string) Permission {
+	perms, ok := a.repos[org+"/"+repo]
+
+	if !ok {
+		perms, ok = a.repos[org+"/*"]
+	}
+
+	if !ok {
+		perms, ok = a.repos[anyUser]
+	}
+
+	if !ok {
+		return NoPermission
+	}
+
+	if containsUser(perms.Write, user) {
+		return WritePermission
+	} else if containsUser(perms.Read, user) {
+		return ReadPermission
+	}
+
+	return NoPermission
+}
+
+func containsUser(users []string, user string) bool {
+	for _, u := range users {
+		if u == anyUser || (user != "" && u == user) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type permissionKey struct{}
+
+// permissionFromContext returns the permission granted to the client for the repository of the current request.  If
+// the server is not using an Authorizer every client has WritePermission.
+func permissionFromContext(ctx context.Context) Permission {
+	if perm, ok := ctx.Value(permissionKey{}).(Permission); ok {
+		return perm
+	}
+
+	return WritePermission
+}
+
+type repoRequest interface {
+	GetRepoId() *remotesapi.RepoId
+}
+
+// UnaryInterceptor returns a grpc interceptor which rejects requests from clients which don't have the permissions
+// needed for the method being called.
+func (a *Authorizer) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		required, ok := methodPermissions[strings.TrimPrefix(info.FullMethod, chunkStoreServicePrefix)]
+		repoReq, hasRepo := req.(repoRequest)
+
+		if !ok || !hasRepo || repoReq.GetRepoId() == nil {
+			return nil, status.Error(codes.PermissionDenied, "unknown method "+info.FullMethod)
+		}
+
+		user, err := a.userFromContext(ctx)
+
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+
+		repoId := repoReq.GetRepoId()
+		perm := a.Permission(user, repoId.Org, repoId.RepoName)
+
+		if perm < required {
+			if user == "" {
+				return nil, status.Error(codes.Unauthenticated, "credentials are required to access "+repoId.Org+"/"+repoId.RepoName)
+			}
+
+			return nil, status.Errorf(codes.PermissionDenied, "%s does not have permission to access %s/%s", user, repoId.Org, repoId.RepoName)
+		}
+
+		return handler(context.WithValue(ctx, permissionKey{}, perm), req)
+	}
+}
+
+func (a *Authorizer) userFromContext(ctx context.Context) (string, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+
+	if !ok {
+		return "", nil
+	}
+
+	authHeaders := md.Get("authorization")
+
+	if len(authHeaders) == 0 {
+		return "", nil
+	}
+
+	const bearerPrefix = "Bearer "
+	if !strings.HasPrefix(authHeaders[0], bearerPrefix) {
+		return "", ErrInvalidToken
+	}
+
+	user, err := a.Authenticate(strings.TrimPrefix(authHeaders[0], bearerPrefix))
+
+	if err == ErrUnknownKey {
+		// dolt clients send a token signed with the user's current credentials to every remote, so a key which
+		// isn't configured is treated the same as a client without credentials.
+		return "", nil
+	}
+
+	return user, err
+}
@@ -0,0 +1,125 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/creds"
+)
+
+func bearerToken(t *testing.T, dc creds.DoltCreds) string {
+	md, err := dc.GetRequestMetadata(context.Background())
+	require.NoError(t, err)
+
+	return strings.TrimPrefix(md["authorization"], "Bearer ")
+}
+
+func TestAuthorizer(t *testing.T) {
+	alice, err := creds.GenerateCredentials()
+	require.NoError(t, err)
+	bob, err := creds.GenerateCredentials()
+	require.NoError(t, err)
+	unknown, err := creds.GenerateCredentials()
+	require.NoError(t, err)
+
+	auth, err := NewAuthorizer(&AuthConfig{
+		Users: map[string]UserConfig{
+			"alice": {Keys: []string{alice.PubKeyBase32Str()}},
+			"bob":   {Keys: []string{bob.PubKeyBase32Str()}},
+		},
+		Repos: map[string]RepoPermissions{
+			"*":            {Read: []string{"*"}},
+			"alice/*":      {Read: []string{"*"}, Write: []string{"alice"}},
+			"alice/secret": {Write: []string{"alice"}},
+			"bob/*":        {Read: []string{"alice"}, Write: []string{"bob"}},
+		},
+	})
+	require.NoError(t, err)
+
+	user, err := auth.Authenticate(bearerToken(t, alice))
+	require.NoError(t, err)
+	assert.Equal(t, "alice", user)
+
+	user, err = auth.Authenticate(bearerToken(t, bob))
+	require.NoError(t, err)
+	assert.Equal(t, "bob", user)
+
+	_, err = auth.Authenticate(bearerToken(t, unknown))
+	assert.Equal(t, ErrUnknownKey, err)
+
+	_, err = auth.Authenticate("not a token")
+	assert.Equal(t, ErrInvalidToken, err)
+
+	tests := []struct {
+		user     string
+		org      string
+		repo     string
+		expected Permission
+	}{
+		{"", "other", "repo", ReadPermission},
+		{"bob", "other", "repo", ReadPermission},
+		{"", "alice", "repo", ReadPermission},
+		{"alice", "alice", "repo", WritePermission},
+		{"bob", "alice", "repo", ReadPermission},
+		{"", "alice", "secret", NoPermission},
+		{"bob", "alice", "secret", NoPermission},
+		{"alice", "alice", "secret", WritePermission},
+		{"", "bob", "repo", NoPermission},
+		{"alice", "bob", "repo", ReadPermission},
+		{"bob", "bob", "repo", WritePermission},
+	}
+
+	for _, test := range tests {
+		actual := auth.Permission(test.user, test.org, test.repo)
+		assert.Equal(t, test.expected, actual, "user: '%s' repo: %s/%s", test.user, test.org, test.repo)
+	}
+}
+
+func TestURLSigner(t *testing.T) {
+	signer, err := newURLSigner()
+	require.NoError(t, err)
+
+	const path = "org/repo/m7edcm8jm6cfda266qle6gs0mkf36uce"
+	readQuery, err := url.ParseQuery(signer.sign(false, path))
+	require.NoError(t, err)
+	writeQuery, err := url.ParseQuery(signer.sign(true, path))
+	require.NoError(t, err)
+
+	assert.True(t, signer.verify(false, path, readQuery))
+	assert.True(t, signer.verify(true, path, writeQuery))
+	assert.False(t, signer.verify(true, path, readQuery), "a read url should not allow writes")
+	assert.False(t, signer.verify(false, "org/other/m7edcm8jm6cfda266qle6gs0mkf36uce", readQuery))
+	assert.False(t, signer.verify(false, path, url.Values{}))
+
+	otherSigner, err := newURLSigner()
+	require.NoError(t, err)
+	assert.False(t, otherSigner.verify(false, path, readQuery))
+}
+
+func TestValidateRepoName(t *testing.T) {
+	assert.NoError(t, validateRepoName("org", "repo"))
+	assert.NoError(t, validateRepoName("my-org", "repo.v2"))
+	assert.Error(t, validateRepoName("..", "repo"))
+	assert.Error(t, validateRepoName("org", "."))
+	assert.Error(t, validateRepoName("org", ""))
+	assert.Error(t, validateRepoName("org/sub", "repo"))
+}
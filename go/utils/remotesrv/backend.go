@@ -0,0 +1,233 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+
+	"cloud.google.com/go/storage"
+
+	"github.com/dolthub/dolt/go/libraries/utils/earl"
+	"github.com/dolthub/dolt/go/store/blobstore"
+	"github.com/dolthub/dolt/go/store/nbs"
+)
+
+var ErrRepoNotFound = errors.New("repository not found")
+var ErrInvalidRepoName = errors.New("invalid repository name")
+
+var repoNameRegex = regexp.MustCompile(`^[A-Za-z0-9_\-][A-Za-z0-9_.\-]*$`)
+
+// validateRepoName returns ErrInvalidRepoName if |org| or |repo| can't be used as part of a path within a Backend
+func validateRepoName(org, repo string) error {
+	if !repoNameRegex.MatchString(org) || !repoNameRegex.MatchString(repo) {
+		return ErrInvalidRepoName
+	}
+
+	return nil
+}
+
+// Backend is the storage used to hold the table files and manifests of the repositories hosted by the server.  Each
+// repository is stored separately beneath the path org/repo.
+type Backend interface {
+	// Open returns the chunk store for the repository |org|/|repo|.  If the repository doesn't exist and |create| is
+	// false ErrRepoNotFound is returned.
+	Open(ctx context.Context, org, repo, nbfVerStr string, create bool) (*nbs.NomsBlockStore, error)
+
+	// ReadTableFile returns a reader for |length| bytes of a table file starting at |offset|.  If |length| is 0 the
+	// reader returns the remainder of the file.
+	ReadTableFile(ctx context.Context, org, repo, fileID string, offset, length int64) (io.ReadCloser, error)
+
+	// WriteTableFile writes a table file which has been uploaded.
+	WriteTableFile(ctx context.Context, org, repo, fileID string, rd io.Reader) error
+}
+
+// NewBackend returns the Backend for |backendURL|.  An empty url stores repositories on the local disk beneath the
+// working directory.  Blobstore backends are supported using the urls gs://bucket/path and localbs://path.
+func NewBackend(ctx context.Context, backendURL string) (Backend, error) {
+	if backendURL == "" {
+		return diskBackend{"."}, nil
+	}
+
+	u, err := earl.Parse(backendURL)
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch u.Scheme {
+	case "file":
+		return diskBackend{filepath.Join(u.Host, filepath.FromSlash(u.Path))}, nil
+
+	case "localbs":
+		dir := filepath.Join(u.Host, filepath.FromSlash(u.Path))
+		return blobBackend{func(org, repo string) (blobstore.Blobstore, error) {
+			repoDir := filepath.Join(dir, org, repo)
+			err := os.MkdirAll(repoDir, os.ModePerm)
+
+			if err != nil {
+				return nil, err
+			}
+
+			return blobstore.NewLocalBlobstore(repoDir), nil
+		}}, nil
+
+	case "gs":
+		gcs, err := storage.NewClient(ctx)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return blobBackend{func(org, repo string) (blobstore.Blobstore, error) {
+			return blobstore.NewGCSBlobstore(gcs, u.Host, path.Join(u.Path, org, repo)+"/"), nil
+		}}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported backend url: '%s'", (&url.URL{Scheme: u.Scheme, Host: u.Host, Path: u.Path}).String())
+}
+
+// diskBackend stores repositories as local noms directories beneath |root|.
+type diskBackend struct {
+	root string
+}
+
+func (db diskBackend) Open(ctx context.Context, org, repo, nbfVerStr string, create bool) (*nbs.NomsBlockStore, error) {
+	dir := filepath.Join(db.root, org, repo)
+
+	if create {
+		err := os.MkdirAll(dir, os.ModePerm)
+
+		if err != nil {
+			return nil, err
+		}
+	} else if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return nil, ErrRepoNotFound
+	}
+
+	return nbs.NewLocalStore(ctx, nbfVerStr, dir, defaultMemTableSize)
+}
+
+func (db diskBackend) ReadTableFile(ctx context.Context, org, repo, fileID string, offset, length int64) (io.ReadCloser, error) {
+	f, err := os.Open(filepath.Join(db.root, org, repo, fileID))
+
+	if os.IsNotExist(err) {
+		return nil, ErrTableFileNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	_, err = f.Seek(offset, io.SeekStart)
+
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	if length == 0 {
+		return f, nil
+	}
+
+	return limitedReadCloser{io.LimitReader(f, length), f}, nil
+}
+
+func (db diskBackend) WriteTableFile(ctx context.Context, org, repo, fileID string, rd io.Reader) error {
+	dir := filepath.Join(db.root, org, repo)
+	f, err := ioutil.TempFile(dir, fileID+".tmp")
+
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(f, rd)
+
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+
+	// table files are renamed into place so that a partially written file is never visible
+	return os.Rename(f.Name(), filepath.Join(dir, fileID))
+}
+
+// blobBackend stores each repository in a separate Blobstore.
+type blobBackend struct {
+	repoBlobstore func(org, repo string) (blobstore.Blobstore, error)
+}
+
+func (bb blobBackend) Open(ctx context.Context, org, repo, nbfVerStr string, create bool) (*nbs.NomsBlockStore, error) {
+	bs, err := bb.repoBlobstore(org, repo)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !create {
+		exists, err := bs.Exists(ctx, "manifest")
+
+		if err != nil {
+			return nil, err
+		} else if !exists {
+			return nil, ErrRepoNotFound
+		}
+	}
+
+	return nbs.NewBSStore(ctx, nbfVerStr, bs, defaultMemTableSize)
+}
+
+func (bb blobBackend) ReadTableFile(ctx context.Context, org, repo, fileID string, offset, length int64) (io.ReadCloser, error) {
+	bs, err := bb.repoBlobstore(org, repo)
+
+	if err != nil {
+		return nil, err
+	}
+
+	rd, _, err := bs.Get(ctx, fileID, blobstore.NewBlobRange(offset, length))
+
+	if blobstore.IsNotFoundError(err) {
+		return nil, ErrTableFileNotFound
+	}
+
+	return rd, err
+}
+
+func (bb blobBackend) WriteTableFile(ctx context.Context, org, repo, fileID string, rd io.Reader) error {
+	bs, err := bb.repoBlobstore(org, repo)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = bs.Put(ctx, fileID, rd)
+
+	return err
+}
+
+type limitedReadCloser struct {
+	io.Reader
+	io.Closer
+}
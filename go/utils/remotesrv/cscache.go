@@ -16,10 +16,9 @@ package main
 
 import (
 	"context"
-	"path/filepath"
+	"path"
 	"sync"
 
-	"github.com/dolthub/dolt/go/libraries/utils/filesys"
 	"github.com/dolthub/dolt/go/store/nbs"
 )
 
@@ -27,44 +26,44 @@ const (
 	defaultMemTableSize = 128 * 1024 * 1024
 )
 
+// DBCache caches the chunk stores of the repositories which have been opened
 type DBCache struct {
 	mu  *sync.Mutex
 	dbs map[string]*nbs.NomsBlockStore
 
-	fs filesys.Filesys
+	backend Backend
 }
 
-func NewLocalCSCache(filesys filesys.Filesys) *DBCache {
+func NewCSCache(backend Backend) *DBCache {
 	return &DBCache{
 		&sync.Mutex{},
 		make(map[string]*nbs.NomsBlockStore),
-		filesys,
+		backend,
 	}
 }
 
-func (cache *DBCache) Get(org, repo, nbfVerStr string) (*nbs.NomsBlockStore, error) {
+// Get returns the chunk store for the repository |org|/|repo|. If the repository does not exist it will be created
+// when |create| is true, and ErrRepoNotFound is returned otherwise.
+func (cache *DBCache) Get(ctx context.Context, org, repo, nbfVerStr string, create bool) (*nbs.NomsBlockStore, error) {
+	err := validateRepoName(org, repo)
+
+	if err != nil {
+		return nil, err
+	}
+
 	cache.mu.Lock()
 	defer cache.mu.Unlock()
 
-	id := filepath.Join(org, repo)
+	id := path.Join(org, repo)
 
 	if cs, ok := cache.dbs[id]; ok {
 		return cs, nil
 	}
 
-	var newCS *nbs.NomsBlockStore
-	if cache.fs != nil {
-		err := cache.fs.MkDirs(id)
-
-		if err != nil {
-			return nil, err
-		}
-
-		newCS, err = nbs.NewLocalStore(context.TODO(), nbfVerStr, id, defaultMemTableSize)
+	newCS, err := cache.backend.Open(ctx, org, repo, nbfVerStr, create)
 
-		if err != nil {
-			return nil, err
-		}
+	if err != nil {
+		return nil, err
 	}
 
 	cache.dbs[id] = newCS
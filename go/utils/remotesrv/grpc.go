@@ -18,8 +18,6 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"os"
-	"path/filepath"
 	"sync/atomic"
 
 	"google.golang.org/grpc/codes"
@@ -35,15 +33,19 @@ import (
 type RemoteChunkStore struct {
 	HttpHost string
 	csCache  *DBCache
-	bucket   string
+	signer   *urlSigner
+	expected *expectedFiles
 	remotesapi.UnimplementedChunkStoreServiceServer
 }
 
-func NewHttpFSBackedChunkStore(httpHost string, csCache *DBCache) *RemoteChunkStore {
+// NewRemoteChunkStore returns a RemoteChunkStore which serves the repositories in |csCache|.  Table files are read and
+// written using urls on |httpHost| which are signed by |signer|.
+func NewRemoteChunkStore(httpHost string, csCache *DBCache, signer *urlSigner, expected *expectedFiles) *RemoteChunkStore {
 	return &RemoteChunkStore{
 		HttpHost: httpHost,
 		csCache:  csCache,
-		bucket:   "",
+		signer:   signer,
+		expected: expected,
 	}
 }
 
@@ -51,10 +53,10 @@ func (rs *RemoteChunkStore) HasChunks(ctx context.Context, req *remotesapi.HasCh
 	logger := getReqLogger("GRPC", "HasChunks")
 	defer func() { logger("finished") }()
 
-	cs := rs.getStore(req.RepoId, "HasChunks")
+	cs, err := rs.getStore(ctx, req.RepoId, "HasChunks")
 
-	if cs == nil {
-		return nil, status.Error(codes.Internal, "Could not get chunkstore")
+	if err != nil {
+		return nil, err
 	}
 
 	logger(fmt.Sprintf("found repo %s/%s", req.RepoId.Org, req.RepoId.RepoName))
@@ -88,10 +90,10 @@ func (rs *RemoteChunkStore) GetDownloadLocations(ctx context.Context, req *remot
 	logger := getReqLogger("GRPC", "GetDownloadLocations")
 	defer func() { logger("finished") }()
 
-	cs := rs.getStore(req.RepoId, "GetDownloadLoctions")
+	cs, err := rs.getStore(ctx, req.RepoId, "GetDownloadLoctions")
 
-	if cs == nil {
-		return nil, status.Error(codes.Internal, "Could not get chunkstore")
+	if err != nil {
+		return nil, err
 	}
 
 	logger(fmt.Sprintf("found repo %s/%s", req.RepoId.Org, req.RepoId.RepoName))
@@ -128,7 +130,8 @@ func (rs *RemoteChunkStore) GetDownloadLocations(ctx context.Context, req *remot
 }
 
 func (rs *RemoteChunkStore) getDownloadUrl(logger func(string), org, repoName, fileId string) (string, error) {
-	return fmt.Sprintf("http://%s/%s/%s/%s", rs.HttpHost, org, repoName, fileId), nil
+	path := org + "/" + repoName + "/" + fileId
+	return fmt.Sprintf("http://%s/%s?%s", rs.HttpHost, path, rs.signer.sign(false, path)), nil
 }
 
 func parseTableFileDetails(req *remotesapi.GetUploadLocsRequest) []*remotesapi.TableFileDetails {
@@ -154,10 +157,10 @@ func (rs *RemoteChunkStore) GetUploadLocations(ctx context.Context, req *remotes
 	logger := getReqLogger("GRPC", "GetUploadLocations")
 	defer func() { logger("finished") }()
 
-	cs := rs.getStore(req.RepoId, "GetWriteChunkUrls")
+	_, err := rs.getStore(ctx, req.RepoId, "GetWriteChunkUrls")
 
-	if cs == nil {
-		return nil, status.Error(codes.Internal, "Could not get chunkstore")
+	if err != nil {
+		return nil, err
 	}
 
 	logger(fmt.Sprintf("found repo %s/%s", req.RepoId.Org, req.RepoId.RepoName))
@@ -186,23 +189,24 @@ func (rs *RemoteChunkStore) GetUploadLocations(ctx context.Context, req *remotes
 
 func (rs *RemoteChunkStore) getUploadUrl(logger func(string), org, repoName string, tfd *remotesapi.TableFileDetails) (string, error) {
 	fileID := hash.New(tfd.Id).String()
-	expectedFiles[fileID] = tfd
-	return fmt.Sprintf("http://%s/%s/%s/%s", rs.HttpHost, org, repoName, fileID), nil
+	path := org + "/" + repoName + "/" + fileID
+	rs.expected.add(path, tfd)
+	return fmt.Sprintf("http://%s/%s?%s", rs.HttpHost, path, rs.signer.sign(true, path)), nil
 }
 
 func (rs *RemoteChunkStore) Rebase(ctx context.Context, req *remotesapi.RebaseRequest) (*remotesapi.RebaseResponse, error) {
 	logger := getReqLogger("GRPC", "Rebase")
 	defer func() { logger("finished") }()
 
-	cs := rs.getStore(req.RepoId, "Rebase")
+	cs, err := rs.getStore(ctx, req.RepoId, "Rebase")
 
-	if cs == nil {
-		return nil, status.Error(codes.Internal, "Could not get chunkstore")
+	if err != nil {
+		return nil, err
 	}
 
 	logger(fmt.Sprintf("found %s/%s", req.RepoId.Org, req.RepoId.RepoName))
 
-	err := cs.Rebase(ctx)
+	err = cs.Rebase(ctx)
 
 	if err != nil {
 		logger(fmt.Sprintf("error occurred during processing of Rebace rpc of %s/%s details: %v", req.RepoId.Org, req.RepoId.RepoName, err))
@@ -216,10 +220,10 @@ func (rs *RemoteChunkStore) Root(ctx context.Context, req *remotesapi.RootReques
 	logger := getReqLogger("GRPC", "Root")
 	defer func() { logger("finished") }()
 
-	cs := rs.getStore(req.RepoId, "Root")
+	cs, err := rs.getStore(ctx, req.RepoId, "Root")
 
-	if cs == nil {
-		return nil, status.Error(codes.Internal, "Could not get chunkstore")
+	if err != nil {
+		return nil, err
 	}
 
 	h, err := cs.Root(ctx)
@@ -236,10 +240,10 @@ func (rs *RemoteChunkStore) Commit(ctx context.Context, req *remotesapi.CommitRe
 	logger := getReqLogger("GRPC", "Commit")
 	defer func() { logger("finished") }()
 
-	cs := rs.getStore(req.RepoId, "Commit")
+	cs, err := rs.getStore(ctx, req.RepoId, "Commit")
 
-	if cs == nil {
-		return nil, status.Error(codes.Internal, "Could not get chunkstore")
+	if err != nil {
+		return nil, err
 	}
 
 	logger(fmt.Sprintf("found %s/%s", req.RepoId.Org, req.RepoId.RepoName))
@@ -250,7 +254,7 @@ func (rs *RemoteChunkStore) Commit(ctx context.Context, req *remotesapi.CommitRe
 		updates[hash.New(cti.Hash)] = cti.ChunkCount
 	}
 
-	_, err := cs.UpdateManifest(ctx, updates)
+	_, err = cs.UpdateManifest(ctx, updates)
 
 	if err != nil {
 		logger(fmt.Sprintf("error occurred updating the manifest: %s", err.Error()))
@@ -276,27 +280,16 @@ func (rs *RemoteChunkStore) GetRepoMetadata(ctx context.Context, req *remotesapi
 	logger := getReqLogger("GRPC", "GetRepoMetadata")
 	defer func() { logger("finished") }()
 
-	cs := rs.getOrCreateStore(req.RepoId, "GetRepoMetadata", req.ClientRepoFormat.NbfVersion)
-	if cs == nil {
-		return nil, status.Error(codes.Internal, "Could not get chunkstore")
-	}
-
-	_, tfs, err := cs.Sources(ctx)
+	cs, err := rs.getOrCreateStore(ctx, req.RepoId, "GetRepoMetadata", req.ClientRepoFormat.NbfVersion)
 
 	if err != nil {
 		return nil, err
 	}
 
-	var size uint64
-	for _, tf := range tfs {
-		path := filepath.Join(req.RepoId.Org, req.RepoId.RepoName, tf.FileID())
-		info, err := os.Stat(path)
-
-		if err != nil {
-			return nil, err
-		}
+	size, err := cs.Size(ctx)
 
-		size += uint64(info.Size())
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to get the size of the repository")
 	}
 
 	return &remotesapi.GetRepoMetadataResponse{
@@ -310,10 +303,10 @@ func (rs *RemoteChunkStore) ListTableFiles(ctx context.Context, req *remotesapi.
 	logger := getReqLogger("GRPC", "ListTableFiles")
 	defer func() { logger("finished") }()
 
-	cs := rs.getStore(req.RepoId, "ListTableFiles")
+	cs, err := rs.getStore(ctx, req.RepoId, "ListTableFiles")
 
-	if cs == nil {
-		return nil, status.Error(codes.Internal, "Could not get chunkstore")
+	if err != nil {
+		return nil, err
 	}
 
 	logger(fmt.Sprintf("found repo %s/%s", req.RepoId.Org, req.RepoId.RepoName))
@@ -352,10 +345,10 @@ func (rs *RemoteChunkStore) AddTableFiles(ctx context.Context, req *remotesapi.A
 	logger := getReqLogger("GRPC", "Commit")
 	defer func() { logger("finished") }()
 
-	cs := rs.getStore(req.RepoId, "Commit")
+	cs, err := rs.getStore(ctx, req.RepoId, "Commit")
 
-	if cs == nil {
-		return nil, status.Error(codes.Internal, "Could not get chunkstore")
+	if err != nil {
+		return nil, err
 	}
 
 	logger(fmt.Sprintf("found %s/%s", req.RepoId.Org, req.RepoId.RepoName))
@@ -366,7 +359,7 @@ func (rs *RemoteChunkStore) AddTableFiles(ctx context.Context, req *remotesapi.A
 		updates[hash.New(cti.Hash)] = cti.ChunkCount
 	}
 
-	_, err := cs.UpdateManifest(ctx, updates)
+	_, err = cs.UpdateManifest(ctx, updates)
 
 	if err != nil {
 		logger(fmt.Sprintf("error occurred updating the manifest: %s", err.Error()))
@@ -376,21 +369,34 @@ func (rs *RemoteChunkStore) AddTableFiles(ctx context.Context, req *remotesapi.A
 	return &remotesapi.AddTableFilesResponse{Success: true}, nil
 }
 
-func (rs *RemoteChunkStore) getStore(repoId *remotesapi.RepoId, rpcName string) *nbs.NomsBlockStore {
-	return rs.getOrCreateStore(repoId, rpcName, types.Format_Default.VersionString())
+func (rs *RemoteChunkStore) getStore(ctx context.Context, repoId *remotesapi.RepoId, rpcName string) (*nbs.NomsBlockStore, error) {
+	return rs.getOrCreateStore(ctx, repoId, rpcName, types.Format_Default.VersionString())
 }
 
-func (rs *RemoteChunkStore) getOrCreateStore(repoId *remotesapi.RepoId, rpcName, nbfVerStr string) *nbs.NomsBlockStore {
+// getOrCreateStore returns the chunk store for a repository.  Repositories which don't exist yet are only created for
+// clients which have write permission.
+func (rs *RemoteChunkStore) getOrCreateStore(ctx context.Context, repoId *remotesapi.RepoId, rpcName, nbfVerStr string) (*nbs.NomsBlockStore, error) {
+	if repoId == nil {
+		return nil, status.Error(codes.InvalidArgument, "no repository specified")
+	}
+
 	org := repoId.Org
 	repoName := repoId.RepoName
+	create := permissionFromContext(ctx) == WritePermission
 
-	cs, err := rs.csCache.Get(org, repoName, nbfVerStr)
+	cs, err := rs.csCache.Get(ctx, org, repoName, nbfVerStr, create)
 
-	if err != nil {
-		log.Printf("Failed to retrieve chunkstore for %s/%s\n", org, repoName)
+	switch err {
+	case nil:
+		return cs, nil
+	case ErrInvalidRepoName:
+		return nil, status.Errorf(codes.InvalidArgument, "invalid repository name %s/%s", org, repoName)
+	case ErrRepoNotFound:
+		return nil, status.Errorf(codes.NotFound, "repository %s/%s not found", org, repoName)
 	}
 
-	return cs
+	log.Printf("Failed to retrieve chunkstore for %s/%s during %s: %v\n", org, repoName, rpcName, err)
+	return nil, status.Error(codes.Internal, "Could not get chunkstore")
 }
 
 var requestId int32
@@ -16,54 +16,161 @@ package main
 
 import (
 	"bytes"
+	"crypto/hmac"
 	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
-	"os"
-	"path/filepath"
+	"net/url"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	remotesapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/remotesapi/v1alpha1"
-
-	"github.com/dolthub/dolt/go/libraries/utils/iohelp"
 	"github.com/dolthub/dolt/go/store/hash"
 )
 
-var expectedFiles = make(map[string]*remotesapi.TableFileDetails)
+const (
+	readURLExpiration  = 24 * time.Hour
+	writeURLExpiration = time.Hour
+
+	expiresParam   = "expires"
+	signatureParam = "signature"
+)
+
+var ErrTableFileNotFound = errors.New("table file not found")
+
+// urlSigner signs the urls of the table files handed out by the grpc server, so that the http server only serves the
+// reads and writes which have been authorized.
+type urlSigner struct {
+	key []byte
+}
+
+func newURLSigner() (*urlSigner, error) {
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &urlSigner{key}, nil
+}
+
+func (s *urlSigner) signature(write bool, path string, expires int64) string {
+	mac := hmac.New(sha256.New, s.key)
+	fmt.Fprintf(mac, "%t\n%s\n%d", write, path, expires)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// sign returns the query string which authorizes a read, or a write, of the file at |path|
+func (s *urlSigner) sign(write bool, path string) string {
+	expiration := readURLExpiration
+	if write {
+		expiration = writeURLExpiration
+	}
+
+	expires := time.Now().Add(expiration).Unix()
+	vals := url.Values{
+		expiresParam:   []string{strconv.FormatInt(expires, 10)},
+		signatureParam: []string{s.signature(write, path, expires)},
+	}
+
+	return vals.Encode()
+}
+
+func (s *urlSigner) verify(write bool, path string, query url.Values) bool {
+	expires, err := strconv.ParseInt(query.Get(expiresParam), 10, 64)
+
+	if err != nil || time.Now().Unix() > expires {
+		return false
+	}
+
+	expected := s.signature(write, path, expires)
+
+	return hmac.Equal([]byte(expected), []byte(query.Get(signatureParam)))
+}
+
+// expectedFiles holds the details of the table files which clients have been given upload locations for
+type expectedFiles struct {
+	mu    *sync.Mutex
+	files map[string]*remotesapi.TableFileDetails
+}
+
+func newExpectedFiles() *expectedFiles {
+	return &expectedFiles{&sync.Mutex{}, make(map[string]*remotesapi.TableFileDetails)}
+}
+
+func (ef *expectedFiles) add(path string, tfd *remotesapi.TableFileDetails) {
+	ef.mu.Lock()
+	defer ef.mu.Unlock()
+
+	ef.files[path] = tfd
+}
+
+func (ef *expectedFiles) get(path string) (*remotesapi.TableFileDetails, bool) {
+	ef.mu.Lock()
+	defer ef.mu.Unlock()
 
-func ServeHTTP(respWr http.ResponseWriter, req *http.Request) {
-	logger := getReqLogger("HTTP_"+req.Method, req.RequestURI)
+	tfd, ok := ef.files[path]
+	return tfd, ok
+}
+
+// fileHandler serves reads and writes of the table files of the repositories stored in a Backend
+type fileHandler struct {
+	backend  Backend
+	signer   *urlSigner
+	expected *expectedFiles
+}
+
+func newFileHandler(backend Backend, signer *urlSigner, expected *expectedFiles) *fileHandler {
+	return &fileHandler{backend, signer, expected}
+}
+
+func (fh *fileHandler) ServeHTTP(respWr http.ResponseWriter, req *http.Request) {
+	logger := getReqLogger("HTTP_"+req.Method, req.URL.Path)
 	defer func() { logger("finished") }()
 
 	path := strings.TrimLeft(req.URL.Path, "/")
 	tokens := strings.Split(path, "/")
 
-	if len(tokens) != 3 {
-		logger(fmt.Sprintf("response to: %v method: %v http response code: %v", req.RequestURI, req.Method, http.StatusNotFound))
+	if len(tokens) != 3 || validateRepoName(tokens[0], tokens[1]) != nil {
+		logger(fmt.Sprintf("response to: %v method: %v http response code: %v", req.URL.Path, req.Method, http.StatusNotFound))
 		respWr.WriteHeader(http.StatusNotFound)
+		return
 	}
 
 	org := tokens[0]
 	repo := tokens[1]
-	hashStr := tokens[2]
+	fileId := tokens[2]
+
+	if _, ok := hash.MaybeParse(fileId); !ok {
+		logger(fileId + " is not a valid hash")
+		respWr.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	write := req.Method == http.MethodPost || req.Method == http.MethodPut
+	if !fh.signer.verify(write, path, req.URL.Query()) {
+		logger("invalid or expired signature")
+		respWr.WriteHeader(http.StatusForbidden)
+		return
+	}
 
 	statusCode := http.StatusMethodNotAllowed
 	switch req.Method {
 	case http.MethodGet:
-		rangeStr := req.Header.Get("Range")
-
-		if rangeStr == "" {
-			statusCode = readFile(logger, org, repo, hashStr, respWr)
-		} else {
-			statusCode = readChunk(logger, org, repo, hashStr, rangeStr, respWr)
-		}
+		statusCode = fh.readTableFile(logger, req, org, repo, fileId, respWr)
 
 	case http.MethodPost, http.MethodPut:
-		statusCode = writeTableFile(logger, org, repo, hashStr, req)
+		statusCode = fh.writeTableFile(logger, req, org, repo, fileId)
 	}
 
 	if statusCode != -1 {
@@ -71,23 +178,22 @@ func ServeHTTP(respWr http.ResponseWriter, req *http.Request) {
 	}
 }
 
-func writeTableFile(logger func(string), org, repo, fileId string, request *http.Request) int {
-	_, ok := hash.MaybeParse(fileId)
-
-	if !ok {
-		logger(fileId + " is not a valid hash")
-		return http.StatusBadRequest
-	}
-
-	tfd, ok := expectedFiles[fileId]
+func (fh *fileHandler) writeTableFile(logger func(string), request *http.Request, org, repo, fileId string) int {
+	tfd, ok := fh.expected.get(org + "/" + repo + "/" + fileId)
 
 	if !ok {
+		logger(fileId + " was not expected")
 		return http.StatusBadRequest
 	}
 
 	logger(fileId + " is valid")
 	data, err := ioutil.ReadAll(request.Body)
 
+	if err != nil {
+		logger("failed to read body " + err.Error())
+		return http.StatusInternalServerError
+	}
+
 	if tfd.ContentLength != 0 && tfd.ContentLength != uint64(len(data)) {
 		return http.StatusBadRequest
 	}
@@ -99,33 +205,16 @@ func writeTableFile(logger func(string), org, repo, fileId string, request *http
 		}
 	}
 
-	if err != nil {
-		logger("failed to read body " + err.Error())
-		return http.StatusInternalServerError
-	}
-
-	err = writeLocal(logger, org, repo, fileId, data)
+	err = fh.backend.WriteTableFile(request.Context(), org, repo, fileId, bytes.NewReader(data))
 
 	if err != nil {
+		logger(fmt.Sprintf("failed to write table file %s: %v", fileId, err))
 		return http.StatusInternalServerError
 	}
 
-	return http.StatusOK
-}
-
-func writeLocal(logger func(string), org, repo, fileId string, data []byte) error {
-	path := filepath.Join(org, repo, fileId)
-
-	err := ioutil.WriteFile(path, data, os.ModePerm)
-
-	if err != nil {
-		logger(fmt.Sprintf("failed to write file %s", path))
-		return err
-	}
-
 	logger("Successfully wrote object to storage")
 
-	return nil
+	return http.StatusOK
 }
 
 func offsetAndLenFromRange(rngStr string) (int64, int64, error) {
@@ -155,130 +244,60 @@ func offsetAndLenFromRange(rngStr string) (int64, int64, error) {
 		return -1, -1, errors.New("invalid length is not a number. should be bytes=#-#")
 	}
 
+	if end < start {
+		return -1, -1, errors.New("invalid range. the end of the range is before the start")
+	}
+
 	return int64(start), int64(end-start) + 1, nil
 }
 
-func readFile(logger func(string), org, repo, fileId string, writer io.Writer) int {
-	path := filepath.Join(org, repo, fileId)
-
-	info, err := os.Stat(path)
-
-	if err != nil {
-		logger("file not found. path: " + path)
-		return http.StatusNotFound
-	}
+func (fh *fileHandler) readTableFile(logger func(string), req *http.Request, org, repo, fileId string, respWr http.ResponseWriter) int {
+	var offset, length int64
+	statusCode := http.StatusOK
 
-	f, err := os.Open(path)
-
-	if err != nil {
-		logger("failed to open file. file: " + path + " err: " + err.Error())
-		return http.StatusInternalServerError
-	}
-
-	defer func() {
-		err := f.Close()
+	if rangeStr := req.Header.Get("Range"); rangeStr != "" {
+		var err error
+		offset, length, err = offsetAndLenFromRange(rangeStr)
 
 		if err != nil {
-			logger(fmt.Sprintf("Close failed. file: %s, err: %v", path, err))
-		} else {
-			logger("Close Successful")
+			logger(fmt.Sprintln(rangeStr, "is not a valid range"))
+			return http.StatusBadRequest
 		}
-	}()
-
-	n, err := io.Copy(writer, f)
-
-	if err != nil {
-		logger("failed to write data to response. err : " + err.Error())
-		return -1
-	}
 
-	if n != info.Size() {
-		logger(fmt.Sprintf("failed to write entire file to response. Copied %d of %d err: %v", n, info.Size(), err))
-		return -1
+		statusCode = http.StatusPartialContent
 	}
 
-	return -1
-}
-
-func readChunk(logger func(string), org, repo, fileId, rngStr string, writer io.Writer) int {
-	offset, length, err := offsetAndLenFromRange(rngStr)
+	logger(fmt.Sprintf("Attempting to read %d bytes at offset %d from %s/%s/%s", length, offset, org, repo, fileId))
+	rd, err := fh.backend.ReadTableFile(req.Context(), org, repo, fileId, offset, length)
 
-	if err != nil {
-		logger(fmt.Sprintln(rngStr, "is not a valid range"))
-		return http.StatusBadRequest
-	}
-
-	data, retVal := readLocalRange(logger, org, repo, fileId, int64(offset), int64(length))
-
-	if retVal != -1 {
-		return retVal
-	}
-
-	logger(fmt.Sprintf("writing %d bytes", len(data)))
-	err = iohelp.WriteAll(writer, data)
-
-	if err != nil {
-		logger("failed to write data to response " + err.Error())
-		return -1
-	}
-
-	logger("Successfully wrote data")
-	return -1
-}
-
-func readLocalRange(logger func(string), org, repo, fileId string, offset, length int64) ([]byte, int) {
-	path := filepath.Join(org, repo, fileId)
-
-	logger(fmt.Sprintf("Attempting to read bytes %d to %d from %s", offset, offset+length, path))
-	info, err := os.Stat(path)
-
-	if err != nil {
-		logger(fmt.Sprintf("file %s not found", path))
-		return nil, http.StatusNotFound
-	}
-
-	logger(fmt.Sprintf("Verified file %s exists", path))
-
-	if info.Size() < int64(offset+length) {
-		logger(fmt.Sprintf("Attempted to read bytes %d to %d, but the file is only %d bytes in size", offset, offset+length, info.Size()))
-		return nil, http.StatusBadRequest
-	}
-
-	logger(fmt.Sprintf("Verified the file is large enough to contain the range"))
-	f, err := os.Open(path)
-
-	if err != nil {
-		logger(fmt.Sprintf("Failed to open %s: %v", path, err))
-		return nil, http.StatusInternalServerError
+	if err == ErrTableFileNotFound {
+		logger("table file not found")
+		return http.StatusNotFound
+	} else if err != nil {
+		logger(fmt.Sprintf("failed to open table file: %v", err))
+		return http.StatusInternalServerError
 	}
 
 	defer func() {
-		err := f.Close()
+		err := rd.Close()
 
 		if err != nil {
-			logger(fmt.Sprintf("Close failed. file: %s, err: %v", path, err))
-		} else {
-			logger("Close Successful")
+			logger(fmt.Sprintf("Close failed. err: %v", err))
 		}
 	}()
 
-	logger(fmt.Sprintf("Successfully opened file"))
-	pos, err := f.Seek(int64(offset), 0)
-
-	if err != nil {
-		logger(fmt.Sprintf("Failed to seek to %d: %v", offset, err))
-		return nil, http.StatusInternalServerError
+	if length != 0 {
+		respWr.Header().Set("Content-Length", strconv.FormatInt(length, 10))
 	}
 
-	logger(fmt.Sprintf("Seek succeeded.  Current position is %d", pos))
-	diff := offset - pos
-	data, err := iohelp.ReadNBytes(f, int(diff+int64(length)))
+	respWr.WriteHeader(statusCode)
+	n, err := io.Copy(respWr, rd)
 
 	if err != nil {
-		logger(fmt.Sprintf("Failed to read %d bytes: %v", diff+length, err))
-		return nil, http.StatusInternalServerError
+		logger("failed to write data to response. err : " + err.Error())
+		return -1
 	}
 
-	logger(fmt.Sprintf("Successfully read %d bytes", len(data)))
-	return data[diff:], -1
+	logger(fmt.Sprintf("Successfully wrote %d bytes", n))
+	return -1
 }
@@ -28,13 +28,15 @@ import (
 	"google.golang.org/grpc"
 
 	remotesapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/remotesapi/v1alpha1"
-	"github.com/dolthub/dolt/go/libraries/utils/filesys"
 )
 
 func main() {
 	dirParam := flag.String("dir", "", "root directory that this command will run in.")
-	grpcPortParam := flag.Int("grpc-port", -1, "root directory that this command will run in.")
-	httpPortParam := flag.Int("http-port", -1, "root directory that this command will run in.")
+	grpcPortParam := flag.Int("grpc-port", -1, "port the grpc server listens on.")
+	httpPortParam := flag.Int("http-port", -1, "port the http server, which is used to transfer table files, listens on.")
+	httpHostParam := flag.String("http-host", "", "host name, and optionally the port, of the http server used in the urls given to clients. Defaults to localhost and the http port.")
+	backendParam := flag.String("backend", "", "url of the storage used for repositories. Either file://path, localbs://path or gs://bucket/path. Defaults to the working directory.")
+	authConfigParam := flag.String("auth-config", "", "yaml file containing the users of the server and the repositories they can read and write. If not provided anyone can read and write every repository.")
 	flag.Parse()
 
 	if dirParam != nil && len(*dirParam) > 0 {
@@ -51,21 +53,59 @@ func main() {
 		log.Println("'dir' parameter not provided. Using the current working dir.")
 	}
 
-	httpHost := "localhost"
-
-	if *httpPortParam != -1 {
-		httpHost = fmt.Sprintf("%s:%d", httpHost, *httpPortParam)
-	} else {
+	if *httpPortParam == -1 {
 		*httpPortParam = 80
 		log.Println("'http-port' parameter not provided. Using default port 80")
 	}
 
+	httpHost := *httpHostParam
+	if httpHost == "" {
+		httpHost = fmt.Sprintf("localhost:%d", *httpPortParam)
+	}
+
 	if *grpcPortParam == -1 {
 		*grpcPortParam = 50051
 		log.Println("'grpc-port' parameter not provided. Using default port 50051")
 	}
 
-	stopChan, wg := startServer(httpHost, *httpPortParam, *grpcPortParam)
+	backend, err := NewBackend(context.Background(), *backendParam)
+
+	if err != nil {
+		log.Fatalln("failed to create backend:", err.Error())
+	}
+
+	var auth *Authorizer
+	if *authConfigParam != "" {
+		cfg, err := LoadAuthConfig(*authConfigParam)
+
+		if err == nil {
+			auth, err = NewAuthorizer(cfg)
+		}
+
+		if err != nil {
+			log.Fatalln("failed to load auth config:", err.Error())
+		}
+	} else {
+		log.Println("'auth-config' parameter not provided. Anyone can read and write every repository.")
+	}
+
+	signer, err := newURLSigner()
+
+	if err != nil {
+		log.Fatalln("failed to create url signer:", err.Error())
+	}
+
+	srv := &server{
+		httpHost: httpHost,
+		httpPort: *httpPortParam,
+		grpcPort: *grpcPortParam,
+		backend:  backend,
+		auth:     auth,
+		signer:   signer,
+		expected: newExpectedFiles(),
+	}
+
+	stopChan, wg := srv.start()
 	waitForSignal()
 
 	close(stopChan)
@@ -73,50 +113,65 @@ func main() {
 }
 
 func waitForSignal() {
-	c := make(chan os.Signal)
+	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt)
 	signal.Notify(c, os.Kill)
 
 	<-c
 }
 
-func startServer(httpHost string, httpPort, grpcPort int) (chan interface{}, *sync.WaitGroup) {
+type server struct {
+	httpHost string
+	httpPort int
+	grpcPort int
+	backend  Backend
+	auth     *Authorizer
+	signer   *urlSigner
+	expected *expectedFiles
+}
+
+func (srv *server) start() (chan interface{}, *sync.WaitGroup) {
 	wg := sync.WaitGroup{}
 	stopChan := make(chan interface{})
 
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		httpServer(httpPort, stopChan)
+		srv.httpServer(stopChan)
 	}()
 
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		grpcServer(httpHost, grpcPort, stopChan)
+		srv.grpcServer(stopChan)
 	}()
 
 	return stopChan, &wg
 }
 
-func grpcServer(httpHost string, grpcPort int, stopChan chan interface{}) {
+func (srv *server) grpcServer(stopChan chan interface{}) {
 	defer func() {
 		log.Println("exiting grpc Server go routine")
 	}()
 
-	dbCache := NewLocalCSCache(filesys.LocalFS)
-	chnkSt := NewHttpFSBackedChunkStore(httpHost, dbCache)
+	dbCache := NewCSCache(srv.backend)
+	chnkSt := NewRemoteChunkStore(srv.httpHost, dbCache, srv.signer, srv.expected)
 
-	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", grpcPort))
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", srv.grpcPort))
 	if err != nil {
 		log.Fatalf("failed to listen: %v", err)
 	}
 
-	grpcServer := grpc.NewServer(grpc.MaxRecvMsgSize(128 * 1024 * 1024))
+	opts := []grpc.ServerOption{grpc.MaxRecvMsgSize(128 * 1024 * 1024)}
+	if srv.auth != nil {
+		opts = append(opts, grpc.UnaryInterceptor(srv.auth.UnaryInterceptor()))
+	}
+
+	grpcServer := grpc.NewServer(opts...)
 	go func() {
 		remotesapi.RegisterChunkStoreServiceServer(grpcServer, chnkSt)
 
-		log.Println("Starting grpc server on port", grpcPort)
+		log.Println("Starting grpc server on port", srv.grpcPort)
 		err := grpcServer.Serve(lis)
 		log.Println("grpc server exited. error:", err)
 	}()
@@ -125,18 +180,18 @@ func grpcServer(httpHost string, grpcPort int, stopChan chan interface{}) {
 	grpcServer.GracefulStop()
 }
 
-func httpServer(httpPort int, stopChan chan interface{}) {
+func (srv *server) httpServer(stopChan chan interface{}) {
 	defer func() {
 		log.Println("exiting http Server go routine")
 	}()
 
 	server := http.Server{
-		Addr:    fmt.Sprintf(":%d", httpPort),
-		Handler: http.HandlerFunc(ServeHTTP),
+		Addr:    fmt.Sprintf(":%d", srv.httpPort),
+		Handler: newFileHandler(srv.backend, srv.signer, srv.expected),
 	}
 
 	go func() {
-		log.Println("Starting http server on port ", httpPort)
+		log.Println("Starting http server on port ", srv.httpPort)
 		err := server.ListenAndServe()
 		log.Println("http server exited. exit error:", err)
 	}()